@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Main package
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/test/manifestgen"
+)
+
+// genmanifest writes a generated extensions manifest and the deployment packages it lists to a directory, as test
+// fixtures for the extensions provisioner. It is run by go generate:
+//
+//	//go:generate go run ../../cmd/genmanifest -packages 25 -deployments 100 -absent-every 5 -out testdata/extensions/generated
+
+func main() {
+	packages := flag.Int("packages", 10, "number of deployment packages")
+	deployments := flag.Int("deployments", 30, "number of deployments, spread over the packages with one profile per round")
+	absentEvery := flag.Int("absent-every", 5, "make every nth package and deployment absent, 0 makes everything present")
+	repository := flag.String("repository", manifestgen.DefaultRepository, "repository of the deployment packages")
+	release := flag.String("release", manifestgen.DefaultRelease, "release of the manifest")
+	out := flag.String("out", ".", "directory to write the manifest and deployment packages to. Files of a previous, larger manifest are not removed")
+	flag.Parse()
+
+	fixture, err := manifestgen.Generate(manifestgen.Options{
+		Packages:    *packages,
+		Deployments: *deployments,
+		AbsentEvery: *absentEvery,
+		Repository:  *repository,
+		Release:     *release,
+	})
+	if err == nil {
+		err = fixture.Write(*out)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "genmanifest: %v\n", err)
+		os.Exit(1)
+	}
+}
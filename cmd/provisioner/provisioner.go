@@ -5,11 +5,18 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
 	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
 	"github.com/open-edge-platform/app-orch-tenant-controller/internal/manager"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
 	"github.com/open-edge-platform/orch-library/go/dazl"
 	_ "github.com/open-edge-platform/orch-library/go/dazl/zap"
-	"os"
 	k8sconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	k8smanager "sigs.k8s.io/controller-runtime/pkg/manager"
@@ -18,9 +25,15 @@ import (
 
 var log = dazl.GetPackageLogger()
 
+var validateConfig = flag.Bool("validate-config", false, "check the configuration and the services and secrets it refers to, print a report and exit")
+
 func main() {
+	flag.Parse()
+	if *validateConfig {
+		os.Exit(validate())
+	}
 
-	cfg, err := config.InitConfig()
+	cfg, err := config.InitConfigStrict()
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -56,3 +69,31 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// validate prints a report of the configuration checks and returns the exit code.
+func validate() int {
+	cfg, err := config.InitConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to read the configuration: %v\n", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	report := config.Validate(cfg)
+	report.CheckEnvironment(ctx, cfg, config.EnvironmentChecks{
+		LookupHost: net.DefaultResolver.LookupHost,
+		ReadSecret: func(ctx context.Context, namespace string, name string) (map[string][]byte, error) {
+			k8sClient, err := southbound.K8sFactory(namespace)
+			if err != nil {
+				return nil, err
+			}
+			return k8sClient.ReadSecret(ctx, name)
+		},
+	})
+	report.Print(os.Stdout)
+	if report.Failed() {
+		return 1
+	}
+	return 0
+}
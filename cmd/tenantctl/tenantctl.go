@@ -0,0 +1,778 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Main package
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/manager"
+	nexushook "github.com/open-edge-platform/app-orch-tenant-controller/internal/nexus"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/plugins"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	"github.com/open-edge-platform/app-orch-tenant-controller/test/fake"
+	"github.com/open-edge-platform/app-orch-tenant-controller/test/manifestgen"
+	_ "github.com/open-edge-platform/orch-library/go/dazl/zap"
+	k8sconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// tenantctl is an operator tool for day-2 tenant provisioning tasks. Commands that provision or read the
+// manifest use the controller configuration from the environment, so they are normally run inside the
+// controller pod with kubectl exec.
+
+const usage = `Usage: tenantctl [-kubeconfig path] <command> [flags]
+
+Commands:
+  status              list the provisioning status of tenant projects
+  reprovision         run provisioning again for a project
+  rotate-credentials  issue new Harbor robot secrets and update the catalog registries that use them
+  dry-run             show what provisioning a project would do, without doing it
+  apply-manifest      apply an extensions manifest to a project, changing only the deployments that differ
+  validate-manifest   validate an extensions manifest
+  inventory           print the resources created for a project
+  query               list the catalog registries or ADM deployments of a project, read-only
+  export              write the provisioned resources of a project to a bundle for migration
+  import              provision a project from an export bundle of another orchestrator
+  loadtest            create and delete many synthetic projects and report throughput and latency
+
+Run 'tenantctl <command> -h' for the flags of a command.
+`
+
+var timeout = flag.Duration("timeout", 30*time.Minute, "time allowed for the command")
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	var err error
+	command, args := flag.Arg(0), flag.Args()[1:]
+	switch command {
+	case "status":
+		err = status(ctx, args)
+	case "reprovision":
+		err = reprovision(ctx, args)
+	case "rotate-credentials":
+		err = rotateCredentials(ctx, args)
+	case "dry-run":
+		err = dryRun(ctx, args)
+	case "apply-manifest":
+		err = applyManifest(ctx, args)
+	case "validate-manifest":
+		err = validateManifest(ctx, args)
+	case "inventory":
+		err = inventory(ctx, args)
+	case "query":
+		err = query(ctx, args)
+	case "export":
+		err = exportProject(ctx, args)
+	case "import":
+		err = importProject(ctx, args)
+	case "loadtest":
+		err = loadTest(ctx, args)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tenantctl %s: %v\n", command, err)
+		os.Exit(1)
+	}
+}
+
+// projectFlags are the flags identifying a single project
+type projectFlags struct {
+	org     *string
+	project *string
+	uuid    *string
+	profile *string
+}
+
+func newProjectFlags(fs *flag.FlagSet) projectFlags {
+	return projectFlags{
+		org:     fs.String("org", "", "organization name (required)"),
+		project: fs.String("project", "", "project name (required)"),
+		uuid:    fs.String("uuid", "", "project UUID, looked up in Nexus if not set"),
+		profile: fs.String("profile", "", "provisioning profile, defaults to the profile selected by the controller"),
+	}
+}
+
+func (f projectFlags) validate() error {
+	if *f.org == "" || *f.project == "" {
+		return errors.New("-org and -project are required")
+	}
+	return nil
+}
+
+// event builds the create event the controller would generate for the project. The project's UUID, requested
+// profile and deployment labels are read from Nexus; the UUID and profile can be given on the command line instead.
+func (f projectFlags) event(ctx context.Context, configuration config.Configuration, lookup bool) (plugins.Event, error) {
+	uuid := *f.uuid
+	requestedProfile := *f.profile
+	var deploymentLabels map[string]string
+	if lookup {
+		project, err := findProject(ctx, *f.org, *f.project)
+		if err != nil {
+			return plugins.Event{}, err
+		}
+		if uuid == "" {
+			uuid = project.UUID
+		}
+		if requestedProfile == "" {
+			requestedProfile = project.Profile
+		}
+		deploymentLabels = configuration.SelectDeploymentLabels(project.Labels, project.Annotations)
+	}
+	return plugins.Event{
+		EventType:        "create",
+		Organization:     *f.org,
+		Name:             *f.project,
+		UUID:             uuid,
+		Profile:          configuration.ProvisioningProfiles.Select(requestedProfile, *f.org),
+		DeploymentLabels: deploymentLabels,
+	}, nil
+}
+
+func listProjects(ctx context.Context) ([]nexushook.ProjectStatus, error) {
+	cfg, err := k8sconfig.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	return nexushook.ListProjectStatus(ctx, cfg)
+}
+
+func findProject(ctx context.Context, org string, project string) (*nexushook.ProjectStatus, error) {
+	projects, err := listProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range projects {
+		if projects[i].Organization == org && projects[i].Name == project {
+			return &projects[i], nil
+		}
+	}
+	return nil, fmt.Errorf("project %s/%s not found", org, project)
+}
+
+func status(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	org := fs.String("org", "", "only list projects of this organization")
+	_ = fs.Parse(args)
+
+	projects, err := listProjects(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ORGANIZATION\tPROJECT\tUUID\tPROFILE\tSTATUS\tMANIFEST\tCONTROLLER\tPROVISIONED\tUPDATED\tMESSAGE")
+	for _, project := range projects {
+		if *org != "" && project.Organization != *org {
+			continue
+		}
+		projectStatus := strings.TrimPrefix(project.Status, "STATUS_INDICATION_")
+		if project.Status == "" {
+			projectStatus = "NOT_WATCHED"
+		}
+		if project.Deleted {
+			projectStatus += " (deleted)"
+		}
+		updated := ""
+		if project.TimeStamp != 0 {
+			updated = time.Unix(int64(project.TimeStamp), 0).UTC().Format(time.RFC3339) //nolint:gosec // Unix time fits in int64
+		}
+		provisioned := ""
+		if !project.ProvisionedAt.IsZero() {
+			provisioned = project.ProvisionedAt.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", project.Organization, project.Name, project.UUID,
+			project.Profile, projectStatus, project.ManifestTag, project.ControllerVersion, provisioned, updated, project.Message)
+	}
+	return w.Flush()
+}
+
+func reprovision(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("reprovision", flag.ExitOnError)
+	pf := newProjectFlags(fs)
+	refreshCredentials := fs.Bool("refresh-credentials", false, "issue a new Harbor robot secret even if the robot account is reused")
+	pluginNames := fs.String("plugins", "", "comma separated plugins to converge the project with, e.g. catalog, without deleting resources or issuing new credentials")
+	_ = fs.Parse(args)
+	if err := pf.validate(); err != nil {
+		return err
+	}
+	if *pluginNames != "" && *refreshCredentials {
+		return errors.New("-plugins and -refresh-credentials cannot be combined, new credentials are only issued by provisioning all plugins")
+	}
+
+	configuration, err := config.InitConfig()
+	if err != nil {
+		return err
+	}
+	event, err := pf.event(ctx, configuration, true)
+	if err != nil {
+		return err
+	}
+	event.RefreshCredentials = *refreshCredentials
+
+	if err := manager.RegisterPlugins(ctx, configuration); err != nil {
+		return err
+	}
+	if *pluginNames != "" {
+		event.EventType = "ensure"
+		event.Plugins = strings.Split(*pluginNames, ",")
+		if err := plugins.CheckPluginNames(event.Plugins); err != nil {
+			return err
+		}
+	}
+	if err := plugins.Initialize(ctx); err != nil {
+		return err
+	}
+	result, err := plugins.Dispatch(ctx, event, nil)
+	if printErr := printDispatchResult(result); printErr != nil {
+		return printErr
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Project %s/%s reprovisioned\n", event.Organization, event.Name)
+	return nil
+}
+
+// printDispatchResult prints how each plugin handled an event, followed by the warnings of the plugins.
+func printDispatchResult(result *plugins.DispatchResult) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PLUGIN\tSTATUS\tATTEMPTS\tDURATION")
+	for _, plugin := range result.Plugins {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", plugin.Name, plugin.Status, plugin.Attempts, plugin.Duration.Round(time.Millisecond))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, warning := range result.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+	return nil
+}
+
+func rotateCredentials(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("rotate-credentials", flag.ExitOnError)
+	org := fs.String("org", "", "organization name, required unless -all is set")
+	project := fs.String("project", "", "project name, required unless -all is set")
+	all := fs.Bool("all", false, "rotate the credentials of every project, or of every project of -org")
+	_ = fs.Parse(args)
+	if !*all && (*org == "" || *project == "") {
+		return errors.New("-org and -project, or -all, are required")
+	}
+
+	configuration, err := config.InitConfig()
+	if err != nil {
+		return err
+	}
+	projects, err := listProjects(ctx)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, p := range projects {
+		if p.Deleted || *org != "" && p.Organization != *org || !*all && p.Name != *project {
+			continue
+		}
+		event := plugins.Event{Organization: p.Organization, Name: p.Name, UUID: p.UUID}
+		rotation, err := plugins.RotateCredentials(ctx, configuration, event)
+		if err != nil {
+			fmt.Printf("Project %s/%s: %v\n", p.Organization, p.Name, err)
+			failed = append(failed, p.Organization+"/"+p.Name)
+			continue
+		}
+		fmt.Printf("Project %s/%s: rotated robots %s, updated registries %s\n", p.Organization, p.Name,
+			strings.Join(rotation.Robots, ", "), strings.Join(rotation.Registries, ", "))
+		if !*all {
+			return nil
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("unable to rotate the credentials of %s", strings.Join(failed, ", "))
+	}
+	if !*all {
+		return fmt.Errorf("project %s/%s not found", *org, *project)
+	}
+	return nil
+}
+
+func dryRun(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("dry-run", flag.ExitOnError)
+	pf := newProjectFlags(fs)
+	_ = fs.Parse(args)
+	if err := pf.validate(); err != nil {
+		return err
+	}
+
+	configuration, err := config.InitConfig()
+	if err != nil {
+		return err
+	}
+	// A hypothetical project does not exist in Nexus, so nothing is looked up
+	event, err := pf.event(context.Background(), configuration, false)
+	if err != nil {
+		return err
+	}
+	plan, err := plugins.PlanProvisioning(ctx, configuration, event)
+	if err != nil {
+		return err
+	}
+
+	profile := plan.Profile
+	if profile == "" {
+		profile = "(none)"
+	}
+	fmt.Printf("Project:              %s/%s\n", plan.Organization, plan.Project)
+	fmt.Printf("Provisioning profile: %s\n", profile)
+	fmt.Printf("Harbor project:       %s\n", plan.HarborProject)
+	if plan.HarborStorageLimit != 0 {
+		fmt.Printf("Harbor storage limit: %d\n", plan.HarborStorageLimit)
+	}
+	if len(plan.HarborSigning) > 0 {
+		fmt.Printf("Harbor signatures:    %s\n", strings.Join(plan.HarborSigning, ", "))
+	}
+	fmt.Println("Catalog registries:")
+	for _, registry := range plan.Registries {
+		fmt.Printf("  %s (%s) %s\n", registry.Name, registry.Type, registry.RootURL)
+	}
+	if len(plan.StarterApps) > 0 {
+		fmt.Println("Starter applications:")
+		for _, app := range plan.StarterApps {
+			fmt.Printf("  upload %s\n", app)
+		}
+	}
+	fmt.Printf("Extensions from manifest %s:\n", plan.ManifestRelease)
+	for _, dp := range plan.DeploymentPackages {
+		fmt.Printf("  upload %s %s\n", dp.Name, dp.Version)
+	}
+	for _, dl := range plan.Deployments {
+		fmt.Printf("  deploy %s %s profile %s labels %v\n", dl.Name, dl.Version, dl.Profile, dl.Labels)
+	}
+	for _, dl := range plan.RemovedDeployments {
+		fmt.Printf("  remove %s %s profile %s\n", dl.Name, dl.Version, dl.Profile)
+	}
+	return nil
+}
+
+func applyManifest(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("apply-manifest", flag.ExitOnError)
+	pf := newProjectFlags(fs)
+	tag := fs.String("tag", "", "manifest tag to apply (required)")
+	previousTag := fs.String("previous-tag", "", "manifest tag the project was provisioned with, defaults to the tag recorded on its project watcher")
+	dryRun := fs.Bool("dry-run", false, "show the changes without making them")
+	_ = fs.Parse(args)
+	if err := pf.validate(); err != nil {
+		return err
+	}
+	if *tag == "" {
+		return errors.New("-tag is required")
+	}
+
+	configuration, err := config.InitConfig()
+	if err != nil {
+		return err
+	}
+	if *previousTag == "" {
+		project, err := findProject(ctx, *pf.org, *pf.project)
+		if err != nil {
+			return err
+		}
+		*previousTag = project.ManifestTag
+	}
+	event, err := pf.event(ctx, configuration, true)
+	if err != nil {
+		return err
+	}
+	diff, err := plugins.ApplyManifest(ctx, configuration, event, plugins.ApplyManifestOptions{
+		ManifestTag:         *tag,
+		PreviousManifestTag: *previousTag,
+		DryRun:              *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	action := func(verb string) string {
+		if *dryRun {
+			return "would " + verb
+		}
+		return verb
+	}
+	fmt.Printf("Project %s/%s, manifest %s (%s):\n", event.Organization, event.Name, *tag, diff.ManifestRelease)
+	for _, dp := range diff.Packages {
+		fmt.Printf("  %s %s %s\n", action("upload"), dp.Name, dp.Version)
+	}
+	for _, dl := range diff.Delete {
+		fmt.Printf("  %s %s %s %s profile %s\n", action("delete"), dl.DisplayName, dl.Name, dl.Version, dl.Profile)
+	}
+	for _, dl := range diff.Create {
+		fmt.Printf("  %s %s %s %s profile %s labels %v\n", action("create"), dl.DisplayName, dl.Name, dl.Version, dl.Profile, dl.Labels)
+	}
+	for _, dl := range diff.Unchanged {
+		fmt.Printf("  keep %s %s %s profile %s\n", dl.DisplayName, dl.Name, dl.Version, dl.Profile)
+	}
+	if diff.IsEmpty() {
+		fmt.Println("  no deployment changes")
+	}
+	return nil
+}
+
+func validateManifest(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("validate-manifest", flag.ExitOnError)
+	file := fs.String("file", "", "manifest file, defaults to the manifest configured for the controller")
+	_ = fs.Parse(args)
+
+	var manifest *plugins.Manifest
+	var err error
+	if *file != "" {
+		var yamlBytes []byte
+		yamlBytes, err = os.ReadFile(*file)
+		if err != nil {
+			return err
+		}
+		manifest, err = plugins.ParseManifest(yamlBytes)
+	} else {
+		var configuration config.Configuration
+		configuration, err = config.InitConfig()
+		if err != nil {
+			return err
+		}
+		manifest, err = plugins.LoadManifest(ctx, configuration)
+	}
+	if err != nil {
+		return err
+	}
+
+	errs := plugins.ValidateManifest(manifest)
+	for _, err := range errs {
+		fmt.Println(err)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("manifest %s has %d problems", manifest.Metadata.Release, len(errs))
+	}
+	fmt.Printf("Manifest %s is valid: %d deployment packages, %d deployments\n", manifest.Metadata.Release,
+		len(manifest.Lpke.DeploymentPackages), len(manifest.Lpke.DeploymentList))
+	return nil
+}
+
+func inventory(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("inventory", flag.ExitOnError)
+	org := fs.String("org", "", "organization name (required)")
+	project := fs.String("project", "", "project name (required)")
+	uuid := fs.String("uuid", "", "project UUID, looked up in Nexus if not set")
+	namespace := fs.String("namespace", os.Getenv("POD_NAMESPACE"), "namespace of the controller")
+	_ = fs.Parse(args)
+	if *org == "" || *project == "" {
+		return errors.New("-org and -project are required")
+	}
+	if *namespace == "" {
+		return errors.New("-namespace is required outside the controller pod")
+	}
+
+	if *uuid == "" {
+		status, err := findProject(ctx, *org, *project)
+		if err != nil {
+			return err
+		}
+		*uuid = status.UUID
+	}
+	cfg, err := k8sconfig.GetConfig()
+	if err != nil {
+		return err
+	}
+	store, err := southbound.NewInventoryStore(cfg, *namespace)
+	if err != nil {
+		return err
+	}
+	projectInventory, err := store.Load(ctx, *uuid)
+	if err != nil {
+		return err
+	}
+	if projectInventory == nil {
+		return fmt.Errorf("project %s/%s has no inventory", *org, *project)
+	}
+	document, err := json.MarshalIndent(projectInventory, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(document))
+	return nil
+}
+
+// query lists the catalog registries or ADM deployments of a project with the credentials of the controller, so
+// that they can be checked during incidents without extracting a service account token. Nothing is changed.
+func query(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+	pf := newProjectFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: tenantctl query -org org -project project [-uuid uuid] registries|deployments")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if err := pf.validate(); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("a query is required")
+	}
+
+	configuration, err := config.InitConfig()
+	if err != nil {
+		return err
+	}
+	event, err := pf.event(ctx, configuration, *pf.uuid == "")
+	if err != nil {
+		return err
+	}
+	var result interface{}
+	switch fs.Arg(0) {
+	case "registries":
+		result, err = plugins.ListDebugRegistries(ctx, configuration, event.UUID)
+	case "deployments":
+		result, err = plugins.ListDebugDeployments(ctx, configuration, event.UUID)
+	default:
+		return fmt.Errorf("unknown query %s, must be registries or deployments", fs.Arg(0))
+	}
+	if err != nil {
+		return err
+	}
+	document, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(document))
+	return nil
+}
+
+func exportProject(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	pf := newProjectFlags(fs)
+	out := fs.String("out", "", "file to write the bundle to, defaults to standard output")
+	_ = fs.Parse(args)
+	if err := pf.validate(); err != nil {
+		return err
+	}
+
+	configuration, err := config.InitConfig()
+	if err != nil {
+		return err
+	}
+	event, err := pf.event(ctx, configuration, true)
+	if err != nil {
+		return err
+	}
+	bundle, err := plugins.ExportTenant(ctx, configuration, event)
+	if err != nil {
+		return err
+	}
+	document, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		fmt.Println(string(document))
+		return nil
+	}
+	if err := os.WriteFile(*out, append(document, '\n'), 0600); err != nil {
+		return err
+	}
+	fmt.Printf("Project %s/%s exported to %s\n", bundle.Organization, bundle.Project, *out)
+	return nil
+}
+
+func importProject(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("file", "", "export bundle to import (required)")
+	uuid := fs.String("uuid", "", "UUID of the project in this orchestrator, looked up in Nexus if not set")
+	profile := fs.String("profile", "", "provisioning profile, defaults to the profile requested by the project, then the profile of the bundle")
+	_ = fs.Parse(args)
+	if *file == "" {
+		return errors.New("-file is required")
+	}
+
+	document, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+	bundle := &plugins.ExportBundle{}
+	if err := json.Unmarshal(document, bundle); err != nil {
+		return fmt.Errorf("invalid export bundle %s: %w", *file, err)
+	}
+	configuration, err := config.InitConfig()
+	if err != nil {
+		return err
+	}
+
+	// The project must exist in this orchestrator; its labels take precedence over those of the bundle
+	deploymentLabels := bundle.DeploymentLabels
+	if *uuid == "" || *profile == "" {
+		project, err := findProject(ctx, bundle.Organization, bundle.Project)
+		if err != nil {
+			return err
+		}
+		if *uuid == "" {
+			*uuid = project.UUID
+		}
+		if *profile == "" {
+			*profile = project.Profile
+		}
+		if labels := configuration.SelectDeploymentLabels(project.Labels, project.Annotations); len(labels) > 0 {
+			deploymentLabels = labels
+		}
+	}
+	if *profile == "" {
+		*profile = bundle.Profile
+	}
+	event := plugins.Event{
+		Organization:     bundle.Organization,
+		Name:             bundle.Project,
+		UUID:             *uuid,
+		Profile:          configuration.ProvisioningProfiles.Select(*profile, bundle.Organization),
+		DeploymentLabels: deploymentLabels,
+	}
+
+	if err := manager.RegisterPlugins(ctx, configuration); err != nil {
+		return err
+	}
+	if err := plugins.Initialize(ctx); err != nil {
+		return err
+	}
+	result, err := plugins.ImportTenant(ctx, configuration, bundle, event)
+	if result != nil && result.Dispatch != nil {
+		if printErr := printDispatchResult(result.Dispatch); printErr != nil {
+			return printErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	for _, registry := range result.RestoredRegistries {
+		fmt.Printf("Restored registry %s\n", registry)
+	}
+	for _, resource := range result.Missing {
+		fmt.Printf("Missing %s\n", resource)
+	}
+	if len(result.Missing) > 0 {
+		return fmt.Errorf("project %s/%s imported, but %d resources of the bundle are missing", event.Organization, event.Name, len(result.Missing))
+	}
+	fmt.Printf("Project %s/%s imported from %s\n", event.Organization, event.Name, *file)
+	return nil
+}
+
+func loadTest(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("loadtest", flag.ExitOnError)
+	org := fs.String("org", "loadtest", "organization of the synthetic projects")
+	projects := fs.Int("projects", 10, "number of synthetic projects")
+	workers := fs.Int("workers", 0, "number of worker threads, defaults to the controller configuration")
+	queueSize := fs.Int("queue-size", 0, "event queue size, defaults to the controller configuration")
+	keep := fs.Bool("keep", false, "do not delete the projects after creating them")
+	useFakes := fs.Bool("fake", false, "provision against in-process fakes of the southbound services")
+	confirm := fs.Bool("confirm", false, "required without -fake: the projects are created in the configured services")
+	manifestPackages := fs.Int("manifest-packages", 0, "with -fake, provision a generated manifest with this many deployment packages instead of the sample manifest")
+	manifestDeployments := fs.Int("manifest-deployments", 0, "with -fake and -manifest-packages, number of deployments in the generated manifest")
+	_ = fs.Parse(args)
+
+	var configuration config.Configuration
+	if *useFakes {
+		env, err := fake.Start()
+		if err != nil {
+			return err
+		}
+		defer env.Close()
+		if *manifestPackages > 0 {
+			_, err = env.PushGeneratedManifest(manifestgen.Options{Packages: *manifestPackages, Deployments: *manifestDeployments})
+		} else {
+			err = env.PushSampleManifest()
+		}
+		if err != nil {
+			return err
+		}
+		// Retry settings are the chart defaults. All projects share the fake catalog and deployment manager, so
+		// with more than one worker the projects conflict over their registries
+		configuration = env.Configuration()
+		configuration.NumberWorkerThreads = 1
+		configuration.EventQueueSize = 1
+		configuration.InitialSleepInterval = 15 * time.Second
+		configuration.MaxWaitTime = 10 * time.Minute
+	} else {
+		if !*confirm {
+			return errors.New("-confirm is required to create projects in the configured services, or use -fake")
+		}
+		var err error
+		configuration, err = config.InitConfig()
+		if err != nil {
+			return err
+		}
+	}
+	if *workers > 0 {
+		configuration.NumberWorkerThreads = *workers
+	}
+	if *queueSize > 0 {
+		configuration.EventQueueSize = *queueSize
+	}
+	// The synthetic projects are not in Nexus, so there are no project watchers and no inventory to record
+	configuration.PodNamespace = ""
+
+	if err := manager.RegisterPlugins(ctx, configuration); err != nil {
+		return err
+	}
+	if err := plugins.Initialize(ctx); err != nil {
+		return err
+	}
+	phases, err := manager.NewManager(configuration).RunLoadTest(ctx, manager.LoadTest{
+		Organization: *org,
+		Projects:     *projects,
+		KeepProjects: *keep,
+	})
+
+	fmt.Printf("%d projects, %d workers, queue size %d\n", *projects, configuration.NumberWorkerThreads, max(configuration.EventQueueSize, 1))
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "EVENT\tEVENTS\tFAILED\tDURATION\tEVENTS/S\tP50\tP90\tP99\tMAX")
+	failures := []string{}
+	for _, phase := range phases {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%.2f\t%s\t%s\t%s\t%s\n", phase.EventType, phase.Events, len(phase.Failures),
+			phase.Duration.Round(time.Millisecond), phase.Throughput(), phase.Percentile(50).Round(time.Millisecond),
+			phase.Percentile(90).Round(time.Millisecond), phase.Percentile(99).Round(time.Millisecond),
+			phase.Percentile(100).Round(time.Millisecond))
+		for _, failure := range phase.Failures {
+			failures = append(failures, phase.EventType+" "+failure)
+		}
+	}
+	if flushErr := w.Flush(); flushErr != nil {
+		return flushErr
+	}
+	for _, failure := range failures {
+		fmt.Println(failure)
+	}
+	if err != nil {
+		return err
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%d events failed", len(failures))
+	}
+	return nil
+}
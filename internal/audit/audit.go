@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/open-edge-platform/orch-library/go/dazl"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var log = dazl.GetPackageLogger()
+
+const (
+	sendTimeout = 10 * time.Second
+
+	// queueSize is the number of events waiting to be sent before new events are dropped
+	queueSize = 256
+
+	// Source is the component that emits the audit events
+	Source = "app-orch-tenant-controller"
+)
+
+// Types of the tenant lifecycle events
+const (
+	TenantCreated            = "tenant.created"
+	TenantProvisioned        = "tenant.provisioned"
+	TenantProvisioningFailed = "tenant.provisioning_failed"
+	TenantDeleted            = "tenant.deleted"
+	TenantDeletionFailed     = "tenant.deletion_failed"
+)
+
+// Results of sending an audit event, the values of the result label of the metric
+const (
+	resultSent    = "sent"
+	resultError   = "error"
+	resultDropped = "dropped"
+)
+
+var auditEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "tenant_controller_audit_events_total",
+	Help: "Tenant lifecycle events sent to the audit service, by event type and result",
+}, []string{"type", "result"})
+
+func init() {
+	metrics.Registry.MustRegister(auditEvents)
+}
+
+// Event is the JSON body posted to the audit ingestion API for a tenant lifecycle event.
+type Event struct {
+	Type   string    `json:"type"`
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+	// event source that asked for the change, e.g. nexus
+	Actor           string             `json:"actor"`
+	Organization    string             `json:"organization"`
+	Project         string             `json:"project"`
+	UUID            string             `json:"uuid"`
+	Error           string             `json:"error,omitempty"`
+	Resources       []string           `json:"resources,omitempty"`
+	DurationSeconds float64            `json:"durationSeconds,omitempty"`
+	PluginSeconds   map[string]float64 `json:"pluginSeconds,omitempty"`
+	// bytes of catalog artifacts and Harbor request bodies sent for the event, by southbound service
+	PayloadBytes      map[string]int64 `json:"payloadBytes,omitempty"`
+	ControllerVersion string           `json:"controllerVersion,omitempty"`
+}
+
+// Emitter posts audit events as JSON to the ingestion API of the audit service. Events are queued and sent in the
+// background, so that a slow or unavailable audit service does not hold up provisioning. Events that cannot be
+// sent are logged and dropped. A nil Emitter drops all events.
+type Emitter struct {
+	url    string
+	client *http.Client
+	token  func(ctx context.Context) (string, error)
+	queue  chan Event
+}
+
+func NewEmitter(url string) *Emitter {
+	return &Emitter{
+		url:    url,
+		client: &http.Client{Timeout: sendTimeout},
+		queue:  make(chan Event, queueSize),
+	}
+}
+
+// WithToken sets the source of the bearer token sent with the events. Without it, no token is sent.
+func (e *Emitter) WithToken(token func(ctx context.Context) (string, error)) *Emitter {
+	e.token = token
+	return e
+}
+
+// Start sends the queued events until the context is done.
+func (e *Emitter) Start(ctx context.Context) {
+	if e == nil {
+		return
+	}
+	go func() {
+		for {
+			select {
+			case event := <-e.queue:
+				e.deliver(ctx, event)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Emit queues the event to be sent. If the queue is full, the event is dropped.
+func (e *Emitter) Emit(event Event) {
+	if e == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	event.Source = Source
+	select {
+	case e.queue <- event:
+	default:
+		auditEvents.WithLabelValues(event.Type, resultDropped).Inc()
+		log.Warnf("Audit event queue is full, dropping %s event for project %s", event.Type, event.Project)
+	}
+}
+
+func (e *Emitter) deliver(ctx context.Context, event Event) {
+	if err := e.send(ctx, event); err != nil {
+		auditEvents.WithLabelValues(event.Type, resultError).Inc()
+		log.Warnf("Unable to send %s audit event for project %s: %v", event.Type, event.Project, err)
+		return
+	}
+	auditEvents.WithLabelValues(event.Type, resultSent).Inc()
+}
+
+func (e *Emitter) send(ctx context.Context, event Event) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("content-type", "application/json")
+	if e.token != nil {
+		token, err := e.token(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to get a token for the audit service: %w", err)
+		}
+		req.Header.Add("Authorization", "Bearer "+token)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		responseBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("audit service returned %s: %s", resp.Status, string(responseBody))
+	}
+	return nil
+}
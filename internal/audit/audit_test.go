@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+// Suite of audit event tests
+type AuditTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *AuditTestSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 1*time.Minute)
+}
+
+func (s *AuditTestSuite) TearDownTest() {
+	s.cancel()
+}
+
+func TestAudit(t *testing.T) {
+	suite.Run(t, &AuditTestSuite{})
+}
+
+// auditService records the events posted to it
+func (s *AuditTestSuite) auditService(status int) (*httptest.Server, chan Event, chan string) {
+	received := make(chan Event, 10)
+	authorizations := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal(http.MethodPost, r.Method)
+		s.Equal("application/json", r.Header.Get("content-type"))
+		var event Event
+		s.NoError(json.NewDecoder(r.Body).Decode(&event))
+		received <- event
+		authorizations <- r.Header.Get("Authorization")
+		w.WriteHeader(status)
+	}))
+	return server, received, authorizations
+}
+
+func (s *AuditTestSuite) receive(received chan Event) Event {
+	select {
+	case event := <-received:
+		return event
+	case <-s.ctx.Done():
+		s.FailNow("the audit event was not sent")
+		return Event{}
+	}
+}
+
+func (s *AuditTestSuite) TestEmit() {
+	server, received, authorizations := s.auditService(http.StatusAccepted)
+	defer server.Close()
+	emitter := NewEmitter(server.URL).WithToken(func(_ context.Context) (string, error) { return "token", nil })
+	emitter.Start(s.ctx)
+	sent := testutil.ToFloat64(auditEvents.WithLabelValues(TenantProvisioned, resultSent))
+
+	emitter.Emit(Event{Type: TenantProvisioned, Actor: "nexus", Organization: "org", Project: "project",
+		UUID: "uuid", Resources: []string{"harbor-project/org-project"}, DurationSeconds: 1.5,
+		PluginSeconds: map[string]float64{"harbor": 1.25}, PayloadBytes: map[string]int64{"catalog": 2048}})
+	event := s.receive(received)
+	s.Equal(TenantProvisioned, event.Type)
+	s.Equal(Source, event.Source)
+	s.Equal("nexus", event.Actor)
+	s.Equal("project", event.Project)
+	s.Equal([]string{"harbor-project/org-project"}, event.Resources)
+	s.Equal(1.5, event.DurationSeconds)
+	s.Equal(map[string]float64{"harbor": 1.25}, event.PluginSeconds)
+	s.Equal(map[string]int64{"catalog": 2048}, event.PayloadBytes)
+	s.False(event.Time.IsZero())
+	s.Equal("Bearer token", <-authorizations)
+	s.Eventually(func() bool {
+		return testutil.ToFloat64(auditEvents.WithLabelValues(TenantProvisioned, resultSent)) == sent+1
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func (s *AuditTestSuite) TestSendErrors() {
+	server, received, authorizations := s.auditService(http.StatusServiceUnavailable)
+	defer server.Close()
+	emitter := NewEmitter(server.URL)
+	s.ErrorContains(emitter.send(s.ctx, Event{Type: TenantDeleted}), "503")
+	s.Equal(TenantDeleted, (<-received).Type)
+	s.Empty(<-authorizations)
+
+	emitter.WithToken(func(_ context.Context) (string, error) { return "", errors.New("vault unavailable") })
+	s.ErrorContains(emitter.send(s.ctx, Event{Type: TenantDeleted}), "vault unavailable")
+	s.Empty(received)
+}
+
+func (s *AuditTestSuite) TestEmitDropsWhenQueueIsFull() {
+	// The emitter is not started, so nothing leaves the queue
+	emitter := NewEmitter("http://audit.invalid")
+	dropped := testutil.ToFloat64(auditEvents.WithLabelValues(TenantCreated, resultDropped))
+	for i := 0; i < queueSize+2; i++ {
+		emitter.Emit(Event{Type: TenantCreated})
+	}
+	s.Len(emitter.queue, queueSize)
+	s.Equal(dropped+2, testutil.ToFloat64(auditEvents.WithLabelValues(TenantCreated, resultDropped)))
+}
+
+func (s *AuditTestSuite) TestNilEmitter() {
+	var emitter *Emitter
+	emitter.Start(s.ctx)
+	emitter.Emit(Event{Type: TenantCreated})
+}
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package auth authenticates the clients of the HTTP endpoints of the controller that change projects, such as the
+// CloudEvents receiver and the admin API.
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	"github.com/open-edge-platform/orch-library/go/dazl"
+)
+
+var log = dazl.GetPackageLogger()
+
+const (
+	// time a token read from its secret is used before it is read again, so that a rotated token is picked up
+	tokenRefreshInterval = time.Minute
+	// shortest time between two reads of the secret caused by requests with another token
+	tokenRereadInterval = 10 * time.Second
+)
+
+var (
+	// ErrUnauthenticated is returned for requests without the expected bearer token
+	ErrUnauthenticated = errors.New("unauthenticated")
+)
+
+// BearerToken authenticates requests by the bearer token of their Authorization header, which must be the token
+// held by a secret. The secret is read again every minute, and at most every 10 seconds when a request has another
+// token, so that rotating the token needs no restart.
+type BearerToken struct {
+	ref config.SecretRef
+
+	mu    sync.Mutex
+	token []byte
+	read  time.Time
+}
+
+// NewBearerToken returns an authenticator checking requests against the token held by the secret.
+func NewBearerToken(ref config.SecretRef) *BearerToken {
+	return &BearerToken{ref: ref}
+}
+
+// expected returns the token of the secret, read again if it is older than maxAge.
+func (t *BearerToken) expected(ctx context.Context, maxAge time.Duration) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.token != nil && time.Since(t.read) < maxAge {
+		return t.token, nil
+	}
+	value, err := southbound.ReadSecretRef(ctx, t.ref)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read token %s: %w", t.ref, err)
+	}
+	value = bytes.TrimSpace(value)
+	if len(value) == 0 {
+		return nil, fmt.Errorf("token %s is empty", t.ref)
+	}
+	t.token = value
+	t.read = time.Now()
+	return t.token, nil
+}
+
+// Authenticate returns an error wrapping ErrUnauthenticated if the request does not have the expected bearer token,
+// or another error if the token of the secret cannot be read.
+func (t *BearerToken) Authenticate(req *http.Request) error {
+	presented, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || presented == "" {
+		return fmt.Errorf("%w: no bearer token", ErrUnauthenticated)
+	}
+	expected, err := t.expected(req.Context(), tokenRefreshInterval)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(presented), expected) == 1 {
+		return nil
+	}
+	// The token may have been rotated since it was read
+	expected, err = t.expected(req.Context(), tokenRereadInterval)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(presented), expected) == 1 {
+		return nil
+	}
+	return fmt.Errorf("%w: invalid bearer token", ErrUnauthenticated)
+}
+
+// Handler returns a handler passing the authenticated requests to next. The others are answered with 401
+// Unauthorized, or 503 Service Unavailable if the token of the secret cannot be read.
+func (t *BearerToken) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !t.Allow(w, req) {
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// Allow authenticates the request, and answers it with 401 Unauthorized or 503 Service Unavailable and returns false
+// if it may not proceed.
+func (t *BearerToken) Allow(w http.ResponseWriter, req *http.Request) bool {
+	err := t.Authenticate(req)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, ErrUnauthenticated):
+		log.Warnf("Rejected %s %s from %s: %v", req.Method, req.URL.Path, req.RemoteAddr, err)
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	default:
+		log.Errorf("Unable to authenticate %s %s: %v", req.Method, req.URL.Path, err)
+		http.Error(w, "unable to authenticate the request", http.StatusServiceUnavailable)
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/stretchr/testify/suite"
+)
+
+// Suite of bearer token authentication tests
+type TokenTestSuite struct {
+	suite.Suite
+	dir   string
+	token *BearerToken
+}
+
+func TestToken(t *testing.T) {
+	suite.Run(t, &TokenTestSuite{})
+}
+
+func (s *TokenTestSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.writeToken("first\n")
+	s.token = NewBearerToken(config.SecretRef{Key: config.DefaultTokenKey, MountPath: s.dir})
+}
+
+func (s *TokenTestSuite) writeToken(token string) {
+	s.NoError(os.WriteFile(filepath.Join(s.dir, config.DefaultTokenKey), []byte(token), 0o600))
+}
+
+func (s *TokenTestSuite) request(authorization string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/harbor-credentials/reload", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return req
+}
+
+func (s *TokenTestSuite) TestAuthenticate() {
+	s.NoError(s.token.Authenticate(s.request("Bearer first")))
+	for _, authorization := range []string{"", "Bearer ", "Bearer firs", "Bearer first2", "bearer first", "first"} {
+		s.ErrorIs(s.token.Authenticate(s.request(authorization)), ErrUnauthenticated, authorization)
+	}
+
+	// A rotated token is accepted once the secret is read again, the old one is then refused
+	s.writeToken("second")
+	s.ErrorIs(s.token.Authenticate(s.request("Bearer second")), ErrUnauthenticated)
+	s.token.read = time.Now().Add(-tokenRereadInterval)
+	s.NoError(s.token.Authenticate(s.request("Bearer second")))
+	s.token.read = time.Now().Add(-tokenRefreshInterval)
+	s.ErrorIs(s.token.Authenticate(s.request("Bearer first")), ErrUnauthenticated)
+}
+
+func (s *TokenTestSuite) TestUnreadableToken() {
+	// An empty or missing token refuses every request, without answering them as unauthenticated
+	s.writeToken("\n")
+	err := s.token.Authenticate(s.request("Bearer "))
+	s.ErrorIs(err, ErrUnauthenticated)
+	err = s.token.Authenticate(s.request("Bearer x"))
+	s.ErrorContains(err, "is empty")
+	s.NotErrorIs(err, ErrUnauthenticated)
+
+	s.NoError(os.Remove(filepath.Join(s.dir, config.DefaultTokenKey)))
+	s.ErrorContains(s.token.Authenticate(s.request("Bearer x")), "unable to read token")
+}
+
+func (s *TokenTestSuite) TestHandler() {
+	called := 0
+	handler := s.token.Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, s.request("Bearer first"))
+	s.Equal(http.StatusNoContent, recorder.Code)
+	s.Equal(1, called)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, s.request("Bearer other"))
+	s.Equal(http.StatusUnauthorized, recorder.Code)
+	s.Equal("Bearer", recorder.Header().Get("WWW-Authenticate"))
+
+	s.NoError(os.Remove(filepath.Join(s.dir, config.DefaultTokenKey)))
+	s.token.read = time.Time{}
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, s.request("Bearer first"))
+	s.Equal(http.StatusServiceUnavailable, recorder.Code)
+	s.NotContains(recorder.Body.String(), s.dir)
+	s.Equal(1, called)
+}
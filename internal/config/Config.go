@@ -5,16 +5,53 @@
 package config
 
 import (
+	"cmp"
 	"fmt"
-	"os"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/open-edge-platform/orch-library/go/dazl"
+	yaml "gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 var log = dazl.GetPackageLogger()
 
+const (
+	// RobotPolicyRecreate deletes and recreates the Harbor robot accounts given to the catalog on every create event
+	RobotPolicyRecreate = "recreate"
+	// RobotPolicyReuse keeps an existing Harbor robot account, refreshing its secret only when requested
+	RobotPolicyReuse = "reuse"
+)
+
+const (
+	// DeletionModeHard deletes the resources of a deleted project right away
+	DeletionModeHard = "hard"
+	// DeletionModeSoft deactivates the resources of a deleted project, and deletes them after the retention period
+	DeletionModeSoft = "soft"
+)
+
+// registryNameAffix matches the prefixes and suffixes of the catalog registry names
+var registryNameAffix = regexp.MustCompile(`^[a-z0-9-]*$`)
+
+// DefaultDeletionRetention is the time the resources of a deleted project are kept in the soft deletion mode
+const DefaultDeletionRetention = 7 * 24 * time.Hour
+
+const (
+	// MissingOrganizationReject reports the projects whose organization is missing or has an empty name in error on
+	// their watcher
+	MissingOrganizationReject = "reject"
+	// MissingOrganizationFallback provisions the projects whose organization is missing or has an empty name in the
+	// fallback organization
+	MissingOrganizationFallback = "fallback"
+)
+
+// DefaultMaxCatalogArtifactSize is the default maximum size of a file uploaded to the catalog, 16 MiB
+const DefaultMaxCatalogArtifactSize = 16 << 20
+
 // Configuration is a manager configuration
 type Configuration struct {
 	// service addresses. These are all addresses internal to the cluster
@@ -25,11 +62,22 @@ type Configuration struct {
 	// harbor core - REST
 	HarborServer string
 
-	// harbor namespace name
-	HarborNamespace string
+	// what to do with an existing harbor robot account when a project is provisioned again
+	HarborRobotPolicy string
+
+	// what to do with projects whose organization is missing or has an empty name
+	MissingOrganizationPolicy string
+
+	// organization of the projects whose organization is missing or has an empty name, with the fallback policy
+	FallbackOrganization string
+
+	// whether the resources of deleted projects are deleted right away, or deactivated and deleted after the
+	// retention period
+	DeletionMode string
 
-	// harbor credential name
-	HarborAdminCredential string
+	// time the resources of a deleted project are kept in the soft deletion mode, during which the deletion can be
+	// undone
+	DeletionRetention time.Duration
 
 	// keycloak server for external use - REST
 	KeycloakServer string
@@ -43,11 +91,8 @@ type Configuration struct {
 	// Service account name
 	ServiceAccount string
 
-	// keycloak server namespace
-	KeycloakNamespace string
-
-	// keycloak server secret name
-	KeycloakSecret string
+	// Kubernetes secrets holding the Harbor and Keycloak admin credentials
+	Secrets K8sSecretsRef
 
 	// app deployment manager - gRPC
 	AdmServer string
@@ -55,17 +100,36 @@ type Configuration struct {
 	// release service proxy - HTTP
 	ReleaseServiceBase string
 
+	// credential and TLS settings of the registry at the release service base
+	ReleaseServiceAccess ReleaseServiceAccess
+
 	// release service configurations
 
 	// harbor REST API external to cluster
 	HarborServerExternal string
 
+	// externally reachable Harbor registry for Helm charts, defaults to the Harbor server external to cluster
+	HarborHelmRegistryExternal string
+
+	// externally reachable Harbor registry for Docker images, defaults to the Harbor server external to cluster
+	HarborDockerRegistryExternal string
+
 	// release service root URL - used for Docker registry on release service
 	ReleaseServiceRootURL string
 
 	// release service proxy root URL - used for Helm registry on release service
 	ReleaseServiceProxyRootURL string
 
+	// release service registry for Helm charts, defaults to the release service proxy root URL
+	ReleaseServiceHelmRootURL string
+
+	// release service registry for Docker images, defaults to the release service root URL
+	ReleaseServiceImageRootURL string
+
+	// optional paths appended to the release service registries for Helm charts and Docker images
+	ReleaseServiceHelmPathPrefix  string
+	ReleaseServiceImagePathPrefix string
+
 	// path to manifest repo
 	ManifestPath string
 
@@ -81,6 +145,42 @@ type Configuration struct {
 	// number of worker threads
 	NumberWorkerThreads int
 
+	// number of project events that can wait for a free worker before new events are held back
+	EventQueueSize int
+
+	// time after which the watchdog cancels and requeues an event that is still being handled, zero disables the
+	// watchdog
+	StuckEventTimeout time.Duration
+
+	// time allowed for each interaction with the Nexus server
+	NexusTimeout time.Duration
+
+	// time allowed for each file uploaded to the catalog, which may hold large artifacts
+	CatalogUploadTimeout time.Duration
+
+	// time allowed for each ADM deployment creation
+	ADMCreateTimeout time.Duration
+
+	// time allowed for each Harbor REST call
+	HarborRequestTimeout time.Duration
+
+	// keepalive and reconnection settings of the gRPC connections to the catalog and ADM
+	GRPC GRPCSettings
+
+	// time between checks of the connection to the Nexus server, zero disables resubscribing after a lost connection
+	NexusHealthCheckInterval time.Duration
+
+	// minimum time between two writes of a project watcher that keep its status, zero writes every update
+	WatcherUpdateInterval time.Duration
+
+	// provision the projects that are already provisioned and up to date again at startup, so that configuration
+	// changes reach every project
+	StartupResync bool
+
+	// provisioning plugins that are not registered, e.g. extensions for deployments without ADM. Every plugin is
+	// registered by default
+	DisabledPlugins []string
+
 	// if this string is nonempty, provisioner will use a local manifest contianed in the string instead of using manifest from remote release service
 	UseLocalManifest string
 
@@ -88,6 +188,346 @@ type Configuration struct {
 	// When false (single-tenant mode), the tenant controller skips Nexus subscription
 	// and instead provisions a single default project at startup.
 	MultiTenancyEnabled bool
+
+	// sources of project lifecycle events in multi-tenant mode, e.g. nexus and cloudevents
+	EventSources []string
+
+	// address the CloudEvents receiver listens on when the cloudevents event source is enabled
+	CloudEventsAddress string
+
+	// bearer token the senders of CloudEvents must present
+	CloudEventsToken SecretRef
+
+	// number of events kept in the provisioning history of each project, 0 disables the history
+	HistorySize int
+
+	// address the read-only project history API listens on
+	HistoryAPIAddress string
+
+	// address the admin API listens on, next to the history API. Empty if the admin API is not served
+	AdminAPIAddress string
+
+	// bearer token the clients of the admin API must present
+	AdminToken SecretRef
+
+	// serve read-only catalog and ADM queries about the projects on the history API, run with the credentials of
+	// the controller, for troubleshooting
+	DebugQueries bool
+
+	// path to the catalog registry template. If empty, the built-in registry definitions are used
+	RegistryTemplatePath string
+
+	// path to the list of catalog applications uploaded to every new project. If empty, none are uploaded
+	StarterAppsPath string
+
+	// path to the extensions added or removed for the projects of each organization. If empty, every organization
+	// gets the extensions of the manifest
+	OrgExtensionsPath string
+
+	// maximum number of catalog registries created for a project, 0 for no limit
+	MaxCatalogRegistries int
+
+	// added before and after the names of the catalog registries created for the projects, so that the
+	// environments sharing a catalog do not collide
+	CatalogRegistryPrefix string
+	CatalogRegistrySuffix string
+
+	// maximum number of ADM deployments created for the extensions of a project, 0 for no limit
+	MaxExtensionDeployments int
+
+	// maximum size in bytes of a file uploaded to the catalog, 0 for no limit
+	MaxCatalogArtifactSize int64
+
+	// platform capacity: new projects are refused while all Harbor projects together use this many bytes or more,
+	// or while this many projects are provisioned in the catalog. 0 for no limit
+	MaxHarborStorage   int64
+	MaxCatalogProjects int
+
+	// provisioning profiles (tiers) that can be selected per project or per organization
+	ProvisioningProfiles ProvisioningProfiles
+
+	// Keycloak realms of the organizations and names of the OIDC groups that are members of the Harbor projects
+	HarborGroups HarborGroups
+
+	// resources and actions granted to the Harbor robot accounts of the projects
+	HarborRobotPermissions HarborRobotPermissions
+
+	// robot accounts created in the Harbor projects
+	HarborRobots []HarborRobot
+
+	// labels created in the Harbor projects to classify their artifacts
+	HarborLabels []HarborLabel
+
+	// keys of the project labels and annotations that are added to the labels of the project's ADM deployments
+	DeploymentLabelKeys []string
+
+	// add the organization and project UUID to the labels of the project's ADM deployments
+	DeploymentTenantLabels bool
+
+	// time allowed from receiving a project event until the project is idle again. 0 disables SLO alerts
+	ProvisioningSLO time.Duration
+
+	// URL that SLO violations are posted to. If empty, no webhook is called
+	SLOWebhookURL string
+
+	// URL of the ingestion API of the audit service that tenant lifecycle events are posted to. If empty, no audit
+	// events are sent
+	AuditURL string
+
+	// pod and namespace of the controller, used to record Kubernetes events. If empty, no events are recorded
+	PodName      string
+	PodNamespace string
+
+	// release service artifacts copied into the Harbor project of every new project. If empty, nothing is mirrored
+	MirrorArtifacts []MirrorArtifact
+
+	// Git repository created for the application configuration of every project
+	GitOps GitOps
+
+	// Kubernetes namespace created or labeled for every project
+	TenantNamespaces TenantNamespaces
+
+	// version of the controller, recorded when the migrations of existing projects are complete
+	ControllerVersion string
+
+	// configuration profile whose defaults apply to the environment variables that are not set, if any
+	Profile string
+}
+
+const (
+	// EventSourceNexus receives project events from the Nexus tenancy datamodel
+	EventSourceNexus = "nexus"
+	// EventSourceCloudEvents receives project events posted as CloudEvents over HTTP
+	EventSourceCloudEvents = "cloudevents"
+)
+
+// Provisioning plugins that can be disabled
+const (
+	PluginHarbor     = "harbor"
+	PluginCatalog    = "catalog"
+	PluginExtensions = "extensions"
+)
+
+// PluginEnabled returns true if the named provisioning plugin is registered.
+func (c Configuration) PluginEnabled(name string) bool {
+	return !slices.Contains(c.DisabledPlugins, name)
+}
+
+// HarborHelmRegistry returns the OCI URL of the Harbor registry for Helm charts, as reached from the edge nodes.
+func (c Configuration) HarborHelmRegistry() string {
+	return ociRegistryURL(c.HarborHelmRegistryExternal, c.HarborServerExternal)
+}
+
+// HarborDockerRegistry returns the OCI URL of the Harbor registry for Docker images, as reached from the edge nodes.
+func (c Configuration) HarborDockerRegistry() string {
+	return ociRegistryURL(c.HarborDockerRegistryExternal, c.HarborServerExternal)
+}
+
+// ReleaseServiceHelmRegistry returns the OCI URL of the release service registry for Helm charts, with its path
+// prefix.
+func (c Configuration) ReleaseServiceHelmRegistry() string {
+	return registryPath(cmp.Or(c.ReleaseServiceHelmRootURL, c.ReleaseServiceProxyRootURL), c.ReleaseServiceHelmPathPrefix)
+}
+
+// ReleaseServiceImageRegistry returns the OCI URL of the release service registry for Docker images, with its path
+// prefix.
+func (c Configuration) ReleaseServiceImageRegistry() string {
+	return registryPath(cmp.Or(c.ReleaseServiceImageRootURL, c.ReleaseServiceRootURL), c.ReleaseServiceImagePathPrefix)
+}
+
+// registryPath appends the path prefix, if any, to the registry root URL.
+func registryPath(rootURL string, prefix string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return rootURL
+	}
+	return strings.TrimSuffix(rootURL, "/") + "/" + prefix
+}
+
+// ociRegistryURL returns the registry URL with the https scheme replaced by oci, falling back to the Harbor server
+// external to the cluster.
+func ociRegistryURL(registry string, harborServerExternal string) string {
+	if registry == "" {
+		return strings.ReplaceAll(harborServerExternal, "https://", "oci://")
+	}
+	return strings.TrimSuffix(strings.Replace(registry, "https://", "oci://", 1), "/")
+}
+
+// EventSourceEnabled returns true if the named event source is configured. Without event sources, only Nexus is
+// enabled.
+func (c Configuration) EventSourceEnabled(name string) bool {
+	if len(c.EventSources) == 0 {
+		return name == EventSourceNexus
+	}
+	return slices.Contains(c.EventSources, name)
+}
+
+// parseEventSources reads a comma separated list of event sources.
+func parseEventSources(value string) ([]string, error) {
+	var sources []string
+	for _, source := range strings.Split(value, ",") {
+		if source = strings.TrimSpace(source); source == "" {
+			continue
+		}
+		if source != EventSourceNexus && source != EventSourceCloudEvents {
+			return nil, fmt.Errorf("invalid EVENT_SOURCES entry %q: must be %s or %s", source, EventSourceNexus, EventSourceCloudEvents)
+		}
+		if !slices.Contains(sources, source) {
+			sources = append(sources, source)
+		}
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("invalid EVENT_SOURCES value %q: at least one event source is required", value)
+	}
+	return sources, nil
+}
+
+// MirrorArtifact is an image or chart on the release service, e.g. edge-orch/en/charts/base-extensions:1.0.0
+type MirrorArtifact struct {
+	Repository string
+	Tag        string
+}
+
+func (a MirrorArtifact) String() string {
+	return a.Repository + ":" + a.Tag
+}
+
+// parseMirrorArtifacts reads a comma separated list of repository:tag references.
+func parseMirrorArtifacts(value string) ([]MirrorArtifact, error) {
+	var artifacts []MirrorArtifact
+	for _, reference := range strings.Split(value, ",") {
+		if reference = strings.TrimSpace(reference); reference == "" {
+			continue
+		}
+		i := strings.LastIndex(reference, ":")
+		if i <= 0 || i == len(reference)-1 || strings.Contains(reference[i:], "/") {
+			return nil, fmt.Errorf("invalid MIRROR_ARTIFACTS entry %q: must be repository:tag", reference)
+		}
+		artifacts = append(artifacts, MirrorArtifact{Repository: strings.TrimPrefix(reference[:i], "/"), Tag: reference[i+1:]})
+	}
+	return artifacts, nil
+}
+
+// SelectDeploymentLabels returns the project labels and annotations listed in DeploymentLabelKeys. An annotation
+// takes precedence over a label with the same key.
+func (c Configuration) SelectDeploymentLabels(labels map[string]string, annotations map[string]string) map[string]string {
+	selected := map[string]string{}
+	for _, key := range c.DeploymentLabelKeys {
+		if value, ok := labels[key]; ok {
+			selected[key] = value
+		}
+		if value, ok := annotations[key]; ok {
+			selected[key] = value
+		}
+	}
+	return selected
+}
+
+// ProvisioningProfile controls what gets provisioned for a project
+type ProvisioningProfile struct {
+	// name of the profile, filled in when the profile is selected
+	Name string `yaml:"-"`
+
+	// Harbor project storage limit in bytes. 0 leaves the Harbor default in place
+	HarborStorageLimit int64 `yaml:"harborStorageLimit"`
+
+	// signatures, HarborSigningCosign and/or HarborSigningNotation, that Harbor requires of the artifacts pulled
+	// from the project. If empty, unsigned artifacts can be pulled
+	HarborSigning []string `yaml:"harborSigning"`
+
+	// extension deployment packages to install. If empty, all packages in the manifest are installed
+	DeploymentPackages []string `yaml:"deploymentPackages"`
+
+	// ADM deployment profiles (e.g. baseline/restricted/privileged) to deploy. If empty, all are deployed
+	DeploymentProfiles []string `yaml:"deploymentProfiles"`
+}
+
+// Signatures that a provisioning profile can require of Harbor artifacts
+const (
+	HarborSigningCosign   = "cosign"
+	HarborSigningNotation = "notation"
+)
+
+// RequiresSignature reports whether Harbor requires the signature of the given format of the artifacts of the
+// projects of the profile. A nil profile requires none.
+func (p *ProvisioningProfile) RequiresSignature(format string) bool {
+	return p != nil && slices.Contains(p.HarborSigning, format)
+}
+
+// AllowsDeploymentPackage reports whether the profile installs the named deployment package.
+// A nil profile allows everything.
+func (p *ProvisioningProfile) AllowsDeploymentPackage(name string) bool {
+	if p == nil || len(p.DeploymentPackages) == 0 {
+		return true
+	}
+	return slices.Contains(p.DeploymentPackages, name)
+}
+
+// AllowsDeploymentProfile reports whether the profile deploys the named ADM deployment profile.
+// A nil profile allows everything.
+func (p *ProvisioningProfile) AllowsDeploymentProfile(name string) bool {
+	if p == nil || len(p.DeploymentProfiles) == 0 {
+		return true
+	}
+	return slices.Contains(p.DeploymentProfiles, name)
+}
+
+// ProvisioningProfiles is the registry of available provisioning profiles
+type ProvisioningProfiles struct {
+	// profile used when neither the project nor its organization selects one
+	Default string `yaml:"default"`
+
+	// organization name to profile name
+	Orgs map[string]string `yaml:"orgs"`
+
+	// profile name to profile
+	Profiles map[string]ProvisioningProfile `yaml:"profiles"`
+}
+
+// Select returns the profile for a project. A profile requested by the project takes precedence over the
+// organization mapping, which takes precedence over the default. Returns nil if no profile applies.
+func (p ProvisioningProfiles) Select(requested string, org string) *ProvisioningProfile {
+	candidates := []string{requested, p.Orgs[org], p.Default}
+	for _, name := range candidates {
+		if name == "" {
+			continue
+		}
+		profile, ok := p.Profiles[name]
+		if !ok {
+			log.Warnf("Unknown provisioning profile %s requested for organization %s, ignoring", name, org)
+			continue
+		}
+		profile.Name = name
+		return &profile
+	}
+	return nil
+}
+
+func parseProvisioningProfiles(profilesString string) (ProvisioningProfiles, error) {
+	profiles := ProvisioningProfiles{}
+	if profilesString == "" {
+		return profiles, nil
+	}
+	if err := yaml.UnmarshalStrict([]byte(profilesString), &profiles); err != nil {
+		return profiles, fmt.Errorf("invalid PROVISIONING_PROFILES: %w", err)
+	}
+	if _, ok := profiles.Profiles[profiles.Default]; profiles.Default != "" && !ok {
+		return profiles, fmt.Errorf("invalid PROVISIONING_PROFILES: default profile %s is not defined", profiles.Default)
+	}
+	for org, name := range profiles.Orgs {
+		if _, ok := profiles.Profiles[name]; !ok {
+			return profiles, fmt.Errorf("invalid PROVISIONING_PROFILES: profile %s for organization %s is not defined", name, org)
+		}
+	}
+	for name, profile := range profiles.Profiles {
+		for _, format := range profile.HarborSigning {
+			if format != HarborSigningCosign && format != HarborSigningNotation {
+				return profiles, fmt.Errorf("invalid PROVISIONING_PROFILES: harborSigning %q of profile %s must be %s or %s",
+					format, name, HarborSigningCosign, HarborSigningNotation)
+			}
+		}
+	}
+	return profiles, nil
 }
 
 // DumpConfig logs the current configuration values.
@@ -98,52 +538,182 @@ func DumpConfig(config Configuration) {
 	log.Infof("   manifestTag: %s", config.ManifestTag)
 	log.Infof("   releaseServiceRootURL: %s", config.ReleaseServiceRootURL)
 	log.Infof("   releaseServiceProxyRootURL: %s", config.ReleaseServiceProxyRootURL)
+	log.Infof("   releaseServiceHelmRegistry: %s", config.ReleaseServiceHelmRegistry())
+	log.Infof("   releaseServiceImageRegistry: %s", config.ReleaseServiceImageRegistry())
 	log.Infof("   harborServer: %s", config.HarborServer)
-	log.Infof("   harborNamespce: %s", config.HarborNamespace)
-	log.Infof("   harborAdminCredential: %s", config.HarborAdminCredential)
+	log.Infof("   harborAdminCredential: %s", config.Secrets.HarborAdmin)
 	log.Infof("   vaultServer: %s", config.VaultServer)
 	log.Infof("   serviceAccount: %s", config.ServiceAccount)
 	log.Infof("   harborServerExternal: %s", config.HarborServerExternal)
+	log.Infof("   harborHelmRegistryExternal: %s", config.HarborHelmRegistryExternal)
+	log.Infof("   harborDockerRegistryExternal: %s", config.HarborDockerRegistryExternal)
 	log.Infof("   catalogServer: %s", config.CatalogServer)
 	log.Infof("   keycloakServer: %s", config.KeycloakServer)
 	log.Infof("   keycloakServiceBase: %s", config.KeycloakServiceBase)
-	log.Infof("   keycloakNamespace: %s", config.KeycloakNamespace)
-	log.Infof("   keycloakSecret: %s", config.KeycloakSecret)
+	log.Infof("   keycloakSecret: %s", config.Secrets.KeycloakAdmin)
 	log.Infof("   admServer: %s", config.AdmServer)
 	log.Infof("   releaseServiceBase: %s", config.ReleaseServiceBase)
+	log.Infof("   releaseServiceAccess: %s", config.ReleaseServiceAccess)
 	log.Infof("   initialSleepInterval: %s", config.InitialSleepInterval)
 	log.Infof("   maxWaitTime: %s", config.MaxWaitTime)
 	log.Infof("   numberWorkerThreads: %d", config.NumberWorkerThreads)
+	log.Infof("   eventQueueSize: %d", config.EventQueueSize)
+	log.Infof("   stuckEventTimeout: %s", config.StuckEventTimeout)
+	log.Infof("   nexusTimeout: %s", config.NexusTimeout)
+	log.Infof("   catalogUploadTimeout: %s", config.CatalogUploadTimeout)
+	log.Infof("   admCreateTimeout: %s", config.ADMCreateTimeout)
+	log.Infof("   harborRequestTimeout: %s", config.HarborRequestTimeout)
+	log.Infof("   grpc: %s", config.GRPC)
+	log.Infof("   nexusHealthCheckInterval: %s", config.NexusHealthCheckInterval)
+	log.Infof("   watcherUpdateInterval: %s", config.WatcherUpdateInterval)
+	log.Infof("   startupResync: %v", config.StartupResync)
+	log.Infof("   disabledPlugins: %v", config.DisabledPlugins)
 	log.Infof("   useLocalManifest: %s", config.UseLocalManifest)
 	log.Infof("   multiTenancyEnabled: %v", config.MultiTenancyEnabled)
+	log.Infof("   eventSources: %v", config.EventSources)
+	log.Infof("   cloudEventsAddress: %s", config.CloudEventsAddress)
+	log.Infof("   cloudEventsToken: %s", config.CloudEventsToken)
+	log.Infof("   historySize: %d", config.HistorySize)
+	log.Infof("   historyAPIAddress: %s", config.HistoryAPIAddress)
+	log.Infof("   adminAPIAddress: %s", config.AdminAPIAddress)
+	log.Infof("   adminToken: %s", config.AdminToken)
+	log.Infof("   debugQueries: %v", config.DebugQueries)
+	log.Infof("   registryTemplatePath: %s", config.RegistryTemplatePath)
+	log.Infof("   starterAppsPath: %s", config.StarterAppsPath)
+	log.Infof("   orgExtensionsPath: %s", config.OrgExtensionsPath)
+	log.Infof("   maxCatalogRegistries: %d", config.MaxCatalogRegistries)
+	log.Infof("   catalogRegistryPrefix: %s", config.CatalogRegistryPrefix)
+	log.Infof("   catalogRegistrySuffix: %s", config.CatalogRegistrySuffix)
+	log.Infof("   maxExtensionDeployments: %d", config.MaxExtensionDeployments)
+	log.Infof("   maxCatalogArtifactSize: %d", config.MaxCatalogArtifactSize)
+	log.Infof("   maxHarborStorage: %d", config.MaxHarborStorage)
+	log.Infof("   maxCatalogProjects: %d", config.MaxCatalogProjects)
+	log.Infof("   harborRobotPolicy: %s", config.HarborRobotPolicy)
+	log.Infof("   missingOrganizationPolicy: %s", config.MissingOrganizationPolicy)
+	log.Infof("   fallbackOrganization: %s", config.FallbackOrganization)
+	log.Infof("   deletionMode: %s", config.DeletionMode)
+	log.Infof("   deletionRetention: %s", config.DeletionRetention)
+	log.Infof("   provisioningProfiles: %+v", config.ProvisioningProfiles)
+	log.Infof("   harborGroups: %+v", config.HarborGroups)
+	log.Infof("   harborRobotPermissions: %s", config.HarborRobotPermissions)
+	log.Infof("   harborRobots: %s", FormatRobots(config.HarborRobots))
+	log.Infof("   harborLabels: %s", FormatHarborLabels(config.HarborLabels))
+	log.Infof("   deploymentLabelKeys: %v", config.DeploymentLabelKeys)
+	log.Infof("   deploymentTenantLabels: %v", config.DeploymentTenantLabels)
+	log.Infof("   provisioningSLO: %s", config.ProvisioningSLO)
+	log.Infof("   sloWebhookURL: %s", config.SLOWebhookURL)
+	log.Infof("   auditURL: %s", config.AuditURL)
+	log.Infof("   podName: %s", config.PodName)
+	log.Infof("   podNamespace: %s", config.PodNamespace)
+	log.Infof("   controllerVersion: %s", config.ControllerVersion)
+	log.Infof("   mirrorArtifacts: %v", config.MirrorArtifacts)
+	log.Infof("   gitOps: %s", config.GitOps)
+	log.Infof("   tenantNamespaces: %s", config.TenantNamespaces)
+	log.Infof("   profile: %s", config.Profile)
 }
 
 func InitConfig() (Configuration, error) {
 	config := Configuration{}
-	config.ReleaseServiceRootURL = os.Getenv("RS_ROOT_URL")
-	config.ReleaseServiceProxyRootURL = os.Getenv("RS_PROXY_ROOT_URL")
-	config.ManifestPath = os.Getenv("MANIFEST_PATH")
-	config.ManifestTag = os.Getenv("MANIFEST_TAG")
-	config.HarborServerExternal = os.Getenv("REGISTRY_HOST_EXTERNAL")
-	config.CatalogServer = os.Getenv("CATALOG_SERVER")
-	config.HarborServer = os.Getenv("HARBOR_SERVER")
-	config.HarborNamespace = os.Getenv("HARBOR_NAMESPACE")
-	config.HarborAdminCredential = os.Getenv("HARBOR_ADMIN_CREDENTIAL")
-	config.KeycloakServer = os.Getenv("KEYCLOAK_SERVER")
-	config.KeycloakServiceBase = os.Getenv("KEYCLOAK_SERVICE_BASE")
-	config.KeycloakNamespace = os.Getenv("KEYCLOAK_NAMESPACE")
-	config.KeycloakSecret = os.Getenv("KEYCLOAK_SECRET")
-	config.AdmServer = os.Getenv("ADM_SERVER")
-	config.VaultServer = os.Getenv("VAULT_SERVER")
-	config.ReleaseServiceBase = os.Getenv("RELEASE_SERVICE_BASE")
-	config.ServiceAccount = os.Getenv("SERVICE_ACCOUNT")
-	config.UseLocalManifest = os.Getenv("USE_LOCAL_MANIFEST")
+	env, err := newEnvironment()
+	if err != nil {
+		return config, err
+	}
+	config.Profile = env.profile
+	config.ReleaseServiceRootURL = env.get("RS_ROOT_URL")
+	config.ReleaseServiceProxyRootURL = env.get("RS_PROXY_ROOT_URL")
+	config.ReleaseServiceHelmRootURL = env.get("RS_HELM_ROOT_URL")
+	config.ReleaseServiceImageRootURL = env.get("RS_IMAGE_ROOT_URL")
+	config.ReleaseServiceHelmPathPrefix = env.get("RS_HELM_PATH_PREFIX")
+	config.ReleaseServiceImagePathPrefix = env.get("RS_IMAGE_PATH_PREFIX")
+	config.ManifestPath = env.get("MANIFEST_PATH")
+	config.ManifestTag = env.get("MANIFEST_TAG")
+	config.HarborServerExternal = env.get("REGISTRY_HOST_EXTERNAL")
+	config.HarborHelmRegistryExternal = env.get("REGISTRY_HELM_HOST_EXTERNAL")
+	config.HarborDockerRegistryExternal = env.get("REGISTRY_DOCKER_HOST_EXTERNAL")
+	config.CatalogServer = env.get("CATALOG_SERVER")
+	config.HarborServer = env.get("HARBOR_SERVER")
+	config.KeycloakServer = env.get("KEYCLOAK_SERVER")
+	config.KeycloakServiceBase = env.get("KEYCLOAK_SERVICE_BASE")
+	config.Secrets = K8sSecretsRef{
+		HarborAdmin: secretRefFromEnv(env, "HARBOR_NAMESPACE", "HARBOR_ADMIN_CREDENTIAL", "HARBOR_ADMIN_CREDENTIAL_KEY",
+			"HARBOR_ADMIN_CREDENTIAL_PATH", DefaultHarborAdminCredentialKey),
+		KeycloakAdmin: secretRefFromEnv(env, "KEYCLOAK_NAMESPACE", "KEYCLOAK_SECRET", "KEYCLOAK_SECRET_KEY",
+			"KEYCLOAK_SECRET_PATH", DefaultKeycloakSecretKey),
+	}
+	config.AdmServer = env.get("ADM_SERVER")
+	config.VaultServer = env.get("VAULT_SERVER")
+	config.ReleaseServiceBase = env.get("RELEASE_SERVICE_BASE")
+	config.ServiceAccount = env.get("SERVICE_ACCOUNT")
+	config.UseLocalManifest = env.get("USE_LOCAL_MANIFEST")
+	config.RegistryTemplatePath = env.get("REGISTRY_TEMPLATE_PATH")
+	config.CatalogRegistryPrefix = env.get("CATALOG_REGISTRY_PREFIX")
+	if !registryNameAffix.MatchString(config.CatalogRegistryPrefix) {
+		return config, fmt.Errorf("invalid CATALOG_REGISTRY_PREFIX value %q: must be lower case letters, digits or '-'", config.CatalogRegistryPrefix)
+	}
+	config.CatalogRegistrySuffix = env.get("CATALOG_REGISTRY_SUFFIX")
+	if !registryNameAffix.MatchString(config.CatalogRegistrySuffix) {
+		return config, fmt.Errorf("invalid CATALOG_REGISTRY_SUFFIX value %q: must be lower case letters, digits or '-'", config.CatalogRegistrySuffix)
+	}
+	config.StarterAppsPath = env.get("STARTER_APPS_PATH")
+	config.OrgExtensionsPath = env.get("ORG_EXTENSIONS_PATH")
+	config.SLOWebhookURL = env.get("SLO_WEBHOOK_URL")
+	config.AuditURL = env.get("AUDIT_URL")
+	config.PodName = env.get("POD_NAME")
+	config.PodNamespace = env.get("POD_NAMESPACE")
+	config.ControllerVersion = env.get("CONTROLLER_VERSION")
+
+	config.HarborRobotPolicy = env.get("HARBOR_ROBOT_POLICY")
+	if config.HarborRobotPolicy == "" {
+		config.HarborRobotPolicy = RobotPolicyRecreate
+	}
+	if config.HarborRobotPolicy != RobotPolicyRecreate && config.HarborRobotPolicy != RobotPolicyReuse {
+		return config, fmt.Errorf("invalid HARBOR_ROBOT_POLICY value %q: must be %s or %s", config.HarborRobotPolicy, RobotPolicyRecreate, RobotPolicyReuse)
+	}
+
+	config.MissingOrganizationPolicy = env.get("MISSING_ORGANIZATION_POLICY")
+	if config.MissingOrganizationPolicy == "" {
+		config.MissingOrganizationPolicy = MissingOrganizationReject
+	}
+	config.FallbackOrganization = env.get("FALLBACK_ORGANIZATION")
+	switch config.MissingOrganizationPolicy {
+	case MissingOrganizationReject:
+		if config.FallbackOrganization != "" {
+			return config, fmt.Errorf("FALLBACK_ORGANIZATION %q is only used with MISSING_ORGANIZATION_POLICY %s", config.FallbackOrganization, MissingOrganizationFallback)
+		}
+	case MissingOrganizationFallback:
+		if len(validation.IsDNS1123Label(config.FallbackOrganization)) > 0 {
+			return config, fmt.Errorf("invalid FALLBACK_ORGANIZATION value %q: must be a non-empty DNS label with MISSING_ORGANIZATION_POLICY %s", config.FallbackOrganization, MissingOrganizationFallback)
+		}
+	default:
+		return config, fmt.Errorf("invalid MISSING_ORGANIZATION_POLICY value %q: must be %s or %s", config.MissingOrganizationPolicy, MissingOrganizationReject, MissingOrganizationFallback)
+	}
+
+	config.DeletionMode = env.get("DELETION_MODE")
+	if config.DeletionMode == "" {
+		config.DeletionMode = DeletionModeHard
+	}
+	if config.DeletionMode != DeletionModeHard && config.DeletionMode != DeletionModeSoft {
+		return config, fmt.Errorf("invalid DELETION_MODE value %q: must be %s or %s", config.DeletionMode, DeletionModeHard, DeletionModeSoft)
+	}
+	// The deactivated projects are found through their inventory, which is kept in the controller namespace
+	if config.DeletionMode == DeletionModeSoft && config.PodNamespace == "" {
+		return config, fmt.Errorf("DELETION_MODE %s needs POD_NAMESPACE to record the deactivated projects", DeletionModeSoft)
+	}
+	// DELETION_RETENTION is optional, in seconds
+	config.DeletionRetention = DefaultDeletionRetention
+	if retentionString := env.get("DELETION_RETENTION"); retentionString != "" {
+		retention, err := strconv.Atoi(retentionString)
+		if err != nil || retention < 1 {
+			return config, fmt.Errorf("invalid DELETION_RETENTION value %q: must be a positive number of seconds", retentionString)
+		}
+		config.DeletionRetention = time.Duration(retention) * time.Second
+	}
 
 	// MultiTenancyEnabled defaults to true for backward compatibility.
 	// Set MULTI_TENANCY_ENABLED=false to run in single-tenant mode (skips Nexus subscription).
         // Accepts any value recognised by strconv.ParseBool (true/false/1/0/TRUE/FALSE etc.).
         // Unset or empty defaults to true; an unrecognised value is a fatal misconfiguration.
-        multiTenancyEnabledStr := os.Getenv("MULTI_TENANCY_ENABLED")
+        multiTenancyEnabledStr := env.get("MULTI_TENANCY_ENABLED")
         if multiTenancyEnabledStr == "" {
                 config.MultiTenancyEnabled = true
         } else {
@@ -155,7 +725,94 @@ func InitConfig() (Configuration, error) {
         }
 
 
-	initialSleepIntervalString := os.Getenv("INITIAL_SLEEP_INTERVAL")
+	provisioningProfiles, err := parseProvisioningProfiles(env.get("PROVISIONING_PROFILES"))
+	if err != nil {
+		return config, err
+	}
+	config.ProvisioningProfiles = provisioningProfiles
+
+	harborGroups, err := parseHarborGroups(env.get("HARBOR_GROUPS"))
+	if err != nil {
+		return config, err
+	}
+	config.HarborGroups = harborGroups
+
+	harborRobotPermissions, err := parseHarborRobotPermissions(env.get("HARBOR_ROBOT_PERMISSIONS"))
+	if err != nil {
+		return config, err
+	}
+	config.HarborRobotPermissions = harborRobotPermissions
+
+	harborRobots, err := parseHarborRobots(env.get("HARBOR_ROBOTS"))
+	if err != nil {
+		return config, err
+	}
+	config.HarborRobots = harborRobots
+
+	harborLabels, err := parseHarborLabels(env.get("HARBOR_LABELS"))
+	if err != nil {
+		return config, err
+	}
+	config.HarborLabels = harborLabels
+
+	for _, key := range strings.Split(env.get("DEPLOYMENT_LABEL_KEYS"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			config.DeploymentLabelKeys = append(config.DeploymentLabelKeys, key)
+		}
+	}
+
+	// DEPLOYMENT_TENANT_LABELS is optional, disabled by default
+	if tenantLabels := env.get("DEPLOYMENT_TENANT_LABELS"); tenantLabels != "" {
+		enabled, err := strconv.ParseBool(tenantLabels)
+		if err != nil {
+			return config, fmt.Errorf("invalid DEPLOYMENT_TENANT_LABELS value %q: must be true or false", tenantLabels)
+		}
+		config.DeploymentTenantLabels = enabled
+	}
+
+	// EVENT_SOURCES is optional, Nexus only by default
+	eventSources := env.get("EVENT_SOURCES")
+	if eventSources == "" {
+		eventSources = EventSourceNexus
+	}
+	config.EventSources, err = parseEventSources(eventSources)
+	if err != nil {
+		return config, err
+	}
+	config.CloudEventsAddress = env.get("CLOUDEVENTS_ADDRESS")
+	if config.CloudEventsAddress == "" {
+		config.CloudEventsAddress = ":8090"
+	}
+	config.CloudEventsToken, err = parseTokenSecret(env, "CLOUDEVENTS_TOKEN", config.EventSourceEnabled(EventSourceCloudEvents))
+	if err != nil {
+		return config, err
+	}
+
+	mirrorArtifacts, err := parseMirrorArtifacts(env.get("MIRROR_ARTIFACTS"))
+	if err != nil {
+		return config, err
+	}
+	config.MirrorArtifacts = mirrorArtifacts
+
+	config.ReleaseServiceAccess, err = parseReleaseServiceAccess(env)
+	if err != nil {
+		return config, err
+	}
+
+	config.GitOps, err = parseGitOps(env)
+	if err != nil {
+		return config, err
+	}
+	if config.GitOps.Enabled() && config.PodNamespace == "" {
+		return config, fmt.Errorf("GITOPS_PROVIDER requires POD_NAMESPACE, the deploy keys are stored in the controller namespace")
+	}
+
+	config.TenantNamespaces, err = parseTenantNamespaces(env)
+	if err != nil {
+		return config, err
+	}
+
+	initialSleepIntervalString := env.get("INITIAL_SLEEP_INTERVAL")
 	initialSleepInterval, err := strconv.Atoi(initialSleepIntervalString)
 	if err != nil {
 		log.Errorf("Invalid sleep interval %s", initialSleepIntervalString)
@@ -163,7 +820,7 @@ func InitConfig() (Configuration, error) {
 	}
 	config.InitialSleepInterval = time.Duration(initialSleepInterval) * time.Second
 
-	maxWaitTimeString := os.Getenv("MAX_WAIT_TIME")
+	maxWaitTimeString := env.get("MAX_WAIT_TIME")
 	maxWaitTime, err := strconv.Atoi(maxWaitTimeString)
 	if err != nil {
 		log.Errorf("Invalid max wait string %s", maxWaitTimeString)
@@ -171,7 +828,7 @@ func InitConfig() (Configuration, error) {
 	}
 	config.MaxWaitTime = time.Duration(maxWaitTime) * time.Second
 
-	numberWorkerThreadsString := os.Getenv("NUMBER_WORKER_THREADS")
+	numberWorkerThreadsString := env.get("NUMBER_WORKER_THREADS")
 	numberWorkerThreads, err := strconv.Atoi(numberWorkerThreadsString)
 	if err != nil {
 		log.Errorf("Invalid number of worker threads string %s", numberWorkerThreadsString)
@@ -179,6 +836,208 @@ func InitConfig() (Configuration, error) {
 	}
 	config.NumberWorkerThreads = numberWorkerThreads
 
+	// EVENT_QUEUE_SIZE is optional
+	config.EventQueueSize = 1
+	if eventQueueSizeString := env.get("EVENT_QUEUE_SIZE"); eventQueueSizeString != "" {
+		eventQueueSize, err := strconv.Atoi(eventQueueSizeString)
+		if err != nil || eventQueueSize < 1 {
+			log.Errorf("Invalid event queue size %s", eventQueueSizeString)
+			return config, fmt.Errorf("invalid EVENT_QUEUE_SIZE value %q: must be at least 1", eventQueueSizeString)
+		}
+		config.EventQueueSize = eventQueueSize
+	}
+
+	// HISTORY_SIZE is optional
+	config.HistorySize = 50
+	if historySizeString := env.get("HISTORY_SIZE"); historySizeString != "" {
+		historySize, err := strconv.Atoi(historySizeString)
+		if err != nil || historySize < 0 {
+			log.Errorf("Invalid history size %s", historySizeString)
+			return config, fmt.Errorf("invalid HISTORY_SIZE value %q: must be 0 or more", historySizeString)
+		}
+		config.HistorySize = historySize
+	}
+	// MAX_CATALOG_REGISTRIES is optional
+	config.MaxCatalogRegistries = 20
+	if maxCatalogRegistriesString := env.get("MAX_CATALOG_REGISTRIES"); maxCatalogRegistriesString != "" {
+		maxCatalogRegistries, err := strconv.Atoi(maxCatalogRegistriesString)
+		if err != nil || maxCatalogRegistries < 0 {
+			log.Errorf("Invalid maximum catalog registries %s", maxCatalogRegistriesString)
+			return config, fmt.Errorf("invalid MAX_CATALOG_REGISTRIES value %q: must be 0 or more", maxCatalogRegistriesString)
+		}
+		config.MaxCatalogRegistries = maxCatalogRegistries
+	}
+
+	// MAX_EXTENSION_DEPLOYMENTS is optional
+	config.MaxExtensionDeployments = 50
+	if maxExtensionDeploymentsString := env.get("MAX_EXTENSION_DEPLOYMENTS"); maxExtensionDeploymentsString != "" {
+		maxExtensionDeployments, err := strconv.Atoi(maxExtensionDeploymentsString)
+		if err != nil || maxExtensionDeployments < 0 {
+			log.Errorf("Invalid maximum extension deployments %s", maxExtensionDeploymentsString)
+			return config, fmt.Errorf("invalid MAX_EXTENSION_DEPLOYMENTS value %q: must be 0 or more", maxExtensionDeploymentsString)
+		}
+		config.MaxExtensionDeployments = maxExtensionDeployments
+	}
+
+	// MAX_CATALOG_ARTIFACT_SIZE is optional
+	config.MaxCatalogArtifactSize = DefaultMaxCatalogArtifactSize
+	if maxCatalogArtifactSizeString := env.get("MAX_CATALOG_ARTIFACT_SIZE"); maxCatalogArtifactSizeString != "" {
+		maxCatalogArtifactSize, err := strconv.ParseInt(maxCatalogArtifactSizeString, 10, 64)
+		if err != nil || maxCatalogArtifactSize < 0 {
+			log.Errorf("Invalid maximum catalog artifact size %s", maxCatalogArtifactSizeString)
+			return config, fmt.Errorf("invalid MAX_CATALOG_ARTIFACT_SIZE value %q: must be 0 or more", maxCatalogArtifactSizeString)
+		}
+		config.MaxCatalogArtifactSize = maxCatalogArtifactSize
+	}
+
+	// MAX_HARBOR_STORAGE is optional, in bytes
+	if maxHarborStorageString := env.get("MAX_HARBOR_STORAGE"); maxHarborStorageString != "" {
+		maxHarborStorage, err := strconv.ParseInt(maxHarborStorageString, 10, 64)
+		if err != nil || maxHarborStorage < 0 {
+			log.Errorf("Invalid maximum Harbor storage %s", maxHarborStorageString)
+			return config, fmt.Errorf("invalid MAX_HARBOR_STORAGE value %q: must be 0 or more", maxHarborStorageString)
+		}
+		config.MaxHarborStorage = maxHarborStorage
+	}
+
+	// MAX_CATALOG_PROJECTS is optional
+	if maxCatalogProjectsString := env.get("MAX_CATALOG_PROJECTS"); maxCatalogProjectsString != "" {
+		maxCatalogProjects, err := strconv.Atoi(maxCatalogProjectsString)
+		if err != nil || maxCatalogProjects < 0 {
+			log.Errorf("Invalid maximum catalog projects %s", maxCatalogProjectsString)
+			return config, fmt.Errorf("invalid MAX_CATALOG_PROJECTS value %q: must be 0 or more", maxCatalogProjectsString)
+		}
+		if maxCatalogProjects > 0 && config.PodNamespace == "" {
+			return config, fmt.Errorf("MAX_CATALOG_PROJECTS requires POD_NAMESPACE, the catalog projects are counted from the project inventories")
+		}
+		config.MaxCatalogProjects = maxCatalogProjects
+	}
+
+	config.HistoryAPIAddress = env.get("HISTORY_API_ADDRESS")
+	if config.HistoryAPIAddress == "" {
+		config.HistoryAPIAddress = ":8091"
+	}
+	config.AdminAPIAddress = env.get("ADMIN_API_ADDRESS")
+	if config.AdminAPIAddress != "" && config.AdminAPIAddress == config.HistoryAPIAddress {
+		return config, fmt.Errorf("invalid ADMIN_API_ADDRESS value %q: must differ from HISTORY_API_ADDRESS", config.AdminAPIAddress)
+	}
+	config.AdminToken, err = parseTokenSecret(env, "ADMIN_TOKEN", config.AdminAPIAddress != "")
+	if err != nil {
+		return config, err
+	}
+
+	// NEXUS_TIMEOUT is optional, in seconds
+	config.NexusTimeout = 5 * time.Second
+	if nexusTimeoutString := env.get("NEXUS_TIMEOUT"); nexusTimeoutString != "" {
+		nexusTimeout, err := strconv.Atoi(nexusTimeoutString)
+		if err != nil || nexusTimeout < 1 {
+			log.Errorf("Invalid Nexus timeout %s", nexusTimeoutString)
+			return config, fmt.Errorf("invalid NEXUS_TIMEOUT value %q: must be a positive number of seconds", nexusTimeoutString)
+		}
+		config.NexusTimeout = time.Duration(nexusTimeout) * time.Second
+	}
+
+	// STUCK_EVENT_TIMEOUT is optional, in seconds. An event is given three times the maximum wait time by default,
+	// it should not take longer than the retries it is allowed
+	config.StuckEventTimeout = 3 * config.MaxWaitTime
+	if timeoutString := env.get("STUCK_EVENT_TIMEOUT"); timeoutString != "" {
+		timeout, err := strconv.Atoi(timeoutString)
+		if err != nil || timeout < 0 || (timeout > 0 && time.Duration(timeout)*time.Second < config.MaxWaitTime) {
+			return config, fmt.Errorf("invalid STUCK_EVENT_TIMEOUT value %q: must be a number of seconds no less than MAX_WAIT_TIME, 0 to disable", timeoutString)
+		}
+		config.StuckEventTimeout = time.Duration(timeout) * time.Second
+	}
+
+	// The southbound call timeouts are optional, in seconds
+	for _, timeout := range []struct {
+		env   string
+		value *time.Duration
+		def   time.Duration
+	}{
+		{"CATALOG_UPLOAD_TIMEOUT", &config.CatalogUploadTimeout, 300 * time.Second},
+		{"ADM_CREATE_TIMEOUT", &config.ADMCreateTimeout, 60 * time.Second},
+		{"HARBOR_REQUEST_TIMEOUT", &config.HarborRequestTimeout, 60 * time.Second},
+	} {
+		*timeout.value = timeout.def
+		if timeoutString := env.get(timeout.env); timeoutString != "" {
+			seconds, err := strconv.Atoi(timeoutString)
+			if err != nil || seconds < 1 {
+				return config, fmt.Errorf("invalid %s value %q: must be a positive number of seconds", timeout.env, timeoutString)
+			}
+			*timeout.value = time.Duration(seconds) * time.Second
+		}
+	}
+
+	config.GRPC, err = parseGRPCSettings(env)
+	if err != nil {
+		return config, err
+	}
+
+	// NEXUS_HEALTH_CHECK_INTERVAL is optional, in seconds
+	config.NexusHealthCheckInterval = 30 * time.Second
+	if intervalString := env.get("NEXUS_HEALTH_CHECK_INTERVAL"); intervalString != "" {
+		interval, err := strconv.Atoi(intervalString)
+		if err != nil || interval < 0 {
+			return config, fmt.Errorf("invalid NEXUS_HEALTH_CHECK_INTERVAL value %q: must be a number of seconds, 0 to disable", intervalString)
+		}
+		config.NexusHealthCheckInterval = time.Duration(interval) * time.Second
+	}
+
+	// WATCHER_UPDATE_INTERVAL is optional, in seconds
+	config.WatcherUpdateInterval = 10 * time.Second
+	if intervalString := env.get("WATCHER_UPDATE_INTERVAL"); intervalString != "" {
+		interval, err := strconv.Atoi(intervalString)
+		if err != nil || interval < 0 {
+			return config, fmt.Errorf("invalid WATCHER_UPDATE_INTERVAL value %q: must be a number of seconds, 0 to write every update", intervalString)
+		}
+		config.WatcherUpdateInterval = time.Duration(interval) * time.Second
+	}
+
+	// DEBUG_QUERIES is optional, disabled by default
+	if debugString := env.get("DEBUG_QUERIES"); debugString != "" {
+		debug, err := strconv.ParseBool(debugString)
+		if err != nil {
+			return config, fmt.Errorf("invalid DEBUG_QUERIES value %q: must be true or false", debugString)
+		}
+		config.DebugQueries = debug
+	}
+
+	// STARTUP_RESYNC is optional, disabled by default
+	if resyncString := env.get("STARTUP_RESYNC"); resyncString != "" {
+		resync, err := strconv.ParseBool(resyncString)
+		if err != nil {
+			return config, fmt.Errorf("invalid STARTUP_RESYNC value %q: must be true or false", resyncString)
+		}
+		config.StartupResync = resync
+	}
+
+	// ENABLE_<NAME>_PLUGIN are optional, every plugin is enabled by default
+	for _, plugin := range []string{PluginHarbor, PluginCatalog, PluginExtensions} {
+		name := "ENABLE_" + strings.ToUpper(plugin) + "_PLUGIN"
+		enabledString := env.get(name)
+		if enabledString == "" {
+			continue
+		}
+		enabled, err := strconv.ParseBool(enabledString)
+		if err != nil {
+			return config, fmt.Errorf("invalid %s value %q: must be true or false", name, enabledString)
+		}
+		if !enabled {
+			config.DisabledPlugins = append(config.DisabledPlugins, plugin)
+		}
+	}
+
+	// PROVISIONING_SLO is optional, in seconds
+	config.ProvisioningSLO = 5 * time.Minute
+	if provisioningSLOString := env.get("PROVISIONING_SLO"); provisioningSLOString != "" {
+		provisioningSLO, err := strconv.Atoi(provisioningSLOString)
+		if err != nil || provisioningSLO < 0 {
+			log.Errorf("Invalid provisioning SLO %s", provisioningSLOString)
+			return config, fmt.Errorf("invalid PROVISIONING_SLO value %q: must be a number of seconds, 0 to disable", provisioningSLOString)
+		}
+		config.ProvisioningSLO = time.Duration(provisioningSLO) * time.Second
+	}
+
 	if config.InitialSleepInterval > config.MaxWaitTime {
 		log.Errorf("Sleep interval %d must be less than max wait time %d", config.InitialSleepInterval, config.MaxWaitTime)
 		return config, fmt.Errorf("invlaid sleep interval %d must be less than max wait time %d", config.InitialSleepInterval, config.MaxWaitTime)
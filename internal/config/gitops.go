@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Git servers the GitOps repositories can be created on
+const (
+	GitOpsProviderGitea  = "gitea"
+	GitOpsProviderGitLab = "gitlab"
+)
+
+// DefaultGitOpsTokenKey is the default key of the GitOps API token secret
+const DefaultGitOpsTokenKey = "token"
+
+// GitOps configures the Git repositories created for the application configuration of each project
+type GitOps struct {
+	// gitea or gitlab. If empty, no repositories are created
+	Provider string
+	// URL of the Git server, e.g. https://gitea.example.com
+	Server string
+	// organization (Gitea) or group (GitLab) the repositories are created in
+	Owner string
+	// API token of a user allowed to create repositories in the owner
+	Token SecretRef
+	// deploy keys may only pull from the repositories
+	DeployKeyReadOnly bool
+}
+
+// Enabled returns true if a GitOps repository is created for every project.
+func (g GitOps) Enabled() bool {
+	return g.Provider != ""
+}
+
+func (g GitOps) String() string {
+	if !g.Enabled() {
+		return "disabled"
+	}
+	return fmt.Sprintf("%s %s owner %s token %s deployKeyReadOnly %v", g.Provider, g.Server, g.Owner, g.Token, g.DeployKeyReadOnly)
+}
+
+// parseGitOps reads the GitOps settings from the environment. The server, owner and token secret are required
+// once a provider is set.
+func parseGitOps(env environment) (GitOps, error) {
+	gitOps := GitOps{
+		Provider: env.get("GITOPS_PROVIDER"),
+		Server:   env.get("GITOPS_SERVER"),
+		Owner:    env.get("GITOPS_OWNER"),
+		Token: secretRefFromEnv(env, "GITOPS_NAMESPACE", "GITOPS_TOKEN_SECRET", "GITOPS_TOKEN_KEY", "GITOPS_TOKEN_PATH",
+			DefaultGitOpsTokenKey),
+		DeployKeyReadOnly: true,
+	}
+	if !gitOps.Enabled() {
+		return gitOps, nil
+	}
+	if gitOps.Provider != GitOpsProviderGitea && gitOps.Provider != GitOpsProviderGitLab {
+		return gitOps, fmt.Errorf("invalid GITOPS_PROVIDER %q: must be %s or %s", gitOps.Provider, GitOpsProviderGitea, GitOpsProviderGitLab)
+	}
+	if u, err := url.Parse(gitOps.Server); err != nil || u.Scheme == "" || u.Host == "" {
+		return gitOps, fmt.Errorf("invalid GITOPS_SERVER %q: must be an absolute URL", gitOps.Server)
+	}
+	if gitOps.Owner == "" {
+		return gitOps, fmt.Errorf("GITOPS_OWNER is required with GITOPS_PROVIDER %s", gitOps.Provider)
+	}
+	if !gitOps.Token.Mounted() && (gitOps.Token.Namespace == "" || gitOps.Token.Name == "") {
+		return gitOps, fmt.Errorf("GITOPS_NAMESPACE and GITOPS_TOKEN_SECRET are required with GITOPS_PROVIDER %s", gitOps.Provider)
+	}
+	if readOnly := env.get("GITOPS_DEPLOY_KEY_READ_ONLY"); readOnly != "" {
+		value, err := strconv.ParseBool(readOnly)
+		if err != nil {
+			return gitOps, fmt.Errorf("invalid GITOPS_DEPLOY_KEY_READ_ONLY %q: %w", readOnly, err)
+		}
+		gitOps.DeployKeyReadOnly = value
+	}
+	return gitOps, nil
+}
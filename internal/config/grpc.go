@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// GRPCSettings configures the gRPC connections to the catalog and the app deployment manager. The zero value uses
+// the gRPC defaults: no keepalive pings and the default connection backoff.
+type GRPCSettings struct {
+	// time without activity after which the client pings the server to check that the connection is alive, zero
+	// disables the pings
+	KeepaliveTime time.Duration
+	// time the client waits for the ping to be acknowledged before it closes the connection
+	KeepaliveTimeout time.Duration
+	// ping idle connections too, not only connections with calls in progress. The server must permit it
+	KeepalivePermitWithoutStream bool
+	// delay before the first attempt to reconnect, and the maximum delay that it backs off to
+	BackoffBaseDelay time.Duration
+	BackoffMaxDelay  time.Duration
+}
+
+func (g GRPCSettings) String() string {
+	return fmt.Sprintf("keepaliveTime %s keepaliveTimeout %s keepalivePermitWithoutStream %v backoffBaseDelay %s backoffMaxDelay %s",
+		g.KeepaliveTime, g.KeepaliveTimeout, g.KeepalivePermitWithoutStream, g.BackoffBaseDelay, g.BackoffMaxDelay)
+}
+
+// parseGRPCSettings reads the gRPC connection settings from the environment. The durations are in seconds.
+func parseGRPCSettings(env environment) (GRPCSettings, error) {
+	settings := GRPCSettings{}
+	for _, duration := range []struct {
+		env     string
+		value   *time.Duration
+		def     time.Duration
+		minimum int
+	}{
+		{"GRPC_KEEPALIVE_TIME", &settings.KeepaliveTime, 300 * time.Second, 0},
+		{"GRPC_KEEPALIVE_TIMEOUT", &settings.KeepaliveTimeout, 20 * time.Second, 1},
+		{"GRPC_BACKOFF_BASE_DELAY", &settings.BackoffBaseDelay, 1 * time.Second, 1},
+		{"GRPC_BACKOFF_MAX_DELAY", &settings.BackoffMaxDelay, 30 * time.Second, 1},
+	} {
+		*duration.value = duration.def
+		if durationString := env.get(duration.env); durationString != "" {
+			seconds, err := strconv.Atoi(durationString)
+			if err != nil || seconds < duration.minimum {
+				return settings, fmt.Errorf("invalid %s value %q: must be a number of seconds no less than %d", duration.env, durationString, duration.minimum)
+			}
+			*duration.value = time.Duration(seconds) * time.Second
+		}
+	}
+	if settings.BackoffMaxDelay < settings.BackoffBaseDelay {
+		return settings, fmt.Errorf("invalid GRPC_BACKOFF_MAX_DELAY %s: must be no less than GRPC_BACKOFF_BASE_DELAY %s",
+			settings.BackoffMaxDelay, settings.BackoffBaseDelay)
+	}
+	if permit := env.get("GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM"); permit != "" {
+		value, err := strconv.ParseBool(permit)
+		if err != nil {
+			return settings, fmt.Errorf("invalid GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM %q: %w", permit, err)
+		}
+		settings.KeepalivePermitWithoutStream = value
+	}
+	return settings, nil
+}
@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// DefaultKeycloakRealm is the realm of the organizations that are not mapped to a realm
+	DefaultKeycloakRealm = "master"
+	// DefaultHarborGroupNameTemplate names the groups of the realms that do not set a template
+	DefaultHarborGroupNameTemplate = "{{.ProjectUUID}}_Edge-{{.Role}}-Group"
+)
+
+// Harbor project member role IDs
+const (
+	HarborRoleProjectAdmin = 1
+	HarborRoleDeveloper    = 2
+	HarborRoleGuest        = 3
+	HarborRoleMaintainer   = 4
+	HarborRoleLimitedGuest = 5
+)
+
+// DefaultHarborGroupRoles are the project roles bound to OIDC groups if HarborGroups sets none
+var DefaultHarborGroupRoles = []HarborGroupRole{
+	{Role: "Operator", RoleID: HarborRoleGuest},
+	{Role: "Manager", RoleID: HarborRoleMaintainer},
+}
+
+// HarborGroupRole binds the OIDC group of a project role to a Harbor project member role
+type HarborGroupRole struct {
+	// name of the role, the .Role variable of the group name template, e.g. Viewer
+	Role string `yaml:"role"`
+	// Harbor role given to the group, e.g. HarborRoleGuest
+	RoleID int `yaml:"roleID"`
+	// Go template of the OIDC group name of the role. If empty, the template of the realm is used
+	NameTemplate string `yaml:"nameTemplate"`
+}
+
+// HarborGroupRealm is a Keycloak realm whose groups are bound to Harbor projects
+type HarborGroupRealm struct {
+	// Go template of the OIDC group name of a project role. If empty, DefaultHarborGroupNameTemplate is used
+	NameTemplate string `yaml:"nameTemplate"`
+}
+
+// HarborGroups maps organizations to the Keycloak realms holding their groups, and names the OIDC groups that
+// are made members of the Harbor projects.
+type HarborGroups struct {
+	// realm of the organizations that are not mapped. If empty, DefaultKeycloakRealm is used
+	DefaultRealm string `yaml:"defaultRealm"`
+
+	// organization name to realm name
+	Orgs map[string]string `yaml:"orgs"`
+
+	// realm name to realm
+	Realms map[string]HarborGroupRealm `yaml:"realms"`
+
+	// project roles whose groups are made members of the Harbor projects. If empty, DefaultHarborGroupRoles is used
+	Roles []HarborGroupRole `yaml:"roles"`
+}
+
+// HarborGroupData are the variables of the group name templates
+type HarborGroupData struct {
+	Organization string
+	Project      string
+	ProjectUUID  string
+	Realm        string
+	// name of the project role, Operator or Manager unless other roles are configured
+	Role string
+}
+
+// Realm returns the realm of the organization.
+func (g HarborGroups) Realm(org string) string {
+	if realm := g.Orgs[org]; realm != "" {
+		return realm
+	}
+	if g.DefaultRealm != "" {
+		return g.DefaultRealm
+	}
+	return DefaultKeycloakRealm
+}
+
+// GroupRoles returns the project roles whose groups are made members of the Harbor projects.
+func (g HarborGroups) GroupRoles() []HarborGroupRole {
+	if len(g.Roles) > 0 {
+		return g.Roles
+	}
+	return DefaultHarborGroupRoles
+}
+
+// GroupName returns the OIDC group name of a project role, using the template of the role if it has one, and
+// otherwise the template of the realm of the organization.
+func (g HarborGroups) GroupName(org string, project string, projectUUID string, role string) (string, error) {
+	realm := g.Realm(org)
+	owner := "realm " + realm
+	nameTemplate := g.Realms[realm].NameTemplate
+	for _, groupRole := range g.GroupRoles() {
+		if groupRole.Role == role && groupRole.NameTemplate != "" {
+			owner = "role " + role
+			nameTemplate = groupRole.NameTemplate
+		}
+	}
+	if nameTemplate == "" {
+		nameTemplate = DefaultHarborGroupNameTemplate
+	}
+	return executeGroupNameTemplate(owner, nameTemplate, HarborGroupData{
+		Organization: org,
+		Project:      project,
+		ProjectUUID:  projectUUID,
+		Realm:        realm,
+		Role:         role,
+	})
+}
+
+// executeGroupNameTemplate names a group with the template of the owner, the realm or role that sets it.
+func executeGroupNameTemplate(owner string, nameTemplate string, data HarborGroupData) (string, error) {
+	t, err := template.New(owner).Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid group name template of %s: %w", owner, err)
+	}
+	name := strings.Builder{}
+	if err := t.Execute(&name, data); err != nil {
+		return "", fmt.Errorf("invalid group name template of %s: %w", owner, err)
+	}
+	if strings.TrimSpace(name.String()) == "" {
+		return "", fmt.Errorf("invalid group name template of %s: the group name is empty", owner)
+	}
+	return name.String(), nil
+}
+
+func parseHarborGroups(groupsString string) (HarborGroups, error) {
+	groups := HarborGroups{}
+	if groupsString == "" {
+		return groups, nil
+	}
+	if err := yaml.UnmarshalStrict([]byte(groupsString), &groups); err != nil {
+		return groups, fmt.Errorf("invalid HARBOR_GROUPS: %w", err)
+	}
+	sample := HarborGroupData{Organization: "org", Project: "project", ProjectUUID: "uuid", Role: "Operator"}
+	for realm, r := range groups.Realms {
+		if r.NameTemplate == "" {
+			continue
+		}
+		sample.Realm = realm
+		if _, err := executeGroupNameTemplate("realm "+realm, r.NameTemplate, sample); err != nil {
+			return groups, fmt.Errorf("invalid HARBOR_GROUPS: %w", err)
+		}
+	}
+	for org, realm := range groups.Orgs {
+		if realm == "" {
+			return groups, fmt.Errorf("invalid HARBOR_GROUPS: organization %s has no realm", org)
+		}
+	}
+	roles := map[string]bool{}
+	for _, role := range groups.Roles {
+		if role.Role == "" {
+			return groups, errors.New("invalid HARBOR_GROUPS: a role has no name")
+		}
+		if roles[role.Role] {
+			return groups, fmt.Errorf("invalid HARBOR_GROUPS: role %s is listed more than once", role.Role)
+		}
+		roles[role.Role] = true
+		if role.RoleID <= 0 {
+			return groups, fmt.Errorf("invalid HARBOR_GROUPS: role %s has no Harbor roleID", role.Role)
+		}
+		if role.NameTemplate == "" {
+			continue
+		}
+		sample.Realm = DefaultKeycloakRealm
+		sample.Role = role.Role
+		if _, err := executeGroupNameTemplate("role "+role.Role, role.NameTemplate, sample); err != nil {
+			return groups, fmt.Errorf("invalid HARBOR_GROUPS: %w", err)
+		}
+	}
+	return groups, nil
+}
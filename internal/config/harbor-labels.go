@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// HarborLabel is a label created in the Harbor project of every project, so that the artifacts pushed to it can be
+// classified. Harbor label colors are #rrggbb values.
+type HarborLabel struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Color       string `yaml:"color"`
+}
+
+// DefaultHarborLabels classify the artifacts of the extensions deployed by the orchestrator apart from the
+// applications of the customer
+var DefaultHarborLabels = []HarborLabel{
+	{Name: "extension", Description: "Artifact of an orchestrator extension", Color: "#0065FF"},
+	{Name: "customer-app", Description: "Artifact of a customer application", Color: "#00B86B"},
+}
+
+var harborLabelColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// parseHarborLabels reads the YAML list of Harbor labels. DefaultHarborLabels are used if it is not set, an empty
+// list creates no labels.
+func parseHarborLabels(labelsString string) ([]HarborLabel, error) {
+	if strings.TrimSpace(labelsString) == "" {
+		return DefaultHarborLabels, nil
+	}
+	labels := []HarborLabel{}
+	if err := yaml.UnmarshalStrict([]byte(labelsString), &labels); err != nil {
+		return nil, fmt.Errorf("invalid HARBOR_LABELS: %w", err)
+	}
+	if err := validateHarborLabels(labels); err != nil {
+		return nil, fmt.Errorf("invalid HARBOR_LABELS: %w", err)
+	}
+	return labels, nil
+}
+
+func validateHarborLabels(labels []HarborLabel) error {
+	names := []string{}
+	for _, label := range labels {
+		if label.Name == "" {
+			return errors.New("a label has no name")
+		}
+		if slices.Contains(names, label.Name) {
+			return fmt.Errorf("label %s is listed more than once", label.Name)
+		}
+		names = append(names, label.Name)
+		if label.Color != "" && !harborLabelColor.MatchString(label.Color) {
+			return fmt.Errorf("label %s color %q is not a #rrggbb value", label.Name, label.Color)
+		}
+	}
+	return nil
+}
+
+// FormatHarborLabels formats labels for the logs, e.g. "extension customer-app".
+func FormatHarborLabels(labels []HarborLabel) string {
+	names := make([]string, 0, len(labels))
+	for _, label := range labels {
+		names = append(names, label.Name)
+	}
+	return strings.Join(names, " ")
+}
@@ -0,0 +1,210 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// HarborRobotAccess lists the actions a robot account may perform on a Harbor resource, e.g. pull on repository
+type HarborRobotAccess struct {
+	Resource string   `yaml:"resource"`
+	Actions  []string `yaml:"actions"`
+}
+
+// DefaultHarborReadWriteAccess lets the read-write robot push, pull and delete the artifacts of the project
+var DefaultHarborReadWriteAccess = []HarborRobotAccess{
+	{Resource: "repository", Actions: []string{"list", "pull", "push", "delete"}},
+	{Resource: "artifact", Actions: []string{"read", "list", "delete"}},
+	{Resource: "artifact-label", Actions: []string{"create", "delete"}},
+	{Resource: "tag", Actions: []string{"create", "delete", "list"}},
+	{Resource: "scan", Actions: []string{"create", "stop"}},
+}
+
+// DefaultHarborPullAccess only lets the pull-only robot list and pull the artifacts of the project
+var DefaultHarborPullAccess = []HarborRobotAccess{
+	{Resource: "repository", Actions: []string{"list", "pull"}},
+	{Resource: "artifact", Actions: []string{"read", "list"}},
+	{Resource: "tag", Actions: []string{"list"}},
+}
+
+// HarborRobotPermissions are the permissions of the robot accounts created in the Harbor projects. Harbor checks
+// the resources and actions when the robots are created.
+type HarborRobotPermissions struct {
+	// access of the robot used by the catalog. If empty, DefaultHarborReadWriteAccess is used
+	ReadWrite []HarborRobotAccess `yaml:"readWrite"`
+	// access of the pull-only robot. If empty, DefaultHarborPullAccess is used
+	Pull []HarborRobotAccess `yaml:"pull"`
+}
+
+// ReadWriteAccess returns the access of the robot used by the catalog.
+func (p HarborRobotPermissions) ReadWriteAccess() []HarborRobotAccess {
+	if len(p.ReadWrite) > 0 {
+		return p.ReadWrite
+	}
+	return DefaultHarborReadWriteAccess
+}
+
+// PullAccess returns the access of the pull-only robot.
+func (p HarborRobotPermissions) PullAccess() []HarborRobotAccess {
+	if len(p.Pull) > 0 {
+		return p.Pull
+	}
+	return DefaultHarborPullAccess
+}
+
+// FormatRobotAccess formats robot access for the logs, e.g. "repository:list,pull tag:list".
+func FormatRobotAccess(access []HarborRobotAccess) string {
+	resources := make([]string, 0, len(access))
+	for _, a := range access {
+		resources = append(resources, a.Resource+":"+strings.Join(a.Actions, ","))
+	}
+	return strings.Join(resources, " ")
+}
+
+func (p HarborRobotPermissions) String() string {
+	return fmt.Sprintf("readWrite [%s] pull [%s]", FormatRobotAccess(p.ReadWriteAccess()), FormatRobotAccess(p.PullAccess()))
+}
+
+func parseHarborRobotPermissions(permissionsString string) (HarborRobotPermissions, error) {
+	permissions := HarborRobotPermissions{}
+	if permissionsString == "" {
+		return permissions, nil
+	}
+	if err := yaml.UnmarshalStrict([]byte(permissionsString), &permissions); err != nil {
+		return permissions, fmt.Errorf("invalid HARBOR_ROBOT_PERMISSIONS: %w", err)
+	}
+	for robot, access := range map[string][]HarborRobotAccess{"readWrite": permissions.ReadWrite, "pull": permissions.Pull} {
+		if err := validateRobotAccess(access); err != nil {
+			return permissions, fmt.Errorf("invalid HARBOR_ROBOT_PERMISSIONS: %s robot: %w", robot, err)
+		}
+	}
+	return permissions, nil
+}
+
+func validateRobotAccess(access []HarborRobotAccess) error {
+	resources := []string{}
+	for _, a := range access {
+		if a.Resource == "" {
+			return errors.New("a resource has no name")
+		}
+		if slices.Contains(resources, a.Resource) {
+			return fmt.Errorf("resource %s is listed more than once", a.Resource)
+		}
+		resources = append(resources, a.Resource)
+		if len(a.Actions) == 0 {
+			return fmt.Errorf("resource %s has no actions", a.Resource)
+		}
+		if slices.Contains(a.Actions, "") {
+			return fmt.Errorf("resource %s has an empty action", a.Resource)
+		}
+	}
+	return nil
+}
+
+// Permission profiles of the robot accounts, see HarborRobotPermissions
+const (
+	RobotProfileReadWrite = "readWrite"
+	RobotProfilePull      = "pull"
+)
+
+// Access returns the access of the robots with the permission profile.
+func (p HarborRobotPermissions) Access(profile string) []HarborRobotAccess {
+	if profile == RobotProfilePull {
+		return p.PullAccess()
+	}
+	return p.ReadWriteAccess()
+}
+
+// HarborRobot is a robot account created in the Harbor project of every project
+type HarborRobot struct {
+	Name string `yaml:"name"`
+	// permission profile of the robot, RobotProfileReadWrite or RobotProfilePull
+	Permissions string `yaml:"permissions"`
+	// whether the catalog registries are given the credentials of the robot. At most one robot of each profile may
+	// be given to the catalog: the readWrite one is used for the harborToken of the registries, the pull one for
+	// harborPullToken
+	Catalog bool `yaml:"catalog"`
+}
+
+// DefaultHarborRobots are the robot accounts created when HARBOR_ROBOTS is not set
+var DefaultHarborRobots = []HarborRobot{
+	{Name: "catalog-apps-read-write", Permissions: RobotProfileReadWrite, Catalog: true},
+	{Name: "catalog-apps-read-only", Permissions: RobotProfilePull, Catalog: true},
+}
+
+// CatalogRobot returns the robot whose credentials are given to the catalog for the permission profile, if any.
+func CatalogRobot(robots []HarborRobot, profile string) (HarborRobot, bool) {
+	for _, robot := range robots {
+		if robot.Catalog && robot.Permissions == profile {
+			return robot, true
+		}
+	}
+	return HarborRobot{}, false
+}
+
+// FormatRobots formats robot accounts for the logs, e.g. "catalog-apps-read-write:readWrite:catalog ci-push:readWrite".
+func FormatRobots(robots []HarborRobot) string {
+	formatted := make([]string, 0, len(robots))
+	for _, robot := range robots {
+		f := robot.Name + ":" + robot.Permissions
+		if robot.Catalog {
+			f += ":catalog"
+		}
+		formatted = append(formatted, f)
+	}
+	return strings.Join(formatted, " ")
+}
+
+// Harbor accepts lowercase robot names made of letters, digits, '.', '_' and '-'
+var robotNameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9._-]*[a-z0-9])?$`)
+
+func parseHarborRobots(robotsString string) ([]HarborRobot, error) {
+	if robotsString == "" {
+		return DefaultHarborRobots, nil
+	}
+	robots := []HarborRobot{}
+	if err := yaml.UnmarshalStrict([]byte(robotsString), &robots); err != nil {
+		return nil, fmt.Errorf("invalid HARBOR_ROBOTS: %w", err)
+	}
+	if err := validateRobots(robots); err != nil {
+		return nil, fmt.Errorf("invalid HARBOR_ROBOTS: %w", err)
+	}
+	return robots, nil
+}
+
+func validateRobots(robots []HarborRobot) error {
+	if len(robots) == 0 {
+		return errors.New("no robots are listed")
+	}
+	names := []string{}
+	catalogProfiles := []string{}
+	for _, robot := range robots {
+		if !robotNameRegex.MatchString(robot.Name) {
+			return fmt.Errorf("invalid robot name %q", robot.Name)
+		}
+		if slices.Contains(names, robot.Name) {
+			return fmt.Errorf("robot %s is listed more than once", robot.Name)
+		}
+		names = append(names, robot.Name)
+		if robot.Permissions != RobotProfileReadWrite && robot.Permissions != RobotProfilePull {
+			return fmt.Errorf("robot %s has invalid permissions %q, must be %s or %s", robot.Name, robot.Permissions,
+				RobotProfileReadWrite, RobotProfilePull)
+		}
+		if robot.Catalog {
+			if slices.Contains(catalogProfiles, robot.Permissions) {
+				return fmt.Errorf("more than one %s robot is given to the catalog", robot.Permissions)
+			}
+			catalogProfiles = append(catalogProfiles, robot.Permissions)
+		}
+	}
+	return nil
+}
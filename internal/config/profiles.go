@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Configuration profiles, selected with CONFIG_PROFILE
+const (
+	// ConfigProfileKind is for local and CI deployments on a kind cluster: short retries and a single worker
+	ConfigProfileKind = "kind"
+	// ConfigProfileProd is for orchestrator deployments
+	ConfigProfileProd = "prod"
+)
+
+// orchestratorServices are the addresses of the services of an orchestrator deployment, used by every profile
+var orchestratorServices = map[string]string{
+	"CATALOG_SERVER":          "catalog-service-grpc-server.orch-app.svc.cluster.local:8080",
+	"ADM_SERVER":              "app-deployment-api-grpc-server.orch-app.svc.cluster.local:8080",
+	"HARBOR_SERVER":           "http://harbor-oci-core.orch-harbor.svc.cluster.local:80",
+	"KEYCLOAK_SERVICE_BASE":   "http://platform-keycloak.orch-platform.svc.cluster.local:8080",
+	"VAULT_SERVER":            "http://vault.orch-platform.svc.cluster.local:8200",
+	"RELEASE_SERVICE_BASE":    "rs-proxy.rs-proxy.svc.cluster.local:8081",
+	"RS_ROOT_URL":             "oci://registry-rs.edgeorchestration.intel.com",
+	"RS_PROXY_ROOT_URL":       "oci://rs-proxy.rs-proxy.svc.cluster.local:8443",
+	"HARBOR_NAMESPACE":        "orch-harbor",
+	"HARBOR_ADMIN_CREDENTIAL": "harbor-admin-credential",
+	"KEYCLOAK_NAMESPACE":      "orch-platform",
+	"KEYCLOAK_SECRET":         "platform-keycloak",
+	"SERVICE_ACCOUNT":         "orch-svc",
+}
+
+// configProfiles are the defaults of the environment variables of each profile
+var configProfiles = map[string]map[string]string{
+	ConfigProfileKind: withServices(map[string]string{
+		"REGISTRY_HOST_EXTERNAL": "https://registry-oci.kind.internal",
+		"INITIAL_SLEEP_INTERVAL": "1",
+		"MAX_WAIT_TIME":          "30",
+		"NUMBER_WORKER_THREADS":  "1",
+		"NEXUS_TIMEOUT":          "5",
+		"CATALOG_UPLOAD_TIMEOUT": "60",
+		"ADM_CREATE_TIMEOUT":     "30",
+		"HARBOR_REQUEST_TIMEOUT": "30",
+		"PROVISIONING_SLO":       "0",
+	}),
+	ConfigProfileProd: withServices(map[string]string{
+		"INITIAL_SLEEP_INTERVAL": "15",
+		"MAX_WAIT_TIME":          "600",
+		"NUMBER_WORKER_THREADS":  "2",
+		"NEXUS_TIMEOUT":          "5",
+		"CATALOG_UPLOAD_TIMEOUT": "300",
+		"ADM_CREATE_TIMEOUT":     "60",
+		"HARBOR_REQUEST_TIMEOUT": "60",
+	}),
+}
+
+func withServices(defaults map[string]string) map[string]string {
+	profile := maps.Clone(orchestratorServices)
+	maps.Copy(profile, defaults)
+	return profile
+}
+
+// ConfigProfiles returns the names of the configuration profiles.
+func ConfigProfiles() []string {
+	return slices.Sorted(maps.Keys(configProfiles))
+}
+
+// environment reads the settings from the environment variables, falling back to the defaults of the selected
+// configuration profile. A variable that is set, even to an empty value, takes precedence over the profile.
+type environment struct {
+	profile  string
+	defaults map[string]string
+}
+
+// newEnvironment returns the environment of the profile named by CONFIG_PROFILE, or of no profile if it is not set.
+func newEnvironment() (environment, error) {
+	name := strings.TrimSpace(os.Getenv("CONFIG_PROFILE"))
+	if name == "" {
+		return environment{}, nil
+	}
+	defaults, ok := configProfiles[name]
+	if !ok {
+		return environment{}, fmt.Errorf("invalid CONFIG_PROFILE value %q: must be one of %s", name, strings.Join(ConfigProfiles(), ", "))
+	}
+	return environment{profile: name, defaults: defaults}, nil
+}
+
+func (e environment) get(name string) string {
+	if value, ok := os.LookupEnv(name); ok {
+		return value
+	}
+	return e.defaults[name]
+}
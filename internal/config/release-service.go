@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package config
+
+import (
+	"crypto/x509"
+	"fmt"
+	"strconv"
+)
+
+// DefaultReleaseServiceCredentialKey is the default key of the release service registry credential secret
+const DefaultReleaseServiceCredentialKey = "credential"
+
+// ReleaseServiceAccess configures how the extensions manifest and deployment packages are pulled from the release
+// service registry at the release service base. The zero value pulls anonymously over plain HTTP, as from the
+// release service proxy.
+type ReleaseServiceAccess struct {
+	// credential of the registry, as username:password. If no secret is set, the registry is pulled from anonymously
+	Credential SecretRef
+	// connect to the registry with HTTPS instead of plain HTTP
+	TLS bool
+	// PEM encoded CA certificates trusted for the registry, in addition to the system CAs
+	CACerts string
+	// do not verify the certificate of the registry
+	InsecureSkipVerify bool
+}
+
+// Anonymous returns true if the registry is pulled from without a credential.
+func (a ReleaseServiceAccess) Anonymous() bool {
+	return !a.Credential.Mounted() && a.Credential.Name == ""
+}
+
+func (a ReleaseServiceAccess) String() string {
+	credential := "anonymous"
+	if !a.Anonymous() {
+		credential = a.Credential.String()
+	}
+	return fmt.Sprintf("credential %s tls %v caCerts %v insecureSkipVerify %v", credential, a.TLS, a.CACerts != "", a.InsecureSkipVerify)
+}
+
+// parseReleaseServiceAccess reads the release service registry access settings from the environment. The CA
+// certificates and skipping verification are only valid with TLS.
+func parseReleaseServiceAccess(env environment) (ReleaseServiceAccess, error) {
+	access := ReleaseServiceAccess{
+		Credential: secretRefFromEnv(env, "RS_CREDENTIAL_NAMESPACE", "RS_CREDENTIAL_SECRET", "RS_CREDENTIAL_KEY",
+			"RS_CREDENTIAL_PATH", DefaultReleaseServiceCredentialKey),
+		CACerts: env.get("RS_CA_CERTS"),
+	}
+	if !access.Credential.Mounted() && access.Credential.Name != "" && access.Credential.Namespace == "" {
+		return access, fmt.Errorf("RS_CREDENTIAL_NAMESPACE is required with RS_CREDENTIAL_SECRET")
+	}
+	for _, flag := range []struct {
+		env   string
+		value *bool
+	}{
+		{"RS_TLS", &access.TLS},
+		{"RS_TLS_INSECURE_SKIP_VERIFY", &access.InsecureSkipVerify},
+	} {
+		if flagString := env.get(flag.env); flagString != "" {
+			value, err := strconv.ParseBool(flagString)
+			if err != nil {
+				return access, fmt.Errorf("invalid %s %q: %w", flag.env, flagString, err)
+			}
+			*flag.value = value
+		}
+	}
+	if !access.TLS && (access.CACerts != "" || access.InsecureSkipVerify) {
+		return access, fmt.Errorf("RS_CA_CERTS and RS_TLS_INSECURE_SKIP_VERIFY require RS_TLS")
+	}
+	if access.CACerts != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(access.CACerts)) {
+		return access, fmt.Errorf("invalid RS_CA_CERTS: no PEM encoded certificate found")
+	}
+	return access, nil
+}
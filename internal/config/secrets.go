@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Default keys of the secrets read by the controller
+const (
+	DefaultHarborAdminCredentialKey = "credential"
+	DefaultKeycloakSecretKey        = "admin-password"
+)
+
+// SecretRef is a key of a Kubernetes secret read by the controller.
+type SecretRef struct {
+	Namespace string
+	Name      string
+	Key       string
+	// directory the secret is mounted at. If set, the key is read from the file of the same name in the directory
+	// instead of through the Kubernetes API, for clusters where the controller may not read secrets of other
+	// namespaces
+	MountPath string
+}
+
+// Mounted returns true if the secret is read from a mounted file.
+func (r SecretRef) Mounted() bool {
+	return r.MountPath != ""
+}
+
+// File returns the path of the file holding the key of a mounted secret.
+func (r SecretRef) File() string {
+	return filepath.Join(r.MountPath, r.Key)
+}
+
+func (r SecretRef) String() string {
+	if r.Mounted() {
+		return r.File()
+	}
+	return fmt.Sprintf("%s/%s[%s]", r.Namespace, r.Name, r.Key)
+}
+
+// K8sSecretsRef are the Kubernetes secrets read by the controller.
+type K8sSecretsRef struct {
+	// Harbor admin credential, as username:password
+	HarborAdmin SecretRef
+	// Keycloak admin password
+	KeycloakAdmin SecretRef
+}
+
+// secretSetting is a secret reference with the environment variables it is read from
+type secretSetting struct {
+	// environment variables of the namespace and name
+	namespaceEnv string
+	nameEnv      string
+	ref          SecretRef
+}
+
+func secretSettings(secrets K8sSecretsRef) []secretSetting {
+	return []secretSetting{
+		{"HARBOR_NAMESPACE", "HARBOR_ADMIN_CREDENTIAL", secrets.HarborAdmin},
+		{"KEYCLOAK_NAMESPACE", "KEYCLOAK_SECRET", secrets.KeycloakAdmin},
+	}
+}
+
+// secretRefFromEnv reads a secret reference from the environment. The key defaults to defaultKey, and the secret
+// is read from a mounted file if mountPathEnv is set.
+func secretRefFromEnv(env environment, namespaceEnv string, nameEnv string, keyEnv string, mountPathEnv string, defaultKey string) SecretRef {
+	ref := SecretRef{
+		Namespace: env.get(namespaceEnv),
+		Name:      env.get(nameEnv),
+		Key:       env.get(keyEnv),
+		MountPath: env.get(mountPathEnv),
+	}
+	if ref.Key == "" {
+		ref.Key = defaultKey
+	}
+	return ref
+}
+
+// validateSecrets checks that every secret can be located: the namespace and name are required for secrets read
+// through the Kubernetes API, and the file of mounted secrets must be readable.
+func (r *ValidationReport) validateSecrets(secrets K8sSecretsRef) {
+	for _, s := range secretSettings(secrets) {
+		r.validateSecret(s)
+	}
+}
+
+func (r *ValidationReport) validateSecret(s secretSetting) {
+	if !s.ref.Mounted() {
+		if s.ref.Namespace == "" {
+			r.fail(s.namespaceEnv, "required", "not set")
+		}
+		if s.ref.Name == "" {
+			r.fail(s.nameEnv, "required", "not set")
+		}
+		return
+	}
+	if !filepath.IsAbs(s.ref.MountPath) {
+		r.fail(s.nameEnv, "secret", "mount path %q is not absolute", s.ref.MountPath)
+	} else if _, err := os.Stat(s.ref.File()); err != nil {
+		r.fail(s.nameEnv, "secret", "mounted secret %s is not readable: %v", s.ref.File(), err)
+	} else {
+		r.pass(s.nameEnv, "secret", "mounted secret %s found", s.ref.File())
+	}
+}
+
+// DefaultTokenKey is the default key of the secrets holding the bearer tokens of the HTTP endpoints
+const DefaultTokenKey = "token"
+
+// parseTokenSecret reads the reference of a bearer token secret from the environment variables with the given prefix:
+// _NAMESPACE, _SECRET, _KEY and _PATH. The secret is required if the endpoint it protects is enabled.
+func parseTokenSecret(env environment, prefix string, required bool) (SecretRef, error) {
+	ref := secretRefFromEnv(env, prefix+"_NAMESPACE", prefix+"_SECRET", prefix+"_KEY", prefix+"_PATH", DefaultTokenKey)
+	switch {
+	case !ref.Mounted() && ref.Name != "" && ref.Namespace == "":
+		return ref, fmt.Errorf("%s_NAMESPACE is required with %s_SECRET", prefix, prefix)
+	case required && !ref.Mounted() && ref.Name == "":
+		return ref, fmt.Errorf("%s_SECRET or %s_PATH is required to authenticate the requests", prefix, prefix)
+	}
+	return ref, nil
+}
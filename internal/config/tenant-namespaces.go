@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package config
+
+import (
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// maxTenantNamespacePrefix leaves room in the 63 characters of a namespace name for the organization and project
+const maxTenantNamespacePrefix = 20
+
+// TenantNamespaces configures the Kubernetes namespace of each project, labeled with the project UUID and
+// organization so that other components can colocate the resources of a project in it.
+type TenantNamespaces struct {
+	// create a namespace for every project, or label the namespace if it already exists
+	Enabled bool
+	// prefix of the namespace names, followed by <organization>-<project>
+	Prefix string
+}
+
+func (n TenantNamespaces) String() string {
+	if !n.Enabled {
+		return "disabled"
+	}
+	return fmt.Sprintf("prefix %q", n.Prefix)
+}
+
+// parseTenantNamespaces reads the tenant namespace settings from the environment. The prefix must be usable at the
+// start of a namespace name.
+func parseTenantNamespaces(env environment) (TenantNamespaces, error) {
+	namespaces := TenantNamespaces{Prefix: env.get("TENANT_NAMESPACE_PREFIX")}
+	if enabled := env.get("TENANT_NAMESPACES"); enabled != "" {
+		value, err := strconv.ParseBool(enabled)
+		if err != nil {
+			return namespaces, fmt.Errorf("invalid TENANT_NAMESPACES %q: %w", enabled, err)
+		}
+		namespaces.Enabled = value
+	}
+	if namespaces.Prefix != "" {
+		if len(namespaces.Prefix) > maxTenantNamespacePrefix || len(validation.IsDNS1123Label(namespaces.Prefix+"x")) > 0 {
+			return namespaces, fmt.Errorf("invalid TENANT_NAMESPACE_PREFIX %q: must be at most %d lower case letters, digits or '-', starting with a letter or digit",
+				namespaces.Prefix, maxTenantNamespacePrefix)
+		}
+	}
+	return namespaces, nil
+}
@@ -0,0 +1,305 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// ValidationCheck is the outcome of checking one configuration setting
+type ValidationCheck struct {
+	// environment variable of the setting
+	Setting string
+	// what was checked, e.g. "url" or "dns"
+	Check   string
+	Passed  bool
+	Message string
+}
+
+// ValidationReport lists the outcome of the configuration checks
+type ValidationReport struct {
+	Checks []ValidationCheck
+}
+
+func (r *ValidationReport) pass(setting string, check string, format string, args ...interface{}) {
+	r.Checks = append(r.Checks, ValidationCheck{Setting: setting, Check: check, Passed: true, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *ValidationReport) fail(setting string, check string, format string, args ...interface{}) {
+	r.Checks = append(r.Checks, ValidationCheck{Setting: setting, Check: check, Message: fmt.Sprintf(format, args...)})
+}
+
+// Failed returns true if any check failed.
+func (r *ValidationReport) Failed() bool {
+	return slices.ContainsFunc(r.Checks, func(c ValidationCheck) bool { return !c.Passed })
+}
+
+// Err returns an error listing the failed checks, or nil if all checks passed.
+func (r *ValidationReport) Err() error {
+	var failures []string
+	for _, c := range r.Checks {
+		if !c.Passed {
+			failures = append(failures, fmt.Sprintf("%s: %s", c.Setting, c.Message))
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration: %s", strings.Join(failures, "; "))
+}
+
+// Print writes the report as a table.
+func (r *ValidationReport) Print(out io.Writer) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "RESULT\tSETTING\tCHECK\tMESSAGE")
+	for _, c := range r.Checks {
+		result := "PASS"
+		if !c.Passed {
+			result = "FAIL"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result, c.Setting, c.Check, c.Message)
+	}
+	_ = w.Flush()
+}
+
+// setting is a configuration value with the environment variable it is read from
+type setting struct {
+	env   string
+	value string
+}
+
+func urlSettings(config Configuration) []setting {
+	return []setting{
+		{"HARBOR_SERVER", config.HarborServer},
+		{"KEYCLOAK_SERVER", config.KeycloakServer},
+		{"KEYCLOAK_SERVICE_BASE", config.KeycloakServiceBase},
+		{"VAULT_SERVER", config.VaultServer},
+		{"REGISTRY_HOST_EXTERNAL", config.HarborServerExternal},
+		{"RS_ROOT_URL", config.ReleaseServiceRootURL},
+		{"RS_PROXY_ROOT_URL", config.ReleaseServiceProxyRootURL},
+		{"SLO_WEBHOOK_URL", config.SLOWebhookURL},
+		{"AUDIT_URL", config.AuditURL},
+	}
+}
+
+// registrySettings are the OCI registries reached from the edge nodes, which may not resolve inside the cluster
+func registrySettings(config Configuration) []setting {
+	return []setting{
+		{"REGISTRY_HELM_HOST_EXTERNAL", config.HarborHelmRegistryExternal},
+		{"REGISTRY_DOCKER_HOST_EXTERNAL", config.HarborDockerRegistryExternal},
+	}
+}
+
+// releaseServiceSettings are the OCI registries of the release service, which may be on other hosts for Helm charts
+// and Docker images
+func releaseServiceSettings(config Configuration) []setting {
+	return []setting{
+		{"RS_ROOT_URL", config.ReleaseServiceRootURL},
+		{"RS_PROXY_ROOT_URL", config.ReleaseServiceProxyRootURL},
+		{"RS_HELM_ROOT_URL", config.ReleaseServiceHelmRootURL},
+		{"RS_IMAGE_ROOT_URL", config.ReleaseServiceImageRootURL},
+	}
+}
+
+func hostPortSettings(config Configuration) []setting {
+	return []setting{
+		{"CATALOG_SERVER", config.CatalogServer},
+		{"ADM_SERVER", config.AdmServer},
+		{"RELEASE_SERVICE_BASE", config.ReleaseServiceBase},
+	}
+}
+
+// Validate checks the configuration without contacting any service: required settings, URL and address
+// formats, and intervals that must be consistent with each other.
+func Validate(config Configuration) ValidationReport {
+	report := ValidationReport{}
+
+	required := []setting{
+		{"CATALOG_SERVER", config.CatalogServer},
+		{"HARBOR_SERVER", config.HarborServer},
+	}
+	if config.UseLocalManifest == "" {
+		required = append(required, setting{"MANIFEST_PATH", config.ManifestPath}, setting{"MANIFEST_TAG", config.ManifestTag})
+	}
+	for _, s := range required {
+		if s.value == "" {
+			report.fail(s.env, "required", "not set")
+		}
+	}
+	report.validateSecrets(config.Secrets)
+	if !config.ReleaseServiceAccess.Anonymous() {
+		report.validateSecret(secretSetting{"RS_CREDENTIAL_NAMESPACE", "RS_CREDENTIAL_SECRET", config.ReleaseServiceAccess.Credential})
+	}
+
+	for _, s := range urlSettings(config) {
+		if s.value == "" {
+			continue
+		}
+		u, err := url.Parse(s.value)
+		switch {
+		case err != nil:
+			report.fail(s.env, "url", "%v", err)
+		case u.Scheme == "" || u.Host == "":
+			report.fail(s.env, "url", "%q is not an absolute URL with a scheme and host", s.value)
+		default:
+			report.pass(s.env, "url", "%s", s.value)
+		}
+	}
+
+	for _, s := range registrySettings(config) {
+		if s.value == "" {
+			continue
+		}
+		u, err := url.Parse(s.value)
+		switch {
+		case err != nil:
+			report.fail(s.env, "registry", "%v", err)
+		case u.Scheme != "oci" && u.Scheme != "https" || u.Host == "":
+			report.fail(s.env, "registry", "%q is not an oci:// or https:// URL with a host", s.value)
+		case strings.Trim(u.Path, "/") != "" || u.RawQuery != "":
+			report.fail(s.env, "registry", "%q must not have a path, the Harbor project is appended to it", s.value)
+		default:
+			report.pass(s.env, "registry", "%s", s.value)
+		}
+	}
+
+	for _, s := range releaseServiceSettings(config) {
+		if s.value == "" {
+			continue
+		}
+		u, err := url.Parse(s.value)
+		switch {
+		case err != nil:
+			report.fail(s.env, "oci", "%v", err)
+		case u.Scheme != "oci" || u.Host == "":
+			report.fail(s.env, "oci", "%q is not an oci:// URL with a host", s.value)
+		case u.RawQuery != "" || u.Fragment != "":
+			report.fail(s.env, "oci", "%q must not have a query or fragment", s.value)
+		default:
+			report.pass(s.env, "oci", "%s", s.value)
+		}
+	}
+	for _, s := range []setting{
+		{"RS_HELM_PATH_PREFIX", config.ReleaseServiceHelmPathPrefix},
+		{"RS_IMAGE_PATH_PREFIX", config.ReleaseServiceImagePathPrefix},
+	} {
+		if s.value == "" {
+			continue
+		}
+		if strings.Contains(s.value, "://") || strings.ContainsAny(s.value, "?# ") {
+			report.fail(s.env, "path", "%q is not a registry path", s.value)
+		} else {
+			report.pass(s.env, "path", "%s", s.value)
+		}
+	}
+
+	for _, s := range hostPortSettings(config) {
+		if s.value == "" {
+			continue
+		}
+		if strings.Contains(s.value, "://") {
+			report.fail(s.env, "address", "%q must be host:port without a scheme", s.value)
+		} else if host, port, err := net.SplitHostPort(s.value); err != nil || host == "" || port == "" {
+			report.fail(s.env, "address", "%q is not a host:port address", s.value)
+		} else {
+			report.pass(s.env, "address", "%s", s.value)
+		}
+	}
+
+	if config.InitialSleepInterval <= 0 {
+		report.fail("INITIAL_SLEEP_INTERVAL", "interval", "must be positive, got %s", config.InitialSleepInterval)
+	} else if config.InitialSleepInterval > config.MaxWaitTime {
+		report.fail("INITIAL_SLEEP_INTERVAL", "interval", "%s is longer than MAX_WAIT_TIME %s", config.InitialSleepInterval, config.MaxWaitTime)
+	} else {
+		report.pass("INITIAL_SLEEP_INTERVAL", "interval", "%s, at most MAX_WAIT_TIME %s", config.InitialSleepInterval, config.MaxWaitTime)
+	}
+	if config.NexusTimeout > config.MaxWaitTime {
+		report.fail("NEXUS_TIMEOUT", "interval", "%s is longer than MAX_WAIT_TIME %s", config.NexusTimeout, config.MaxWaitTime)
+	}
+	if config.NumberWorkerThreads < 1 {
+		report.fail("NUMBER_WORKER_THREADS", "range", "must be at least 1, got %d", config.NumberWorkerThreads)
+	}
+	if config.EventQueueSize < 1 {
+		report.fail("EVENT_QUEUE_SIZE", "range", "must be at least 1, got %d", config.EventQueueSize)
+	}
+	if config.HistorySize > 0 {
+		if _, port, err := net.SplitHostPort(config.HistoryAPIAddress); err != nil || port == "" {
+			report.fail("HISTORY_API_ADDRESS", "address", "%q is not a [host]:port listen address", config.HistoryAPIAddress)
+		} else {
+			report.pass("HISTORY_API_ADDRESS", "address", "%s", config.HistoryAPIAddress)
+		}
+	}
+	if len(config.MirrorArtifacts) > 0 && !config.PluginEnabled(PluginHarbor) {
+		report.fail("MIRROR_ARTIFACTS", "plugins", "artifacts are mirrored into Harbor, which needs ENABLE_HARBOR_PLUGIN")
+	}
+	if config.EventSourceEnabled(EventSourceCloudEvents) {
+		if _, port, err := net.SplitHostPort(config.CloudEventsAddress); err != nil || port == "" {
+			report.fail("CLOUDEVENTS_ADDRESS", "address", "%q is not a [host]:port listen address", config.CloudEventsAddress)
+		} else {
+			report.pass("CLOUDEVENTS_ADDRESS", "address", "%s", config.CloudEventsAddress)
+		}
+	}
+	return report
+}
+
+// EnvironmentChecks are the lookups used to check the configuration against the cluster
+type EnvironmentChecks struct {
+	LookupHost func(ctx context.Context, host string) ([]string, error)
+	ReadSecret func(ctx context.Context, namespace string, name string) (map[string][]byte, error)
+}
+
+// CheckEnvironment adds checks that the service host names resolve and that the required secrets exist.
+func (r *ValidationReport) CheckEnvironment(ctx context.Context, config Configuration, checks EnvironmentChecks) {
+	for _, s := range slices.Concat(urlSettings(config), hostPortSettings(config)) {
+		if s.value == "" {
+			continue
+		}
+		host := s.value
+		if u, err := url.Parse(s.value); err == nil && u.Host != "" {
+			host = u.Hostname()
+		} else if h, _, err := net.SplitHostPort(s.value); err == nil {
+			host = h
+		}
+		if net.ParseIP(host) != nil {
+			continue
+		}
+		if _, err := checks.LookupHost(ctx, host); err != nil {
+			r.fail(s.env, "dns", "%s does not resolve: %v", host, err)
+		} else {
+			r.pass(s.env, "dns", "%s resolves", host)
+		}
+	}
+
+	// Mounted secrets are checked by Validate
+	for _, s := range secretSettings(config.Secrets) {
+		if s.ref.Name == "" || s.ref.Mounted() {
+			continue
+		}
+		data, err := checks.ReadSecret(ctx, s.ref.Namespace, s.ref.Name)
+		if err != nil {
+			r.fail(s.nameEnv, "secret", "unable to read secret %s/%s: %v", s.ref.Namespace, s.ref.Name, err)
+		} else if _, ok := data[s.ref.Key]; !ok {
+			r.fail(s.nameEnv, "secret", "secret %s/%s has no %s key", s.ref.Namespace, s.ref.Name, s.ref.Key)
+		} else {
+			r.pass(s.nameEnv, "secret", "secret %s/%s found", s.ref.Namespace, s.ref.Name)
+		}
+	}
+}
+
+// InitConfigStrict reads the configuration like InitConfig and also fails if Validate finds problems.
+func InitConfigStrict() (Configuration, error) {
+	config, err := InitConfig()
+	if err != nil {
+		return config, err
+	}
+	report := Validate(config)
+	return config, report.Err()
+}
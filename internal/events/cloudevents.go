@@ -0,0 +1,270 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/auth"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	nexushook "github.com/open-edge-platform/app-orch-tenant-controller/internal/nexus"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// CloudEvents types of the project lifecycle events
+	ProjectCreatedEventType = "io.open-edge-platform.project.created"
+	ProjectUpdatedEventType = "io.open-edge-platform.project.updated"
+	ProjectDeletedEventType = "io.open-edge-platform.project.deleted"
+
+	cloudEventsContentType = "application/cloudevents+json"
+	// largest event body accepted, project events are small
+	maxCloudEventSize = 1 << 20
+)
+
+var cloudEventsReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "tenant_controller_cloudevents_received_total",
+	Help: "Project CloudEvents received over HTTP, by event type and result",
+}, []string{"type", "result"})
+
+func init() {
+	metrics.Registry.MustRegister(cloudEventsReceived)
+}
+
+// ProjectEventData is the data of a project CloudEvent. Labels and annotations select the provisioning profile and
+// deployment labels in the same way as those of a Nexus project.
+type ProjectEventData struct {
+	Organization string            `json:"organization"`
+	Name         string            `json:"name"`
+	UUID         string            `json:"uuid"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+}
+
+// cloudEvent holds the context attributes of an event in structured content mode
+type cloudEvent struct {
+	SpecVersion string          `json:"specversion"`
+	ID          string          `json:"id"`
+	Source      string          `json:"source"`
+	Type        string          `json:"type"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// CloudEventsReceiver receives project events posted as CloudEvents 1.0 over HTTP, in binary or structured
+// content mode, and passes them to the project manager. An event is acknowledged with 202 Accepted once the
+// manager has accepted it; the sender should retry events answered with 503 Service Unavailable. Senders must
+// present the bearer token of the receiver, events without it are answered with 401 Unauthorized.
+//
+// The receiver keeps the last version of each project it has seen so that the changes of an update can be
+// worked out. This state is not persisted: after a restart, the first update of a project is handled as if all
+// of its labels and annotations changed.
+type CloudEventsReceiver struct {
+	address  string
+	projects nexushook.ProjectManager
+	token    *auth.BearerToken
+
+	mu   sync.Mutex
+	seen map[string]*ExternalProject
+}
+
+// NewCloudEventsReceiver returns a receiver listening on the given address, accepting the events of senders with the
+// token.
+func NewCloudEventsReceiver(address string, projects nexushook.ProjectManager, token *auth.BearerToken) *CloudEventsReceiver {
+	return &CloudEventsReceiver{
+		address:  address,
+		projects: projects,
+		token:    token,
+		seen:     map[string]*ExternalProject{},
+	}
+}
+
+func (r *CloudEventsReceiver) Name() string {
+	return config.EventSourceCloudEvents
+}
+
+// Start listens on the receiver address and serves events until the context is done.
+func (r *CloudEventsReceiver) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", r.address)
+	if err != nil {
+		return fmt.Errorf("unable to listen for CloudEvents on %s: %w", r.address, err)
+	}
+	server := &http.Server{
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("CloudEvents receiver stopped: %v", err)
+		}
+	}()
+	log.Infof("Receiving project CloudEvents on %s", listener.Addr())
+	return nil
+}
+
+// ServeHTTP handles a single CloudEvent.
+func (r *CloudEventsReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if !r.token.Allow(w, req) {
+		cloudEventsReceived.WithLabelValues("unknown", "unauthorized").Inc()
+		return
+	}
+	eventType, data, status, err := readCloudEvent(req)
+	if err != nil {
+		cloudEventsReceived.WithLabelValues(eventType, "rejected").Inc()
+		log.Warnf("Rejected project CloudEvent %s: %v", eventType, err)
+		http.Error(w, err.Error(), status)
+		return
+	}
+	if err := r.dispatch(req.Context(), eventType, data); err != nil {
+		cloudEventsReceived.WithLabelValues(eventType, "unavailable").Inc()
+		log.Warnf("Unable to accept project CloudEvent %s for project %s: %v", eventType, data.Name, err)
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	cloudEventsReceived.WithLabelValues(eventType, "accepted").Inc()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// readCloudEvent returns the type and project data of the posted event, or the HTTP status to reply with.
+func readCloudEvent(req *http.Request) (string, ProjectEventData, int, error) {
+	data := ProjectEventData{}
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		return "", data, http.StatusUnsupportedMediaType, fmt.Errorf("invalid content type: %w", err)
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxCloudEventSize+1))
+	if err != nil {
+		return "", data, http.StatusBadRequest, fmt.Errorf("unable to read event: %w", err)
+	}
+	if len(body) > maxCloudEventSize {
+		return "", data, http.StatusRequestEntityTooLarge, fmt.Errorf("event is larger than %d bytes", maxCloudEventSize)
+	}
+
+	var event cloudEvent
+	switch mediaType {
+	case cloudEventsContentType:
+		if err := json.Unmarshal(body, &event); err != nil {
+			return "", data, http.StatusBadRequest, fmt.Errorf("invalid structured event: %w", err)
+		}
+	case "application/json":
+		event = cloudEvent{
+			SpecVersion: req.Header.Get("ce-specversion"),
+			ID:          req.Header.Get("ce-id"),
+			Source:      req.Header.Get("ce-source"),
+			Type:        req.Header.Get("ce-type"),
+			Data:        body,
+		}
+	default:
+		return "", data, http.StatusUnsupportedMediaType, fmt.Errorf("content type %s is not supported, use %s or application/json", mediaType, cloudEventsContentType)
+	}
+
+	if event.SpecVersion != "1.0" {
+		return event.Type, data, http.StatusBadRequest, fmt.Errorf("CloudEvents spec version %q is not supported", event.SpecVersion)
+	}
+	if event.ID == "" || event.Source == "" {
+		return event.Type, data, http.StatusBadRequest, fmt.Errorf("event id and source are required")
+	}
+	switch event.Type {
+	case ProjectCreatedEventType, ProjectUpdatedEventType, ProjectDeletedEventType:
+	default:
+		return "unknown", data, http.StatusBadRequest, fmt.Errorf("event type %q is not a project event", event.Type)
+	}
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return event.Type, data, http.StatusBadRequest, fmt.Errorf("invalid project data: %w", err)
+	}
+	if err := data.Validate(); err != nil {
+		return event.Type, data, http.StatusBadRequest, err
+	}
+	return event.Type, data, http.StatusAccepted, nil
+}
+
+// Validate applies the same limits as the Nexus hook to the names of the project.
+func (d ProjectEventData) Validate() error {
+	switch {
+	case d.Organization == "":
+		return fmt.Errorf("organization name is empty")
+	case strings.Contains(d.Organization, "\n"):
+		return fmt.Errorf("organization name contains illegal characters")
+	case len(d.Organization) > nexushook.MaxOrganizationNameLength:
+		return fmt.Errorf("organization name is too long")
+	case d.Name == "":
+		return fmt.Errorf("project name is empty")
+	case strings.Contains(d.Name, "\n"):
+		return fmt.Errorf("project name contains illegal characters")
+	case len(d.Name) > nexushook.MaxProjectNameLength:
+		return fmt.Errorf("project name is too long")
+	case d.UUID == "":
+		return fmt.Errorf("project UUID is empty")
+	case len(d.UUID) > nexushook.MaxProjectUUIDLength:
+		return fmt.Errorf("project UUID is too long")
+	}
+	return nil
+}
+
+// dispatch passes the event to the project manager. The last seen version of the project is only updated once the
+// manager accepted the event, so that a retried update reports the same changes.
+func (r *CloudEventsReceiver) dispatch(ctx context.Context, eventType string, data ProjectEventData) error {
+	project := &ExternalProject{
+		Organization: data.Organization,
+		Name:         data.Name,
+		UUID:         data.UUID,
+		Labels:       data.Labels,
+		Annotations:  data.Annotations,
+		Deleted:      eventType == ProjectDeletedEventType,
+	}
+	log.Infof("Received %s CloudEvent for project %s in organization %s", eventType, data.Name, data.Organization)
+
+	switch eventType {
+	case ProjectCreatedEventType:
+		if err := r.projects.CreateProject(ctx, data.Organization, data.Name, data.UUID, project); err != nil {
+			return err
+		}
+	case ProjectUpdatedEventType:
+		r.mu.Lock()
+		previous, ok := r.seen[data.UUID]
+		r.mu.Unlock()
+		if !ok {
+			previous = &ExternalProject{}
+		}
+		changes := nexushook.DiffProjects(previous, project)
+		if err := r.projects.UpdateProject(ctx, data.Organization, data.Name, data.UUID, project, changes); err != nil {
+			return err
+		}
+	case ProjectDeletedEventType:
+		if err := r.projects.DeleteProject(ctx, data.Organization, data.Name, data.UUID, project); err != nil {
+			return err
+		}
+		r.mu.Lock()
+		delete(r.seen, data.UUID)
+		r.mu.Unlock()
+		return nil
+	}
+	r.mu.Lock()
+	r.seen[data.UUID] = project
+	r.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/auth"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	nexushook "github.com/open-edge-platform/app-orch-tenant-controller/internal/nexus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+// Suite of CloudEvents receiver tests
+type CloudEventsTestSuite struct {
+	suite.Suite
+	manager  *recordingManager
+	receiver *CloudEventsReceiver
+}
+
+func (s *CloudEventsTestSuite) SetupTest() {
+	s.manager = &recordingManager{}
+	tokenDir := s.T().TempDir()
+	s.NoError(os.WriteFile(filepath.Join(tokenDir, config.DefaultTokenKey), []byte("s3cret\n"), 0o600))
+	token := auth.NewBearerToken(config.SecretRef{Key: config.DefaultTokenKey, MountPath: tokenDir})
+	s.receiver = NewCloudEventsReceiver("127.0.0.1:0", s.manager, token)
+}
+
+func TestCloudEvents(t *testing.T) {
+	suite.Run(t, &CloudEventsTestSuite{})
+}
+
+// projectCall is a call made to the recording manager
+type projectCall struct {
+	eventType string
+	org       string
+	name      string
+	uuid      string
+	project   nexushook.NexusProjectInterface
+	changes   nexushook.ProjectChanges
+}
+
+// recordingManager records the project events it receives, failing them while err is set
+type recordingManager struct {
+	calls []projectCall
+	err   error
+}
+
+func (m *recordingManager) record(call projectCall) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.calls = append(m.calls, call)
+	return nil
+}
+
+func (m *recordingManager) CreateProject(_ context.Context, org string, name string, uuid string, project nexushook.NexusProjectInterface) error {
+	return m.record(projectCall{eventType: "create", org: org, name: name, uuid: uuid, project: project})
+}
+
+func (m *recordingManager) UpdateProject(_ context.Context, org string, name string, uuid string, project nexushook.NexusProjectInterface, changes nexushook.ProjectChanges) error {
+	return m.record(projectCall{eventType: "update", org: org, name: name, uuid: uuid, project: project, changes: changes})
+}
+
+func (m *recordingManager) DeleteProject(_ context.Context, org string, name string, uuid string, project nexushook.NexusProjectInterface) error {
+	return m.record(projectCall{eventType: "delete", org: org, name: name, uuid: uuid, project: project})
+}
+
+func (m *recordingManager) EnsureProject(_ context.Context, org string, name string, uuid string, project nexushook.NexusProjectInterface) error {
+	return m.record(projectCall{eventType: "ensure", org: org, name: name, uuid: uuid, project: project})
+}
+
+func (m *recordingManager) ManifestTag() string {
+	return "1.0.0"
+}
+
+func (m *recordingManager) ControllerVersion() string {
+	return "1.0.0"
+}
+
+// postBinary posts an event in binary content mode
+func (s *CloudEventsTestSuite) postBinary(eventType string, data string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("ce-specversion", "1.0")
+	req.Header.Set("ce-id", "event-1")
+	req.Header.Set("ce-source", "/iam")
+	req.Header.Set("ce-type", eventType)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	recorder := httptest.NewRecorder()
+	s.receiver.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// postStructured posts an event in structured content mode
+func (s *CloudEventsTestSuite) postStructured(event string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(event))
+	req.Header.Set("Content-Type", "application/cloudevents+json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer s3cret")
+	recorder := httptest.NewRecorder()
+	s.receiver.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func (s *CloudEventsTestSuite) TestProjectLifecycle() {
+	accepted := testutil.ToFloat64(cloudEventsReceived.WithLabelValues(ProjectCreatedEventType, "accepted"))
+
+	response := s.postBinary(ProjectCreatedEventType,
+		`{"organization":"org1","name":"project1","uuid":"uuid-1","labels":{"region":"eu"},"annotations":{"app-orch-tenant-controller/provisioning-profile":"small"}}`)
+	s.Equal(http.StatusAccepted, response.Code)
+	s.Len(s.manager.calls, 1)
+	created := s.manager.calls[0]
+	s.Equal("create", created.eventType)
+	s.Equal("org1", created.org)
+	s.Equal("project1", created.name)
+	s.Equal("uuid-1", created.uuid)
+	s.Equal("project1", created.project.DisplayName())
+	s.Equal("uuid-1", created.project.GetUID())
+	s.Equal(map[string]string{"region": "eu"}, created.project.GetLabels())
+	s.False(created.project.IsDeleted())
+	s.Equal(accepted+1, testutil.ToFloat64(cloudEventsReceived.WithLabelValues(ProjectCreatedEventType, "accepted")))
+
+	// The changes of an update are worked out from the last version of the project
+	response = s.postStructured(`{"specversion":"1.0","id":"event-2","source":"/iam","type":"` + ProjectUpdatedEventType + `",
+		"data":{"organization":"org1","name":"project1","uuid":"uuid-1","labels":{"region":"eu"},"annotations":{"app-orch-tenant-controller/provisioning-profile":"large"}}}`)
+	s.Equal(http.StatusAccepted, response.Code)
+	s.Len(s.manager.calls, 2)
+	updated := s.manager.calls[1]
+	s.Equal("update", updated.eventType)
+	s.True(updated.changes.AnnotationChanged(nexushook.ProvisioningProfileAnnotationKey))
+	s.Equal(nexushook.ProjectChange{Old: "small", New: "large"}, updated.changes.Annotations[nexushook.ProvisioningProfileAnnotationKey])
+	s.Empty(updated.changes.Labels)
+
+	response = s.postBinary(ProjectDeletedEventType, `{"organization":"org1","name":"project1","uuid":"uuid-1"}`)
+	s.Equal(http.StatusAccepted, response.Code)
+	s.Len(s.manager.calls, 3)
+	s.Equal("delete", s.manager.calls[2].eventType)
+	s.True(s.manager.calls[2].project.IsDeleted())
+	s.Empty(s.receiver.seen)
+}
+
+func (s *CloudEventsTestSuite) TestUpdateOfUnknownProject() {
+	response := s.postBinary(ProjectUpdatedEventType, `{"organization":"org1","name":"project1","uuid":"uuid-1","labels":{"region":"eu"}}`)
+	s.Equal(http.StatusAccepted, response.Code)
+	s.Len(s.manager.calls, 1)
+	s.Equal(nexushook.ProjectChange{New: "eu"}, s.manager.calls[0].changes.Labels["region"])
+}
+
+func (s *CloudEventsTestSuite) TestManagerUnavailable() {
+	s.postBinary(ProjectCreatedEventType, `{"organization":"org1","name":"project1","uuid":"uuid-1","labels":{"region":"eu"}}`)
+
+	s.manager.err = errors.New("queue is full")
+	response := s.postBinary(ProjectUpdatedEventType, `{"organization":"org1","name":"project1","uuid":"uuid-1","labels":{"region":"us"}}`)
+	s.Equal(http.StatusServiceUnavailable, response.Code)
+
+	// The retried event reports the same changes
+	s.manager.err = nil
+	response = s.postBinary(ProjectUpdatedEventType, `{"organization":"org1","name":"project1","uuid":"uuid-1","labels":{"region":"us"}}`)
+	s.Equal(http.StatusAccepted, response.Code)
+	s.Len(s.manager.calls, 2)
+	s.Equal(nexushook.ProjectChange{Old: "eu", New: "us"}, s.manager.calls[1].changes.Labels["region"])
+}
+
+func (s *CloudEventsTestSuite) TestInvalidEvents() {
+	valid := `{"organization":"org1","name":"project1","uuid":"uuid-1"}`
+
+	s.Equal(http.StatusBadRequest, s.postBinary("io.example.other", valid).Code)
+	s.Equal(http.StatusBadRequest, s.postBinary(ProjectCreatedEventType, `{"organization":"org1","name":"project1"}`).Code)
+	s.Equal(http.StatusBadRequest, s.postBinary(ProjectCreatedEventType, `{"organization":"org1","name":"project\n1","uuid":"uuid-1"}`).Code)
+	s.Equal(http.StatusBadRequest, s.postBinary(ProjectCreatedEventType, `{"organization":"`+strings.Repeat("o", 64)+`","name":"project1","uuid":"uuid-1"}`).Code)
+	s.Equal(http.StatusBadRequest, s.postBinary(ProjectCreatedEventType, `not json`).Code)
+	s.Equal(http.StatusBadRequest, s.postStructured(`{"specversion":"0.3","id":"1","source":"/iam","type":"`+ProjectCreatedEventType+`","data":`+valid+`}`).Code)
+	s.Equal(http.StatusBadRequest, s.postStructured(`{"specversion":"1.0","type":"`+ProjectCreatedEventType+`","data":`+valid+`}`).Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(valid))
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("Authorization", "Bearer s3cret")
+	recorder := httptest.NewRecorder()
+	s.receiver.ServeHTTP(recorder, req)
+	s.Equal(http.StatusUnsupportedMediaType, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	s.receiver.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	s.Equal(http.StatusMethodNotAllowed, recorder.Code)
+
+	s.Empty(s.manager.calls)
+}
+
+func (s *CloudEventsTestSuite) TestUnauthenticatedEvents() {
+	unauthorized := testutil.ToFloat64(cloudEventsReceived.WithLabelValues("unknown", "unauthorized"))
+	valid := `{"organization":"org1","name":"project1","uuid":"uuid-1"}`
+
+	for _, authorization := range []string{"", "Bearer", "Bearer other", "Basic czNjcmV0", "s3cret"} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(valid))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("ce-specversion", "1.0")
+		req.Header.Set("ce-id", "event-1")
+		req.Header.Set("ce-source", "/iam")
+		req.Header.Set("ce-type", ProjectDeletedEventType)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		recorder := httptest.NewRecorder()
+		s.receiver.ServeHTTP(recorder, req)
+		s.Equal(http.StatusUnauthorized, recorder.Code, authorization)
+		s.Equal("Bearer", recorder.Header().Get("WWW-Authenticate"))
+	}
+	s.Empty(s.manager.calls)
+	s.Equal(unauthorized+5, testutil.ToFloat64(cloudEventsReceived.WithLabelValues("unknown", "unauthorized")))
+
+	// Events are refused if the token cannot be read
+	receiver := NewCloudEventsReceiver("127.0.0.1:0", s.manager, auth.NewBearerToken(config.SecretRef{Key: "token", MountPath: s.T().TempDir()}))
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(valid))
+	req.Header.Set("Authorization", "Bearer s3cret")
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, req)
+	s.Equal(http.StatusServiceUnavailable, recorder.Code)
+	s.Empty(s.manager.calls)
+}
+
+func (s *CloudEventsTestSuite) TestStart() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.NoError(s.receiver.Start(ctx))
+
+	s.ErrorContains(NewCloudEventsReceiver("not-an-address", s.manager, s.receiver.token).Start(ctx), "unable to listen for CloudEvents")
+}
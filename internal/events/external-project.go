@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package events
+
+import (
+	"context"
+	"fmt"
+
+	nexushook "github.com/open-edge-platform/app-orch-tenant-controller/internal/nexus"
+	projectActiveWatcherv1 "github.com/open-edge-platform/orch-utils/tenancy-datamodel/build/apis/projectactivewatcher.edge-orchestrator.intel.com/v1"
+)
+
+// ExternalProject is a project that is managed outside the Nexus datamodel, e.g. by an external IAM system. It has
+// no project watcher, so the provisioning status is only reported in the logs and metrics.
+type ExternalProject struct {
+	Organization string
+	Name         string
+	UUID         string
+	Labels       map[string]string
+	Annotations  map[string]string
+	Deleted      bool
+}
+
+var _ nexushook.NexusProjectInterface = &ExternalProject{}
+
+// GetActiveWatchers returns no watcher, which the Nexus hook treats as a project it does not report status for.
+func (p *ExternalProject) GetActiveWatchers(_ context.Context, _ string) (nexushook.NexusProjectActiveWatcherInterface, error) {
+	return nil, nil
+}
+
+func (p *ExternalProject) AddActiveWatchers(_ context.Context, _ *projectActiveWatcherv1.ProjectActiveWatcher) (nexushook.NexusProjectActiveWatcherInterface, error) {
+	return nil, fmt.Errorf("external project %s has no project watchers", p.Name)
+}
+
+func (p *ExternalProject) DeleteActiveWatchers(_ context.Context, _ string) error {
+	return nil
+}
+
+func (p *ExternalProject) GetParent(_ context.Context) (nexushook.NexusFolderInterface, error) {
+	return nil, fmt.Errorf("external project %s has no Nexus folder", p.Name)
+}
+
+func (p *ExternalProject) DisplayName() string {
+	return p.Name
+}
+
+func (p *ExternalProject) GetUID() string {
+	return p.UUID
+}
+
+func (p *ExternalProject) GetAnnotations() map[string]string {
+	return p.Annotations
+}
+
+func (p *ExternalProject) GetLabels() map[string]string {
+	return p.Labels
+}
+
+func (p *ExternalProject) IsDeleted() bool {
+	return p.Deleted
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package events
+
+import (
+	"strconv"
+
+	nexushook "github.com/open-edge-platform/app-orch-tenant-controller/internal/nexus"
+)
+
+// SchemaVersionV1 is the version of the project event schema defined in this file. Fields may be added to a
+// version, but a field is never removed or given a new meaning: that needs a new version, handled alongside V1
+// until all of the sources have moved to it.
+const SchemaVersionV1 = "v1"
+
+const (
+	// Types of the project events
+	CreateProjectType  = "CreateProject"
+	UpdateProjectType  = "UpdateProject"
+	DeleteProjectType  = "DeleteProject"
+	UpdateManifestType = "UpdateManifest"
+	EnsureProjectType  = "EnsureProject"
+)
+
+// ProjectEvent is a project lifecycle event, independent of the source it came from.
+type ProjectEvent interface {
+	// SchemaVersion is the version of the schema of the event, such as SchemaVersionV1
+	SchemaVersion() string
+	// Type is the type of the event, such as CreateProjectType
+	Type() string
+	// ProjectRef is the project the event is about
+	ProjectRef() ProjectV1
+}
+
+// ProjectV1 is a project, as seen by the events of version 1 of the schema. Labels and annotations select the
+// provisioning profile and deployment labels of the project.
+type ProjectV1 struct {
+	Organization string            `json:"organization"`
+	Name         string            `json:"name"`
+	UUID         string            `json:"uuid"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+
+	// object the project was converted from, whose watcher reports the provisioning status. Nil if the source
+	// has no watcher.
+	source nexushook.NexusProjectInterface
+}
+
+// Source returns the Nexus project, or the stand-in of an external project, that the project was converted from.
+func (p ProjectV1) Source() nexushook.NexusProjectInterface {
+	return p.source
+}
+
+// CreateProjectV1 asks for a new project to be provisioned.
+type CreateProjectV1 struct {
+	Project ProjectV1 `json:"project"`
+}
+
+// UpdateProjectV1 reports changes to the labels and annotations of a provisioned project.
+type UpdateProjectV1 struct {
+	Project ProjectV1                `json:"project"`
+	Changes nexushook.ProjectChanges `json:"changes"`
+}
+
+// DeleteProjectV1 asks for the resources of a deleted project to be removed.
+type DeleteProjectV1 struct {
+	Project ProjectV1 `json:"project"`
+	// archive the Harbor project rather than delete it
+	RetainData bool `json:"retainData,omitempty"`
+}
+
+// UpdateManifestV1 asks for a provisioned project to be provisioned again, with the current manifest.
+type UpdateManifestV1 struct {
+	Project ProjectV1 `json:"project"`
+	// profile the project was provisioned with
+	Profile string `json:"profile,omitempty"`
+	// give the project new Harbor robot credentials
+	RefreshCredentials bool `json:"refreshCredentials,omitempty"`
+}
+
+// EnsureProjectV1 asks for a provisioned project to be converged to its desired state, creating what is missing
+// without deleting anything or issuing new credentials.
+type EnsureProjectV1 struct {
+	Project ProjectV1 `json:"project"`
+	// plugins that converge the project, all if empty, so that one drifted part can be fixed on its own
+	Plugins []string `json:"plugins,omitempty"`
+}
+
+func (e CreateProjectV1) SchemaVersion() string  { return SchemaVersionV1 }
+func (e CreateProjectV1) Type() string           { return CreateProjectType }
+func (e CreateProjectV1) ProjectRef() ProjectV1  { return e.Project }
+func (e UpdateProjectV1) SchemaVersion() string  { return SchemaVersionV1 }
+func (e UpdateProjectV1) Type() string           { return UpdateProjectType }
+func (e UpdateProjectV1) ProjectRef() ProjectV1  { return e.Project }
+func (e DeleteProjectV1) SchemaVersion() string  { return SchemaVersionV1 }
+func (e DeleteProjectV1) Type() string           { return DeleteProjectType }
+func (e DeleteProjectV1) ProjectRef() ProjectV1  { return e.Project }
+func (e UpdateManifestV1) SchemaVersion() string { return SchemaVersionV1 }
+func (e UpdateManifestV1) Type() string          { return UpdateManifestType }
+func (e UpdateManifestV1) ProjectRef() ProjectV1 { return e.Project }
+func (e EnsureProjectV1) SchemaVersion() string  { return SchemaVersionV1 }
+func (e EnsureProjectV1) Type() string           { return EnsureProjectType }
+func (e EnsureProjectV1) ProjectRef() ProjectV1  { return e.Project }
+
+// ProjectFromNexus converts a Nexus project, or any other implementation of the Nexus project interface, to a
+// project of version 1 of the schema. The project is kept as the source of the converted project.
+func ProjectFromNexus(organization string, name string, uuid string, project nexushook.NexusProjectInterface) ProjectV1 {
+	p := ProjectV1{
+		Organization: organization,
+		Name:         name,
+		UUID:         uuid,
+		source:       project,
+	}
+	if project != nil {
+		p.Labels = project.GetLabels()
+		p.Annotations = project.GetAnnotations()
+	}
+	return p
+}
+
+// ProjectFromEventData converts the data of a project CloudEvent to a project of version 1 of the schema. The
+// source of the converted project is an ExternalProject.
+func ProjectFromEventData(data ProjectEventData, deleted bool) ProjectV1 {
+	return ProjectV1{
+		Organization: data.Organization,
+		Name:         data.Name,
+		UUID:         data.UUID,
+		Labels:       data.Labels,
+		Annotations:  data.Annotations,
+		source: &ExternalProject{
+			Organization: data.Organization,
+			Name:         data.Name,
+			UUID:         data.UUID,
+			Labels:       data.Labels,
+			Annotations:  data.Annotations,
+			Deleted:      deleted,
+		},
+	}
+}
+
+// NewDeleteProjectV1 returns the delete event of the project, retaining its data if the project is annotated so.
+func NewDeleteProjectV1(project ProjectV1) DeleteProjectV1 {
+	retain, _ := strconv.ParseBool(project.Annotations[nexushook.RetainDataAnnotationKey])
+	return DeleteProjectV1{Project: project, RetainData: retain}
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"testing"
+
+	nexushook "github.com/open-edge-platform/app-orch-tenant-controller/internal/nexus"
+	"github.com/stretchr/testify/suite"
+)
+
+// Suite of project event schema tests
+type SchemaTestSuite struct {
+	suite.Suite
+}
+
+func TestSchema(t *testing.T) {
+	suite.Run(t, &SchemaTestSuite{})
+}
+
+func (s *SchemaTestSuite) TestProjectFromNexus() {
+	source := &ExternalProject{Labels: map[string]string{"region": "eu"}, Annotations: map[string]string{"a": "b"}}
+	project := ProjectFromNexus("org", "name", "uuid", source)
+	s.Equal("org", project.Organization)
+	s.Equal("name", project.Name)
+	s.Equal("uuid", project.UUID)
+	s.Equal(map[string]string{"region": "eu"}, project.Labels)
+	s.Equal(map[string]string{"a": "b"}, project.Annotations)
+	s.Equal(source, project.Source())
+
+	project = ProjectFromNexus("org", "name", "uuid", nil)
+	s.Nil(project.Labels)
+	s.Nil(project.Source())
+}
+
+func (s *SchemaTestSuite) TestProjectFromEventData() {
+	data := ProjectEventData{Organization: "org", Name: "name", UUID: "uuid", Labels: map[string]string{"region": "eu"}}
+	project := ProjectFromEventData(data, true)
+	s.Equal("uuid", project.UUID)
+	s.Equal(map[string]string{"region": "eu"}, project.Labels)
+	source, ok := project.Source().(*ExternalProject)
+	s.True(ok)
+	s.True(source.IsDeleted())
+	s.Equal("uuid", source.GetUID())
+}
+
+func (s *SchemaTestSuite) TestEvents() {
+	project := ProjectV1{Organization: "org", Name: "name", UUID: "uuid"}
+	for _, test := range []struct {
+		event     ProjectEvent
+		eventType string
+	}{
+		{CreateProjectV1{Project: project}, CreateProjectType},
+		{UpdateProjectV1{Project: project}, UpdateProjectType},
+		{DeleteProjectV1{Project: project}, DeleteProjectType},
+		{UpdateManifestV1{Project: project}, UpdateManifestType},
+	} {
+		s.Equal(SchemaVersionV1, test.event.SchemaVersion())
+		s.Equal(test.eventType, test.event.Type())
+		s.Equal(project, test.event.ProjectRef())
+	}
+}
+
+func (s *SchemaTestSuite) TestNewDeleteProjectV1() {
+	s.False(NewDeleteProjectV1(ProjectV1{}).RetainData)
+	s.False(NewDeleteProjectV1(ProjectV1{Annotations: map[string]string{nexushook.RetainDataAnnotationKey: "no"}}).RetainData)
+	s.True(NewDeleteProjectV1(ProjectV1{Annotations: map[string]string{nexushook.RetainDataAnnotationKey: "true"}}).RetainData)
+}
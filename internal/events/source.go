@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package events
+
+import (
+	"context"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	nexushook "github.com/open-edge-platform/app-orch-tenant-controller/internal/nexus"
+	"github.com/open-edge-platform/orch-library/go/dazl"
+)
+
+var log = dazl.GetPackageLogger()
+
+// Source delivers project lifecycle events to the project manager. Start returns once the source is receiving
+// events; the source stops when the context is done.
+type Source interface {
+	Name() string
+	Start(ctx context.Context) error
+}
+
+// NexusSource receives project events from the Nexus tenancy datamodel through the Nexus hook.
+type NexusSource struct {
+	hook *nexushook.Hook
+}
+
+// NewNexusSource returns the event source for the Nexus hook.
+func NewNexusSource(hook *nexushook.Hook) *NexusSource {
+	return &NexusSource{hook: hook}
+}
+
+func (s *NexusSource) Name() string {
+	return config.EventSourceNexus
+}
+
+// Start subscribes to the Nexus project events. The subscription lasts for the lifetime of the hook context. A
+// failed subscription is logged and not returned, so that the controller keeps serving the other sources.
+func (s *NexusSource) Start(_ context.Context) error {
+	if err := s.hook.Subscribe(); err != nil {
+		log.Errorf("Unable to subscribe to Nexus hook %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package golden compares the output of tests with reviewed expected output kept in golden files, so that a change
+// of the output shows up as a diff of the golden file rather than as scattered field assertions.
+//
+//nolint:revive // Test utility package
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Dir is the directory of the golden files, relative to the package under test
+const Dir = "testdata/golden"
+
+var update = flag.Bool("update", false, "write the golden files with the actual output of the tests")
+
+// Assert checks that got, encoded as indented JSON, is the content of the golden file Dir/<name>.json. If the tests
+// are run with -update, the golden file is written with got instead, and the change is to be reviewed like code.
+func Assert(t testing.TB, name string, got interface{}) {
+	t.Helper()
+	actual, err := json.MarshalIndent(got, "", "  ")
+	if err != nil {
+		t.Fatalf("unable to encode the output for golden file %s: %v", name, err)
+	}
+	if err := check(filepath.Join(Dir, name+".json"), append(actual, '\n'), *update); err != nil {
+		t.Error(err)
+	}
+}
+
+// check compares the actual output with the golden file at path, or writes it to the file if update is set.
+func check(path string, actual []byte, update bool) error {
+	if update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, actual, 0o600)
+	}
+	expected, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("golden file %s does not exist, run the test with -update to create it", path)
+	}
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(expected, actual) {
+		return fmt.Errorf("output does not match golden file %s, run the test with -update and review the diff if the change is expected\n--- expected\n%s\n+++ actual\n%s",
+			path, expected, actual)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package golden
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Suite of golden file tests
+type GoldenTestSuite struct {
+	suite.Suite
+}
+
+func TestGolden(t *testing.T) {
+	suite.Run(t, &GoldenTestSuite{})
+}
+
+func (s *GoldenTestSuite) TestCheck() {
+	path := filepath.Join(s.T().TempDir(), "golden", "registries.json")
+
+	err := check(path, []byte("{}\n"), false)
+	s.ErrorContains(err, "does not exist, run the test with -update")
+
+	// Updating writes the golden file, which then matches
+	s.NoError(check(path, []byte("{}\n"), true))
+	content, err := os.ReadFile(path)
+	s.NoError(err)
+	s.Equal("{}\n", string(content))
+	s.NoError(check(path, []byte("{}\n"), false))
+
+	err = check(path, []byte("{\"name\": \"harbor-helm-oci\"}\n"), false)
+	s.ErrorContains(err, "does not match golden file")
+	s.ErrorContains(err, "+++ actual\n{\"name\": \"harbor-helm-oci\"}")
+}
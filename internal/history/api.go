@@ -0,0 +1,439 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/auth"
+	"github.com/open-edge-platform/app-orch-tenant-controller/pkg/api"
+	"github.com/open-edge-platform/app-orch-tenant-controller/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// API serves the provisioning history of projects over a read-only HTTP API:
+//
+//	GET /api/v1/projects/{uuid}/history
+//	GET /api/v1/projects/{uuid}/status
+//	GET /api/v1/projects/{uuid}/export
+//	GET /api/v1/openapi.yaml
+//	GET /api/v1/openapi.json
+//
+// and the calls that change the controller over an admin API, on a separate listener that authenticates its clients:
+//
+//	POST /api/v1/admin/harbor-credentials/reload
+//	POST /api/v1/admin/projects/{uuid}/reprovision?plugin=catalog
+//	POST /api/v1/admin/projects/{uuid}/restore
+//	GET /api/v1/admin/debug/projects/{uuid}/{query}
+//
+// The first returns the History of the project as JSON, the second its provisioning state as a client.ProjectStatus.
+// Both return 404 Not Found if no events were recorded for the project, and none is in progress. The third returns the
+// export bundle of the project, if an export source is set, and 404 Not Found if the project has none. The admin reload
+// reads the Harbor admin credential again, if a reload is set, and returns whether it changed as a CredentialReload.
+// The reprovision call queues an ensure event for the project, if a reprovisioner is set, limited to the plugins given
+// in the plugin parameters, and returns 202 Accepted with a Reprovision; the status of the project follows the event.
+// The restore call queues a reactivate event for a project deleted in the soft deletion mode, if a restorer is set, and
+// returns 202 Accepted with a Reprovision, or 409 Conflict once its retention period is over. The debug call runs the
+// named read-only query about the project, if debug queries are set, such as listing its catalog registries, and
+// returns 502 Bad Gateway if the queried service fails. It is an admin call as the query reads the southbound services
+// with the credentials of the controller. The openapi calls return the OpenAPI specification of the API, from package
+// api, as YAML and JSON.
+type API struct {
+	address      string
+	adminAddress string
+	adminToken   *auth.BearerToken
+	store        Store
+	active       ActiveEvents
+	export       Export
+	harborReload CredentialReloader
+	reprovision  Reprovisioner
+	restore      Restorer
+	debug        map[string]DebugQuery
+	mux          *http.ServeMux
+	admin        *http.ServeMux
+}
+
+// ActiveEvents returns the type of the event of the project that is queued or being handled, or false if there is
+// none.
+type ActiveEvents func(uuid string) (eventType string, ok bool)
+
+// Export returns the export bundle of a project, to be encoded as JSON, or nil if the project has none.
+type Export func(ctx context.Context, uuid string) (interface{}, error)
+
+// CredentialReloader reads a credential again, returning true if it changed.
+type CredentialReloader func(ctx context.Context) (bool, error)
+
+// Reprovisioner queues an ensure event for a project, limited to the named plugins, or handled by all of them if
+// none are named. It returns an error wrapping ErrProjectNotFound if the project is not known, and
+// ErrInvalidRequest if the plugins are not.
+type Reprovisioner func(ctx context.Context, uuid string, plugins []string) error
+
+// Restorer queues a reactivate event for a project whose resources were deactivated when it was deleted, so that
+// they are kept. It returns an error wrapping ErrProjectNotFound if no deactivated project has the UUID, and
+// ErrRetentionExpired if the resources of the project are being deleted.
+type Restorer func(ctx context.Context, uuid string) error
+
+// DebugQuery runs a read-only query about a project against a southbound service, with the credentials of the
+// controller, and returns its result to be encoded as JSON.
+type DebugQuery func(ctx context.Context, uuid string) (interface{}, error)
+
+var (
+	// ErrProjectNotFound is returned for projects the controller does not know
+	ErrProjectNotFound = errors.New("project not found")
+	// ErrInvalidRequest is returned for requests that can never succeed as they are
+	ErrInvalidRequest = errors.New("invalid request")
+	// ErrRetentionExpired is returned for deleted projects whose retention period is over
+	ErrRetentionExpired = errors.New("retention period expired")
+)
+
+// Reprovision is the event queued to provision a project again.
+type Reprovision struct {
+	UUID      string `json:"uuid"`
+	EventType string `json:"eventType"`
+	// plugins that handle the event, all if empty
+	Plugins []string `json:"plugins,omitempty"`
+}
+
+// CredentialReload is the result of reloading a credential.
+type CredentialReload struct {
+	Changed bool `json:"changed"`
+}
+
+// NewAPI returns an API listening on the given address.
+func NewAPI(address string, store Store) *API {
+	a := &API{
+		address: address,
+		store:   store,
+		mux:     http.NewServeMux(),
+		admin:   http.NewServeMux(),
+	}
+	a.mux.HandleFunc("GET /api/v1/projects/{uuid}/history", a.getHistory)
+	a.mux.HandleFunc("GET /api/v1/projects/{uuid}/status", a.getStatus)
+	a.mux.HandleFunc("GET /api/v1/projects/{uuid}/export", a.getExport)
+	a.mux.HandleFunc("GET "+api.SpecPath, a.getSpec)
+	a.mux.HandleFunc("GET "+api.SpecJSONPath, a.getSpecJSON)
+	a.admin.HandleFunc("POST /api/v1/admin/harbor-credentials/reload", a.reloadHarborCredentials)
+	a.admin.HandleFunc("POST /api/v1/admin/projects/{uuid}/reprovision", a.reprovisionProject)
+	a.admin.HandleFunc("POST /api/v1/admin/projects/{uuid}/restore", a.restoreProject)
+	a.admin.HandleFunc("GET /api/v1/admin/debug/projects/{uuid}/{query}", a.debugQuery)
+	return a
+}
+
+// WithAdmin serves the admin API on a second listener at the given address, to the clients presenting the bearer
+// token. Without it, the admin calls are not served.
+func (a *API) WithAdmin(address string, token *auth.BearerToken) *API {
+	a.adminAddress = address
+	a.adminToken = token
+	return a
+}
+
+// WithActiveEvents sets the source of the events in progress, which the status of a project reports before they are
+// recorded in its history.
+func (a *API) WithActiveEvents(active ActiveEvents) *API {
+	a.active = active
+	return a
+}
+
+// WithExport sets the source of the export bundles of the projects. Without it, exports are not found.
+func (a *API) WithExport(export Export) *API {
+	a.export = export
+	return a
+}
+
+// WithHarborCredentialReload sets how the Harbor admin credential is reloaded. Without it, reloads are not found.
+func (a *API) WithHarborCredentialReload(reload CredentialReloader) *API {
+	a.harborReload = reload
+	return a
+}
+
+// WithReprovision sets how projects are provisioned again. Without it, reprovisioning requests are not found.
+func (a *API) WithReprovision(reprovision Reprovisioner) *API {
+	a.reprovision = reprovision
+	return a
+}
+
+// WithRestore sets how deleted projects are restored. Without it, restore requests are not found.
+func (a *API) WithRestore(restore Restorer) *API {
+	a.restore = restore
+	return a
+}
+
+// WithDebugQueries sets the read-only troubleshooting queries, by name. Without them, debug requests are not found.
+func (a *API) WithDebugQueries(queries map[string]DebugQuery) *API {
+	a.debug = queries
+	return a
+}
+
+// Start listens on the API address, and the admin address if it is set, and serves requests until the context is
+// done.
+func (a *API) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", a.address)
+	if err != nil {
+		return fmt.Errorf("unable to listen for history API requests on %s: %w", a.address, err)
+	}
+	var adminListener net.Listener
+	if a.adminAddress != "" {
+		adminListener, err = net.Listen("tcp", a.adminAddress)
+		if err != nil {
+			_ = listener.Close()
+			return fmt.Errorf("unable to listen for admin API requests on %s: %w", a.adminAddress, err)
+		}
+	}
+	serve(ctx, listener, a, "History")
+	log.Infof("Serving the project history API on %s", listener.Addr())
+	if adminListener != nil {
+		serve(ctx, adminListener, a.AdminHandler(), "Admin")
+		log.Infof("Serving the admin API on %s", adminListener.Addr())
+	}
+	return nil
+}
+
+// serve serves the requests of the listener with the handler until the context is done.
+func serve(ctx context.Context, listener net.Listener, handler http.Handler, name string) {
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("%s API stopped: %v", name, err)
+		}
+	}()
+}
+
+// ServeHTTP handles a single request of the read-only API.
+func (a *API) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	a.mux.ServeHTTP(w, req)
+}
+
+// AdminHandler returns the handler of the admin API, which only passes on the requests presenting the bearer token.
+func (a *API) AdminHandler() http.Handler {
+	return a.adminToken.Handler(a.admin)
+}
+
+func (a *API) getHistory(w http.ResponseWriter, req *http.Request) {
+	uuid := req.PathValue("uuid")
+	history, err := a.store.Load(req.Context(), uuid)
+	if err != nil {
+		log.Warnf("Unable to load the history of project %s: %v", uuid, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if history == nil {
+		http.Error(w, fmt.Sprintf("no history recorded for project %s", uuid), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(history)
+}
+
+func (a *API) getStatus(w http.ResponseWriter, req *http.Request) {
+	uuid := req.PathValue("uuid")
+	history, err := a.store.Load(req.Context(), uuid)
+	if err != nil {
+		log.Warnf("Unable to load the history of project %s: %v", uuid, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	activeEvent, active := "", false
+	if a.active != nil {
+		activeEvent, active = a.active(uuid)
+	}
+	status := projectStatus(uuid, history, activeEvent, active)
+	if status == nil {
+		http.Error(w, fmt.Sprintf("no events recorded for project %s", uuid), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(status)
+}
+
+func (a *API) getExport(w http.ResponseWriter, req *http.Request) {
+	uuid := req.PathValue("uuid")
+	var bundle interface{}
+	if a.export != nil {
+		var err error
+		bundle, err = a.export(req.Context(), uuid)
+		if err != nil {
+			log.Warnf("Unable to export project %s: %v", uuid, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	if bundle == nil {
+		http.Error(w, fmt.Sprintf("no export available for project %s", uuid), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(bundle)
+}
+
+func (a *API) reloadHarborCredentials(w http.ResponseWriter, req *http.Request) {
+	if a.harborReload == nil {
+		http.Error(w, "the Harbor admin credential is not used", http.StatusNotFound)
+		return
+	}
+	changed, err := a.harborReload(req.Context())
+	if err != nil {
+		log.Warnf("Unable to reload the Harbor admin credential: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(CredentialReload{Changed: changed})
+}
+
+func (a *API) reprovisionProject(w http.ResponseWriter, req *http.Request) {
+	if a.reprovision == nil {
+		http.Error(w, "reprovisioning is not enabled", http.StatusNotFound)
+		return
+	}
+	uuid := req.PathValue("uuid")
+	plugins := req.URL.Query()["plugin"]
+	if err := a.reprovision(req.Context(), uuid, plugins); err != nil {
+		switch {
+		case errors.Is(err, ErrProjectNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, ErrInvalidRequest):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			log.Warnf("Unable to reprovision project %s: %v", uuid, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	log.Infof("Reprovisioning project %s with plugins %v", uuid, plugins)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(Reprovision{UUID: uuid, EventType: "ensure", Plugins: plugins})
+}
+
+func (a *API) restoreProject(w http.ResponseWriter, req *http.Request) {
+	if a.restore == nil {
+		http.Error(w, "restoring projects is not enabled", http.StatusNotFound)
+		return
+	}
+	uuid := req.PathValue("uuid")
+	if err := a.restore(req.Context(), uuid); err != nil {
+		switch {
+		case errors.Is(err, ErrProjectNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, ErrRetentionExpired):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			log.Warnf("Unable to restore project %s: %v", uuid, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	log.Infof("Restoring project %s", uuid)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(Reprovision{UUID: uuid, EventType: "reactivate"})
+}
+
+func (a *API) debugQuery(w http.ResponseWriter, req *http.Request) {
+	name := req.PathValue("query")
+	query, ok := a.debug[name]
+	if !ok {
+		http.Error(w, fmt.Sprintf("debug query %s is not enabled", name), http.StatusNotFound)
+		return
+	}
+	uuid := req.PathValue("uuid")
+	log.Infof("Running debug query %s for project %s", name, uuid)
+	result, err := query(req.Context(), uuid)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		log.Warnf("Debug query %s for project %s failed: %v", name, uuid, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(result)
+}
+
+func (a *API) getSpec(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(api.Spec)
+}
+
+func (a *API) getSpecJSON(w http.ResponseWriter, _ *http.Request) {
+	spec, err := yaml.YAMLToJSON(api.Spec)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(spec)
+}
+
+// projectStatus works out the provisioning state of a project from the event in progress, if any, and the last
+// event of its history. It returns nil if the project has neither.
+func projectStatus(uuid string, history *History, activeEvent string, active bool) *client.ProjectStatus {
+	status := &client.ProjectStatus{UUID: uuid}
+	if history != nil {
+		status.Organization = history.Organization
+		status.Project = history.Project
+	}
+	if active {
+		status.EventType = activeEvent
+		status.State = client.StateProvisioning
+		// Projects deleted in the soft deletion mode are deactivated
+		if activeEvent == "delete" || activeEvent == "deactivate" {
+			status.State = client.StateDeleting
+		}
+		return status
+	}
+	if history == nil || len(history.Events) == 0 {
+		return nil
+	}
+
+	last := history.Events[len(history.Events)-1]
+	status.EventType = last.EventType
+	status.Updated = last.Finished
+	switch {
+	case last.Result == ResultError:
+		status.State = client.StateFailed
+		status.Error = last.Error
+	case last.Result == ResultCancelled:
+		// Cancelled on shutdown, the event is handled again at the next start
+		status.State = client.StateProvisioning
+	case last.EventType == "delete" || last.EventType == "deactivate":
+		status.State = client.StateDeleted
+	default:
+		status.State = client.StateReady
+	}
+	return status
+}
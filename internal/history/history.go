@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	"github.com/open-edge-platform/orch-library/go/dazl"
+	coreV1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	coreV1Types "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+)
+
+var log = dazl.GetPackageLogger()
+
+const (
+	// ConfigMapPrefix is followed by the project UUID in the name of the history ConfigMap of a project
+	ConfigMapPrefix = "tenant-history-"
+	// Key is the ConfigMap key holding the history document
+	Key = "history.json"
+	// Label is set on all history ConfigMaps, so that they can be listed
+	Label = "app-orch-tenant-controller/history"
+
+	ResultSuccess   = "success"
+	ResultError     = "error"
+	ResultCancelled = "cancelled"
+
+	// longest error message kept, so that the history of a project stays well below the ConfigMap size limit
+	maxErrorLength = 1024
+)
+
+// History is the timeline of the events handled for a project, oldest first.
+type History struct {
+	Organization string  `json:"organization"`
+	Project      string  `json:"project"`
+	UUID         string  `json:"uuid"`
+	Events       []Entry `json:"events"`
+}
+
+// Entry is the outcome of handling a project event.
+type Entry struct {
+	EventType         string          `json:"eventType"`
+	Received          time.Time       `json:"received"`
+	Finished          time.Time       `json:"finished"`
+	DurationSeconds   float64         `json:"durationSeconds"`
+	Result            string          `json:"result"`
+	Profile           string          `json:"profile,omitempty"`
+	ControllerVersion string          `json:"controllerVersion,omitempty"`
+	Error             string          `json:"error,omitempty"`
+	Plugins           []PluginOutcome `json:"plugins,omitempty"`
+	// issues reported by the plugins that did not fail the event
+	Warnings []string `json:"warnings,omitempty"`
+	// identifiers of the resources recorded by the plugins
+	Resources []string `json:"resources,omitempty"`
+}
+
+// PluginOutcome is the time a plugin spent on an event, including retries, and whether it completed the event.
+type PluginOutcome struct {
+	Name            string  `json:"name"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	Attempts        int     `json:"attempts,omitempty"`
+	Result          string  `json:"result"`
+}
+
+// NewEntry returns the history entry of an event, with the outcome of each plugin that ran. The error is recorded
+// with its user message.
+func NewEntry(eventType string, received time.Time, finished time.Time, result string, err error, plugins []PluginOutcome) Entry {
+	entry := Entry{
+		EventType:       eventType,
+		Received:        received.UTC(),
+		Finished:        finished.UTC(),
+		DurationSeconds: finished.Sub(received).Seconds(),
+		Result:          result,
+		Plugins:         plugins,
+	}
+	if err != nil {
+		entry.Error = southbound.UserMessage(err)
+		if len(entry.Error) > maxErrorLength {
+			entry.Error = entry.Error[:maxErrorLength] + "..."
+		}
+	}
+	return entry
+}
+
+// Store keeps the provisioning history of each project.
+type Store interface {
+	// Append adds an entry to the history of the project, dropping the oldest entries beyond the history size
+	Append(ctx context.Context, organization string, project string, uuid string, entry Entry) error
+	// Load returns the history of the project, or nil if the project has none
+	Load(ctx context.Context, uuid string) (*History, error)
+}
+
+// ConfigMapStore keeps the history of each project in a ConfigMap named after the project UUID. The history is
+// kept after the project is deleted, so that its deletion can be looked up.
+type ConfigMapStore struct {
+	configMaps coreV1Types.ConfigMapInterface
+	size       int
+}
+
+// NewConfigMapStore creates a store in the namespace using the in-cluster Kubernetes configuration, keeping the
+// given number of entries per project.
+func NewConfigMapStore(namespace string, size int) (*ConfigMapStore, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return newConfigMapStore(clientset.CoreV1().ConfigMaps(namespace), size), nil
+}
+
+func newConfigMapStore(configMaps coreV1Types.ConfigMapInterface, size int) *ConfigMapStore {
+	return &ConfigMapStore{configMaps: configMaps, size: size}
+}
+
+func (s *ConfigMapStore) Append(ctx context.Context, organization string, project string, uuid string, entry Entry) error {
+	// Events of a project are handled one at a time, so conflicts only arise from concurrent edits by hand
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		name := ConfigMapPrefix + uuid
+		history := &History{UUID: uuid}
+		configMap, err := s.configMaps.Get(ctx, name, metaV1.GetOptions{})
+		exists := err == nil
+		if apierrors.IsNotFound(err) {
+			configMap = &coreV1.ConfigMap{
+				ObjectMeta: metaV1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{Label: "true"},
+				},
+			}
+		} else if err != nil {
+			return err
+		} else if err := json.Unmarshal([]byte(configMap.Data[Key]), history); err != nil {
+			log.Warnf("Replacing invalid history of project %s: %v", uuid, err)
+			history = &History{UUID: uuid}
+		}
+
+		history.Organization = organization
+		history.Project = project
+		history.Events = append(history.Events, entry)
+		if len(history.Events) > s.size {
+			history.Events = history.Events[len(history.Events)-s.size:]
+		}
+		document, err := json.MarshalIndent(history, "", "  ")
+		if err != nil {
+			return err
+		}
+		configMap.Annotations = map[string]string{
+			"organization": organization,
+			"project":      project,
+		}
+		configMap.Data = map[string]string{Key: string(document)}
+		if exists {
+			_, err = s.configMaps.Update(ctx, configMap, metaV1.UpdateOptions{})
+		} else {
+			_, err = s.configMaps.Create(ctx, configMap, metaV1.CreateOptions{})
+		}
+		return err
+	})
+}
+
+func (s *ConfigMapStore) Load(ctx context.Context, uuid string) (*History, error) {
+	configMap, err := s.configMaps.Get(ctx, ConfigMapPrefix+uuid, metaV1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	history := &History{}
+	if err := json.Unmarshal([]byte(configMap.Data[Key]), history); err != nil {
+		return nil, fmt.Errorf("invalid history of project %s: %w", uuid, err)
+	}
+	return history, nil
+}
@@ -0,0 +1,629 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package history
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/auth"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	"github.com/open-edge-platform/app-orch-tenant-controller/pkg/api"
+	"github.com/open-edge-platform/app-orch-tenant-controller/pkg/client"
+	"github.com/stretchr/testify/suite"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Suite of project history tests
+type HistoryTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *HistoryTestSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 1*time.Minute)
+}
+
+func (s *HistoryTestSuite) TearDownTest() {
+	s.cancel()
+}
+
+func TestHistory(t *testing.T) {
+	suite.Run(t, &HistoryTestSuite{})
+}
+
+func (s *HistoryTestSuite) TestNewEntry() {
+	received := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	plugins := []PluginOutcome{
+		{Name: "harbor", DurationSeconds: 2, Attempts: 1, Result: ResultSuccess},
+		{Name: "catalog", DurationSeconds: 1, Attempts: 3, Result: ResultError},
+	}
+	entry := NewEntry("create", received, received.Add(5*time.Second), ResultError, errors.New("catalog is unavailable"), plugins)
+	s.Equal("create", entry.EventType)
+	s.Equal(5.0, entry.DurationSeconds)
+	s.Equal(ResultError, entry.Result)
+	s.Equal("catalog is unavailable", entry.Error)
+	s.Equal(plugins, entry.Plugins)
+
+	// Errors are recorded with their user message
+	err := southbound.WithUserMessage(errors.New("rpc error: code = InvalidArgument desc = yaml: line 13"), "Catalog file a.yaml is invalid: yaml: line 13")
+	entry = NewEntry("create", received, received, ResultError, err, nil)
+	s.Equal("Catalog file a.yaml is invalid: yaml: line 13", entry.Error)
+
+	entry = NewEntry("create", received, received, ResultError, errors.New(strings.Repeat("e", 2000)), nil)
+	s.Len(entry.Error, maxErrorLength+3)
+	s.Empty(entry.Plugins)
+}
+
+func (s *HistoryTestSuite) TestConfigMapStore() {
+	configMaps := fake.NewClientset().CoreV1().ConfigMaps("orch-app")
+	store := newConfigMapStore(configMaps, 2)
+
+	history, err := store.Load(s.ctx, "uuid-1")
+	s.NoError(err)
+	s.Nil(history)
+
+	for _, eventType := range []string{"create", "update", "delete"} {
+		s.NoError(store.Append(s.ctx, "org", "proj", "uuid-1", Entry{EventType: eventType, Result: ResultSuccess}))
+	}
+	history, err = store.Load(s.ctx, "uuid-1")
+	s.NoError(err)
+	s.Equal("org", history.Organization)
+	s.Equal("proj", history.Project)
+	s.Equal("uuid-1", history.UUID)
+	// The oldest entries beyond the history size are dropped
+	s.Len(history.Events, 2)
+	s.Equal("update", history.Events[0].EventType)
+	s.Equal("delete", history.Events[1].EventType)
+
+	configMap, err := configMaps.Get(s.ctx, "tenant-history-uuid-1", metaV1.GetOptions{})
+	s.NoError(err)
+	s.Equal("true", configMap.Labels[Label])
+
+	// An invalid history is replaced
+	configMap.Data[Key] = "not json"
+	_, err = configMaps.Update(s.ctx, configMap, metaV1.UpdateOptions{})
+	s.NoError(err)
+	_, err = store.Load(s.ctx, "uuid-1")
+	s.ErrorContains(err, "invalid history")
+	s.NoError(store.Append(s.ctx, "org", "proj", "uuid-1", Entry{EventType: "create"}))
+	history, err = store.Load(s.ctx, "uuid-1")
+	s.NoError(err)
+	s.Len(history.Events, 1)
+}
+
+func (s *HistoryTestSuite) TestAPI() {
+	store := newConfigMapStore(fake.NewClientset().CoreV1().ConfigMaps("orch-app"), 10)
+	s.NoError(store.Append(s.ctx, "org", "proj", "uuid-1", Entry{EventType: "create", Result: ResultSuccess}))
+	api := NewAPI("127.0.0.1:0", store)
+
+	recorder := httptest.NewRecorder()
+	api.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/projects/uuid-1/history", nil))
+	s.Equal(http.StatusOK, recorder.Code)
+	s.Equal("application/json", recorder.Header().Get("Content-Type"))
+	history := &History{}
+	s.NoError(json.Unmarshal(recorder.Body.Bytes(), history))
+	s.Equal("proj", history.Project)
+	s.Len(history.Events, 1)
+
+	recorder = httptest.NewRecorder()
+	api.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/projects/uuid-2/history", nil))
+	s.Equal(http.StatusNotFound, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	api.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/api/v1/projects/uuid-1/history", nil))
+	s.Equal(http.StatusMethodNotAllowed, recorder.Code)
+
+	s.NoError(api.Start(s.ctx))
+	s.ErrorContains(NewAPI("not-an-address", store).Start(s.ctx), "unable to listen for history API requests")
+}
+
+func (s *HistoryTestSuite) TestExportAPI() {
+	store := newConfigMapStore(fake.NewClientset().CoreV1().ConfigMaps("orch-app"), 10)
+	export := func(uuid string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		NewAPI("127.0.0.1:0", store).WithExport(func(_ context.Context, uuid string) (interface{}, error) {
+			switch uuid {
+			case "uuid-1":
+				return map[string]string{"project": "proj"}, nil
+			case "uuid-2":
+				return nil, errors.New("catalog unavailable")
+			}
+			return nil, nil
+		}).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/projects/"+uuid+"/export", nil))
+		return recorder
+	}
+
+	recorder := export("uuid-1")
+	s.Equal(http.StatusOK, recorder.Code)
+	s.Equal("application/json", recorder.Header().Get("Content-Type"))
+	bundle := map[string]string{}
+	s.NoError(json.Unmarshal(recorder.Body.Bytes(), &bundle))
+	s.Equal("proj", bundle["project"])
+	s.Equal(http.StatusInternalServerError, export("uuid-2").Code)
+	s.Equal(http.StatusNotFound, export("uuid-3").Code)
+
+	// Without an export source nothing is found
+	recorder = httptest.NewRecorder()
+	NewAPI("127.0.0.1:0", store).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/projects/uuid-1/export", nil))
+	s.Equal(http.StatusNotFound, recorder.Code)
+}
+
+func (s *HistoryTestSuite) TestHarborCredentialReloadAPI() {
+	store := newConfigMapStore(fake.NewClientset().CoreV1().ConfigMaps("orch-app"), 10)
+	var reloadErr error
+	reloads := 0
+	api := NewAPI("127.0.0.1:0", store).WithHarborCredentialReload(func(_ context.Context) (bool, error) {
+		reloads++
+		return reloads == 1, reloadErr
+	})
+	reload := func(api *API, method string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		api.admin.ServeHTTP(recorder, httptest.NewRequest(method, "/api/v1/admin/harbor-credentials/reload", nil))
+		return recorder
+	}
+
+	recorder := reload(api, http.MethodPost)
+	s.Equal(http.StatusOK, recorder.Code)
+	s.Equal("application/json", recorder.Header().Get("Content-Type"))
+	result := CredentialReload{}
+	s.NoError(json.Unmarshal(recorder.Body.Bytes(), &result))
+	s.True(result.Changed)
+
+	// Reloading an unchanged credential is not an error
+	recorder = reload(api, http.MethodPost)
+	s.Equal(http.StatusOK, recorder.Code)
+	s.NoError(json.Unmarshal(recorder.Body.Bytes(), &result))
+	s.False(result.Changed)
+
+	reloadErr = errors.New("no credential found in secret orch-harbor/harbor-admin-credential")
+	recorder = reload(api, http.MethodPost)
+	s.Equal(http.StatusInternalServerError, recorder.Code)
+	s.Contains(recorder.Body.String(), "no credential found")
+
+	s.Equal(http.StatusMethodNotAllowed, reload(api, http.MethodGet).Code)
+	s.Equal(3, reloads)
+
+	// Without a reload nothing is found
+	s.Equal(http.StatusNotFound, reload(NewAPI("127.0.0.1:0", store), http.MethodPost).Code)
+
+	// The read-only API does not serve it
+	recorder = httptest.NewRecorder()
+	api.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/harbor-credentials/reload", nil))
+	s.Equal(http.StatusNotFound, recorder.Code)
+	s.Equal(3, reloads)
+}
+
+// The admin API only passes on the requests presenting the bearer token.
+func (s *HistoryTestSuite) TestAdminAPI() {
+	store := newConfigMapStore(fake.NewClientset().CoreV1().ConfigMaps("orch-app"), 10)
+	reloads := 0
+	api := NewAPI("127.0.0.1:0", store).
+		WithHarborCredentialReload(func(_ context.Context) (bool, error) {
+			reloads++
+			return false, nil
+		}).
+		WithAdmin("127.0.0.1:0", s.adminToken())
+	reload := func(authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/harbor-credentials/reload", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		recorder := httptest.NewRecorder()
+		api.AdminHandler().ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	s.Equal(http.StatusUnauthorized, reload("").Code)
+	s.Equal(http.StatusUnauthorized, reload("Bearer other").Code)
+	s.Equal(0, reloads)
+	s.Equal(http.StatusOK, reload("Bearer admin-token").Code)
+	s.Equal(1, reloads)
+
+	// The admin API does not serve the read-only calls
+	for _, target := range []string{"/api/v1/projects/uuid-1/history", "/api/v1/openapi.yaml"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("Authorization", "Bearer admin-token")
+		recorder := httptest.NewRecorder()
+		api.AdminHandler().ServeHTTP(recorder, req)
+		s.Equal(http.StatusNotFound, recorder.Code, target)
+	}
+
+	s.NoError(api.Start(s.ctx))
+}
+
+// adminToken returns an authenticator of the admin-token bearer token.
+func (s *HistoryTestSuite) adminToken() *auth.BearerToken {
+	dir := s.T().TempDir()
+	s.NoError(os.WriteFile(filepath.Join(dir, config.DefaultTokenKey), []byte("admin-token"), 0o600))
+	return auth.NewBearerToken(config.SecretRef{Key: config.DefaultTokenKey, MountPath: dir})
+}
+
+func (s *HistoryTestSuite) TestReprovisionAPI() {
+	store := newConfigMapStore(fake.NewClientset().CoreV1().ConfigMaps("orch-app"), 10)
+	var requested []string
+	api := NewAPI("127.0.0.1:0", store).WithReprovision(func(_ context.Context, uuid string, plugins []string) error {
+		switch {
+		case uuid != "uuid-1":
+			return fmt.Errorf("%w: %s", ErrProjectNotFound, uuid)
+		case slices.Contains(plugins, "unknown"):
+			return fmt.Errorf("%w: unknown plugin", ErrInvalidRequest)
+		case slices.Contains(plugins, "broken"):
+			return errors.New("queue closed")
+		}
+		requested = plugins
+		return nil
+	})
+	reprovision := func(api *API, target string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		api.admin.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, target, nil))
+		return recorder
+	}
+
+	recorder := reprovision(api, "/api/v1/admin/projects/uuid-1/reprovision?plugin=catalog&plugin=extensions")
+	s.Equal(http.StatusAccepted, recorder.Code)
+	s.Equal("application/json", recorder.Header().Get("Content-Type"))
+	result := Reprovision{}
+	s.NoError(json.Unmarshal(recorder.Body.Bytes(), &result))
+	s.Equal(Reprovision{UUID: "uuid-1", EventType: "ensure", Plugins: []string{"catalog", "extensions"}}, result)
+	s.Equal([]string{"catalog", "extensions"}, requested)
+
+	// Without plugins all of them handle the event
+	s.Equal(http.StatusAccepted, reprovision(api, "/api/v1/admin/projects/uuid-1/reprovision").Code)
+	s.Empty(requested)
+
+	s.Equal(http.StatusNotFound, reprovision(api, "/api/v1/admin/projects/uuid-2/reprovision").Code)
+	s.Equal(http.StatusBadRequest, reprovision(api, "/api/v1/admin/projects/uuid-1/reprovision?plugin=unknown").Code)
+	s.Equal(http.StatusInternalServerError, reprovision(api, "/api/v1/admin/projects/uuid-1/reprovision?plugin=broken").Code)
+
+	// Without a reprovisioner nothing is found
+	s.Equal(http.StatusNotFound, reprovision(NewAPI("127.0.0.1:0", store), "/api/v1/admin/projects/uuid-1/reprovision").Code)
+
+	// The read-only API does not serve it
+	requested = nil
+	recorder = httptest.NewRecorder()
+	api.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/projects/uuid-1/reprovision?plugin=catalog", nil))
+	s.Equal(http.StatusNotFound, recorder.Code)
+	s.Nil(requested)
+}
+
+func (s *HistoryTestSuite) TestRestoreAPI() {
+	store := newConfigMapStore(fake.NewClientset().CoreV1().ConfigMaps("orch-app"), 10)
+	var restored []string
+	api := NewAPI("127.0.0.1:0", store).WithRestore(func(_ context.Context, uuid string) error {
+		switch uuid {
+		case "uuid-1":
+			restored = append(restored, uuid)
+			return nil
+		case "uuid-2":
+			return fmt.Errorf("%w: purged", ErrRetentionExpired)
+		case "uuid-3":
+			return errors.New("queue closed")
+		}
+		return fmt.Errorf("%w: %s", ErrProjectNotFound, uuid)
+	})
+	restore := func(api *API, uuid string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		api.admin.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/projects/"+uuid+"/restore", nil))
+		return recorder
+	}
+
+	recorder := restore(api, "uuid-1")
+	s.Equal(http.StatusAccepted, recorder.Code)
+	result := Reprovision{}
+	s.NoError(json.Unmarshal(recorder.Body.Bytes(), &result))
+	s.Equal(Reprovision{UUID: "uuid-1", EventType: "reactivate"}, result)
+	s.Equal([]string{"uuid-1"}, restored)
+
+	s.Equal(http.StatusConflict, restore(api, "uuid-2").Code)
+	s.Equal(http.StatusInternalServerError, restore(api, "uuid-3").Code)
+	s.Equal(http.StatusNotFound, restore(api, "uuid-4").Code)
+
+	// Without a restorer nothing is found
+	s.Equal(http.StatusNotFound, restore(NewAPI("127.0.0.1:0", store), "uuid-1").Code)
+
+	// The read-only API does not serve it
+	recorder = httptest.NewRecorder()
+	api.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/projects/uuid-1/restore", nil))
+	s.Equal(http.StatusNotFound, recorder.Code)
+	s.Equal([]string{"uuid-1"}, restored)
+}
+
+func (s *HistoryTestSuite) TestDebugAPI() {
+	store := newConfigMapStore(fake.NewClientset().CoreV1().ConfigMaps("orch-app"), 10)
+	var queried []string
+	api := NewAPI("127.0.0.1:0", store).WithDebugQueries(map[string]DebugQuery{
+		"registries": func(_ context.Context, uuid string) (interface{}, error) {
+			switch uuid {
+			case "uuid-2":
+				return nil, errors.New("catalog unavailable")
+			case "uuid-3":
+				return nil, fmt.Errorf("%w: %s", ErrProjectNotFound, uuid)
+			}
+			queried = append(queried, uuid)
+			return []string{"harbor-helm-oci"}, nil
+		},
+	})
+	query := func(api *API, uuid string, name string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		api.admin.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/debug/projects/"+uuid+"/"+name, nil))
+		return recorder
+	}
+
+	recorder := query(api, "uuid-1", "registries")
+	s.Equal(http.StatusOK, recorder.Code)
+	s.JSONEq(`["harbor-helm-oci"]`, recorder.Body.String())
+	s.Equal([]string{"uuid-1"}, queried)
+
+	// A failure of the queried service is told apart from a failure of the controller
+	s.Equal(http.StatusBadGateway, query(api, "uuid-2", "registries").Code)
+	s.Equal(http.StatusNotFound, query(api, "uuid-3", "registries").Code)
+	s.Equal(http.StatusNotFound, query(api, "uuid-1", "deployments").Code)
+
+	// Queries only run with GET, and without debug queries nothing is found
+	recorder = httptest.NewRecorder()
+	api.admin.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/debug/projects/uuid-1/registries", nil))
+	s.Equal(http.StatusMethodNotAllowed, recorder.Code)
+	s.Equal(http.StatusNotFound, query(NewAPI("127.0.0.1:0", store), "uuid-1", "registries").Code)
+
+	// The read-only API does not serve them, as they use the credentials of the controller
+	queried = nil
+	recorder = httptest.NewRecorder()
+	api.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/debug/projects/uuid-1/registries", nil))
+	s.Equal(http.StatusNotFound, recorder.Code)
+	recorder = httptest.NewRecorder()
+	api.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/debug/projects/uuid-1/registries", nil))
+	s.Equal(http.StatusNotFound, recorder.Code)
+	s.Nil(queried)
+}
+
+func (s *HistoryTestSuite) TestStatusAPI() {
+	store := newConfigMapStore(fake.NewClientset().CoreV1().ConfigMaps("orch-app"), 10)
+	active := map[string]string{}
+	api := NewAPI("127.0.0.1:0", store).WithActiveEvents(func(uuid string) (string, bool) {
+		eventType, ok := active[uuid]
+		return eventType, ok
+	})
+	status := func(uuid string) (int, *client.ProjectStatus) {
+		recorder := httptest.NewRecorder()
+		api.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/projects/"+uuid+"/status", nil))
+		status := &client.ProjectStatus{}
+		if recorder.Code == http.StatusOK {
+			s.NoError(json.Unmarshal(recorder.Body.Bytes(), status))
+		}
+		return recorder.Code, status
+	}
+
+	code, _ := status("uuid-1")
+	s.Equal(http.StatusNotFound, code)
+
+	// The first event is reported while it is in progress
+	active["uuid-1"] = "create"
+	code, st := status("uuid-1")
+	s.Equal(http.StatusOK, code)
+	s.Equal(client.StateProvisioning, st.State)
+	s.Equal("create", st.EventType)
+	delete(active, "uuid-1")
+
+	finished := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.NoError(store.Append(s.ctx, "org", "proj", "uuid-1", Entry{EventType: "create", Result: ResultError, Error: "harbor unavailable", Finished: finished}))
+	_, st = status("uuid-1")
+	s.Equal(client.StateFailed, st.State)
+	s.Equal("harbor unavailable", st.Error)
+	s.Equal("proj", st.Project)
+	s.Equal(finished, st.Updated)
+
+	s.NoError(store.Append(s.ctx, "org", "proj", "uuid-1", Entry{EventType: "update", Result: ResultSuccess}))
+	_, st = status("uuid-1")
+	s.Equal(client.StateReady, st.State)
+	s.Empty(st.Error)
+
+	s.NoError(store.Append(s.ctx, "org", "proj", "uuid-1", Entry{EventType: "create", Result: ResultCancelled}))
+	_, st = status("uuid-1")
+	s.Equal(client.StateProvisioning, st.State)
+
+	active["uuid-1"] = "delete"
+	_, st = status("uuid-1")
+	s.Equal(client.StateDeleting, st.State)
+	delete(active, "uuid-1")
+
+	s.NoError(store.Append(s.ctx, "org", "proj", "uuid-1", Entry{EventType: "delete", Result: ResultSuccess}))
+	_, st = status("uuid-1")
+	s.Equal(client.StateDeleted, st.State)
+
+	// Projects deleted in the soft deletion mode are deactivated
+	active["uuid-2"] = "deactivate"
+	_, st = status("uuid-2")
+	s.Equal(client.StateDeleting, st.State)
+	delete(active, "uuid-2")
+
+	s.NoError(store.Append(s.ctx, "org", "proj-2", "uuid-2", Entry{EventType: "deactivate", Result: ResultSuccess}))
+	_, st = status("uuid-2")
+	s.Equal(client.StateDeleted, st.State)
+
+	s.NoError(store.Append(s.ctx, "org", "proj-2", "uuid-2", Entry{EventType: "reactivate", Result: ResultSuccess}))
+	_, st = status("uuid-2")
+	s.Equal(client.StateReady, st.State)
+}
+
+// The responses of the API are checked against its OpenAPI specification, and decoded with the types generated from
+// it without losing any field.
+func (s *HistoryTestSuite) TestOpenAPI() {
+	spec, err := openapi3.NewLoader().LoadFromData(api.Spec)
+	s.Require().NoError(err)
+	// The requests are sent to the test server rather than the ones of the specification
+	spec.Servers = nil
+	for _, path := range spec.Paths.Map() {
+		path.Servers = nil
+	}
+	router, err := legacy.NewRouter(spec)
+	s.Require().NoError(err)
+
+	store := newConfigMapStore(fake.NewClientset().CoreV1().ConfigMaps("orch-app"), 10)
+	received := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.NoError(store.Append(s.ctx, "org", "proj", "uuid-1", Entry{
+		EventType: "create", Received: received, Finished: received.Add(1500 * time.Millisecond), DurationSeconds: 1.5,
+		Result: ResultError, Profile: "gold", ControllerVersion: "1.2.3", Error: "catalog unavailable",
+		Plugins:   []PluginOutcome{{Name: "harbor", DurationSeconds: 1, Attempts: 2, Result: ResultSuccess}, {Name: "catalog", Result: ResultError}},
+		Warnings:  []string{"registry skipped"},
+		Resources: []string{"harbor project catalog-apps-org-proj"},
+	}))
+	bundle := api.ExportBundle{Version: "v1", Exported: received, Organization: "org", Project: "proj", UUID: "uuid-1",
+		HarborProject: &api.ExportHarborProject{Name: "catalog-apps-org-proj"},
+		Registries:    &[]api.ExportRegistry{{Name: "harbor-helm", Type: "HELM", RootURL: "oci://harbor"}},
+	}
+	historyAPI := NewAPI("127.0.0.1:0", store).
+		WithActiveEvents(func(uuid string) (string, bool) { return "update", uuid == "uuid-2" }).
+		WithExport(func(_ context.Context, uuid string) (interface{}, error) {
+			if uuid == "uuid-1" {
+				return bundle, nil
+			}
+			return nil, nil
+		}).
+		WithHarborCredentialReload(func(_ context.Context) (bool, error) { return true, nil }).
+		WithReprovision(func(_ context.Context, _ string, _ []string) error { return nil }).
+		WithRestore(func(_ context.Context, uuid string) error {
+			if uuid != "uuid-1" {
+				return fmt.Errorf("%w: %s", ErrRetentionExpired, uuid)
+			}
+			return nil
+		}).
+		WithDebugQueries(map[string]DebugQuery{
+			"registries": func(_ context.Context, _ string) (interface{}, error) {
+				return []api.DebugRegistry{{Name: "harbor-helm-oci", Type: "HELM", RootURL: "oci://harbor/helm"}}, nil
+			},
+			"deployments": func(_ context.Context, uuid string) (interface{}, error) {
+				if uuid != "uuid-1" {
+					return nil, errors.New("ADM unavailable")
+				}
+				return []api.DebugDeployment{{ID: "id-1", DisplayName: "base", AppName: "base", AppVersion: "0.2.0", ProfileName: "default"}}, nil
+			},
+		}).
+		WithAdmin("127.0.0.1:0", s.adminToken())
+	// The admin calls are served by the admin handler, as on the admin listener
+	handler := http.NewServeMux()
+	handler.Handle("/api/v1/admin/", historyAPI.AdminHandler())
+	handler.Handle("/", historyAPI)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	c, err := api.NewClientWithResponses(server.URL, api.WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer admin-token")
+		return nil
+	}))
+	s.Require().NoError(err)
+
+	validate := func(resp *http.Response, body []byte, decoded interface{}) {
+		route, pathParams, err := router.FindRoute(resp.Request)
+		s.Require().NoError(err)
+		s.NoError(openapi3filter.ValidateResponse(s.ctx, &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: &openapi3filter.RequestValidationInput{Request: resp.Request, PathParams: pathParams, Route: route},
+			Status:                 resp.StatusCode,
+			Header:                 resp.Header,
+			Body:                   io.NopCloser(bytes.NewReader(body)),
+		}), "%s %s", resp.Request.Method, resp.Request.URL.Path)
+		if decoded != nil {
+			encoded, err := json.Marshal(decoded)
+			s.NoError(err)
+			s.JSONEq(string(body), string(encoded))
+		}
+	}
+
+	history, err := c.GetProjectHistoryWithResponse(s.ctx, "uuid-1")
+	s.Require().NoError(err)
+	s.Require().NotNil(history.JSON200)
+	s.Len(history.JSON200.Events, 1)
+	validate(history.HTTPResponse, history.Body, history.JSON200)
+	history, err = c.GetProjectHistoryWithResponse(s.ctx, "uuid-3")
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, history.StatusCode())
+	validate(history.HTTPResponse, history.Body, nil)
+
+	for _, uuid := range []string{"uuid-1", "uuid-2"} {
+		status, err := c.GetProjectStatusWithResponse(s.ctx, uuid)
+		s.Require().NoError(err)
+		s.Require().NotNil(status.JSON200)
+		validate(status.HTTPResponse, status.Body, status.JSON200)
+	}
+
+	export, err := c.GetProjectExportWithResponse(s.ctx, "uuid-1")
+	s.Require().NoError(err)
+	s.Equal(&bundle, export.JSON200)
+	validate(export.HTTPResponse, export.Body, export.JSON200)
+	export, err = c.GetProjectExportWithResponse(s.ctx, "uuid-2")
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, export.StatusCode())
+	validate(export.HTTPResponse, export.Body, nil)
+
+	reload, err := c.ReloadHarborCredentialsWithResponse(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(&api.CredentialReload{Changed: true}, reload.JSON200)
+	validate(reload.HTTPResponse, reload.Body, reload.JSON200)
+	unauthenticated, err := api.NewClientWithResponses(server.URL)
+	s.Require().NoError(err)
+	reload, err = unauthenticated.ReloadHarborCredentialsWithResponse(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(http.StatusUnauthorized, reload.StatusCode())
+	validate(reload.HTTPResponse, reload.Body, nil)
+
+	reprovision, err := c.ReprovisionProjectWithResponse(s.ctx, "uuid-1", &api.ReprovisionProjectParams{Plugin: &[]string{"catalog"}})
+	s.Require().NoError(err)
+	s.Equal(&api.Reprovision{UUID: "uuid-1", EventType: "ensure", Plugins: &[]string{"catalog"}}, reprovision.JSON202)
+	validate(reprovision.HTTPResponse, reprovision.Body, reprovision.JSON202)
+
+	restore, err := c.RestoreProjectWithResponse(s.ctx, "uuid-1")
+	s.Require().NoError(err)
+	s.Equal(&api.Reprovision{UUID: "uuid-1", EventType: "reactivate"}, restore.JSON202)
+	validate(restore.HTTPResponse, restore.Body, restore.JSON202)
+	restore, err = c.RestoreProjectWithResponse(s.ctx, "uuid-2")
+	s.Require().NoError(err)
+	s.Equal(http.StatusConflict, restore.StatusCode())
+	validate(restore.HTTPResponse, restore.Body, nil)
+
+	registries, err := c.ListDebugRegistriesWithResponse(s.ctx, "uuid-1")
+	s.Require().NoError(err)
+	s.Require().NotNil(registries.JSON200)
+	s.Len(*registries.JSON200, 1)
+	validate(registries.HTTPResponse, registries.Body, registries.JSON200)
+	deployments, err := c.ListDebugDeploymentsWithResponse(s.ctx, "uuid-1")
+	s.Require().NoError(err)
+	s.Require().NotNil(deployments.JSON200)
+	s.Equal("base", (*deployments.JSON200)[0].DisplayName)
+	validate(deployments.HTTPResponse, deployments.Body, deployments.JSON200)
+	deployments, err = c.ListDebugDeploymentsWithResponse(s.ctx, "uuid-2")
+	s.Require().NoError(err)
+	s.Equal(http.StatusBadGateway, deployments.StatusCode())
+	validate(deployments.HTTPResponse, deployments.Body, nil)
+
+	// The specification is served as YAML and JSON
+	yamlSpec, err := c.GetOpenAPISpecWithResponse(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(api.Spec, yamlSpec.Body)
+	validate(yamlSpec.HTTPResponse, yamlSpec.Body, nil)
+	jsonSpec, err := c.GetOpenAPISpecJSONWithResponse(s.ctx)
+	s.Require().NoError(err)
+	s.Require().NotNil(jsonSpec.JSON200)
+	validate(jsonSpec.HTTPResponse, jsonSpec.Body, nil)
+	served, err := openapi3.NewLoader().LoadFromData(jsonSpec.Body)
+	s.Require().NoError(err)
+	s.Equal(spec.Info.Title, served.Info.Title)
+	s.Equal(spec.Paths.Len(), served.Paths.Len())
+}
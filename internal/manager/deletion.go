@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/history"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/plugins"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+)
+
+// purgeInterval is the time between looking for deactivated projects whose retention period is over, it is replaced
+// in tests
+var purgeInterval = 10 * time.Minute
+
+// startPurge deletes the resources of the projects deactivated in the soft deletion mode once their retention
+// period is over, until the manager is closed.
+func (m *Manager) startPurge() {
+	if m.Config.DeletionMode != config.DeletionModeSoft {
+		return
+	}
+	log.Infof("Resources of deleted projects are kept for %s", m.Config.DeletionRetention)
+	go func() {
+		ticker := time.NewTicker(purgeInterval)
+		defer ticker.Stop()
+		for {
+			if err := m.purgeExpired(m.ctx, time.Now()); err != nil {
+				log.Warnf("Unable to purge deleted projects: %v", southbound.ScrubError(err))
+			}
+			select {
+			case <-m.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// purgeExpired queues a delete event for each deactivated project whose retention period is over at the given
+// time. Projects that already have an event in progress are left for the next purge.
+func (m *Manager) purgeExpired(ctx context.Context, now time.Time) error {
+	store, err := plugins.InventoryStoreFactory(m.Config)
+	if err != nil {
+		return err
+	}
+	inventories, err := store.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, inventory := range inventories {
+		deactivation := inventory.Deactivation
+		if deactivation == nil || now.Before(deactivation.PurgeAfter) {
+			continue
+		}
+		if eventType, ok := m.projects.activeEvent(inventory.UUID); ok {
+			log.Infof("Not purging project %s yet, it has a %s event in progress", inventory.Project, eventType)
+			continue
+		}
+		log.Infof("Retention period of project %s ended at %s, deleting its resources", inventory.Project,
+			deactivation.PurgeAfter.Format(time.RFC3339))
+		err := m.enqueue(ctx, plugins.Event{
+			EventType:    "delete",
+			Organization: inventory.Organization,
+			Name:         inventory.Project,
+			UUID:         inventory.UUID,
+			RetainData:   deactivation.RetainData,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreProject queues a reactivate event for a project deactivated in the soft deletion mode, for the history
+// API. It does not wait for the event.
+func (m *Manager) restoreProject(ctx context.Context, uuid string) error {
+	if m.Config.DeletionMode != config.DeletionModeSoft {
+		return fmt.Errorf("%w: projects are deleted in the %s deletion mode", history.ErrProjectNotFound, m.Config.DeletionMode)
+	}
+	store, err := plugins.InventoryStoreFactory(m.Config)
+	if err != nil {
+		return err
+	}
+	inventory, err := store.Load(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	if inventory == nil || inventory.Deactivation == nil {
+		return fmt.Errorf("%w: no deactivated project has UUID %s", history.ErrProjectNotFound, uuid)
+	}
+	if eventType, ok := m.projects.activeEvent(uuid); ok && eventType == "delete" {
+		return fmt.Errorf("%w: the resources of project %s are being deleted", history.ErrRetentionExpired, inventory.Project)
+	}
+	if !time.Now().Before(inventory.Deactivation.PurgeAfter) {
+		return fmt.Errorf("%w: the resources of project %s were kept until %s", history.ErrRetentionExpired, inventory.Project,
+			inventory.Deactivation.PurgeAfter.Format(time.RFC3339))
+	}
+	return m.enqueue(ctx, plugins.Event{
+		EventType:    "reactivate",
+		Organization: inventory.Organization,
+		Name:         inventory.Project,
+		UUID:         uuid,
+	})
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"sync"
+	"time"
+)
+
+// eventLatencyWindow is the period over which the average event latency is reported
+const eventLatencyWindow = 5 * time.Minute
+
+// maxLatencySamples bounds the memory held by the latency window under load; the oldest samples are dropped first
+const maxLatencySamples = 10000
+
+type latencySample struct {
+	finished time.Time
+	latency  time.Duration
+}
+
+// latencyWindow keeps the latencies of the events finished in the last window, so that their average drops back to
+// zero once the controller is idle rather than keep the value of the last burst of events.
+type latencyWindow struct {
+	mu      sync.Mutex
+	window  time.Duration
+	samples []latencySample
+}
+
+func newLatencyWindow(window time.Duration) *latencyWindow {
+	return &latencyWindow{window: window}
+}
+
+// add records the latency of an event that finished at the given time.
+func (w *latencyWindow) add(finished time.Time, latency time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.samples) >= maxLatencySamples {
+		w.samples = w.samples[1:]
+	}
+	w.samples = append(w.samples, latencySample{finished: finished, latency: latency})
+}
+
+// average returns the average latency of the events finished in the window before now, 0 if there are none.
+func (w *latencyWindow) average(now time.Time) time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	expired := 0
+	for expired < len(w.samples) && now.Sub(w.samples[expired].finished) > w.window {
+		expired++
+	}
+	w.samples = w.samples[expired:]
+	if len(w.samples) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, sample := range w.samples {
+		total += sample.latency
+	}
+	return total / time.Duration(len(w.samples))
+}
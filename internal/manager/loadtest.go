@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/plugins"
+)
+
+// LoadTest describes a run of synthetic project events through the workers, used to check that a deployment can
+// handle onboarding many projects at once.
+type LoadTest struct {
+	// organization of the synthetic projects
+	Organization string
+	// number of projects created, and then deleted
+	Projects int
+	// if true, the projects are not deleted after they are created
+	KeepProjects bool
+}
+
+// LoadTestPhase holds the measurements for one event type of a load test.
+type LoadTestPhase struct {
+	EventType string
+	// events queued, which is less than the number of projects if the load test was stopped
+	Events int
+	// time from queueing the events until the workers were done with the last one
+	Duration time.Duration
+	// time from queueing each event until the workers were done with it, including the time spent waiting for a
+	// worker, shortest first
+	Latencies []time.Duration
+	// errors of the events that failed, prefixed with the project name
+	Failures []string
+}
+
+// Throughput returns the number of events handled per second.
+func (p LoadTestPhase) Throughput() float64 {
+	if p.Duration <= 0 {
+		return 0
+	}
+	return float64(len(p.Latencies)) / p.Duration.Seconds()
+}
+
+// Percentile returns the latency that the given percentage of the events did not exceed, 0 if there are none.
+func (p LoadTestPhase) Percentile(percent float64) time.Duration {
+	if len(p.Latencies) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(percent / 100 * float64(len(p.Latencies))))
+	return p.Latencies[min(max(rank, 1), len(p.Latencies))-1]
+}
+
+// LoadTestProjectName returns the name of the i-th synthetic project.
+func LoadTestProjectName(i int) string {
+	return fmt.Sprintf("loadtest-%04d", i)
+}
+
+// LoadTestProjectUUID returns the UUID of the i-th synthetic project. The UUIDs are fixed, so that the projects of an
+// interrupted load test can be deleted by running it again.
+func LoadTestProjectUUID(i int) string {
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", i)
+}
+
+// RunLoadTest starts the workers and queues a create event for each synthetic project, then a delete event for each
+// of them unless they are kept. The plugins must already be registered and initialized; the project watchers are not
+// involved, as the projects do not exist in Nexus. Close must not be called afterwards.
+func (m *Manager) RunLoadTest(ctx context.Context, test LoadTest) ([]LoadTestPhase, error) {
+	if test.Projects < 1 {
+		return nil, fmt.Errorf("a load test needs at least 1 project, got %d", test.Projects)
+	}
+	if m.Config.NumberWorkerThreads < 1 {
+		return nil, fmt.Errorf("NumberWorkerThreads must be at least 1, got %d", m.Config.NumberWorkerThreads)
+	}
+	m.startWorkers()
+	defer m.Close()
+
+	phases := []LoadTestPhase{m.runLoadTestPhase(ctx, test, "create")}
+	if !test.KeepProjects && ctx.Err() == nil {
+		phases = append(phases, m.runLoadTestPhase(ctx, test, "delete"))
+	}
+	return phases, ctx.Err()
+}
+
+func (m *Manager) runLoadTestPhase(ctx context.Context, test LoadTest, eventType string) LoadTestPhase {
+	log.Infof("Load test: queueing %d %s events", test.Projects, eventType)
+	phase := LoadTestPhase{EventType: eventType}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	start := time.Now()
+	// Like the Nexus hook, every event is queued on its own goroutine, so the events arrive all at once
+	for i := 0; i < test.Projects; i++ {
+		e := plugins.Event{
+			EventType:    eventType,
+			Organization: test.Organization,
+			Name:         LoadTestProjectName(i),
+			UUID:         LoadTestProjectUUID(i),
+			Lifecycle:    plugins.NewLifecycle(context.Background()),
+		}
+		if eventType == "create" {
+			e.Profile = m.Config.ProvisioningProfiles.Select("", test.Organization)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			queued := time.Now()
+			if err := m.enqueue(ctx, e); err != nil {
+				log.Warnf("Load test: %v", err)
+				return
+			}
+			err := waitForEvent(ctx, e)
+			latency := time.Since(queued)
+			mu.Lock()
+			defer mu.Unlock()
+			phase.Events++
+			phase.Latencies = append(phase.Latencies, latency)
+			if err != nil {
+				phase.Failures = append(phase.Failures, fmt.Sprintf("%s: %v", e.Name, err))
+			}
+		}()
+	}
+	wg.Wait()
+	phase.Duration = time.Since(start)
+	sort.Slice(phase.Latencies, func(i, j int) bool { return phase.Latencies[i] < phase.Latencies[j] })
+	sort.Strings(phase.Failures)
+	log.Infof("Load test: %d %s events handled in %s, %d failed", phase.Events, eventType, phase.Duration, len(phase.Failures))
+	return phase
+}
@@ -6,15 +6,25 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/audit"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/auth"
 	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/events"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/history"
 	nexushook "github.com/open-edge-platform/app-orch-tenant-controller/internal/nexus"
 	"github.com/open-edge-platform/app-orch-tenant-controller/internal/plugins"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/retry"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/slo"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
 	"github.com/open-edge-platform/orch-library/go/dazl"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
@@ -25,8 +35,22 @@ var log = dazl.GetPackageLogger()
 
 // NewManager creates a new manager
 func NewManager(config config.Configuration) *Manager {
+	tracker := slo.NewTracker(config.ProvisioningSLO)
+	if config.SLOWebhookURL != "" {
+		tracker.WithNotifier(slo.NewWebhookNotifier(config.SLOWebhookURL))
+	}
+	var emitter *audit.Emitter
+	if config.AuditURL != "" {
+		emitter = audit.NewEmitter(config.AuditURL).WithToken(southbound.SharedTokenSource(config).Token)
+	}
 	return &Manager{
-		Config: config,
+		Config:   config,
+		ctx:      context.Background(),
+		closing:  make(chan struct{}),
+		tracker:  tracker,
+		projects: newProjectQueues(),
+		watchdog: newWatchdog(config.StuckEventTimeout),
+		audit:    emitter,
 	}
 }
 
@@ -35,6 +59,22 @@ type Manager struct {
 	Config    config.Configuration
 	NexusHook *nexushook.Hook
 	eventChan chan plugins.Event
+	// closed by Close, which waits for the requeue senders before closing the event queue
+	closing   chan struct{}
+	requeuers sync.WaitGroup
+	ctx       context.Context
+	cancel    context.CancelFunc
+	tracker   *slo.Tracker
+	projects  *projectQueues
+	watchdog  *watchdog
+	// stops the watchdog, nil if it is not running
+	stopWatchdog context.CancelFunc
+	// closed once the watchdog has stopped, so that it no longer requeues events
+	watchdogDone chan struct{}
+	// provisioning history of the projects, nil if it is not recorded
+	history history.Store
+	// audit events of the tenant lifecycle, nil if they are not sent
+	audit *audit.Emitter
 }
 
 // Run starts the provisioner server manager
@@ -55,49 +95,73 @@ func (m *Manager) Start() error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*30)
 	defer cancel()
 
-	harborPlugin, err := plugins.NewHarborProvisionerPlugin(ctx, m.Config.HarborServer, m.Config.KeycloakServer, m.Config.HarborNamespace, m.Config.HarborAdminCredential)
-	if err != nil {
+	if err := RegisterPlugins(ctx, m.Config); err != nil {
 		return err
 	}
 
-	log.Infof("Edge Node manifest path %s%s:%s", m.Config.ReleaseServiceBase, m.Config.ManifestPath, m.Config.ManifestTag)
-	catalogPlugin, err := plugins.NewCatalogProvisionerPlugin(m.Config)
+	err := plugins.Initialize(context.Background())
 	if err != nil {
 		return err
 	}
 
-	extensionsPlugin, err := plugins.NewExtensionsProvisionerPlugin(m.Config)
-	if err != nil {
+	if err := checkAPIs(ctx, apiChecks(m.Config)); err != nil {
 		return err
 	}
 
-	plugins.Register(harborPlugin)
-	plugins.Register(catalogPlugin)
-	plugins.Register(extensionsPlugin)
-
-	err = plugins.Initialize(context.Background())
-	if err != nil {
-		return err
-	}
+	// Context bounding the lifetime of the manager, cancelled on shutdown
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	defer m.cancel()
+	m.audit.Start(m.ctx)
 
 	// Create a new Nexus hook.
-	m.NexusHook = nexushook.NewNexusHook(m)
+	m.NexusHook = nexushook.NewNexusHook(m).WithContext(m.ctx).WithTimeout(m.Config.NexusTimeout).
+		WithHealthCheckInterval(m.Config.NexusHealthCheckInterval).WithStartupResync(m.Config.StartupResync).
+		WithFallbackOrganization(m.Config.FallbackOrganization).WithStatusUpdateInterval(m.Config.WatcherUpdateInterval)
 
 	if m.Config.NumberWorkerThreads < 1 {
 		return fmt.Errorf("NumberWorkerThreads must be at least 1, got %d", m.Config.NumberWorkerThreads)
 	}
 
-	// Shared: set up event channel and worker goroutines for both modes.
-	m.eventChan = make(chan plugins.Event, 1)
-	for i := 0; i < m.Config.NumberWorkerThreads; i++ {
-		go m.eventWorker(i)
+	if m.Config.ProvisioningSLO != 0 && m.Config.PodName != "" && m.Config.PodNamespace != "" {
+		notifier, err := slo.NewEventNotifier(m.Config.PodNamespace, m.Config.PodName)
+		if err != nil {
+			log.Warnf("Unable to record provisioning SLO alerts as Kubernetes events: %v", err)
+		} else {
+			m.tracker.WithNotifier(notifier)
+		}
+	}
+
+	if err := m.startHistory(); err != nil {
+		return err
+	}
+	if swept := southbound.SweepOrasTempDirs(); swept > 0 {
+		log.Infof("Removed %d download directories left by a previous run", swept)
 	}
+	m.recordVersion(ctx)
+	m.watchKeycloakSecret()
+	m.watchHarborSecret()
+
+	// Shared: set up event channel and worker goroutines for both modes.
+	m.startWorkers()
+	m.startPurge()
 
 	if m.Config.MultiTenancyEnabled {
-		// Multi-tenant mode: subscribe to Nexus for project lifecycle events.
-		err = m.NexusHook.Subscribe()
-		if err != nil {
-			log.Errorf("Unable to subscribe to Nexus hook %v", err)
+		// Multi-tenant mode: receive project lifecycle events from the configured sources.
+		for _, source := range m.eventSources() {
+			if err := source.Start(m.ctx); err != nil {
+				return fmt.Errorf("unable to start %s event source: %w", source.Name(), err)
+			}
+		}
+		// Migrations apply to the projects recorded in Nexus
+		if !m.Config.EventSourceEnabled(config.EventSourceNexus) {
+			log.Info("Nexus event source is not enabled, skipping migrations")
+			if m.Config.StartupResync {
+				log.Warn("Nexus event source is not enabled, skipping the startup resync")
+			}
+		} else if m.Config.PodNamespace != "" {
+			go m.runMigrations()
+		} else {
+			log.Warn("Controller namespace is not known, skipping migrations")
 		}
 	} else {
 		log.Info("Multi-tenancy disabled: provisioning default project")
@@ -114,7 +178,9 @@ func (m *Manager) Start() error {
 		} else {
 			log.Infof("Resolved default project UUID: %s", uuid)
 		}
-		m.CreateProject("default", "default", uuid, nil)
+		if err := m.CreateProject(m.ctx, "default", "default", uuid, nil); err != nil {
+			return err
+		}
 	}
 
 	// Wait for a termination signal.
@@ -125,100 +191,657 @@ func (m *Manager) Start() error {
 	return nil
 }
 
+// startHistory sets up the store of the provisioning history of the projects and starts the history API.
+func (m *Manager) startHistory() error {
+	if m.Config.HistorySize == 0 {
+		log.Info("Project history is disabled")
+		return nil
+	}
+	if m.Config.PodNamespace == "" {
+		log.Warn("Controller namespace is not known, not recording the project history")
+		return nil
+	}
+	store, err := history.NewConfigMapStore(m.Config.PodNamespace, m.Config.HistorySize)
+	if err != nil {
+		log.Warnf("Unable to record the project history: %v", err)
+		return nil
+	}
+	m.history = store
+	api := history.NewAPI(m.Config.HistoryAPIAddress, store).WithActiveEvents(m.projects.activeEvent).WithExport(m.exportProject).
+		WithHarborCredentialReload(reloadHarborCredentials).WithReprovision(m.reprovisionProject).
+		WithRestore(m.restoreProject).WithDebugQueries(m.debugQueries())
+	if m.Config.AdminAPIAddress != "" {
+		api = api.WithAdmin(m.Config.AdminAPIAddress, auth.NewBearerToken(m.Config.AdminToken))
+	}
+	return api.Start(m.ctx)
+}
+
+// debugQueries returns the read-only troubleshooting queries served by the admin API, none unless they are enabled.
+// Deployments are only queried if ADM is configured.
+func (m *Manager) debugQueries() map[string]history.DebugQuery {
+	if !m.Config.DebugQueries {
+		return nil
+	}
+	if m.Config.AdminAPIAddress == "" {
+		log.Warn("Debug queries are enabled but not served, they are admin calls and the admin API is disabled")
+		return nil
+	}
+	log.Warn("Debug queries are enabled, the admin API reads catalog and ADM data with the controller credentials")
+	queries := map[string]history.DebugQuery{
+		"registries": func(ctx context.Context, uuid string) (interface{}, error) {
+			return plugins.ListDebugRegistries(ctx, m.Config, uuid)
+		},
+	}
+	if m.Config.AdmServer != "" {
+		queries["deployments"] = func(ctx context.Context, uuid string) (interface{}, error) {
+			return plugins.ListDebugDeployments(ctx, m.Config, uuid)
+		}
+	}
+	return queries
+}
+
+// exportProject returns the export bundle of a project for the history API, or nil if the project has no inventory.
+// The provisioning profile and deployment labels of the project are not known here, so they are not exported.
+func (m *Manager) exportProject(ctx context.Context, uuid string) (interface{}, error) {
+	bundle, err := plugins.ExportTenant(ctx, m.Config, plugins.Event{UUID: uuid})
+	if errors.Is(err, plugins.ErrNoInventory) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, southbound.ScrubError(err)
+	}
+	return bundle, nil
+}
+
+// watchSecret is replaced in tests
+var watchSecret = southbound.WatchSecret
+
+// watchKeycloakSecret refreshes the credentials of the southbound clients when the Keycloak admin secret changes, so
+// that a rotated secret is picked up without restarting the controller. A secret that cannot be watched is only
+// logged, the controller then has to be restarted after the secret is rotated.
+func (m *Manager) watchKeycloakSecret() {
+	ref := m.Config.Secrets.KeycloakAdmin
+	if ref.Name == "" && !ref.Mounted() {
+		return
+	}
+	err := watchSecret(m.ctx, ref, func(ctx context.Context) {
+		log.Info("Keycloak admin secret changed, refreshing the service account credentials")
+		if err := plugins.RefreshSecrets(ctx); err != nil {
+			log.Warnf("Unable to refresh the service account credentials: %v", southbound.ScrubError(err))
+		}
+	})
+	if err != nil {
+		log.Warnf("Unable to watch the Keycloak admin secret %s, restart the controller after rotating it: %v", ref, err)
+	}
+}
+
+// watchHarborSecret reloads the Harbor admin credential of the Harbor plugin when the Harbor admin secret changes,
+// so that a rotated credential is picked up without restarting the controller. Harbor calls rejected while the
+// secret is being rotated are sent again with the new credential. A secret that cannot be watched is only logged,
+// the credential can then be reloaded through the history API.
+func (m *Manager) watchHarborSecret() {
+	ref := m.Config.Secrets.HarborAdmin
+	if !m.Config.PluginEnabled(config.PluginHarbor) || (ref.Name == "" && !ref.Mounted()) {
+		return
+	}
+	err := watchSecret(m.ctx, ref, func(ctx context.Context) {
+		log.Info("Harbor admin secret changed, reloading the Harbor admin credential")
+		_, _ = reloadHarborCredentials(ctx)
+	})
+	if err != nil {
+		log.Warnf("Unable to watch the Harbor admin secret %s, reload the credential through the history API after rotating it: %v", ref, err)
+	}
+}
+
+// reloadHarborCredentials reloads the Harbor admin credential of the plugins, with the secrets scrubbed from the
+// error.
+func reloadHarborCredentials(ctx context.Context) (bool, error) {
+	changed, err := plugins.ReloadHarborCredentials(ctx)
+	return changed, southbound.ScrubError(err)
+}
+
+// eventSources returns the configured sources of project lifecycle events.
+func (m *Manager) eventSources() []events.Source {
+	sources := []events.Source{}
+	if m.Config.EventSourceEnabled(config.EventSourceNexus) {
+		sources = append(sources, events.NewNexusSource(m.NexusHook))
+	}
+	if m.Config.EventSourceEnabled(config.EventSourceCloudEvents) {
+		sources = append(sources, events.NewCloudEventsReceiver(m.Config.CloudEventsAddress, m, auth.NewBearerToken(m.Config.CloudEventsToken)))
+	}
+	return sources
+}
+
+// RegisterPlugins creates the provisioning plugins for the configuration and registers them in dispatch order.
+func RegisterPlugins(ctx context.Context, configuration config.Configuration) error {
+	// The plugins are all created before any is registered, so that an error registers none of them
+	var registered []plugins.Plugin
+	if configuration.PluginEnabled(config.PluginHarbor) {
+		harborPlugin, err := plugins.NewHarborProvisionerPlugin(ctx, configuration.HarborServer, configuration.KeycloakServer, configuration.Secrets.HarborAdmin)
+		if err != nil {
+			return err
+		}
+		harborPlugin.WithRobotPolicy(configuration.HarborRobotPolicy).WithGroups(configuration.HarborGroups).
+			WithRobotPermissions(configuration.HarborRobotPermissions).WithRobots(configuration.HarborRobots).
+			WithRequestTimeout(configuration.HarborRequestTimeout).WithLabels(configuration.HarborLabels).WithMaxStorage(configuration.MaxHarborStorage).WithInventory(configuration)
+		registered = append(registered, harborPlugin)
+	}
+
+	if configuration.PluginEnabled(config.PluginCatalog) {
+		if !configuration.PluginEnabled(config.PluginHarbor) {
+			log.Warn("The Harbor plugin is disabled, catalog registries using Harbor robot credentials are skipped")
+		}
+		catalogPlugin, err := plugins.NewCatalogProvisionerPlugin(configuration)
+		if err != nil {
+			return err
+		}
+		registered = append(registered, catalogPlugin)
+	}
+
+	if len(configuration.MirrorArtifacts) > 0 {
+		registered = append(registered, plugins.NewMirrorProvisionerPlugin(configuration))
+	}
+
+	if configuration.PluginEnabled(config.PluginExtensions) {
+		log.Infof("Edge Node manifest path %s%s:%s", configuration.ReleaseServiceBase, configuration.ManifestPath, configuration.ManifestTag)
+		extensionsPlugin, err := plugins.NewExtensionsProvisionerPlugin(configuration)
+		if err != nil {
+			return err
+		}
+		registered = append(registered, extensionsPlugin)
+	}
+
+	if configuration.GitOps.Enabled() {
+		registered = append(registered, plugins.NewGitOpsProvisionerPlugin(configuration))
+	}
+
+	if configuration.TenantNamespaces.Enabled {
+		registered = append(registered, plugins.NewNamespaceProvisionerPlugin(configuration))
+	}
+
+	if configuration.PodNamespace != "" {
+		registered = append(registered, plugins.NewInventoryRecorderPlugin(configuration))
+	}
+	for _, plugin := range registered {
+		plugins.Register(plugin)
+	}
+	if len(configuration.DisabledPlugins) > 0 {
+		log.Infof("Disabled plugins: %v, registered plugins: %v", configuration.DisabledPlugins, plugins.Names())
+	}
+	return nil
+}
+
+// startWorkers creates the event queue and starts the worker goroutines and their watchdog. Close stops them.
+func (m *Manager) startWorkers() {
+	m.eventChan = make(chan plugins.Event, max(m.Config.EventQueueSize, 1))
+	eventQueueCapacity.Set(float64(cap(m.eventChan)))
+	eventWorkers.Set(float64(m.Config.NumberWorkerThreads))
+	for i := 0; i < m.Config.NumberWorkerThreads; i++ {
+		go m.eventWorker(i)
+	}
+	if m.watchdog.enabled() {
+		var ctx context.Context
+		ctx, m.stopWatchdog = context.WithCancel(context.Background())
+		m.watchdogDone = make(chan struct{})
+		go m.runWatchdog(ctx)
+	}
+}
+
 func (m *Manager) eventWorker(id int) {
 	for event := range m.eventChan {
-		start := time.Now()
-		log.Infof("Event worker %d found work on for project %s", id, event.Name)
-		err := m.handleProjectEvent(event)
-		if err != nil {
-			log.Errorf("Unable to handle project event: %v", err)
-			if event.Project != nil && m.NexusHook != nil {
-				if watchErr := m.NexusHook.SetWatcherStatusError(event.Project, err.Error()); watchErr != nil {
-					log.Errorf("Unable to set watcher error status: %v", watchErr)
-				}
+		eventQueueDepth.Set(float64(len(m.eventChan)))
+		// Events for the same project that arrived meanwhile are handled by this worker, in order
+		for {
+			m.processEvent(id, event)
+			next, ok := m.projects.release(event.UUID)
+			if !ok {
+				break
 			}
-			continue
+			event = next
 		}
-		// Success path: update watcher status to IDLE.
-			if event.Project != nil && m.NexusHook != nil {
-			if setStatusErr := m.NexusHook.SetWatcherStatusIdle(event.Project); setStatusErr != nil {
-				log.Errorf("Failed to update ProjectActiveWatcher object with an error: %v", setStatusErr)
-				return
+	}
+}
+
+// processEvent takes the event through its lifecycle and reports the outcome on the project watcher.
+func (m *Manager) processEvent(id int, event plugins.Event) {
+	start := time.Now()
+	lifecycle := event.Lifecycle
+	if err := lifecycle.Validate(); err != nil {
+		log.Infof("Skipping %s event for project %s: %s", event.EventType, event.Name, lifecycle)
+		return
+	}
+	log.Infof("Event worker %d found work on for project %s", id, event.Name)
+	eventWorkersBusy.Inc()
+	defer eventWorkersBusy.Dec()
+	ctx, cancel := context.WithCancelCause(lifecycle.Context())
+	defer cancel(nil)
+	m.watchdog.start(id, event, cancel)
+	defer m.watchdog.done(id)
+	var result *plugins.DispatchResult
+	err := event.Validate()
+	if err == nil {
+		result, err = m.handleProjectEvent(ctx, event)
+	}
+	// An event cancelled by the watchdog fails with the diagnostics of the watchdog
+	var stuck *errEventStuck
+	if errors.As(context.Cause(ctx), &stuck) {
+		err = stuck
+	}
+	if lifecycle.Phase() == plugins.PhaseCancelled {
+		log.Infof("%s event for project %s was cancelled", event.EventType, event.Name)
+		m.record(event, result, history.ResultCancelled, err)
+		return
+	}
+	if err != nil {
+		_ = lifecycle.Fail(err)
+		log.Errorf("Unable to handle project event, %s: %v", lifecycle, err)
+		if event.Project != nil && m.NexusHook != nil {
+			if watchErr := m.NexusHook.SetWatcherStatusError(event.Project, southbound.UserMessage(err)); watchErr != nil {
+				log.Errorf("Unable to set watcher error status: %v", watchErr)
 			}
 		}
-		if event.EventType == "delete" && event.Project != nil && m.NexusHook != nil {
-			m.NexusHook.StopWatchingProject(event.Project)
+		m.observe(event, result, err)
+		m.record(event, result, history.ResultError, err)
+		m.emitAudit(event, result, err)
+		return
+	}
+	_ = lifecycle.Complete()
+	// Success path: update watcher status to IDLE.
+	if event.Project != nil && m.NexusHook != nil {
+		if setStatusErr := m.NexusHook.SetWatcherStatusIdle(event.Project, watcherMessage(result)); setStatusErr != nil {
+			log.Errorf("Failed to update ProjectActiveWatcher object with an error: %v", setStatusErr)
+			return
+		}
+	}
+	m.observe(event, result, nil)
+	m.record(event, result, history.ResultSuccess, nil)
+	m.emitAudit(event, result, nil)
+	if deletion(event) && event.Project != nil && m.NexusHook != nil {
+		m.NexusHook.StopWatchingProject(event.Project)
+	}
+	elapsed := time.Since(start)
+	log.Infof("Done with %s on worker %d for project %s elapsed time %d seconds", event.EventType, id, event.Name, int(elapsed.Seconds()))
+}
+
+// watcherMessage returns the message of the watcher of a project whose event succeeded, listing the warnings of the
+// plugins if there were any.
+func watcherMessage(result *plugins.DispatchResult) string {
+	if result == nil || len(result.Warnings) == 0 {
+		return "Created"
+	}
+	return fmt.Sprintf("Created with warnings: %s", strings.Join(result.Warnings, "; "))
+}
+
+// observe reports the time from receiving the event until the watcher was updated to the SLO tracker and the average
+// event latency metric.
+func (m *Manager) observe(event plugins.Event, result *plugins.DispatchResult, err error) {
+	if event.Received.IsZero() {
+		return
+	}
+	finished := time.Now()
+	eventLatencies.add(finished, finished.Sub(event.Received))
+	m.tracker.Observe(context.Background(), slo.Provisioning{
+		EventType:    event.EventType,
+		Organization: event.Organization,
+		Project:      event.Name,
+		UUID:         event.UUID,
+		Received:     event.Received,
+		Finished:     finished,
+		Err:          err,
+		PluginTimes:  result.PluginTimes(),
+	})
+}
+
+// record adds the outcome of the event to the history of the project. Failing to record it is logged and does not
+// fail the event.
+func (m *Manager) record(event plugins.Event, result *plugins.DispatchResult, outcome string, err error) {
+	if m.history == nil || event.Received.IsZero() {
+		return
+	}
+	entry := history.NewEntry(event.EventType, event.Received, time.Now(), outcome, err, pluginOutcomes(result, outcome))
+	if result != nil {
+		entry.Warnings = result.Warnings
+		entry.Resources = result.Resources()
+	}
+	if event.Profile != nil {
+		entry.Profile = event.Profile.Name
+	}
+	entry.ControllerVersion = m.Config.ControllerVersion
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := m.history.Append(ctx, event.Organization, event.Name, event.UUID, entry); err != nil {
+		log.Warnf("Unable to record the history of project %s: %v", event.Name, err)
+	}
+}
+
+// emitAudit sends the audit event of a finished create or delete event, with the resources the plugins created and
+// the time it took from receiving the event.
+func (m *Manager) emitAudit(event plugins.Event, result *plugins.DispatchResult, err error) {
+	var eventType string
+	switch {
+	case event.EventType == "create" && err == nil:
+		eventType = audit.TenantProvisioned
+	case event.EventType == "create":
+		eventType = audit.TenantProvisioningFailed
+	case event.EventType == "delete" && err == nil:
+		eventType = audit.TenantDeleted
+	case event.EventType == "delete":
+		eventType = audit.TenantDeletionFailed
+	default:
+		return
+	}
+	auditEvent := m.auditEvent(eventType, event)
+	if err != nil {
+		auditEvent.Error = southbound.ScrubError(err).Error()
+	}
+	if result != nil {
+		auditEvent.Resources = result.Resources()
+		auditEvent.PluginSeconds = map[string]float64{}
+		for plugin, elapsed := range result.PluginTimes() {
+			auditEvent.PluginSeconds[plugin] = elapsed.Seconds()
+		}
+		auditEvent.PayloadBytes = result.PayloadBytes
+	}
+	if !event.Received.IsZero() {
+		auditEvent.DurationSeconds = time.Since(event.Received).Seconds()
+	}
+	m.audit.Emit(auditEvent)
+}
+
+// auditEvent returns the audit event of the given type for the project of the event.
+func (m *Manager) auditEvent(eventType string, event plugins.Event) audit.Event {
+	actor := config.EventSourceNexus
+	if _, ok := event.Project.(*events.ExternalProject); ok {
+		actor = config.EventSourceCloudEvents
+	}
+	return audit.Event{
+		Type:              eventType,
+		Actor:             actor,
+		Organization:      event.Organization,
+		Project:           event.Name,
+		UUID:              event.UUID,
+		ControllerVersion: m.Config.ControllerVersion,
+	}
+}
+
+// pluginOutcomes returns the history of the plugins that ran for the event. The plugin that failed, if any, has the
+// outcome of the event.
+func pluginOutcomes(result *plugins.DispatchResult, outcome string) []history.PluginOutcome {
+	if result == nil {
+		return nil
+	}
+	var outcomes []history.PluginOutcome
+	for _, plugin := range result.Plugins {
+		if plugin.Status == plugins.PluginSkipped {
+			continue
+		}
+		pluginOutcome := history.PluginOutcome{Name: plugin.Name, DurationSeconds: plugin.Duration.Seconds(),
+			Attempts: plugin.Attempts, Result: history.ResultSuccess}
+		if plugin.Status == plugins.PluginFailed {
+			pluginOutcome.Result = outcome
 		}
-		elapsed := time.Since(start)
-		log.Infof("Done with %s on worker %d for project %s elapsed time %d seconds", event.EventType, id, event.Name, int(elapsed.Seconds()))
+		outcomes = append(outcomes, pluginOutcome)
 	}
+	return outcomes
 }
 
-func (m *Manager) handleProjectEvent(event plugins.Event) error {
-	startTime := time.Now()
+// handleProjectEvent dispatches the event, retrying transient failures from the plugin that failed with jittered
+// exponential backoff. Throttled failures are retried after the delay the service asked for instead. The maximum
+// wait time is a retry budget shared with the retries made by the plugins, so that together they stop once it is
+// used up. It stops when the context is done. The dispatch result covers all attempts.
+func (m *Manager) handleProjectEvent(ctx context.Context, event plugins.Event) (*plugins.DispatchResult, error) {
+	eventCtx := retry.WithBudget(ctx, retry.ClockFrom(ctx).Now().Add(m.Config.MaxWaitTime))
 	maxTimeout := m.Config.InitialSleepInterval * 10 * time.Second
-	sleepInterval := m.Config.InitialSleepInterval
+	backoff := retry.Backoff{
+		Initial: m.Config.InitialSleepInterval,
+		Max:     m.Config.MaxWaitTime,
+		Jitter:  0.2,
+	}
 
+	var result *plugins.DispatchResult
 	var err error
 
-	for {
-		ctx, cancel := context.WithTimeout(context.Background(), maxTimeout)
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(eventCtx, maxTimeout)
 
-		// dispatch the event
-		err = plugins.Dispatch(ctx, event, m.NexusHook)
+		// dispatch the event, scrubbing the error as it is logged and reported on the project watcher
+		result, err = plugins.Dispatch(ctx, event, m.NexusHook)
+		err = southbound.ScrubError(err)
 
 		cancel()
 
 		if err == nil {
-			return err
+			return result, err
+		}
+		if eventCtx.Err() != nil {
+			return result, err
 		}
-		log.Infof("Error processing event, retrying: %+v", err)
 
-		// Check if the maximum wait time has been exceeded
-		if time.Since(startTime) > m.Config.MaxWaitTime {
-			log.Errorf("Failed to handle event %s within the maximum wait time\n", event.Name)
-			break
+		// Permanent failures are reported to the watcher right away, retrying them would only delay the error
+		if !southbound.IsRetryable(err) {
+			log.Errorf("Permanent error processing event %s for project %s, not retrying: %v", event.EventType, event.Name, err)
+			return result, err
 		}
+		log.Infof("Error processing event, retrying: %+v", err)
 
 		if event.Project != nil {
-			err = m.NexusHook.SetWatcherStatusInProgress(event.Project, fmt.Sprintf("Retry backoff for project %s. Last error was %s", event.Name, err.Error()))
-			if err != nil {
-				return err
+			lastError := southbound.UserMessage(err)
+			message := fmt.Sprintf("Retry backoff for project %s. Last error was %s", event.Name, lastError)
+			if errors.Is(err, southbound.ErrConflict) {
+				message = fmt.Sprintf("Retry backoff for project %s after a conflicting change. Last error was %s", event.Name, lastError)
+			} else if errors.Is(err, southbound.ErrThrottled) {
+				message = fmt.Sprintf("Retry backoff for project %s, throttled by a southbound service. Last error was %s", event.Name, lastError)
+			}
+			if watchErr := m.NexusHook.SetWatcherStatusInProgress(event.Project, message); watchErr != nil {
+				return result, watchErr
 			}
 		}
-		log.Infof("Retrying in %d seconds", int(sleepInterval.Seconds()))
-		time.Sleep(sleepInterval)
+		// A throttling server is given the time it asked for, without growing the backoff of the failed attempts
+		delay, throttled := backoff.ThrottleDelay(err)
+		if throttled {
+			attempt--
+		} else {
+			delay = backoff.Delay(attempt)
+		}
+		log.Infof("Retrying %s in %d seconds", event.Lifecycle, int(delay.Seconds()))
+		if sleepErr := retry.Sleep(eventCtx, delay); sleepErr != nil {
+			if errors.Is(sleepErr, retry.ErrBudgetExhausted) {
+				log.Errorf("Failed to handle event %s within the maximum wait time", event.Name)
+			}
+			return result, err
+		}
 	}
-	return err
 }
 
-func (m *Manager) CreateProject(organizationName string, projectName string, projectUUID string, project nexushook.NexusProjectInterface) {
-	log.Debugf("Creating project with organizationName=%s; projectName=%s; projectUUID=%s", organizationName, projectName, projectUUID)
-	e := plugins.Event{
-		EventType:    "create",
-		Organization: organizationName,
-		Name:         projectName,
-		UUID:         projectUUID,
-		Project:      project,
+func (m *Manager) selectProfile(organizationName string, annotations map[string]string) *config.ProvisioningProfile {
+	profile := m.Config.ProvisioningProfiles.Select(annotations[nexushook.ProvisioningProfileAnnotationKey], organizationName)
+	if profile != nil {
+		log.Infof("Using provisioning profile %s for organization %s", profile.Name, organizationName)
 	}
-	m.eventChan <- e
+	return profile
 }
 
-func (m *Manager) DeleteProject(organizationName string, projectName string, projectUUID string, project nexushook.NexusProjectInterface) {
-	log.Debugf("Deleting project with organizationName=%s; projectName=%s; projectUUID=%s", organizationName, projectName, projectUUID)
+func (m *Manager) deploymentLabels(project events.ProjectV1) map[string]string {
+	var labels map[string]string
+	if project.Labels != nil || project.Annotations != nil {
+		labels = m.Config.SelectDeploymentLabels(project.Labels, project.Annotations)
+	}
+	if m.Config.DeploymentTenantLabels {
+		labels = plugins.TenantDeploymentLabels(labels, project.Organization, project.UUID)
+	}
+	return labels
+}
+
+// enqueue hands the event to the worker pool, acknowledging it once a worker queue slot accepts it. An event for a
+// project that already has an event in progress is acknowledged right away and handled after it. While the queue is
+// full, the project watcher reports the event as queued. An event that was received before, like a stuck event
+// queued again by the watchdog, keeps its received time.
+func (m *Manager) enqueue(ctx context.Context, e plugins.Event) error {
+	if e.Received.IsZero() {
+		e.Received = time.Now()
+	}
+	if e.Lifecycle == nil {
+		e.Lifecycle = plugins.NewLifecycle(context.Background())
+	}
+	if !m.projects.acquire(e) {
+		return nil
+	}
+	select {
+	case m.eventChan <- e:
+		eventQueueDepth.Set(float64(len(m.eventChan)))
+		return nil
+	default:
+	}
+
+	// The queue is full. Show that the project is waiting rather than leave its watcher as it was.
+	eventQueueSaturated.Inc()
+	eventQueueBlocked.Inc()
+	defer eventQueueBlocked.Dec()
+	log.Infof("Event queue is full, %s event for project %s is waiting for a free worker", e.EventType, e.Name)
+	if e.Project != nil && m.NexusHook != nil {
+		if err := m.NexusHook.SetWatcherStatusInProgress(e.Project, "Queued"); err != nil {
+			log.Warnf("Unable to set queued watcher status for project %s: %v", e.Name, err)
+		}
+	}
+	select {
+	case m.eventChan <- e:
+		eventQueueDepth.Set(float64(len(m.eventChan)))
+		return nil
+	case <-ctx.Done():
+		e.Lifecycle.Cancel()
+		// Events held back behind this one must still be handled
+		if next, ok := m.projects.release(e.UUID); ok {
+			m.requeue(next)
+		}
+		return fmt.Errorf("unable to queue %s event for project %s: %w", e.EventType, e.Name, ctx.Err())
+	}
+}
+
+// requeue hands an event that was held back behind an event that could not be queued to the worker pool, without
+// blocking the caller. If the manager shuts down first, the event and the events held back behind it are cancelled.
+func (m *Manager) requeue(e plugins.Event) {
+	m.requeuers.Add(1)
+	go func() {
+		defer m.requeuers.Done()
+		select {
+		case m.eventChan <- e:
+			eventQueueDepth.Set(float64(len(m.eventChan)))
+			return
+		case <-m.ctx.Done():
+		case <-m.closing:
+		}
+		for {
+			e.Lifecycle.Cancel()
+			log.Infof("Dropping %s event for project %s, the manager is shutting down", e.EventType, e.Name)
+			next, ok := m.projects.release(e.UUID)
+			if !ok {
+				return
+			}
+			e = next
+		}
+	}()
+}
+
+func (m *Manager) CreateProject(ctx context.Context, organizationName string, projectName string, projectUUID string, project nexushook.NexusProjectInterface) error {
+	return m.HandleProjectEvent(ctx, events.CreateProjectV1{
+		Project: events.ProjectFromNexus(organizationName, projectName, projectUUID, project),
+	})
+}
+
+func (m *Manager) UpdateProject(ctx context.Context, organizationName string, projectName string, projectUUID string, project nexushook.NexusProjectInterface, changes nexushook.ProjectChanges) error {
+	return m.HandleProjectEvent(ctx, events.UpdateProjectV1{
+		Project: events.ProjectFromNexus(organizationName, projectName, projectUUID, project),
+		Changes: changes,
+	})
+}
+
+func (m *Manager) DeleteProject(ctx context.Context, organizationName string, projectName string, projectUUID string, project nexushook.NexusProjectInterface) error {
+	return m.HandleProjectEvent(ctx, events.NewDeleteProjectV1(
+		events.ProjectFromNexus(organizationName, projectName, projectUUID, project)))
+}
+
+// EnsureProject converges a provisioned project, without deleting its resources or issuing new credentials.
+func (m *Manager) EnsureProject(ctx context.Context, organizationName string, projectName string, projectUUID string, project nexushook.NexusProjectInterface) error {
+	return m.HandleProjectEvent(ctx, events.EnsureProjectV1{
+		Project: events.ProjectFromNexus(organizationName, projectName, projectUUID, project),
+	})
+}
+
+// HandleProjectEvent queues a project event of the versioned schema for the plugins. It returns a permanent error
+// for events of a schema version or type the manager does not handle.
+func (m *Manager) HandleProjectEvent(ctx context.Context, event events.ProjectEvent) error {
+	e, err := m.pluginEvent(event)
+	if err != nil {
+		return err
+	}
+	log.Debugf("Handling %s %s event with organizationName=%s; projectName=%s; projectUUID=%s",
+		event.Type(), event.SchemaVersion(), e.Organization, e.Name, e.UUID)
+	if err := m.enqueue(ctx, e); err != nil {
+		return err
+	}
+	if _, ok := event.(events.CreateProjectV1); ok {
+		m.audit.Emit(m.auditEvent(audit.TenantCreated, e))
+	}
+	return nil
+}
+
+// pluginEvent converts a project event of the versioned schema to the event passed to the plugins. This is the
+// only place that knows both, so the schema and the plugins can change independently.
+func (m *Manager) pluginEvent(event events.ProjectEvent) (plugins.Event, error) {
+	if event.SchemaVersion() != events.SchemaVersionV1 {
+		return plugins.Event{}, fmt.Errorf("%w: unsupported project event schema version %s", southbound.ErrPermanent, event.SchemaVersion())
+	}
+	project := event.ProjectRef()
 	e := plugins.Event{
-		EventType:    "delete",
-		Organization: organizationName,
-		Name:         projectName,
-		UUID:         projectUUID,
-		Project:      project,
+		Organization: project.Organization,
+		Name:         project.Name,
+		UUID:         project.UUID,
+		Project:      project.Source(),
+	}
+	switch event := event.(type) {
+	case events.CreateProjectV1:
+		e.EventType = "create"
+		e.Profile = m.selectProfile(project.Organization, project.Annotations)
+		e.DeploymentLabels = m.deploymentLabels(project)
+	case events.UpdateProjectV1:
+		e.EventType = "update"
+		e.Profile = m.selectProfile(project.Organization, project.Annotations)
+		e.Changes = event.Changes
+		e.DeploymentLabels = m.deploymentLabels(project)
+	case events.DeleteProjectV1:
+		e.EventType = "delete"
+		e.RetainData = event.RetainData
+		// The resources are deleted by the purge once the retention period is over, unless the project is restored
+		if m.Config.DeletionMode == config.DeletionModeSoft {
+			e.EventType = "deactivate"
+			e.PurgeAfter = time.Now().Add(m.Config.DeletionRetention)
+		}
+	case events.UpdateManifestV1:
+		// Provisioning is idempotent, so the current manifest is applied by provisioning the project again
+		e.EventType = "create"
+		e.Profile = m.Config.ProvisioningProfiles.Select(event.Profile, project.Organization)
+		e.DeploymentLabels = m.deploymentLabels(project)
+		e.RefreshCredentials = event.RefreshCredentials
+	case events.EnsureProjectV1:
+		e.EventType = "ensure"
+		e.Profile = m.selectProfile(project.Organization, project.Annotations)
+		e.DeploymentLabels = m.deploymentLabels(project)
+		e.Plugins = event.Plugins
+	default:
+		return plugins.Event{}, fmt.Errorf("%w: unsupported project event type %s", southbound.ErrPermanent, event.Type())
 	}
-	m.eventChan <- e
+	return e, nil
 }
 
 // Close kills the channels and manager related objects
 func (m *Manager) Close() {
 	log.Info("Closing Manager")
+	if m.stopWatchdog != nil {
+		m.stopWatchdog()
+		<-m.watchdogDone
+	}
+	close(m.closing)
+	m.requeuers.Wait()
 	close(m.eventChan)
 }
 
@@ -226,6 +849,10 @@ func (m *Manager) ManifestTag() string {
 	return m.Config.ManifestTag
 }
 
+func (m *Manager) ControllerVersion() string {
+	return m.Config.ControllerVersion
+}
+
 // HealthCheck is a struct receiver implementing onos northbound Register interface.
 type HealthCheck struct{}
 
@@ -4,13 +4,31 @@
 package manager
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/audit"
 	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/events"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/history"
+	nexushook "github.com/open-edge-platform/app-orch-tenant-controller/internal/nexus"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/plugins"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/retry"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/suite"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 	"os"
+	"path/filepath"
 )
 
 // Suite of manager tests
@@ -40,6 +58,8 @@ func (s *ManagerTestSuite) clearEnvironment() {
 	_ = os.Unsetenv("MANIFEST_PATH")
 	_ = os.Unsetenv("MANIFEST_TAG")
 	_ = os.Unsetenv("REGISTRY_HOST_EXTERNAL")
+	_ = os.Unsetenv("REGISTRY_HELM_HOST_EXTERNAL")
+	_ = os.Unsetenv("REGISTRY_DOCKER_HOST_EXTERNAL")
 	_ = os.Unsetenv("CATALOG_SERVER")
 	_ = os.Unsetenv("HARBOR_SERVER")
 	_ = os.Unsetenv("HARBOR_NAMESPACE")
@@ -49,10 +69,97 @@ func (s *ManagerTestSuite) clearEnvironment() {
 	_ = os.Unsetenv("SERVICE_ACCOUNT")
 	_ = os.Unsetenv("KEYCLOAK_NAMESPACE")
 	_ = os.Unsetenv("KEYCLOAK_SECRET")
+	_ = os.Unsetenv("KEYCLOAK_SECRET_KEY")
+	_ = os.Unsetenv("KEYCLOAK_SECRET_PATH")
+	_ = os.Unsetenv("HARBOR_ADMIN_CREDENTIAL_KEY")
+	_ = os.Unsetenv("HARBOR_ADMIN_CREDENTIAL_PATH")
 	_ = os.Unsetenv("ADM_SERVER")
 	_ = os.Unsetenv("RELEASE_SERVICE_BASE")
 	_ = os.Unsetenv("INITIAL_SLEEP_INTERVAL")
 	_ = os.Unsetenv("MAX_WAIT_TIME")
+	_ = os.Unsetenv("PROVISIONING_PROFILES")
+	_ = os.Unsetenv("HARBOR_GROUPS")
+	_ = os.Unsetenv("HARBOR_ROBOT_PERMISSIONS")
+	_ = os.Unsetenv("HARBOR_ROBOTS")
+	_ = os.Unsetenv("HARBOR_LABELS")
+	_ = os.Unsetenv("REGISTRY_TEMPLATE_PATH")
+	_ = os.Unsetenv("NEXUS_TIMEOUT")
+	_ = os.Unsetenv("STUCK_EVENT_TIMEOUT")
+	_ = os.Unsetenv("CATALOG_UPLOAD_TIMEOUT")
+	_ = os.Unsetenv("ADM_CREATE_TIMEOUT")
+	_ = os.Unsetenv("HARBOR_REQUEST_TIMEOUT")
+	_ = os.Unsetenv("NEXUS_HEALTH_CHECK_INTERVAL")
+	_ = os.Unsetenv("WATCHER_UPDATE_INTERVAL")
+	_ = os.Unsetenv("GRPC_KEEPALIVE_TIME")
+	_ = os.Unsetenv("GRPC_KEEPALIVE_TIMEOUT")
+	_ = os.Unsetenv("GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM")
+	_ = os.Unsetenv("GRPC_BACKOFF_BASE_DELAY")
+	_ = os.Unsetenv("GRPC_BACKOFF_MAX_DELAY")
+	_ = os.Unsetenv("STARTUP_RESYNC")
+	_ = os.Unsetenv("ENABLE_HARBOR_PLUGIN")
+	_ = os.Unsetenv("ENABLE_CATALOG_PLUGIN")
+	_ = os.Unsetenv("ENABLE_EXTENSIONS_PLUGIN")
+	_ = os.Unsetenv("DEPLOYMENT_LABEL_KEYS")
+	_ = os.Unsetenv("DEPLOYMENT_TENANT_LABELS")
+	_ = os.Unsetenv("PROVISIONING_SLO")
+	_ = os.Unsetenv("SLO_WEBHOOK_URL")
+	_ = os.Unsetenv("AUDIT_URL")
+	_ = os.Unsetenv("POD_NAME")
+	_ = os.Unsetenv("POD_NAMESPACE")
+	_ = os.Unsetenv("KEYCLOAK_SERVICE_BASE")
+	_ = os.Unsetenv("USE_LOCAL_MANIFEST")
+	_ = os.Unsetenv("MIRROR_ARTIFACTS")
+	_ = os.Unsetenv("STARTER_APPS_PATH")
+	_ = os.Unsetenv("ORG_EXTENSIONS_PATH")
+	_ = os.Unsetenv("MAX_CATALOG_REGISTRIES")
+	_ = os.Unsetenv("MAX_EXTENSION_DEPLOYMENTS")
+	_ = os.Unsetenv("MAX_CATALOG_ARTIFACT_SIZE")
+	_ = os.Unsetenv("MAX_HARBOR_STORAGE")
+	_ = os.Unsetenv("MAX_CATALOG_PROJECTS")
+	_ = os.Unsetenv("CONTROLLER_VERSION")
+	_ = os.Unsetenv("EVENT_QUEUE_SIZE")
+	_ = os.Unsetenv("EVENT_SOURCES")
+	_ = os.Unsetenv("CLOUDEVENTS_ADDRESS")
+	_ = os.Unsetenv("CLOUDEVENTS_TOKEN_NAMESPACE")
+	_ = os.Unsetenv("CLOUDEVENTS_TOKEN_SECRET")
+	_ = os.Unsetenv("CLOUDEVENTS_TOKEN_KEY")
+	_ = os.Unsetenv("CLOUDEVENTS_TOKEN_PATH")
+	_ = os.Unsetenv("ADMIN_API_ADDRESS")
+	_ = os.Unsetenv("ADMIN_TOKEN_NAMESPACE")
+	_ = os.Unsetenv("ADMIN_TOKEN_SECRET")
+	_ = os.Unsetenv("ADMIN_TOKEN_KEY")
+	_ = os.Unsetenv("ADMIN_TOKEN_PATH")
+	_ = os.Unsetenv("HISTORY_SIZE")
+	_ = os.Unsetenv("HISTORY_API_ADDRESS")
+	_ = os.Unsetenv("GITOPS_PROVIDER")
+	_ = os.Unsetenv("GITOPS_SERVER")
+	_ = os.Unsetenv("GITOPS_OWNER")
+	_ = os.Unsetenv("GITOPS_NAMESPACE")
+	_ = os.Unsetenv("GITOPS_TOKEN_SECRET")
+	_ = os.Unsetenv("GITOPS_TOKEN_KEY")
+	_ = os.Unsetenv("GITOPS_TOKEN_PATH")
+	_ = os.Unsetenv("GITOPS_DEPLOY_KEY_READ_ONLY")
+	_ = os.Unsetenv("TENANT_NAMESPACES")
+	_ = os.Unsetenv("TENANT_NAMESPACE_PREFIX")
+	_ = os.Unsetenv("MISSING_ORGANIZATION_POLICY")
+	_ = os.Unsetenv("FALLBACK_ORGANIZATION")
+	_ = os.Unsetenv("DELETION_MODE")
+	_ = os.Unsetenv("DELETION_RETENTION")
+	_ = os.Unsetenv("CATALOG_REGISTRY_PREFIX")
+	_ = os.Unsetenv("DEBUG_QUERIES")
+	_ = os.Unsetenv("CATALOG_REGISTRY_SUFFIX")
+	_ = os.Unsetenv("CONFIG_PROFILE")
+	_ = os.Unsetenv("RS_HELM_ROOT_URL")
+	_ = os.Unsetenv("RS_IMAGE_ROOT_URL")
+	_ = os.Unsetenv("RS_HELM_PATH_PREFIX")
+	_ = os.Unsetenv("RS_IMAGE_PATH_PREFIX")
+	_ = os.Unsetenv("RS_CREDENTIAL_NAMESPACE")
+	_ = os.Unsetenv("RS_CREDENTIAL_SECRET")
+	_ = os.Unsetenv("RS_CREDENTIAL_KEY")
+	_ = os.Unsetenv("RS_CREDENTIAL_PATH")
+	_ = os.Unsetenv("RS_TLS")
+	_ = os.Unsetenv("RS_CA_CERTS")
+	_ = os.Unsetenv("RS_TLS_INSECURE_SKIP_VERIFY")
 }
 
 func (s *ManagerTestSuite) TestInit() {
@@ -77,6 +184,8 @@ func (s *ManagerTestSuite) TestInit() {
 	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "11")
 	_ = os.Setenv("MAX_WAIT_TIME", "22")
 	_ = os.Setenv("NUMBER_WORKER_THREADS", "33")
+	_ = os.Setenv("REGISTRY_TEMPLATE_PATH", "REGISTRY_TEMPLATE_PATH")
+	_ = os.Setenv("NEXUS_TIMEOUT", "44")
 
 	conf, err := config.InitConfig()
 	s.NoError(err)
@@ -88,19 +197,19 @@ func (s *ManagerTestSuite) TestInit() {
 	s.Equal("REGISTRY_HOST_EXTERNAL", conf.HarborServerExternal)
 	s.Equal("CATALOG_SERVER", conf.CatalogServer)
 	s.Equal("HARBOR_SERVER", conf.HarborServer)
-	s.Equal("HARBOR_NAMESPACE", conf.HarborNamespace)
-	s.Equal("HARBOR_ADMIN_CREDENTIAL", conf.HarborAdminCredential)
+	s.Equal(config.SecretRef{Namespace: "HARBOR_NAMESPACE", Name: "HARBOR_ADMIN_CREDENTIAL", Key: "credential"}, conf.Secrets.HarborAdmin)
 	s.Equal("KEYCLOAK_SERVICE_BASE", conf.KeycloakServiceBase)
 	s.Equal("KEYCLOAK_SERVER", conf.KeycloakServer)
 	s.Equal("VAULT_SERVER", conf.VaultServer)
 	s.Equal("SERVICE_ACCOUNT", conf.ServiceAccount)
-	s.Equal("KEYCLOAK_NAMESPACE", conf.KeycloakNamespace)
-	s.Equal("KEYCLOAK_SECRET", conf.KeycloakSecret)
+	s.Equal(config.SecretRef{Namespace: "KEYCLOAK_NAMESPACE", Name: "KEYCLOAK_SECRET", Key: "admin-password"}, conf.Secrets.KeycloakAdmin)
 	s.Equal("ADM_SERVER", conf.AdmServer)
 	s.Equal("RELEASE_SERVICE_BASE", conf.ReleaseServiceBase)
 	s.Equal(11*time.Second, conf.InitialSleepInterval)
 	s.Equal(22*time.Second, conf.MaxWaitTime)
 	s.Equal(33, conf.NumberWorkerThreads)
+	s.Equal("REGISTRY_TEMPLATE_PATH", conf.RegistryTemplatePath)
+	s.Equal(44*time.Second, conf.NexusTimeout)
 }
 
 func (s *ManagerTestSuite) TestBadInterval() {
@@ -126,34 +235,1454 @@ func (s *ManagerTestSuite) TestBadMaxWait() {
 	s.Contains(err.Error(), "invalid syntax")
 }
 
+func (s *ManagerTestSuite) TestNexusTimeout() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "100")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "2")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Equal(5*time.Second, conf.NexusTimeout)
+
+	_ = os.Setenv("NEXUS_TIMEOUT", "0")
+	_, err = config.InitConfig()
+	s.Error(err)
+	s.Contains(err.Error(), "invalid NEXUS_TIMEOUT")
+}
+
+func (s *ManagerTestSuite) TestStuckEventTimeout() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "100")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "2")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Equal(300*time.Second, conf.StuckEventTimeout)
+
+	_ = os.Setenv("STUCK_EVENT_TIMEOUT", "0")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Zero(conf.StuckEventTimeout)
+
+	// Events must be allowed the maximum wait time
+	for _, value := range []string{"50", "-1", "soon"} {
+		_ = os.Setenv("STUCK_EVENT_TIMEOUT", value)
+		_, err = config.InitConfig()
+		s.ErrorContains(err, "invalid STUCK_EVENT_TIMEOUT", value)
+	}
+}
+
+func (s *ManagerTestSuite) TestSouthboundTimeouts() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "100")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "2")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Equal(300*time.Second, conf.CatalogUploadTimeout)
+	s.Equal(60*time.Second, conf.ADMCreateTimeout)
+	s.Equal(60*time.Second, conf.HarborRequestTimeout)
+
+	// The timeouts are independent of each other and of the maximum wait time
+	_ = os.Setenv("CATALOG_UPLOAD_TIMEOUT", "900")
+	_ = os.Setenv("HARBOR_REQUEST_TIMEOUT", "10")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal(900*time.Second, conf.CatalogUploadTimeout)
+	s.Equal(60*time.Second, conf.ADMCreateTimeout)
+	s.Equal(10*time.Second, conf.HarborRequestTimeout)
+
+	_ = os.Setenv("ADM_CREATE_TIMEOUT", "0")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "invalid ADM_CREATE_TIMEOUT")
+}
+
+func (s *ManagerTestSuite) TestGRPCSettings() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "100")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "2")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Equal(config.GRPCSettings{
+		KeepaliveTime:    300 * time.Second,
+		KeepaliveTimeout: 20 * time.Second,
+		BackoffBaseDelay: time.Second,
+		BackoffMaxDelay:  30 * time.Second,
+	}, conf.GRPC)
+
+	_ = os.Setenv("GRPC_KEEPALIVE_TIME", "0")
+	_ = os.Setenv("GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM", "true")
+	_ = os.Setenv("GRPC_BACKOFF_MAX_DELAY", "120")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Zero(conf.GRPC.KeepaliveTime)
+	s.True(conf.GRPC.KeepalivePermitWithoutStream)
+	s.Equal(120*time.Second, conf.GRPC.BackoffMaxDelay)
+
+	for env, value := range map[string]string{
+		"GRPC_KEEPALIVE_TIMEOUT":               "0",
+		"GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM": "sometimes",
+		"GRPC_BACKOFF_BASE_DELAY":              "300",
+	} {
+		_ = os.Setenv(env, value)
+		_, err = config.InitConfig()
+		s.ErrorContains(err, "GRPC_", env)
+		s.clearEnvironment()
+		_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+		_ = os.Setenv("MAX_WAIT_TIME", "100")
+		_ = os.Setenv("NUMBER_WORKER_THREADS", "2")
+	}
+}
+
+func (s *ManagerTestSuite) TestNexusHealthCheckInterval() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "100")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "2")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Equal(30*time.Second, conf.NexusHealthCheckInterval)
+
+	_ = os.Setenv("NEXUS_HEALTH_CHECK_INTERVAL", "0")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal(time.Duration(0), conf.NexusHealthCheckInterval)
+
+	_ = os.Setenv("NEXUS_HEALTH_CHECK_INTERVAL", "-1")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "invalid NEXUS_HEALTH_CHECK_INTERVAL")
+}
+
+func (s *ManagerTestSuite) TestWatcherUpdateInterval() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "100")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "2")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Equal(10*time.Second, conf.WatcherUpdateInterval)
+
+	_ = os.Setenv("WATCHER_UPDATE_INTERVAL", "0")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal(time.Duration(0), conf.WatcherUpdateInterval)
+
+	_ = os.Setenv("WATCHER_UPDATE_INTERVAL", "soon")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "invalid WATCHER_UPDATE_INTERVAL")
+}
+
+func (s *ManagerTestSuite) TestStartupResync() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "100")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "2")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.False(conf.StartupResync)
+
+	_ = os.Setenv("STARTUP_RESYNC", "true")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.True(conf.StartupResync)
+
+	_ = os.Setenv("STARTUP_RESYNC", "sometimes")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "invalid STARTUP_RESYNC")
+}
+
+func (s *ManagerTestSuite) TestEnablePlugins() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "100")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "2")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Empty(conf.DisabledPlugins)
+	s.True(conf.PluginEnabled(config.PluginExtensions))
+
+	_ = os.Setenv("ENABLE_HARBOR_PLUGIN", "true")
+	_ = os.Setenv("ENABLE_EXTENSIONS_PLUGIN", "false")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal([]string{config.PluginExtensions}, conf.DisabledPlugins)
+	s.True(conf.PluginEnabled(config.PluginHarbor))
+	s.True(conf.PluginEnabled(config.PluginCatalog))
+	s.False(conf.PluginEnabled(config.PluginExtensions))
+
+	_ = os.Setenv("ENABLE_CATALOG_PLUGIN", "maybe")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "invalid ENABLE_CATALOG_PLUGIN")
+
+	// Mirrored artifacts are pushed to Harbor
+	s.setValidEnvironment()
+	_ = os.Setenv("ENABLE_HARBOR_PLUGIN", "false")
+	_, err = config.InitConfigStrict()
+	s.NoError(err)
+	_ = os.Setenv("MIRROR_ARTIFACTS", "edge-orch/en/charts/base-extensions:0.2.0")
+	_, err = config.InitConfigStrict()
+	s.ErrorContains(err, "MIRROR_ARTIFACTS: artifacts are mirrored into Harbor")
+}
+
+func (s *ManagerTestSuite) TestEventQueueSize() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "100")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "2")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Equal(1, conf.EventQueueSize)
+
+	_ = os.Setenv("EVENT_QUEUE_SIZE", "50")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal(50, conf.EventQueueSize)
+
+	_ = os.Setenv("EVENT_QUEUE_SIZE", "0")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "invalid EVENT_QUEUE_SIZE")
+}
+
+func (s *ManagerTestSuite) TestProvisioningSLO() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "100")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "2")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Equal(5*time.Minute, conf.ProvisioningSLO)
+
+	_ = os.Setenv("PROVISIONING_SLO", "0")
+	_ = os.Setenv("SLO_WEBHOOK_URL", "http://alerts")
+	_ = os.Setenv("POD_NAME", "tenant-controller-1")
+	_ = os.Setenv("POD_NAMESPACE", "orch-app")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal(time.Duration(0), conf.ProvisioningSLO)
+	s.Equal("http://alerts", conf.SLOWebhookURL)
+	s.Equal("tenant-controller-1", conf.PodName)
+	s.Equal("orch-app", conf.PodNamespace)
+
+	_ = os.Setenv("PROVISIONING_SLO", "-1")
+	_, err = config.InitConfig()
+	s.Error(err)
+	s.Contains(err.Error(), "invalid PROVISIONING_SLO")
+}
+
 func (s *ManagerTestSuite) TestIntervalLargerThanWait() {
 	s.clearEnvironment()
-	_ = os.Setenv("MAX_WAIT_TIME", "100")
-	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1000")
-	_ = os.Setenv("NUMBER_WORKER_THREADS", "2")
+	_ = os.Setenv("MAX_WAIT_TIME", "100")
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1000")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "2")
+
+	_, err := config.InitConfig()
+	s.Error(err)
+	s.Contains(err.Error(), "must be less than")
+}
+
+// setValidEnvironment sets the environment of a typical deployment
+func (s *ManagerTestSuite) setValidEnvironment() {
+	s.clearEnvironment()
+	_ = os.Setenv("RS_ROOT_URL", "oci://registry-rs.example.com")
+	_ = os.Setenv("RS_PROXY_ROOT_URL", "oci://rs-proxy.rs-proxy.svc.cluster.local:8443")
+	_ = os.Setenv("MANIFEST_PATH", "/edge-orch/en/file/cluster-extension-manifest")
+	_ = os.Setenv("MANIFEST_TAG", "v1.5.11")
+	_ = os.Setenv("REGISTRY_HOST_EXTERNAL", "https://registry-oci.kind.internal")
+	_ = os.Setenv("CATALOG_SERVER", "catalog-service-grpc-server.orch-app.svc.cluster.local:8080")
+	_ = os.Setenv("HARBOR_SERVER", "http://harbor-oci-core.orch-harbor.svc.cluster.local:80")
+	_ = os.Setenv("HARBOR_NAMESPACE", "orch-harbor")
+	_ = os.Setenv("HARBOR_ADMIN_CREDENTIAL", "harbor-admin-credential")
+	_ = os.Setenv("KEYCLOAK_SERVER", "https://localhost:9090")
+	_ = os.Setenv("KEYCLOAK_SERVICE_BASE", "http://platform-keycloak.orch-platform.svc.cluster.local:8080")
+	_ = os.Setenv("VAULT_SERVER", "http://vault.orch-platform.svc.cluster.local:8200")
+	_ = os.Setenv("KEYCLOAK_NAMESPACE", "orch-platform")
+	_ = os.Setenv("KEYCLOAK_SECRET", "platform-keycloak")
+	_ = os.Setenv("ADM_SERVER", "app-deployment-api-grpc-server.orch-app.svc.cluster.local:8080")
+	_ = os.Setenv("RELEASE_SERVICE_BASE", "rs-proxy.rs-proxy.svc.cluster.local:8081")
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "15")
+	_ = os.Setenv("MAX_WAIT_TIME", "600")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "2")
+}
+
+func (s *ManagerTestSuite) TestValidateConfig() {
+	s.setValidEnvironment()
+	conf, err := config.InitConfigStrict()
+	s.NoError(err)
+	report := config.Validate(conf)
+	s.False(report.Failed())
+
+	// Misspelled or malformed settings are reported together
+	_ = os.Unsetenv("CATALOG_SERVER")
+	_ = os.Setenv("HARBOR_SERVER", "harbor-oci-core.orch-harbor.svc.cluster.local")
+	_ = os.Setenv("ADM_SERVER", "http://app-deployment-api-grpc-server:8080")
+	_ = os.Setenv("NEXUS_TIMEOUT", "900")
+	_, err = config.InitConfigStrict()
+	s.Error(err)
+	s.Contains(err.Error(), "CATALOG_SERVER: not set")
+	s.Contains(err.Error(), "HARBOR_SERVER: \"harbor-oci-core.orch-harbor.svc.cluster.local\" is not an absolute URL")
+	s.Contains(err.Error(), "ADM_SERVER: \"http://app-deployment-api-grpc-server:8080\" must be host:port")
+	s.Contains(err.Error(), "NEXUS_TIMEOUT: 15m0s is longer than MAX_WAIT_TIME")
+
+	// The manifest location is not needed with a local manifest
+	s.setValidEnvironment()
+	_ = os.Unsetenv("MANIFEST_PATH")
+	_, err = config.InitConfigStrict()
+	s.ErrorContains(err, "MANIFEST_PATH: not set")
+	_ = os.Setenv("USE_LOCAL_MANIFEST", "metadata: {}")
+	_, err = config.InitConfigStrict()
+	s.NoError(err)
+}
+
+func (s *ManagerTestSuite) TestExternalRegistries() {
+	s.setValidEnvironment()
+	conf, err := config.InitConfigStrict()
+	s.NoError(err)
+	s.Equal("oci://registry-oci.kind.internal", conf.HarborHelmRegistry())
+	s.Equal("oci://registry-oci.kind.internal", conf.HarborDockerRegistry())
+
+	_ = os.Setenv("REGISTRY_HELM_HOST_EXTERNAL", "https://charts.example.com/")
+	_ = os.Setenv("REGISTRY_DOCKER_HOST_EXTERNAL", "oci://images.example.com:8443")
+	conf, err = config.InitConfigStrict()
+	s.NoError(err)
+	s.Equal("oci://charts.example.com", conf.HarborHelmRegistry())
+	s.Equal("oci://images.example.com:8443", conf.HarborDockerRegistry())
+
+	_ = os.Setenv("REGISTRY_HELM_HOST_EXTERNAL", "harbor-oci-core.orch-harbor.svc.cluster.local")
+	_ = os.Setenv("REGISTRY_DOCKER_HOST_EXTERNAL", "oci://images.example.com/catalog-apps")
+	_, err = config.InitConfigStrict()
+	s.ErrorContains(err, "REGISTRY_HELM_HOST_EXTERNAL: \"harbor-oci-core.orch-harbor.svc.cluster.local\" is not an oci:// or https:// URL")
+	s.ErrorContains(err, "REGISTRY_DOCKER_HOST_EXTERNAL: \"oci://images.example.com/catalog-apps\" must not have a path")
+}
+
+func (s *ManagerTestSuite) TestReleaseServiceRegistries() {
+	s.setValidEnvironment()
+	conf, err := config.InitConfigStrict()
+	s.NoError(err)
+	s.Equal("oci://rs-proxy.rs-proxy.svc.cluster.local:8443", conf.ReleaseServiceHelmRegistry())
+	s.Equal("oci://registry-rs.example.com", conf.ReleaseServiceImageRegistry())
+
+	_ = os.Setenv("RS_HELM_ROOT_URL", "oci://charts.example.com/")
+	_ = os.Setenv("RS_IMAGE_ROOT_URL", "oci://images.example.com:8443")
+	_ = os.Setenv("RS_HELM_PATH_PREFIX", "edge-orch/charts")
+	_ = os.Setenv("RS_IMAGE_PATH_PREFIX", "/edge-orch/images/")
+	conf, err = config.InitConfigStrict()
+	s.NoError(err)
+	s.Equal("oci://charts.example.com/edge-orch/charts", conf.ReleaseServiceHelmRegistry())
+	s.Equal("oci://images.example.com:8443/edge-orch/images", conf.ReleaseServiceImageRegistry())
+
+	_ = os.Setenv("RS_ROOT_URL", "https://registry-rs.example.com")
+	_ = os.Setenv("RS_HELM_ROOT_URL", "charts.example.com")
+	_ = os.Setenv("RS_IMAGE_PATH_PREFIX", "oci://images.example.com")
+	_, err = config.InitConfigStrict()
+	s.ErrorContains(err, "RS_ROOT_URL: \"https://registry-rs.example.com\" is not an oci:// URL with a host")
+	s.ErrorContains(err, "RS_HELM_ROOT_URL: \"charts.example.com\" is not an oci:// URL with a host")
+	s.ErrorContains(err, "RS_IMAGE_PATH_PREFIX: \"oci://images.example.com\" is not a registry path")
+}
+
+func (s *ManagerTestSuite) TestValidateConfigEnvironment() {
+	s.setValidEnvironment()
+	conf, err := config.InitConfig()
+	s.NoError(err)
+
+	report := config.ValidationReport{}
+	report.CheckEnvironment(context.Background(), conf, config.EnvironmentChecks{
+		LookupHost: func(_ context.Context, host string) ([]string, error) {
+			if host == "vault.orch-platform.svc.cluster.local" {
+				return nil, fmt.Errorf("no such host")
+			}
+			return []string{"10.0.0.1"}, nil
+		},
+		ReadSecret: func(_ context.Context, namespace string, name string) (map[string][]byte, error) {
+			if namespace == "orch-harbor" && name == "harbor-admin-credential" {
+				return map[string][]byte{"credential": []byte("admin:secret")}, nil
+			}
+			return map[string][]byte{"password": []byte("secret")}, nil
+		},
+	})
+	s.True(report.Failed())
+	s.EqualError(report.Err(), "invalid configuration: "+
+		"VAULT_SERVER: vault.orch-platform.svc.cluster.local does not resolve: no such host; "+
+		"KEYCLOAK_SECRET: secret orch-platform/platform-keycloak has no admin-password key")
+
+	out := &strings.Builder{}
+	report.Print(out)
+	s.Regexp(`PASS +KEYCLOAK_SERVER +dns +localhost resolves`, out.String())
+	s.Regexp(`FAIL +VAULT_SERVER +dns +vault.orch-platform.svc.cluster.local does not resolve: no such host`, out.String())
+}
+
+func (s *ManagerTestSuite) TestSecretRefs() {
+	s.setValidEnvironment()
+	_ = os.Setenv("HARBOR_ADMIN_CREDENTIAL_KEY", "harbor-credential")
+	conf, err := config.InitConfigStrict()
+	s.NoError(err)
+	s.Equal(config.SecretRef{Namespace: "orch-harbor", Name: "harbor-admin-credential", Key: "harbor-credential"}, conf.Secrets.HarborAdmin)
+	s.False(conf.Secrets.HarborAdmin.Mounted())
+
+	// A mounted secret needs no namespace or name, but its file must exist
+	mount := s.T().TempDir()
+	_ = os.Setenv("KEYCLOAK_SECRET_PATH", mount)
+	_ = os.Unsetenv("KEYCLOAK_NAMESPACE")
+	_ = os.Unsetenv("KEYCLOAK_SECRET")
+	_, err = config.InitConfigStrict()
+	s.ErrorContains(err, "KEYCLOAK_SECRET: mounted secret "+mount+"/admin-password is not readable")
+	s.NoError(os.WriteFile(filepath.Join(mount, "admin-password"), []byte("secret"), 0600))
+	conf, err = config.InitConfigStrict()
+	s.NoError(err)
+	s.Equal(filepath.Join(mount, "admin-password"), conf.Secrets.KeycloakAdmin.File())
+
+	// Mounted secrets are not looked up through the Kubernetes API
+	report := config.ValidationReport{}
+	report.CheckEnvironment(context.Background(), conf, config.EnvironmentChecks{
+		LookupHost: func(_ context.Context, _ string) ([]string, error) { return []string{"10.0.0.1"}, nil },
+		ReadSecret: func(_ context.Context, namespace string, name string) (map[string][]byte, error) {
+			s.Equal("orch-harbor/harbor-admin-credential", namespace+"/"+name)
+			return map[string][]byte{"harbor-credential": []byte("admin:secret")}, nil
+		},
+	})
+	s.NoError(report.Err())
+
+	_ = os.Setenv("KEYCLOAK_SECRET_PATH", "secrets/keycloak")
+	_, err = config.InitConfigStrict()
+	s.ErrorContains(err, "KEYCLOAK_SECRET: mount path \"secrets/keycloak\" is not absolute")
+
+	// Secrets read through the API need a namespace and name
+	_ = os.Unsetenv("KEYCLOAK_SECRET_PATH")
+	_, err = config.InitConfigStrict()
+	s.ErrorContains(err, "KEYCLOAK_NAMESPACE: not set")
+	s.ErrorContains(err, "KEYCLOAK_SECRET: not set")
+}
+
+func (s *ManagerTestSuite) TestProvisioningProfiles() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "10")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "2")
+	_ = os.Setenv("PROVISIONING_PROFILES", `
+default: basic
+orgs:
+  acme: premium
+profiles:
+  basic:
+    harborStorageLimit: 1073741824
+    deploymentPackages: [base-extensions]
+    deploymentProfiles: [baseline]
+  premium:
+    harborSigning: [cosign]
+`)
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Len(conf.ProvisioningProfiles.Profiles, 2)
+
+	m := NewManager(conf)
+
+	// default applies when there is no org mapping or project annotation
+	profile := m.selectProfile("other", nil)
+	s.NotNil(profile)
+	s.Equal("basic", profile.Name)
+	s.Equal(int64(1073741824), profile.HarborStorageLimit)
+	s.True(profile.AllowsDeploymentPackage("base-extensions"))
+	s.False(profile.AllowsDeploymentPackage("intel-gpu"))
+	s.False(profile.AllowsDeploymentProfile("privileged"))
+
+	// org mapping takes precedence over the default
+	profile = m.selectProfile("acme", nil)
+	s.Equal("premium", profile.Name)
+	s.True(profile.AllowsDeploymentPackage("intel-gpu"))
+	s.True(profile.RequiresSignature(config.HarborSigningCosign))
+	s.False(profile.RequiresSignature(config.HarborSigningNotation))
+	s.False(conf.ProvisioningProfiles.Select("basic", "acme").RequiresSignature(config.HarborSigningCosign))
+
+	// project annotation takes precedence over the org mapping, unknown profiles are ignored
+	s.Equal("basic", conf.ProvisioningProfiles.Select("basic", "acme").Name)
+	s.Equal("premium", conf.ProvisioningProfiles.Select("gold", "acme").Name)
+
+	// undefined profile names are rejected
+	_ = os.Setenv("PROVISIONING_PROFILES", "default: gold\n")
+	_, err = config.InitConfig()
+	s.Error(err)
+	s.Contains(err.Error(), "default profile gold is not defined")
+
+	_ = os.Setenv("PROVISIONING_PROFILES", "profiles:\n  premium:\n    harborSigning: [notary]\n")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, `harborSigning "notary" of profile premium must be cosign or notation`)
+}
+
+// testProject provides the labels and annotations of a project; other methods are not used
+type testProject struct {
+	nexushook.NexusProjectInterface
+	labels      map[string]string
+	annotations map[string]string
+}
+
+func (p *testProject) GetLabels() map[string]string {
+	return p.labels
+}
+
+func (p *testProject) GetAnnotations() map[string]string {
+	return p.annotations
+}
+
+func (s *ManagerTestSuite) TestHarborGroups() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Equal(config.DefaultKeycloakRealm, conf.HarborGroups.Realm("acme"))
+	name, err := conf.HarborGroups.GroupName("acme", "proj", "uuid-1", "Operator")
+	s.NoError(err)
+	s.Equal("uuid-1_Edge-Operator-Group", name)
+
+	_ = os.Setenv("HARBOR_GROUPS", `
+defaultRealm: edge
+orgs:
+  acme: acme
+realms:
+  acme:
+    nameTemplate: "{{.Organization}}-{{.ProjectUUID}}-{{.Role}}"
+`)
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal("edge", conf.HarborGroups.Realm("globex"))
+	s.Equal("acme", conf.HarborGroups.Realm("acme"))
+	name, err = conf.HarborGroups.GroupName("acme", "proj", "uuid-1", "Manager")
+	s.NoError(err)
+	s.Equal("acme-uuid-1-Manager", name)
+	name, err = conf.HarborGroups.GroupName("globex", "proj", "uuid-2", "Manager")
+	s.NoError(err)
+	s.Equal("uuid-2_Edge-Manager-Group", name)
+	s.Equal(config.DefaultHarborGroupRoles, conf.HarborGroups.GroupRoles())
+
+	_ = os.Setenv("HARBOR_GROUPS", `
+roles:
+  - role: Operator
+    roleID: 2
+  - role: Viewer
+    roleID: 5
+    nameTemplate: "{{.Organization}}-viewers"
+`)
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal([]config.HarborGroupRole{
+		{Role: "Operator", RoleID: config.HarborRoleDeveloper},
+		{Role: "Viewer", RoleID: config.HarborRoleLimitedGuest, NameTemplate: "{{.Organization}}-viewers"},
+	}, conf.HarborGroups.GroupRoles())
+	name, err = conf.HarborGroups.GroupName("acme", "proj", "uuid-1", "Viewer")
+	s.NoError(err)
+	s.Equal("acme-viewers", name)
+
+	for value, message := range map[string]string{
+		"realms: {acme: {nameTemplate: \"{{.Tenant}}\"}}":              "invalid group name template of realm acme",
+		"realms: {acme: {nameTemplate: \"{{\"}}":                       "invalid group name template of realm acme",
+		"realms: {acme: {nameTemplate: \" \"}}":                        "the group name is empty",
+		"orgs: {acme: \"\"}":                                           "organization acme has no realm",
+		"unknown: true":                                                "invalid HARBOR_GROUPS",
+		"roles: [{roleID: 3}]":                                         "a role has no name",
+		"roles: [{role: Viewer}]":                                      "role Viewer has no Harbor roleID",
+		"roles: [{role: A, roleID: 3}, {role: A, roleID: 4}]":          "role A is listed more than once",
+		"roles: [{role: A, roleID: 3, nameTemplate: \"{{.Tenant}}\"}]": "invalid group name template of role A",
+	} {
+		_ = os.Setenv("HARBOR_GROUPS", value)
+		_, err = config.InitConfig()
+		s.ErrorContains(err, message, value)
+	}
+}
+
+func (s *ManagerTestSuite) TestHarborRobotPermissions() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Equal(config.DefaultHarborReadWriteAccess, conf.HarborRobotPermissions.ReadWriteAccess())
+	s.Equal(config.DefaultHarborPullAccess, conf.HarborRobotPermissions.PullAccess())
+
+	// Only the read-write robot is tightened, the pull robot keeps its defaults
+	_ = os.Setenv("HARBOR_ROBOT_PERMISSIONS", `
+readWrite:
+  - resource: repository
+    actions: [list, pull, push]
+  - resource: artifact
+    actions: [read, list]
+`)
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal([]config.HarborRobotAccess{
+		{Resource: "repository", Actions: []string{"list", "pull", "push"}},
+		{Resource: "artifact", Actions: []string{"read", "list"}},
+	}, conf.HarborRobotPermissions.ReadWriteAccess())
+	s.Equal(config.DefaultHarborPullAccess, conf.HarborRobotPermissions.PullAccess())
+	s.Equal("readWrite [repository:list,pull,push artifact:read,list] pull [repository:list,pull artifact:read,list tag:list]",
+		conf.HarborRobotPermissions.String())
+
+	for value, message := range map[string]string{
+		"unknown: true":                            "invalid HARBOR_ROBOT_PERMISSIONS",
+		"readWrite: [{actions: [pull]}]":           "readWrite robot: a resource has no name",
+		"pull: [{resource: tag}]":                  "pull robot: resource tag has no actions",
+		"pull: [{resource: tag, actions: [\"\"]}]": "pull robot: resource tag has an empty action",
+		"pull: [{resource: tag, actions: [list]}, {resource: tag, actions: [read]}]": "resource tag is listed more than once",
+	} {
+		_ = os.Setenv("HARBOR_ROBOT_PERMISSIONS", value)
+		_, err = config.InitConfig()
+		s.ErrorContains(err, message, value)
+	}
+}
+
+func (s *ManagerTestSuite) TestHarborRobots() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Equal(config.DefaultHarborRobots, conf.HarborRobots)
+
+	// A CI robot that pushes next to the catalog robots
+	_ = os.Setenv("HARBOR_ROBOTS", `
+- {name: catalog-apps-read-write, permissions: readWrite, catalog: true}
+- {name: catalog-apps-read-only, permissions: pull, catalog: true}
+- {name: ci-push, permissions: readWrite}
+`)
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Len(conf.HarborRobots, 3)
+	s.Equal(config.HarborRobot{Name: "ci-push", Permissions: config.RobotProfileReadWrite}, conf.HarborRobots[2])
+	s.Equal("catalog-apps-read-write:readWrite:catalog catalog-apps-read-only:pull:catalog ci-push:readWrite",
+		config.FormatRobots(conf.HarborRobots))
+	robot, ok := config.CatalogRobot(conf.HarborRobots, config.RobotProfilePull)
+	s.True(ok)
+	s.Equal("catalog-apps-read-only", robot.Name)
+
+	for value, message := range map[string]string{
+		"unknown: true":                    "invalid HARBOR_ROBOTS",
+		"[]":                               "no robots are listed",
+		"[{name: CI, permissions: pull}]":  "invalid robot name \"CI\"",
+		"[{name: ci, permissions: admin}]": "robot ci has invalid permissions \"admin\"",
+		"[{name: ci, permissions: pull}, {name: ci, permissions: readWrite}]":                        "robot ci is listed more than once",
+		"[{name: a, permissions: pull, catalog: true}, {name: b, permissions: pull, catalog: true}]": "more than one pull robot is given to the catalog",
+	} {
+		_ = os.Setenv("HARBOR_ROBOTS", value)
+		_, err = config.InitConfig()
+		s.ErrorContains(err, message, value)
+	}
+}
+
+func (s *ManagerTestSuite) TestHarborLabels() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Equal(config.DefaultHarborLabels, conf.HarborLabels)
+	s.Equal("extension customer-app", config.FormatHarborLabels(conf.HarborLabels))
+
+	_ = os.Setenv("HARBOR_LABELS", `
+- name: extension
+  description: Orchestrator extension
+  color: "#FF0000"
+- name: certified
+`)
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal([]config.HarborLabel{
+		{Name: "extension", Description: "Orchestrator extension", Color: "#FF0000"},
+		{Name: "certified"},
+	}, conf.HarborLabels)
+
+	// An empty list creates no labels
+	_ = os.Setenv("HARBOR_LABELS", "[]")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Empty(conf.HarborLabels)
+
+	for value, message := range map[string]string{
+		"name: extension":                  "invalid HARBOR_LABELS",
+		"[{description: x}]":               "a label has no name",
+		"[{name: a}, {name: a}]":           "label a is listed more than once",
+		"[{name: a, color: red}]":          `label a color "red" is not a #rrggbb value`,
+		"[{name: a, colour: \"#FF0000\"}]": "invalid HARBOR_LABELS",
+	} {
+		_ = os.Setenv("HARBOR_LABELS", value)
+		_, err = config.InitConfig()
+		s.ErrorContains(err, message, value)
+	}
+}
+
+func (s *ManagerTestSuite) TestDeploymentLabelKeys() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Empty(conf.DeploymentLabelKeys)
+
+	_ = os.Setenv("DEPLOYMENT_LABEL_KEYS", "region, app.example.com/zone,")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal([]string{"region", "app.example.com/zone"}, conf.DeploymentLabelKeys)
+
+	manager := NewManager(conf)
+	project := &testProject{
+		labels:      map[string]string{"region": "eu", "team": "blue", "app.example.com/zone": "a"},
+		annotations: map[string]string{"app.example.com/zone": "b"},
+	}
+	s.Equal(map[string]string{"region": "eu", "app.example.com/zone": "b"}, manager.deploymentLabels(events.ProjectFromNexus("org", "project", "uuid", project)))
+	s.Nil(manager.deploymentLabels(events.ProjectFromNexus("org", "project", "uuid", nil)))
+}
+
+func (s *ManagerTestSuite) TestDeploymentTenantLabels() {
+	s.clearEnvironment()
+	defer s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
+	_ = os.Setenv("DEPLOYMENT_LABEL_KEYS", "region,"+plugins.DeploymentProjectUUIDLabel)
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.False(conf.DeploymentTenantLabels)
+
+	_ = os.Setenv("DEPLOYMENT_TENANT_LABELS", "maybe")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "DEPLOYMENT_TENANT_LABELS")
+
+	_ = os.Setenv("DEPLOYMENT_TENANT_LABELS", "true")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.True(conf.DeploymentTenantLabels)
+
+	// The tenant labels are added to every project, and cannot be overridden by project labels
+	manager := NewManager(conf)
+	project := &testProject{labels: map[string]string{"region": "eu", plugins.DeploymentProjectUUIDLabel: "other"}}
+	s.Equal(map[string]string{
+		"region":                            "eu",
+		plugins.DeploymentOrganizationLabel: "org",
+		plugins.DeploymentProjectUUIDLabel:  "uuid",
+	}, manager.deploymentLabels(events.ProjectFromNexus("org", "project", "uuid", project)))
+	s.Equal(map[string]string{
+		plugins.DeploymentOrganizationLabel: "org",
+		plugins.DeploymentProjectUUIDLabel:  "uuid",
+	}, manager.deploymentLabels(events.ProjectFromNexus("org", "project", "uuid", nil)))
+
+	// An organization that is not a valid label value is left out
+	s.Equal(map[string]string{plugins.DeploymentProjectUUIDLabel: "uuid"},
+		manager.deploymentLabels(events.ProjectFromNexus("Org Name", "project", "uuid", nil)))
+}
+
+func (s *ManagerTestSuite) TestPluginEvent() {
+	manager := NewManager(config.Configuration{})
+	project := &testProject{annotations: map[string]string{nexushook.RetainDataAnnotationKey: "true"}}
+
+	e, err := manager.pluginEvent(events.CreateProjectV1{Project: events.ProjectFromNexus("org", "name", "uuid", project)})
+	s.NoError(err)
+	s.Equal("create", e.EventType)
+	s.Equal("uuid", e.UUID)
+	s.Equal(project, e.Project)
+	s.False(e.RefreshCredentials)
+
+	e, err = manager.pluginEvent(events.NewDeleteProjectV1(events.ProjectFromNexus("org", "name", "uuid", project)))
+	s.NoError(err)
+	s.Equal("delete", e.EventType)
+	s.True(e.RetainData)
+
+	e, err = manager.pluginEvent(events.UpdateManifestV1{Project: events.ProjectV1{Organization: "org", Name: "name", UUID: "uuid"}, RefreshCredentials: true})
+	s.NoError(err)
+	s.Equal("create", e.EventType)
+	s.Nil(e.Project)
+	s.True(e.RefreshCredentials)
+
+	e, err = manager.pluginEvent(events.EnsureProjectV1{Project: events.ProjectFromNexus("org", "name", "uuid", project)})
+	s.NoError(err)
+	s.Equal("ensure", e.EventType)
+	s.Equal(project, e.Project)
+	s.False(e.RefreshCredentials)
+
+	_, err = manager.pluginEvent(futureProjectEvent{})
+	s.ErrorIs(err, southbound.ErrPermanent)
+}
+
+// futureProjectEvent is an event of a schema version the manager does not know
+type futureProjectEvent struct {
+	events.CreateProjectV1
+}
+
+func (futureProjectEvent) SchemaVersion() string { return "v2" }
+
+func (s *ManagerTestSuite) TestMirrorArtifacts() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Empty(conf.MirrorArtifacts)
+
+	_ = os.Setenv("MIRROR_ARTIFACTS", "edge-orch/en/charts/base-extensions:0.2.0, /edge-orch/en/images/agent:1.0,")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal([]config.MirrorArtifact{
+		{Repository: "edge-orch/en/charts/base-extensions", Tag: "0.2.0"},
+		{Repository: "edge-orch/en/images/agent", Tag: "1.0"},
+	}, conf.MirrorArtifacts)
+
+	for _, invalid := range []string{"edge-orch/en/images/agent", "registry:5000/agent", "agent:", ":1.0"} {
+		_ = os.Setenv("MIRROR_ARTIFACTS", invalid)
+		_, err = config.InitConfig()
+		s.ErrorContains(err, "invalid MIRROR_ARTIFACTS entry", invalid)
+	}
+}
+
+func (s *ManagerTestSuite) TestGitOps() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.False(conf.GitOps.Enabled())
+
+	_ = os.Setenv("GITOPS_PROVIDER", "gitlab")
+	_ = os.Setenv("GITOPS_SERVER", "https://gitlab.example.com")
+	_ = os.Setenv("GITOPS_OWNER", "tenants")
+	_ = os.Setenv("GITOPS_NAMESPACE", "orch-app")
+	_ = os.Setenv("GITOPS_TOKEN_SECRET", "gitops-token")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "GITOPS_PROVIDER requires POD_NAMESPACE")
+
+	_ = os.Setenv("POD_NAMESPACE", "orch-app")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.True(conf.GitOps.Enabled())
+	s.Equal(config.GitOps{
+		Provider:          config.GitOpsProviderGitLab,
+		Server:            "https://gitlab.example.com",
+		Owner:             "tenants",
+		Token:             config.SecretRef{Namespace: "orch-app", Name: "gitops-token", Key: "token"},
+		DeployKeyReadOnly: true,
+	}, conf.GitOps)
+
+	_ = os.Setenv("GITOPS_DEPLOY_KEY_READ_ONLY", "false")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.False(conf.GitOps.DeployKeyReadOnly)
+
+	for env, invalid := range map[string]string{
+		"GITOPS_PROVIDER":             "github",
+		"GITOPS_SERVER":               "gitlab.example.com",
+		"GITOPS_OWNER":                "",
+		"GITOPS_TOKEN_SECRET":         "",
+		"GITOPS_DEPLOY_KEY_READ_ONLY": "maybe",
+	} {
+		previous := os.Getenv(env)
+		_ = os.Setenv(env, invalid)
+		_, err = config.InitConfig()
+		s.ErrorContains(err, env, invalid)
+		_ = os.Setenv(env, previous)
+	}
+}
+
+func (s *ManagerTestSuite) TestTenantNamespaces() {
+	s.clearEnvironment()
+	defer s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.False(conf.TenantNamespaces.Enabled)
+
+	_ = os.Setenv("TENANT_NAMESPACES", "true")
+	_ = os.Setenv("TENANT_NAMESPACE_PREFIX", "tenant-")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal(config.TenantNamespaces{Enabled: true, Prefix: "tenant-"}, conf.TenantNamespaces)
+
+	for env, invalid := range map[string]string{
+		"TENANT_NAMESPACES":       "maybe",
+		"TENANT_NAMESPACE_PREFIX": "Tenant_",
+	} {
+		previous := os.Getenv(env)
+		_ = os.Setenv(env, invalid)
+		_, err = config.InitConfig()
+		s.ErrorContains(err, env, invalid)
+		_ = os.Setenv(env, previous)
+	}
+	_ = os.Setenv("TENANT_NAMESPACE_PREFIX", "a-very-long-namespace-prefix-")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "TENANT_NAMESPACE_PREFIX")
+}
+
+func (s *ManagerTestSuite) TestMissingOrganizationPolicy() {
+	s.clearEnvironment()
+	defer s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Equal(config.MissingOrganizationReject, conf.MissingOrganizationPolicy)
+	s.Empty(conf.FallbackOrganization)
+
+	_ = os.Setenv("MISSING_ORGANIZATION_POLICY", "fallback")
+	_ = os.Setenv("FALLBACK_ORGANIZATION", "unassigned")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal(config.MissingOrganizationFallback, conf.MissingOrganizationPolicy)
+	s.Equal("unassigned", conf.FallbackOrganization)
+
+	// The fallback organization must be usable in the Harbor project and namespace names
+	for _, invalid := range []string{"", " ", "Unassigned", "org-", strings.Repeat("o", 64)} {
+		_ = os.Setenv("FALLBACK_ORGANIZATION", invalid)
+		_, err = config.InitConfig()
+		s.ErrorContains(err, "FALLBACK_ORGANIZATION", invalid)
+	}
+
+	// A fallback organization without the fallback policy is a mistake
+	_ = os.Setenv("MISSING_ORGANIZATION_POLICY", "reject")
+	_ = os.Setenv("FALLBACK_ORGANIZATION", "unassigned")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "FALLBACK_ORGANIZATION")
+
+	_ = os.Setenv("MISSING_ORGANIZATION_POLICY", "ignore")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "MISSING_ORGANIZATION_POLICY")
+}
+
+func (s *ManagerTestSuite) TestDeletionModeConfig() {
+	s.clearEnvironment()
+	defer s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Equal(config.DeletionModeHard, conf.DeletionMode)
+	s.Equal(config.DefaultDeletionRetention, conf.DeletionRetention)
+
+	// The soft mode records the deactivated projects in the controller namespace
+	_ = os.Setenv("DELETION_MODE", "soft")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "POD_NAMESPACE")
+	_ = os.Setenv("POD_NAMESPACE", "orch-app")
+	_ = os.Setenv("DELETION_RETENTION", "3600")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal(config.DeletionModeSoft, conf.DeletionMode)
+	s.Equal(time.Hour, conf.DeletionRetention)
+
+	for _, invalid := range []string{"0", "-1", "1h"} {
+		_ = os.Setenv("DELETION_RETENTION", invalid)
+		_, err = config.InitConfig()
+		s.ErrorContains(err, "DELETION_RETENTION", invalid)
+	}
+
+	_ = os.Setenv("DELETION_RETENTION", "3600")
+	_ = os.Setenv("DELETION_MODE", "archive")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "DELETION_MODE")
+}
+
+func (s *ManagerTestSuite) TestCatalogRegistryAffixConfig() {
+	s.clearEnvironment()
+	defer s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Empty(conf.CatalogRegistryPrefix)
+	s.Empty(conf.CatalogRegistrySuffix)
+
+	_ = os.Setenv("CATALOG_REGISTRY_PREFIX", "staging-")
+	_ = os.Setenv("CATALOG_REGISTRY_SUFFIX", "-eu1")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal("staging-", conf.CatalogRegistryPrefix)
+	s.Equal("-eu1", conf.CatalogRegistrySuffix)
+
+	_ = os.Setenv("CATALOG_REGISTRY_PREFIX", "Staging_")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "CATALOG_REGISTRY_PREFIX")
+	_ = os.Setenv("CATALOG_REGISTRY_PREFIX", "")
+	_ = os.Setenv("CATALOG_REGISTRY_SUFFIX", "/eu1")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "CATALOG_REGISTRY_SUFFIX")
+}
+
+func (s *ManagerTestSuite) TestReleaseServiceAccess() {
+	s.clearEnvironment()
+	defer s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.True(conf.ReleaseServiceAccess.Anonymous())
+	s.False(conf.ReleaseServiceAccess.TLS)
+
+	_ = os.Setenv("RS_CREDENTIAL_SECRET", "rs-credential")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "RS_CREDENTIAL_NAMESPACE is required")
+
+	_ = os.Setenv("RS_CREDENTIAL_NAMESPACE", "orch-app")
+	_ = os.Setenv("RS_TLS", "true")
+	_ = os.Setenv("RS_TLS_INSECURE_SKIP_VERIFY", "true")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal(config.ReleaseServiceAccess{
+		Credential:         config.SecretRef{Namespace: "orch-app", Name: "rs-credential", Key: "credential"},
+		TLS:                true,
+		InsecureSkipVerify: true,
+	}, conf.ReleaseServiceAccess)
+	s.False(conf.ReleaseServiceAccess.Anonymous())
+
+	_ = os.Setenv("RS_CREDENTIAL_PATH", "/vault/secrets")
+	_ = os.Setenv("RS_CREDENTIAL_KEY", "release-service")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal("/vault/secrets/release-service", conf.ReleaseServiceAccess.Credential.File())
+
+	_ = os.Setenv("RS_CA_CERTS", "not a certificate")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "invalid RS_CA_CERTS")
+
+	_ = os.Unsetenv("RS_CA_CERTS")
+	_ = os.Setenv("RS_TLS", "false")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "require RS_TLS")
+
+	_ = os.Setenv("RS_TLS", "maybe")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "invalid RS_TLS")
+}
+
+func (s *ManagerTestSuite) TestConfigProfiles() {
+	s.clearEnvironment()
+	_ = os.Unsetenv("NUMBER_WORKER_THREADS")
+	defer s.clearEnvironment()
+
+	// Without a profile, the unset variables have no value
+	conf, err := config.InitConfig()
+	s.Error(err)
+
+	// The profile fills in the variables that are not set
+	_ = os.Setenv("CONFIG_PROFILE", config.ConfigProfileKind)
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal(config.ConfigProfileKind, conf.Profile)
+	s.Equal("catalog-service-grpc-server.orch-app.svc.cluster.local:8080", conf.CatalogServer)
+	s.Equal("https://registry-oci.kind.internal", conf.HarborServerExternal)
+	s.Equal(config.SecretRef{Namespace: "orch-harbor", Name: "harbor-admin-credential", Key: "credential"}, conf.Secrets.HarborAdmin)
+	s.Equal(time.Second, conf.InitialSleepInterval)
+	s.Equal(30*time.Second, conf.MaxWaitTime)
+	s.Equal(1, conf.NumberWorkerThreads)
+	s.Equal(30*time.Second, conf.HarborRequestTimeout)
+
+	_ = os.Setenv("CONFIG_PROFILE", config.ConfigProfileProd)
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal(15*time.Second, conf.InitialSleepInterval)
+	s.Equal(600*time.Second, conf.MaxWaitTime)
+	s.Equal(2, conf.NumberWorkerThreads)
+	s.Empty(conf.HarborServerExternal)
+
+	// Variables that are set override the profile, even when empty
+	_ = os.Setenv("CATALOG_SERVER", "catalog:8080")
+	_ = os.Setenv("MAX_WAIT_TIME", "60")
+	_ = os.Setenv("RS_PROXY_ROOT_URL", "")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal("catalog:8080", conf.CatalogServer)
+	s.Equal(60*time.Second, conf.MaxWaitTime)
+	s.Empty(conf.ReleaseServiceProxyRootURL)
+	s.Equal("oci://registry-rs.edgeorchestration.intel.com", conf.ReleaseServiceRootURL)
+
+	_ = os.Setenv("CONFIG_PROFILE", "staging")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, `invalid CONFIG_PROFILE value "staging": must be one of kind, prod`)
+}
+
+func (s *ManagerTestSuite) TestEventSources() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Equal([]string{config.EventSourceNexus}, conf.EventSources)
+	s.Equal(":8090", conf.CloudEventsAddress)
+	s.False(conf.EventSourceEnabled(config.EventSourceCloudEvents))
+
+	_ = os.Setenv("EVENT_SOURCES", "cloudevents, nexus,cloudevents")
+	_ = os.Setenv("CLOUDEVENTS_ADDRESS", "127.0.0.1:9000")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "CLOUDEVENTS_TOKEN_SECRET or CLOUDEVENTS_TOKEN_PATH is required")
+
+	_ = os.Setenv("CLOUDEVENTS_TOKEN_SECRET", "cloudevents-token")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "CLOUDEVENTS_TOKEN_NAMESPACE is required with CLOUDEVENTS_TOKEN_SECRET")
+
+	_ = os.Setenv("CLOUDEVENTS_TOKEN_NAMESPACE", "orch-app")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal([]string{config.EventSourceCloudEvents, config.EventSourceNexus}, conf.EventSources)
+	s.Equal("127.0.0.1:9000", conf.CloudEventsAddress)
+	s.True(conf.EventSourceEnabled(config.EventSourceCloudEvents))
+	s.Equal(config.SecretRef{Namespace: "orch-app", Name: "cloudevents-token", Key: config.DefaultTokenKey}, conf.CloudEventsToken)
+
+	_ = os.Unsetenv("CLOUDEVENTS_TOKEN_NAMESPACE")
+	_ = os.Unsetenv("CLOUDEVENTS_TOKEN_SECRET")
+	_ = os.Setenv("CLOUDEVENTS_TOKEN_PATH", "/var/run/secrets/cloudevents")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal(config.SecretRef{Key: config.DefaultTokenKey, MountPath: "/var/run/secrets/cloudevents"}, conf.CloudEventsToken)
+
+	for _, invalid := range []string{"kafka", " , "} {
+		_ = os.Setenv("EVENT_SOURCES", invalid)
+		_, err = config.InitConfig()
+		s.ErrorContains(err, "invalid EVENT_SOURCES", invalid)
+	}
+}
+
+func (s *ManagerTestSuite) TestHistorySize() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Equal(50, conf.HistorySize)
+	s.Equal(":8091", conf.HistoryAPIAddress)
+
+	_ = os.Setenv("HISTORY_SIZE", "0")
+	_ = os.Setenv("HISTORY_API_ADDRESS", "127.0.0.1:9001")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal(0, conf.HistorySize)
+	s.Equal("127.0.0.1:9001", conf.HistoryAPIAddress)
+
+	_ = os.Setenv("HISTORY_SIZE", "-1")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "invalid HISTORY_SIZE")
+}
+
+func (s *ManagerTestSuite) TestAdminAPI() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
+
+	// The admin API is off by default
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Empty(conf.AdminAPIAddress)
+
+	_ = os.Setenv("ADMIN_API_ADDRESS", ":8092")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "ADMIN_TOKEN_SECRET or ADMIN_TOKEN_PATH is required")
+
+	_ = os.Setenv("ADMIN_TOKEN_SECRET", "admin-token")
+	_ = os.Setenv("ADMIN_TOKEN_NAMESPACE", "orch-app")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal(":8092", conf.AdminAPIAddress)
+	s.Equal(config.SecretRef{Namespace: "orch-app", Name: "admin-token", Key: config.DefaultTokenKey}, conf.AdminToken)
+
+	_ = os.Setenv("ADMIN_API_ADDRESS", ":8091")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "must differ from HISTORY_API_ADDRESS")
+}
+
+func (s *ManagerTestSuite) TestDebugQueries() {
+	s.clearEnvironment()
+	defer s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.False(conf.DebugQueries)
+	s.Nil(NewManager(conf).debugQueries())
+
+	_ = os.Setenv("DEBUG_QUERIES", "true")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.True(conf.DebugQueries)
+	// They are admin calls, not served without the admin API
+	s.Nil(NewManager(conf).debugQueries())
+	conf.AdminAPIAddress = ":8092"
+	s.Contains(NewManager(conf).debugQueries(), "registries")
+	s.NotContains(NewManager(conf).debugQueries(), "deployments")
+	conf.AdmServer = "adm:8080"
+	s.Contains(NewManager(conf).debugQueries(), "deployments")
+
+	_ = os.Setenv("DEBUG_QUERIES", "maybe")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "invalid DEBUG_QUERIES")
+}
+
+func (s *ManagerTestSuite) TestProjectQuotas() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
+
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Equal(20, conf.MaxCatalogRegistries)
+	s.Equal(50, conf.MaxExtensionDeployments)
+	s.Equal(int64(config.DefaultMaxCatalogArtifactSize), conf.MaxCatalogArtifactSize)
+
+	_ = os.Setenv("MAX_CATALOG_REGISTRIES", "0")
+	_ = os.Setenv("MAX_EXTENSION_DEPLOYMENTS", "5")
+	_ = os.Setenv("MAX_CATALOG_ARTIFACT_SIZE", "1048576")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal(0, conf.MaxCatalogRegistries)
+	s.Equal(5, conf.MaxExtensionDeployments)
+	s.Equal(int64(1048576), conf.MaxCatalogArtifactSize)
+
+	_ = os.Setenv("MAX_CATALOG_REGISTRIES", "-1")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "invalid MAX_CATALOG_REGISTRIES")
+
+	_ = os.Setenv("MAX_CATALOG_REGISTRIES", "")
+	_ = os.Setenv("MAX_EXTENSION_DEPLOYMENTS", "many")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "invalid MAX_EXTENSION_DEPLOYMENTS")
+
+	_ = os.Setenv("MAX_EXTENSION_DEPLOYMENTS", "")
+	_ = os.Setenv("MAX_CATALOG_ARTIFACT_SIZE", "-1")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "invalid MAX_CATALOG_ARTIFACT_SIZE")
+}
+
+func (s *ManagerTestSuite) TestPlatformCapacity() {
+	s.clearEnvironment()
+	_ = os.Setenv("INITIAL_SLEEP_INTERVAL", "1")
+	_ = os.Setenv("MAX_WAIT_TIME", "2")
+	_ = os.Setenv("NUMBER_WORKER_THREADS", "1")
 
-	_, err := config.InitConfig()
-	s.Error(err)
-	s.Contains(err.Error(), "must be less than")
+	conf, err := config.InitConfig()
+	s.NoError(err)
+	s.Zero(conf.MaxHarborStorage)
+	s.Zero(conf.MaxCatalogProjects)
+
+	_ = os.Setenv("MAX_HARBOR_STORAGE", "1099511627776")
+	_ = os.Setenv("MAX_CATALOG_PROJECTS", "500")
+	_ = os.Setenv("POD_NAMESPACE", "orch-app")
+	conf, err = config.InitConfig()
+	s.NoError(err)
+	s.Equal(int64(1099511627776), conf.MaxHarborStorage)
+	s.Equal(500, conf.MaxCatalogProjects)
+
+	// The catalog projects are counted from the inventories
+	_ = os.Setenv("POD_NAMESPACE", "")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "MAX_CATALOG_PROJECTS requires POD_NAMESPACE")
+
+	_ = os.Setenv("MAX_CATALOG_PROJECTS", "some")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "invalid MAX_CATALOG_PROJECTS")
+
+	_ = os.Setenv("MAX_CATALOG_PROJECTS", "")
+	_ = os.Setenv("MAX_HARBOR_STORAGE", "-1")
+	_, err = config.InitConfig()
+	s.ErrorContains(err, "invalid MAX_HARBOR_STORAGE")
+}
+
+// memoryHistory keeps the project history in memory
+type memoryHistory struct {
+	mu       sync.Mutex
+	projects map[string]*history.History
+}
+
+func (h *memoryHistory) Append(_ context.Context, organization string, project string, uuid string, entry history.Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.projects[uuid] == nil {
+		h.projects[uuid] = &history.History{Organization: organization, Project: project, UUID: uuid}
+	}
+	h.projects[uuid].Events = append(h.projects[uuid].Events, entry)
+	return nil
+}
+
+func (h *memoryHistory) Load(_ context.Context, uuid string) (*history.History, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.projects[uuid], nil
+}
+
+func (s *ManagerTestSuite) TestWatcherMessage() {
+	s.Equal("Created", watcherMessage(nil))
+	s.Equal("Created", watcherMessage(&plugins.DispatchResult{}))
+	s.Equal("Created with warnings: mirror failed; deployment kept",
+		watcherMessage(&plugins.DispatchResult{Warnings: []string{"mirror failed", "deployment kept"}}))
+}
+
+func (s *ManagerTestSuite) TestProjectHistory() {
+	manager := NewManager(config.Configuration{
+		InitialSleepInterval: 100 * time.Millisecond,
+		MaxWaitTime:          time.Second,
+		ControllerVersion:    "1.2.3",
+	})
+	store := &memoryHistory{projects: map[string]*history.History{}}
+	manager.history = store
+	plugins.RemoveAllPlugins()
+	plugins.Register(&recordingPlugin{})
+	defer plugins.RemoveAllPlugins()
+
+	event := plugins.Event{EventType: "create", Organization: "org", Name: "project", UUID: "uuid-project",
+		Profile: &config.ProvisioningProfile{Name: "small"}, Received: time.Now()}
+	event.Lifecycle = plugins.NewLifecycle(context.Background())
+	manager.processEvent(0, event)
+
+	invalid := plugins.Event{EventType: "rename", Organization: "org", Name: "project", UUID: "uuid-project",
+		Received: time.Now()}
+	invalid.Lifecycle = plugins.NewLifecycle(context.Background())
+	manager.processEvent(0, invalid)
+
+	recorded, err := store.Load(context.Background(), "uuid-project")
+	s.NoError(err)
+	s.Equal("org", recorded.Organization)
+	s.Len(recorded.Events, 2)
+	created := recorded.Events[0]
+	s.Equal("create", created.EventType)
+	s.Equal(history.ResultSuccess, created.Result)
+	s.Equal("small", created.Profile)
+	s.Equal("1.2.3", created.ControllerVersion)
+	s.Empty(created.Error)
+	s.Len(created.Plugins, 1)
+	s.Equal("recording", created.Plugins[0].Name)
+	s.Equal(history.ResultSuccess, created.Plugins[0].Result)
+	s.Equal(1, created.Plugins[0].Attempts)
+
+	s.Equal(history.ResultError, recorded.Events[1].Result)
+	s.Contains(recorded.Events[1].Error, "unknown event type: rename")
+	s.Empty(recorded.Events[1].Plugins)
+}
+
+func (s *ManagerTestSuite) TestAuditEvents() {
+	received := make(chan audit.Event, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		var event audit.Event
+		s.NoError(json.NewDecoder(r.Body).Decode(&event))
+		received <- event
+	}))
+	defer server.Close()
+	receive := func() audit.Event {
+		select {
+		case event := <-received:
+			return event
+		case <-time.After(10 * time.Second):
+			s.FailNow("the audit event was not sent")
+			return audit.Event{}
+		}
+	}
+
+	s.Nil(NewManager(config.Configuration{}).audit)
+	s.NotNil(NewManager(config.Configuration{AuditURL: server.URL}).audit)
+	manager := NewManager(config.Configuration{
+		InitialSleepInterval: 100 * time.Millisecond,
+		MaxWaitTime:          time.Second,
+		ControllerVersion:    "1.2.3",
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	manager.audit = audit.NewEmitter(server.URL)
+	manager.audit.Start(ctx)
+	manager.eventChan = make(chan plugins.Event, 1)
+	plugins.RemoveAllPlugins()
+	plugins.Register(&recordingPlugin{})
+	defer plugins.RemoveAllPlugins()
+
+	// A project created by a CloudEvent is created and provisioned
+	s.NoError(manager.HandleProjectEvent(ctx, events.CreateProjectV1{Project: events.ProjectFromEventData(
+		events.ProjectEventData{Organization: "org", Name: "project", UUID: "uuid-project"}, false)}))
+	created := receive()
+	s.Equal(audit.TenantCreated, created.Type)
+	s.Equal(config.EventSourceCloudEvents, created.Actor)
+	s.Equal("org", created.Organization)
+	s.Equal("project", created.Project)
+	s.Equal("uuid-project", created.UUID)
+	s.Equal("1.2.3", created.ControllerVersion)
+	manager.processEvent(0, <-manager.eventChan)
+	provisioned := receive()
+	s.Equal(audit.TenantProvisioned, provisioned.Type)
+	s.Equal(config.EventSourceCloudEvents, provisioned.Actor)
+	s.Empty(provisioned.Error)
+	s.Positive(provisioned.DurationSeconds)
+	s.Contains(provisioned.PluginSeconds, "recording")
+
+	// A Nexus project that fails to provision
+	invalid := plugins.Event{EventType: "create", Organization: "org", Name: "project", Received: time.Now(),
+		Lifecycle: plugins.NewLifecycle(context.Background())}
+	manager.processEvent(0, invalid)
+	failed := receive()
+	s.Equal(audit.TenantProvisioningFailed, failed.Type)
+	s.Equal(config.EventSourceNexus, failed.Actor)
+	s.Contains(failed.Error, "missing the organization, name or UUID")
+
+	// Update events are not audited
+	update := plugins.Event{EventType: "update", Organization: "org", Name: "project", UUID: "uuid-project",
+		Received: time.Now(), Lifecycle: plugins.NewLifecycle(context.Background())}
+	manager.processEvent(0, update)
+	deleted := plugins.Event{EventType: "delete", Organization: "org", Name: "project", UUID: "uuid-project",
+		Received: time.Now(), Lifecycle: plugins.NewLifecycle(context.Background())}
+	manager.processEvent(0, deleted)
+	s.Equal(audit.TenantDeleted, receive().Type)
+	s.Empty(received)
 }
 
 // Test to verify error propagation in manager
 func (s *ManagerTestSuite) TestManagerErrorPropagation() {
 	// Create a manager with invalid config that will cause plugin initialization to fail
 	cfg := config.Configuration{
-		HarborServer:          "http://invalid-harbor-server:99999",
-		HarborNamespace:       "invalid-namespace",
-		HarborAdminCredential: "invalid-credential",
-		KeycloakServer:        "http://invalid-keycloak:99999",
-		CatalogServer:         "invalid-catalog:99999",
-		ReleaseServiceBase:    "http://invalid-rs:99999",
-		ManifestPath:          "/invalid",
-		ManifestTag:           "invalid",
-		VaultServer:           "http://invalid-vault:99999",
-		KeycloakServiceBase:   "http://invalid-keycloak-svc:99999",
-		NumberWorkerThreads:   1,
-		InitialSleepInterval:  1 * time.Second,
-		MaxWaitTime:           5 * time.Second,
+		HarborServer: "http://invalid-harbor-server:99999",
+		Secrets: config.K8sSecretsRef{
+			HarborAdmin: config.SecretRef{Namespace: "invalid-namespace", Name: "invalid-credential", Key: "credential"},
+		},
+		KeycloakServer:       "http://invalid-keycloak:99999",
+		CatalogServer:        "invalid-catalog:99999",
+		ReleaseServiceBase:   "http://invalid-rs:99999",
+		ManifestPath:         "/invalid",
+		ManifestTag:          "invalid",
+		VaultServer:          "http://invalid-vault:99999",
+		KeycloakServiceBase:  "http://invalid-keycloak-svc:99999",
+		NumberWorkerThreads:  1,
+		InitialSleepInterval: 1 * time.Second,
+		MaxWaitTime:          5 * time.Second,
 	}
 
 	manager := NewManager(cfg)
@@ -178,19 +1707,20 @@ func (s *ManagerTestSuite) TestManagerErrorPropagation() {
 func (s *ManagerTestSuite) TestManagerDoesNotHangIndefinitely() {
 	// Create a manager with minimal valid config
 	cfg := config.Configuration{
-		HarborServer:          "http://localhost:8080",
-		HarborNamespace:       "test-namespace",
-		HarborAdminCredential: "test-credential",
-		KeycloakServer:        "http://localhost:8081",
-		CatalogServer:         "localhost:8082",
-		ReleaseServiceBase:    "http://localhost:8083",
-		ManifestPath:          "/test",
-		ManifestTag:           "test",
-		VaultServer:           "http://localhost:8200",
-		KeycloakServiceBase:   "http://localhost:8080",
-		NumberWorkerThreads:   1,
-		InitialSleepInterval:  1 * time.Second,
-		MaxWaitTime:           5 * time.Second,
+		HarborServer: "http://localhost:8080",
+		Secrets: config.K8sSecretsRef{
+			HarborAdmin: config.SecretRef{Namespace: "test-namespace", Name: "test-credential", Key: "credential"},
+		},
+		KeycloakServer:       "http://localhost:8081",
+		CatalogServer:        "localhost:8082",
+		ReleaseServiceBase:   "http://localhost:8083",
+		ManifestPath:         "/test",
+		ManifestTag:          "test",
+		VaultServer:          "http://localhost:8200",
+		KeycloakServiceBase:  "http://localhost:8080",
+		NumberWorkerThreads:  1,
+		InitialSleepInterval: 1 * time.Second,
+		MaxWaitTime:          5 * time.Second,
 	}
 
 	manager := NewManager(cfg)
@@ -214,3 +1744,1104 @@ func (s *ManagerTestSuite) TestManagerDoesNotHangIndefinitely() {
 		s.T().Fatal("Manager.Start() appears to hang indefinitely - due to timeouts")
 	}
 }
+
+// failingPlugin fails every create event with the given error
+type failingPlugin struct {
+	err   error
+	calls int
+}
+
+func (p *failingPlugin) Name() string {
+	return "failing"
+}
+
+func (p *failingPlugin) Initialize(_ context.Context, _ *plugins.PluginData) error {
+	return nil
+}
+
+func (p *failingPlugin) CreateEvent(_ context.Context, _ plugins.Event, _ *plugins.PluginData) error {
+	p.calls++
+	return p.err
+}
+
+func (p *failingPlugin) DeleteEvent(_ context.Context, _ plugins.Event, _ *plugins.PluginData) error {
+	return nil
+}
+
+func (s *ManagerTestSuite) TestHandleProjectEventRetries() {
+	manager := NewManager(config.Configuration{
+		InitialSleepInterval: 10 * time.Millisecond,
+		MaxWaitTime:          100 * time.Millisecond,
+	})
+	defer plugins.RemoveAllPlugins()
+
+	// Permanent errors fail on the first attempt
+	plugin := &failingPlugin{err: fmt.Errorf("%w: bad request", southbound.ErrPermanent)}
+	plugins.RemoveAllPlugins()
+	plugins.Register(plugin)
+	result, err := manager.handleProjectEvent(context.Background(), s.validatedEvent("create", "project"))
+	s.ErrorIs(err, southbound.ErrPermanent)
+	s.Equal(1, plugin.calls)
+	s.Equal(plugin.Name(), result.FailedPlugin())
+
+	// Transient errors are retried until the maximum wait time
+	plugin = &failingPlugin{err: fmt.Errorf("%w: service unavailable", southbound.ErrTransient)}
+	plugins.RemoveAllPlugins()
+	plugins.Register(plugin)
+	result, err = manager.handleProjectEvent(context.Background(), s.validatedEvent("create", "project"))
+	s.ErrorIs(err, southbound.ErrTransient)
+	s.Greater(plugin.calls, 1)
+	// The result covers all attempts
+	s.Equal(plugin.calls, result.Plugin(plugin.Name()).Attempts)
+}
+
+// retryingPlugin retries a failing operation itself, with more attempts than the maximum wait time allows
+type retryingPlugin struct {
+	failingPlugin
+}
+
+func (p *retryingPlugin) CreateEvent(ctx context.Context, _ plugins.Event, _ *plugins.PluginData) error {
+	backoff := retry.Backoff{Initial: 20 * time.Millisecond, Attempts: 100}
+	return retry.Do(ctx, "test operation", backoff, southbound.IsRetryable, func(_ context.Context) error {
+		p.calls++
+		return p.err
+	})
+}
+
+func (s *ManagerTestSuite) TestHandleProjectEventRetryBudget() {
+	manager := NewManager(config.Configuration{
+		InitialSleepInterval: 10 * time.Millisecond,
+		MaxWaitTime:          300 * time.Millisecond,
+	})
+	defer plugins.RemoveAllPlugins()
+
+	plugin := &retryingPlugin{failingPlugin{err: fmt.Errorf("%w: service unavailable", southbound.ErrTransient)}}
+	plugins.RemoveAllPlugins()
+	plugins.Register(plugin)
+
+	// The retries of the plugin and of the manager stop together when the maximum wait time is used up
+	start := time.Now()
+	_, err := manager.handleProjectEvent(context.Background(), s.validatedEvent("create", "project"))
+	s.ErrorIs(err, retry.ErrBudgetExhausted)
+	s.ErrorIs(err, southbound.ErrTransient)
+	s.Less(time.Since(start), 300*time.Millisecond)
+	s.Greater(plugin.calls, 1)
+}
+
+// validatedEvent returns an event whose lifecycle is ready for dispatching
+func (s *ManagerTestSuite) validatedEvent(eventType string, name string) plugins.Event {
+	event := plugins.Event{EventType: eventType, Organization: "org", Name: name, UUID: "uuid-" + name}
+	event.Lifecycle = plugins.NewLifecycle(context.Background())
+	s.NoError(event.Lifecycle.Validate())
+	return event
+}
+
+// recordingPlugin records the order of events and blocks creates of project "slow" until released
+type recordingPlugin struct {
+	mu      sync.Mutex
+	events  []string
+	release chan struct{}
+}
+
+func (p *recordingPlugin) Name() string {
+	return "recording"
+}
+
+func (p *recordingPlugin) Initialize(_ context.Context, _ *plugins.PluginData) error {
+	return nil
+}
+
+func (p *recordingPlugin) record(event plugins.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event.EventType+" "+event.Name)
+}
+
+func (p *recordingPlugin) recorded() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string{}, p.events...)
+}
+
+func (p *recordingPlugin) CreateEvent(ctx context.Context, event plugins.Event, _ *plugins.PluginData) error {
+	if event.Name == "slow" {
+		select {
+		case <-p.release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	p.record(event)
+	return nil
+}
+
+func (p *recordingPlugin) DeleteEvent(_ context.Context, event plugins.Event, _ *plugins.PluginData) error {
+	p.record(event)
+	return nil
+}
+
+func (s *ManagerTestSuite) TestEventsSerializedPerProject() {
+	manager := NewManager(config.Configuration{
+		InitialSleepInterval: 100 * time.Millisecond,
+		MaxWaitTime:          time.Second,
+	})
+	plugin := &recordingPlugin{release: make(chan struct{})}
+	plugins.RemoveAllPlugins()
+	plugins.Register(plugin)
+	defer plugins.RemoveAllPlugins()
+
+	manager.eventChan = make(chan plugins.Event, 1)
+	for i := 0; i < 2; i++ {
+		go manager.eventWorker(i)
+	}
+	defer manager.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s.NoError(manager.CreateProject(ctx, "org", "slow", "uuid-slow", nil))
+	s.NoError(manager.CreateProject(ctx, "org", "slow", "uuid-slow", nil))
+	s.NoError(manager.CreateProject(ctx, "org", "fast", "uuid-fast", nil))
+
+	// Another project is not held up by the slow create, the second create waits for the first
+	s.Eventually(func() bool { return len(plugin.recorded()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	s.Equal([]string{"create fast"}, plugin.recorded())
+	// The slow project has one event being handled and one held back
+	s.Eventually(func() bool { return testutil.ToFloat64(eventBacklog) == 2 }, 5*time.Second, 10*time.Millisecond)
+	s.Equal(float64(1), testutil.ToFloat64(eventWorkersBusy))
+
+	close(plugin.release)
+	s.Eventually(func() bool { return len(plugin.recorded()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	s.Equal([]string{"create fast", "create slow", "create slow"}, plugin.recorded())
+
+	// Once the project is idle its next event is queued to the workers again
+	s.NoError(manager.CreateProject(ctx, "org", "slow", "uuid-slow", nil))
+	s.Eventually(func() bool { return len(plugin.recorded()) == 4 }, 5*time.Second, 10*time.Millisecond)
+	s.Eventually(func() bool {
+		manager.projects.mu.Lock()
+		defer manager.projects.mu.Unlock()
+		return len(manager.projects.queues) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	s.Equal(float64(0), testutil.ToFloat64(eventBacklog))
+}
+
+func (s *ManagerTestSuite) TestEventLatencyWindow() {
+	window := newLatencyWindow(time.Minute)
+	now := time.Now()
+	s.Equal(time.Duration(0), window.average(now))
+
+	window.add(now.Add(-90*time.Second), 10*time.Second)
+	window.add(now.Add(-30*time.Second), 2*time.Second)
+	window.add(now, 4*time.Second)
+	// The event finished before the window is left out
+	s.Equal(3*time.Second, window.average(now))
+	s.Len(window.samples, 2)
+
+	// Once no event finished in the window the average is back to zero
+	s.Equal(time.Duration(0), window.average(now.Add(2*time.Minute)))
+}
+
+func (s *ManagerTestSuite) TestDeleteCancelsCreate() {
+	manager := NewManager(config.Configuration{
+		InitialSleepInterval: 100 * time.Millisecond,
+		MaxWaitTime:          time.Second,
+	})
+	plugin := &recordingPlugin{release: make(chan struct{})}
+	plugins.RemoveAllPlugins()
+	plugins.Register(plugin)
+	defer plugins.RemoveAllPlugins()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	manager.eventChan = make(chan plugins.Event, 1)
+	s.NoError(manager.CreateProject(ctx, "org", "slow", "uuid-slow", nil))
+	create := <-manager.eventChan
+	s.NoError(manager.UpdateProject(ctx, "org", "slow", "uuid-slow", nil, nexushook.ProjectChanges{}))
+	update := manager.projects.queues["uuid-slow"].pending[0]
+	eventType, ok := manager.projects.activeEvent("uuid-slow")
+	s.True(ok)
+	s.Equal("update", eventType)
+
+	done := make(chan struct{})
+	go func() {
+		manager.processEvent(0, create)
+		close(done)
+	}()
+	s.Eventually(func() bool { return create.Lifecycle.Phase() == plugins.PhaseProvisioning }, 5*time.Second, 10*time.Millisecond)
+
+	// The delete cancels the create in progress and drops the pending update
+	s.NoError(manager.DeleteProject(ctx, "org", "slow", "uuid-slow", nil))
+	<-done
+	s.Equal(plugins.PhaseCancelled, create.Lifecycle.Phase())
+	s.Equal(plugins.PhaseCancelled, update.Lifecycle.Phase())
+	eventType, _ = manager.projects.activeEvent("uuid-slow")
+	s.Equal("delete", eventType)
+
+	next, ok := manager.projects.release("uuid-slow")
+	s.True(ok)
+	s.Equal("delete", next.EventType)
+	manager.processEvent(0, next)
+	s.Equal(plugins.PhaseCompleted, next.Lifecycle.Phase())
+	s.Equal([]string{"delete slow"}, plugin.recorded())
+
+	_, ok = manager.projects.release("uuid-slow")
+	s.False(ok)
+	_, ok = manager.projects.activeEvent("uuid-slow")
+	s.False(ok)
+}
+
+// resourcePlugin keeps the resources of each project as the provisioning plugins would: provisioning events create
+// them, delete events remove them, and deactivate events disable them until they are reactivated
+type resourcePlugin struct {
+	deactivatingPlugin
+	resources map[string]string
+}
+
+func (p *resourcePlugin) set(event plugins.Event, state string) {
+	p.record(event)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if state == "" {
+		delete(p.resources, event.UUID)
+		return
+	}
+	p.resources[event.UUID] = state
+}
+
+func (p *resourcePlugin) CreateEvent(_ context.Context, event plugins.Event, _ *plugins.PluginData) error {
+	p.set(event, "active")
+	return nil
+}
+
+func (p *resourcePlugin) UpdateEvent(_ context.Context, event plugins.Event, _ *plugins.PluginData) error {
+	p.set(event, "active")
+	return nil
+}
+
+func (p *resourcePlugin) EnsureEvent(_ context.Context, event plugins.Event, _ *plugins.PluginData) error {
+	p.set(event, "active")
+	return nil
+}
+
+func (p *resourcePlugin) DeleteEvent(_ context.Context, event plugins.Event, _ *plugins.PluginData) error {
+	p.set(event, "")
+	return nil
+}
+
+func (p *resourcePlugin) DeactivateEvent(_ context.Context, event plugins.Event, _ *plugins.PluginData) error {
+	p.set(event, "inactive")
+	return nil
+}
+
+func (p *resourcePlugin) ReactivateEvent(_ context.Context, event plugins.Event, _ *plugins.PluginData) error {
+	p.set(event, "active")
+	return nil
+}
+
+// The events of a project are received in various orders relative to the worker handling them. Each step of a
+// scenario receives an event of the project, or has the worker handle the event it holds, if any; the remaining
+// events are handled at the end. Events received after a delete must not bring the resources of the project back.
+func (s *ManagerTestSuite) TestDeletionOrdering() {
+	scenarios := []struct {
+		name      string
+		steps     []string
+		handled   []string
+		resources string
+		refused   int
+	}{
+		{
+			name:    "create received after a queued delete",
+			steps:   []string{"create", "delete", "create"},
+			handled: []string{"delete proj"},
+			refused: 1,
+		},
+		{
+			name:    "events received after the delete of a provisioned project",
+			steps:   []string{"create", "handle", "delete", "create", "update", "ensure"},
+			handled: []string{"create proj", "delete proj"},
+			refused: 3,
+		},
+		{
+			name:    "events received while the delete is handled",
+			steps:   []string{"create", "update", "delete", "handle", "update", "create"},
+			handled: []string{"delete proj"},
+			refused: 2,
+		},
+		{
+			name:    "delete received after pending events",
+			steps:   []string{"create", "handle", "update", "ensure", "delete"},
+			handled: []string{"create proj", "delete proj"},
+		},
+		{
+			name:    "repeated delete",
+			steps:   []string{"delete", "delete"},
+			handled: []string{"delete proj", "delete proj"},
+		},
+		{
+			name:      "reactivate received after a deactivate",
+			steps:     []string{"create", "handle", "deactivate", "update", "reactivate", "update"},
+			handled:   []string{"create proj", "deactivate proj", "reactivate proj", "update proj"},
+			resources: "active",
+			refused:   1,
+		},
+		{
+			name:      "deactivate received while the project is provisioned",
+			steps:     []string{"create", "handle", "update", "deactivate", "ensure"},
+			handled:   []string{"create proj", "deactivate proj"},
+			resources: "inactive",
+			refused:   1,
+		},
+		{
+			name:    "reactivate received after the purge",
+			steps:   []string{"create", "handle", "deactivate", "handle", "delete", "reactivate", "create"},
+			handled: []string{"create proj", "deactivate proj", "delete proj"},
+			refused: 2,
+		},
+		{
+			name:    "events received after the delete was handled",
+			steps:   []string{"create", "handle", "delete", "handle", "create", "update", "delete"},
+			handled: []string{"create proj", "delete proj", "delete proj"},
+			refused: 2,
+		},
+		{
+			name:      "events received after a handled reactivate",
+			steps:     []string{"create", "handle", "deactivate", "handle", "update", "reactivate", "handle", "update"},
+			handled:   []string{"create proj", "deactivate proj", "reactivate proj", "update proj"},
+			resources: "active",
+			refused:   1,
+		},
+	}
+	refused := func() int {
+		total := 0
+		for _, eventType := range []string{"create", "update", "ensure", "reactivate"} {
+			total += int(testutil.ToFloat64(refusedEvents.WithLabelValues(eventType)))
+		}
+		return total
+	}
+
+	for _, scenario := range scenarios {
+		s.Run(scenario.name, func() {
+			manager := NewManager(config.Configuration{
+				InitialSleepInterval: 100 * time.Millisecond,
+				MaxWaitTime:          time.Second,
+			})
+			plugin := &resourcePlugin{resources: map[string]string{}}
+			plugins.RemoveAllPlugins()
+			plugins.Register(plugin)
+			defer plugins.RemoveAllPlugins()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			manager.eventChan = make(chan plugins.Event, 1)
+			var current *plugins.Event
+			handle := func() bool {
+				if current == nil {
+					select {
+					case event := <-manager.eventChan:
+						current = &event
+					default:
+						return false
+					}
+				}
+				manager.processEvent(0, *current)
+				current = nil
+				if next, ok := manager.projects.release("uuid-proj"); ok {
+					current = &next
+				}
+				return true
+			}
+
+			refusedBefore := refused()
+			for _, step := range scenario.steps {
+				if step == "handle" {
+					s.True(handle())
+					continue
+				}
+				s.NoError(manager.enqueue(ctx, plugins.Event{EventType: step, Organization: "org", Name: "proj", UUID: "uuid-proj"}))
+			}
+			for handle() {
+			}
+
+			s.Equal(scenario.handled, plugin.recorded())
+			s.Equal(scenario.resources, plugin.resources["uuid-proj"])
+			s.Equal(scenario.refused, refused()-refusedBefore)
+			_, ok := manager.projects.activeEvent("uuid-proj")
+			s.False(ok)
+		})
+	}
+}
+
+func (s *ManagerTestSuite) TestDeletedProjectsForgotten() {
+	manager := NewManager(config.Configuration{
+		InitialSleepInterval: 100 * time.Millisecond,
+		MaxWaitTime:          time.Second,
+	})
+	plugins.RemoveAllPlugins()
+	plugins.Register(&recordingPlugin{})
+	defer plugins.RemoveAllPlugins()
+
+	queues := manager.projects
+	now := time.Now()
+	queues.now = func() time.Time { return now }
+	event := func(eventType string, uuid string) plugins.Event {
+		return plugins.Event{EventType: eventType, Organization: "org", Name: uuid, UUID: uuid,
+			Lifecycle: plugins.NewLifecycle(context.Background())}
+	}
+	handle := func(e plugins.Event) {
+		s.True(queues.acquire(e))
+		manager.processEvent(0, e)
+		s.Equal(plugins.PhaseCompleted, e.Lifecycle.Phase())
+		_, ok := queues.release(e.UUID)
+		s.False(ok)
+	}
+
+	// A deleted project is refused until the TTL is over
+	handle(event("delete", "uuid-1"))
+	s.False(queues.acquire(event("create", "uuid-1")))
+	now = now.Add(deletedProjectTTL + time.Second)
+	handle(event("create", "uuid-1"))
+	s.NotContains(queues.deleted, "uuid-1")
+
+	// The oldest deleted project is forgotten first
+	for i := 0; i < maxDeletedProjects; i++ {
+		now = now.Add(time.Millisecond)
+		handle(event("delete", fmt.Sprintf("uuid-%d", i+2)))
+	}
+	s.Len(queues.deleted, maxDeletedProjects)
+	handle(event("delete", "uuid-last"))
+	s.Len(queues.deleted, maxDeletedProjects)
+	s.NotContains(queues.deleted, "uuid-2")
+	handle(event("create", "uuid-2"))
+	s.False(queues.acquire(event("create", "uuid-3")))
+}
+
+// failingDeletePlugin fails the delete events
+type failingDeletePlugin struct {
+	resourcePlugin
+}
+
+func (p *failingDeletePlugin) DeleteEvent(_ context.Context, event plugins.Event, _ *plugins.PluginData) error {
+	p.record(event)
+	return fmt.Errorf("%w: project is in use", southbound.ErrPermanent)
+}
+
+func (s *ManagerTestSuite) TestFailedDeleteNotRemembered() {
+	manager := NewManager(config.Configuration{
+		InitialSleepInterval: 100 * time.Millisecond,
+		MaxWaitTime:          time.Second,
+	})
+	plugin := &failingDeletePlugin{resourcePlugin{resources: map[string]string{}}}
+	plugins.RemoveAllPlugins()
+	plugins.Register(plugin)
+	defer plugins.RemoveAllPlugins()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	manager.eventChan = make(chan plugins.Event, 1)
+	handle := func(eventType string) plugins.Event {
+		s.NoError(manager.enqueue(ctx, plugins.Event{EventType: eventType, Organization: "org", Name: "proj", UUID: "uuid-proj"}))
+		var event plugins.Event
+		select {
+		case event = <-manager.eventChan:
+		default:
+			s.FailNow(eventType + " event was not queued")
+		}
+		manager.processEvent(0, event)
+		_, ok := manager.projects.release(event.UUID)
+		s.False(ok)
+		return event
+	}
+
+	// A failed delete removed nothing, so the project can be created again
+	handle("create")
+	s.Equal(plugins.PhaseFailed, handle("delete").Lifecycle.Phase())
+	s.Equal(plugins.PhaseCompleted, handle("create").Lifecycle.Phase())
+	s.Equal([]string{"create proj", "delete proj", "create proj"}, plugin.recorded())
+	s.Equal("active", plugin.resources["uuid-proj"])
+
+	// So can a delete that was dropped before it was handled
+	drop := plugins.Event{EventType: "delete", Organization: "org", Name: "proj", UUID: "uuid-proj",
+		Lifecycle: plugins.NewLifecycle(context.Background())}
+	s.True(manager.projects.acquire(drop))
+	drop.Lifecycle.Cancel()
+	_, ok := manager.projects.release(drop.UUID)
+	s.False(ok)
+	s.Equal(plugins.PhaseCompleted, handle("create").Lifecycle.Phase())
+	s.NotContains(manager.projects.deleted, "uuid-proj")
+}
+
+func (s *ManagerTestSuite) TestWatchdogCancelsStuckEvent() {
+	manager := NewManager(config.Configuration{
+		InitialSleepInterval: 100 * time.Millisecond,
+		MaxWaitTime:          time.Second,
+		StuckEventTimeout:    time.Hour,
+	})
+	plugin := &recordingPlugin{release: make(chan struct{})}
+	plugins.RemoveAllPlugins()
+	plugins.Register(plugin)
+	defer plugins.RemoveAllPlugins()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	manager.eventChan = make(chan plugins.Event, 1)
+	s.NoError(manager.CreateProject(ctx, "org", "slow", "uuid-slow", nil))
+	create := <-manager.eventChan
+
+	done := make(chan struct{})
+	go func() {
+		manager.processEvent(0, create)
+		close(done)
+	}()
+	s.Eventually(func() bool { return create.Lifecycle.Phase() == plugins.PhaseProvisioning }, 5*time.Second, 10*time.Millisecond)
+
+	// Nothing is stuck before the timeout
+	stuck := testutil.ToFloat64(stuckEvents.WithLabelValues(plugin.Name()))
+	manager.checkStuckEvents(ctx, time.Now())
+	s.Equal(plugins.PhaseProvisioning, create.Lifecycle.Phase())
+
+	// The stuck event fails with the diagnostics of the watchdog and is queued again
+	manager.checkStuckEvents(ctx, time.Now().Add(2*time.Hour))
+	<-done
+	s.Equal(plugins.PhaseFailed, create.Lifecycle.Phase())
+	s.ErrorContains(create.Lifecycle.Err(), "create event for project slow stuck for")
+	s.ErrorContains(create.Lifecycle.Err(), "in Provisioning (recording)")
+	s.ErrorContains(create.Lifecycle.Err(), "cancelled and requeued")
+	s.Equal(stuck+1, testutil.ToFloat64(stuckEvents.WithLabelValues(plugin.Name())))
+	s.Eventually(func() bool {
+		manager.projects.mu.Lock()
+		defer manager.projects.mu.Unlock()
+		return len(manager.projects.queues["uuid-slow"].pending) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The requeued event is handled once the project is released
+	next, ok := manager.projects.release("uuid-slow")
+	s.True(ok)
+	s.Equal("create", next.EventType)
+	s.NotSame(create.Lifecycle, next.Lifecycle)
+	s.Equal(create.Received, next.Received)
+	close(plugin.release)
+	manager.processEvent(0, next)
+	s.Equal(plugins.PhaseCompleted, next.Lifecycle.Phase())
+	s.Equal([]string{"create slow"}, plugin.recorded())
+}
+
+func (s *ManagerTestSuite) TestWatchdogRequeueAtShutdown() {
+	manager := NewManager(config.Configuration{
+		InitialSleepInterval: 100 * time.Millisecond,
+		MaxWaitTime:          time.Second,
+		StuckEventTimeout:    time.Hour,
+	})
+	plugins.RemoveAllPlugins()
+	plugins.Register(&recordingPlugin{})
+	defer plugins.RemoveAllPlugins()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	watchdogCtx, stopWatchdog := context.WithCancel(ctx)
+	manager.stopWatchdog = stopWatchdog
+	manager.watchdogDone = make(chan struct{})
+	close(manager.watchdogDone)
+
+	// The queue is full, so the stuck event is requeued while the manager is closed
+	manager.eventChan = make(chan plugins.Event, 1)
+	s.NoError(manager.CreateProject(ctx, "org", "other", "uuid-other", nil))
+	stuck := s.validatedEvent("create", "stuck")
+	stuck.Received = time.Now()
+	manager.watchdog.start(0, stuck, func(error) {})
+	manager.checkStuckEvents(watchdogCtx, time.Now().Add(2*time.Hour))
+	s.Eventually(func() bool { return testutil.ToFloat64(eventQueueBlocked) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// Closing waits for the requeue, which gives up the stuck event rather than send it on the closed queue
+	manager.Close()
+	_, ok := manager.projects.activeEvent("uuid-stuck")
+	s.False(ok)
+	var queued []string
+	for event := range manager.eventChan {
+		queued = append(queued, event.Name)
+	}
+	s.Equal([]string{"other"}, queued)
+}
+
+func (s *ManagerTestSuite) TestWatchdogRequeueLimit() {
+	w := newWatchdog(time.Minute)
+	event := s.validatedEvent("create", "project")
+	later := time.Now().Add(time.Hour)
+
+	// Events of a project that keep getting stuck are requeued a limited number of times
+	for i := 1; i <= maxStuckRequeues+1; i++ {
+		w.start(0, event, func(error) {})
+		stuck := w.stuck(later)
+		s.Len(stuck, 1)
+		s.Equal(i <= maxStuckRequeues, stuck[0].requeue, i)
+		// Each stuck event is reported once
+		s.Empty(w.stuck(later))
+		w.done(0)
+	}
+
+	// An event that completes in time resets the count
+	w.start(0, event, func(error) {})
+	w.done(0)
+	w.start(0, event, func(error) {})
+	stuck := w.stuck(later)
+	s.Len(stuck, 1)
+	s.True(stuck[0].requeue)
+	w.done(0)
+	s.Empty(w.events)
+}
+
+func (s *ManagerTestSuite) TestInvalidEventFails() {
+	manager := NewManager(config.Configuration{
+		InitialSleepInterval: 100 * time.Millisecond,
+		MaxWaitTime:          time.Second,
+	})
+	plugin := &recordingPlugin{}
+	plugins.RemoveAllPlugins()
+	plugins.Register(plugin)
+	defer plugins.RemoveAllPlugins()
+
+	event := plugins.Event{EventType: "rename", Organization: "org", Name: "project", UUID: "uuid-project"}
+	event.Lifecycle = plugins.NewLifecycle(context.Background())
+	manager.processEvent(0, event)
+	s.Equal(plugins.PhaseFailed, event.Lifecycle.Phase())
+	s.ErrorIs(event.Lifecycle.Err(), southbound.ErrPermanent)
+	s.Empty(plugin.recorded())
+}
+
+func (s *ManagerTestSuite) TestReprovisionWaitsForEvent() {
+	manager := NewManager(config.Configuration{
+		InitialSleepInterval: 100 * time.Millisecond,
+		MaxWaitTime:          time.Second,
+	})
+	plugin := &recordingPlugin{release: make(chan struct{})}
+	plugins.RemoveAllPlugins()
+	plugins.Register(plugin)
+	defer plugins.RemoveAllPlugins()
+
+	manager.eventChan = make(chan plugins.Event, 1)
+	go manager.eventWorker(0)
+	defer manager.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s.NoError(manager.reprovision(ctx, nexushook.ProjectStatus{Organization: "org", Name: "fast", UUID: "uuid-fast"}))
+	s.Equal([]string{"create fast"}, plugin.recorded())
+
+	// The error of a failed event is returned
+	err := manager.reprovision(ctx, nexushook.ProjectStatus{Organization: "org", Name: "broken"})
+	s.ErrorIs(err, southbound.ErrPermanent)
+
+	// Giving up cancels the event
+	shortCtx, shortCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer shortCancel()
+	s.ErrorIs(manager.reprovision(shortCtx, nexushook.ProjectStatus{Organization: "org", Name: "slow", UUID: "uuid-slow"}), context.DeadlineExceeded)
+	s.Equal([]string{"create fast"}, plugin.recorded())
+	close(plugin.release)
+}
+
+// ensuringPlugin records the ensure events it receives
+type ensuringPlugin struct {
+	recordingPlugin
+	name string
+}
+
+func (p *ensuringPlugin) Name() string {
+	return p.name
+}
+
+func (p *ensuringPlugin) EnsureEvent(_ context.Context, event plugins.Event, _ *plugins.PluginData) error {
+	p.record(event)
+	return nil
+}
+
+func (s *ManagerTestSuite) TestReprovisionProject() {
+	manager := NewManager(config.Configuration{
+		InitialSleepInterval: 100 * time.Millisecond,
+		MaxWaitTime:          time.Second,
+	})
+	harbor := &ensuringPlugin{name: "Harbor Provisioner"}
+	catalog := &ensuringPlugin{name: "Catalog Provisioner"}
+	plugins.RemoveAllPlugins()
+	plugins.Register(harbor)
+	plugins.Register(catalog)
+	defer plugins.RemoveAllPlugins()
+	defaultListProjectStatus := listProjectStatus
+	listProjectStatus = func(_ context.Context) ([]nexushook.ProjectStatus, error) {
+		return []nexushook.ProjectStatus{
+			{Organization: "org", Name: "web", UUID: "uuid-web", Status: "STATUS_INDICATION_IDLE"},
+			{Organization: "org", Name: "gone", UUID: "uuid-gone", Status: "STATUS_INDICATION_IDLE", Deleted: true},
+		}, nil
+	}
+	defer func() { listProjectStatus = defaultListProjectStatus }()
+
+	manager.eventChan = make(chan plugins.Event, 1)
+	go manager.eventWorker(0)
+	defer manager.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s.NoError(manager.reprovisionProject(ctx, "uuid-web", []string{"catalog"}))
+	s.Eventually(func() bool { return len(catalog.recorded()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	s.Equal([]string{"ensure web"}, catalog.recorded())
+	s.Empty(harbor.recorded())
+
+	s.ErrorIs(manager.reprovisionProject(ctx, "uuid-web", []string{"gitops"}), history.ErrInvalidRequest)
+	s.ErrorIs(manager.reprovisionProject(ctx, "uuid-gone", nil), history.ErrProjectNotFound)
+	s.ErrorIs(manager.reprovisionProject(ctx, "uuid-unknown", nil), history.ErrProjectNotFound)
+
+	// Without plugins the project is converged by all of them
+	s.NoError(manager.reprovisionProject(ctx, "uuid-web", nil))
+	s.Eventually(func() bool { return len(catalog.recorded()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	s.Equal([]string{"ensure web"}, harbor.recorded())
+}
+
+type deactivatingPlugin struct {
+	recordingPlugin
+}
+
+func (p *deactivatingPlugin) DeactivateEvent(_ context.Context, event plugins.Event, _ *plugins.PluginData) error {
+	p.record(event)
+	return nil
+}
+
+func (p *deactivatingPlugin) ReactivateEvent(_ context.Context, event plugins.Event, _ *plugins.PluginData) error {
+	p.record(event)
+	return nil
+}
+
+// memoryInventoryStore keeps the inventories of the projects in memory
+type memoryInventoryStore struct {
+	mu          sync.Mutex
+	inventories map[string]southbound.Inventory
+}
+
+func (m *memoryInventoryStore) Save(_ context.Context, inventory *southbound.Inventory) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inventories[inventory.UUID] = *inventory
+	return nil
+}
+
+func (m *memoryInventoryStore) Load(_ context.Context, uuid string) (*southbound.Inventory, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inventory, ok := m.inventories[uuid]
+	if !ok {
+		return nil, nil
+	}
+	return &inventory, nil
+}
+
+func (m *memoryInventoryStore) List(_ context.Context) ([]*southbound.Inventory, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inventories := []*southbound.Inventory{}
+	for _, inventory := range m.inventories {
+		inventories = append(inventories, &inventory)
+	}
+	return inventories, nil
+}
+
+func (m *memoryInventoryStore) Delete(_ context.Context, uuid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.inventories, uuid)
+	return nil
+}
+
+func (s *ManagerTestSuite) TestSoftDeletion() {
+	configuration := config.Configuration{
+		InitialSleepInterval: 100 * time.Millisecond,
+		MaxWaitTime:          time.Second,
+		PodNamespace:         "orch-app",
+		DeletionMode:         config.DeletionModeSoft,
+		DeletionRetention:    time.Hour,
+	}
+	manager := NewManager(configuration)
+	store := &memoryInventoryStore{inventories: map[string]southbound.Inventory{}}
+	plugins.InventoryStoreFactory = func(_ config.Configuration) (plugins.InventoryStore, error) { return store, nil }
+	defer func() { plugins.InventoryStoreFactory = plugins.NewInventoryStore }()
+	plugin := &deactivatingPlugin{}
+	plugins.RemoveAllPlugins()
+	plugins.Register(plugin)
+	plugins.Register(plugins.NewInventoryRecorderPlugin(configuration))
+	defer plugins.RemoveAllPlugins()
+
+	manager.eventChan = make(chan plugins.Event, 1)
+	go manager.eventWorker(0)
+	defer manager.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	deactivation := func() *southbound.InventoryDeactivation {
+		inventory, _ := store.Load(ctx, "uuid-web")
+		if inventory == nil {
+			return nil
+		}
+		return inventory.Deactivation
+	}
+
+	// The resources of a deleted project are deactivated and kept for the retention period
+	deleted := time.Now()
+	s.NoError(manager.DeleteProject(ctx, "org", "web", "uuid-web", nil))
+	s.Eventually(func() bool { return deactivation() != nil }, 5*time.Second, 10*time.Millisecond)
+	s.Equal([]string{"deactivate web"}, plugin.recorded())
+	s.WithinDuration(deleted.Add(time.Hour), deactivation().PurgeAfter, time.Minute)
+	s.NoError(manager.purgeExpired(ctx, time.Now()))
+	s.Never(func() bool { return len(plugin.recorded()) > 1 }, 100*time.Millisecond, 10*time.Millisecond)
+
+	// The deletion is undone within the retention period
+	s.NoError(manager.restoreProject(ctx, "uuid-web"))
+	s.Eventually(func() bool { return deactivation() == nil }, 5*time.Second, 10*time.Millisecond)
+	s.Equal([]string{"deactivate web", "reactivate web"}, plugin.recorded())
+	s.ErrorIs(manager.restoreProject(ctx, "uuid-web"), history.ErrProjectNotFound)
+	s.ErrorIs(manager.restoreProject(ctx, "uuid-unknown"), history.ErrProjectNotFound)
+
+	// Once the retention period is over the resources are deleted, and cannot be restored
+	s.NoError(manager.DeleteProject(ctx, "org", "web", "uuid-web", nil))
+	s.Eventually(func() bool { return deactivation() != nil }, 5*time.Second, 10*time.Millisecond)
+	s.NoError(manager.purgeExpired(ctx, time.Now().Add(2*time.Hour)))
+	s.Eventually(func() bool { return len(plugin.recorded()) == 4 }, 5*time.Second, 10*time.Millisecond)
+	s.Equal("delete web", plugin.recorded()[3])
+	s.Eventually(func() bool { return deactivation() == nil }, 5*time.Second, 10*time.Millisecond)
+
+	s.NoError(store.Save(ctx, &southbound.Inventory{Organization: "org", Project: "old", UUID: "uuid-old",
+		Deactivation: &southbound.InventoryDeactivation{PurgeAfter: time.Now().Add(-time.Minute)}}))
+	s.ErrorIs(manager.restoreProject(ctx, "uuid-old"), history.ErrRetentionExpired)
+
+	// Projects are not restored in the hard deletion mode
+	s.ErrorIs(NewManager(config.Configuration{DeletionMode: config.DeletionModeHard}).restoreProject(ctx, "uuid-old"),
+		history.ErrProjectNotFound)
+}
+
+func (s *ManagerTestSuite) TestEnqueueFullQueue() {
+	manager := NewManager(config.Configuration{
+		InitialSleepInterval: 100 * time.Millisecond,
+		MaxWaitTime:          time.Second,
+	})
+	plugin := &recordingPlugin{}
+	plugins.RemoveAllPlugins()
+	plugins.Register(plugin)
+	defer plugins.RemoveAllPlugins()
+
+	manager.eventChan = make(chan plugins.Event, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	saturated := testutil.ToFloat64(eventQueueSaturated)
+
+	s.NoError(manager.CreateProject(ctx, "org", "first", "uuid-first", nil))
+	s.Equal(float64(1), testutil.ToFloat64(eventQueueDepth))
+	s.Equal(saturated, testutil.ToFloat64(eventQueueSaturated))
+
+	// Without a free slot the event is not accepted
+	shortCtx, shortCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer shortCancel()
+	s.ErrorIs(manager.CreateProject(shortCtx, "org", "second", "uuid-second", nil), context.DeadlineExceeded)
+	s.Equal(saturated+1, testutil.ToFloat64(eventQueueSaturated))
+	s.Equal(float64(0), testutil.ToFloat64(eventQueueBlocked))
+
+	// A held back event is accepted once a worker frees a slot
+	done := make(chan error)
+	go func() { done <- manager.CreateProject(ctx, "org", "third", "uuid-third", nil) }()
+	s.Eventually(func() bool { return testutil.ToFloat64(eventQueueBlocked) == 1 }, 5*time.Second, 10*time.Millisecond)
+	go manager.eventWorker(0)
+	defer manager.Close()
+	s.NoError(<-done)
+	s.Eventually(func() bool { return len(plugin.recorded()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	s.Equal([]string{"create first", "create third"}, plugin.recorded())
+	s.Equal(saturated+2, testutil.ToFloat64(eventQueueSaturated))
+	s.Equal(float64(0), testutil.ToFloat64(eventQueueBlocked))
+}
+
+func (s *ManagerTestSuite) TestEnqueueHeldBackAtShutdown() {
+	manager := NewManager(config.Configuration{
+		InitialSleepInterval: 100 * time.Millisecond,
+		MaxWaitTime:          time.Second,
+	})
+	plugins.RemoveAllPlugins()
+	plugins.Register(&recordingPlugin{})
+	defer plugins.RemoveAllPlugins()
+
+	manager.eventChan = make(chan plugins.Event, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s.NoError(manager.CreateProject(ctx, "org", "first", "uuid-first", nil))
+
+	// An event waits for a free slot, and an event for the same project is held back behind it
+	shortCtx, shortCancel := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() { done <- manager.CreateProject(shortCtx, "org", "second", "uuid-second", nil) }()
+	s.Eventually(func() bool { return testutil.ToFloat64(eventQueueBlocked) == 1 }, 5*time.Second, 10*time.Millisecond)
+	s.NoError(manager.DeleteProject(ctx, "org", "second", "uuid-second", nil))
+
+	// The waiting event is given up and the held back event waits for a free slot in its place
+	shortCancel()
+	s.ErrorIs(<-done, context.Canceled)
+	event, ok := manager.projects.activeEvent("uuid-second")
+	s.True(ok)
+	s.Equal("delete", event)
+
+	// Without a worker, the held back event is dropped when the manager is closed
+	manager.Close()
+	_, ok = manager.projects.activeEvent("uuid-second")
+	s.False(ok)
+}
+
+func (s *ManagerTestSuite) TestRunLoadTest() {
+	manager := NewManager(config.Configuration{
+		InitialSleepInterval: 100 * time.Millisecond,
+		MaxWaitTime:          time.Second,
+		NumberWorkerThreads:  3,
+	})
+	plugin := &recordingPlugin{}
+	plugins.RemoveAllPlugins()
+	plugins.Register(plugin)
+	defer plugins.RemoveAllPlugins()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	phases, err := manager.RunLoadTest(ctx, LoadTest{Organization: "org", Projects: 20})
+	s.NoError(err)
+	s.Len(phases, 2)
+	for i, eventType := range []string{"create", "delete"} {
+		s.Equal(eventType, phases[i].EventType)
+		s.Equal(20, phases[i].Events)
+		s.Len(phases[i].Latencies, 20)
+		s.Empty(phases[i].Failures)
+		s.LessOrEqual(phases[i].Percentile(50), phases[i].Percentile(99))
+		s.Equal(phases[i].Latencies[19], phases[i].Percentile(100))
+		s.Greater(phases[i].Throughput(), float64(0))
+	}
+	recorded := plugin.recorded()
+	s.Len(recorded, 40)
+	s.Contains(recorded[:20], "create "+LoadTestProjectName(19))
+	s.Contains(recorded[20:], "delete "+LoadTestProjectName(0))
+
+	_, err = NewManager(config.Configuration{NumberWorkerThreads: 1}).RunLoadTest(ctx, LoadTest{Organization: "org"})
+	s.ErrorContains(err, "at least 1 project")
+}
+
+func (s *ManagerTestSuite) TestLoadTestPercentile() {
+	phase := LoadTestPhase{Latencies: []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}}
+	s.Equal(time.Duration(5), phase.Percentile(50))
+	s.Equal(time.Duration(9), phase.Percentile(90))
+	s.Equal(time.Duration(10), phase.Percentile(99))
+	s.Equal(time.Duration(1), phase.Percentile(0))
+	s.Equal(time.Duration(0), LoadTestPhase{}.Percentile(50))
+	s.Equal("00000000-0000-4000-8000-000000000042", LoadTestProjectUUID(42))
+}
+
+func (s *ManagerTestSuite) TestRecordVersion() {
+	ctx := context.Background()
+	started := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	configuration := config.Configuration{ControllerVersion: "0.6.5", ManifestTag: "v1.2.3"}
+	configMaps := fake.NewClientset().CoreV1().ConfigMaps("orch-app")
+
+	s.NoError(writeVersion(ctx, configMaps, versionData(configuration, started)))
+	configMap, err := configMaps.Get(ctx, VersionConfigMap, metaV1.GetOptions{})
+	s.NoError(err)
+	s.Equal(map[string]string{
+		VersionKeyController:  "0.6.5",
+		VersionKeyManifestTag: "v1.2.3",
+		VersionKeyCatalogAPI:  "catalog.v3.CatalogService",
+		VersionKeyADMAPI:      "deployment.v1.DeploymentService",
+		VersionKeyStarted:     "2026-03-04T05:06:07Z",
+	}, configMap.Data)
+
+	// The record is replaced on restart, and only names the APIs of the enabled plugins
+	configuration.ControllerVersion = "0.6.6"
+	configuration.DisabledPlugins = []string{config.PluginExtensions}
+	s.NoError(writeVersion(ctx, configMaps, versionData(configuration, started)))
+	configMap, err = configMaps.Get(ctx, VersionConfigMap, metaV1.GetOptions{})
+	s.NoError(err)
+	s.Equal("0.6.6", configMap.Data[VersionKeyController])
+	s.NotContains(configMap.Data, VersionKeyManifestTag)
+	s.NotContains(configMap.Data, VersionKeyADMAPI)
+	s.Contains(configMap.Data, VersionKeyCatalogAPI)
+}
+
+func (s *ManagerTestSuite) TestWatchKeycloakSecret() {
+	var watched []config.SecretRef
+	var onChange func(ctx context.Context)
+	watchSecret = func(_ context.Context, ref config.SecretRef, changed func(ctx context.Context)) error {
+		watched = append(watched, ref)
+		onChange = changed
+		return nil
+	}
+	defer func() { watchSecret = southbound.WatchSecret }()
+
+	// Without a Keycloak secret there is nothing to watch
+	m := &Manager{ctx: context.Background()}
+	m.watchKeycloakSecret()
+	s.Empty(watched)
+
+	ref := config.SecretRef{Namespace: "orch-platform", Name: "platform-keycloak", Key: "admin-password"}
+	m.Config.Secrets.KeycloakAdmin = ref
+	m.watchKeycloakSecret()
+	s.Equal([]config.SecretRef{ref}, watched)
+	plugins.RemoveAllPlugins()
+	onChange(context.Background())
+
+	// A secret that cannot be watched does not stop the controller
+	watchSecret = func(_ context.Context, _ config.SecretRef, _ func(ctx context.Context)) error {
+		return errors.New("forbidden")
+	}
+	m.watchKeycloakSecret()
+}
+
+func (s *ManagerTestSuite) TestWatchHarborSecret() {
+	var watched []config.SecretRef
+	var onChange func(ctx context.Context)
+	watchSecret = func(_ context.Context, ref config.SecretRef, changed func(ctx context.Context)) error {
+		watched = append(watched, ref)
+		onChange = changed
+		return nil
+	}
+	defer func() { watchSecret = southbound.WatchSecret }()
+
+	// Without a Harbor admin secret there is nothing to watch
+	m := &Manager{ctx: context.Background()}
+	m.watchHarborSecret()
+	s.Empty(watched)
+
+	// Nor if the Harbor plugin is disabled
+	ref := config.SecretRef{Namespace: "orch-harbor", Name: "harbor-admin-credential", Key: "credential"}
+	m.Config.Secrets.HarborAdmin = ref
+	m.Config.DisabledPlugins = []string{config.PluginHarbor}
+	m.watchHarborSecret()
+	s.Empty(watched)
+
+	m.Config.DisabledPlugins = nil
+	m.watchHarborSecret()
+	s.Equal([]config.SecretRef{ref}, watched)
+	plugins.RemoveAllPlugins()
+	onChange(context.Background())
+
+	// A secret that cannot be watched does not stop the controller
+	watchSecret = func(_ context.Context, _ config.SecretRef, _ func(ctx context.Context)) error {
+		return errors.New("forbidden")
+	}
+	m.watchHarborSecret()
+}
+
+func (s *ManagerTestSuite) TestCheckAPIs() {
+	ctx := context.Background()
+	checked := []string{}
+	check := func(service string, err error) apiCheck {
+		return apiCheck{service: service, check: func(context.Context) error {
+			checked = append(checked, service)
+			return err
+		}}
+	}
+
+	// Unreachable services do not prevent the controller from starting
+	s.NoError(checkAPIs(ctx, []apiCheck{
+		check("catalog", nil),
+		check("adm", fmt.Errorf("%w: connection refused", southbound.ErrTransient)),
+	}))
+	s.Equal([]string{"catalog", "adm"}, checked)
+
+	err := checkAPIs(ctx, []apiCheck{
+		check("catalog", fmt.Errorf("%w: unknown service", southbound.ErrPermanent)),
+		check("adm", nil),
+	})
+	s.ErrorContains(err, "incompatible catalog service")
+	s.Equal([]string{"catalog", "adm", "catalog"}, checked)
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package manager
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// The metrics of every package are registered with the controller-runtime registry and served by its metrics server
+var (
+	eventQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "tenant_controller_event_queue_depth",
+		Help: "Project events in the queue waiting for a free worker",
+	})
+
+	eventQueueCapacity = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "tenant_controller_event_queue_capacity",
+		Help: "Number of project events the queue can hold",
+	})
+
+	eventQueueBlocked = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "tenant_controller_event_queue_blocked",
+		Help: "Project events held back because the queue is full",
+	})
+
+	eventQueueSaturated = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "tenant_controller_event_queue_saturated_total",
+		Help: "Project events that found the queue full and had to wait for a free slot",
+	})
+
+	eventBacklog = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "tenant_controller_event_backlog",
+		Help: "Project events received and not finished yet: queued, held back or being handled",
+	})
+
+	eventWorkers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "tenant_controller_event_workers",
+		Help: "Number of workers handling project events",
+	})
+
+	eventWorkersBusy = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "tenant_controller_event_workers_busy",
+		Help: "Workers handling a project event",
+	})
+
+	// latencies of the finished events, reported as their average by eventLatencyAverage
+	eventLatencies = newLatencyWindow(eventLatencyWindow)
+
+	eventLatencyAverage = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "tenant_controller_event_latency_average_seconds",
+		Help: "Average time from receiving a project event until it was handled, over the events finished in the last 5 minutes",
+	}, func() float64 { return eventLatencies.average(time.Now()).Seconds() })
+
+	refusedEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tenant_controller_refused_events_total",
+		Help: "Project events refused because a delete event of the project was queued, being handled or handled recently, by event type",
+	}, []string{"event_type"})
+
+	stuckEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tenant_controller_stuck_events_total",
+		Help: "Project events cancelled by the watchdog for taking longer than the stuck event timeout, by plugin",
+	}, []string{"plugin"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(eventQueueDepth, eventQueueCapacity, eventQueueBlocked, eventQueueSaturated, eventBacklog,
+		eventWorkers, eventWorkersBusy, eventLatencyAverage, refusedEvents, stuckEvents)
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/events"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/history"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/migration"
+	nexushook "github.com/open-edge-platform/app-orch-tenant-controller/internal/nexus"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/plugins"
+	k8sconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// migrationSteps are applied once to the projects provisioned by earlier controller versions. New steps are added
+// at the end; the ID of a released step must never change, or it runs again.
+func (m *Manager) migrationSteps() []migration.Step {
+	return []migration.Step{
+		{
+			ID:          "0001-harbor-pull-robot",
+			Description: "create the pull-only Harbor robot and switch the harbor-docker-oci registry to it",
+			Apply:       m.reprovision,
+		},
+	}
+}
+
+// runMigrations applies the pending migrations to the existing projects. Migrations that do not complete are
+// resumed on the next start.
+func (m *Manager) runMigrations() {
+	store, err := migration.NewConfigMapStore(m.Config.PodNamespace)
+	if err != nil {
+		log.Errorf("Unable to run migrations: %v", err)
+		return
+	}
+	runner := migration.NewRunner(store, m.Config.ControllerVersion, m.migrationProjects).WithSteps(m.migrationSteps()...)
+	if err := runner.Run(m.ctx); err != nil {
+		log.Errorf("Migrations did not complete: %v", err)
+	}
+}
+
+// listProjectStatus reads the status of every project from the Kubernetes API, it is replaced in tests
+var listProjectStatus = func(ctx context.Context) ([]nexushook.ProjectStatus, error) {
+	cfg, err := k8sconfig.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	return nexushook.ListProjectStatus(ctx, cfg)
+}
+
+// migrationProjects lists the projects provisioned by the controller that still exist.
+func (m *Manager) migrationProjects(ctx context.Context) ([]nexushook.ProjectStatus, error) {
+	statuses, err := listProjectStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	projects := []nexushook.ProjectStatus{}
+	for _, status := range statuses {
+		if !status.Deleted && status.Status != "" {
+			projects = append(projects, status)
+		}
+	}
+	return projects, nil
+}
+
+// reprovision queues a create event for the project, with new Harbor robot credentials, and waits until the
+// workers are done with it.
+func (m *Manager) reprovision(ctx context.Context, project nexushook.ProjectStatus) error {
+	e, err := m.pluginEvent(events.UpdateManifestV1{
+		Project: events.ProjectV1{
+			Organization: project.Organization,
+			Name:         project.Name,
+			UUID:         project.UUID,
+			Labels:       project.Labels,
+			Annotations:  project.Annotations,
+		},
+		Profile:            project.Profile,
+		RefreshCredentials: true,
+	})
+	if err != nil {
+		return err
+	}
+	e.Lifecycle = plugins.NewLifecycle(context.Background())
+	if err := m.enqueue(ctx, e); err != nil {
+		return err
+	}
+	return waitForEvent(ctx, e)
+}
+
+// reprovisionProject queues an ensure event for a provisioned project, limited to the named plugins, for the
+// history API. It does not wait for the event, whose progress the status of the project shows.
+func (m *Manager) reprovisionProject(ctx context.Context, uuid string, names []string) error {
+	if err := plugins.CheckPluginNames(names); err != nil {
+		return fmt.Errorf("%w: %w", history.ErrInvalidRequest, err)
+	}
+	projects, err := m.migrationProjects(ctx)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(projects, func(project nexushook.ProjectStatus) bool { return project.UUID == uuid })
+	if i < 0 {
+		return fmt.Errorf("%w: no provisioned project has UUID %s", history.ErrProjectNotFound, uuid)
+	}
+	project := projects[i]
+	return m.HandleProjectEvent(ctx, events.EnsureProjectV1{
+		Project: events.ProjectV1{
+			Organization: project.Organization,
+			Name:         project.Name,
+			UUID:         project.UUID,
+			Labels:       project.Labels,
+			Annotations:  project.Annotations,
+		},
+		Plugins: names,
+	})
+}
+
+// waitForEvent waits until the workers are done with a queued event and returns its error. The event is cancelled
+// if the context is done first.
+func waitForEvent(ctx context.Context, e plugins.Event) error {
+	select {
+	case <-e.Lifecycle.Context().Done():
+	case <-ctx.Done():
+		e.Lifecycle.Cancel()
+		return ctx.Err()
+	}
+	switch e.Lifecycle.Phase() {
+	case plugins.PhaseCompleted:
+		return nil
+	case plugins.PhaseFailed:
+		return e.Lifecycle.Err()
+	default:
+		return fmt.Errorf("%s event for project %s was %s", e.EventType, e.Name, e.Lifecycle)
+	}
+}
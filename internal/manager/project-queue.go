@@ -0,0 +1,215 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"sync"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/plugins"
+)
+
+const (
+	// deletedProjectTTL is how long the events of a deleted project are refused after its delete event completed
+	deletedProjectTTL = time.Hour
+	// maxDeletedProjects bounds the deleted projects remembered, the oldest are forgotten first
+	maxDeletedProjects = 10000
+)
+
+// projectQueue holds the event of a project that is being handled and the events received after it
+type projectQueue struct {
+	active  plugins.Event
+	pending []plugins.Event
+}
+
+// projectQueues serializes the events of each project. While an event for a project is being handled, later
+// events for the same project are held back and handed to the worker that handles the active event, in the
+// order they were received. Events for different projects are handled concurrently.
+//
+// A delete event, or a deactivate event in the soft deletion mode, cancels the create and update events of the
+// project received before it, including the active one, since their result would be removed by the delete anyway.
+// Conversely, the events received after it are refused rather than held back, so that a late or replayed event does
+// not provision the resources of the deleted project again. Only a reactivate event may follow a deactivate event.
+// Once completed, the delete is remembered for deletedProjectTTL, so that events received afterwards are refused too.
+type projectQueues struct {
+	mu sync.Mutex
+	// keyed by project UUID. A project has an entry while one of its events is queued or being handled.
+	queues map[string]*projectQueue
+	// the projects whose last handled event was a delete or deactivate event, keyed by project UUID
+	deleted map[string]deletedProject
+	now     func() time.Time
+}
+
+// deletedProject is a project deleted by a delete or deactivate event.
+type deletedProject struct {
+	eventType string
+	handled   time.Time
+}
+
+func newProjectQueues() *projectQueues {
+	return &projectQueues{
+		queues:  map[string]*projectQueue{},
+		deleted: map[string]deletedProject{},
+		now:     time.Now,
+	}
+}
+
+// acquire marks the event as the active event of its project and returns true, or holds it back and returns
+// false if the project already has an active event. An event refused for following a deletion is cancelled, and
+// false is returned as well.
+func (q *projectQueues) acquire(event plugins.Event) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	queue, active := q.queues[event.UUID]
+	defer q.updateBacklog()
+	if !active {
+		if deleted, ok := q.recentlyDeleted(event.UUID); ok && refusedAfter(event, deleted.eventType) {
+			event.Lifecycle.Cancel()
+			refusedEvents.WithLabelValues(event.EventType).Inc()
+			log.Warnf("Refusing %s event for project %s, the project was removed by a %s event %s ago", event.EventType,
+				event.Name, deleted.eventType, q.now().Sub(deleted.handled).Round(time.Second))
+			return false
+		}
+		q.queues[event.UUID] = &projectQueue{active: event}
+		return true
+	}
+
+	if deleted, ok := queue.deletion(); ok && refusedAfter(event, deleted.EventType) {
+		event.Lifecycle.Cancel()
+		refusedEvents.WithLabelValues(event.EventType).Inc()
+		log.Warnf("Refusing %s event for project %s, a %s event of the project is queued", event.EventType, event.Name, deleted.EventType)
+		return false
+	}
+	if deletion(event) {
+		if !deletion(queue.active) && queue.active.Lifecycle.Cancel() {
+			log.Infof("Cancelling %s event for project %s, the project is being deleted", queue.active.EventType, event.Name)
+		}
+		pending := queue.pending[:0]
+		for _, p := range queue.pending {
+			if !deletion(p) && p.Lifecycle.Cancel() {
+				log.Infof("Dropping %s event for project %s, the project is being deleted", p.EventType, event.Name)
+				continue
+			}
+			pending = append(pending, p)
+		}
+		queue.pending = pending
+	}
+	log.Infof("Holding back %s event for project %s until the previous event is done", event.EventType, event.Name)
+	queue.pending = append(queue.pending, event)
+	return false
+}
+
+// deletion returns the latest delete or deactivate event of the project, active or held back, unless a reactivate
+// event follows it.
+func (queue *projectQueue) deletion() (plugins.Event, bool) {
+	var deleted plugins.Event
+	found := false
+	for _, e := range append([]plugins.Event{queue.active}, queue.pending...) {
+		switch {
+		case deletion(e):
+			deleted, found = e, true
+		case e.EventType == "reactivate":
+			found = false
+		}
+	}
+	return deleted, found
+}
+
+// deletion reports whether the event is for a deleted project.
+func deletion(event plugins.Event) bool {
+	return event.EventType == "delete" || event.EventType == "deactivate"
+}
+
+// refusedAfter reports whether the event is refused after a delete or deactivate event of the given type.
+func refusedAfter(event plugins.Event, deletedType string) bool {
+	return !deletion(event) && (event.EventType != "reactivate" || deletedType != "deactivate")
+}
+
+// recentlyDeleted returns the delete or deactivate event the project was last removed by, unless it was handled more
+// than deletedProjectTTL ago. The caller holds the lock.
+func (q *projectQueues) recentlyDeleted(uuid string) (deletedProject, bool) {
+	deleted, ok := q.deleted[uuid]
+	if ok && q.now().Sub(deleted.handled) > deletedProjectTTL {
+		delete(q.deleted, uuid)
+		return deletedProject{}, false
+	}
+	return deleted, ok
+}
+
+// remember records that a completed delete or deactivate event removed the project, or that a completed reactivate
+// event restored it. A failed or dropped event changed nothing and is not remembered. The caller holds the lock.
+func (q *projectQueues) remember(event plugins.Event) {
+	if event.Lifecycle.Phase() != plugins.PhaseCompleted {
+		return
+	}
+	switch {
+	case deletion(event):
+		now := q.now()
+		if len(q.deleted) >= maxDeletedProjects {
+			q.forget(now)
+		}
+		q.deleted[event.UUID] = deletedProject{eventType: event.EventType, handled: now}
+	case event.EventType == "reactivate":
+		delete(q.deleted, event.UUID)
+	}
+}
+
+// forget drops the deleted projects handled more than deletedProjectTTL ago and, if there are still too many, the
+// oldest one. The caller holds the lock.
+func (q *projectQueues) forget(now time.Time) {
+	oldest := ""
+	for uuid, deleted := range q.deleted {
+		if now.Sub(deleted.handled) > deletedProjectTTL {
+			delete(q.deleted, uuid)
+		} else if oldest == "" || deleted.handled.Before(q.deleted[oldest].handled) {
+			oldest = uuid
+		}
+	}
+	if len(q.deleted) >= maxDeletedProjects {
+		delete(q.deleted, oldest)
+	}
+}
+
+// release ends the active event of a project, remembering it if it removed the project. If events were held back,
+// the oldest one becomes the active event and is returned.
+func (q *projectQueues) release(uuid string) (plugins.Event, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	defer q.updateBacklog()
+	queue := q.queues[uuid]
+	if queue != nil {
+		q.remember(queue.active)
+	}
+	if queue == nil || len(queue.pending) == 0 {
+		delete(q.queues, uuid)
+		return plugins.Event{}, false
+	}
+	queue.active = queue.pending[0]
+	queue.pending = queue.pending[1:]
+	return queue.active, true
+}
+
+// updateBacklog reports the events of all the projects, active and held back. The caller holds the lock.
+func (q *projectQueues) updateBacklog() {
+	backlog := 0
+	for _, queue := range q.queues {
+		backlog += 1 + len(queue.pending)
+	}
+	eventBacklog.Set(float64(backlog))
+}
+
+// activeEvent returns the type of the latest event of the project that is queued or being handled, or false if the
+// project has none.
+func (q *projectQueues) activeEvent(uuid string) (string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	queue := q.queues[uuid]
+	if queue == nil {
+		return "", false
+	}
+	if len(queue.pending) > 0 {
+		return queue.pending[len(queue.pending)-1].EventType, true
+	}
+	return queue.active.EventType, true
+}
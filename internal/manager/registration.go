@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	coreV1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	coreV1Types "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+// VersionConfigMap is the name of the ConfigMap, in the controller namespace, that records the version of the
+// running controller for the orchestrator upgrade tooling. It is rewritten every time the controller starts.
+const VersionConfigMap = "app-orch-tenant-controller-version"
+
+// Keys of the VersionConfigMap
+const (
+	VersionKeyController  = "controllerVersion"
+	VersionKeyManifestTag = "manifestTag"
+	VersionKeyCatalogAPI  = "catalogAPI"
+	VersionKeyADMAPI      = "admAPI"
+	VersionKeyStarted     = "startedAt"
+)
+
+// versionData is the content of the VersionConfigMap for a controller started at the given time.
+func versionData(configuration config.Configuration, started time.Time) map[string]string {
+	data := map[string]string{
+		VersionKeyController: configuration.ControllerVersion,
+		VersionKeyStarted:    started.UTC().Format(time.RFC3339),
+	}
+	if configuration.PluginEnabled(config.PluginExtensions) {
+		data[VersionKeyManifestTag] = configuration.ManifestTag
+	}
+	for _, check := range apiChecks(configuration) {
+		data[check.key] = check.api
+	}
+	return data
+}
+
+// writeVersion creates or replaces the VersionConfigMap.
+func writeVersion(ctx context.Context, configMaps coreV1Types.ConfigMapInterface, data map[string]string) error {
+	configMap, err := configMaps.Get(ctx, VersionConfigMap, metaV1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		configMap = &coreV1.ConfigMap{ObjectMeta: metaV1.ObjectMeta{Name: VersionConfigMap}, Data: data}
+		_, err = configMaps.Create(ctx, configMap, metaV1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	configMap.Data = data
+	_, err = configMaps.Update(ctx, configMap, metaV1.UpdateOptions{})
+	return err
+}
+
+// recordVersion writes the VersionConfigMap. The upgrade tooling is not needed to provision projects, so a
+// failure is only logged.
+func (m *Manager) recordVersion(ctx context.Context) {
+	if m.Config.PodNamespace == "" {
+		log.Warn("Controller namespace is not known, not recording the controller version")
+		return
+	}
+	restConfig, err := rest.InClusterConfig()
+	if err != nil {
+		log.Warnf("Unable to record the controller version: %v", err)
+		return
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		log.Warnf("Unable to record the controller version: %v", err)
+		return
+	}
+	configMaps := clientset.CoreV1().ConfigMaps(m.Config.PodNamespace)
+	if err := writeVersion(ctx, configMaps, versionData(m.Config, time.Now())); err != nil {
+		log.Warnf("Unable to record the controller version: %v", err)
+		return
+	}
+	log.Infof("Recorded controller version %s in ConfigMap %s", m.Config.ControllerVersion, VersionConfigMap)
+}
+
+// apiCheckTimeout bounds the check of each service, the gRPC clients retry unavailable services until their
+// context is done
+const apiCheckTimeout = 30 * time.Second
+
+// apiCheck checks that a southbound service serves the API the controller is built against
+type apiCheck struct {
+	service string
+	api     string
+	// key of the API in the VersionConfigMap
+	key   string
+	check func(ctx context.Context) error
+}
+
+// apiChecks returns the checks of the services used by the enabled plugins.
+func apiChecks(configuration config.Configuration) []apiCheck {
+	var checks []apiCheck
+	if configuration.PluginEnabled(config.PluginCatalog) || configuration.PluginEnabled(config.PluginExtensions) {
+		checks = append(checks, apiCheck{
+			service: southbound.ServiceCatalog,
+			api:     southbound.CatalogAPI,
+			key:     VersionKeyCatalogAPI,
+			check: func(ctx context.Context) error {
+				catalog, err := southbound.NewAppCatalog(configuration)
+				if err != nil {
+					return err
+				}
+				return catalog.CheckAPI(ctx)
+			},
+		})
+	}
+	if configuration.PluginEnabled(config.PluginExtensions) {
+		checks = append(checks, apiCheck{
+			service: southbound.ServiceADM,
+			api:     southbound.ADMAPI,
+			key:     VersionKeyADMAPI,
+			check: func(ctx context.Context) error {
+				adm, err := southbound.NewAppDeployment(configuration)
+				if err != nil {
+					return err
+				}
+				return adm.CheckAPI(ctx)
+			},
+		})
+	}
+	return checks
+}
+
+// checkAPIs returns an error if a service does not serve the API the controller is built against. Services that
+// cannot be reached are not known to be incompatible, the controller starts and retries its calls to them later.
+func checkAPIs(ctx context.Context, checks []apiCheck) error {
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, apiCheckTimeout)
+		err := check.check(checkCtx)
+		cancel()
+		switch {
+		case err == nil:
+			log.Infof("The %s service serves the %s API", check.service, check.api)
+		case errors.Is(err, southbound.ErrPermanent):
+			return fmt.Errorf("incompatible %s service, refusing to start: %w", check.service, err)
+		default:
+			log.Warnf("Unable to check the API of the %s service: %v", check.service, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/plugins"
+)
+
+// maxStuckRequeues is the number of times in a row an event of a project is requeued after getting stuck. A
+// project whose events keep getting stuck is left in error until its next event.
+const maxStuckRequeues = 3
+
+// maxWatchdogInterval bounds the time between checks for stuck events
+const maxWatchdogInterval = 30 * time.Second
+
+// inFlightEvent is an event being handled by a worker
+type inFlightEvent struct {
+	event   plugins.Event
+	started time.Time
+	cancel  context.CancelCauseFunc
+	// set once the watchdog has found the event stuck
+	stuck bool
+}
+
+// watchdog tracks the events being handled by the workers, so that events that take longer than the timeout can
+// be found and cancelled. A plugin that waits on a call that never returns would otherwise hold its worker and the
+// events of its project forever.
+type watchdog struct {
+	mu      sync.Mutex
+	timeout time.Duration
+	// keyed by worker ID
+	events map[int]*inFlightEvent
+	// number of times in a row an event of the project got stuck, keyed by project UUID
+	requeues map[string]int
+}
+
+func newWatchdog(timeout time.Duration) *watchdog {
+	return &watchdog{
+		timeout:  timeout,
+		events:   map[int]*inFlightEvent{},
+		requeues: map[string]int{},
+	}
+}
+
+// enabled returns false if events are never considered stuck.
+func (w *watchdog) enabled() bool {
+	return w.timeout > 0
+}
+
+// interval returns the time between checks for stuck events.
+func (w *watchdog) interval() time.Duration {
+	return min(w.timeout/4, maxWatchdogInterval)
+}
+
+// start records that the worker started handling the event, with a context cancelled by the given function.
+func (w *watchdog) start(worker int, event plugins.Event, cancel context.CancelCauseFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.events[worker] = &inFlightEvent{event: event, started: time.Now(), cancel: cancel}
+}
+
+// done records that the worker is done with its event.
+func (w *watchdog) done(worker int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	inFlight := w.events[worker]
+	if inFlight == nil {
+		return
+	}
+	if !inFlight.stuck {
+		delete(w.requeues, inFlight.event.UUID)
+	}
+	delete(w.events, worker)
+}
+
+// stuckEvent describes an event found stuck by the watchdog
+type stuckEvent struct {
+	worker  int
+	event   plugins.Event
+	elapsed time.Duration
+	cancel  context.CancelCauseFunc
+	// false if the event of the project got stuck too many times in a row
+	requeue bool
+}
+
+// stuck returns the events that have been handled for longer than the timeout at the given time. Each event is
+// returned once.
+func (w *watchdog) stuck(now time.Time) []stuckEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var stuck []stuckEvent
+	for worker, inFlight := range w.events {
+		elapsed := now.Sub(inFlight.started)
+		if inFlight.stuck || elapsed < w.timeout {
+			continue
+		}
+		inFlight.stuck = true
+		w.requeues[inFlight.event.UUID]++
+		stuck = append(stuck, stuckEvent{
+			worker:  worker,
+			event:   inFlight.event,
+			elapsed: elapsed,
+			cancel:  inFlight.cancel,
+			requeue: w.requeues[inFlight.event.UUID] <= maxStuckRequeues,
+		})
+	}
+	return stuck
+}
+
+// errEventStuck is the cause of the cancellation of a stuck event
+type errEventStuck struct {
+	message string
+}
+
+func (e *errEventStuck) Error() string {
+	return e.message
+}
+
+// stuckError describes where the event got stuck, for the project watcher and the logs.
+func stuckError(stuck stuckEvent) error {
+	lifecycle := stuck.event.Lifecycle
+	message := fmt.Sprintf("%s event for project %s stuck for %d seconds on worker %d in %s", stuck.event.EventType,
+		stuck.event.Name, int(stuck.elapsed.Seconds()), stuck.worker, lifecycle)
+	if plugin := lifecycle.Plugin(); plugin != "" {
+		if result := lifecycle.Result().Plugin(plugin); result != nil && result.Attempts > 0 {
+			message += fmt.Sprintf(" after %d attempts", result.Attempts)
+			if result.Err != nil {
+				message += fmt.Sprintf(", last error was %v", result.Err)
+			}
+		}
+	}
+	if stuck.requeue {
+		message += ", cancelled and requeued"
+	} else {
+		message += fmt.Sprintf(", cancelled and not requeued after getting stuck %d times in a row", maxStuckRequeues+1)
+	}
+	return &errEventStuck{message: message}
+}
+
+// runWatchdog checks for stuck events until the context is done.
+func (m *Manager) runWatchdog(ctx context.Context) {
+	defer close(m.watchdogDone)
+	ticker := time.NewTicker(m.watchdog.interval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.checkStuckEvents(ctx, now)
+		}
+	}
+}
+
+// checkStuckEvents cancels the events that are stuck at the given time, reports them on the project watchers and
+// queues them again. A plugin that ignores the cancellation keeps its worker, the requeued event is then handled
+// once the plugin returns.
+func (m *Manager) checkStuckEvents(ctx context.Context, now time.Time) {
+	for _, stuck := range m.watchdog.stuck(now) {
+		err := stuckError(stuck)
+		log.Errorf("Watchdog: %v", err)
+		stuckEvents.WithLabelValues(stuck.event.Lifecycle.Plugin()).Inc()
+		stuck.cancel(err)
+		if stuck.event.Project != nil && m.NexusHook != nil {
+			if watchErr := m.NexusHook.SetWatcherStatusError(stuck.event.Project, err.Error()); watchErr != nil {
+				log.Errorf("Unable to set watcher error status: %v", watchErr)
+			}
+		}
+		if !stuck.requeue {
+			continue
+		}
+		// The requeued event keeps the time it was received, its latency includes the time it was stuck
+		event := stuck.event
+		event.Lifecycle = nil
+		m.requeuers.Add(1)
+		go func() {
+			defer m.requeuers.Done()
+			select {
+			case <-m.closing:
+				return
+			default:
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if err := m.enqueue(ctx, event); err != nil {
+				log.Errorf("Unable to requeue stuck event: %v", err)
+			}
+		}()
+	}
+}
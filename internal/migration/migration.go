@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package migration brings the projects provisioned by an earlier controller version up to date. Migration steps
+// run once, in order, against every existing project when the controller starts. Progress is saved after each
+// project, so that a controller restarted in the middle of a migration resumes it where it stopped, and projects
+// that failed are retried on the next start.
+//
+//nolint:revive // Internal package
+package migration
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	nexushook "github.com/open-edge-platform/app-orch-tenant-controller/internal/nexus"
+	"github.com/open-edge-platform/orch-library/go/dazl"
+)
+
+var log = dazl.GetPackageLogger()
+
+// Step is a migration applied to every existing project. Steps must be idempotent, since a project may be
+// migrated again if the controller stops before its progress is saved.
+type Step struct {
+	// unique and never reused, e.g. 0001-harbor-pull-robot
+	ID          string
+	Description string
+	Apply       func(ctx context.Context, project nexushook.ProjectStatus) error
+}
+
+// State is the migration progress saved between controller runs
+type State struct {
+	// controller version that completed all migrations
+	Version string `json:"version"`
+	// IDs of the steps applied to all projects
+	Completed []string `json:"completed,omitempty"`
+	// step in progress, with the UUIDs of the projects already migrated and the errors of those that failed
+	Step     string            `json:"step,omitempty"`
+	Migrated []string          `json:"migrated,omitempty"`
+	Failed   map[string]string `json:"failed,omitempty"`
+	Updated  time.Time         `json:"updated"`
+}
+
+type Store interface {
+	// Load returns nil if no state was saved yet
+	Load(ctx context.Context) (*State, error)
+	Save(ctx context.Context, state *State) error
+}
+
+// Runner applies the pending migration steps
+type Runner struct {
+	store    Store
+	version  string
+	projects func(ctx context.Context) ([]nexushook.ProjectStatus, error)
+	steps    []Step
+}
+
+// NewRunner creates a runner for the given controller version. The projects function lists the projects to migrate.
+func NewRunner(store Store, version string, projects func(ctx context.Context) ([]nexushook.ProjectStatus, error)) *Runner {
+	return &Runner{
+		store:    store,
+		version:  version,
+		projects: projects,
+	}
+}
+
+// WithSteps adds migration steps, applied in the order they are added.
+func (r *Runner) WithSteps(steps ...Step) *Runner {
+	r.steps = append(r.steps, steps...)
+	return r
+}
+
+// Run applies the steps that were not completed before. It stops at the first step that fails for a project,
+// after trying it on all other projects; the remaining steps run on the next start.
+func (r *Runner) Run(ctx context.Context) error {
+	state, err := r.store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to load migration state: %w", err)
+	}
+	if state == nil {
+		state = &State{}
+	}
+	switch state.Version {
+	case r.version:
+		log.Infof("Controller version %s has not changed", r.version)
+	case "":
+		log.Infof("No previous controller version recorded, checking migrations for version %s", r.version)
+	default:
+		log.Infof("Controller upgraded from version %s to %s", state.Version, r.version)
+	}
+
+	for _, step := range r.steps {
+		if slices.Contains(state.Completed, step.ID) {
+			continue
+		}
+		if err := r.apply(ctx, state, step); err != nil {
+			return err
+		}
+	}
+	if state.Version != r.version {
+		state.Version = r.version
+		return r.save(ctx, state)
+	}
+	return nil
+}
+
+func (r *Runner) apply(ctx context.Context, state *State, step Step) error {
+	if state.Step != step.ID {
+		state.Step = step.ID
+		state.Migrated = nil
+		state.Failed = nil
+	}
+	if state.Failed == nil {
+		state.Failed = map[string]string{}
+	}
+	projects, err := r.projects(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list projects for migration %s: %w", step.ID, err)
+	}
+	log.Infof("Running migration %s on %d projects: %s", step.ID, len(projects), step.Description)
+	for i, project := range projects {
+		if slices.Contains(state.Migrated, project.UUID) {
+			continue
+		}
+		log.Infof("Migration %s: project %s/%s (%d/%d)", step.ID, project.Organization, project.Name, i+1, len(projects))
+		err := step.Apply(ctx, project)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			log.Warnf("Migration %s failed for project %s/%s: %v", step.ID, project.Organization, project.Name, err)
+			state.Failed[project.UUID] = err.Error()
+		} else {
+			state.Migrated = append(state.Migrated, project.UUID)
+			delete(state.Failed, project.UUID)
+		}
+		if err := r.save(ctx, state); err != nil {
+			return err
+		}
+	}
+	if len(state.Failed) > 0 {
+		return fmt.Errorf("migration %s failed for %d of %d projects, it is retried on the next start", step.ID, len(state.Failed), len(projects))
+	}
+
+	log.Infof("Migration %s completed", step.ID)
+	state.Completed = append(state.Completed, step.ID)
+	state.Step = ""
+	state.Migrated = nil
+	state.Failed = nil
+	return r.save(ctx, state)
+}
+
+func (r *Runner) save(ctx context.Context, state *State) error {
+	state.Updated = time.Now().UTC()
+	if err := r.store.Save(ctx, state); err != nil {
+		return fmt.Errorf("unable to save migration state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package migration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	nexushook "github.com/open-edge-platform/app-orch-tenant-controller/internal/nexus"
+	"github.com/stretchr/testify/suite"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Suite of migration tests
+type MigrationTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *MigrationTestSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 1*time.Minute)
+}
+
+func (s *MigrationTestSuite) TearDownTest() {
+	s.cancel()
+}
+
+func TestMigration(t *testing.T) {
+	suite.Run(t, &MigrationTestSuite{})
+}
+
+type memoryStore struct {
+	state *State
+	saves int
+}
+
+func (m *memoryStore) Load(_ context.Context) (*State, error) {
+	if m.state == nil {
+		return nil, nil
+	}
+	state := *m.state
+	return &state, nil
+}
+
+func (m *memoryStore) Save(_ context.Context, state *State) error {
+	saved := *state
+	m.state = &saved
+	m.saves++
+	return nil
+}
+
+var testProjects = []nexushook.ProjectStatus{
+	{Organization: "org", Name: "a", UUID: "uuid-a"},
+	{Organization: "org", Name: "b", UUID: "uuid-b"},
+	{Organization: "org", Name: "c", UUID: "uuid-c"},
+}
+
+func listTestProjects(_ context.Context) ([]nexushook.ProjectStatus, error) {
+	return testProjects, nil
+}
+
+// recordingStep records the projects it is applied to and fails for the projects in failing
+type recordingStep struct {
+	applied []string
+	failing map[string]bool
+}
+
+func (r *recordingStep) step(id string) Step {
+	return Step{
+		ID:          id,
+		Description: "test step " + id,
+		Apply: func(_ context.Context, project nexushook.ProjectStatus) error {
+			if r.failing[project.Name] {
+				return errors.New("unavailable")
+			}
+			r.applied = append(r.applied, id+" "+project.Name)
+			return nil
+		},
+	}
+}
+
+func (s *MigrationTestSuite) TestRun() {
+	store := &memoryStore{}
+	steps := &recordingStep{failing: map[string]bool{"b": true}}
+
+	// A failed project stops the migration after the other projects are migrated
+	runner := NewRunner(store, "1.1.0", listTestProjects).WithSteps(steps.step("0001-first"), steps.step("0002-second"))
+	err := runner.Run(s.ctx)
+	s.ErrorContains(err, "migration 0001-first failed for 1 of 3 projects")
+	s.Equal([]string{"0001-first a", "0001-first c"}, steps.applied)
+	s.Equal("", store.state.Version)
+	s.Equal("0001-first", store.state.Step)
+	s.Equal([]string{"uuid-a", "uuid-c"}, store.state.Migrated)
+	s.Equal(map[string]string{"uuid-b": "unavailable"}, store.state.Failed)
+
+	// The next run resumes with the projects that were not migrated
+	steps.applied = nil
+	steps.failing = nil
+	s.NoError(runner.Run(s.ctx))
+	s.Equal([]string{"0001-first b", "0002-second a", "0002-second b", "0002-second c"}, steps.applied)
+	s.Equal("1.1.0", store.state.Version)
+	s.Equal([]string{"0001-first", "0002-second"}, store.state.Completed)
+	s.Empty(store.state.Step)
+	s.Empty(store.state.Failed)
+
+	// Completed steps are not applied again, new steps are
+	steps.applied = nil
+	runner = NewRunner(store, "1.2.0", listTestProjects).
+		WithSteps(steps.step("0001-first"), steps.step("0002-second"), steps.step("0003-third"))
+	s.NoError(runner.Run(s.ctx))
+	s.Equal([]string{"0003-third a", "0003-third b", "0003-third c"}, steps.applied)
+	s.Equal("1.2.0", store.state.Version)
+
+	saves := store.saves
+	s.NoError(runner.Run(s.ctx))
+	s.Equal(saves, store.saves)
+}
+
+func (s *MigrationTestSuite) TestConfigMapStore() {
+	store := newConfigMapStore(fake.NewClientset().CoreV1().ConfigMaps("orch-app"))
+	state, err := store.Load(s.ctx)
+	s.NoError(err)
+	s.Nil(state)
+
+	saved := &State{Version: "1.0.0", Completed: []string{"0001-first"}, Updated: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	s.NoError(store.Save(s.ctx, saved))
+	saved.Step = "0002-second"
+	saved.Migrated = []string{"uuid-a"}
+	s.NoError(store.Save(s.ctx, saved))
+
+	state, err = store.Load(s.ctx)
+	s.NoError(err)
+	s.Equal(saved, state)
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	coreV1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	coreV1Types "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// StateConfigMap is the name of the ConfigMap holding the migration state in the controller namespace
+	StateConfigMap = "app-orch-tenant-controller-migrations"
+	// StateKey is the ConfigMap key holding the state document
+	StateKey = "state.json"
+)
+
+// ConfigMapStore keeps the migration state in a ConfigMap
+type ConfigMapStore struct {
+	configMaps coreV1Types.ConfigMapInterface
+}
+
+// NewConfigMapStore creates a store in the namespace using the in-cluster Kubernetes configuration.
+func NewConfigMapStore(namespace string) (*ConfigMapStore, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return newConfigMapStore(clientset.CoreV1().ConfigMaps(namespace)), nil
+}
+
+func newConfigMapStore(configMaps coreV1Types.ConfigMapInterface) *ConfigMapStore {
+	return &ConfigMapStore{configMaps: configMaps}
+}
+
+func (s *ConfigMapStore) Load(ctx context.Context) (*State, error) {
+	configMap, err := s.configMaps.Get(ctx, StateConfigMap, metaV1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := &State{}
+	if err := json.Unmarshal([]byte(configMap.Data[StateKey]), state); err != nil {
+		return nil, fmt.Errorf("invalid migration state: %w", err)
+	}
+	return state, nil
+}
+
+func (s *ConfigMapStore) Save(ctx context.Context, state *State) error {
+	document, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	configMap, err := s.configMaps.Get(ctx, StateConfigMap, metaV1.GetOptions{})
+	exists := err == nil
+	if apierrors.IsNotFound(err) {
+		configMap = &coreV1.ConfigMap{ObjectMeta: metaV1.ObjectMeta{Name: StateConfigMap}}
+	} else if err != nil {
+		return err
+	}
+	configMap.Data = map[string]string{StateKey: string(document)}
+	if exists {
+		_, err = s.configMaps.Update(ctx, configMap, metaV1.UpdateOptions{})
+	} else {
+		_, err = s.configMaps.Create(ctx, configMap, metaV1.CreateOptions{})
+	}
+	return err
+}
@@ -12,10 +12,14 @@ import (
 // This module contains mocks for the Nexus client. It maintains an in-memory list of watchers.
 
 type MockNexusOrganization struct {
+	displayName string
 }
 
 func (o *MockNexusOrganization) DisplayName() string {
-	return "MockNexusOrganization"
+	if o == nil || o.displayName == "" {
+		return "MockNexusOrganization"
+	}
+	return o.displayName
 }
 
 type MockNexusFolder struct {
@@ -30,8 +34,8 @@ func (f *MockNexusFolder) GetParent(ctx context.Context) (NexusOrganizationInter
 type MockNexusProjectActiveWatcher nexus.ProjectactivewatcherProjectActiveWatcher
 
 func (w *MockNexusProjectActiveWatcher) Update(ctx context.Context) error {
-	_ = ctx
-	return nil
+	// Like the Nexus client, fail if the context is done
+	return ctx.Err()
 }
 
 func (w *MockNexusProjectActiveWatcher) GetSpec() *projectActiveWatcherv1.ProjectActiveWatcherSpec {
@@ -46,17 +50,30 @@ type MockNexusProject struct {
 	isDeleted      bool
 	displayName    string
 	uid            string
+	annotations    map[string]string
+	labels         map[string]string
 	parent         *MockNexusFolder
+	parentErr      error
 	activeWatchers map[string]*MockNexusProjectActiveWatcher
 }
 
 func (p *MockNexusProject) GetActiveWatchers(ctx context.Context, name string) (NexusProjectActiveWatcherInterface, error) {
-	_ = ctx
-	return p.activeWatchers[name], nil
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	watcher, ok := p.activeWatchers[name]
+	if !ok {
+		return nil, nil
+	}
+	return watcher, nil
 }
 
 func (p *MockNexusProject) AddActiveWatchers(ctx context.Context, watcher *projectActiveWatcherv1.ProjectActiveWatcher) (NexusProjectActiveWatcherInterface, error) {
 	_ = ctx
+	// Like Nexus, return the watcher as it is if it already exists
+	if existing, ok := p.activeWatchers[watcher.Name]; ok {
+		return existing, nil
+	}
 	p.activeWatchers[watcher.Name] = &MockNexusProjectActiveWatcher{ProjectActiveWatcher: watcher}
 
 	return p.activeWatchers[watcher.Name], nil
@@ -70,6 +87,9 @@ func (p *MockNexusProject) DeleteActiveWatchers(ctx context.Context, name string
 
 func (p *MockNexusProject) GetParent(ctx context.Context) (NexusFolderInterface, error) {
 	_ = ctx
+	if p.parentErr != nil {
+		return nil, p.parentErr
+	}
 	return p.parent, nil
 }
 
@@ -81,6 +101,14 @@ func (p *MockNexusProject) GetUID() string {
 	return p.uid
 }
 
+func (p *MockNexusProject) GetAnnotations() map[string]string {
+	return p.annotations
+}
+
+func (p *MockNexusProject) GetLabels() map[string]string {
+	return p.labels
+}
+
 func (p *MockNexusProject) IsDeleted() bool {
 	return p.isDeleted
 }
@@ -45,6 +45,8 @@ type NexusProjectInterface interface { // nolint:revive
 	GetParent(ctx context.Context) (NexusFolderInterface, error)
 	DisplayName() string
 	GetUID() string
+	GetAnnotations() map[string]string
+	GetLabels() map[string]string
 	IsDeleted() bool
 }
 
@@ -111,6 +113,14 @@ func (p *NexusProject) GetUID() string {
 	return string((*nexus.RuntimeprojectRuntimeProject)(p).UID)
 }
 
+func (p *NexusProject) GetAnnotations() map[string]string {
+	return (*nexus.RuntimeprojectRuntimeProject)(p).GetAnnotations()
+}
+
+func (p *NexusProject) GetLabels() map[string]string {
+	return (*nexus.RuntimeprojectRuntimeProject)(p).GetLabels()
+}
+
 func (p *NexusProject) IsDeleted() bool {
 	return p.Spec.Deleted
 }
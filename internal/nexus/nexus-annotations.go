@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package nexus
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// watcher annotation key holding the version of the controller that last provisioned the project
+	ControllerVersionAnnotationKey = "app-orch-tenant-controller/controller-version"
+	// watcher annotation key holding the RFC 3339 time of the last successful provisioning
+	ProvisionedAtAnnotationKey = "app-orch-tenant-controller/provisioned-at"
+	// watcher annotation key holding the resources of the project for the UI, as a compact InventorySummary document
+	InventoryAnnotationKey = "app-orch-tenant-controller/inventory"
+
+	// InventorySummaryVersion is the version of the InventorySummary document, increased on incompatible changes
+	InventorySummaryVersion = 1
+	// MaxInventoryAnnotationSize is the maximum length of the inventory annotation. Kubernetes allows 256 KiB for all
+	// annotations of an object together, which the other apps watching the project share.
+	MaxInventoryAnnotationSize = 4096
+)
+
+// InventorySummary is the part of the inventory of a project shown by the UI, stored in the watcher annotations.
+type InventorySummary struct {
+	Version int `json:"v"`
+	// name of the Harbor project
+	HarborProject string `json:"harbor,omitempty"`
+	// names of the catalog registries
+	Registries []string `json:"registries,omitempty"`
+	// extension deployment packages as name:version
+	Extensions []string `json:"extensions,omitempty"`
+	// set if extensions or registries were left out to fit MaxInventoryAnnotationSize
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Annotation returns the summary as compact JSON of at most MaxInventoryAnnotationSize bytes. Extensions, then
+// registries, are left out from the end of their lists until it fits.
+func (s InventorySummary) Annotation() (string, error) {
+	s.Version = InventorySummaryVersion
+	for {
+		document, err := json.Marshal(s)
+		if err != nil {
+			return "", err
+		}
+		if len(document) <= MaxInventoryAnnotationSize || (len(s.Extensions) == 0 && len(s.Registries) == 0) {
+			return string(document), nil
+		}
+		s.Truncated = true
+		if len(s.Extensions) > 0 {
+			s.Extensions = s.Extensions[:len(s.Extensions)-1]
+		} else {
+			s.Registries = s.Registries[:len(s.Registries)-1]
+		}
+	}
+}
+
+// InventorySummaryFromAnnotations reads the inventory summary from watcher annotations. It returns false if there is
+// none, or if it cannot be read.
+func InventorySummaryFromAnnotations(annotations map[string]string) (InventorySummary, bool) {
+	summary := InventorySummary{}
+	document, ok := annotations[InventoryAnnotationKey]
+	if !ok || json.Unmarshal([]byte(document), &summary) != nil {
+		return InventorySummary{}, false
+	}
+	return summary, true
+}
+
+// ProvisionedVersions records what was applied to a project the last time it was successfully provisioned. It is
+// stored in the annotations of the project watcher of this app.
+type ProvisionedVersions struct {
+	ManifestTag       string
+	ControllerVersion string
+	// zero if the project was provisioned before the time was recorded
+	ProvisionedAt time.Time
+}
+
+// ProvisionedVersionsFromAnnotations reads the provisioned versions from watcher annotations. Missing or malformed
+// values are left empty.
+func ProvisionedVersionsFromAnnotations(annotations map[string]string) ProvisionedVersions {
+	versions := ProvisionedVersions{
+		ManifestTag:       annotations[ManifestTagAnnotationKey],
+		ControllerVersion: annotations[ControllerVersionAnnotationKey],
+	}
+	if provisionedAt, err := time.Parse(time.RFC3339, annotations[ProvisionedAtAnnotationKey]); err == nil {
+		versions.ProvisionedAt = provisionedAt
+	}
+	return versions
+}
+
+// SetAnnotations returns a copy of the annotations with the provisioned versions set. Other annotations are kept.
+func (v ProvisionedVersions) SetAnnotations(annotations map[string]string) map[string]string {
+	updated := make(map[string]string, len(annotations)+3)
+	for key, value := range annotations {
+		updated[key] = value
+	}
+	updated[ManifestTagAnnotationKey] = v.ManifestTag
+	if v.ControllerVersion != "" {
+		updated[ControllerVersionAnnotationKey] = v.ControllerVersion
+	}
+	if !v.ProvisionedAt.IsZero() {
+		updated[ProvisionedAtAnnotationKey] = v.ProvisionedAt.UTC().Format(time.RFC3339)
+	}
+	return updated
+}
+
+// UpToDate returns true if the project does not need to be provisioned again for the wanted manifest tag and
+// controller version. A controller version is only compared if it is known on both sides, so that projects
+// provisioned before the version was recorded are not all provisioned again on upgrade; migrations take care of
+// changes that require it.
+func (v ProvisionedVersions) UpToDate(manifestTag string, controllerVersion string) bool {
+	if v.ManifestTag != manifestTag {
+		return false
+	}
+	return v.ControllerVersion == "" || controllerVersion == "" || v.ControllerVersion == controllerVersion
+}
@@ -6,6 +6,7 @@ package nexus
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/open-edge-platform/orch-library/go/dazl"
 	projectActiveWatcherv1 "github.com/open-edge-platform/orch-utils/tenancy-datamodel/build/apis/projectactivewatcher.edge-orchestrator.intel.com/v1"
@@ -14,16 +15,20 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
 	"strings"
+	"sync"
 	"time"
 )
 
 var log = dazl.GetPackageLogger()
 
+// errMissingOrganization is the error of a project whose organization is missing or has an empty name
+var errMissingOrganization = errors.New("organization of project is missing")
+
 const (
 	appName = "config-provisioner"
 
-	// Allow only certain time for interacting with Nexus server
-	nexusTimeout = 5 * time.Second
+	// Default time allowed for interacting with Nexus server
+	DefaultNexusTimeout = 5 * time.Second
 
 	// Some reasonable limits for names that come from Nexus events, to guard against attack vector on event
 	// handling. Note that there is no guarantee the plugins will be able to correctly process names at this
@@ -33,25 +38,225 @@ const (
 	MaxProjectUUIDLength      = 36
 	// manifest tag annotation key
 	ManifestTagAnnotationKey = "app-orch-tenant-controller/manifest-tag"
+	// project annotation key used to select a provisioning profile
+	ProvisioningProfileAnnotationKey = "app-orch-tenant-controller/provisioning-profile"
+	// project annotation key that archives the Harbor project of a deleted project instead of deleting it
+	RetainDataAnnotationKey = "app-orch-tenant-controller/retain-data"
 )
 
+// ProjectManager receives project lifecycle events. CreateProject, UpdateProject, DeleteProject and EnsureProject
+// acknowledge an event by returning nil once it has been accepted for processing; they return an error if the event
+// could not be accepted before the context is done. EnsureProject converges a provisioned project without deleting
+// its resources or issuing new credentials.
 type ProjectManager interface {
-	CreateProject(orgName string, projectName string, projectUUID string, project NexusProjectInterface)
-	DeleteProject(orgName string, projectName string, projectUUID string, project NexusProjectInterface)
+	CreateProject(ctx context.Context, orgName string, projectName string, projectUUID string, project NexusProjectInterface) error
+	UpdateProject(ctx context.Context, orgName string, projectName string, projectUUID string, project NexusProjectInterface, changes ProjectChanges) error
+	DeleteProject(ctx context.Context, orgName string, projectName string, projectUUID string, project NexusProjectInterface) error
+	EnsureProject(ctx context.Context, orgName string, projectName string, projectUUID string, project NexusProjectInterface) error
 	ManifestTag() string
+	ControllerVersion() string
+}
+
+// ProjectChange is the old and new value of a changed label or annotation. An empty value means the key
+// was not present.
+type ProjectChange struct {
+	Old string
+	New string
+}
+
+// ProjectChanges describes the labels and annotations that differ between two versions of a project.
+type ProjectChanges struct {
+	Labels      map[string]ProjectChange
+	Annotations map[string]ProjectChange
+}
+
+// IsEmpty returns true if nothing changed.
+func (c ProjectChanges) IsEmpty() bool {
+	return len(c.Labels) == 0 && len(c.Annotations) == 0
+}
+
+// AnnotationChanged returns true if the given annotation was added, removed or modified.
+func (c ProjectChanges) AnnotationChanged(key string) bool {
+	_, ok := c.Annotations[key]
+	return ok
+}
+
+// LabelChanged returns true if the given label was added, removed or modified.
+func (c ProjectChanges) LabelChanged(key string) bool {
+	_, ok := c.Labels[key]
+	return ok
+}
+
+func diffMaps(oldMap map[string]string, newMap map[string]string) map[string]ProjectChange {
+	changes := make(map[string]ProjectChange)
+	for key, oldValue := range oldMap {
+		if newValue, ok := newMap[key]; !ok || newValue != oldValue {
+			changes[key] = ProjectChange{Old: oldValue, New: newValue}
+		}
+	}
+	for key, newValue := range newMap {
+		if _, ok := oldMap[key]; !ok {
+			changes[key] = ProjectChange{New: newValue}
+		}
+	}
+	return changes
+}
+
+// DiffProjects returns the labels and annotations that differ between the old and new version of a project.
+func DiffProjects(oldProject NexusProjectInterface, newProject NexusProjectInterface) ProjectChanges {
+	return ProjectChanges{
+		Labels:      diffMaps(oldProject.GetLabels(), newProject.GetLabels()),
+		Annotations: diffMaps(oldProject.GetAnnotations(), newProject.GetAnnotations()),
+	}
 }
 
 type Hook struct {
-	dispatcher  ProjectManager
-	nexusClient *nexus.Clientset
+	dispatcher          ProjectManager
+	nexusClient         *nexus.Clientset
+	connection          nexusConnection
+	ctx                 context.Context
+	timeout             time.Duration
+	healthCheckInterval time.Duration
+	startupResync       bool
+	// minimum time between two writes of the same watcher status, zero writes every update
+	statusUpdateInterval time.Duration
+	// organization of the projects whose organization is missing or has an empty name, empty to reject them
+	fallbackOrganization string
+	inFlight             sync.WaitGroup
+
+	// events not yet handed to the dispatcher, by project UUID. A project has an entry while a goroutine hands its
+	// events over one at a time, so that they reach the dispatcher in the order they were received
+	pending     map[string][]pendingEvent
+	pendingLock sync.Mutex
+
+	// projects accepted for provisioning, by UUID, so that projects removed while the subscription is down are seen
+	projects     map[string]knownProject
+	projectsLock sync.Mutex
 }
 
 // NewNexusHook creates a new hook for receiving project lifecycle events from Nexus.
 func NewNexusHook(dispatcher ProjectManager) *Hook {
-	return &Hook{dispatcher: dispatcher}
+	return &Hook{
+		dispatcher:          dispatcher,
+		ctx:                 context.Background(),
+		timeout:             DefaultNexusTimeout,
+		healthCheckInterval: DefaultHealthCheckInterval,
+		projects:            make(map[string]knownProject),
+		pending:             make(map[string][]pendingEvent),
+	}
+}
+
+// pendingEvent is an event waiting to be handed to the dispatcher.
+type pendingEvent struct {
+	project   NexusProjectInterface
+	eventType string
+	dispatch  func(ctx context.Context) error
+}
+
+// WithContext sets the context that bounds the lifetime of the hook. Cancelling it abandons
+// events that have not yet been accepted by the dispatcher.
+func (h *Hook) WithContext(ctx context.Context) *Hook {
+	h.ctx = ctx
+	return h
 }
 
-// Subscribe issues all required subscriptions for receiving project lifecycle events.
+// WithTimeout sets the time allowed for each interaction with the Nexus server.
+func (h *Hook) WithTimeout(timeout time.Duration) *Hook {
+	if timeout > 0 {
+		h.timeout = timeout
+	}
+	return h
+}
+
+// WithHealthCheckInterval sets the time between checks of the connection to the Nexus API server. Zero disables the
+// checks, so that a lost connection is not recovered from.
+func (h *Hook) WithHealthCheckInterval(interval time.Duration) *Hook {
+	if interval >= 0 {
+		h.healthCheckInterval = interval
+	}
+	return h
+}
+
+// WithStatusUpdateInterval sets the minimum time between two writes of a project watcher that keep its status. Within
+// it, the same message is not written again and an in progress message replaces the previous one only once it is
+// over, so that retries do not flood the Nexus API server. A change of status is always written. Zero writes every
+// update.
+func (h *Hook) WithStatusUpdateInterval(interval time.Duration) *Hook {
+	if interval >= 0 {
+		h.statusUpdateInterval = interval
+	}
+	return h
+}
+
+// WithFallbackOrganization sets the organization of the projects whose organization is missing or has an empty name.
+// Empty rejects these projects, which are then reported in error on their watcher.
+func (h *Hook) WithFallbackOrganization(organizationName string) *Hook {
+	h.fallbackOrganization = organizationName
+	return h
+}
+
+// Wait blocks until all events handed to the dispatcher have been acknowledged.
+func (h *Hook) Wait() {
+	h.inFlight.Wait()
+}
+
+func (h *Hook) nexusContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(h.ctx, h.timeout)
+}
+
+// dispatchAsync hands an event to the dispatcher without blocking the Nexus informer thread. The events of a project
+// are handed over in the order they were received, so that a create never reaches the dispatcher after the delete
+// that followed it. If the dispatcher does not acknowledge the event, the failure is reported on the project watcher.
+func (h *Hook) dispatchAsync(project NexusProjectInterface, eventType string, dispatch func(ctx context.Context) error) {
+	h.inFlight.Add(1)
+	uid := project.GetUID()
+	h.pendingLock.Lock()
+	queued, running := h.pending[uid]
+	h.pending[uid] = append(queued, pendingEvent{project: project, eventType: eventType, dispatch: dispatch})
+	h.pendingLock.Unlock()
+	if !running {
+		go h.dispatchPending(uid)
+	}
+}
+
+// dispatchPending hands the pending events of a project to the dispatcher one at a time, until there are none left.
+func (h *Hook) dispatchPending(uid string) {
+	for {
+		h.pendingLock.Lock()
+		queued := h.pending[uid]
+		if len(queued) == 0 {
+			delete(h.pending, uid)
+			h.pendingLock.Unlock()
+			return
+		}
+		event := queued[0]
+		h.pending[uid] = queued[1:]
+		h.pendingLock.Unlock()
+
+		if err := event.dispatch(h.ctx); err != nil {
+			log.Errorf("Project %s %s event was not accepted: %v", event.project.DisplayName(), event.eventType, err)
+			// The hook context is usually done when an event is not accepted, at shutdown, so the status is written
+			// with a context of its own
+			ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+			if statusErr := h.setWatcherStatusError(ctx, event.project, err.Error()); statusErr != nil {
+				log.Errorf("Unable to set watcher error status: %v", statusErr)
+			}
+			cancel()
+		}
+		h.inFlight.Done()
+	}
+}
+
+// WithStartupResync enables provisioning the up to date projects again once the hook has subscribed.
+func (h *Hook) WithStartupResync(enabled bool) *Hook {
+	h.startupResync = enabled
+	return h
+}
+
+// Subscribe issues all required subscriptions for receiving project lifecycle events. Unless the health checks are
+// disabled, the connection to the Nexus API server is then checked until the hook context is done, and the
+// subscriptions are re-established when it is back after being lost. If the startup resync is enabled, the projects
+// skipped by the subscription replay are then provisioned again.
 func (h *Hook) Subscribe() error {
 	// Initialize Nexus SDK, by pointing it to the K8s API endpoint where CRD's are to be stored.
 	cfg, err := rest.InClusterConfig()
@@ -71,6 +276,26 @@ func (h *Hook) Subscribe() error {
 		return err
 	}
 
+	if err := h.subscribe(); err != nil {
+		return err
+	}
+	log.Info("Nexus hook successfully subscribed")
+
+	h.connection = &clientConnection{hook: h}
+	if h.healthCheckInterval > 0 {
+		go h.monitor()
+	}
+	if h.startupResync {
+		go func() {
+			if err := h.ensureProjects(); err != nil {
+				log.Errorf("Startup resync did not complete: %v", err)
+			}
+		}()
+	}
+	return nil
+}
+
+func (h *Hook) subscribe() error {
 	// Subscribe to Multi-Tenancy graph.
 	// Subscribe() api empowers subscription to objects from datamodel.
 	// What subscription does is to keep the local cache in sync with datamodel changes.
@@ -89,15 +314,13 @@ func (h *Hook) Subscribe() error {
 		log.Errorf("Unable to register project deletion callback: %+v", err)
 		return err
 	}
-	log.Info("Nexus hook successfully subscribed")
-
 	return nil
 }
 
 func (h *Hook) setupConfigProvisionerWatcherConfig() error {
 	tenancy := h.nexusClient.TenancyMultiTenancy()
 
-	ctx, cancel := context.WithTimeout(context.Background(), nexusTimeout)
+	ctx, cancel := h.nexusContext()
 	defer cancel()
 
 	projWatcher, err := tenancy.Config().AddProjectWatchers(ctx, &projectwatcherv1.ProjectWatcher{ObjectMeta: metav1.ObjectMeta{
@@ -121,13 +344,31 @@ func (h *Hook) safeUnixTime() uint64 {
 	return uint64(t)
 }
 
-func (h *Hook) setProjWatcherStatus(watcherObj NexusProjectActiveWatcherInterface, statusInd projectActiveWatcherv1.ActiveWatcherStatus, status string) error {
+// statusUpdateThrottled returns true if writing the status and message to the watcher can be skipped, because the
+// watcher already has this status and it was written less than the status update interval ago with the same message
+// or, for an in progress status, with any message.
+func (h *Hook) statusUpdateThrottled(spec *projectActiveWatcherv1.ProjectActiveWatcherSpec, statusInd projectActiveWatcherv1.ActiveWatcherStatus, status string) bool {
+	if h.statusUpdateInterval <= 0 || spec.StatusIndicator != statusInd {
+		return false
+	}
+	if spec.Message != status && statusInd != projectActiveWatcherv1.StatusIndicationInProgress {
+		return false
+	}
+	now := h.safeUnixTime()
+	return spec.TimeStamp <= now && time.Duration(now-spec.TimeStamp)*time.Second < h.statusUpdateInterval
+}
+
+func (h *Hook) setProjWatcherStatus(ctx context.Context, watcherObj NexusProjectActiveWatcherInterface, statusInd projectActiveWatcherv1.ActiveWatcherStatus, status string) error {
+	if h.statusUpdateThrottled(watcherObj.GetSpec(), statusInd, status) {
+		log.Debugf("Skipping update of ProjectActiveWatcher %s to %s: %s", watcherObj.DisplayName(), statusInd, status)
+		return nil
+	}
 	watcherObj.GetSpec().StatusIndicator = statusInd
 	watcherObj.GetSpec().Message = status
 	watcherObj.GetSpec().TimeStamp = h.safeUnixTime()
 	log.Debugf("ProjWatcher object to update: %+v", watcherObj)
 
-	err := watcherObj.Update(context.Background())
+	err := watcherObj.Update(ctx)
 	if err != nil {
 		log.Errorf("Failed to update ProjectActiveWatcher object with an error: %v", err)
 		return err
@@ -135,8 +376,11 @@ func (h *Hook) setProjWatcherStatus(watcherObj NexusProjectActiveWatcherInterfac
 	return nil
 }
 
-func (h *Hook) SetWatcherStatusIdle(proj NexusProjectInterface) error {
-	watcherObj, err := proj.GetActiveWatchers(context.Background(), appName)
+// SetWatcherStatusIdle marks the watcher of the project idle with the given message, e.g. "Created".
+func (h *Hook) SetWatcherStatusIdle(proj NexusProjectInterface, message string) error {
+	ctx, cancel := h.nexusContext()
+	defer cancel()
+	watcherObj, err := proj.GetActiveWatchers(ctx, appName)
 	if err == nil && watcherObj != nil {
 		// If watcher exists and is IDLE, simply return.
 		if watcherObj.GetSpec().StatusIndicator == projectActiveWatcherv1.StatusIndicationIdle {
@@ -145,7 +389,7 @@ func (h *Hook) SetWatcherStatusIdle(proj NexusProjectInterface) error {
 		}
 
 		// If watcher exists and is not IDLE, mark it as idle
-		setStatusErr := h.setProjWatcherStatus(watcherObj, projectActiveWatcherv1.StatusIndicationIdle, "Created")
+		setStatusErr := h.setProjWatcherStatus(ctx, watcherObj, projectActiveWatcherv1.StatusIndicationIdle, message)
 		if setStatusErr != nil {
 			log.Errorf("Failed to update ProjectActiveWatcher object with an error: %v", setStatusErr)
 			return setStatusErr
@@ -156,9 +400,15 @@ func (h *Hook) SetWatcherStatusIdle(proj NexusProjectInterface) error {
 }
 
 func (h *Hook) SetWatcherStatusError(proj NexusProjectInterface, message string) error {
-	watcherObj, err := proj.GetActiveWatchers(context.Background(), appName)
+	ctx, cancel := h.nexusContext()
+	defer cancel()
+	return h.setWatcherStatusError(ctx, proj, message)
+}
+
+func (h *Hook) setWatcherStatusError(ctx context.Context, proj NexusProjectInterface, message string) error {
+	watcherObj, err := proj.GetActiveWatchers(ctx, appName)
 	if err == nil && watcherObj != nil {
-		setStatusErr := h.setProjWatcherStatus(watcherObj, projectActiveWatcherv1.StatusIndicationError, message)
+		setStatusErr := h.setProjWatcherStatus(ctx, watcherObj, projectActiveWatcherv1.StatusIndicationError, message)
 		if setStatusErr != nil {
 			log.Errorf("Failed to update ProjectActiveWatcher object with an error: %v", setStatusErr)
 			return setStatusErr
@@ -169,10 +419,12 @@ func (h *Hook) SetWatcherStatusError(proj NexusProjectInterface, message string)
 }
 
 func (h *Hook) SetWatcherStatusInProgress(proj NexusProjectInterface, message string) error {
-	watcherObj, err := proj.GetActiveWatchers(context.Background(), appName)
+	ctx, cancel := h.nexusContext()
+	defer cancel()
+	watcherObj, err := proj.GetActiveWatchers(ctx, appName)
 	log.Infof("Setting watcher status to InProgress for project %s to %s", proj.DisplayName(), message)
 	if err == nil && watcherObj != nil {
-		setStatusErr := h.setProjWatcherStatus(watcherObj, projectActiveWatcherv1.StatusIndicationInProgress, message)
+		setStatusErr := h.setProjWatcherStatus(ctx, watcherObj, projectActiveWatcherv1.StatusIndicationInProgress, message)
 		if setStatusErr != nil {
 			log.Errorf("Failed to update ProjectActiveWatcher object with an error: %v", setStatusErr)
 			return setStatusErr
@@ -182,24 +434,60 @@ func (h *Hook) SetWatcherStatusInProgress(proj NexusProjectInterface, message st
 	return err
 }
 
+// UpdateProjectManifestTag records the manifest tag and controller version applied to the project, and the time of
+// this successful provisioning, in the annotations of the project watcher. Other annotations are kept.
 func (h *Hook) UpdateProjectManifestTag(proj NexusProjectInterface) error {
-	log.Infof("Setting watcher manifest tag for project %s to %s", proj.DisplayName(), h.dispatcher.ManifestTag())
-	watcherObj, err := proj.GetActiveWatchers(context.Background(), appName)
+	versions := ProvisionedVersions{
+		ManifestTag:       h.dispatcher.ManifestTag(),
+		ControllerVersion: h.dispatcher.ControllerVersion(),
+		ProvisionedAt:     time.Now(),
+	}
+	log.Infof("Setting watcher manifest tag for project %s to %s, controller version %s", proj.DisplayName(),
+		versions.ManifestTag, versions.ControllerVersion)
+	ctx, cancel := h.nexusContext()
+	defer cancel()
+	watcherObj, err := proj.GetActiveWatchers(ctx, appName)
 	if err != nil {
 		return err
 	}
 	if watcherObj != nil {
 		log.Debug("Setting watcher annotations")
-		annotations := make(map[string]string)
-		annotations[ManifestTagAnnotationKey] = h.dispatcher.ManifestTag()
-		watcherObj.SetAnnotations(annotations)
-		return watcherObj.Update(context.Background())
+		watcherObj.SetAnnotations(versions.SetAnnotations(watcherObj.GetAnnotations()))
+		return watcherObj.Update(ctx)
 	}
 	return err
 }
 
+// SetWatcherInventory records the summary of the resources of the project in the annotations of the project
+// watcher, so that the UI can show them. Other annotations are kept, and the watcher is not written if the summary
+// is unchanged.
+func (h *Hook) SetWatcherInventory(proj NexusProjectInterface, summary InventorySummary) error {
+	annotation, err := summary.Annotation()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := h.nexusContext()
+	defer cancel()
+	watcherObj, err := proj.GetActiveWatchers(ctx, appName)
+	if err != nil || watcherObj == nil {
+		return err
+	}
+	annotations := watcherObj.GetAnnotations()
+	if annotations[InventoryAnnotationKey] == annotation {
+		return nil
+	}
+	updated := make(map[string]string, len(annotations)+1)
+	for key, value := range annotations {
+		updated[key] = value
+	}
+	updated[InventoryAnnotationKey] = annotation
+	log.Debugf("Setting watcher inventory for project %s to %s", proj.DisplayName(), annotation)
+	watcherObj.SetAnnotations(updated)
+	return watcherObj.Update(ctx)
+}
+
 func (h *Hook) StopWatchingProject(project NexusProjectInterface) {
-	ctx, cancel := context.WithTimeout(context.Background(), nexusTimeout)
+	ctx, cancel := h.nexusContext()
 	defer cancel()
 
 	// Stop watching the project as it is marked for deletion.
@@ -218,8 +506,21 @@ func (h *Hook) StopWatchingProject(project NexusProjectInterface) {
 func (h *Hook) deleteProject(project NexusProjectInterface) {
 	log.Infof("Project: %+v marked for deletion", project.DisplayName())
 
-	organizationName := h.getOrganizationName(project)
-	h.dispatcher.DeleteProject(organizationName, project.DisplayName(), project.GetUID(), project)
+	// The organization may already be gone, so the one the project was provisioned in is preferred
+	organizationName := ""
+	if known, ok := h.untrackProject(project); ok {
+		organizationName = known.organization
+	} else {
+		var err error
+		organizationName, err = h.organizationName(project)
+		if err != nil {
+			log.Errorf("Unable to process delete for project %s: %v", project.DisplayName(), err)
+			return
+		}
+	}
+	h.dispatchAsync(project, "delete", func(ctx context.Context) error {
+		return h.dispatcher.DeleteProject(ctx, organizationName, project.DisplayName(), project.GetUID(), project)
+	})
 }
 
 func (h *Hook) validateArgs(project NexusProjectInterface, organizationName string, projectName string, projectUUID string) error {
@@ -303,7 +604,7 @@ func (h *Hook) projectCreated(project NexusProjectInterface) error {
 		return nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), nexusTimeout)
+	ctx, cancel := h.nexusContext()
 	defer cancel()
 
 	// Register this app as an active watcher for this project.
@@ -325,17 +626,24 @@ func (h *Hook) projectCreated(project NexusProjectInterface) error {
 
 	var action string
 
-	if watcherObj.GetSpec().StatusIndicator == projectActiveWatcherv1.StatusIndicationIdle && watcherObj.GetSpec().Message == "Created" {
+	if provisioned(watcherObj) {
 		// This is a rerun of an event we already processed - check for update
 		log.Infof("Watch %s for project %s already provisioned", watcherObj.DisplayName(), project.DisplayName())
 		log.Debugf("existing watcher annotations are: %+v", watcherObj.GetAnnotations())
-		annotations := watcherObj.GetAnnotations()
-		if annotations[ManifestTagAnnotationKey] == h.dispatcher.ManifestTag() {
-			// Manifest tag is correct
-			log.Infof("Manifest tag is correct, no need to update")
+		versions := ProvisionedVersionsFromAnnotations(watcherObj.GetAnnotations())
+		if h.upToDate(watcherObj) {
+			log.Infof("Manifest tag and controller version are correct, no need to update")
+			organizationName, err := h.organizationName(project)
+			if err != nil {
+				log.Warnf("Project %s is not tracked, it will not be deleted if removed while the subscription is down: %v",
+					project.DisplayName(), err)
+				return nil
+			}
+			h.trackProject(organizationName, project)
 			return nil
 		}
-		log.Infof("Manifest tag is not correct, updating. Have %s, want %s", annotations[ManifestTagAnnotationKey], h.dispatcher.ManifestTag())
+		log.Infof("Provisioned versions are not correct, updating. Have manifest %s controller %s, want manifest %s controller %s",
+			versions.ManifestTag, versions.ControllerVersion, h.dispatcher.ManifestTag(), h.dispatcher.ControllerVersion())
 		action = "update"
 	} else {
 		action = "created"
@@ -349,48 +657,126 @@ func (h *Hook) projectCreated(project NexusProjectInterface) error {
 	}
 
 	// handle the creation of the project
-	organizationName := h.getOrganizationName(project)
+	organizationName, err := h.organizationName(project)
+	if err != nil {
+		if statusErr := h.SetWatcherStatusError(project, err.Error()); statusErr != nil {
+			log.Errorf("Unable to set watcher error status: %v", statusErr)
+		}
+		return err
+	}
 	err = h.validateArgs(project, organizationName, project.DisplayName(), project.GetUID())
 	if err != nil {
 		// If there is an error, validateArgs() will also set the watcher status appropriately.
 		return err
 	}
-	h.dispatcher.CreateProject(organizationName, project.DisplayName(), project.GetUID(), project)
+	h.trackProject(organizationName, project)
+	h.dispatchAsync(project, "create", func(ctx context.Context) error {
+		return h.dispatcher.CreateProject(ctx, organizationName, project.DisplayName(), project.GetUID(), project)
+	})
 
 	log.Infof("Active watcher %s %s created for Project %s", watcherObj.DisplayName(), action, project.DisplayName())
 
 	return nil
 }
 
-func (h *Hook) getOrganizationName(project NexusProjectInterface) string {
-	ctx, cancel := context.WithTimeout(context.Background(), nexusTimeout)
+// provisioned reports whether the watcher records a completed provisioning of the project, with or without warnings.
+func provisioned(watcher NexusProjectActiveWatcherInterface) bool {
+	return watcher != nil && watcher.GetSpec().StatusIndicator == projectActiveWatcherv1.StatusIndicationIdle &&
+		strings.HasPrefix(watcher.GetSpec().Message, "Created")
+}
+
+// upToDate reports whether the watcher records a completed provisioning with the current manifest tag and controller
+// version. The subscription replay skips these projects.
+func (h *Hook) upToDate(watcher NexusProjectActiveWatcherInterface) bool {
+	return provisioned(watcher) && ProvisionedVersionsFromAnnotations(watcher.GetAnnotations()).
+		UpToDate(h.dispatcher.ManifestTag(), h.dispatcher.ControllerVersion())
+}
+
+// organizationName returns the name of the organization of the project. A project whose organization is missing or
+// has an empty name belongs to the fallback organization if one is set, or else is an error, so that the project is
+// never provisioned with an empty organization name. Other lookup failures are always an error.
+func (h *Hook) organizationName(project NexusProjectInterface) (string, error) {
+	organizationName, err := h.lookupOrganizationName(project)
+	if errors.Is(err, errMissingOrganization) && h.fallbackOrganization != "" {
+		log.Warnf("Using fallback organization %s for project %s: %v", h.fallbackOrganization, project.DisplayName(), err)
+		return h.fallbackOrganization, nil
+	}
+	return organizationName, err
+}
+
+func (h *Hook) lookupOrganizationName(project NexusProjectInterface) (string, error) {
+	ctx, cancel := h.nexusContext()
 	defer cancel()
 
-	// TODO: Revisit this. For now, demote errors to mere warnings.
 	folderOrgs, err := project.GetParent(ctx)
-	if err != nil {
-		log.Warnf("Unable to get project parent folder: %v", err)
-		return ""
+	if nexus.IsNotFound(err) || nexus.IsParentNotFound(err) {
+		return "", fmt.Errorf("%w: project parent folder not found: %v", errMissingOrganization, err)
+	} else if err != nil {
+		return "", fmt.Errorf("unable to get project parent folder: %w", err)
 	}
 
-	organization, err := folderOrgs.GetParent(context.Background())
-	if err != nil {
-		log.Warnf("Unable to get parent folder organization: %v", err)
-		return ""
+	organization, err := folderOrgs.GetParent(ctx)
+	if nexus.IsNotFound(err) || nexus.IsParentNotFound(err) {
+		return "", fmt.Errorf("%w: parent folder organization not found: %v", errMissingOrganization, err)
+	} else if err != nil {
+		return "", fmt.Errorf("unable to get parent folder organization: %w", err)
 	}
-	return organization.DisplayName()
+	if strings.TrimSpace(organization.DisplayName()) == "" {
+		return "", fmt.Errorf("%w: organization name is empty", errMissingOrganization)
+	}
+	return organization.DisplayName(), nil
 }
 
-// Callback function to be invoked when Project is deleted.
-func (h *Hook) projectUpdatedCallback(_, nexusProject *nexus.RuntimeprojectRuntimeProject) {
+// Callback function to be invoked when Project is updated or marked for deletion.
+func (h *Hook) projectUpdatedCallback(oldNexusProject, nexusProject *nexus.RuntimeprojectRuntimeProject) {
 	project := (*NexusProject)(nexusProject)
-	h.projectUpdated(project)
+	var oldProject NexusProjectInterface
+	if oldNexusProject != nil {
+		oldProject = (*NexusProject)(oldNexusProject)
+	}
+	h.projectUpdated(oldProject, project)
 }
 
-func (h *Hook) projectUpdated(project NexusProjectInterface) {
+func (h *Hook) projectUpdated(oldProject NexusProjectInterface, project NexusProjectInterface) {
 	if project.IsDeleted() {
 		h.deleteProject(project)
+		return
+	}
+	if oldProject == nil {
+		return
+	}
+
+	changes := DiffProjects(oldProject, project)
+	if changes.IsEmpty() {
+		// Nexus also reports updates for changes we don't care about, such as watchers being added
+		return
+	}
+	log.Infof("Project %s updated: labels %+v annotations %+v", project.DisplayName(), changes.Labels, changes.Annotations)
+
+	// Only projects that this app has already started provisioning can be updated
+	ctx, cancel := h.nexusContext()
+	defer cancel()
+	watcherObj, err := project.GetActiveWatchers(ctx, appName)
+	if err != nil || watcherObj == nil {
+		log.Infof("Project %s is not watched by %s, ignoring update", project.DisplayName(), appName)
+		return
 	}
+
+	organizationName, err := h.organizationName(project)
+	if err != nil {
+		log.Errorf("Unable to process update for project %s: %v", project.DisplayName(), err)
+		if statusErr := h.SetWatcherStatusError(project, err.Error()); statusErr != nil {
+			log.Errorf("Unable to set watcher error status: %v", statusErr)
+		}
+		return
+	}
+	if err := h.validateArgs(project, organizationName, project.DisplayName(), project.GetUID()); err != nil {
+		log.Errorf("Unable to process update for project %s: %v", project.DisplayName(), err)
+		return
+	}
+	h.dispatchAsync(project, "update", func(ctx context.Context) error {
+		return h.dispatcher.UpdateProject(ctx, organizationName, project.DisplayName(), project.GetUID(), project, changes)
+	})
 }
 
 // LookupProjectUID returns the Nexus-assigned UUID for the given org/project by querying
@@ -4,34 +4,97 @@
 package nexus
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	projectActiveWatcherv1 "github.com/open-edge-platform/orch-utils/tenancy-datamodel/build/apis/projectactivewatcher.edge-orchestrator.intel.com/v1"
+	runtimeprojectv1 "github.com/open-edge-platform/orch-utils/tenancy-datamodel/build/apis/runtimeproject.edge-orchestrator.intel.com/v1"
+	nexus "github.com/open-edge-platform/orch-utils/tenancy-datamodel/build/nexus-client"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"strings"
 	"testing"
+	"time"
 )
 
 type MockProjectManager struct {
-	deleted []string
-	created []string
+	deleted           []string
+	created           []string
+	ensured           []string
+	updated           map[string]ProjectChanges
+	organizations     map[string]string
+	reject            bool
+	gate              chan struct{}
+	events            []string
+	manifestTag       string
+	controllerVersion string
 }
 
-func (m *MockProjectManager) CreateProject(orgName string, projectName string, projectUUID string, project NexusProjectInterface) {
-	_ = orgName
+func (m *MockProjectManager) CreateProject(ctx context.Context, orgName string, projectName string, projectUUID string, project NexusProjectInterface) error {
 	_ = projectUUID
 	_ = project
+	if m.reject {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	if m.gate != nil {
+		<-m.gate
+	}
 	m.created = append(m.created, projectName)
+	m.events = append(m.events, "create "+projectName)
+	m.recordOrganization(projectName, orgName)
+	return nil
+}
+
+func (m *MockProjectManager) recordOrganization(projectName string, orgName string) {
+	if m.organizations == nil {
+		m.organizations = make(map[string]string)
+	}
+	m.organizations[projectName] = orgName
 }
 
-func (m *MockProjectManager) DeleteProject(orgName string, projectName string, projectUUID string, project NexusProjectInterface) {
+func (m *MockProjectManager) UpdateProject(_ context.Context, orgName string, projectName string, projectUUID string, project NexusProjectInterface, changes ProjectChanges) error {
 	_ = orgName
+	_ = projectUUID
+	_ = project
+	if m.updated == nil {
+		m.updated = make(map[string]ProjectChanges)
+	}
+	m.updated[projectName] = changes
+	return nil
+}
+
+func (m *MockProjectManager) DeleteProject(_ context.Context, orgName string, projectName string, projectUUID string, project NexusProjectInterface) error {
 	_ = projectUUID
 	_ = project
 	m.deleted = append(m.deleted, projectName)
+	m.events = append(m.events, "delete "+projectName)
+	m.recordOrganization(projectName, orgName)
+	return nil
+}
+
+func (m *MockProjectManager) EnsureProject(ctx context.Context, orgName string, projectName string, projectUUID string, project NexusProjectInterface) error {
+	_ = orgName
+	_ = projectUUID
+	_ = project
+	if m.reject {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	m.ensured = append(m.ensured, projectName)
+	return nil
 }
 
 func (m *MockProjectManager) ManifestTag() string {
-	return ""
+	return m.manifestTag
+}
+
+func (m *MockProjectManager) ControllerVersion() string {
+	return m.controllerVersion
 }
 
 type NexusHookTestSuite struct {
@@ -57,6 +120,7 @@ func (s *NexusHookTestSuite) TestProjectCreated() {
 	project := NewMockNexusProject("project1", "uid1")
 	err := h.projectCreated(project)
 	s.NoError(err, "Expected no error when creating project")
+	h.Wait()
 
 	s.Contains(m.created, "project1", "Expected project1 to be in the created list")
 
@@ -71,13 +135,159 @@ func (s *NexusHookTestSuite) TestProjectDeleted() {
 
 	project := NewMockNexusProject("project1", "uid1")
 	project.isDeleted = true
-	h.projectUpdated(project)
+	h.projectUpdated(nil, project)
+	h.Wait()
 
 	s.Contains(m.deleted, "project1", "Expected project1 to be in the created list")
 
 	s.Equal(0, len(project.activeWatchers), "Expected 0 active watcher")
 }
 
+func (s *NexusHookTestSuite) TestProjectUpdated() {
+	m := &MockProjectManager{}
+	h := NewNexusHook(m)
+
+	oldProject := NewMockNexusProject("project1", "uid1")
+	oldProject.labels = map[string]string{"tier": "bronze", "region": "eu"}
+	project := NewMockNexusProject("project1", "uid1")
+	project.labels = map[string]string{"tier": "gold"}
+	project.annotations = map[string]string{ProvisioningProfileAnnotationKey: "large"}
+
+	// Projects that were never provisioned are not updated
+	h.projectUpdated(oldProject, project)
+	h.Wait()
+	s.Empty(m.updated)
+
+	s.NoError(h.projectCreated(project))
+	h.Wait()
+
+	h.projectUpdated(oldProject, project)
+	h.Wait()
+	s.Contains(m.updated, "project1")
+	changes := m.updated["project1"]
+	s.Equal(map[string]ProjectChange{
+		"tier":   {Old: "bronze", New: "gold"},
+		"region": {Old: "eu"},
+	}, changes.Labels)
+	s.True(changes.AnnotationChanged(ProvisioningProfileAnnotationKey))
+	s.Equal("large", changes.Annotations[ProvisioningProfileAnnotationKey].New)
+	s.Empty(m.deleted)
+}
+
+func (s *NexusHookTestSuite) TestProjectUpdatedNoChanges() {
+	m := &MockProjectManager{}
+	h := NewNexusHook(m)
+
+	project := NewMockNexusProject("project1", "uid1")
+	project.labels = map[string]string{"tier": "gold"}
+	s.NoError(h.projectCreated(project))
+	h.Wait()
+
+	h.projectUpdated(project, project)
+	h.Wait()
+	s.Empty(m.updated)
+}
+
+func (s *NexusHookTestSuite) TestProjectCreatedNotAccepted() {
+	m := &MockProjectManager{reject: true}
+	ctx, cancel := context.WithCancel(context.Background())
+	h := NewNexusHook(m).WithContext(ctx)
+
+	project := NewMockNexusProject("project1", "uid1")
+	err := h.projectCreated(project)
+	s.NoError(err, "Callback must not wait for the dispatcher")
+
+	// The manager shuts down before accepting the event
+	cancel()
+	h.Wait()
+
+	s.Empty(m.created)
+	s.Equal(projectActiveWatcherv1.StatusIndicationError, project.activeWatchers["config-provisioner"].Spec.StatusIndicator, "Expected status to be 'Error'")
+}
+
+func (s *NexusHookTestSuite) TestProjectEventsDispatchedInOrder() {
+	m := &MockProjectManager{gate: make(chan struct{})}
+	h := NewNexusHook(m)
+
+	project := NewMockNexusProject("project1", "uid1")
+	s.NoError(h.projectCreated(project))
+	deleted := NewMockNexusProject("project1", "uid1")
+	deleted.isDeleted = true
+	h.projectUpdated(project, deleted)
+
+	// The delete is not handed to the dispatcher while the create is still waiting for it
+	time.Sleep(50 * time.Millisecond)
+	close(m.gate)
+	h.Wait()
+
+	s.Equal([]string{"create project1", "delete project1"}, m.events)
+	s.Empty(h.pending)
+}
+
+func (s *NexusHookTestSuite) TestUpdateProjectManifestTag() {
+	m := &MockProjectManager{manifestTag: "1.2", controllerVersion: "3.0.0"}
+	h := NewNexusHook(m)
+
+	project := NewMockNexusProject("project1", "uid1")
+	s.NoError(h.projectCreated(project))
+	h.Wait()
+	s.Equal([]string{"project1"}, m.created)
+	watcher := project.activeWatchers["config-provisioner"]
+	watcher.SetAnnotations(map[string]string{"other": "kept"})
+
+	before := time.Now().Add(-time.Second)
+	s.NoError(h.UpdateProjectManifestTag(project))
+	annotations := watcher.GetAnnotations()
+	s.Equal("kept", annotations["other"])
+	versions := ProvisionedVersionsFromAnnotations(annotations)
+	s.Equal("1.2", versions.ManifestTag)
+	s.Equal("3.0.0", versions.ControllerVersion)
+	s.True(versions.ProvisionedAt.After(before))
+
+	// A replayed event for a provisioned project is skipped
+	watcher.Spec.StatusIndicator = projectActiveWatcherv1.StatusIndicationIdle
+	watcher.Spec.Message = "Created"
+	s.NoError(h.projectCreated(project))
+	h.Wait()
+	s.Equal([]string{"project1"}, m.created)
+
+	// ... also when it was provisioned with warnings
+	watcher.Spec.Message = "Created with warnings: extension package base-extensions not loaded"
+	s.NoError(h.projectCreated(project))
+	h.Wait()
+	s.Equal([]string{"project1"}, m.created)
+
+	// ... unless a new controller version provisions it again
+	m.controllerVersion = "3.1.0"
+	s.NoError(h.projectCreated(project))
+	h.Wait()
+	s.Equal([]string{"project1", "project1"}, m.created)
+}
+
+func (s *NexusHookTestSuite) TestSetWatcherInventory() {
+	m := &MockProjectManager{}
+	h := NewNexusHook(m)
+
+	project := NewMockNexusProject("project1", "uid1")
+	s.NoError(h.projectCreated(project))
+	h.Wait()
+	watcher := project.activeWatchers["config-provisioner"]
+	watcher.SetAnnotations(map[string]string{"other": "kept"})
+
+	s.NoError(h.SetWatcherInventory(project, InventorySummary{
+		HarborProject: "org1-project1",
+		Registries:    []string{"harbor-helm", "harbor-docker"},
+		Extensions:    []string{"base-extensions:0.1.0"},
+	}))
+	annotations := watcher.GetAnnotations()
+	s.Equal("kept", annotations["other"])
+	s.Equal(`{"v":1,"harbor":"org1-project1","registries":["harbor-helm","harbor-docker"],"extensions":["base-extensions:0.1.0"]}`,
+		annotations[InventoryAnnotationKey])
+	summary, ok := InventorySummaryFromAnnotations(annotations)
+	s.True(ok)
+	s.Equal("org1-project1", summary.HarborProject)
+}
+
 func (s *NexusHookTestSuite) TestSetWatcherStatusError() {
 	m := &MockProjectManager{}
 	h := NewNexusHook(m)
@@ -118,11 +328,54 @@ func (s *NexusHookTestSuite) TestSetWatcherStatusIdle() {
 	err := h.projectCreated(project)
 	s.NoError(err, "Expected no error when creating project")
 
-	err = h.SetWatcherStatusIdle(project)
+	err = h.SetWatcherStatusIdle(project, "Created with warnings: mirror failed")
 	s.NoError(err, "Expected no error when setting watcher status to idle")
 
 	s.Contains(project.activeWatchers, "config-provisioner", "Expected 'config-provisioner' to be a key in the activeWatchers map")
 	s.Equal(projectActiveWatcherv1.StatusIndicationIdle, project.activeWatchers["config-provisioner"].Spec.StatusIndicator, "Expected status to be 'Idle'")
+	s.Equal("Created with warnings: mirror failed", project.activeWatchers["config-provisioner"].Spec.Message)
+}
+
+func (s *NexusHookTestSuite) TestSetWatcherStatusThrottled() {
+	m := &MockProjectManager{}
+	h := NewNexusHook(m).WithStatusUpdateInterval(time.Minute)
+
+	project := NewMockNexusProject("project1", "uid1")
+	s.NoError(h.projectCreated(project))
+	spec := &project.activeWatchers["config-provisioner"].Spec
+	spec.StatusIndicator = projectActiveWatcherv1.StatusIndicationIdle
+
+	// A change of status is written
+	s.NoError(h.SetWatcherStatusInProgress(project, "Processing project create with Harbor"))
+	s.Equal("Processing project create with Harbor", spec.Message)
+	written := spec.TimeStamp
+
+	// Within the interval, in progress messages and repeated errors are not written again
+	s.NoError(h.SetWatcherStatusInProgress(project, "Retry backoff for project project1"))
+	s.Equal("Processing project create with Harbor", spec.Message)
+	s.NoError(h.SetWatcherStatusError(project, "unavailable"))
+	s.Equal(projectActiveWatcherv1.StatusIndicationError, spec.StatusIndicator)
+	s.NoError(h.SetWatcherStatusInProgress(project, "Retry backoff for project project1"))
+	s.NoError(h.SetWatcherStatusError(project, "unavailable"))
+	spec.TimeStamp = written - 30
+	s.NoError(h.SetWatcherStatusError(project, "unavailable"))
+	s.Equal(written-30, spec.TimeStamp)
+
+	// A different error is written right away
+	s.NoError(h.SetWatcherStatusError(project, "permission denied"))
+	s.Equal("permission denied", spec.Message)
+
+	// Once the interval is over, the status is written again
+	s.NoError(h.SetWatcherStatusInProgress(project, "Processing project create with Harbor"))
+	spec.TimeStamp = written - 60
+	s.NoError(h.SetWatcherStatusInProgress(project, "Processing project create with Catalog"))
+	s.Equal("Processing project create with Catalog", spec.Message)
+	s.GreaterOrEqual(spec.TimeStamp, written)
+
+	// Every update is written without an interval
+	h.WithStatusUpdateInterval(0)
+	s.NoError(h.SetWatcherStatusInProgress(project, "Retry backoff for project project1"))
+	s.Equal("Retry backoff for project project1", spec.Message)
 }
 
 func TestNexusHook(t *testing.T) {
@@ -156,6 +409,7 @@ new line`, "project1")
 
 		project := NewMockNexusProject(displayName, uid)
 		err := h.projectCreated(project)
+		h.Wait()
 
 		if err != nil {
 			allowedErr := []string{"Organization name is empty", "Organization name is too long", "Project name is empty", "Project name is too long", "Project UUID is empty", "Project UUID is too long", "Organization name contains illegal characters", "Project name contains illegal characters"}
@@ -197,10 +451,262 @@ new line`, "project1")
 
 		project := NewMockNexusProject(displayName, uid)
 		project.isDeleted = true
-		h.projectUpdated(project)
+		h.projectUpdated(nil, project)
+		h.Wait()
 
 		assert.Contains(t, m.deleted, displayName, "Expected project to be in the created list")
 
 		s.Equal(0, len(project.activeWatchers), "Expected 0 active watcher")
 	})
 }
+
+func TestProjectStatuses(t *testing.T) {
+	newProject := func(org string, name string, uid string, annotations map[string]string) *nexus.RuntimeprojectRuntimeProject {
+		return &nexus.RuntimeprojectRuntimeProject{RuntimeProject: &runtimeprojectv1.RuntimeProject{
+			ObjectMeta: metav1.ObjectMeta{
+				UID:         types.UID(uid),
+				Labels:      map[string]string{"nexus/display_name": name, runtimeOrgLabel: org},
+				Annotations: annotations,
+			},
+		}}
+	}
+	newWatcher := func(app string, org string, project string, status projectActiveWatcherv1.ActiveWatcherStatus, tag string) *nexus.ProjectactivewatcherProjectActiveWatcher {
+		return &nexus.ProjectactivewatcherProjectActiveWatcher{ProjectActiveWatcher: &projectActiveWatcherv1.ProjectActiveWatcher{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{"nexus/display_name": app, runtimeOrgLabel: org, runtimeProjectLabel: project},
+				Annotations: map[string]string{ManifestTagAnnotationKey: tag, ControllerVersionAnnotationKey: "3.0.0", ProvisionedAtAnnotationKey: "2026-01-02T03:04:05Z"},
+			},
+			Spec: projectActiveWatcherv1.ProjectActiveWatcherSpec{StatusIndicator: status, Message: string(status)},
+		}}
+	}
+
+	statuses := projectStatuses(
+		[]*nexus.RuntimeprojectRuntimeProject{
+			newProject("org2", "p1", "uid3", nil),
+			newProject("org1", "p2", "uid2", map[string]string{ProvisioningProfileAnnotationKey: "large"}),
+			newProject("org1", "p1", "uid1", nil),
+		},
+		[]*nexus.ProjectactivewatcherProjectActiveWatcher{
+			newWatcher(appName, "org1", "p1", projectActiveWatcherv1.StatusIndicationIdle, "1.0"),
+			newWatcher(appName, "org1", "p2", projectActiveWatcherv1.StatusIndicationError, ""),
+			newWatcher("other-app", "org2", "p1", projectActiveWatcherv1.StatusIndicationIdle, ""),
+		},
+	)
+
+	// Labels and annotations are copied from the project
+	assert.Equal(t, "org1", statuses[1].Labels[runtimeOrgLabel])
+	assert.Equal(t, "large", statuses[1].Annotations[ProvisioningProfileAnnotationKey])
+	for i := range statuses {
+		statuses[i].Labels, statuses[i].Annotations = nil, nil
+	}
+
+	provisionedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.Equal(t, []ProjectStatus{
+		{Organization: "org1", Name: "p1", UUID: "uid1", Status: string(projectActiveWatcherv1.StatusIndicationIdle), Message: string(projectActiveWatcherv1.StatusIndicationIdle), ManifestTag: "1.0", ControllerVersion: "3.0.0", ProvisionedAt: provisionedAt},
+		{Organization: "org1", Name: "p2", UUID: "uid2", Profile: "large", Status: string(projectActiveWatcherv1.StatusIndicationError), Message: string(projectActiveWatcherv1.StatusIndicationError), ControllerVersion: "3.0.0", ProvisionedAt: provisionedAt},
+		{Organization: "org2", Name: "p1", UUID: "uid3"},
+	}, statuses)
+}
+
+func TestProvisionedVersions(t *testing.T) {
+	versions := ProvisionedVersionsFromAnnotations(map[string]string{
+		ManifestTagAnnotationKey:       "1.0",
+		ControllerVersionAnnotationKey: "3.0.0",
+		ProvisionedAtAnnotationKey:     "not a time",
+	})
+	assert.Equal(t, ProvisionedVersions{ManifestTag: "1.0", ControllerVersion: "3.0.0"}, versions)
+
+	assert.True(t, versions.UpToDate("1.0", "3.0.0"))
+	assert.False(t, versions.UpToDate("1.1", "3.0.0"))
+	assert.False(t, versions.UpToDate("1.0", "3.1.0"))
+	// Unknown controller versions are not compared
+	assert.True(t, versions.UpToDate("1.0", ""))
+	assert.True(t, ProvisionedVersions{ManifestTag: "1.0"}.UpToDate("1.0", "3.1.0"))
+
+	provisionedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	annotations := ProvisionedVersions{ManifestTag: "1.1", ProvisionedAt: provisionedAt}.SetAnnotations(map[string]string{
+		"other":                        "kept",
+		ControllerVersionAnnotationKey: "3.0.0",
+	})
+	assert.Equal(t, map[string]string{
+		"other":                        "kept",
+		ManifestTagAnnotationKey:       "1.1",
+		ControllerVersionAnnotationKey: "3.0.0",
+		ProvisionedAtAnnotationKey:     "2026-01-02T03:04:05Z",
+	}, annotations)
+}
+
+func TestInventorySummary(t *testing.T) {
+	_, ok := InventorySummaryFromAnnotations(map[string]string{})
+	assert.False(t, ok)
+	_, ok = InventorySummaryFromAnnotations(map[string]string{InventoryAnnotationKey: "not json"})
+	assert.False(t, ok)
+
+	summary := InventorySummary{HarborProject: "org1-project1", Registries: []string{"harbor-helm"}}
+	annotation, err := summary.Annotation()
+	assert.NoError(t, err)
+	read, ok := InventorySummaryFromAnnotations(map[string]string{InventoryAnnotationKey: annotation})
+	assert.True(t, ok)
+	summary.Version = InventorySummaryVersion
+	assert.Equal(t, summary, read)
+
+	// Extensions are left out until the summary fits in the annotation
+	for i := 0; i < 500; i++ {
+		summary.Extensions = append(summary.Extensions, fmt.Sprintf("extension-%d:1.0.0", i))
+	}
+	annotation, err = summary.Annotation()
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, len(annotation), MaxInventoryAnnotationSize)
+	read, ok = InventorySummaryFromAnnotations(map[string]string{InventoryAnnotationKey: annotation})
+	assert.True(t, ok)
+	assert.True(t, read.Truncated)
+	assert.Equal(t, []string{"harbor-helm"}, read.Registries)
+	assert.NotEmpty(t, read.Extensions)
+	assert.Equal(t, summary.Extensions[:len(read.Extensions)], read.Extensions)
+}
+
+type mockConnection struct {
+	checkErr     error
+	resubscribes int
+	projects     []NexusProjectInterface
+}
+
+func (c *mockConnection) Check(_ context.Context) error {
+	return c.checkErr
+}
+
+func (c *mockConnection) Resubscribe(_ context.Context) ([]NexusProjectInterface, error) {
+	c.resubscribes++
+	return c.projects, nil
+}
+
+func (c *mockConnection) List(_ context.Context) ([]NexusProjectInterface, error) {
+	return c.projects, nil
+}
+
+func (s *NexusHookTestSuite) TestReconnect() {
+	m := &MockProjectManager{}
+	connection := &mockConnection{}
+	h := NewNexusHook(m)
+	h.connection = connection
+
+	kept := NewMockNexusProject("kept", "uid1")
+	removed := NewMockNexusProject("removed", "uid2")
+	s.NoError(h.projectCreated(kept))
+	h.Wait()
+	s.NoError(h.projectCreated(removed))
+	h.Wait()
+	s.Equal([]string{"kept", "removed"}, m.created)
+
+	// Nothing happens while the connection is up
+	lostAt := h.checkConnection(time.Time{})
+	s.True(lostAt.IsZero())
+	s.Equal(0, connection.resubscribes)
+
+	// The connection is lost, and only resubscribed once it is back
+	connection.checkErr = errors.New("connection refused")
+	lostAt = h.checkConnection(lostAt)
+	s.False(lostAt.IsZero())
+	s.Equal(lostAt, h.checkConnection(lostAt))
+	s.Equal(0, connection.resubscribes)
+
+	connection.checkErr = nil
+	connection.projects = []NexusProjectInterface{kept}
+	lostAt = h.checkConnection(lostAt)
+	h.Wait()
+	s.True(lostAt.IsZero())
+	s.Equal(1, connection.resubscribes)
+
+	// The project removed during the gap is deleted, and only once
+	s.Equal([]string{"removed"}, m.deleted)
+	s.NoError(h.resync())
+	h.Wait()
+	s.Equal([]string{"removed"}, m.deleted)
+	s.Contains(h.projects, "uid1")
+	s.NotContains(h.projects, "uid2")
+}
+
+func (s *NexusHookTestSuite) TestStartupResync() {
+	m := &MockProjectManager{manifestTag: "1.2", controllerVersion: "3.0.0"}
+	h := NewNexusHook(m)
+
+	provisionedProject := func(name string, uid string, manifestTag string) *MockNexusProject {
+		project := NewMockNexusProject(name, uid)
+		watcher := &MockNexusProjectActiveWatcher{ProjectActiveWatcher: &projectActiveWatcherv1.ProjectActiveWatcher{}}
+		watcher.Spec.StatusIndicator = projectActiveWatcherv1.StatusIndicationIdle
+		watcher.Spec.Message = "Created"
+		watcher.SetAnnotations(ProvisionedVersions{ManifestTag: manifestTag, ControllerVersion: "3.0.0"}.SetAnnotations(nil))
+		project.activeWatchers[appName] = watcher
+		return project
+	}
+	upToDate := provisionedProject("up-to-date", "uid1", "1.2")
+	outdated := provisionedProject("outdated", "uid2", "1.1")
+	deleted := provisionedProject("deleted", "uid3", "1.2")
+	deleted.isDeleted = true
+	unwatched := NewMockNexusProject("unwatched", "uid4")
+	h.connection = &mockConnection{projects: []NexusProjectInterface{upToDate, outdated, deleted, unwatched}}
+
+	// Only the projects skipped by the subscription replay are converged, with ensure events
+	s.NoError(h.ensureProjects())
+	s.Equal([]string{"up-to-date"}, m.ensured)
+	s.Empty(m.created)
+
+	// The resync stops if the dispatcher does not accept an event
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	m.reject = true
+	s.ErrorIs(h.WithContext(ctx).ensureProjects(), context.Canceled)
+}
+
+func (s *NexusHookTestSuite) TestMissingOrganization() {
+	m := &MockProjectManager{}
+	h := NewNexusHook(m)
+	notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "runtimefolders"}, "folder")
+
+	// Projects without an organization are reported in error rather than provisioned with an empty name
+	orphan := NewMockNexusProject("orphan", "uid1")
+	orphan.parentErr = notFound
+	s.ErrorContains(h.projectCreated(orphan), "not found")
+	blank := NewMockNexusProject("blank", "uid2")
+	blank.parent = &MockNexusFolder{parent: &MockNexusOrganization{displayName: " "}}
+	s.ErrorContains(h.projectCreated(blank), "organization name is empty")
+	h.Wait()
+	s.Empty(m.created)
+	s.Equal(projectActiveWatcherv1.StatusIndicationError, orphan.activeWatchers[appName].Spec.StatusIndicator)
+	s.Equal(projectActiveWatcherv1.StatusIndicationError, blank.activeWatchers[appName].Spec.StatusIndicator)
+
+	// ... nor deleted
+	orphan.isDeleted = true
+	h.projectUpdated(nil, orphan)
+	h.Wait()
+	s.Empty(m.deleted)
+
+	// With a fallback organization, they are provisioned in it
+	h.WithFallbackOrganization("unassigned")
+	orphan.isDeleted = false
+	s.NoError(h.projectCreated(orphan))
+	h.Wait()
+	s.NoError(h.projectCreated(blank))
+	h.Wait()
+	s.Equal([]string{"orphan", "blank"}, m.created)
+	s.Equal("unassigned", m.organizations["orphan"])
+	s.Equal("unassigned", m.organizations["blank"])
+
+	// ... but not if the organization cannot be looked up
+	unreachable := NewMockNexusProject("unreachable", "uid3")
+	unreachable.parentErr = errors.New("context deadline exceeded")
+	s.ErrorContains(h.projectCreated(unreachable), "deadline")
+	h.Wait()
+	s.NotContains(m.created, "unreachable")
+
+	// A project whose organization is removed first is deleted in the organization it was provisioned in
+	project := NewMockNexusProject("project1", "uid4")
+	s.NoError(h.projectCreated(project))
+	h.Wait()
+	project.parentErr = notFound
+	project.isDeleted = true
+	h.projectUpdated(nil, project)
+	h.Wait()
+	s.Equal([]string{"project1"}, m.deleted)
+	s.Equal("MockNexusOrganization", m.organizations["project1"])
+}
@@ -0,0 +1,237 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package nexus
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// DefaultHealthCheckInterval is the time between checks of the connection to the Nexus API server
+const DefaultHealthCheckInterval = 30 * time.Second
+
+var runtimeProjectsResource = schema.GroupVersionResource{
+	Group:    "runtimeproject.edge-orchestrator.intel.com",
+	Version:  "v1",
+	Resource: "runtimeprojects",
+}
+
+var (
+	nexusConnected = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "tenant_controller_nexus_connected",
+		Help: "1 if the last check of the connection to the Nexus API server succeeded, 0 otherwise",
+	})
+
+	nexusSubscriptionGaps = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "tenant_controller_nexus_subscription_gaps_total",
+		Help: "Times the connection to the Nexus API server was lost and the subscriptions had to be re-established",
+	})
+
+	nexusResyncDeletes = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "tenant_controller_nexus_resync_deletes_total",
+		Help: "Delete events dispatched by a resync for projects that were removed while the subscription was down",
+	})
+
+	startupResyncProjects = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "tenant_controller_startup_resync_projects_total",
+		Help: "Up to date projects converged by the startup resync",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(nexusConnected, nexusSubscriptionGaps, nexusResyncDeletes, startupResyncProjects)
+}
+
+// nexusConnection is the part of the Nexus client used to detect a lost connection and recover from it.
+type nexusConnection interface {
+	// Check makes a request to the API server, bypassing the subscription caches
+	Check(ctx context.Context) error
+	// Resubscribe discards the subscriptions and returns the projects listed by the API server, then subscribes
+	// again. The new subscriptions replay an add event for every project.
+	Resubscribe(ctx context.Context) ([]NexusProjectInterface, error)
+	// List returns the projects listed by the API server
+	List(ctx context.Context) ([]NexusProjectInterface, error)
+}
+
+// clientConnection is the nexusConnection of the Nexus client of a hook.
+type clientConnection struct {
+	hook *Hook
+}
+
+func (c *clientConnection) Check(ctx context.Context) error {
+	_, err := c.hook.nexusClient.DynamicClient.Resource(runtimeProjectsResource).List(ctx, metav1.ListOptions{Limit: 1})
+	return err
+}
+
+func (c *clientConnection) Resubscribe(ctx context.Context) ([]NexusProjectInterface, error) {
+	c.hook.nexusClient.UnsubscribeAll()
+	// Without subscriptions, the list is read from the API server rather than from the cache
+	projects, err := c.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return projects, c.hook.subscribe()
+}
+
+func (c *clientConnection) List(ctx context.Context) ([]NexusProjectInterface, error) {
+	nexusProjects, err := c.hook.nexusClient.Runtimeproject().ListRuntimeProjects(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	projects := make([]NexusProjectInterface, 0, len(nexusProjects))
+	for _, nexusProject := range nexusProjects {
+		projects = append(projects, (*NexusProject)(nexusProject))
+	}
+	return projects, nil
+}
+
+// knownProject is a project this app has accepted a create event for, with its organization name, so that it can
+// still be deleted if it disappears while the subscription is down.
+type knownProject struct {
+	organization string
+	project      NexusProjectInterface
+}
+
+func (h *Hook) trackProject(organizationName string, project NexusProjectInterface) {
+	h.projectsLock.Lock()
+	defer h.projectsLock.Unlock()
+	h.projects[project.GetUID()] = knownProject{organization: organizationName, project: project}
+}
+
+// untrackProject stops tracking the project, and returns it as it was tracked if it was.
+func (h *Hook) untrackProject(project NexusProjectInterface) (knownProject, bool) {
+	h.projectsLock.Lock()
+	defer h.projectsLock.Unlock()
+	known, ok := h.projects[project.GetUID()]
+	delete(h.projects, project.GetUID())
+	return known, ok
+}
+
+// monitor checks the connection to the Nexus API server until the hook context is done. Once the connection is
+// back after a failed check, the subscriptions are re-established and the projects resynchronized.
+func (h *Hook) monitor() {
+	ticker := time.NewTicker(h.healthCheckInterval)
+	defer ticker.Stop()
+	var lostAt time.Time
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-ticker.C:
+			lostAt = h.checkConnection(lostAt)
+		}
+	}
+}
+
+// checkConnection checks the connection once, given the time it was lost or zero if it was up at the last check,
+// and returns the time it is lost since, or zero once it is up and the subscriptions are re-established.
+func (h *Hook) checkConnection(lostAt time.Time) time.Time {
+	ctx, cancel := h.nexusContext()
+	defer cancel()
+	if err := h.connection.Check(ctx); err != nil {
+		nexusConnected.Set(0)
+		if lostAt.IsZero() {
+			log.Warnf("Lost the connection to the Nexus API server, project events may be missed until it is back: %v", err)
+			return time.Now()
+		}
+		log.Warnf("Nexus API server unreachable for %v: %v", time.Since(lostAt).Round(time.Second), err)
+		return lostAt
+	}
+	nexusConnected.Set(1)
+	if lostAt.IsZero() {
+		return lostAt
+	}
+
+	log.Warnf("Connection to the Nexus API server is back after %v, resubscribing and resynchronizing projects",
+		time.Since(lostAt).Round(time.Second))
+	if err := h.resync(); err != nil {
+		log.Warnf("Unable to resynchronize projects, retrying at the next check: %v", err)
+		return lostAt
+	}
+	nexusSubscriptionGaps.Inc()
+	return time.Time{}
+}
+
+// resync re-establishes the subscriptions and dispatches the events missed while they were down. The new
+// subscriptions replay an add event for every project, which provisions projects created during the gap and deletes
+// projects marked for deletion; projects that were removed altogether are deleted here.
+func (h *Hook) resync() error {
+	ctx, cancel := h.nexusContext()
+	defer cancel()
+	projects, err := h.connection.Resubscribe(ctx)
+	if err != nil {
+		return err
+	}
+	listed := make(map[string]bool, len(projects))
+	for _, project := range projects {
+		listed[project.GetUID()] = true
+	}
+
+	h.projectsLock.Lock()
+	var vanished []knownProject
+	for uid, known := range h.projects {
+		if !listed[uid] {
+			vanished = append(vanished, known)
+			delete(h.projects, uid)
+		}
+	}
+	h.projectsLock.Unlock()
+
+	for _, known := range vanished {
+		project := known.project
+		log.Warnf("Project %s was removed while the Nexus subscription was down, dispatching delete event", project.DisplayName())
+		nexusResyncDeletes.Inc()
+		h.dispatchAsync(project, "delete", func(ctx context.Context) error {
+			return h.dispatcher.DeleteProject(ctx, known.organization, project.DisplayName(), project.GetUID(), project)
+		})
+	}
+	log.Infof("Resynchronized %d Nexus projects, %d removed during the gap", len(projects), len(vanished))
+	return nil
+}
+
+// ensureProjects converges the projects that the subscription replay skips because they are provisioned with the
+// current manifest tag and controller version, so that configuration changes reach them too. Ensure events leave the
+// robot accounts and other resources of the healthy projects in place. The replay handles the other projects.
+// Events are handed to the dispatcher one at a time, so the resync waits for free queue slots rather than filling
+// the queue.
+func (h *Hook) ensureProjects() error {
+	ctx, cancel := h.nexusContext()
+	projects, err := h.connection.List(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	ensured := 0
+	for _, project := range projects {
+		if project.IsDeleted() {
+			continue
+		}
+		ctx, cancel := h.nexusContext()
+		watcher, err := project.GetActiveWatchers(ctx, appName)
+		cancel()
+		if err != nil || !h.upToDate(watcher) {
+			continue
+		}
+		organizationName, err := h.organizationName(project)
+		if err != nil {
+			log.Warnf("Skipping startup resync of project %s: %v", project.DisplayName(), err)
+			continue
+		}
+		log.Infof("Startup resync of project %s in organization %s", project.DisplayName(), organizationName)
+		if err := h.dispatcher.EnsureProject(h.ctx, organizationName, project.DisplayName(), project.GetUID(), project); err != nil {
+			return err
+		}
+		startupResyncProjects.Inc()
+		ensured++
+	}
+	log.Infof("Startup resync queued %d of %d projects", ensured, len(projects))
+	return nil
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package nexus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	nexus "github.com/open-edge-platform/orch-utils/tenancy-datamodel/build/nexus-client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// Labels set by Nexus on child nodes, holding the display names of their parents
+	runtimeOrgLabel     = "runtimeorgs.runtimeorg.edge-orchestrator.intel.com"
+	runtimeProjectLabel = "runtimeprojects.runtimeproject.edge-orchestrator.intel.com"
+)
+
+// ProjectStatus is the provisioning state of a project as recorded on the project watcher of this app.
+type ProjectStatus struct {
+	Organization string
+	Name         string
+	UUID         string
+	Deleted      bool
+	// requested provisioning profile, empty if the project does not request one
+	Profile string
+	// watcher status indicator, empty if the project is not watched by this app
+	Status      string
+	Message     string
+	ManifestTag string
+	// controller version that last provisioned the project, empty if not recorded
+	ControllerVersion string
+	// time of the last successful provisioning, zero if not recorded
+	ProvisionedAt time.Time
+	TimeStamp     uint64
+	Labels        map[string]string
+	Annotations   map[string]string
+}
+
+// ListProjectStatus reads the provisioning status of every project directly from the Kubernetes API, without
+// subscribing to Nexus. The result is sorted by organization and project name.
+func ListProjectStatus(ctx context.Context, cfg *rest.Config) ([]ProjectStatus, error) {
+	client, err := nexus.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Nexus client: %w", err)
+	}
+	projects, err := client.Runtimeproject().ListRuntimeProjects(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list projects: %w", err)
+	}
+	watchers, err := client.Projectactivewatcher().ListProjectActiveWatchers(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list project watchers: %w", err)
+	}
+	return projectStatuses(projects, watchers), nil
+}
+
+func projectStatuses(projects []*nexus.RuntimeprojectRuntimeProject, watchers []*nexus.ProjectactivewatcherProjectActiveWatcher) []ProjectStatus {
+	type projectKey struct {
+		org     string
+		project string
+	}
+	ownWatchers := make(map[projectKey]*nexus.ProjectactivewatcherProjectActiveWatcher)
+	for _, watcher := range watchers {
+		if watcher.DisplayName() != appName {
+			continue
+		}
+		labels := watcher.GetLabels()
+		ownWatchers[projectKey{org: labels[runtimeOrgLabel], project: labels[runtimeProjectLabel]}] = watcher
+	}
+
+	statuses := make([]ProjectStatus, 0, len(projects))
+	for _, project := range projects {
+		status := ProjectStatus{
+			Organization: project.GetLabels()[runtimeOrgLabel],
+			Name:         project.DisplayName(),
+			UUID:         string(project.UID),
+			Deleted:      project.Spec.Deleted,
+			Profile:      project.GetAnnotations()[ProvisioningProfileAnnotationKey],
+			Labels:       project.GetLabels(),
+			Annotations:  project.GetAnnotations(),
+		}
+		if watcher, ok := ownWatchers[projectKey{org: status.Organization, project: status.Name}]; ok {
+			status.Status = string(watcher.Spec.StatusIndicator)
+			status.Message = watcher.Spec.Message
+			status.TimeStamp = watcher.Spec.TimeStamp
+			versions := ProvisionedVersionsFromAnnotations(watcher.GetAnnotations())
+			status.ManifestTag = versions.ManifestTag
+			status.ControllerVersion = versions.ControllerVersion
+			status.ProvisionedAt = versions.ProvisionedAt
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Organization != statuses[j].Organization {
+			return statuses[i].Organization < statuses[j].Organization
+		}
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
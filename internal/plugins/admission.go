@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package plugins
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Platform capacity thresholds
+const (
+	// CapacityHarborStorage caps the bytes used by all Harbor projects
+	CapacityHarborStorage = "harbor_storage"
+	// CapacityCatalogProjects caps the projects provisioned in the catalog
+	CapacityCatalogProjects = "catalog_projects"
+)
+
+// ErrCapacityExceeded is returned for a new project while the platform is at one of its capacity thresholds. It is a
+// permanent error: the project is provisioned by its next create event once capacity is freed or the threshold is
+// raised, e.g. when the controller restarts.
+var ErrCapacityExceeded = fmt.Errorf("%w: platform capacity exceeded", southbound.ErrPermanent)
+
+var admissionRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "tenant_controller_admission_rejections_total",
+	Help: "New projects refused because the platform is at a capacity threshold, by threshold",
+}, []string{"threshold"})
+
+func init() {
+	metrics.Registry.MustRegister(admissionRejections)
+}
+
+// AdmissionPlugin is implemented by plugins that check the capacity of their service before a new project is
+// provisioned. All admission checks run before the first plugin handles a create event, so that a refused project
+// is not left half provisioned. Projects that were already provisioned must be admitted, as they are sent create
+// events to upgrade them, and other events are never checked, so that existing projects keep working.
+type AdmissionPlugin interface {
+	Admit(context.Context, Event) error
+}
+
+// admit runs the admission checks of the plugins that handle the create event.
+func admit(ctx context.Context, event Event, lifecycle *Lifecycle) error {
+	for _, plugin := range plugins {
+		admissionPlugin, ok := plugin.(AdmissionPlugin)
+		if !ok || !event.handledBy(plugin) {
+			continue
+		}
+		if err := lifecycle.provision(plugin.Name()); err != nil {
+			return err
+		}
+		event.ReportProgress("Checking platform capacity with %s", plugin.Name())
+		if err := admissionPlugin.Admit(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// atCapacity reports whether the used capacity has reached the limit, where a limit of 0 or less means there is no
+// limit.
+func atCapacity(used int64, limit int64) bool {
+	return limit > 0 && used >= limit
+}
+
+// capacityExceeded counts the refusal of a new project at the limit of the threshold and returns ErrCapacityExceeded.
+func capacityExceeded(threshold string, setting string, used int64, limit int64) error {
+	admissionRejections.WithLabelValues(threshold).Inc()
+	return fmt.Errorf("%w: %s, the maximum is %d (%s), no new projects are provisioned", ErrCapacityExceeded,
+		fmt.Sprintf(capacityUsage[threshold], used), limit, setting)
+}
+
+// usage of the capacity thresholds in error messages
+var capacityUsage = map[string]string{
+	CapacityHarborStorage:   "%d bytes of Harbor storage are used",
+	CapacityCatalogProjects: "%d projects are provisioned in the catalog",
+}
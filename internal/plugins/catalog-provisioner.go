@@ -6,28 +6,49 @@ package plugins
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/retry"
 	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
 	"strings"
 	"time"
 )
 
+// Keys of the Harbor section of the plugin data
 const (
 	HarborTokenName    = `harborToken`
 	HarborUsernameName = `harborUsername`
+	// set to "false" by the Harbor plugin when an existing robot account was kept with its current secret
+	HarborCredentialsChangedName = `harborCredentialsChanged`
+	// credentials of the pull-only robot account, for consumers of the project images
+	HarborPullTokenName              = `harborPullToken`
+	HarborPullUsernameName           = `harborPullUsername`
+	HarborPullCredentialsChangedName = `harborPullCredentialsChanged`
 )
 
 type Catalog interface {
 	CreateOrUpdateRegistry(ctx context.Context, attrs southbound.RegistryAttributes) error
+	RegistryExists(ctx context.Context, projectUUID string, name string) (bool, error)
+	GetRegistry(ctx context.Context, projectUUID string, name string) (southbound.RegistryAttributes, error)
+	ListProjectRegistries(ctx context.Context, projectUUID string) ([]southbound.RegistryAttributes, error)
+	UpdateRegistryCredentials(ctx context.Context, projectUUID string, name string, username string, authToken string) error
+	ClearRegistryCredentials(ctx context.Context, projectUUID string, name string) error
 	ListRegistries(ctx context.Context) error
 	UploadYAMLFile(ctx context.Context, projectUUID string, fileName string, artifact []byte, lastFile bool) error
+	CommitUpload(ctx context.Context, projectUUID string, upload *southbound.CatalogUpload) error
 	InitializeClientSecret(ctx context.Context) (string, error)
-	WipeProject(ctx context.Context, projectUUID string, catalogServer string) error
+	DeploymentPackageApplications(ctx context.Context, projectUUID string, name string, version string) ([]string, error)
+	ListProjectFiles(ctx context.Context, projectUUID string) ([]southbound.ProjectFile, error)
+	DeleteProjectFile(ctx context.Context, projectUUID string, file southbound.ProjectFile) error
+	VerifyProjectOwnership(ctx context.Context, projectUUID string, recorded []string) error
+	WipeProject(ctx context.Context, projectUUID string, catalogServer string, progress func(message string)) error
 }
 
 type CatalogProvisionerPlugin struct {
-	config config.Configuration
+	config      config.Configuration
+	registries  []RegistryTemplate
+	starterApps []StarterApp
 }
 
 func NewCatalog(config config.Configuration) (Catalog, error) {
@@ -37,10 +58,19 @@ func NewCatalog(config config.Configuration) (Catalog, error) {
 var CatalogFactory = NewCatalog
 
 func NewCatalogProvisionerPlugin(config config.Configuration) (*CatalogProvisionerPlugin, error) {
+	registries, err := loadRegistryTemplates(config)
+	if err != nil {
+		return nil, err
+	}
+	starterApps, err := loadStarterApps(config)
+	if err != nil {
+		return nil, err
+	}
 	return &CatalogProvisionerPlugin{
-		config: config,
+		config:      config,
+		registries:  registries,
+		starterApps: starterApps,
 	}, nil
-
 }
 
 func (p *CatalogProvisionerPlugin) waitForCatalog(ctx context.Context) error {
@@ -50,33 +80,20 @@ func (p *CatalogProvisionerPlugin) waitForCatalog(ctx context.Context) error {
 		return fmt.Errorf("failed to create catalog client: %w", err)
 	}
 
-	maxRetries := 12 // Total wait time: ~5 minutes
-	retryDelay := 5 * time.Second
-
-	for attempt := 1; attempt <= maxRetries; attempt++ {
+	err = retry.Do(ctx, "Catalog ping", serviceBackoff, southbound.IsRetryable, func(ctx context.Context) error {
 		lctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
 		err := catalog.ListRegistries(lctx)
-		cancel()
-
-		if err == nil || strings.Contains(err.Error(), "Unauthenticated") {
-			log.Info("Catalog ready")
+		if err != nil && strings.Contains(err.Error(), "Unauthenticated") {
 			return nil
 		}
-
-		if attempt == maxRetries {
-			return fmt.Errorf("catalog not available after %d attempts: %w", maxRetries, err)
-		}
-
-		log.Infof("Catalog ping failed (attempt %d/%d): %v. Retrying in %v...", attempt, maxRetries, err, retryDelay)
-		time.Sleep(retryDelay)
-
-		retryDelay = retryDelay * 2
-		if retryDelay > 60*time.Second {
-			retryDelay = 60 * time.Second
-		}
+		return err
+	})
+	if err != nil {
+		return retryFailed("catalog not available", err)
 	}
-
-	return fmt.Errorf("catalog not available after maximum retries")
+	log.Info("Catalog ready")
+	return nil
 }
 
 func (p *CatalogProvisionerPlugin) waitForVault(ctx context.Context) error {
@@ -86,36 +103,20 @@ func (p *CatalogProvisionerPlugin) waitForVault(ctx context.Context) error {
 		return fmt.Errorf("failed to create catalog client for vault: %w", err)
 	}
 
-	maxRetries := 12 // Total wait time: ~5 minutes
-	retryDelay := 5 * time.Second
-
-	for attempt := 1; attempt <= maxRetries; attempt++ {
+	err = retry.Do(ctx, "Vault login", serviceBackoff, southbound.IsRetryable, func(ctx context.Context) error {
 		lctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
 		_, err := catalog.InitializeClientSecret(lctx)
-		cancel()
-
-		if err == nil {
-			log.Info("Vault ready")
-			return nil
-		}
-
-		if attempt == maxRetries {
-			return fmt.Errorf("vault not available after %d attempts: %w", maxRetries, err)
-		}
-
-		log.Infof("Vault login failed (attempt %d/%d): %v. Retrying in %v...", attempt, maxRetries, err, retryDelay)
-		time.Sleep(retryDelay)
-
-		retryDelay = retryDelay * 2
-		if retryDelay > 60*time.Second {
-			retryDelay = 60 * time.Second
-		}
+		return err
+	})
+	if err != nil {
+		return retryFailed("vault not available", err)
 	}
-
-	return fmt.Errorf("vault not available after maximum retries")
+	log.Info("Vault ready")
+	return nil
 }
 
-func (p *CatalogProvisionerPlugin) Initialize(ctx context.Context, _ PluginData) error {
+func (p *CatalogProvisionerPlugin) Initialize(ctx context.Context, _ *PluginData) error {
 	var err error
 	var catalog Catalog
 	catalog, err = CatalogFactory(p.config)
@@ -123,6 +124,10 @@ func (p *CatalogProvisionerPlugin) Initialize(ctx context.Context, _ PluginData)
 		return fmt.Errorf("catalog initialization failed: %w", err)
 	}
 
+	if err := waitForKeycloak(ctx, p.config); err != nil {
+		return fmt.Errorf("catalog initialization failed during keycloak check: %w", err)
+	}
+
 	if err := p.waitForVault(ctx); err != nil {
 		return fmt.Errorf("catalog initialization failed during vault check: %w", err)
 	}
@@ -140,7 +145,19 @@ func (p *CatalogProvisionerPlugin) Initialize(ctx context.Context, _ PluginData)
 	return nil
 }
 
-func (p *CatalogProvisionerPlugin) CreateEvent(ctx context.Context, event Event, pluginData PluginData) error {
+// RefreshSecrets initializes the client secret again with the current Keycloak admin password.
+func (p *CatalogProvisionerPlugin) RefreshSecrets(ctx context.Context) error {
+	catalog, err := CatalogFactory(p.config)
+	if err != nil {
+		return err
+	}
+	return retry.Do(ctx, "Client secret refresh", configurationBackoff, southbound.IsRetryable, func(ctx context.Context) error {
+		_, err := catalog.InitializeClientSecret(ctx)
+		return err
+	})
+}
+
+func (p *CatalogProvisionerPlugin) CreateEvent(ctx context.Context, event Event, pluginData *PluginData) error {
 	var err error
 	var catalog Catalog
 	catalog, err = CatalogFactory(p.config)
@@ -148,97 +165,237 @@ func (p *CatalogProvisionerPlugin) CreateEvent(ctx context.Context, event Event,
 		return err
 	}
 
-	rsReposDescription := fmt.Sprintf("Repo on registry %s", strings.ReplaceAll(p.config.ReleaseServiceRootURL, "oci://", ""))
-	rsHelmRegistryAttrs := southbound.RegistryAttributes{
-		Name:        `intel-rs-helm`,
-		DisplayName: `intel-rs-helm`,
-		Description: rsReposDescription,
-		Type:        `HELM`,
-		ProjectUUID: event.UUID,
-		RootURL:     p.config.ReleaseServiceProxyRootURL,
-	}
-	err = catalog.CreateOrUpdateRegistry(ctx, rsHelmRegistryAttrs)
+	credentials := pluginData.HarborCredentials()
+	pullCredentials := pluginData.HarborPullCredentials()
+	data := newRegistryTemplateData(p.config, event, credentials, pullCredentials)
+	affixes, err := projectRegistryAffixes(ctx, p.config, event.UUID)
 	if err != nil {
-		log.Errorf("Error creating registry intel-rs-helm: %v", err)
 		return err
 	}
 
-	rsDockerRegistryAttrs := southbound.RegistryAttributes{
-		Name:        `intel-rs-images`,
-		DisplayName: `intel-rs-image`,
-		Description: rsReposDescription,
-		Type:        `IMAGE`,
-		ProjectUUID: event.UUID,
-		RootURL:     p.config.ReleaseServiceRootURL,
-	}
-	err = catalog.CreateOrUpdateRegistry(ctx, rsDockerRegistryAttrs)
-	if err != nil {
-		log.Errorf("Error creating registry intel-rs-images: %v", err)
+	if err := checkQuota(QuotaCatalogRegistries, "MAX_CATALOG_REGISTRIES", len(p.registries), p.config.MaxCatalogRegistries); err != nil {
 		return err
 	}
 
-	var (
-		token    string
-		username string
-		cacerts  string
-	)
-
-	if v, ok := (*pluginData)[HarborTokenName]; ok {
-		token = v
-	}
-	if v, ok := (*pluginData)[HarborUsernameName]; ok {
-		username = v
+	credentialsUnchanged := credentials.Kept
+	pullCredentialsUnchanged := pullCredentials.Kept
+	registryNames := []string{}
+	for i, registry := range p.registries {
+		attrs, err := registry.expand(data)
+		if err != nil {
+			return err
+		}
+		attrs.Name = affixes.name(attrs.Name)
+		if credentialsUnchanged && registry.usesHarborCredentials() ||
+			pullCredentialsUnchanged && registry.usesHarborPullCredentials() {
+			// The robot secret is not known, so the registry can only be kept as it is
+			exists, err := catalog.RegistryExists(ctx, event.UUID, attrs.Name)
+			if err != nil {
+				return err
+			}
+			if !exists && event.EventType == "ensure" {
+				// Recreating the registry needs new robot secrets, which ensure events do not issue
+				event.ReportWarning("Catalog registry %s is missing, provision the project with new Harbor credentials to recreate it", attrs.Name)
+				continue
+			}
+			if !exists {
+				return fmt.Errorf("registry %s is missing and the Harbor robot credentials were not refreshed", attrs.Name)
+			}
+			registryNames = append(registryNames, attrs.Name)
+			log.Infof("Harbor credentials unchanged, keeping registry %s", attrs.Name)
+			continue
+		}
+		if err := attrs.ValidateCredentials(); err != nil {
+			if registry.usesHarborCredentials() && credentials.Token == "" ||
+				registry.usesHarborPullCredentials() && pullCredentials.Token == "" {
+				// No Harbor robot account was created for the project, e.g. the Harbor plugin is disabled
+				event.ReportWarning("Skipping catalog registry %s, the Harbor robot credentials are not known", attrs.Name)
+				continue
+			}
+			return err
+		}
+		registryNames = append(registryNames, attrs.Name)
+		event.ReportProgress("Creating catalog registries %d/%d", i+1, len(p.registries))
+		err = catalog.CreateOrUpdateRegistry(ctx, attrs)
+		if err != nil {
+			log.Errorf("Error creating registry %s: %v", attrs.Name, err)
+			return err
+		}
+		if err := verifyRegistry(ctx, catalog, event, attrs); err != nil {
+			return err
+		}
 	}
+	recordInventory(pluginData, func(inventory *southbound.Inventory) {
+		inventory.CatalogRegistries = registryNames
+		inventory.CatalogRegistryPrefix = affixes.prefix
+		inventory.CatalogRegistrySuffix = affixes.suffix
+	})
 
-	cacerts = "use-dynamic-cacert"
+	return p.uploadStarterApps(ctx, catalog, event, pluginData)
+}
 
-	ociRegistry := strings.ReplaceAll(p.config.HarborServerExternal, "https://", "oci://")
+// EnsureEvent creates the missing registries and starter applications of the project and updates the others. A
+// registry that needs the Harbor robot credentials can only be recreated with new secrets, so it is reported as a
+// warning if it is missing.
+func (p *CatalogProvisionerPlugin) EnsureEvent(ctx context.Context, event Event, pluginData *PluginData) error {
+	return p.CreateEvent(ctx, event, pluginData)
+}
 
-	harborProjectName := southbound.HarborProjectName(event.Organization, event.Name)
-	OCIHelmRegistryAttrs := southbound.RegistryAttributes{
-		Name:         `harbor-helm-oci`,
-		DisplayName:  `harbor oci helm`,
-		Description:  `Harbor OCI helm charts registry`,
-		Type:         `HELM`,
-		ProjectUUID:  event.UUID,
-		RootURL:      ociRegistry + "/" + harborProjectName,
-		InventoryURL: p.config.HarborServerExternal + `/api/v2.0/projects/` + harborProjectName,
-		Username:     username,
-		Cacerts:      cacerts,
-		AuthToken:    token,
-	}
-	err = catalog.CreateOrUpdateRegistry(ctx, OCIHelmRegistryAttrs)
+func (p *CatalogProvisionerPlugin) DeleteEvent(ctx context.Context, event Event, _ *PluginData) error {
+	catalog, err := CatalogFactory(p.config)
 	if err != nil {
-		log.Errorf("Error creating registry harbor-helm-oci: %v", err)
 		return err
 	}
+	event.ReportProgress("Verifying catalog project ownership")
+	if err := catalog.VerifyProjectOwnership(ctx, event.UUID, p.recordedRegistries(ctx, event)); err != nil {
+		return err
+	}
+	event.ReportProgress("Deleting catalog project contents")
+	return catalog.WipeProject(ctx, event.UUID, p.config.CatalogServer, func(message string) {
+		event.ReportProgress("%s", message)
+	})
+}
 
-	OCIimageRegistryAttrs := southbound.RegistryAttributes{
-		Name:        `harbor-docker-oci`,
-		DisplayName: `harbor oci docker`,
-		Description: `Harbor OCI docker images registry`,
-		Type:        "IMAGE",
-		ProjectUUID: event.UUID,
-		RootURL:     ociRegistry + "/" + strings.ToLower(harborProjectName),
-		Username:    username,
-		Cacerts:     cacerts,
-		AuthToken:   token,
+// DeactivateEvent removes the credentials of the catalog registries of the project that pull from its Harbor project,
+// so that they stop working without being deleted. Missing registries are skipped.
+func (p *CatalogProvisionerPlugin) DeactivateEvent(ctx context.Context, event Event, _ *PluginData) error {
+	catalog, err := CatalogFactory(p.config)
+	if err != nil {
+		return err
 	}
-	err = catalog.CreateOrUpdateRegistry(ctx, OCIimageRegistryAttrs)
+	registries, err := p.harborRegistries(ctx, event, newRegistryTemplateData(p.config, event, HarborRobot{}, HarborRobot{}))
 	if err != nil {
-		log.Errorf("Error creating registry harbor-docker-oci: %v", err)
 		return err
 	}
-
+	for _, attrs := range registries {
+		event.ReportProgress("Disabling catalog registry %s", attrs.Name)
+		err := catalog.ClearRegistryCredentials(ctx, event.UUID, attrs.Name)
+		if errors.Is(err, southbound.ErrNotFound) {
+			log.Infof("Catalog registry %s of project %s is missing, nothing to disable", attrs.Name, event.Name)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (p *CatalogProvisionerPlugin) DeleteEvent(ctx context.Context, event Event, _ PluginData) error {
+// ReactivateEvent gives the catalog registries of the project that pull from its Harbor project the credentials of
+// the robot accounts the Harbor plugin enabled again. Registries whose robot credentials are not known are left
+// disabled, with a warning.
+func (p *CatalogProvisionerPlugin) ReactivateEvent(ctx context.Context, event Event, pluginData *PluginData) error {
 	catalog, err := CatalogFactory(p.config)
 	if err != nil {
 		return err
 	}
-	return catalog.WipeProject(ctx, event.UUID, p.config.CatalogServer)
+	credentials := pluginData.HarborCredentials()
+	pullCredentials := pluginData.HarborPullCredentials()
+	registries, err := p.harborRegistries(ctx, event, newRegistryTemplateData(p.config, event, credentials, pullCredentials))
+	if err != nil {
+		return err
+	}
+	for i, attrs := range registries {
+		if p.registries[attrs.template].usesHarborCredentials() && credentials.Token == "" ||
+			p.registries[attrs.template].usesHarborPullCredentials() && pullCredentials.Token == "" {
+			event.ReportWarning("Catalog registry %s is left disabled, the Harbor robot credentials are not known", attrs.Name)
+			continue
+		}
+		event.ReportProgress("Enabling catalog registries %d/%d", i+1, len(registries))
+		err := catalog.UpdateRegistryCredentials(ctx, event.UUID, attrs.Name, attrs.Username, attrs.AuthToken)
+		if errors.Is(err, southbound.ErrNotFound) {
+			event.ReportWarning("Catalog registry %s is missing, provision the project again to recreate it", attrs.Name)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// harborRegistry is a catalog registry of the project expanded from the registry template at the given index.
+type harborRegistry struct {
+	southbound.RegistryAttributes
+	template int
+}
+
+// harborRegistries expands the registries of the project that use the credentials of a Harbor robot account, with
+// the name affixes of the project.
+func (p *CatalogProvisionerPlugin) harborRegistries(ctx context.Context, event Event, data RegistryTemplateData) ([]harborRegistry, error) {
+	affixes, err := projectRegistryAffixes(ctx, p.config, event.UUID)
+	if err != nil {
+		return nil, err
+	}
+	registries := []harborRegistry{}
+	for i, registry := range p.registries {
+		if !registry.usesHarborCredentials() && !registry.usesHarborPullCredentials() {
+			continue
+		}
+		attrs, err := registry.expand(data)
+		if err != nil {
+			return nil, err
+		}
+		attrs.Name = affixes.name(attrs.Name)
+		registries = append(registries, harborRegistry{RegistryAttributes: attrs, template: i})
+	}
+	return registries, nil
+}
+
+// recordedRegistries returns the catalog registries recorded in the inventory of the project, or nil if the project
+// has no inventory.
+func (p *CatalogProvisionerPlugin) recordedRegistries(ctx context.Context, event Event) []string {
+	if p.config.PodNamespace == "" {
+		return nil
+	}
+	store, err := InventoryStoreFactory(p.config)
+	if err != nil {
+		log.Warnf("Unable to load the inventory of project %s: %v", event.Name, err)
+		return nil
+	}
+	inventory, err := store.Load(ctx, event.UUID)
+	if err != nil || inventory == nil {
+		if err != nil {
+			log.Warnf("Unable to load the inventory of project %s: %v", event.Name, err)
+		}
+		return nil
+	}
+	if inventory.CatalogRegistries == nil {
+		return []string{}
+	}
+	return inventory.CatalogRegistries
+}
+
+// Admit refuses a new project while the maximum number of projects is provisioned in the catalog. The catalog does
+// not list its projects, so they are counted from the project inventories that record catalog registries. Projects
+// with catalog registries in their inventory are admitted.
+func (p *CatalogProvisionerPlugin) Admit(ctx context.Context, event Event) error {
+	limit := int64(p.config.MaxCatalogProjects)
+	if limit <= 0 {
+		return nil
+	}
+	store, err := InventoryStoreFactory(p.config)
+	if err != nil {
+		return err
+	}
+	inventories, err := store.List(ctx)
+	if err != nil {
+		return err
+	}
+	var used int64
+	for _, inventory := range inventories {
+		if len(inventory.CatalogRegistries) == 0 {
+			continue
+		}
+		if inventory.UUID == event.UUID {
+			return nil
+		}
+		used++
+	}
+	if !atCapacity(used, limit) {
+		return nil
+	}
+	return capacityExceeded(CapacityCatalogProjects, "MAX_CATALOG_PROJECTS", used, limit)
 }
 
 func (p *CatalogProvisionerPlugin) Name() string {
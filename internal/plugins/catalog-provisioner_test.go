@@ -7,16 +7,20 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/golden"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/retry/retrytest"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
 )
 
 type InitPlugin struct{}
 
-func (p *InitPlugin) CreateEvent(_ context.Context, _ Event, pluginData PluginData) error {
-	(*pluginData)[HarborTokenName] = "token"
-	(*pluginData)[HarborUsernameName] = "user"
+func (p *InitPlugin) CreateEvent(_ context.Context, _ Event, pluginData *PluginData) error {
+	pluginData.SetHarborCredentials(HarborRobot{Username: "user", Token: "token"})
+	pluginData.SetHarborPullCredentials(HarborRobot{Username: "pull-user", Token: "pull-token"})
 	return nil
 }
 
@@ -24,11 +28,11 @@ func (p *InitPlugin) Name() string {
 	return "init"
 }
 
-func (p *InitPlugin) Initialize(_ context.Context, _ PluginData) error {
+func (p *InitPlugin) Initialize(_ context.Context, _ *PluginData) error {
 	return nil
 }
 
-func (p *InitPlugin) DeleteEvent(_ context.Context, _ Event, _ PluginData) error { return nil }
+func (p *InitPlugin) DeleteEvent(_ context.Context, _ Event, _ *PluginData) error { return nil }
 
 func (s *PluginsTestSuite) TestCatalogProvisionerPluginCreate() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
@@ -48,7 +52,7 @@ func (s *PluginsTestSuite) TestCatalogProvisionerPluginCreate() {
 	err = Initialize(ctx)
 	s.NoError(err, "Cannot initialize plugins")
 
-	err = Dispatch(ctx, Event{
+	_, err = Dispatch(ctx, Event{
 		EventType:    "create",
 		UUID:         "default",
 		Organization: "test-org",
@@ -62,9 +66,9 @@ func (s *PluginsTestSuite) TestCatalogProvisionerPluginCreate() {
 	s.Equal(`Repo on registry release-service-root.root.io`, mockCatalog.registries["intel-rs-images"].Description)
 	s.Equal("harbor-helm-oci", mockCatalog.registries["harbor-helm-oci"].Name)
 	s.Equal("harbor-docker-oci", mockCatalog.registries["harbor-docker-oci"].Name)
-	s.Equal("token", mockCatalog.registries["harbor-docker-oci"].AuthToken)
+	s.Equal("pull-token", mockCatalog.registries["harbor-docker-oci"].AuthToken)
 	s.Equal("token", mockCatalog.registries["harbor-helm-oci"].AuthToken)
-	s.Equal("user", mockCatalog.registries["harbor-docker-oci"].Username)
+	s.Equal("pull-user", mockCatalog.registries["harbor-docker-oci"].Username)
 	s.Equal("user", mockCatalog.registries["harbor-helm-oci"].Username)
 	s.Equal("/catalog-apps-test-org-", mockCatalog.registries["harbor-docker-oci"].RootURL)
 	s.Equal("/catalog-apps-test-org-", mockCatalog.registries["harbor-helm-oci"].RootURL)
@@ -76,7 +80,492 @@ func (s *PluginsTestSuite) TestCatalogProvisionerPluginCreate() {
 	}
 }
 
+func (s *PluginsTestSuite) TestCatalogProvisionerPluginReleaseServiceRegistries() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	CatalogFactory = newTestCatalog
+	_, _ = newTestCatalog(config.Configuration{})
+	mockCatalog.registries = map[string]southbound.RegistryAttributes{}
+
+	plugin, err := NewCatalogProvisionerPlugin(config.Configuration{
+		ReleaseServiceRootURL:         "oci://registry-rs.example.com",
+		ReleaseServiceProxyRootURL:    "oci://rs-proxy.rs-proxy.svc.cluster.local:8443",
+		ReleaseServiceHelmRootURL:     "oci://charts.example.com",
+		ReleaseServiceImagePathPrefix: "/edge-orch/images/",
+	})
+	s.NoError(err)
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+	Register(&InitPlugin{})
+	Register(plugin)
+
+	_, err = Dispatch(ctx, Event{
+		EventType:    "create",
+		UUID:         "default",
+		Organization: "test-org",
+	}, nil)
+	s.NoError(err)
+	s.Equal("oci://charts.example.com", mockCatalog.registries["intel-rs-helm"].RootURL)
+	s.Equal("oci://registry-rs.example.com/edge-orch/images", mockCatalog.registries["intel-rs-images"].RootURL)
+	s.Equal("Repo on registry registry-rs.example.com", mockCatalog.registries["intel-rs-images"].Description)
+}
+
+func (s *PluginsTestSuite) TestCatalogProvisionerPluginQuota() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	CatalogFactory = newTestCatalog
+	_, _ = newTestCatalog(config.Configuration{})
+	mockCatalog.registries = map[string]southbound.RegistryAttributes{}
+
+	plugin, err := NewCatalogProvisionerPlugin(config.Configuration{MaxCatalogRegistries: 3})
+	s.NoError(err)
+	RemoveAllPlugins()
+	Register(&InitPlugin{})
+	Register(plugin)
+
+	_, err = Dispatch(ctx, Event{
+		EventType:    "create",
+		UUID:         "default",
+		Organization: "test-org",
+	}, nil)
+	s.ErrorIs(err, ErrQuotaExceeded)
+	s.ErrorIs(err, southbound.ErrPermanent)
+	s.ErrorContains(err, "4 catalog registries would be created, the maximum is 3 (MAX_CATALOG_REGISTRIES)")
+	s.Empty(mockCatalog.registries)
+
+	plugin.config.MaxCatalogRegistries = 4
+	_, err = Dispatch(ctx, Event{
+		EventType:    "create",
+		UUID:         "default",
+		Organization: "test-org",
+	}, nil)
+	s.NoError(err)
+	s.Len(mockCatalog.registries, 4)
+	RemoveAllPlugins()
+}
+
+func (s *PluginsTestSuite) TestCatalogProvisionerPluginVerifyRegistries() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	CatalogFactory = newTestCatalog
+	_, _ = newTestCatalog(config.Configuration{})
+	mockCatalog.registries = map[string]southbound.RegistryAttributes{}
+	mockCatalog.registryWrites = 0
+	defer func() { mockCatalog.rewriteRegistry = nil }()
+
+	plugin, err := NewCatalogProvisionerPlugin(config.Configuration{})
+	s.NoError(err)
+	var warnings []string
+	event := Event{EventType: "create", UUID: "default", Organization: "test-org",
+		warn: func(message string) { warnings = append(warnings, message) }}
+	pluginData := NewPluginData()
+	pluginData.SetHarborCredentials(HarborRobot{Username: "user", Token: "token"})
+	pluginData.SetHarborPullCredentials(HarborRobot{Username: "pull-user", Token: "pull-token"})
+
+	// Registries that read back as written are written once
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	s.Equal(4, mockCatalog.registryWrites)
+	s.Empty(warnings)
+
+	// A registry the catalog changed once is written again
+	mockCatalog.registryWrites = 0
+	defaulted := false
+	mockCatalog.rewriteRegistry = func(attrs southbound.RegistryAttributes) southbound.RegistryAttributes {
+		if attrs.Name == "harbor-helm-oci" && !defaulted {
+			defaulted = true
+			attrs.Type = "IMAGE"
+		}
+		return attrs
+	}
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	s.Equal(5, mockCatalog.registryWrites)
+	s.Equal("HELM", mockCatalog.registries["harbor-helm-oci"].Type)
+	s.Empty(warnings)
+
+	// A registry that keeps coming back different is reported
+	mockCatalog.rewriteRegistry = func(attrs southbound.RegistryAttributes) southbound.RegistryAttributes {
+		if attrs.Name == "harbor-docker-oci" {
+			attrs.AuthToken = ""
+		}
+		return attrs
+	}
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	s.Equal([]string{"Catalog registry harbor-docker-oci does not match its definition: authToken is not set"}, warnings)
+}
+
+func (s *PluginsTestSuite) TestCatalogProvisionerPluginRegistryTemplate() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	CatalogFactory = newTestCatalog
+	_, _ = newTestCatalog(config.Configuration{})
+	mockCatalog.registries = map[string]southbound.RegistryAttributes{}
+
+	templateFile := filepath.Join(s.T().TempDir(), "registries.yaml")
+	err := os.WriteFile(templateFile, []byte(`
+registries:
+  - name: customer-mirror
+    displayName: '{{ .Organization }} mirror'
+    description: Customer OCI mirror
+    type: IMAGE
+    rootURL: 'oci://mirror.example.com/{{ lower .Project }}'
+    username: '{{ .HarborUsername }}'
+    authToken: '{{ .HarborToken }}'
+`), 0600)
+	s.NoError(err)
+
+	RemoveAllPlugins()
+	Register(&InitPlugin{})
+	plugin, err := NewCatalogProvisionerPlugin(config.Configuration{RegistryTemplatePath: templateFile})
+	s.NoError(err, "Cannot create catalog provisioner plugin")
+	Register(plugin)
+
+	_, err = Dispatch(ctx, Event{
+		EventType:    "create",
+		UUID:         "default",
+		Organization: "test-org",
+		Name:         "Proj",
+	}, nil)
+	s.NoError(err, "Cannot dispatch create event")
+
+	s.Len(mockCatalog.registries, 1)
+	mirror := mockCatalog.registries["customer-mirror"]
+	s.Equal("test-org mirror", mirror.DisplayName)
+	s.Equal("oci://mirror.example.com/proj", mirror.RootURL)
+	s.Equal("user", mirror.Username)
+	s.Equal("token", mirror.AuthToken)
+	s.Equal("default", mirror.ProjectUUID)
+
+	// templates are validated when the plugin is created
+	err = os.WriteFile(templateFile, []byte(`
+registries:
+  - name: broken
+    rootURL: '{{ .NoSuchField }}'
+`), 0600)
+	s.NoError(err)
+	_, err = NewCatalogProvisionerPlugin(config.Configuration{RegistryTemplatePath: templateFile})
+	s.Error(err)
+	s.Contains(err.Error(), "invalid registry template for broken")
+
+	_, err = NewCatalogProvisionerPlugin(config.Configuration{RegistryTemplatePath: filepath.Join(s.T().TempDir(), "missing.yaml")})
+	s.Error(err)
+
+	// registries have complete credentials or are explicitly anonymous
+	for _, invalid := range []string{
+		"registries:\n  - name: mirror\n    rootURL: oci://mirror\n",
+		"registries:\n  - name: mirror\n    username: '{{ .HarborUsername }}'\n",
+		"registries:\n  - name: mirror\n    anonymous: true\n    authToken: '{{ .HarborToken }}'\n",
+	} {
+		s.NoError(os.WriteFile(templateFile, []byte(invalid), 0600))
+		_, err = NewCatalogProvisionerPlugin(config.Configuration{RegistryTemplatePath: templateFile})
+		s.ErrorContains(err, "invalid registry template for mirror", invalid)
+	}
+	s.NoError(os.WriteFile(templateFile, []byte("registries:\n  - name: mirror\n    rootURL: oci://mirror\n    anonymous: true\n"), 0600))
+	_, err = NewCatalogProvisionerPlugin(config.Configuration{RegistryTemplatePath: templateFile})
+	s.NoError(err)
+}
+
+func (s *PluginsTestSuite) TestCatalogProvisionerPluginAnonymousRegistries() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	CatalogFactory = newTestCatalog
+	_, _ = newTestCatalog(config.Configuration{})
+	mockCatalog.registries = map[string]southbound.RegistryAttributes{}
+
+	plugin, err := NewCatalogProvisionerPlugin(config.Configuration{})
+	s.NoError(err)
+	var warnings []string
+	pluginData := NewPluginData()
+	event := Event{EventType: "create", UUID: "default", Organization: "test-org",
+		warn: func(message string) { warnings = append(warnings, message) }}
+
+	// Without a Harbor robot account, only the anonymous release service registries are created
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	s.Len(mockCatalog.registries, 2)
+	s.True(mockCatalog.registries["intel-rs-helm"].Anonymous)
+	s.True(mockCatalog.registries["intel-rs-images"].Anonymous)
+	s.Equal([]string{
+		"Skipping catalog registry harbor-helm-oci, the Harbor robot credentials are not known",
+		"Skipping catalog registry harbor-docker-oci, the Harbor robot credentials are not known",
+	}, warnings)
+	s.Equal([]string{"intel-rs-helm", "intel-rs-images"}, pendingInventory(pluginData).CatalogRegistries)
+}
+
+func (s *PluginsTestSuite) TestCatalogProvisionerPluginStarterApps() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	CatalogFactory = newTestCatalog
+	_, _ = newTestCatalog(config.Configuration{})
+	mockCatalog.uploadedFiles = map[string]upload{}
+	defer func() { mockCatalog.uploadedFiles = map[string]upload{} }()
+
+	starterAppsFile := filepath.Join(s.T().TempDir(), "starter-apps.yaml")
+	err := os.WriteFile(starterAppsFile, []byte(`
+- name: nginx-app.yaml
+  content: |
+    specSchema: Application
+    schemaVersion: "0.1"
+    $schema: "https://schema.intel.com/catalog.orchestrator/0.1/schema"
+    name: nginx
+    version: 0.1.0
+- name: nginx-dp.yaml
+  content: |
+    specSchema: DeploymentPackage
+    schemaVersion: "0.1"
+    $schema: "https://schema.intel.com/catalog.orchestrator/0.1/schema"
+    name: nginx
+    version: 0.1.0
+`), 0600)
+	s.NoError(err)
+
+	plugin, err := NewCatalogProvisionerPlugin(config.Configuration{StarterAppsPath: starterAppsFile})
+	s.NoError(err, "Cannot create catalog provisioner plugin")
+	var progress []string
+	pluginData := newHarborPluginData(HarborRobot{Username: "user", Token: "token"}, HarborRobot{Username: "pull-user", Token: "pull-token"})
+	err = plugin.CreateEvent(ctx, Event{
+		EventType:    "create",
+		UUID:         "default",
+		Organization: "test-org",
+		progress:     func(message string) { progress = append(progress, message) },
+	}, pluginData)
+	s.NoError(err, "Cannot dispatch create event")
+
+	s.Len(mockCatalog.uploadedFiles, 2)
+	s.Contains(mockCatalog.uploadedFiles["nginx-app.yaml"].artifact, "specSchema: Application")
+	s.False(mockCatalog.uploadedFiles["nginx-app.yaml"].lastUpload)
+	s.Contains(mockCatalog.uploadedFiles["nginx-dp.yaml"].artifact, "specSchema: DeploymentPackage")
+	s.True(mockCatalog.uploadedFiles["nginx-dp.yaml"].lastUpload)
+	s.Equal([]string{"Uploading starter applications 1/2", "Uploading starter applications 2/2"}, progress[len(progress)-2:])
+
+	// starter applications are validated when the plugin is created
+	for _, invalid := range []string{
+		"- name: app.yaml\n",
+		"- name: app.yaml\n  content: 'a: b'\n- name: app.yaml\n  content: 'a: b'\n",
+		"- name: app.yaml\n  content: 'a: [b'\n",
+		"- name: app.yaml\n  contents: 'a: b'\n",
+	} {
+		s.NoError(os.WriteFile(starterAppsFile, []byte(invalid), 0600))
+		_, err = NewCatalogProvisionerPlugin(config.Configuration{StarterAppsPath: starterAppsFile})
+		s.Error(err, invalid)
+	}
+}
+
+func (s *PluginsTestSuite) TestCatalogProvisionerPluginCredentialsUnchanged() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	CatalogFactory = newTestCatalog
+	_, _ = newTestCatalog(config.Configuration{})
+	mockCatalog.registries = map[string]southbound.RegistryAttributes{}
+
+	plugin, err := NewCatalogProvisionerPlugin(config.Configuration{})
+	s.NoError(err)
+	event := Event{
+		EventType:    "create",
+		UUID:         "default",
+		Organization: "test-org",
+	}
+
+	// A credentialed registry that does not exist cannot be created without the robot secret
+	pluginData := newHarborPluginData(HarborRobot{Username: "user", Kept: true}, HarborRobot{})
+	err = plugin.CreateEvent(ctx, event, pluginData)
+	s.Error(err)
+	s.Contains(err.Error(), "registry harbor-helm-oci is missing")
+
+	pluginData = newHarborPluginData(HarborRobot{Username: "user", Token: "token"}, HarborRobot{Username: "pull-user", Token: "pull-token"})
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	s.Len(mockCatalog.registries, 4)
+	s.Equal("user", mockCatalog.registries["harbor-helm-oci"].Username)
+	s.Equal("pull-user", mockCatalog.registries["harbor-docker-oci"].Username)
+
+	// Registries using the Harbor credentials are kept as they are
+	pluginData = newHarborPluginData(HarborRobot{Username: "user", Kept: true}, HarborRobot{Username: "pull-user", Kept: true})
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	s.Len(mockCatalog.registries, 4)
+	s.Equal("token", mockCatalog.registries["harbor-helm-oci"].AuthToken)
+	s.Equal("pull-token", mockCatalog.registries["harbor-docker-oci"].AuthToken)
+
+	// Only the registries of the robot whose secret changed are updated
+	pluginData = newHarborPluginData(HarborRobot{Username: "user", Kept: true}, HarborRobot{Username: "pull-user", Token: "new-pull-token"})
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	s.Equal("token", mockCatalog.registries["harbor-helm-oci"].AuthToken)
+	s.Equal("new-pull-token", mockCatalog.registries["harbor-docker-oci"].AuthToken)
+}
+
+func (s *PluginsTestSuite) TestCatalogProvisionerPluginDeactivate() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	CatalogFactory = newTestCatalog
+	_, _ = newTestCatalog(config.Configuration{})
+	mockCatalog.registries = map[string]southbound.RegistryAttributes{}
+
+	plugin, err := NewCatalogProvisionerPlugin(config.Configuration{})
+	s.NoError(err)
+	event := Event{
+		EventType:    "create",
+		UUID:         "default",
+		Organization: "test-org",
+	}
+	pluginData := newHarborPluginData(HarborRobot{Username: "user", Token: "token"}, HarborRobot{Username: "pull-user", Token: "pull-token"})
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	s.Len(mockCatalog.registries, 4)
+	releaseService := mockCatalog.registries["intel-rs-helm"]
+
+	// The registries pulling from Harbor lose their credentials but are kept, the others are left alone
+	event.EventType = "deactivate"
+	s.NoError(plugin.DeactivateEvent(ctx, event, NewPluginData()))
+	s.Len(mockCatalog.registries, 4)
+	for _, name := range []string{"harbor-helm-oci", "harbor-docker-oci"} {
+		s.Empty(mockCatalog.registries[name].Username, name)
+		s.Empty(mockCatalog.registries[name].AuthToken, name)
+		s.NotEmpty(mockCatalog.registries[name].RootURL, name)
+	}
+	s.Equal(releaseService, mockCatalog.registries["intel-rs-helm"])
+
+	// Without robot credentials the registries are left disabled
+	event.EventType = "reactivate"
+	var warnings []string
+	event.warn = func(message string) { warnings = append(warnings, message) }
+	s.NoError(plugin.ReactivateEvent(ctx, event, NewPluginData()))
+	s.Len(warnings, 2)
+	s.Empty(mockCatalog.registries["harbor-helm-oci"].AuthToken)
+
+	// They are given the credentials of the robots enabled again
+	warnings = nil
+	pluginData = newHarborPluginData(HarborRobot{Username: "user", Token: "new-token"}, HarborRobot{Username: "pull-user", Token: "new-pull-token"})
+	s.NoError(plugin.ReactivateEvent(ctx, event, pluginData))
+	s.Empty(warnings)
+	s.Equal("user", mockCatalog.registries["harbor-helm-oci"].Username)
+	s.Equal("new-token", mockCatalog.registries["harbor-helm-oci"].AuthToken)
+	s.Equal("pull-user", mockCatalog.registries["harbor-docker-oci"].Username)
+	s.Equal("new-pull-token", mockCatalog.registries["harbor-docker-oci"].AuthToken)
+
+	// Missing registries are skipped
+	delete(mockCatalog.registries, "harbor-docker-oci")
+	s.NoError(plugin.DeactivateEvent(ctx, event, NewPluginData()))
+	s.NoError(plugin.ReactivateEvent(ctx, event, pluginData))
+	s.Len(warnings, 1)
+	s.Contains(warnings[0], "harbor-docker-oci is missing")
+}
+
+func (s *PluginsTestSuite) TestCatalogProvisionerPluginRegistryAffixes() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	store := &testInventoryStore{inventories: map[string]*southbound.Inventory{}}
+	InventoryStoreFactory = func(_ config.Configuration) (InventoryStore, error) { return store, nil }
+	defer func() { InventoryStoreFactory = NewInventoryStore }()
+	CatalogFactory = newTestCatalog
+	_, _ = newTestCatalog(config.Configuration{})
+	mockCatalog.registries = map[string]southbound.RegistryAttributes{}
+
+	configuration := config.Configuration{PodNamespace: "orch-app", CatalogRegistryPrefix: "staging-", CatalogRegistrySuffix: "-eu1"}
+	plugin, err := NewCatalogProvisionerPlugin(configuration)
+	s.NoError(err)
+	event := Event{EventType: "create", Name: "proj", Organization: "org", UUID: "uuid-1"}
+
+	// New projects get the configured affixes, which are recorded with the registries
+	pluginData := newHarborPluginData(HarborRobot{Username: "user", Token: "token"}, HarborRobot{Username: "pull-user", Token: "pull-token"})
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	s.Contains(mockCatalog.registries, "staging-intel-rs-helm-eu1")
+	s.Contains(mockCatalog.registries, "staging-harbor-docker-oci-eu1")
+	s.Equal("staging-harbor-docker-oci-eu1", mockCatalog.registries["staging-harbor-docker-oci-eu1"].Name)
+	recorded := pendingInventory(pluginData)
+	s.Contains(recorded.CatalogRegistries, "staging-harbor-helm-oci-eu1")
+	s.Equal("staging-", recorded.CatalogRegistryPrefix)
+	s.Equal("-eu1", recorded.CatalogRegistrySuffix)
+
+	// Projects provisioned before the affixes changed keep the names recorded in their inventory
+	recorded.UUID = "uuid-1"
+	store.inventories["uuid-1"] = recorded
+	configuration.CatalogRegistryPrefix = "prod-"
+	configuration.CatalogRegistrySuffix = ""
+	plugin, err = NewCatalogProvisionerPlugin(configuration)
+	s.NoError(err)
+	mockCatalog.registries = map[string]southbound.RegistryAttributes{}
+	s.NoError(plugin.CreateEvent(ctx, event, newHarborPluginData(HarborRobot{Username: "user", Token: "token"}, HarborRobot{Username: "pull-user", Token: "pull-token"})))
+	s.Contains(mockCatalog.registries, "staging-harbor-helm-oci-eu1")
+	s.NotContains(mockCatalog.registries, "prod-harbor-helm-oci")
+
+	// Other projects get the new affixes
+	event.UUID = "uuid-2"
+	s.NoError(plugin.CreateEvent(ctx, event, newHarborPluginData(HarborRobot{Username: "user", Token: "token"}, HarborRobot{Username: "pull-user", Token: "pull-token"})))
+	s.Contains(mockCatalog.registries, "prod-harbor-helm-oci")
+}
+
+func (s *PluginsTestSuite) TestCatalogProvisionerPluginAdmit() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	store := &testInventoryStore{inventories: map[string]*southbound.Inventory{
+		"uuid-1": {UUID: "uuid-1", CatalogRegistries: []string{"harbor-helm-oci"}},
+		"uuid-2": {UUID: "uuid-2", CatalogRegistries: []string{"harbor-helm-oci"}},
+		// projects without catalog registries are not counted
+		"uuid-3": {UUID: "uuid-3"},
+	}}
+	InventoryStoreFactory = func(_ config.Configuration) (InventoryStore, error) { return store, nil }
+	defer func() { InventoryStoreFactory = NewInventoryStore }()
+	CatalogFactory = newTestCatalog
+
+	plugin, err := NewCatalogProvisionerPlugin(config.Configuration{PodNamespace: "orch-app", MaxCatalogProjects: 3})
+	s.NoError(err)
+	s.NoError(plugin.Admit(ctx, Event{EventType: "create", Name: "proj", Organization: "org", UUID: "uuid-4"}))
+
+	plugin, err = NewCatalogProvisionerPlugin(config.Configuration{PodNamespace: "orch-app", MaxCatalogProjects: 2})
+	s.NoError(err)
+	err = plugin.Admit(ctx, Event{EventType: "create", Name: "proj", Organization: "org", UUID: "uuid-4"})
+	s.ErrorIs(err, ErrCapacityExceeded)
+	s.ErrorContains(err, "2 projects are provisioned in the catalog, the maximum is 2 (MAX_CATALOG_PROJECTS)")
+
+	// Projects provisioned already are admitted, so that they can be upgraded
+	s.NoError(plugin.Admit(ctx, Event{EventType: "create", Name: "proj", Organization: "org", UUID: "uuid-2"}))
+	// A project without catalog registries is new to the catalog
+	s.ErrorIs(plugin.Admit(ctx, Event{EventType: "create", Name: "proj", Organization: "org", UUID: "uuid-3"}), ErrCapacityExceeded)
+}
+
 // TestCatalogWaitForCatalogSucceeds tests that waitForCatalog succeeds when catalog is available
+func (s *PluginsTestSuite) TestCatalogProvisionerPluginVerifiesOwnership() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	store := &testInventoryStore{inventories: map[string]*southbound.Inventory{
+		"uuid-1": {UUID: "uuid-1", CatalogRegistries: []string{"harbor-helm-oci", "harbor-docker-oci"}},
+	}}
+	InventoryStoreFactory = func(_ config.Configuration) (InventoryStore, error) { return store, nil }
+	defer func() { InventoryStoreFactory = NewInventoryStore }()
+	CatalogFactory = newTestCatalog
+	_, _ = newTestCatalog(config.Configuration{})
+	defer func() { mockCatalog.verifyErr = nil }()
+
+	plugin, err := NewCatalogProvisionerPlugin(config.Configuration{PodNamespace: "orch-app"})
+	s.NoError(err)
+	event := Event{EventType: "delete", Name: "Proj", Organization: "Org", UUID: "uuid-1"}
+
+	// The recorded registries are verified before the project is wiped
+	mockCatalog.wiped = false
+	s.NoError(plugin.DeleteEvent(ctx, event, NewPluginData()))
+	s.Equal([]string{"harbor-helm-oci", "harbor-docker-oci"}, mockCatalog.verified)
+	s.True(mockCatalog.wiped)
+
+	// Projects without an inventory are verified by ownership marker only
+	event.UUID = "uuid-2"
+	s.NoError(plugin.DeleteEvent(ctx, event, NewPluginData()))
+	s.Nil(mockCatalog.verified)
+
+	// Nothing is wiped if the verification fails
+	mockCatalog.wiped = false
+	mockCatalog.verifyErr = fmt.Errorf("%w: registry owned by another project", southbound.ErrPermanent)
+	err = plugin.DeleteEvent(ctx, event, NewPluginData())
+	s.ErrorIs(err, southbound.ErrPermanent)
+	s.False(mockCatalog.wiped)
+}
+
 func (s *PluginsTestSuite) TestCatalogWaitForCatalogSucceeds() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
 	defer cancel()
@@ -96,19 +585,18 @@ func (s *PluginsTestSuite) TestCatalogWaitForCatalogSucceeds() {
 
 // TestCatalogWaitForCatalogFailsAfterRetries tests that waitForCatalog fails after max retries
 func (s *PluginsTestSuite) TestCatalogWaitForCatalogFailsAfterRetries() {
-	// Skip this test unless explicitly requested as it takes time
-	if os.Getenv("RUN_LONG_TESTS") != "1" {
-		s.T().Skip("Skipping long-running test. To run: RUN_LONG_TESTS=1 go test -v -run TestPlugins/TestCatalogWaitForCatalogFailsAfterRetries -timeout 2m")
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*2)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
+	ctx, clock := retrytest.Context(ctx)
 
 	failingAttempts := 0
 	CatalogFactory = func(_ config.Configuration) (Catalog, error) {
-		failingAttempts++
-		return nil, fmt.Errorf("catalog factory failed (attempt %d)", failingAttempts)
+		return &mockDynamicCatalog{
+			listRegistriesFunc: func(_ context.Context) error {
+				failingAttempts++
+				return fmt.Errorf("catalog not ready (attempt %d)", failingAttempts)
+			},
+		}, nil
 	}
 
 	plugin := &CatalogProvisionerPlugin{
@@ -117,20 +605,19 @@ func (s *PluginsTestSuite) TestCatalogWaitForCatalogFailsAfterRetries() {
 		},
 	}
 
-	startTime := time.Now()
 	err := plugin.waitForCatalog(ctx)
-	duration := time.Since(startTime)
 
 	s.Error(err, "waitForCatalog should fail when catalog is not available")
 	s.Contains(err.Error(), "catalog not available after", "Error should indicate max retries exceeded")
-	s.Greater(failingAttempts, 1, "Should attempt multiple times")
-	s.Less(duration, time.Minute*2, "Should not exceed context timeout")
+	s.Equal(serviceBackoff.Attempts, failingAttempts, "Should attempt multiple times")
+	s.Len(clock.Waits(), serviceBackoff.Attempts-1, "Should wait between the attempts")
 }
 
 // TestCatalogWaitForCatalogRecoversAfterRetries tests that waitForCatalog succeeds after some failures
 func (s *PluginsTestSuite) TestCatalogWaitForCatalogRecoversAfterRetries() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
+	ctx, clock := retrytest.Context(ctx)
 
 	attempts := 0
 	// Create a mock catalog that fails initially then succeeds
@@ -155,14 +642,43 @@ func (s *PluginsTestSuite) TestCatalogWaitForCatalogRecoversAfterRetries() {
 		},
 	}
 
-	startTime := time.Now()
 	err := plugin.waitForCatalog(ctx)
-	duration := time.Since(startTime)
 
 	s.NoError(err, "waitForCatalog should succeed after recovering")
 	s.GreaterOrEqual(attempts, 3, "Should attempt at least 3 times before succeeding")
-	s.Greater(duration, time.Second*10, "Should take time due to retries with backoff")
-	s.T().Logf("Recovered after %d attempts in %v", attempts, duration)
+	s.Greater(clock.Waited(), time.Second*10, "Should take time due to retries with backoff")
+}
+
+func (s *PluginsTestSuite) TestRefreshSecrets() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	initializations := 0
+	var initializeErr error
+	CatalogFactory = func(_ config.Configuration) (Catalog, error) {
+		return &mockDynamicCatalog{
+			initializeClientSecretFunc: func(_ context.Context) (string, error) {
+				initializations++
+				return "", initializeErr
+			},
+		}, nil
+	}
+	defer func() { CatalogFactory = newTestCatalog }()
+
+	plugin, err := NewCatalogProvisionerPlugin(config.Configuration{})
+	s.NoError(err)
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+	Register(&InitPlugin{})
+	Register(plugin)
+
+	// Only the plugins using the service account secret are refreshed
+	s.NoError(RefreshSecrets(ctx))
+	s.Equal(1, initializations)
+
+	initializeErr = fmt.Errorf("keycloak rejected the password: %w", southbound.ErrPermanent)
+	s.ErrorIs(RefreshSecrets(ctx), southbound.ErrPermanent)
+	s.Equal(2, initializations)
 }
 
 // TestCatalogWaitForVaultSucceeds tests that waitForVault succeeds when vault is available
@@ -185,20 +701,19 @@ func (s *PluginsTestSuite) TestCatalogWaitForVaultSucceeds() {
 
 // TestCatalogWaitForVaultFailsAfterRetries tests that waitForVault fails after max retries
 func (s *PluginsTestSuite) TestCatalogWaitForVaultFailsAfterRetries() {
-	// Skip this test unless explicitly requested as it takes time
-	if os.Getenv("RUN_LONG_TESTS") != "1" {
-		s.T().Skip("Skipping long-running test. To run: RUN_LONG_TESTS=1 go test -v -run TestPlugins/TestCatalogWaitForVaultFailsAfterRetries -timeout 2m")
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*2)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
+	ctx, clock := retrytest.Context(ctx)
 
-	// Use factory that returns error
+	// Use a client whose vault login always fails
 	failingAttempts := 0
 	CatalogFactory = func(_ config.Configuration) (Catalog, error) {
-		failingAttempts++
-		return nil, fmt.Errorf("vault connection failed (attempt %d)", failingAttempts)
+		return &mockDynamicCatalog{
+			initializeClientSecretFunc: func(_ context.Context) (string, error) {
+				failingAttempts++
+				return "", fmt.Errorf("vault connection failed (attempt %d)", failingAttempts)
+			},
+		}, nil
 	}
 
 	plugin := &CatalogProvisionerPlugin{
@@ -207,20 +722,19 @@ func (s *PluginsTestSuite) TestCatalogWaitForVaultFailsAfterRetries() {
 		},
 	}
 
-	startTime := time.Now()
 	err := plugin.waitForVault(ctx)
-	duration := time.Since(startTime)
 
 	s.Error(err, "waitForVault should fail when vault is not available")
 	s.Contains(err.Error(), "vault not available after", "Error should indicate max retries exceeded")
-	s.Greater(failingAttempts, 1, "Should attempt multiple times")
-	s.Less(duration, time.Minute*2, "Should not exceed context timeout")
+	s.Equal(serviceBackoff.Attempts, failingAttempts, "Should attempt multiple times")
+	s.Len(clock.Waits(), serviceBackoff.Attempts-1, "Should wait between the attempts")
 }
 
 // TestCatalogWaitForVaultRecoversAfterRetries tests that waitForVault succeeds after some failures
 func (s *PluginsTestSuite) TestCatalogWaitForVaultRecoversAfterRetries() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
+	ctx, clock := retrytest.Context(ctx)
 
 	attempts := 0
 	// Create a mock catalog that fails initially then succeeds
@@ -245,30 +759,26 @@ func (s *PluginsTestSuite) TestCatalogWaitForVaultRecoversAfterRetries() {
 		},
 	}
 
-	startTime := time.Now()
 	err := plugin.waitForVault(ctx)
-	duration := time.Since(startTime)
 
 	s.NoError(err, "waitForVault should succeed after recovering")
 	s.GreaterOrEqual(attempts, 4, "Should attempt at least 4 times before succeeding")
-	s.Greater(duration, time.Second*15, "Should take time due to retries with backoff")
-	s.T().Logf("Vault recovered after %d attempts in %v", attempts, duration)
+	s.Greater(clock.Waited(), time.Second*15, "Should take time due to retries with backoff")
 }
 
 // TestCatalogInitializeFailsWhenVaultFails tests that Initialize propagates vault errors
 func (s *PluginsTestSuite) TestCatalogInitializeFailsWhenVaultFails() {
-	// Skip this test unless explicitly requested as it takes time
-	if os.Getenv("RUN_LONG_TESTS") != "1" {
-		s.T().Skip("Skipping long-running test. To run: RUN_LONG_TESTS=1 go test -v -run TestPlugins/TestCatalogInitializeFailsWhenVaultFails -timeout 2m")
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*2)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
+	ctx, _ = retrytest.Context(ctx)
 
-	// Use factory that always fails
+	// Use a client whose vault login always fails
 	CatalogFactory = func(_ config.Configuration) (Catalog, error) {
-		return nil, fmt.Errorf("vault connection permanently failed")
+		return &mockDynamicCatalog{
+			initializeClientSecretFunc: func(_ context.Context) (string, error) {
+				return "", fmt.Errorf("vault connection failed")
+			},
+		}, nil
 	}
 
 	plugin := &CatalogProvisionerPlugin{
@@ -285,26 +795,19 @@ func (s *PluginsTestSuite) TestCatalogInitializeFailsWhenVaultFails() {
 
 // TestCatalogInitializeFailsWhenCatalogFails tests that Initialize propagates catalog errors
 func (s *PluginsTestSuite) TestCatalogInitializeFailsWhenCatalogFails() {
-	// Skip this test unless explicitly requested as it takes time
-	if os.Getenv("RUN_LONG_TESTS") != "1" {
-		s.T().Skip("Skipping long-running test. To run: RUN_LONG_TESTS=1 go test -v -run TestPlugins/TestCatalogInitializeFailsWhenCatalogFails -timeout 2m")
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*2)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
+	ctx, _ = retrytest.Context(ctx)
 
-	vaultCallCount := 0
+	// The vault login succeeds, the catalog never answers
 	catalogCallCount := 0
 	CatalogFactory = func(_ config.Configuration) (Catalog, error) {
-		// First call is for vault - succeed
-		if vaultCallCount == 0 {
-			vaultCallCount++
-			return newTestCatalog(config.Configuration{})
-		}
-		// Subsequent calls are for catalog - fail
-		catalogCallCount++
-		return nil, fmt.Errorf("catalog connection failed (attempt %d)", catalogCallCount)
+		return &mockDynamicCatalog{
+			listRegistriesFunc: func(_ context.Context) error {
+				catalogCallCount++
+				return fmt.Errorf("catalog connection failed (attempt %d)", catalogCallCount)
+			},
+		}, nil
 	}
 
 	plugin := &CatalogProvisionerPlugin{
@@ -318,3 +821,168 @@ func (s *PluginsTestSuite) TestCatalogInitializeFailsWhenCatalogFails() {
 	s.Error(err, "Initialize should fail when catalog fails")
 	s.Contains(err.Error(), "catalog initialization failed during catalog check", "Error should indicate catalog check failure")
 }
+
+// TestWaitForKeycloakSkipsWhenNotConfigured tests that the Keycloak check is skipped without a Keycloak service base
+func (s *PluginsTestSuite) TestWaitForKeycloakSkipsWhenNotConfigured() {
+	defer func(check func(context.Context, config.Configuration) error) { KeycloakHealthCheck = check }(KeycloakHealthCheck)
+	KeycloakHealthCheck = func(_ context.Context, _ config.Configuration) error {
+		s.Fail("Keycloak should not be checked")
+		return nil
+	}
+
+	s.NoError(waitForKeycloak(context.Background(), config.Configuration{}))
+}
+
+// TestWaitForKeycloakRecoversAfterRetries tests that waitForKeycloak succeeds once Keycloak is up
+func (s *PluginsTestSuite) TestWaitForKeycloakRecoversAfterRetries() {
+	defer func(check func(context.Context, config.Configuration) error) { KeycloakHealthCheck = check }(KeycloakHealthCheck)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	ctx, clock := retrytest.Context(ctx)
+
+	attempts := 0
+	KeycloakHealthCheck = func(_ context.Context, configuration config.Configuration) error {
+		s.Equal("http://keycloak:8080", configuration.KeycloakServiceBase)
+		attempts++
+		if attempts < 3 {
+			return fmt.Errorf("%w: keycloak starting", southbound.ErrTransient)
+		}
+		return nil
+	}
+
+	s.NoError(waitForKeycloak(ctx, config.Configuration{KeycloakServiceBase: "http://keycloak:8080"}))
+	s.Equal(3, attempts)
+	s.Len(clock.Waits(), 2, "Should wait between the attempts")
+}
+
+// TestWaitForKeycloakFailsAfterRetries tests that waitForKeycloak gives up on a Keycloak that stays down, and
+// immediately on a permanent error
+func (s *PluginsTestSuite) TestWaitForKeycloakFailsAfterRetries() {
+	defer func(check func(context.Context, config.Configuration) error) { KeycloakHealthCheck = check }(KeycloakHealthCheck)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	ctx, clock := retrytest.Context(ctx)
+	configuration := config.Configuration{KeycloakServiceBase: "http://invalid-keycloak:9999"}
+
+	attempts := 0
+	KeycloakHealthCheck = func(_ context.Context, _ config.Configuration) error {
+		attempts++
+		return fmt.Errorf("%w: connection refused", southbound.ErrTransient)
+	}
+	err := waitForKeycloak(ctx, configuration)
+	s.ErrorContains(err, "keycloak not available after")
+	s.Equal(serviceBackoff.Attempts, attempts)
+	s.Len(clock.Waits(), serviceBackoff.Attempts-1, "Should wait between the attempts")
+
+	attempts = 0
+	KeycloakHealthCheck = func(_ context.Context, _ config.Configuration) error {
+		attempts++
+		return fmt.Errorf("%w: realm not found", southbound.ErrPermanent)
+	}
+	err = waitForKeycloak(ctx, configuration)
+	s.ErrorContains(err, "keycloak not available")
+	s.ErrorIs(err, southbound.ErrPermanent)
+	s.Equal(1, attempts)
+}
+
+// TestCatalogInitializeFailsWhenKeycloakFails tests that Initialize fails on Keycloak before vault is tried
+func (s *PluginsTestSuite) TestCatalogInitializeFailsWhenKeycloakFails() {
+	defer func(check func(context.Context, config.Configuration) error) { KeycloakHealthCheck = check }(KeycloakHealthCheck)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	ctx, _ = retrytest.Context(ctx)
+
+	KeycloakHealthCheck = func(_ context.Context, _ config.Configuration) error {
+		return fmt.Errorf("%w: connection refused", southbound.ErrTransient)
+	}
+	vaultAttempts := 0
+	CatalogFactory = func(_ config.Configuration) (Catalog, error) {
+		return &mockDynamicCatalog{
+			initializeClientSecretFunc: func(_ context.Context) (string, error) {
+				vaultAttempts++
+				return "", nil
+			},
+		}, nil
+	}
+
+	plugin := &CatalogProvisionerPlugin{
+		config: config.Configuration{
+			KeycloakServiceBase: "http://invalid-keycloak:9999",
+			VaultServer:         "localhost:8200",
+			CatalogServer:       "localhost:8080",
+		},
+	}
+
+	err := plugin.Initialize(ctx, nil)
+	s.ErrorContains(err, "catalog initialization failed during keycloak check: keycloak not available")
+	s.Zero(vaultAttempts, "Vault should not be tried without Keycloak")
+}
+
+// TestCatalogProvisionerPluginGoldenRegistries checks the registries sent to the catalog for representative projects
+// and configurations against the reviewed golden files in testdata/golden. Run the test with -update to write them
+// after an intended change, e.g. of the registry templates, and review the diff.
+func (s *PluginsTestSuite) TestCatalogProvisionerPluginGoldenRegistries() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	CatalogFactory = newTestCatalog
+	_, _ = newTestCatalog(config.Configuration{})
+	defer func() { mockCatalog.registries = map[string]southbound.RegistryAttributes{} }()
+
+	templateFile := filepath.Join(s.T().TempDir(), "registries.yaml")
+	s.NoError(os.WriteFile(templateFile, []byte(`
+registries:
+  - name: customer-mirror
+    displayName: '{{ .Organization }} mirror'
+    description: 'Mirror of {{ .HarborProjectName }}'
+    type: IMAGE
+    rootURL: '{{ trimSuffix .HarborOCIRegistry "/" }}/mirror/{{ lower .Project }}'
+    username: '{{ .HarborPullUsername }}'
+    authToken: '{{ .HarborPullToken }}'
+  - name: public-charts
+    type: HELM
+    rootURL: 'oci://charts.example.com/{{ .ProjectUUID }}'
+    anonymous: true
+`), 0600))
+
+	orchestrator := config.Configuration{
+		HarborServerExternal:       "https://registry-oci.orch.example.com",
+		ReleaseServiceRootURL:      "oci://registry-rs.edgeorchestration.intel.com",
+		ReleaseServiceProxyRootURL: "oci://rs-proxy.rs-proxy.svc.cluster.local:8443",
+	}
+	separateRegistries := orchestrator
+	separateRegistries.HarborHelmRegistryExternal = "oci://charts.orch.example.com"
+	separateRegistries.HarborDockerRegistryExternal = "oci://images.orch.example.com"
+	separateRegistries.ReleaseServiceHelmRootURL = "oci://charts.example.com"
+	separateRegistries.ReleaseServiceImagePathPrefix = "/edge-orch/images/"
+	customTemplate := orchestrator
+	customTemplate.RegistryTemplatePath = templateFile
+
+	tests := map[string]struct {
+		configuration config.Configuration
+		event         Event
+	}{
+		"catalog-registries-default": {
+			configuration: orchestrator,
+			event:         Event{EventType: "create", UUID: "0d5a8c3e-uuid", Organization: "acme", Name: "edge-apps"},
+		},
+		// Only the Docker registry lower cases the Harbor project name
+		"catalog-registries-separate-registries": {
+			configuration: separateRegistries,
+			event:         Event{EventType: "create", UUID: "0d5a8c3e-uuid", Organization: "Acme", Name: "Edge-Apps"},
+		},
+		"catalog-registries-template": {
+			configuration: customTemplate,
+			event:         Event{EventType: "create", UUID: "0d5a8c3e-uuid", Organization: "Acme", Name: "Edge-Apps"},
+		},
+	}
+	for name, test := range tests {
+		mockCatalog.registries = map[string]southbound.RegistryAttributes{}
+		plugin, err := NewCatalogProvisionerPlugin(test.configuration)
+		s.NoError(err, name)
+		pluginData := NewPluginData()
+		s.NoError((&InitPlugin{}).CreateEvent(ctx, test.event, pluginData), name)
+		s.NoError(plugin.CreateEvent(ctx, test.event, pluginData), name)
+		golden.Assert(s.T(), name, mockCatalog.registries)
+	}
+}
@@ -0,0 +1,316 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// DefaultRegistryTemplate defines the registries created in the catalog for every project when no
+// registry template file is configured.
+const DefaultRegistryTemplate = `
+registries:
+  - name: intel-rs-helm
+    displayName: intel-rs-helm
+    description: 'Repo on registry {{ replace .ReleaseServiceRootURL "oci://" "" }}'
+    type: HELM
+    rootURL: '{{ .ReleaseServiceHelmRegistry }}'
+    anonymous: true
+  - name: intel-rs-images
+    displayName: intel-rs-image
+    description: 'Repo on registry {{ replace .ReleaseServiceRootURL "oci://" "" }}'
+    type: IMAGE
+    rootURL: '{{ .ReleaseServiceImageRegistry }}'
+    anonymous: true
+  - name: harbor-helm-oci
+    displayName: harbor oci helm
+    description: Harbor OCI helm charts registry
+    type: HELM
+    rootURL: '{{ .HarborHelmRegistry }}/{{ .HarborProjectName }}'
+    inventoryURL: '{{ .HarborServerExternal }}/api/v2.0/projects/{{ .HarborProjectName }}'
+    username: '{{ .HarborUsername }}'
+    cacerts: use-dynamic-cacert
+    authToken: '{{ .HarborToken }}'
+  - name: harbor-docker-oci
+    displayName: harbor oci docker
+    description: Harbor OCI docker images registry
+    type: IMAGE
+    rootURL: '{{ .HarborDockerRegistry }}/{{ lower .HarborProjectName }}'
+    username: '{{ .HarborPullUsername }}'
+    cacerts: use-dynamic-cacert
+    authToken: '{{ .HarborPullToken }}'
+`
+
+// RegistryTemplate is the definition of a single catalog registry. Every field but Anonymous is a Go template
+// that is expanded with RegistryTemplateData.
+type RegistryTemplate struct {
+	Name         string `yaml:"name"`
+	DisplayName  string `yaml:"displayName"`
+	Description  string `yaml:"description"`
+	Type         string `yaml:"type"`
+	RootURL      string `yaml:"rootURL"`
+	InventoryURL string `yaml:"inventoryURL"`
+	Username     string `yaml:"username"`
+	Cacerts      string `yaml:"cacerts"`
+	AuthToken    string `yaml:"authToken"`
+	// the registry is pulled without credentials. Registries that are not anonymous need a username and an
+	// authToken
+	Anonymous bool `yaml:"anonymous"`
+}
+
+type registryTemplates struct {
+	Registries []RegistryTemplate `yaml:"registries"`
+}
+
+// RegistryTemplateData holds the variables available to registry templates. HarborOCIRegistry is the Harbor server
+// external to the cluster with the oci scheme; HarborHelmRegistry and HarborDockerRegistry default to it unless the
+// registries are reached on other hosts. Likewise ReleaseServiceHelmRegistry and ReleaseServiceImageRegistry default
+// to ReleaseServiceProxyRootURL and ReleaseServiceRootURL, with their path prefixes.
+type RegistryTemplateData struct {
+	Organization                string
+	Project                     string
+	ProjectUUID                 string
+	HarborProjectName           string
+	HarborServerExternal        string
+	HarborOCIRegistry           string
+	HarborHelmRegistry          string
+	HarborDockerRegistry        string
+	HarborUsername              string
+	HarborToken                 string
+	HarborPullUsername          string
+	HarborPullToken             string
+	ReleaseServiceRootURL       string
+	ReleaseServiceProxyRootURL  string
+	ReleaseServiceHelmRegistry  string
+	ReleaseServiceImageRegistry string
+}
+
+// newRegistryTemplateData returns the template variables for the project of the event, with the given robot
+// account credentials.
+func newRegistryTemplateData(configuration config.Configuration, event Event, credentials HarborRobot, pullCredentials HarborRobot) RegistryTemplateData {
+	return RegistryTemplateData{
+		Organization:                event.Organization,
+		Project:                     event.Name,
+		ProjectUUID:                 event.UUID,
+		HarborProjectName:           southbound.HarborProjectName(event.Organization, event.Name),
+		HarborServerExternal:        configuration.HarborServerExternal,
+		HarborOCIRegistry:           strings.ReplaceAll(configuration.HarborServerExternal, "https://", "oci://"),
+		HarborHelmRegistry:          configuration.HarborHelmRegistry(),
+		HarborDockerRegistry:        configuration.HarborDockerRegistry(),
+		HarborUsername:              credentials.Username,
+		HarborToken:                 credentials.Token,
+		HarborPullUsername:          pullCredentials.Username,
+		HarborPullToken:             pullCredentials.Token,
+		ReleaseServiceRootURL:       configuration.ReleaseServiceRootURL,
+		ReleaseServiceProxyRootURL:  configuration.ReleaseServiceProxyRootURL,
+		ReleaseServiceHelmRegistry:  configuration.ReleaseServiceHelmRegistry(),
+		ReleaseServiceImageRegistry: configuration.ReleaseServiceImageRegistry(),
+	}
+}
+
+// registryAffixes are added before and after the names of the catalog registries of a project, so that the
+// environments sharing a catalog do not collide.
+type registryAffixes struct {
+	prefix string
+	suffix string
+}
+
+func (a registryAffixes) name(name string) string {
+	return a.prefix + name + a.suffix
+}
+
+// projectRegistryAffixes returns the registry name affixes of the project. Once its registries are created, they
+// are the affixes recorded in its inventory, so that the registries are still found after the configured affixes
+// change; the configured affixes only apply to new projects, or when no inventory is kept.
+func projectRegistryAffixes(ctx context.Context, configuration config.Configuration, uuid string) (registryAffixes, error) {
+	configured := registryAffixes{prefix: configuration.CatalogRegistryPrefix, suffix: configuration.CatalogRegistrySuffix}
+	if configuration.PodNamespace == "" {
+		return configured, nil
+	}
+	store, err := InventoryStoreFactory(configuration)
+	if err != nil {
+		return configured, err
+	}
+	inventory, err := store.Load(ctx, uuid)
+	if err != nil {
+		return configured, err
+	}
+	if inventory == nil || len(inventory.CatalogRegistries) == 0 {
+		return configured, nil
+	}
+	recorded := registryAffixes{prefix: inventory.CatalogRegistryPrefix, suffix: inventory.CatalogRegistrySuffix}
+	if recorded != configured {
+		log.Infof("Catalog registries of project %s keep the name prefix %q and suffix %q they were created with",
+			inventory.Project, recorded.prefix, recorded.suffix)
+	}
+	return recorded, nil
+}
+
+var registryTemplateFuncs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"replace":    strings.ReplaceAll,
+	"trimPrefix": strings.TrimPrefix,
+	"trimSuffix": strings.TrimSuffix,
+}
+
+// loadRegistryTemplates reads the registry templates from the configured file, falling back to
+// DefaultRegistryTemplate. All templates are parsed up front so that errors are reported at startup.
+func loadRegistryTemplates(configuration config.Configuration) ([]RegistryTemplate, error) {
+	templateYAML := []byte(DefaultRegistryTemplate)
+	if configuration.RegistryTemplatePath != "" {
+		log.Infof("Loading catalog registry template %s", configuration.RegistryTemplatePath)
+		var err error
+		templateYAML, err = os.ReadFile(configuration.RegistryTemplatePath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read registry template: %w", err)
+		}
+	}
+
+	templates := registryTemplates{}
+	if err := yaml.UnmarshalStrict(templateYAML, &templates); err != nil {
+		return nil, fmt.Errorf("invalid registry template: %w", err)
+	}
+	for _, t := range templates.Registries {
+		if t.Name == "" {
+			return nil, fmt.Errorf("invalid registry template: registry name is required")
+		}
+		if err := t.validateCredentials(); err != nil {
+			return nil, err
+		}
+		if _, err := t.expand(RegistryTemplateData{}); err != nil {
+			return nil, err
+		}
+	}
+	return templates.Registries, nil
+}
+
+// validateCredentials checks that the registry is either anonymous or defines both a username and an authToken.
+func (t RegistryTemplate) validateCredentials() error {
+	switch {
+	case t.Anonymous && (t.Username != "" || t.AuthToken != ""):
+		return fmt.Errorf("invalid registry template for %s: an anonymous registry has no username or authToken", t.Name)
+	case !t.Anonymous && (t.Username == "" || t.AuthToken == ""):
+		return fmt.Errorf("invalid registry template for %s: username and authToken are required, "+
+			"set anonymous: true for a registry pulled without credentials", t.Name)
+	}
+	return nil
+}
+
+// usesHarborCredentials returns true if the registry is configured with the Harbor robot account credentials.
+func (t RegistryTemplate) usesHarborCredentials() bool {
+	for _, text := range []string{t.Username, t.AuthToken} {
+		if strings.Contains(text, ".HarborUsername") || strings.Contains(text, ".HarborToken") {
+			return true
+		}
+	}
+	return false
+}
+
+// usesHarborPullCredentials returns true if the registry is configured with the pull-only Harbor robot account
+// credentials.
+func (t RegistryTemplate) usesHarborPullCredentials() bool {
+	for _, text := range []string{t.Username, t.AuthToken} {
+		if strings.Contains(text, ".HarborPullUsername") || strings.Contains(text, ".HarborPullToken") {
+			return true
+		}
+	}
+	return false
+}
+
+func expandField(registryName string, field string, text string, data RegistryTemplateData) (string, error) {
+	t, err := template.New(registryName + "." + field).Funcs(registryTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid registry template for %s: %w", registryName, err)
+	}
+	var out bytes.Buffer
+	if err := t.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("invalid registry template for %s: %w", registryName, err)
+	}
+	return out.String(), nil
+}
+
+func (t RegistryTemplate) expand(data RegistryTemplateData) (southbound.RegistryAttributes, error) {
+	attrs := southbound.RegistryAttributes{
+		ProjectUUID: data.ProjectUUID,
+		Anonymous:   t.Anonymous,
+	}
+	fields := []struct {
+		name  string
+		text  string
+		value *string
+	}{
+		{"name", t.Name, &attrs.Name},
+		{"displayName", t.DisplayName, &attrs.DisplayName},
+		{"description", t.Description, &attrs.Description},
+		{"type", t.Type, &attrs.Type},
+		{"rootURL", t.RootURL, &attrs.RootURL},
+		{"inventoryURL", t.InventoryURL, &attrs.InventoryURL},
+		{"username", t.Username, &attrs.Username},
+		{"cacerts", t.Cacerts, &attrs.Cacerts},
+		{"authToken", t.AuthToken, &attrs.AuthToken},
+	}
+	for _, f := range fields {
+		value, err := expandField(t.Name, f.name, f.text, data)
+		if err != nil {
+			return attrs, err
+		}
+		*f.value = value
+	}
+	return attrs, nil
+}
+
+// registryMismatches returns how a registry read back from the catalog differs from the attributes it was written
+// with, e.g. a root URL or type that the catalog defaulted. Credentials are only checked for being set, as the
+// catalog may store the auth token in another form.
+func registryMismatches(written southbound.RegistryAttributes, stored southbound.RegistryAttributes) []string {
+	var mismatches []string
+	if stored.RootURL != written.RootURL {
+		mismatches = append(mismatches, fmt.Sprintf("rootURL is %q instead of %q", stored.RootURL, written.RootURL))
+	}
+	if !strings.EqualFold(stored.Type, written.Type) {
+		mismatches = append(mismatches, fmt.Sprintf("type is %q instead of %q", stored.Type, written.Type))
+	}
+	if stored.Username != written.Username {
+		mismatches = append(mismatches, fmt.Sprintf("username is %q instead of %q", stored.Username, written.Username))
+	}
+	if written.AuthToken != "" && stored.AuthToken == "" {
+		mismatches = append(mismatches, "authToken is not set")
+	}
+	return mismatches
+}
+
+// verifyRegistry reads a registry back after writing it, so that fields the catalog silently changed are caught
+// rather than trusted. A registry that does not match is written again once; if it still does not match, the
+// mismatch is reported as a warning of the event.
+func verifyRegistry(ctx context.Context, catalog Catalog, event Event, attrs southbound.RegistryAttributes) error {
+	for attempt := 1; ; attempt++ {
+		stored, err := catalog.GetRegistry(ctx, attrs.ProjectUUID, attrs.Name)
+		if err != nil {
+			return err
+		}
+		mismatches := registryMismatches(attrs, stored)
+		if len(mismatches) == 0 {
+			return nil
+		}
+		if attempt > 1 {
+			event.ReportWarning("Catalog registry %s does not match its definition: %s", attrs.Name, strings.Join(mismatches, ", "))
+			return nil
+		}
+		log.Warnf("Catalog registry %s does not match its definition, writing it again: %s", attrs.Name, strings.Join(mismatches, ", "))
+		if err := catalog.CreateOrUpdateRegistry(ctx, attrs); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// StarterApp is a catalog YAML file, e.g. an application or a deployment package with its profiles, that is
+// uploaded to the catalog of every new project.
+type StarterApp struct {
+	Name    string `yaml:"name"`
+	Content string `yaml:"content"`
+}
+
+// loadStarterApps reads the list of starter applications from the configured file. All files are checked up front
+// so that errors are reported at startup.
+func loadStarterApps(configuration config.Configuration) ([]StarterApp, error) {
+	if configuration.StarterAppsPath == "" {
+		return nil, nil
+	}
+	log.Infof("Loading catalog starter applications %s", configuration.StarterAppsPath)
+	listYAML, err := os.ReadFile(configuration.StarterAppsPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read starter applications: %w", err)
+	}
+
+	apps := []StarterApp{}
+	if err := yaml.UnmarshalStrict(listYAML, &apps); err != nil {
+		return nil, fmt.Errorf("invalid starter applications: %w", err)
+	}
+	names := map[string]bool{}
+	for _, app := range apps {
+		if app.Name == "" || app.Content == "" {
+			return nil, fmt.Errorf("invalid starter applications: name and content are required")
+		}
+		if names[app.Name] {
+			return nil, fmt.Errorf("invalid starter applications: %s is listed more than once", app.Name)
+		}
+		names[app.Name] = true
+		if err := yaml.Unmarshal([]byte(app.Content), &map[string]interface{}{}); err != nil {
+			return nil, fmt.Errorf("invalid starter application %s: %w", app.Name, err)
+		}
+	}
+	return apps, nil
+}
+
+// uploadStarterApps uploads the starter applications to the catalog of the project in a single upload session.
+// The registries they refer to must already exist.
+func (p *CatalogProvisionerPlugin) uploadStarterApps(ctx context.Context, catalog Catalog, event Event, pluginData *PluginData) error {
+	names := []string{}
+	for i, app := range p.starterApps {
+		event.ReportProgress("Uploading starter applications %d/%d", i+1, len(p.starterApps))
+		lastUpload := i == len(p.starterApps)-1
+		if err := catalog.UploadYAMLFile(ctx, event.UUID, app.Name, []byte(app.Content), lastUpload); err != nil {
+			log.Errorf("Error uploading starter application %s: %v", app.Name, err)
+			return err
+		}
+		names = append(names, app.Name)
+	}
+	if len(names) > 0 {
+		recordInventory(pluginData, func(inventory *southbound.Inventory) {
+			inventory.StarterApps = names
+		})
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+)
+
+// CredentialRotation lists what rotating the credentials of a project changed.
+type CredentialRotation struct {
+	// robot accounts given a new secret
+	Robots []string
+	// catalog registries given the new credentials
+	Registries []string
+}
+
+// RotateCredentials issues new secrets for the Harbor robot accounts given to the catalog, then updates the
+// username and auth token of the catalog registries that use them. The other registry fields and the rest of the
+// project are left untouched, so that credentials can be rotated without provisioning the project again.
+func RotateCredentials(ctx context.Context, configuration config.Configuration, event Event) (*CredentialRotation, error) {
+	harbor, err := HarborFactory(ctx, configuration.HarborServer, configuration.KeycloakServer, configuration.Secrets.HarborAdmin)
+	if err != nil {
+		return nil, err
+	}
+	harbor.SetRequestTimeout(configuration.HarborRequestTimeout)
+	org := strings.ToLower(event.Organization)
+	name := strings.ToLower(event.Name)
+	projectID, err := harbor.GetProjectID(ctx, org, name)
+	if err != nil {
+		return nil, err
+	}
+
+	rotation := &CredentialRotation{}
+	rotate := func(robotName string) (HarborRobot, error) {
+		robot, err := harbor.GetRobot(ctx, org, name, robotName, projectID)
+		if err != nil {
+			return HarborRobot{}, fmt.Errorf("unable to rotate robot %s of project %s: %w", robotName, event.Name, err)
+		}
+		secret, err := harbor.RefreshRobotSecret(ctx, robot.ID)
+		if err != nil {
+			return HarborRobot{}, err
+		}
+		log.Infof("Refreshed secret of robot %s for project %s", robot.Name, event.Name)
+		rotation.Robots = append(rotation.Robots, robot.Name)
+		return HarborRobot{Username: robot.Name, Token: secret}, nil
+	}
+	// Only the robots given to the catalog are rotated, nothing stores the secrets of the others
+	robots := configuration.HarborRobots
+	if len(robots) == 0 {
+		robots = config.DefaultHarborRobots
+	}
+	var credentials, pullCredentials HarborRobot
+	if robot, ok := config.CatalogRobot(robots, config.RobotProfileReadWrite); ok {
+		if credentials, err = rotate(robot.Name); err != nil {
+			return rotation, err
+		}
+	}
+	if robot, ok := config.CatalogRobot(robots, config.RobotProfilePull); ok {
+		if pullCredentials, err = rotate(robot.Name); err != nil {
+			return rotation, err
+		}
+	}
+
+	registries, err := loadRegistryTemplates(configuration)
+	if err != nil {
+		return rotation, err
+	}
+	catalog, err := CatalogFactory(configuration)
+	if err != nil {
+		return rotation, err
+	}
+	data := newRegistryTemplateData(configuration, event, credentials, pullCredentials)
+	affixes, err := projectRegistryAffixes(ctx, configuration, event.UUID)
+	if err != nil {
+		return rotation, err
+	}
+	for _, registry := range registries {
+		if !(registry.usesHarborCredentials() && credentials.Username != "") &&
+			!(registry.usesHarborPullCredentials() && pullCredentials.Username != "") {
+			continue
+		}
+		attrs, err := registry.expand(data)
+		if err != nil {
+			return rotation, err
+		}
+		attrs.Name = affixes.name(attrs.Name)
+		if err := catalog.UpdateRegistryCredentials(ctx, event.UUID, attrs.Name, attrs.Username, attrs.AuthToken); err != nil {
+			return rotation, fmt.Errorf("robot secrets were refreshed but registry %s was not updated, reprovision the project: %w", attrs.Name, err)
+		}
+		rotation.Registries = append(rotation.Registries, attrs.Name)
+	}
+	return rotation, nil
+}
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+)
+
+func (s *PluginsTestSuite) TestRotateCredentials() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	HarborFactory = NewTestHarbor
+	CatalogFactory = newTestCatalog
+	harbor, _ := NewTestHarbor(ctx, "", "", testAdminSecret)
+	_, _, err := harbor.CreateRobot(ctx, harborReadWriteRobot, "rot", "proj", config.DefaultHarborReadWriteAccess)
+	s.NoError(err)
+	_, _, err = harbor.CreateRobot(ctx, harborReadOnlyRobot, "rot", "proj", config.DefaultHarborPullAccess)
+	s.NoError(err)
+	robot, err := harbor.GetRobot(ctx, "rot", "proj", harborReadWriteRobot, HarborProjectID)
+	s.NoError(err)
+	pullRobot, err := harbor.GetRobot(ctx, "rot", "proj", harborReadOnlyRobot, HarborProjectID)
+	s.NoError(err)
+
+	_, _ = newTestCatalog(config.Configuration{})
+	mockCatalog.registries = map[string]southbound.RegistryAttributes{
+		"harbor-helm-oci":   {Name: "harbor-helm-oci", RootURL: "oci://harbor/helm", Username: "old", AuthToken: "old"},
+		"harbor-docker-oci": {Name: "harbor-docker-oci", RootURL: "oci://harbor/docker", Username: "old", AuthToken: "old"},
+		"intel-rs-helm":     {Name: "intel-rs-helm", RootURL: "oci://rs"},
+	}
+
+	event := Event{Organization: "Rot", Name: "Proj", UUID: "rot-uuid"}
+	rotation, err := RotateCredentials(ctx, config.Configuration{}, event)
+	s.NoError(err)
+	s.Equal([]string{robot.Name, pullRobot.Name}, rotation.Robots)
+	s.Equal([]string{"harbor-helm-oci", "harbor-docker-oci"}, rotation.Registries)
+
+	// Only the credentials of the registries using the robots change
+	helm := mockCatalog.registries["harbor-helm-oci"]
+	s.Equal(robot.Name, helm.Username)
+	s.Equal(fmt.Sprintf("refreshed-secret-%d", robot.ID), helm.AuthToken)
+	s.Equal("oci://harbor/helm", helm.RootURL)
+	docker := mockCatalog.registries["harbor-docker-oci"]
+	s.Equal(pullRobot.Name, docker.Username)
+	s.Equal(fmt.Sprintf("refreshed-secret-%d", pullRobot.ID), docker.AuthToken)
+	s.Equal("oci://harbor/docker", docker.RootURL)
+	s.Equal(southbound.RegistryAttributes{Name: "intel-rs-helm", RootURL: "oci://rs"}, mockCatalog.registries["intel-rs-helm"])
+
+	// A registry that is missing is reported
+	delete(mockCatalog.registries, "harbor-docker-oci")
+	_, err = RotateCredentials(ctx, config.Configuration{}, event)
+	s.ErrorIs(err, southbound.ErrNotFound)
+	s.ErrorContains(err, "reprovision the project")
+
+	// A project without robot accounts cannot be rotated
+	rotation, err = RotateCredentials(ctx, config.Configuration{}, Event{Organization: "rot", Name: "other", UUID: "other-uuid"})
+	s.ErrorIs(err, southbound.ErrNotFound)
+	s.Empty(rotation.Robots)
+}
+
+func (s *PluginsTestSuite) TestRotateCredentialsRegistryAffixes() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	HarborFactory = NewTestHarbor
+	CatalogFactory = newTestCatalog
+	harbor, _ := NewTestHarbor(ctx, "", "", testAdminSecret)
+	_, _, err := harbor.CreateRobot(ctx, harborReadWriteRobot, "affix", "proj", config.DefaultHarborReadWriteAccess)
+	s.NoError(err)
+	_, _, err = harbor.CreateRobot(ctx, harborReadOnlyRobot, "affix", "proj", config.DefaultHarborPullAccess)
+	s.NoError(err)
+
+	store := &testInventoryStore{inventories: map[string]*southbound.Inventory{
+		"affix-uuid": {UUID: "affix-uuid", CatalogRegistries: []string{"staging-harbor-helm-oci", "staging-harbor-docker-oci"}, CatalogRegistryPrefix: "staging-"},
+	}}
+	InventoryStoreFactory = func(_ config.Configuration) (InventoryStore, error) { return store, nil }
+	defer func() { InventoryStoreFactory = NewInventoryStore }()
+	_, _ = newTestCatalog(config.Configuration{})
+	mockCatalog.registries = map[string]southbound.RegistryAttributes{
+		"staging-harbor-helm-oci":   {Name: "staging-harbor-helm-oci", Username: "old", AuthToken: "old"},
+		"staging-harbor-docker-oci": {Name: "staging-harbor-docker-oci", Username: "old", AuthToken: "old"},
+	}
+
+	// The registries are found with the prefix recorded in the inventory rather than the configured one
+	event := Event{Organization: "Affix", Name: "Proj", UUID: "affix-uuid"}
+	rotation, err := RotateCredentials(ctx, config.Configuration{PodNamespace: "orch-app", CatalogRegistryPrefix: "prod-"}, event)
+	s.NoError(err)
+	s.Equal([]string{"staging-harbor-helm-oci", "staging-harbor-docker-oci"}, rotation.Registries)
+	s.NotEqual("old", mockCatalog.registries["staging-harbor-helm-oci"].AuthToken)
+}
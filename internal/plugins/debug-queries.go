@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package plugins
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+)
+
+// ErrADMNotConfigured is returned by ListDebugDeployments when the controller has no ADM server
+var ErrADMNotConfigured = errors.New("ADM server is not configured")
+
+// DebugRegistry is a catalog registry of a project as listed for troubleshooting. Its auth token is never listed.
+type DebugRegistry struct {
+	Name         string `json:"name"`
+	DisplayName  string `json:"displayName,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Type         string `json:"type"`
+	RootURL      string `json:"rootURL"`
+	InventoryURL string `json:"inventoryURL,omitempty"`
+	Username     string `json:"username,omitempty"`
+	Anonymous    bool   `json:"anonymous,omitempty"`
+}
+
+// DebugDeployment is an ADM deployment of a project as listed for troubleshooting
+type DebugDeployment struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	AppName     string `json:"appName"`
+	AppVersion  string `json:"appVersion"`
+	ProfileName string `json:"profileName"`
+	State       string `json:"state,omitempty"`
+}
+
+// ListDebugRegistries lists the catalog registries of a project with the credentials of the controller, so that
+// operators do not need a token of their own during incidents. Nothing is changed in the catalog.
+func ListDebugRegistries(ctx context.Context, configuration config.Configuration, uuid string) ([]DebugRegistry, error) {
+	catalog, err := CatalogFactory(configuration)
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := catalog.ListProjectRegistries(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	registries := make([]DebugRegistry, 0, len(attrs))
+	for _, a := range attrs {
+		registries = append(registries, DebugRegistry{
+			Name:         a.Name,
+			DisplayName:  a.DisplayName,
+			Description:  a.Description,
+			Type:         a.Type,
+			RootURL:      a.RootURL,
+			InventoryURL: a.InventoryURL,
+			Username:     a.Username,
+			Anonymous:    a.Anonymous,
+		})
+	}
+	return registries, nil
+}
+
+// ListDebugDeployments lists the ADM deployments of a project with the credentials of the controller, sorted by
+// display name. Nothing is changed in ADM.
+func ListDebugDeployments(ctx context.Context, configuration config.Configuration, uuid string) ([]DebugDeployment, error) {
+	if configuration.AdmServer == "" {
+		return nil, ErrADMNotConfigured
+	}
+	adm, err := AppDeploymentFactory(configuration)
+	if err != nil {
+		return nil, err
+	}
+	infos, err := adm.ListDeployments(ctx, uuid, southbound.DeploymentFilter{})
+	if err != nil {
+		return nil, err
+	}
+	deployments := make([]DebugDeployment, 0, len(infos))
+	for _, info := range infos {
+		deployments = append(deployments, DebugDeployment{
+			ID:          info.ID,
+			DisplayName: info.DisplayName,
+			AppName:     info.AppName,
+			AppVersion:  info.AppVersion,
+			ProfileName: info.ProfileName,
+			State:       info.State,
+		})
+	}
+	slices.SortFunc(deployments, func(a, b DebugDeployment) int { return strings.Compare(a.DisplayName, b.DisplayName) })
+	return deployments, nil
+}
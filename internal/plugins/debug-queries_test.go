@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"context"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+)
+
+func (s *PluginsTestSuite) TestDebugQueries() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	CatalogFactory = newTestCatalog
+	_, _ = newTestCatalog(config.Configuration{})
+	mockCatalog.registries = map[string]southbound.RegistryAttributes{
+		"harbor-helm-oci": {Name: "harbor-helm-oci", Type: "HELM", RootURL: "oci://harbor/helm", Username: "robot", AuthToken: "secret", ProjectUUID: "uuid-1"},
+		"intel-rs-helm":   {Name: "intel-rs-helm", Type: "HELM", RootURL: "oci://rs", Anonymous: true, ProjectUUID: "uuid-1"},
+		"other":           {Name: "other", ProjectUUID: "uuid-2"},
+	}
+
+	// The registries of the project are listed without their auth token
+	registries, err := ListDebugRegistries(ctx, config.Configuration{}, "uuid-1")
+	s.NoError(err)
+	s.Equal([]DebugRegistry{
+		{Name: "harbor-helm-oci", Type: "HELM", RootURL: "oci://harbor/helm", Username: "robot"},
+		{Name: "intel-rs-helm", Type: "HELM", RootURL: "oci://rs", Anonymous: true},
+	}, registries)
+
+	var tenant string
+	AppDeploymentFactory = func(_ config.Configuration) (AppDeployment, error) {
+		return &mockDynamicADM{listDeploymentsFunc: func(_ context.Context, t string) (map[string]southbound.DeploymentInfo, error) {
+			tenant = t
+			return map[string]southbound.DeploymentInfo{
+				"observability": {ID: "id-2", DisplayName: "observability", AppName: "obs", AppVersion: "1.0", State: "RUNNING"},
+				"base":          {ID: "id-1", DisplayName: "base", AppName: "base", AppVersion: "0.2.0", ProfileName: "default"},
+			}, nil
+		}}, nil
+	}
+	defer func() { AppDeploymentFactory = NewAppDeployment }()
+
+	_, err = ListDebugDeployments(ctx, config.Configuration{}, "uuid-1")
+	s.ErrorIs(err, ErrADMNotConfigured)
+	deployments, err := ListDebugDeployments(ctx, config.Configuration{AdmServer: "adm:8080"}, "uuid-1")
+	s.NoError(err)
+	s.Equal("uuid-1", tenant)
+	s.Equal([]DebugDeployment{
+		{ID: "id-1", DisplayName: "base", AppName: "base", AppVersion: "0.2.0", ProfileName: "default"},
+		{ID: "id-2", DisplayName: "observability", AppName: "obs", AppVersion: "1.0", State: "RUNNING"},
+	}, deployments)
+}
@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+)
+
+// Status of a plugin in a DispatchResult
+const (
+	// PluginSucceeded plugins completed the event
+	PluginSucceeded = "succeeded"
+	// PluginFailed plugins returned an error on their last attempt
+	PluginFailed = "failed"
+	// PluginSkipped plugins do not handle the type of the event
+	PluginSkipped = "skipped"
+)
+
+// PluginResult is how a plugin handled an event, over all the attempts made to dispatch it.
+type PluginResult struct {
+	Name   string
+	Status string
+	// time spent in the plugin, including retries
+	Duration time.Duration
+	Attempts int
+	// error of the last attempt, nil if the plugin succeeded
+	Err error
+}
+
+// DispatchResult is the outcome of dispatching an event to the plugins. It is kept with the event lifecycle, so
+// that the result returned after a retry covers the attempts before it too.
+type DispatchResult struct {
+	EventType string
+	// plugins that handled or skipped the event, in dispatch order
+	Plugins []PluginResult
+	// resources created or kept by the plugins, nil if none were recorded
+	Inventory *southbound.Inventory
+	// issues reported by the plugins that did not fail the event
+	Warnings []string
+	// bytes of catalog artifacts and Harbor request bodies sent by the plugins, by southbound service. Nil if nothing
+	// was sent
+	PayloadBytes map[string]int64
+}
+
+// Plugin returns the result of the named plugin, or nil if the event was not dispatched to it.
+func (r *DispatchResult) Plugin(name string) *PluginResult {
+	if r == nil {
+		return nil
+	}
+	for i := range r.Plugins {
+		if r.Plugins[i].Name == name {
+			return &r.Plugins[i]
+		}
+	}
+	return nil
+}
+
+// FailedPlugin returns the name of the plugin that failed the event, or an empty string if none did.
+func (r *DispatchResult) FailedPlugin() string {
+	if r == nil {
+		return ""
+	}
+	for _, plugin := range r.Plugins {
+		if plugin.Status == PluginFailed {
+			return plugin.Name
+		}
+	}
+	return ""
+}
+
+// PluginTimes returns the time spent in each plugin that handled the event, keyed by plugin name.
+func (r *DispatchResult) PluginTimes() map[string]time.Duration {
+	times := map[string]time.Duration{}
+	if r == nil {
+		return times
+	}
+	for _, plugin := range r.Plugins {
+		if plugin.Status != PluginSkipped {
+			times[plugin.Name] = plugin.Duration
+		}
+	}
+	return times
+}
+
+// Resources returns the identifiers of the resources recorded by the plugins, e.g.
+// "harbor-project/catalog-apps-org-proj", in the order of the inventory.
+func (r *DispatchResult) Resources() []string {
+	if r == nil || r.Inventory == nil {
+		return nil
+	}
+	var resources []string
+	if harbor := r.Inventory.HarborProject; harbor != nil {
+		resources = append(resources, "harbor-project/"+harbor.Name)
+		for _, robot := range harbor.Robots {
+			resources = append(resources, "harbor-robot/"+robot.Name)
+		}
+	}
+	for _, registry := range r.Inventory.CatalogRegistries {
+		resources = append(resources, "catalog-registry/"+registry)
+	}
+	for _, app := range r.Inventory.StarterApps {
+		resources = append(resources, "starter-app/"+app)
+	}
+	for _, pkg := range r.Inventory.ExtensionPackages {
+		resources = append(resources, "catalog-package/"+pkg.Name+":"+pkg.Version)
+	}
+	for _, deployment := range r.Inventory.Deployments {
+		resources = append(resources, "adm-deployment/"+deployment.ID)
+	}
+	if r.Inventory.Namespace != "" {
+		resources = append(resources, "namespace/"+r.Inventory.Namespace)
+	}
+	return resources
+}
+
+// clone returns a copy of the result that does not change as the event is dispatched again.
+func (r *DispatchResult) clone() *DispatchResult {
+	clone := *r
+	clone.Plugins = slices.Clone(r.Plugins)
+	clone.Warnings = slices.Clone(r.Warnings)
+	clone.PayloadBytes = maps.Clone(r.PayloadBytes)
+	return &clone
+}
+
+// pluginResult returns the result of the named plugin, adding it if the plugin has none yet.
+func (r *DispatchResult) pluginResult(name string) *PluginResult {
+	if result := r.Plugin(name); result != nil {
+		return result
+	}
+	r.Plugins = append(r.Plugins, PluginResult{Name: name})
+	return &r.Plugins[len(r.Plugins)-1]
+}
+
+func (r *DispatchResult) addWarning(message string) {
+	if !slices.Contains(r.Warnings, message) {
+		r.Warnings = append(r.Warnings, message)
+	}
+}
@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package plugins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Kinds and results of the extension deletions, as reported in the labels of the deletion metrics
+const (
+	extensionKindDeployment = "deployment"
+	extensionKindPackage    = "package"
+	deletionDeleted         = "deleted"
+	deletionMissing         = "missing"
+	deletionFailed          = "failed"
+)
+
+var extensionDeletions = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "tenant_controller_extension_deletions_total",
+	Help: "Extension deployments and packages removed with their project, by kind and result: deleted, missing or failed",
+}, []string{"kind", "result"})
+
+func init() {
+	metrics.Registry.MustRegister(extensionDeletions)
+}
+
+// DeleteEvent removes the extensions of a deleted project: its ADM deployments first, then the extension packages
+// in its catalog, which the deployments use. Deployments and packages that are already gone are skipped. The
+// deletions are verified by listing the deployments and packages again, and any that remain are a transient error
+// so that the event is retried.
+func (p *ExtensionsProvisionerPlugin) DeleteEvent(ctx context.Context, event Event, _ *PluginData) error {
+	deployments, packages, err := p.projectExtensions(ctx, event)
+	if err != nil {
+		return err
+	}
+	if p.configuration.AdmServer != "" && len(deployments) > 0 {
+		ad, err := AppDeploymentFactory(p.configuration)
+		if err != nil {
+			return err
+		}
+		if err := deleteExtensionDeployments(ctx, ad, event, deployments); err != nil {
+			return err
+		}
+	}
+	if len(packages) == 0 {
+		return nil
+	}
+	cat, err := CatalogFactory(p.configuration)
+	if err != nil {
+		return err
+	}
+	return deleteExtensionPackages(ctx, cat, event, packages)
+}
+
+// projectExtensions returns the extension deployments and packages of a project: those of the manifest, including
+// the ones it marks absent, and those recorded in the inventory of the project, which the manifest may no longer
+// list. The extensions recorded in the inventory are deleted even if the manifest cannot be loaded.
+func (p *ExtensionsProvisionerPlugin) projectExtensions(ctx context.Context, event Event) ([]southbound.InventoryDeployment, []southbound.InventoryPackage, error) {
+	var deployments []southbound.InventoryDeployment
+	var packages []southbound.InventoryPackage
+	recorded := p.recordedInventory(ctx, event)
+	if recorded != nil {
+		deployments = append(deployments, recorded.Deployments...)
+		packages = append(packages, recorded.ExtensionPackages...)
+	}
+
+	manifest, err := LoadManifest(ctx, p.configuration)
+	if err != nil {
+		if recorded == nil {
+			return nil, nil, err
+		}
+		log.Warnf("Unable to load the extensions manifest, deleting the extensions recorded for project %s: %v", event.Name, err)
+		return deployments, packages, nil
+	}
+	manifest = p.orgExtensions[event.Organization].apply(manifest)
+	for _, dl := range manifest.Lpke.DeploymentList {
+		deployment := southbound.InventoryDeployment{DisplayName: dl.DisplayName, AppName: dl.DpName, AppVersion: dl.DpVersion, ProfileName: dl.DpProfileName}
+		if !slices.ContainsFunc(deployments, func(d southbound.InventoryDeployment) bool { return sameDeployment(d, deployment) }) {
+			deployments = append(deployments, deployment)
+		}
+	}
+	for _, dp := range manifest.Lpke.DeploymentPackages {
+		pkg := southbound.InventoryPackage{Name: path.Base(dp.Dpkg), Version: dp.Version}
+		if !slices.Contains(packages, pkg) {
+			packages = append(packages, pkg)
+		}
+	}
+	return deployments, packages, nil
+}
+
+// sameDeployment reports whether two deployments have the same display name, package, version and profile.
+func sameDeployment(a southbound.InventoryDeployment, b southbound.InventoryDeployment) bool {
+	return a.DisplayName == b.DisplayName && a.AppName == b.AppName && a.AppVersion == b.AppVersion && a.ProfileName == b.ProfileName
+}
+
+// listedDeployment reports whether the ADM deployments include the deployment.
+func listedDeployment(listed map[string]southbound.DeploymentInfo, deployment southbound.InventoryDeployment) bool {
+	info, ok := listed[deployment.DisplayName]
+	return ok && sameDeployment(deployment, southbound.InventoryDeployment{
+		DisplayName: info.DisplayName, AppName: info.AppName, AppVersion: info.AppVersion, ProfileName: info.ProfileName,
+	})
+}
+
+// deleteExtensionDeployments deletes the ADM deployments of the project that are still present, and verifies that
+// none of them remain.
+func deleteExtensionDeployments(ctx context.Context, ad AppDeployment, event Event, deployments []southbound.InventoryDeployment) error {
+	listed, err := ad.ListDeployments(ctx, event.UUID, southbound.DeploymentFilter{})
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for i, deployment := range deployments {
+		if !listedDeployment(listed, deployment) {
+			extensionDeletions.WithLabelValues(extensionKindDeployment, deletionMissing).Inc()
+			continue
+		}
+		event.ReportProgress("Deleting extension deployment %d/%d", i+1, len(deployments))
+		err := ad.DeleteDeployment(ctx, deployment.AppName, deployment.DisplayName, deployment.AppVersion, deployment.ProfileName, event.UUID, true)
+		if err != nil {
+			extensionDeletions.WithLabelValues(extensionKindDeployment, deletionFailed).Inc()
+			errs = append(errs, fmt.Errorf("failed to delete extension deployment %s: %w", deployment.DisplayName, err))
+			continue
+		}
+		extensionDeletions.WithLabelValues(extensionKindDeployment, deletionDeleted).Inc()
+		log.Infof("Deleted extension deployment %s of project %s", deployment.DisplayName, event.Name)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	listed, err = ad.ListDeployments(ctx, event.UUID, southbound.DeploymentFilter{})
+	if err != nil {
+		return err
+	}
+	remaining := 0
+	for _, deployment := range deployments {
+		if listedDeployment(listed, deployment) {
+			remaining++
+		}
+	}
+	if remaining > 0 {
+		return fmt.Errorf("%w: %d extension deployments of project %s remain after deleting them", southbound.ErrTransient, remaining, event.Name)
+	}
+	return nil
+}
+
+// deleteExtensionPackages deletes the extension packages of the project that are still in its catalog, and verifies
+// that none of them remain.
+func deleteExtensionPackages(ctx context.Context, cat Catalog, event Event, packages []southbound.InventoryPackage) error {
+	files, err := cat.ListProjectFiles(ctx, event.UUID)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for i, pkg := range packages {
+		j := slices.IndexFunc(files, func(f southbound.ProjectFile) bool { return f.Name == pkg.Name && f.Version == pkg.Version })
+		if j < 0 {
+			extensionDeletions.WithLabelValues(extensionKindPackage, deletionMissing).Inc()
+			continue
+		}
+		event.ReportProgress("Deleting extension package %d/%d", i+1, len(packages))
+		if err := cat.DeleteProjectFile(ctx, event.UUID, files[j]); err != nil {
+			extensionDeletions.WithLabelValues(extensionKindPackage, deletionFailed).Inc()
+			errs = append(errs, fmt.Errorf("failed to delete extension package %s:%s: %w", pkg.Name, pkg.Version, err))
+			continue
+		}
+		extensionDeletions.WithLabelValues(extensionKindPackage, deletionDeleted).Inc()
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	files, err = cat.ListProjectFiles(ctx, event.UUID)
+	if err != nil {
+		return err
+	}
+	remaining := 0
+	for _, pkg := range packages {
+		if slices.ContainsFunc(files, func(f southbound.ProjectFile) bool { return f.Name == pkg.Name && f.Version == pkg.Version }) {
+			remaining++
+		}
+	}
+	if remaining > 0 {
+		return fmt.Errorf("%w: %d extension packages of project %s remain after deleting them", southbound.ErrTransient, remaining, event.Name)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package plugins
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+)
+
+// packageKey is the key of an extension package in the hashes recorded in the inventory.
+func packageKey(name string, version string) string {
+	return name + ":" + version
+}
+
+// packageHash returns a digest of the files of a deployment package. Only the base names of the files are hashed,
+// as the package is loaded to a new directory every time.
+func packageHash(files []southbound.CatalogFile) string {
+	sorted := slices.Clone(files)
+	slices.SortFunc(sorted, func(a, b southbound.CatalogFile) int {
+		return strings.Compare(path.Base(a.Name), path.Base(b.Name))
+	})
+	digest := sha256.New()
+	for _, f := range sorted {
+		_, _ = fmt.Fprintf(digest, "file %s %d\n", path.Base(f.Name), len(f.Artifact))
+		_, _ = digest.Write(f.Artifact)
+	}
+	return fmt.Sprintf("%x", digest.Sum(nil))
+}
+
+// deploymentHash returns a digest of the definition of a manifest deployment for the project of the event: its
+// package, profile and target cluster label sets.
+func deploymentHash(dl ManifestDeployment, event Event) string {
+	digest := sha256.New()
+	_, _ = fmt.Fprintf(digest, "package %s %s\n", dl.DpName, dl.DpVersion)
+	_, _ = fmt.Fprintf(digest, "profile %s\n", dl.DpProfileName)
+	for _, labels := range deploymentLabelSets(dl, event) {
+		_, _ = fmt.Fprint(digest, "targets")
+		for _, key := range slices.Sorted(maps.Keys(labels)) {
+			_, _ = fmt.Fprintf(digest, " %s=%s", key, labels[key])
+		}
+		_, _ = fmt.Fprintln(digest)
+	}
+	return fmt.Sprintf("%x", digest.Sum(nil))
+}
+
+// deploymentHashes returns the digests of the present deployments of the manifest for the project of the event, by
+// display name.
+func deploymentHashes(manifest *Manifest, event Event) map[string]string {
+	hashes := map[string]string{}
+	for _, dl := range manifest.Lpke.DeploymentList {
+		if !strings.EqualFold(dl.DesiredState, DesiredStateAbsent) {
+			hashes[dl.DisplayName] = deploymentHash(dl, event)
+		}
+	}
+	return hashes
+}
+
+// recordedDeploymentHashes returns the digests recorded in an inventory for its deployments, by display name.
+func recordedDeploymentHashes(inventory *southbound.Inventory) map[string]string {
+	hashes := map[string]string{}
+	if inventory == nil {
+		return hashes
+	}
+	for _, deployment := range inventory.Deployments {
+		if deployment.Hash != "" {
+			hashes[deployment.DisplayName] = deployment.Hash
+		}
+	}
+	return hashes
+}
+
+// keptPackageHashes returns the digests of the kept extension packages: the digest of the packages loaded for the
+// event, or else the digest recorded for them.
+func keptPackageHashes(kept []southbound.InventoryPackage, loaded map[string]string, recorded map[string]string) map[string]string {
+	hashes := map[string]string{}
+	for _, pkg := range kept {
+		key := packageKey(pkg.Name, pkg.Version)
+		if hash, ok := loaded[key]; ok {
+			hashes[key] = hash
+		} else if hash, ok := recorded[key]; ok {
+			hashes[key] = hash
+		}
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+	return hashes
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// OrgExtensions are the changes made to the extensions manifest for every project of an organization.
+type OrgExtensions struct {
+	// names of the deployment packages that are not installed, together with their deployments. Deployments that
+	// already exist are left in place.
+	Remove []string `yaml:"remove"`
+	// deployment packages installed in addition to the manifest, replacing a manifest package with the same name
+	DeploymentPackages []ManifestDeploymentPackage `yaml:"deploymentPackages"`
+	// deployments created in addition to the manifest, replacing a manifest deployment of the same package and
+	// deployment profile
+	DeploymentList []ManifestDeployment `yaml:"deploymentList"`
+}
+
+// loadOrgExtensions reads the extensions of each organization from the configured file, keyed by organization
+// name. The file is checked up front so that errors are reported at startup.
+func loadOrgExtensions(configuration config.Configuration) (map[string]OrgExtensions, error) {
+	if configuration.OrgExtensionsPath == "" {
+		return nil, nil
+	}
+	log.Infof("Loading organization extensions %s", configuration.OrgExtensionsPath)
+	orgsYAML, err := os.ReadFile(configuration.OrgExtensionsPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read organization extensions: %w", err)
+	}
+
+	orgs := map[string]OrgExtensions{}
+	if err := yaml.UnmarshalStrict(orgsYAML, &orgs); err != nil {
+		return nil, fmt.Errorf("invalid organization extensions: %w", err)
+	}
+	for org, extensions := range orgs {
+		for _, dp := range extensions.DeploymentPackages {
+			if dp.Dpkg == "" || dp.Version == "" {
+				return nil, fmt.Errorf("invalid extensions of organization %s: dpkg and version are required", org)
+			}
+			if !validDesiredState(dp.DesiredState) {
+				return nil, fmt.Errorf("invalid extensions of organization %s: deployment package %s has invalid desiredState %s", org, dp.Dpkg, dp.DesiredState)
+			}
+		}
+		for _, dl := range extensions.DeploymentList {
+			if dl.DpName == "" || dl.DpVersion == "" || dl.DpProfileName == "" {
+				return nil, fmt.Errorf("invalid extensions of organization %s: dpName, dpVersion and dpProfileName are required", org)
+			}
+			if !validDesiredState(dl.DesiredState) {
+				return nil, fmt.Errorf("invalid extensions of organization %s: deployment %s has invalid desiredState %s", org, dl.DpName, dl.DesiredState)
+			}
+			if err := validateTargetClusterSets(dl); err != nil {
+				return nil, fmt.Errorf("invalid extensions of organization %s: deployment %s: %w", org, dl.DpName, err)
+			}
+		}
+	}
+	return orgs, nil
+}
+
+// apply returns a copy of the manifest with the packages and deployments of the organization added and the removed
+// packages left out. The manifest itself is not modified.
+func (e OrgExtensions) apply(manifest *Manifest) *Manifest {
+	merged := &Manifest{Metadata: manifest.Metadata}
+	removed := make(map[string]bool, len(e.Remove))
+	for _, name := range e.Remove {
+		removed[name] = true
+	}
+
+	added := make(map[string]bool, len(e.DeploymentPackages))
+	for _, dp := range e.DeploymentPackages {
+		added[path.Base(dp.Dpkg)] = true
+	}
+	for _, dp := range manifest.Lpke.DeploymentPackages {
+		if name := path.Base(dp.Dpkg); !removed[name] && !added[name] {
+			merged.Lpke.DeploymentPackages = append(merged.Lpke.DeploymentPackages, dp)
+		}
+	}
+	merged.Lpke.DeploymentPackages = append(merged.Lpke.DeploymentPackages, e.DeploymentPackages...)
+
+	addedDeployments := make(map[string]bool, len(e.DeploymentList))
+	for _, dl := range e.DeploymentList {
+		addedDeployments[dl.DpName+"/"+dl.DpProfileName] = true
+	}
+	for _, dl := range manifest.Lpke.DeploymentList {
+		if !removed[dl.DpName] && !addedDeployments[dl.DpName+"/"+dl.DpProfileName] {
+			merged.Lpke.DeploymentList = append(merged.Lpke.DeploymentList, dl)
+		}
+	}
+	merged.Lpke.DeploymentList = append(merged.Lpke.DeploymentList, e.DeploymentList...)
+	return merged
+}
+
+// LoadManifestForOrganization reads the extensions manifest like LoadManifest, with the extensions configured for
+// the organization applied.
+func LoadManifestForOrganization(ctx context.Context, configuration config.Configuration, organization string) (*Manifest, error) {
+	orgExtensions, err := loadOrgExtensions(configuration)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := LoadManifest(ctx, configuration)
+	if err != nil {
+		return nil, err
+	}
+	return orgExtensions[organization].apply(manifest), nil
+}
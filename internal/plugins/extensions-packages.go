@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package plugins
+
+import (
+	"context"
+	"errors"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+)
+
+// stalePackages returns the extension packages to remove from the catalog of a project: the recorded packages
+// that the manifest no longer lists at the same version, and the packages the manifest marks as absent. Packages
+// that the manifest lists but the provisioning profile does not allow are not stale, they are left in place.
+func stalePackages(manifest *Manifest, recorded []southbound.InventoryPackage) []southbound.InventoryPackage {
+	listed := []southbound.InventoryPackage{}
+	stale := []southbound.InventoryPackage{}
+	for _, dp := range manifest.Lpke.DeploymentPackages {
+		pkg := southbound.InventoryPackage{Name: path.Base(dp.Dpkg), Version: dp.Version}
+		if strings.EqualFold(dp.DesiredState, DesiredStateAbsent) {
+			stale = append(stale, pkg)
+		} else {
+			listed = append(listed, pkg)
+		}
+	}
+	for _, pkg := range recorded {
+		if !slices.Contains(listed, pkg) && !slices.Contains(stale, pkg) {
+			stale = append(stale, pkg)
+		}
+	}
+	return stale
+}
+
+// recordedPackages returns the extension packages recorded in the inventory of the project, or nil if the project
+// has no inventory.
+func (p *ExtensionsProvisionerPlugin) recordedPackages(ctx context.Context, event Event) []southbound.InventoryPackage {
+	if inventory := p.recordedInventory(ctx, event); inventory != nil {
+		return inventory.ExtensionPackages
+	}
+	return nil
+}
+
+// recordedInventory returns the recorded inventory of the project, or nil if the project has none or it cannot be
+// loaded.
+func (p *ExtensionsProvisionerPlugin) recordedInventory(ctx context.Context, event Event) *southbound.Inventory {
+	if p.configuration.PodNamespace == "" {
+		return nil
+	}
+	store, err := InventoryStoreFactory(p.configuration)
+	if err != nil {
+		log.Warnf("Unable to load the inventory of project %s: %v", event.Name, err)
+		return nil
+	}
+	inventory, err := store.Load(ctx, event.UUID)
+	if err != nil {
+		log.Warnf("Unable to load the inventory of project %s: %v", event.Name, err)
+		return nil
+	}
+	return inventory
+}
+
+// appliedPackages returns the deployment packages of the manifest that are loaded for the project: those that are
+// not absent and are part of its provisioning profile.
+func appliedPackages(manifest *Manifest, event Event) []ManifestDeploymentPackage {
+	packages := make([]ManifestDeploymentPackage, 0, len(manifest.Lpke.DeploymentPackages))
+	for _, dp := range manifest.Lpke.DeploymentPackages {
+		if strings.EqualFold(dp.DesiredState, DesiredStateAbsent) {
+			// Absent packages are removed by reconcilePackages, once the deployments are deleted
+			log.Infof("Skipping deployment package %s version %s as desiredState is %s", dp.Dpkg, dp.Version, dp.DesiredState)
+			continue
+		}
+		if !event.Profile.AllowsDeploymentPackage(path.Base(dp.Dpkg)) {
+			log.Infof("Skipping deployment package %s version %s as it is not part of profile %s", dp.Dpkg, dp.Version, event.Profile.Name)
+			continue
+		}
+		packages = append(packages, dp)
+	}
+	return packages
+}
+
+// reconcilePackages removes the stale extension packages from the catalog of the project, so that it holds the
+// packages of the manifest rather than every package ever uploaded, and records the packages that remain. Packages
+// still used by an ADM deployment are kept until the deployment is gone. A package that cannot be deleted is
+// reported as a warning and stays recorded, so that its deletion is tried again the next time the project is
+// provisioned. Ensure events only record the packages, they remove none.
+func (p *ExtensionsProvisionerPlugin) reconcilePackages(ctx context.Context, cat Catalog, ad AppDeployment, event Event,
+	manifest *Manifest, uploaded []southbound.InventoryPackage, pluginData *PluginData) error {
+	recorded := p.recordedPackages(ctx, event)
+	kept := slices.Clone(uploaded)
+	for _, pkg := range recorded {
+		if !slices.Contains(kept, pkg) {
+			kept = append(kept, pkg)
+		}
+	}
+
+	stale := stalePackages(manifest, recorded)
+	if event.EventType == "ensure" && len(stale) > 0 {
+		log.Infof("Leaving %d stale deployment packages of project %s in place for the ensure event", len(stale), event.Name)
+		stale = nil
+	}
+	if len(stale) > 0 {
+		files, err := cat.ListProjectFiles(ctx, event.UUID)
+		if err != nil {
+			return err
+		}
+		inUse := map[southbound.InventoryPackage]bool{}
+		if ad != nil {
+			deployments, err := ad.ListDeployments(ctx, event.UUID, southbound.DeploymentFilter{})
+			if err != nil {
+				return err
+			}
+			for _, deployment := range deployments {
+				inUse[southbound.InventoryPackage{Name: deployment.AppName, Version: deployment.AppVersion}] = true
+			}
+		}
+
+		for _, pkg := range stale {
+			i := slices.IndexFunc(files, func(f southbound.ProjectFile) bool { return f.Name == pkg.Name && f.Version == pkg.Version })
+			if i < 0 {
+				kept = slices.DeleteFunc(kept, func(k southbound.InventoryPackage) bool { return k == pkg })
+				continue
+			}
+			if inUse[pkg] {
+				log.Infof("Keeping deployment package %s:%s of project %s, it is still deployed", pkg.Name, pkg.Version, event.Name)
+				continue
+			}
+			event.ReportProgress("Removing deployment package %s:%s", pkg.Name, pkg.Version)
+			if err := cat.DeleteProjectFile(ctx, event.UUID, files[i]); err != nil {
+				event.ReportWarning("Unable to remove deployment package %s:%s: %v", pkg.Name, pkg.Version, err)
+				continue
+			}
+			kept = slices.DeleteFunc(kept, func(k southbound.InventoryPackage) bool { return k == pkg })
+		}
+	}
+
+	recordInventory(pluginData, func(inventory *southbound.Inventory) {
+		inventory.ExtensionPackages = kept
+	})
+	return nil
+}
+
+// extensionUploadError names the extension package of a file that the catalog rejected in the user message of the
+// upload error.
+func extensionUploadError(err error, filePackages map[string]southbound.InventoryPackage) error {
+	var fileErr *southbound.CatalogFileError
+	if !errors.As(err, &fileErr) {
+		return err
+	}
+	pkg, ok := filePackages[fileErr.File]
+	if !ok {
+		return err
+	}
+	if errors.Is(err, southbound.ErrConflict) {
+		return southbound.WithUserMessage(err, "Extension package %s version %s conflicts with an entity already in the catalog of the project, file %s: %s",
+			pkg.Name, pkg.Version, fileErr.File, fileErr.Reason)
+	}
+	return southbound.WithUserMessage(err, "Extension package %s version %s is invalid, file %s: %s", pkg.Name, pkg.Version, fileErr.File, fileErr.Reason)
+}
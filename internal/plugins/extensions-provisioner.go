@@ -7,13 +7,20 @@ package plugins
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	nexushook "github.com/open-edge-platform/app-orch-tenant-controller/internal/nexus"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/retry"
 	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
 	yaml "gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
@@ -21,34 +28,89 @@ const (
 	DesiredStateAbsent  = "absent"
 )
 
+const (
+	// DeploymentOrganizationLabel is set to the organization in the labels of the ADM deployments of a project, if
+	// tenant labels are enabled and the organization is a valid label value
+	DeploymentOrganizationLabel = "app-orch-tenant-controller/organization"
+	// DeploymentProjectUUIDLabel is set to the project UUID in the labels of the ADM deployments of a project, if
+	// tenant labels are enabled
+	DeploymentProjectUUIDLabel = "app-orch-tenant-controller/project-uuid"
+)
+
+// TenantDeploymentLabels returns the deployment labels of a project merged with its organization and project UUID
+// labels, which take precedence so that a project cannot claim the resources of another tenant.
+func TenantDeploymentLabels(labels map[string]string, organization string, uuid string) map[string]string {
+	tenantLabels := maps.Clone(labels)
+	if tenantLabels == nil {
+		tenantLabels = map[string]string{}
+	}
+	tenantLabels[DeploymentProjectUUIDLabel] = uuid
+	if len(validation.IsValidLabelValue(organization)) == 0 {
+		tenantLabels[DeploymentOrganizationLabel] = organization
+	} else {
+		log.Warnf("Organization %s is not a valid label value, it is not added to the labels of its deployments", organization)
+		delete(tenantLabels, DeploymentOrganizationLabel)
+	}
+	return tenantLabels
+}
+
+// withoutTenantLabels returns the event with the organization and project UUID removed from its deployment labels.
+func withoutTenantLabels(event Event) Event {
+	event.DeploymentLabels = maps.Clone(event.DeploymentLabels)
+	delete(event.DeploymentLabels, DeploymentOrganizationLabel)
+	delete(event.DeploymentLabels, DeploymentProjectUUIDLabel)
+	return event
+}
+
+// hasTenantLabels reports whether the deployment labels of the event include the project UUID label.
+func hasTenantLabels(event Event) bool {
+	_, ok := event.DeploymentLabels[DeploymentProjectUUIDLabel]
+	return ok
+}
+
+// TargetClusterLabel is a cluster label that a deployment targets
+type TargetClusterLabel struct {
+	Key string `yaml:"key"`
+	Val string `yaml:"val"`
+}
+
 type Manifest struct {
 	Metadata struct {
 		SchemaVersion string `yaml:"schemaVersion"`
 		Release       string `yaml:"release"`
 	} `yaml:"metadata"`
 	Lpke struct {
-		DeploymentPackages []struct {
-			Dpkg         string `yaml:"dpkg"`
-			Version      string `yaml:"version"`
-			DesiredState string `yaml:"desiredState"` // if unspecified, defaults to "present"
-		} `yaml:"deploymentPackages"`
-		DeploymentList []struct {
-			DpName               string `yaml:"dpName"`
-			DisplayName          string `yaml:"displayName"`
-			DpProfileName        string `yaml:"dpProfileName"`
-			DpVersion            string `yaml:"dpVersion"`
-			AllAppTargetClusters []struct {
-				Key string `yaml:"key"`
-				Val string `yaml:"val"`
-			} `yaml:"allAppTargetClusters"`
-			DesiredState string `yaml:"desiredState"` // if unspecified, defaults to "present"
-		} `yaml:"deploymentList"`
+		DeploymentPackages []ManifestDeploymentPackage `yaml:"deploymentPackages"`
+		DeploymentList     []ManifestDeployment        `yaml:"deploymentList"`
 	} `yaml:"lpke"`
 }
 
+// ManifestDeploymentPackage is a deployment package listed in the extensions manifest.
+type ManifestDeploymentPackage struct {
+	Dpkg         string `yaml:"dpkg"`
+	Version      string `yaml:"version"`
+	DesiredState string `yaml:"desiredState"` // if unspecified, defaults to "present"
+}
+
+// ManifestDeployment is an ADM deployment listed in the extensions manifest.
+type ManifestDeployment struct {
+	DpName               string               `yaml:"dpName"`
+	DisplayName          string               `yaml:"displayName"`
+	DpProfileName        string               `yaml:"dpProfileName"`
+	DpVersion            string               `yaml:"dpVersion"`
+	AllAppTargetClusters []TargetClusterLabel `yaml:"allAppTargetClusters"`
+	// further cluster label sets. The deployment targets the clusters that have all the labels of
+	// allAppTargetClusters or of any of these sets
+	TargetClusterSets [][]TargetClusterLabel `yaml:"targetClusterSets"`
+	// cluster label sets that replace allAppTargetClusters and targetClusterSets for the projects provisioned
+	// with a provisioning profile, keyed by profile name
+	ProfileTargetClusters map[string][][]TargetClusterLabel `yaml:"profileTargetClusters"`
+	DesiredState          string                            `yaml:"desiredState"` // if unspecified, defaults to "present"
+}
+
 type AppDeployment interface {
-	ListDeploymentNames(ctx context.Context, projectID string) (map[string]string, error)
-	CreateDeployment(ctx context.Context, dpName string, displayName string, version string, profileName string, projectID string, labels map[string]string) error
+	ListDeployments(ctx context.Context, projectID string, filter southbound.DeploymentFilter) (map[string]southbound.DeploymentInfo, error)
+	CreateDeployment(ctx context.Context, dpName string, displayName string, version string, profileName string, projectID string, targets southbound.DeploymentTargets) error
 	DeleteDeployment(ctx context.Context, dpName string, displayName string, version string, profileName string, projectID string, missingOkay bool) error
 }
 
@@ -59,15 +121,21 @@ func NewAppDeployment(configuration config.Configuration) (AppDeployment, error)
 var AppDeploymentFactory = NewAppDeployment
 
 type Oras interface {
-	Load(string, string) error
+	Load(context.Context, string, string) error
 	Dest() string
 	Close()
 }
 
 var OrasFactory = NewOras
 
-func NewOras(registry string) (Oras, error) {
-	oras, err := southbound.NewOras(registry)
+// NewOras returns a client pulling from the release service registry of the configuration, with its credential and
+// TLS settings.
+func NewOras(configuration config.Configuration) (Oras, error) {
+	options, err := southbound.ReleaseServiceOrasOptions(configuration)
+	if err != nil {
+		return nil, err
+	}
+	oras, err := southbound.NewOrasWithOptions(configuration.ReleaseServiceBase, options)
 	if err != nil {
 		return nil, err
 	}
@@ -76,11 +144,17 @@ func NewOras(registry string) (Oras, error) {
 
 type ExtensionsProvisionerPlugin struct {
 	configuration config.Configuration
+	orgExtensions map[string]OrgExtensions
 }
 
 func NewExtensionsProvisionerPlugin(configuration config.Configuration) (*ExtensionsProvisionerPlugin, error) {
+	orgExtensions, err := loadOrgExtensions(configuration)
+	if err != nil {
+		return nil, err
+	}
 	plugin := &ExtensionsProvisionerPlugin{
 		configuration: configuration,
+		orgExtensions: orgExtensions,
 	}
 	return plugin, nil
 }
@@ -97,172 +171,438 @@ func (p *ExtensionsProvisionerPlugin) waitForADM(ctx context.Context) error {
 		return fmt.Errorf("failed to create ADM client: %w", err)
 	}
 
-	maxRetries := 12 // Total wait time: ~5 minutes
-	retryDelay := 5 * time.Second
-
-	for attempt := 1; attempt <= maxRetries; attempt++ {
+	err = retry.Do(ctx, "ADM ping", serviceBackoff, southbound.IsRetryable, func(ctx context.Context) error {
 		lctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-		_, err := ad.ListDeploymentNames(lctx, "")
-		cancel()
-
-		if err == nil || strings.Contains(err.Error(), "Unauthenticated") {
-			log.Info("App deployment manager ready")
+		defer cancel()
+		_, err := ad.ListDeployments(lctx, "", southbound.DeploymentFilter{})
+		if err != nil && strings.Contains(err.Error(), "Unauthenticated") {
 			return nil
 		}
-
-		if attempt == maxRetries {
-			return fmt.Errorf("ADM not available after %d attempts: %w", maxRetries, err)
-		}
-
-		log.Infof("ADM ping failed (attempt %d/%d): %v. Retrying in %v...", attempt, maxRetries, err, retryDelay)
-		time.Sleep(retryDelay)
-
-		retryDelay = retryDelay * 2
-		if retryDelay > 60*time.Second {
-			retryDelay = 60 * time.Second
-		}
+		return err
+	})
+	if err != nil {
+		return retryFailed("ADM not available", err)
 	}
-
-	return fmt.Errorf("ADM not available after maximum retries")
+	log.Info("App deployment manager ready")
+	return nil
 }
 
-func (p *ExtensionsProvisionerPlugin) Initialize(ctx context.Context, _ PluginData) error {
+func (p *ExtensionsProvisionerPlugin) Initialize(ctx context.Context, _ *PluginData) error {
+	if p.configuration.AdmServer != "" {
+		if err := waitForKeycloak(ctx, p.configuration); err != nil {
+			return fmt.Errorf("extensions initialization failed during keycloak check: %w", err)
+		}
+	}
 	if err := p.waitForADM(ctx); err != nil {
 		return fmt.Errorf("extensions initialization failed during ADM check: %w", err)
 	}
 	return nil
 }
 
-func (p *ExtensionsProvisionerPlugin) CreateEvent(ctx context.Context, event Event, _ PluginData) error {
-	var err error
-
+// LoadManifest reads the extensions manifest, either the local manifest from the configuration or the manifest
+// published in the Release Service.
+func LoadManifest(ctx context.Context, configuration config.Configuration) (*Manifest, error) {
 	var yamlBytes []byte
 
-	if p.configuration.UseLocalManifest != "" {
+	if configuration.UseLocalManifest != "" {
 		log.Info("Using local manifest")
-		yamlBytes = []byte(p.configuration.UseLocalManifest)
+		yamlBytes = []byte(configuration.UseLocalManifest)
 	} else {
-		log.Infof("Using remote manifest directory %s%s:%s", p.configuration.ReleaseServiceBase, p.configuration.ManifestPath, p.configuration.ManifestTag)
+		log.Infof("Using remote manifest directory %s%s:%s", configuration.ReleaseServiceBase, configuration.ManifestPath, configuration.ManifestTag)
 
-		manifestOras, err := OrasFactory(p.configuration.ReleaseServiceBase)
+		manifestOras, err := OrasFactory(configuration)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		defer manifestOras.Close()
 
-		err = manifestOras.Load(p.configuration.ManifestPath, p.configuration.ManifestTag)
+		err = manifestOras.Load(ctx, configuration.ManifestPath, configuration.ManifestTag)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		manifestDir := manifestOras.Dest()
 
 		entries, err := os.ReadDir(manifestDir)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		yamlBytes, err = os.ReadFile(manifestOras.Dest() + "/" + entries[0].Name())
+		yamlBytes, err = os.ReadFile(filepath.Join(manifestDir, entries[0].Name()))
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	cat, err := CatalogFactory(p.configuration)
-	if err != nil {
-		return err
-	}
+	return ParseManifest(yamlBytes)
+}
 
-	manifest := Manifest{}
+// ParseManifest decodes an extensions manifest.
+func ParseManifest(yamlBytes []byte) (*Manifest, error) {
+	manifest := &Manifest{}
 
 	decoder := yaml.NewDecoder(strings.NewReader(string(yamlBytes)))
-	err = decoder.Decode(&manifest)
+	err := decoder.Decode(manifest)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return manifest, nil
+}
 
-	log.Infof("Manifest release %s", manifest.Metadata.Release)
-	pkgOras, err := OrasFactory(p.configuration.ReleaseServiceBase)
-	if err != nil {
-		return err
+func validDesiredState(desiredState string) bool {
+	return desiredState == "" || strings.EqualFold(desiredState, DesiredStatePresent) || strings.EqualFold(desiredState, DesiredStateAbsent)
+}
+
+// validateTargetClusterSets checks that none of the further target cluster sets of a deployment is empty, as an
+// empty set would target every cluster.
+func validateTargetClusterSets(dl ManifestDeployment) error {
+	for i, set := range dl.TargetClusterSets {
+		if len(set) == 0 {
+			return fmt.Errorf("target cluster set %d is empty", i)
+		}
 	}
-	defer pkgOras.Close()
-	for _, dp := range manifest.Lpke.DeploymentPackages {
-		if strings.EqualFold(dp.DesiredState, DesiredStateAbsent) {
-			// TODO: implement deletion of deployment packages. We need to do this _after_ the deployments are deleted.
-			log.Infof("Skipping deployment package %s version %s as desiredState is %s", dp.Dpkg, dp.Version, dp.DesiredState)
+	for profile, sets := range dl.ProfileTargetClusters {
+		for i, set := range sets {
+			if len(set) == 0 {
+				return fmt.Errorf("target cluster set %d of profile %s is empty", i, profile)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateManifest checks that a manifest is complete and consistent, returning every problem found.
+func ValidateManifest(manifest *Manifest) []error {
+	var errs []error
+	if manifest.Metadata.SchemaVersion == "" {
+		errs = append(errs, fmt.Errorf("metadata.schemaVersion is required"))
+	}
+
+	packageVersions := make(map[string]string)
+	for i, dp := range manifest.Lpke.DeploymentPackages {
+		if dp.Dpkg == "" || dp.Version == "" {
+			errs = append(errs, fmt.Errorf("deployment package %d: dpkg and version are required", i))
 			continue
 		}
+		if !validDesiredState(dp.DesiredState) {
+			errs = append(errs, fmt.Errorf("deployment package %s: invalid desiredState %s", dp.Dpkg, dp.DesiredState))
+		}
+		name := path.Base(dp.Dpkg)
+		if _, ok := packageVersions[name]; ok {
+			errs = append(errs, fmt.Errorf("deployment package %s: listed more than once", name))
+		}
+		if !strings.EqualFold(dp.DesiredState, DesiredStateAbsent) {
+			packageVersions[name] = dp.Version
+		}
+	}
 
-		err = pkgOras.Load(`/`+dp.Dpkg, dp.Version)
-		if err != nil {
+	deployments := make(map[string]bool)
+	for i, dl := range manifest.Lpke.DeploymentList {
+		if dl.DpName == "" || dl.DpVersion == "" {
+			errs = append(errs, fmt.Errorf("deployment %d: dpName and dpVersion are required", i))
+			continue
+		}
+		if !validDesiredState(dl.DesiredState) {
+			errs = append(errs, fmt.Errorf("deployment %s: invalid desiredState %s", dl.DpName, dl.DesiredState))
+		}
+		key := dl.DpName + "/" + dl.DpProfileName + "/" + dl.DisplayName
+		if deployments[key] {
+			errs = append(errs, fmt.Errorf("deployment %s with profile %s: listed more than once", dl.DpName, dl.DpProfileName))
+		}
+		deployments[key] = true
+		if strings.EqualFold(dl.DesiredState, DesiredStateAbsent) {
+			continue
+		}
+		if err := validateTargetClusterSets(dl); err != nil {
+			errs = append(errs, fmt.Errorf("deployment %s: %w", dl.DpName, err))
+		}
+		version, ok := packageVersions[dl.DpName]
+		if !ok {
+			errs = append(errs, fmt.Errorf("deployment %s: deployment package is not in the manifest", dl.DpName))
+		} else if version != dl.DpVersion {
+			errs = append(errs, fmt.Errorf("deployment %s: version %s does not match deployment package version %s", dl.DpName, dl.DpVersion, version))
+		}
+	}
+	return errs
+}
+
+func (p *ExtensionsProvisionerPlugin) CreateEvent(ctx context.Context, event Event, pluginData *PluginData) error {
+	event.ReportProgress("Loading extensions manifest")
+	manifest, err := LoadManifest(ctx, p.configuration)
+	if err != nil {
+		return err
+	}
+	manifest = p.orgExtensions[event.Organization].apply(manifest)
+	if p.configuration.AdmServer != "" {
+		if err := checkQuota(QuotaExtensionDeployments, "MAX_EXTENSION_DEPLOYMENTS", countDeployments(manifest, event), p.configuration.MaxExtensionDeployments); err != nil {
 			return err
 		}
+	}
 
-		entries, err := os.ReadDir(pkgOras.Dest())
-		if err != nil {
+	cat, err := CatalogFactory(p.configuration)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Manifest release %s", manifest.Metadata.Release)
+	pkgOras, err := OrasFactory(p.configuration)
+	if err != nil {
+		return err
+	}
+	defer pkgOras.Close()
+	// Packages and deployments recorded with the same hash are not uploaded or checked again
+	recorded := p.recordedInventory(ctx, event)
+	recordedHashes := map[string]string{}
+	if recorded != nil && recorded.ExtensionHashes != nil {
+		recordedHashes = recorded.ExtensionHashes
+	}
+	// The packages are uploaded together, so that a package that fails to load leaves none of them in the catalog
+	upload := &southbound.CatalogUpload{}
+	uploaded := []southbound.InventoryPackage{}
+	// package of each uploaded file, by base name
+	filePackages := map[string]southbound.InventoryPackage{}
+	loadedHashes := map[string]string{}
+	var catalogFiles []southbound.ProjectFile
+	catalogListed := false
+	packages := appliedPackages(manifest, event)
+	for i, dp := range packages {
+		event.ReportProgress("Loading extensions %d/%d", i+1, len(packages))
+		pkgUpload := &southbound.CatalogUpload{}
+		if err := addDeploymentPackage(ctx, pkgOras, pkgUpload, dp.Dpkg, dp.Version); err != nil {
 			return err
 		}
-		for i, entry := range entries {
-			var artifact []byte
-			fileName := pkgOras.Dest() + "/" + entry.Name()
-			artifact, err = os.ReadFile(fileName) //nolint:gosec // File path is controlled
-			if err != nil {
-				return err
+		name := path.Base(dp.Dpkg)
+		key := packageKey(name, dp.Version)
+		loadedHashes[key] = packageHash(pkgUpload.Files())
+		uploaded = append(uploaded, southbound.InventoryPackage{Name: name, Version: dp.Version})
+		if recordedHashes[key] == loadedHashes[key] {
+			// The package is only skipped if it is still in the catalog
+			if !catalogListed {
+				catalogFiles, err = cat.ListProjectFiles(ctx, event.UUID)
+				if err != nil {
+					log.Warnf("Unable to list the deployment packages of project %s, uploading all of them: %v", event.Name, err)
+				}
+				catalogListed = true
 			}
-
-			lastUpload := i == len(entries)-1
-			err = cat.UploadYAMLFile(ctx, event.UUID, fileName, artifact, lastUpload)
-			if err != nil {
-				return err
+			if slices.ContainsFunc(catalogFiles, func(f southbound.ProjectFile) bool { return f.Name == name && f.Version == dp.Version }) {
+				log.Infof("Deployment package %s version %s is unchanged, skipping upload", dp.Dpkg, dp.Version)
+				continue
 			}
 		}
+		for _, f := range pkgUpload.Files() {
+			upload.Add(f.Name, f.Artifact)
+			filePackages[path.Base(f.Name)] = southbound.InventoryPackage{Name: name, Version: dp.Version}
+		}
+	}
+	event.ReportProgress("Uploading extensions")
+	if err := cat.CommitUpload(ctx, event.UUID, upload); err != nil {
+		return extensionUploadError(err, filePackages)
 	}
 
+	var ad AppDeployment
 	if p.configuration.AdmServer == "" {
 		log.Info("No admServer is set, skipping deployments")
 	} else {
 		uuid := event.UUID
-		ad, _ := AppDeploymentFactory(p.configuration)
+		ad, _ = AppDeploymentFactory(p.configuration)
 
-		existingDisplayNames, err := ad.ListDeploymentNames(ctx, uuid)
+		event.ReportProgress("Creating ADM deployments")
+		existingDeployments, err := ad.ListDeployments(ctx, uuid, southbound.DeploymentFilter{})
 		if err != nil {
 			log.Info("Not able to list deployments, skipping deployments")
 			return err
 		}
 
+		hashes := deploymentHashes(manifest, event)
+		recordedDeployments := recordedDeploymentHashes(recorded)
+		changed := false
 		for _, dl := range manifest.Lpke.DeploymentList {
 			log.Infof("displayName: %s", dl.DisplayName)
-			if strings.EqualFold(dl.DesiredState, DesiredStateAbsent) {
+			if strings.EqualFold(dl.DesiredState, DesiredStateAbsent) && event.EventType == "ensure" {
+				log.Infof("Deployment with displayName %s is absent from the manifest, ensure events do not delete it", dl.DisplayName)
+			} else if strings.EqualFold(dl.DesiredState, DesiredStateAbsent) {
 				err = ad.DeleteDeployment(ctx, dl.DpName, dl.DisplayName, dl.DpVersion, dl.DpProfileName, uuid, true)
 				if err != nil {
 					return err
 				}
+				_, exists := existingDeployments[dl.DisplayName]
+				changed = changed || exists
 			} else {
-				if _, exists := existingDisplayNames[dl.DisplayName]; exists {
-					log.Infof("Deployment with displayName %s already exists, skipping creation", dl.DisplayName)
+				if !event.Profile.AllowsDeploymentPackage(dl.DpName) || !event.Profile.AllowsDeploymentProfile(dl.DpProfileName) {
+					log.Infof("Deployment %s with profile %s is not part of profile %s, skipping creation", dl.DpName, dl.DpProfileName, event.Profile.Name)
+					continue
+				}
+				if existing, exists := existingDeployments[dl.DisplayName]; exists {
+					if existing.AppName == dl.DpName && existing.AppVersion == dl.DpVersion && existing.ProfileName == dl.DpProfileName {
+						if hash, ok := recordedDeployments[dl.DisplayName]; ok && hash != hashes[dl.DisplayName] &&
+							hasTenantLabels(event) && hash == deploymentHash(dl, withoutTenantLabels(event)) {
+							// Created before tenant labels were enabled. ADM deployments cannot be retargeted, so the
+							// labels are only added if the deployment is deleted and created again
+							event.ReportWarning("Deployment with displayName %s was created without the organization and project labels, delete it to have it created with them",
+								dl.DisplayName)
+							hashes[dl.DisplayName] = hash
+						} else if hash, ok := recordedDeployments[dl.DisplayName]; ok && hash != hashes[dl.DisplayName] {
+							// ADM deployments cannot be retargeted, the deployment keeps the hash it was created with
+							event.ReportWarning("Deployment with displayName %s was created with other target clusters than the manifest lists, leaving it in place",
+								dl.DisplayName)
+							hashes[dl.DisplayName] = hash
+						} else {
+							log.Infof("Deployment with displayName %s already exists in state %s, skipping creation", dl.DisplayName, existing.State)
+						}
+					} else {
+						event.ReportWarning("Deployment with displayName %s exists as %s:%s profile %s instead of %s:%s profile %s, leaving it in place",
+							dl.DisplayName, existing.AppName, existing.AppVersion, existing.ProfileName, dl.DpName, dl.DpVersion, dl.DpProfileName)
+					}
 					continue
 				}
 
-				labels := map[string]string{}
-				for _, appTargetCluster := range dl.AllAppTargetClusters {
-					labels[appTargetCluster.Key] = appTargetCluster.Val
+				targets, err := deploymentTargets(ctx, cat, uuid, dl.DpName, dl.DpVersion, deploymentLabelSets(dl, event))
+				if err != nil {
+					return err
 				}
-				err = ad.CreateDeployment(ctx, dl.DpName, dl.DisplayName, dl.DpVersion, dl.DpProfileName, uuid, labels)
+				err = ad.CreateDeployment(ctx, dl.DpName, dl.DisplayName, dl.DpVersion, dl.DpProfileName, uuid, targets)
 				if err != nil {
 					return err
 				}
+				changed = true
 			}
 		}
+		// The deployments are only listed again if some were created or deleted
+		if changed {
+			if err := recordDeployments(ctx, ad, uuid, manifest, hashes, pluginData); err != nil {
+				return err
+			}
+		} else {
+			recordListedDeployments(existingDeployments, manifest, hashes, pluginData)
+		}
+	}
+
+	if err := p.reconcilePackages(ctx, cat, ad, event, manifest, uploaded, pluginData); err != nil {
+		return err
+	}
+	recordInventory(pluginData, func(inventory *southbound.Inventory) {
+		inventory.ExtensionHashes = keptPackageHashes(inventory.ExtensionPackages, loadedHashes, recordedHashes)
+	})
+	return nil
+}
+
+// addDeploymentPackage loads a deployment package from the Release Service and adds its files to the upload.
+func addDeploymentPackage(ctx context.Context, pkgOras Oras, upload *southbound.CatalogUpload, dpkg string, version string) error {
+	err := pkgOras.Load(ctx, `/`+dpkg, version)
+	if err != nil {
+		return err
 	}
 
+	entries, err := os.ReadDir(pkgOras.Dest())
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		fileName := filepath.Join(pkgOras.Dest(), entry.Name())
+		artifact, err := os.ReadFile(fileName) //nolint:gosec // File path is controlled
+		if err != nil {
+			return err
+		}
+		upload.Add(fileName, artifact)
+	}
 	return nil
 }
 
-func (p *ExtensionsProvisionerPlugin) DeleteEvent(_ context.Context, _ Event, _ PluginData) error {
+// recordDeployments adds the ADM deployments of the manifest extensions to the inventory of the event, with the
+// given definition hashes by display name. The deployments are listed again, as ADM assigns their IDs.
+func recordDeployments(ctx context.Context, ad AppDeployment, uuid string, manifest *Manifest, hashes map[string]string, pluginData *PluginData) error {
+	deployments, err := ad.ListDeployments(ctx, uuid, southbound.DeploymentFilter{})
+	if err != nil {
+		return err
+	}
+	recordListedDeployments(deployments, manifest, hashes, pluginData)
 	return nil
 }
 
+// recordListedDeployments adds the listed ADM deployments of the manifest extensions to the inventory of the event.
+// Deployments of another package, version or profile than the manifest lists are recorded without a hash.
+func recordListedDeployments(deployments map[string]southbound.DeploymentInfo, manifest *Manifest, hashes map[string]string, pluginData *PluginData) {
+	recorded := []southbound.InventoryDeployment{}
+	for _, dl := range manifest.Lpke.DeploymentList {
+		deployment, exists := deployments[dl.DisplayName]
+		if !exists || strings.EqualFold(dl.DesiredState, DesiredStateAbsent) {
+			continue
+		}
+		inventoryDeployment := southbound.InventoryDeployment{
+			ID:          deployment.ID,
+			DisplayName: deployment.DisplayName,
+			AppName:     deployment.AppName,
+			AppVersion:  deployment.AppVersion,
+			ProfileName: deployment.ProfileName,
+		}
+		if deployment.AppName == dl.DpName && deployment.AppVersion == dl.DpVersion && deployment.ProfileName == dl.DpProfileName {
+			inventoryDeployment.Hash = hashes[dl.DisplayName]
+		}
+		recorded = append(recorded, inventoryDeployment)
+	}
+	recordInventory(pluginData, func(inventory *southbound.Inventory) {
+		inventory.Deployments = recorded
+	})
+}
+
+// deploymentLabels merges the target cluster labels of a manifest deployment with the labels of the project.
+// Project labels take precedence, so that cluster targeting can be customized per project.
+func deploymentLabels(targetClusters []TargetClusterLabel, projectLabels map[string]string) map[string]string {
+	labels := map[string]string{}
+	for _, appTargetCluster := range targetClusters {
+		labels[appTargetCluster.Key] = appTargetCluster.Val
+	}
+	maps.Copy(labels, projectLabels)
+	return labels
+}
+
+// deploymentLabelSets returns the cluster label sets targeted by a manifest deployment, each merged with the labels
+// of the project. The label sets given for the provisioning profile of the event replace the others.
+func deploymentLabelSets(dl ManifestDeployment, event Event) []map[string]string {
+	sets := dl.TargetClusterSets
+	if len(dl.AllAppTargetClusters) > 0 || len(sets) == 0 {
+		sets = append([][]TargetClusterLabel{dl.AllAppTargetClusters}, sets...)
+	}
+	if event.Profile != nil && len(dl.ProfileTargetClusters[event.Profile.Name]) > 0 {
+		sets = dl.ProfileTargetClusters[event.Profile.Name]
+	}
+	labelSets := make([]map[string]string, 0, len(sets))
+	for _, set := range sets {
+		labelSets = append(labelSets, deploymentLabels(set, event.DeploymentLabels))
+	}
+	return labelSets
+}
+
+// deploymentTargets returns the targets of a deployment with the given label sets. The applications of the
+// deployment package are only looked up in the catalog when ADM needs them, for several label sets.
+func deploymentTargets(ctx context.Context, cat Catalog, uuid string, dpName string, version string, labelSets []map[string]string) (southbound.DeploymentTargets, error) {
+	targets := southbound.DeploymentTargets{LabelSets: labelSets}
+	if len(labelSets) <= 1 {
+		return targets, nil
+	}
+	apps, err := cat.DeploymentPackageApplications(ctx, uuid, dpName, version)
+	if err != nil {
+		return targets, fmt.Errorf("failed to list the applications of deployment package %s:%s: %w", dpName, version, err)
+	}
+	targets.Apps = apps
+	return targets, nil
+}
+
+// EnsureEvent uploads and deploys the extensions of the manifest that the project is missing. Unlike create events,
+// deployments and packages that the manifest marks absent or no longer lists are left in place.
+func (p *ExtensionsProvisionerPlugin) EnsureEvent(ctx context.Context, event Event, pluginData *PluginData) error {
+	return p.CreateEvent(ctx, event, pluginData)
+}
+
+// UpdateEvent uploads and deploys the extensions allowed by a newly selected provisioning profile. Extensions
+// that are no longer allowed by the new profile are left in place.
+func (p *ExtensionsProvisionerPlugin) UpdateEvent(ctx context.Context, event Event, pluginData *PluginData) error {
+	if !event.Changes.AnnotationChanged(nexushook.ProvisioningProfileAnnotationKey) {
+		return nil
+	}
+	return p.CreateEvent(ctx, event, pluginData)
+}
+
 func (p *ExtensionsProvisionerPlugin) Name() string {
 	return "Extensions Provisioner"
 }
@@ -5,27 +5,33 @@ package plugins
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/retry/retrytest"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
 // mockDynamicADM is a mock ADM that allows dynamic behavior
 type mockDynamicADM struct {
-	listDeploymentNamesFunc func(ctx context.Context, tenant string) (map[string]string, error)
+	listDeploymentsFunc func(ctx context.Context, tenant string) (map[string]southbound.DeploymentInfo, error)
 }
 
-func (m *mockDynamicADM) ListDeploymentNames(ctx context.Context, tenant string) (map[string]string, error) {
-	if m.listDeploymentNamesFunc != nil {
-		return m.listDeploymentNamesFunc(ctx, tenant)
+func (m *mockDynamicADM) ListDeployments(ctx context.Context, tenant string, _ southbound.DeploymentFilter) (map[string]southbound.DeploymentInfo, error) {
+	if m.listDeploymentsFunc != nil {
+		return m.listDeploymentsFunc(ctx, tenant)
 	}
-	return map[string]string{}, nil
+	return map[string]southbound.DeploymentInfo{}, nil
 }
 
-func (m *mockDynamicADM) CreateDeployment(_ context.Context, _ string, _ string, _ string, _ string, _ string, _ map[string]string) error {
+func (m *mockDynamicADM) CreateDeployment(_ context.Context, _ string, _ string, _ string, _ string, _ string, _ southbound.DeploymentTargets) error {
 	return nil
 }
 
@@ -44,12 +50,13 @@ func (s *PluginsTestSuite) TestExtensionsPluginCreate() {
 	mockDeployments = map[string]*mockDeployment{}
 
 	configuration := config.Configuration{
-		HarborServer:      "https://harbor.org",
-		KeycloakNamespace: "keycloak-ns",
-		KeycloakSecret:    "sekret",
-		AdmServer:         "http://admserver",
-		ManifestPath:      "/registry/edge-node/en/manifest",
-		ManifestTag:       "latest",
+		HarborServer: "https://harbor.org",
+		Secrets: config.K8sSecretsRef{
+			KeycloakAdmin: config.SecretRef{Namespace: "keycloak-ns", Name: "sekret", Key: config.DefaultKeycloakSecretKey},
+		},
+		AdmServer:    "http://admserver",
+		ManifestPath: "/registry/edge-node/en/manifest",
+		ManifestTag:  "latest",
 	}
 
 	plugin, err := NewExtensionsProvisionerPlugin(configuration)
@@ -61,7 +68,7 @@ func (s *PluginsTestSuite) TestExtensionsPluginCreate() {
 	err = Initialize(ctx)
 	assert.NoError(s.T(), err, "Initialize plugin")
 
-	err = Dispatch(ctx, Event{
+	_, err = Dispatch(ctx, Event{
 		EventType: "create",
 		UUID:      "foo",
 	}, nil)
@@ -69,11 +76,16 @@ func (s *PluginsTestSuite) TestExtensionsPluginCreate() {
 
 	s.Len(mockCatalog.uploadedFiles, 7)
 
+	// All packages are uploaded in a single session
+	lastUploads := 0
 	for fileName, file := range mockCatalog.uploadedFiles {
 		s.Equal(fileName, file.path)
-		s.True(file.lastUpload)
+		if file.lastUpload {
+			lastUploads++
+		}
 		s.Contains(file.artifact, `License-Identifier: Apache-2.0`)
 	}
+	s.Equal(1, lastUploads)
 
 	s.Len(mockDeployments, 3)
 	baselineKey := "base-extensions-0.2.0-baseline"
@@ -89,6 +101,508 @@ func (s *PluginsTestSuite) TestExtensionsPluginCreate() {
 	s.Equal("green", mockDeployments[privKey].labels["color"])
 }
 
+func (s *PluginsTestSuite) TestExtensionsPluginCreateGeneratedManifest() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	OrasFactory = NewTestOras
+	CatalogFactory = newTestCatalog
+	AppDeploymentFactory = newTestADM
+
+	mockCatalog.uploadedFiles = map[string]upload{}
+	mockDeployments = map[string]*mockDeployment{}
+
+	configuration := config.Configuration{
+		HarborServer: "https://harbor.org",
+		Secrets: config.K8sSecretsRef{
+			KeycloakAdmin: config.SecretRef{Namespace: "keycloak-ns", Name: "sekret", Key: config.DefaultKeycloakSecretKey},
+		},
+		AdmServer:    "http://admserver",
+		ManifestPath: "/registry/edge-node/en/manifest",
+		ManifestTag:  "generated",
+	}
+	manifest, err := LoadManifest(context.Background(), configuration)
+	s.NoError(err)
+	s.Empty(ValidateManifest(manifest))
+	s.Len(manifest.Lpke.DeploymentPackages, 25)
+	s.Len(manifest.Lpke.DeploymentList, 100)
+
+	plugin, err := NewExtensionsProvisionerPlugin(configuration)
+	s.NoError(err, "Cannot create extensions plugin")
+	RemoveAllPlugins()
+	Register(plugin)
+	s.NoError(Initialize(ctx))
+
+	_, err = Dispatch(ctx, Event{
+		EventType: "create",
+		UUID:      "foo",
+	}, nil)
+	s.NoError(err)
+
+	// Every 5th package and deployment, and the deployments of the absent packages, are absent
+	s.Len(mockCatalog.uploadedFiles, 20)
+	s.Contains(mockCatalog.uploadedFiles, "gen-00_1.0.0.yaml")
+	s.NotContains(mockCatalog.uploadedFiles, "gen-04_1.4.0.yaml")
+	s.Len(mockDeployments, 80)
+	s.Contains(mockDeployments, "gen-23-1.3.0-profile-3")
+	s.Equal("gen-23-profile-3", mockDeployments["gen-23-1.3.0-profile-3"].displayName)
+	s.NotContains(mockDeployments, "gen-04-1.4.0-profile-0")
+}
+
+func (s *PluginsTestSuite) TestExtensionsPluginCreateWithProfile() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	OrasFactory = NewTestOras
+	CatalogFactory = newTestCatalog
+	AppDeploymentFactory = newTestADM
+
+	mockDeployments = map[string]*mockDeployment{}
+	_, _ = newTestCatalog(config.Configuration{})
+	mockCatalog.uploadedFiles = map[string]upload{}
+
+	configuration := config.Configuration{
+		AdmServer:    "http://admserver",
+		ManifestPath: "/registry/edge-node/en/manifest",
+		ManifestTag:  "latest",
+	}
+
+	plugin, err := NewExtensionsProvisionerPlugin(configuration)
+	s.NoError(err, "Cannot create extensions plugin")
+
+	RemoveAllPlugins()
+	Register(plugin)
+
+	_, err = Dispatch(ctx, Event{
+		EventType: "create",
+		UUID:      "foo",
+		Profile: &config.ProvisioningProfile{
+			Name:               "basic",
+			DeploymentPackages: []string{"base-extensions"},
+			DeploymentProfiles: []string{"baseline"},
+		},
+	}, nil)
+	s.NoError(err)
+
+	s.Len(mockCatalog.uploadedFiles, 1)
+	s.Contains(mockCatalog.uploadedFiles, "base-extensions_0.2.0.yaml")
+
+	s.Len(mockDeployments, 1)
+	s.Contains(mockDeployments, "base-extensions-0.2.0-baseline")
+}
+
+func (s *PluginsTestSuite) TestExtensionsPluginCreateWithOrgExtensions() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	OrasFactory = NewTestOras
+	CatalogFactory = newTestCatalog
+	AppDeploymentFactory = newTestADM
+
+	mockDeployments = map[string]*mockDeployment{}
+	_, _ = newTestCatalog(config.Configuration{})
+	mockCatalog.uploadedFiles = map[string]upload{}
+
+	orgExtensionsFile := filepath.Join(s.T().TempDir(), "org-extensions.yaml")
+	s.NoError(os.WriteFile(orgExtensionsFile, []byte(`
+acme:
+  remove:
+    - skupper
+    - sriov
+  deploymentList:
+    - dpName: base-extensions
+      dpProfileName: baseline
+      dpVersion: 0.2.0
+      allAppTargetClusters:
+        - key: color
+          val: yellow
+    - dpName: intel-gpu
+      dpProfileName: default
+      dpVersion: 1.0.2
+`), 0600))
+	configuration := config.Configuration{
+		AdmServer:         "http://admserver",
+		ManifestPath:      "/registry/edge-node/en/manifest",
+		ManifestTag:       "latest",
+		OrgExtensionsPath: orgExtensionsFile,
+	}
+
+	plugin, err := NewExtensionsProvisionerPlugin(configuration)
+	s.NoError(err, "Cannot create extensions plugin")
+
+	RemoveAllPlugins()
+	Register(plugin)
+
+	_, err = Dispatch(ctx, Event{
+		EventType:    "create",
+		Organization: "acme",
+		Name:         "proj",
+		UUID:         "foo",
+	}, nil)
+	s.NoError(err)
+
+	s.Len(mockCatalog.uploadedFiles, 5)
+	s.NotContains(mockCatalog.uploadedFiles, "skupper_0.1.4.yaml")
+	s.NotContains(mockCatalog.uploadedFiles, "sriov_0.1.4.yaml")
+
+	// The deployment of the organization replaces the manifest deployment with the same profile
+	s.Len(mockDeployments, 4)
+	s.Equal("yellow", mockDeployments["base-extensions-0.2.0-baseline"].labels["color"])
+	s.Equal("green", mockDeployments["base-extensions-0.2.0-privileged"].labels["color"])
+	s.Contains(mockDeployments, "intel-gpu-1.0.2-default")
+
+	// Other organizations get the manifest as is
+	mockDeployments = map[string]*mockDeployment{}
+	mockCatalog.uploadedFiles = map[string]upload{}
+	_, err = Dispatch(ctx, Event{
+		EventType:    "create",
+		Organization: "other",
+		Name:         "proj",
+		UUID:         "bar",
+	}, nil)
+	s.NoError(err)
+	s.Len(mockCatalog.uploadedFiles, 7)
+	s.Len(mockDeployments, 3)
+
+	for _, invalid := range []string{
+		`acme: [not a map]`,
+		"acme:\n  unknown: true\n",
+		"acme:\n  deploymentPackages:\n    - dpkg: registry/edge-node/dp/usb\n",
+		"acme:\n  deploymentList:\n    - dpName: usb\n      dpVersion: 0.1.0\n",
+		"acme:\n  deploymentPackages:\n    - dpkg: registry/edge-node/dp/usb\n      version: 0.1.0\n      desiredState: gone\n",
+	} {
+		s.NoError(os.WriteFile(orgExtensionsFile, []byte(invalid), 0600))
+		_, err = NewExtensionsProvisionerPlugin(configuration)
+		s.ErrorContains(err, "invalid", invalid)
+	}
+}
+
+func (s *PluginsTestSuite) TestExtensionsPluginQuota() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	OrasFactory = NewTestOras
+	CatalogFactory = newTestCatalog
+	AppDeploymentFactory = newTestADM
+
+	mockDeployments = map[string]*mockDeployment{}
+	_, _ = newTestCatalog(config.Configuration{})
+	mockCatalog.uploadedFiles = map[string]upload{}
+
+	configuration := config.Configuration{
+		AdmServer:               "http://admserver",
+		ManifestPath:            "/registry/edge-node/en/manifest",
+		ManifestTag:             "latest",
+		MaxExtensionDeployments: 2,
+	}
+	plugin, err := NewExtensionsProvisionerPlugin(configuration)
+	s.NoError(err)
+	RemoveAllPlugins()
+	Register(plugin)
+
+	event := Event{
+		EventType:    "create",
+		Organization: "org",
+		Name:         "proj",
+		UUID:         "foo",
+	}
+	_, err = Dispatch(ctx, event, nil)
+	s.ErrorIs(err, ErrQuotaExceeded)
+	s.ErrorContains(err, "3 extension deployments would be created, the maximum is 2 (MAX_EXTENSION_DEPLOYMENTS)")
+	// Nothing is created for a project over its quota
+	s.Empty(mockCatalog.uploadedFiles)
+	s.Empty(mockDeployments)
+
+	// Only the deployments allowed by the profile count
+	event.Profile = &config.ProvisioningProfile{
+		Name:               "basic",
+		DeploymentPackages: []string{"base-extensions"},
+		DeploymentProfiles: []string{"baseline", "restricted"},
+	}
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	s.Len(mockDeployments, 2)
+	RemoveAllPlugins()
+}
+
+func (s *PluginsTestSuite) TestExtensionsPluginProgress() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	OrasFactory = NewTestOras
+	CatalogFactory = newTestCatalog
+	AppDeploymentFactory = newTestADM
+	mockDeployments = map[string]*mockDeployment{}
+	mockCatalog = testCatalog{}
+	defer func() { mockCatalog = testCatalog{} }()
+
+	plugin, err := NewExtensionsProvisionerPlugin(config.Configuration{
+		UseLocalManifest: `---
+lpke:
+  deploymentPackages:
+    - dpkg: registry/edge-node/dp/base-extensions
+      version: 0.2.0
+    - dpkg: registry/edge-node/dp/usb
+      version: 0.1.0
+      desiredState: absent
+    - dpkg: registry/edge-node/dp/skupper
+      version: 0.1.4`,
+	})
+	s.NoError(err)
+
+	// Only the packages that are loaded are counted
+	var progress []string
+	event := Event{EventType: "create", UUID: "foo", progress: func(message string) { progress = append(progress, message) }}
+	s.NoError(plugin.CreateEvent(ctx, event, NewPluginData()))
+	s.Subset(progress, []string{"Loading extensions 1/2", "Loading extensions 2/2"})
+	s.NotContains(progress, "Loading extensions 3/3")
+}
+
+func (s *PluginsTestSuite) TestExtensionsPluginPartialUpload() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	OrasFactory = NewTestOras
+	CatalogFactory = newTestCatalog
+	AppDeploymentFactory = newTestADM
+
+	mockDeployments = map[string]*mockDeployment{}
+	_, _ = newTestCatalog(config.Configuration{})
+	mockCatalog.uploadedFiles = map[string]upload{}
+	commits := mockCatalog.commits
+
+	configuration := config.Configuration{
+		AdmServer: "http://admserver",
+		UseLocalManifest: `---
+lpke:
+  deploymentPackages:
+    - dpkg: registry/edge-node/dp/base-extensions
+      version: 0.2.0
+    - dpkg: registry/edge-node/dp/missing
+      version: 1.0.0
+    - dpkg: registry/edge-node/dp/skupper
+      version: 0.1.4`,
+	}
+	plugin, err := NewExtensionsProvisionerPlugin(configuration)
+	s.NoError(err)
+
+	// A package that cannot be loaded leaves none of the packages in the catalog
+	err = plugin.CreateEvent(ctx, Event{EventType: "create", UUID: "foo"}, NewPluginData())
+	s.Error(err)
+	s.Empty(mockCatalog.uploadedFiles)
+	s.Equal(commits, mockCatalog.commits)
+
+	mockCatalog.commitErr = errors.New("catalog is unavailable")
+	defer func() { mockCatalog.commitErr = nil }()
+	configuration.UseLocalManifest = `---
+lpke:
+  deploymentPackages:
+    - dpkg: registry/edge-node/dp/base-extensions
+      version: 0.2.0`
+	plugin, err = NewExtensionsProvisionerPlugin(configuration)
+	s.NoError(err)
+	err = plugin.CreateEvent(ctx, Event{EventType: "create", UUID: "foo"}, NewPluginData())
+	s.ErrorContains(err, "catalog is unavailable")
+	s.Empty(mockCatalog.uploadedFiles)
+	s.Empty(mockDeployments)
+
+	// A file that the catalog rejects is reported with its extension package
+	mockCatalog.commitErr = &southbound.CatalogFileError{File: "base-extensions_0.2.0.yaml", Reason: "yaml: line 13: did not find expected key",
+		Err: fmt.Errorf("%w: invalid file", southbound.ErrPermanent)}
+	err = plugin.CreateEvent(ctx, Event{EventType: "create", UUID: "foo"}, NewPluginData())
+	s.ErrorIs(err, southbound.ErrPermanent)
+	s.Equal("Extension package base-extensions version 0.2.0 is invalid, file base-extensions_0.2.0.yaml: yaml: line 13: did not find expected key",
+		southbound.UserMessage(err))
+}
+
+func (s *PluginsTestSuite) TestExtensionsPluginCreateWithProjectLabels() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	OrasFactory = NewTestOras
+	CatalogFactory = newTestCatalog
+	AppDeploymentFactory = newTestADM
+
+	mockDeployments = map[string]*mockDeployment{}
+
+	configuration := config.Configuration{
+		AdmServer:    "http://admserver",
+		ManifestPath: "/registry/edge-node/en/manifest",
+		ManifestTag:  "latest",
+	}
+
+	plugin, err := NewExtensionsProvisionerPlugin(configuration)
+	s.NoError(err, "Cannot create extensions plugin")
+
+	RemoveAllPlugins()
+	Register(plugin)
+
+	_, err = Dispatch(ctx, Event{
+		EventType:        "create",
+		UUID:             "foo",
+		DeploymentLabels: map[string]string{"region": "eu-west", "color": "purple"},
+	}, nil)
+	s.NoError(err)
+
+	// Project labels are merged with the manifest labels and take precedence over them
+	s.Len(mockDeployments, 3)
+	for _, deployment := range mockDeployments {
+		s.Equal(map[string]string{"region": "eu-west", "color": "purple"}, deployment.labels)
+	}
+}
+
+func (s *PluginsTestSuite) TestExtensionsPluginTargetClusterSets() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	OrasFactory = NewTestOras
+	CatalogFactory = newTestCatalog
+	AppDeploymentFactory = newTestADM
+
+	mockDeployments = map[string]*mockDeployment{}
+	_, _ = newTestCatalog(config.Configuration{})
+	mockCatalog.packageApps = map[string][]string{"base-extensions:0.2.0": {"network-policies", "ingress-nginx"}}
+	defer func() { mockCatalog.packageApps = nil }()
+
+	orgExtensionsFile := filepath.Join(s.T().TempDir(), "org-extensions.yaml")
+	s.NoError(os.WriteFile(orgExtensionsFile, []byte(`
+acme:
+  deploymentList:
+    - dpName: base-extensions
+      dpProfileName: baseline
+      dpVersion: 0.2.0
+      allAppTargetClusters:
+        - key: color
+          val: blue
+      targetClusterSets:
+        - - key: tier
+            val: restricted
+      profileTargetClusters:
+        mixed:
+          - - key: tier
+              val: privileged
+`), 0600))
+	configuration := config.Configuration{
+		AdmServer:         "http://admserver",
+		ManifestPath:      "/registry/edge-node/en/manifest",
+		ManifestTag:       "latest",
+		OrgExtensionsPath: orgExtensionsFile,
+	}
+	plugin, err := NewExtensionsProvisionerPlugin(configuration)
+	s.NoError(err)
+	RemoveAllPlugins()
+	Register(plugin)
+
+	// Several label sets are set for each application of the package, with the project labels
+	_, err = Dispatch(ctx, Event{
+		EventType:        "create",
+		Organization:     "acme",
+		UUID:             "foo",
+		DeploymentLabels: map[string]string{"region": "eu"},
+	}, nil)
+	s.NoError(err)
+	deployment := mockDeployments["base-extensions-0.2.0-baseline"]
+	s.Require().NotNil(deployment)
+	s.Equal([]map[string]string{{"color": "blue", "region": "eu"}, {"tier": "restricted", "region": "eu"}}, deployment.targets.LabelSets)
+	s.Equal([]string{"network-policies", "ingress-nginx"}, deployment.targets.Apps)
+
+	// The label sets of the provisioning profile replace the others
+	mockDeployments = map[string]*mockDeployment{}
+	_, err = Dispatch(ctx, Event{
+		EventType:    "create",
+		Organization: "acme",
+		UUID:         "bar",
+		Profile:      &config.ProvisioningProfile{Name: "mixed"},
+	}, nil)
+	s.NoError(err)
+	deployment = mockDeployments["base-extensions-0.2.0-baseline"]
+	s.Require().NotNil(deployment)
+	s.Equal([]map[string]string{{"tier": "privileged"}}, deployment.targets.LabelSets)
+	s.Nil(deployment.targets.Apps)
+
+	// Empty label sets would target every cluster
+	s.NoError(os.WriteFile(orgExtensionsFile, []byte(`
+acme:
+  deploymentList:
+    - dpName: base-extensions
+      dpProfileName: baseline
+      dpVersion: 0.2.0
+      targetClusterSets:
+        - []
+`), 0600))
+	_, err = NewExtensionsProvisionerPlugin(configuration)
+	s.ErrorContains(err, "target cluster set 0 is empty")
+}
+
+func (s *PluginsTestSuite) TestExtensionsPluginExistingDeployments() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	OrasFactory = NewTestOras
+	CatalogFactory = newTestCatalog
+	AppDeploymentFactory = newTestADM
+
+	// an up to date deployment and one with the same display name from an older version
+	mockDeployments = map[string]*mockDeployment{
+		"base-extensions-0.2.0-baseline": {
+			name:        "base-extensions",
+			displayName: "base-extensions-baseline",
+			version:     "0.2.0",
+			profileName: "baseline",
+		},
+		"base-extensions-0.1.0-restricted": {
+			name:        "base-extensions",
+			displayName: "base-extensions-restricted",
+			version:     "0.1.0",
+			profileName: "restricted",
+		},
+	}
+
+	manifest := `---
+metadata:
+  schemaVersion: 0.3.0
+  release: 1.2.0
+lpke:
+  deploymentList:
+    - dpName: base-extensions
+      displayName: base-extensions-baseline
+      dpProfileName: baseline
+      dpVersion: 0.2.0
+    - dpName: base-extensions
+      displayName: base-extensions-restricted
+      dpProfileName: restricted
+      dpVersion: 0.2.0
+    - dpName: base-extensions
+      displayName: base-extensions-privileged
+      dpProfileName: privileged
+      dpVersion: 0.2.0`
+
+	configuration := config.Configuration{
+		AdmServer:        "http://admserver",
+		ManifestPath:     "/registry/edge-node/en/manifest",
+		ManifestTag:      "latest",
+		UseLocalManifest: manifest,
+	}
+
+	plugin, err := NewExtensionsProvisionerPlugin(configuration)
+	s.NoError(err, "Cannot create extensions plugin")
+
+	RemoveAllPlugins()
+	Register(plugin)
+
+	_, err = Dispatch(ctx, Event{
+		EventType: "create",
+		UUID:      "foo",
+	}, nil)
+	s.NoError(err)
+
+	// Only the deployment with an unused display name is created
+	s.Len(mockDeployments, 3)
+	s.Contains(mockDeployments, "base-extensions-0.2.0-privileged")
+	s.Contains(mockDeployments, "base-extensions-0.1.0-restricted")
+	s.NotContains(mockDeployments, "base-extensions-0.2.0-restricted")
+}
+
 func (s *PluginsTestSuite) TestExtensionsPluginDeleteDeployment() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
@@ -140,13 +654,14 @@ lpke:
       desiredState: absent`
 
 	configuration := config.Configuration{
-		HarborServer:      "https://harbor.org",
-		KeycloakNamespace: "keycloak-ns",
-		KeycloakSecret:    "sekret",
-		AdmServer:         "http://admserver",
-		ManifestPath:      "/registry/edge-node/en/manifest",
-		ManifestTag:       "latest",
-		UseLocalManifest:  manifest,
+		HarborServer: "https://harbor.org",
+		Secrets: config.K8sSecretsRef{
+			KeycloakAdmin: config.SecretRef{Namespace: "keycloak-ns", Name: "sekret", Key: config.DefaultKeycloakSecretKey},
+		},
+		AdmServer:        "http://admserver",
+		ManifestPath:     "/registry/edge-node/en/manifest",
+		ManifestTag:      "latest",
+		UseLocalManifest: manifest,
 	}
 
 	plugin, err := NewExtensionsProvisionerPlugin(configuration)
@@ -158,7 +673,15 @@ lpke:
 	err = Initialize(ctx)
 	assert.NoError(s.T(), err, "Initialize plugin")
 
-	err = Dispatch(ctx, Event{
+	// Ensure events do not delete absent deployments
+	_, err = Dispatch(ctx, Event{
+		EventType: "ensure",
+		UUID:      "foo",
+	}, nil)
+	s.NoError(err)
+	s.Len(mockDeployments, 3)
+
+	_, err = Dispatch(ctx, Event{
 		EventType: "create",
 		UUID:      "foo",
 	}, nil)
@@ -179,6 +702,321 @@ lpke:
 	s.Equal("green", mockDeployments[privKey].labels["color"])
 }
 
+func (s *PluginsTestSuite) TestExtensionsPluginReconcilePackages() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	store := &testInventoryStore{inventories: map[string]*southbound.Inventory{
+		"foo": {UUID: "foo", ExtensionPackages: []southbound.InventoryPackage{
+			{Name: "intel-gpu", Version: "1.0.2"},
+			{Name: "usb", Version: "0.1.0"},
+			{Name: "loadbalancer", Version: "0.1.0"},
+			{Name: "sriov", Version: "0.1.4"},
+			{Name: "skupper", Version: "0.1.4"},
+		}},
+	}}
+	InventoryStoreFactory = func(_ config.Configuration) (InventoryStore, error) { return store, nil }
+	defer func() { InventoryStoreFactory = NewInventoryStore }()
+	mockCatalog = testCatalog{}
+	defer func() { mockCatalog = testCatalog{} }()
+	cat, err := newTestCatalog(config.Configuration{})
+	s.NoError(err)
+	for _, fileName := range []string{"intel-gpu_1.0.2.yaml", "usb_0.1.0.yaml", "loadbalancer_0.1.0.yaml", "loadbalancer_0.2.6.yaml",
+		"sriov_0.1.4.yaml", "custom_1.0.yaml"} {
+		mockCatalog.uploadedFiles[fileName] = upload{path: fileName}
+	}
+	ad := &mockDynamicADM{listDeploymentsFunc: func(_ context.Context, _ string) (map[string]southbound.DeploymentInfo, error) {
+		return map[string]southbound.DeploymentInfo{"sriov": {AppName: "sriov", AppVersion: "0.1.4"}}, nil
+	}}
+
+	// usb is absent, loadbalancer has a new version, sriov and skupper were dropped
+	manifest := &Manifest{}
+	manifest.Lpke.DeploymentPackages = []ManifestDeploymentPackage{
+		{Dpkg: "edge-node/dp/intel-gpu", Version: "1.0.2"},
+		{Dpkg: "edge-node/dp/usb", Version: "0.1.0", DesiredState: DesiredStateAbsent},
+		{Dpkg: "edge-node/dp/loadbalancer", Version: "0.2.6"},
+	}
+	plugin := &ExtensionsProvisionerPlugin{configuration: config.Configuration{PodNamespace: "orch-app"}}
+	pluginData := NewPluginData()
+	uploaded := []southbound.InventoryPackage{{Name: "intel-gpu", Version: "1.0.2"}, {Name: "loadbalancer", Version: "0.2.6"}}
+
+	// Ensure events keep the stale packages
+	s.NoError(plugin.reconcilePackages(ctx, cat, ad, Event{EventType: "ensure", UUID: "foo", Name: "foo"}, manifest, uploaded, pluginData))
+	s.Empty(mockCatalog.deletedFiles)
+	s.Len(pendingInventory(pluginData).ExtensionPackages, 6)
+
+	pluginData = NewPluginData()
+	s.NoError(plugin.reconcilePackages(ctx, cat, ad, Event{UUID: "foo", Name: "foo"}, manifest, uploaded, pluginData))
+
+	// Deployed packages and packages the controller did not upload are kept
+	s.Equal([]string{"usb:0.1.0", "loadbalancer:0.1.0"}, mockCatalog.deletedFiles)
+	s.Contains(mockCatalog.uploadedFiles, "sriov_0.1.4.yaml")
+	s.Contains(mockCatalog.uploadedFiles, "custom_1.0.yaml")
+	s.Equal([]southbound.InventoryPackage{
+		{Name: "intel-gpu", Version: "1.0.2"},
+		{Name: "loadbalancer", Version: "0.2.6"},
+		{Name: "sriov", Version: "0.1.4"},
+	}, pendingInventory(pluginData).ExtensionPackages)
+}
+
+func (s *PluginsTestSuite) TestExtensionsPluginContentHashes() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	OrasFactory = NewTestOras
+	CatalogFactory = newTestCatalog
+	AppDeploymentFactory = newTestADM
+	mockCatalog = testCatalog{}
+	defer func() { mockCatalog = testCatalog{} }()
+	mockDeployments = map[string]*mockDeployment{}
+	store := &testInventoryStore{inventories: map[string]*southbound.Inventory{}}
+	InventoryStoreFactory = func(_ config.Configuration) (InventoryStore, error) { return store, nil }
+	defer func() { InventoryStoreFactory = NewInventoryStore }()
+
+	configuration := config.Configuration{
+		AdmServer:    "http://admserver",
+		ManifestPath: "/registry/edge-node/en/manifest",
+		ManifestTag:  "generated",
+		PodNamespace: "orch-app",
+	}
+	plugin, err := NewExtensionsProvisionerPlugin(configuration)
+	s.NoError(err)
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+	Register(plugin)
+	Register(NewInventoryRecorderPlugin(configuration))
+	s.NoError(Initialize(ctx))
+
+	event := Event{EventType: "create", Organization: "org", Name: "proj", UUID: "foo"}
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	inventory := store.inventories["foo"]
+	s.Len(inventory.ExtensionHashes, len(inventory.ExtensionPackages))
+	s.Contains(inventory.ExtensionHashes, "gen-00:1.0.0")
+	s.NotEmpty(inventory.Deployments)
+	for _, deployment := range inventory.Deployments {
+		s.NotEmpty(deployment.Hash, deployment.DisplayName)
+	}
+	uploads := len(mockCatalog.uploadedFiles)
+	s.NotZero(uploads)
+
+	// Provisioning again uploads nothing, the packages and deployments are unchanged
+	for fileName := range mockCatalog.uploadedFiles {
+		mockCatalog.uploadedFiles[fileName] = upload{path: fileName, artifact: "unchanged"}
+	}
+	hashes := inventory.ExtensionHashes
+	result, err := Dispatch(ctx, event, nil)
+	s.NoError(err)
+	s.Empty(result.Warnings)
+	for _, uploaded := range mockCatalog.uploadedFiles {
+		s.Equal("unchanged", uploaded.artifact, uploaded.path)
+	}
+	s.Equal(hashes, store.inventories["foo"].ExtensionHashes)
+
+	// A package whose content changed is uploaded again, as is a package missing from the catalog
+	store.inventories["foo"].ExtensionHashes["gen-00:1.0.0"] = "changed"
+	delete(mockCatalog.uploadedFiles, "gen-01_1.1.0.yaml")
+	result, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	s.NotEqual("unchanged", mockCatalog.uploadedFiles["gen-00_1.0.0.yaml"].artifact)
+	s.Contains(mockCatalog.uploadedFiles, "gen-01_1.1.0.yaml")
+	s.Equal("unchanged", mockCatalog.uploadedFiles["gen-02_1.2.0.yaml"].artifact)
+	s.Equal(hashes["gen-00:1.0.0"], store.inventories["foo"].ExtensionHashes["gen-00:1.0.0"])
+	s.Empty(result.Warnings)
+
+	// A deployment created from another definition is left in place with its hash, and reported
+	deployment := store.inventories["foo"].Deployments[0]
+	store.inventories["foo"].Deployments[0].Hash = "other-targets"
+	result, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	s.Len(result.Warnings, 1)
+	s.Contains(result.Warnings[0], deployment.DisplayName)
+	s.Equal("other-targets", store.inventories["foo"].Deployments[0].Hash)
+}
+
+func (s *PluginsTestSuite) TestExtensionsPluginTenantLabels() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	OrasFactory = NewTestOras
+	CatalogFactory = newTestCatalog
+	AppDeploymentFactory = newTestADM
+	mockCatalog = testCatalog{}
+	defer func() { mockCatalog = testCatalog{} }()
+	mockDeployments = map[string]*mockDeployment{}
+	store := &testInventoryStore{inventories: map[string]*southbound.Inventory{}}
+	InventoryStoreFactory = func(_ config.Configuration) (InventoryStore, error) { return store, nil }
+	defer func() { InventoryStoreFactory = NewInventoryStore }()
+
+	configuration := config.Configuration{
+		AdmServer:    "http://admserver",
+		ManifestPath: "/registry/edge-node/en/manifest",
+		ManifestTag:  "generated",
+		PodNamespace: "orch-app",
+	}
+	plugin, err := NewExtensionsProvisionerPlugin(configuration)
+	s.NoError(err)
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+	Register(plugin)
+	Register(NewInventoryRecorderPlugin(configuration))
+	s.NoError(Initialize(ctx))
+
+	// Deployments created before the tenant labels are enabled are left in place, and reported
+	event := Event{EventType: "create", Organization: "org", Name: "proj", UUID: "foo"}
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	deployments := len(mockDeployments)
+	s.NotZero(deployments)
+	untagged := store.inventories["foo"].Deployments[0]
+
+	event.DeploymentLabels = TenantDeploymentLabels(nil, "org", "foo")
+	result, err := Dispatch(ctx, event, nil)
+	s.NoError(err)
+	s.Len(result.Warnings, deployments)
+	s.Contains(strings.Join(result.Warnings, "\n"), untagged.DisplayName+" was created without the organization and project labels")
+	s.Equal(untagged.Hash, store.inventories["foo"].Deployments[0].Hash)
+
+	// ... and created with the labels once deleted
+	key := fmt.Sprintf("%s-%s-%s", untagged.AppName, untagged.AppVersion, untagged.ProfileName)
+	s.Contains(mockDeployments, key)
+	delete(mockDeployments, key)
+	result, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	s.Len(result.Warnings, deployments-1)
+	s.Equal("org", mockDeployments[key].labels[DeploymentOrganizationLabel])
+	s.Equal("foo", mockDeployments[key].labels[DeploymentProjectUUIDLabel])
+}
+
+func extensionDeletionCount(kind string, result string) float64 {
+	return testutil.ToFloat64(extensionDeletions.WithLabelValues(kind, result))
+}
+
+func (s *PluginsTestSuite) TestExtensionsPluginDeleteEvent() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	OrasFactory = NewTestOras
+	CatalogFactory = newTestCatalog
+	AppDeploymentFactory = newTestADM
+	mockCatalog = testCatalog{}
+	defer func() { mockCatalog = testCatalog{} }()
+	mockDeployments = map[string]*mockDeployment{}
+	store := &testInventoryStore{inventories: map[string]*southbound.Inventory{}}
+	InventoryStoreFactory = func(_ config.Configuration) (InventoryStore, error) { return store, nil }
+	defer func() { InventoryStoreFactory = NewInventoryStore }()
+
+	configuration := config.Configuration{
+		AdmServer:    "http://admserver",
+		ManifestPath: "/registry/edge-node/en/manifest",
+		ManifestTag:  "generated",
+		PodNamespace: "orch-app",
+	}
+	plugin, err := NewExtensionsProvisionerPlugin(configuration)
+	s.NoError(err)
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+	Register(plugin)
+	Register(NewInventoryRecorderPlugin(configuration))
+	s.NoError(Initialize(ctx))
+
+	event := Event{EventType: "create", Organization: "org", Name: "proj", UUID: "foo"}
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	s.NotEmpty(mockDeployments)
+	s.NotEmpty(mockCatalog.uploadedFiles)
+
+	// A deployment and package that the manifest no longer lists are deleted too, as they are recorded
+	_, err = newTestADM(configuration)
+	s.NoError(err)
+	s.NoError(mockADM.CreateDeployment(ctx, "old", "old-deployment", "0.1.0", "default", "foo", southbound.DeploymentTargets{}))
+	mockCatalog.uploadedFiles["old_0.1.0.yaml"] = upload{path: "old_0.1.0.yaml"}
+	inventory := store.inventories["foo"]
+	inventory.Deployments = append(inventory.Deployments, southbound.InventoryDeployment{
+		DisplayName: "old-deployment", AppName: "old", AppVersion: "0.1.0", ProfileName: "default",
+	})
+	inventory.ExtensionPackages = append(inventory.ExtensionPackages, southbound.InventoryPackage{Name: "old", Version: "0.1.0"})
+	deployments, packages := len(mockDeployments), len(mockCatalog.uploadedFiles)
+
+	deleted := extensionDeletionCount(extensionKindDeployment, deletionDeleted)
+	deletedPackages := extensionDeletionCount(extensionKindPackage, deletionDeleted)
+	event.EventType = "delete"
+	s.NoError(plugin.DeleteEvent(ctx, event, NewPluginData()))
+	s.Empty(mockDeployments)
+	s.Empty(mockCatalog.uploadedFiles)
+	s.Equal(deleted+float64(deployments), extensionDeletionCount(extensionKindDeployment, deletionDeleted))
+	s.Equal(deletedPackages+float64(packages), extensionDeletionCount(extensionKindPackage, deletionDeleted))
+
+	// Deleting again finds nothing left to delete
+	missing := extensionDeletionCount(extensionKindDeployment, deletionMissing)
+	s.NoError(plugin.DeleteEvent(ctx, event, NewPluginData()))
+	s.Less(missing, extensionDeletionCount(extensionKindDeployment, deletionMissing))
+	s.Equal(deleted+float64(deployments), extensionDeletionCount(extensionKindDeployment, deletionDeleted))
+}
+
+func (s *PluginsTestSuite) TestExtensionsPluginDeleteEventVerification() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	manifest := `---
+metadata:
+  schemaVersion: 0.3.0
+  release: 1.2.0
+lpke:
+  deploymentList:
+    - dpName: base-extensions
+      displayName: base-extensions-baseline
+      dpProfileName: baseline
+      dpVersion: 0.2.0`
+	configuration := config.Configuration{
+		AdmServer:        "http://admserver",
+		ManifestPath:     "/registry/edge-node/en/manifest",
+		ManifestTag:      "latest",
+		UseLocalManifest: manifest,
+	}
+	plugin, err := NewExtensionsProvisionerPlugin(configuration)
+	s.NoError(err)
+
+	// ADM still lists the deployment after deleting it
+	AppDeploymentFactory = func(_ config.Configuration) (AppDeployment, error) {
+		return &mockDynamicADM{listDeploymentsFunc: func(_ context.Context, _ string) (map[string]southbound.DeploymentInfo, error) {
+			return map[string]southbound.DeploymentInfo{"base-extensions-baseline": {
+				DisplayName: "base-extensions-baseline", AppName: "base-extensions", AppVersion: "0.2.0", ProfileName: "baseline",
+			}}, nil
+		}}, nil
+	}
+	defer func() { AppDeploymentFactory = newTestADM }()
+	CatalogFactory = newTestCatalog
+
+	event := Event{EventType: "delete", Organization: "org", Name: "proj", UUID: "foo"}
+	err = plugin.DeleteEvent(ctx, event, NewPluginData())
+	s.ErrorIs(err, southbound.ErrTransient)
+	s.ErrorContains(err, "1 extension deployments of project proj remain")
+
+	// A deployment that cannot be deleted fails the event
+	AppDeploymentFactory = func(_ config.Configuration) (AppDeployment, error) {
+		return &failingDeleteADM{err: errors.New("adm unavailable")}, nil
+	}
+	err = plugin.DeleteEvent(ctx, event, NewPluginData())
+	s.ErrorContains(err, "failed to delete extension deployment base-extensions-baseline: adm unavailable")
+}
+
+// failingDeleteADM is an ADM that lists a deployment of the base extensions and fails to delete it
+type failingDeleteADM struct {
+	mockDynamicADM
+	err error
+}
+
+func (f *failingDeleteADM) ListDeployments(_ context.Context, _ string, _ southbound.DeploymentFilter) (map[string]southbound.DeploymentInfo, error) {
+	return map[string]southbound.DeploymentInfo{"base-extensions-baseline": {
+		DisplayName: "base-extensions-baseline", AppName: "base-extensions", AppVersion: "0.2.0", ProfileName: "baseline",
+	}}, nil
+}
+
+func (f *failingDeleteADM) DeleteDeployment(_ context.Context, _ string, _ string, _ string, _ string, _ string, _ bool) error {
+	return f.err
+}
+
 func (s *PluginsTestSuite) TestExtensionsPluginDeleteDeploymentNonexistent() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
@@ -230,13 +1068,14 @@ lpke:
       desiredState: absent`
 
 	configuration := config.Configuration{
-		HarborServer:      "https://harbor.org",
-		KeycloakNamespace: "keycloak-ns",
-		KeycloakSecret:    "sekret",
-		AdmServer:         "http://admserver",
-		ManifestPath:      "/registry/edge-node/en/manifest",
-		ManifestTag:       "latest",
-		UseLocalManifest:  manifest,
+		HarborServer: "https://harbor.org",
+		Secrets: config.K8sSecretsRef{
+			KeycloakAdmin: config.SecretRef{Namespace: "keycloak-ns", Name: "sekret", Key: config.DefaultKeycloakSecretKey},
+		},
+		AdmServer:        "http://admserver",
+		ManifestPath:     "/registry/edge-node/en/manifest",
+		ManifestTag:      "latest",
+		UseLocalManifest: manifest,
 	}
 
 	plugin, err := NewExtensionsProvisionerPlugin(configuration)
@@ -248,7 +1087,7 @@ lpke:
 	err = Initialize(ctx)
 	assert.NoError(s.T(), err, "Initialize plugin")
 
-	err = Dispatch(ctx, Event{
+	_, err = Dispatch(ctx, Event{
 		EventType: "create",
 		UUID:      "foo",
 	}, nil)
@@ -309,20 +1148,19 @@ func (s *PluginsTestSuite) TestExtensionsWaitForADMSkipsWhenNotConfigured() {
 
 // TestExtensionsWaitForADMFailsAfterRetries tests that waitForADM fails after max retries
 func (s *PluginsTestSuite) TestExtensionsWaitForADMFailsAfterRetries() {
-	// Skip this test unless explicitly requested as it takes time
-	if os.Getenv("RUN_LONG_TESTS") != "1" {
-		s.T().Skip("Skipping long-running test. To run: RUN_LONG_TESTS=1 go test -v -run TestPlugins/TestExtensionsWaitForADMFailsAfterRetries -timeout 2m")
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*2)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
+	ctx, clock := retrytest.Context(ctx)
 
-	// Use factory that returns error
+	// Use an ADM that never answers
 	failingAttempts := 0
 	AppDeploymentFactory = func(_ config.Configuration) (AppDeployment, error) {
-		failingAttempts++
-		return nil, fmt.Errorf("ADM factory failed (attempt %d)", failingAttempts)
+		return &mockDynamicADM{
+			listDeploymentsFunc: func(_ context.Context, _ string) (map[string]southbound.DeploymentInfo, error) {
+				failingAttempts++
+				return nil, fmt.Errorf("ADM not ready (attempt %d)", failingAttempts)
+			},
+		}, nil
 	}
 
 	plugin := &ExtensionsProvisionerPlugin{
@@ -331,31 +1169,30 @@ func (s *PluginsTestSuite) TestExtensionsWaitForADMFailsAfterRetries() {
 		},
 	}
 
-	startTime := time.Now()
 	err := plugin.waitForADM(ctx)
-	duration := time.Since(startTime)
 
 	s.Error(err, "waitForADM should fail when ADM is not available")
 	s.Contains(err.Error(), "ADM not available after", "Error should indicate max retries exceeded")
-	s.Greater(failingAttempts, 1, "Should attempt multiple times")
-	s.Less(duration, time.Minute*2, "Should not exceed context timeout")
+	s.Equal(serviceBackoff.Attempts, failingAttempts, "Should attempt multiple times")
+	s.Len(clock.Waits(), serviceBackoff.Attempts-1, "Should wait between the attempts")
 }
 
 // TestExtensionsWaitForADMRecoversAfterRetries tests that waitForADM succeeds after some failures
 func (s *PluginsTestSuite) TestExtensionsWaitForADMRecoversAfterRetries() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
+	ctx, clock := retrytest.Context(ctx)
 
 	attempts := 0
 	// Create a mock ADM that fails initially then succeeds
 	mockADM := &mockDynamicADM{
-		listDeploymentNamesFunc: func(_ context.Context, _ string) (map[string]string, error) {
+		listDeploymentsFunc: func(_ context.Context, _ string) (map[string]southbound.DeploymentInfo, error) {
 			attempts++
 			if attempts < 3 {
 				return nil, fmt.Errorf("ADM not ready yet (attempt %d)", attempts)
 			}
 			// After 3 attempts, succeed
-			return map[string]string{}, nil
+			return map[string]southbound.DeploymentInfo{}, nil
 		},
 	}
 
@@ -369,28 +1206,26 @@ func (s *PluginsTestSuite) TestExtensionsWaitForADMRecoversAfterRetries() {
 		},
 	}
 
-	startTime := time.Now()
 	err := plugin.waitForADM(ctx)
-	duration := time.Since(startTime)
 
 	s.NoError(err, "waitForADM should succeed after recovering")
 	s.GreaterOrEqual(attempts, 3, "Should attempt at least 3 times before succeeding")
-	s.Greater(duration, time.Second*10, "Should take time due to retries with backoff")
-	s.T().Logf("ADM recovered after %d attempts in %v", attempts, duration)
-} // TestExtensionsInitializeFailsWhenADMFails tests that Initialize propagates ADM errors
-func (s *PluginsTestSuite) TestExtensionsInitializeFailsWhenADMFails() {
-	// Skip this test unless explicitly requested as it takes time
-	if os.Getenv("RUN_LONG_TESTS") != "1" {
-		s.T().Skip("Skipping long-running test. To run: RUN_LONG_TESTS=1 go test -v -run TestPlugins/TestExtensionsInitializeFailsWhenADMFails -timeout 2m")
-		return
-	}
+	s.Greater(clock.Waited(), time.Second*10, "Should take time due to retries with backoff")
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*2)
+// TestExtensionsInitializeFailsWhenADMFails tests that Initialize propagates ADM errors
+func (s *PluginsTestSuite) TestExtensionsInitializeFailsWhenADMFails() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
+	ctx, _ = retrytest.Context(ctx)
 
-	// Use factory that always fails
+	// Use an ADM that never answers
 	AppDeploymentFactory = func(_ config.Configuration) (AppDeployment, error) {
-		return nil, fmt.Errorf("ADM connection permanently failed")
+		return &mockDynamicADM{
+			listDeploymentsFunc: func(_ context.Context, _ string) (map[string]southbound.DeploymentInfo, error) {
+				return nil, fmt.Errorf("ADM connection failed")
+			},
+		}, nil
 	}
 
 	plugin := &ExtensionsProvisionerPlugin{
@@ -418,3 +1253,30 @@ func (s *PluginsTestSuite) TestExtensionsInitializeSucceedsWhenADMNotConfigured(
 	err := plugin.Initialize(ctx, nil)
 	s.NoError(err, "Initialize should succeed when ADM is not configured")
 }
+
+// TestExtensionsInitializeFailsWhenKeycloakFails tests that Initialize fails on Keycloak before ADM is tried
+func (s *PluginsTestSuite) TestExtensionsInitializeFailsWhenKeycloakFails() {
+	defer func(check func(context.Context, config.Configuration) error) { KeycloakHealthCheck = check }(KeycloakHealthCheck)
+	defer func(factory func(config.Configuration) (AppDeployment, error)) { AppDeploymentFactory = factory }(AppDeploymentFactory)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	ctx, _ = retrytest.Context(ctx)
+
+	KeycloakHealthCheck = func(_ context.Context, _ config.Configuration) error {
+		return fmt.Errorf("%w: connection refused", southbound.ErrTransient)
+	}
+	AppDeploymentFactory = func(_ config.Configuration) (AppDeployment, error) {
+		s.Fail("ADM should not be tried without Keycloak")
+		return &mockDynamicADM{}, nil
+	}
+
+	plugin := &ExtensionsProvisionerPlugin{
+		configuration: config.Configuration{
+			KeycloakServiceBase: "http://invalid-keycloak:9999",
+			AdmServer:           "localhost:8080",
+		},
+	}
+
+	err := plugin.Initialize(ctx, nil)
+	s.ErrorContains(err, "extensions initialization failed during keycloak check: keycloak not available")
+}
@@ -0,0 +1,215 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package plugins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	"k8s.io/client-go/rest"
+)
+
+// gitOpsDeployKeyTitle is the title of the deploy keys added by the controller, other deploy keys are left alone
+const gitOpsDeployKeyTitle = "app-orch-tenant-controller"
+
+type GitServer interface {
+	GetRepository(ctx context.Context, name string) (*southbound.GitRepository, error)
+	CreateRepository(ctx context.Context, name string, description string) (*southbound.GitRepository, error)
+	DeleteRepository(ctx context.Context, name string) error
+	ListDeployKeys(ctx context.Context, name string) ([]southbound.GitDeployKey, error)
+	AddDeployKey(ctx context.Context, name string, title string, publicKey string, readOnly bool) (*southbound.GitDeployKey, error)
+	DeleteDeployKey(ctx context.Context, name string, id int) error
+}
+
+func NewGitServer(ctx context.Context, configuration config.Configuration) (GitServer, error) {
+	token, err := southbound.ReadSecretRef(ctx, configuration.GitOps.Token)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the GitOps API token: %w", err)
+	}
+	return southbound.NewGitServer(configuration.GitOps, string(token)), nil
+}
+
+var GitServerFactory = NewGitServer
+
+type GitOpsSecretStore interface {
+	Save(ctx context.Context, uuid string, organization string, project string, credentials southbound.GitOpsCredentials) error
+	Load(ctx context.Context, uuid string) (*southbound.GitOpsCredentials, error)
+	Delete(ctx context.Context, uuid string) error
+}
+
+func NewGitOpsSecretStore(configuration config.Configuration) (GitOpsSecretStore, error) {
+	restConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	return southbound.NewGitOpsSecretStore(restConfig, configuration.PodNamespace)
+}
+
+var GitOpsSecretStoreFactory = NewGitOpsSecretStore
+
+// GitOpsRepositoryName returns the name of the GitOps repository of a project.
+func GitOpsRepositoryName(org string, name string) string {
+	return strings.ToLower(org) + "-" + strings.ToLower(name)
+}
+
+// GitOpsProvisionerPlugin creates a Git repository for the application configuration of each project, on the Gitea
+// or GitLab server of the configuration, and gives it a deploy key. The private key is stored in a secret in the
+// controller namespace, so that a GitOps agent can be given access to the repository.
+type GitOpsProvisionerPlugin struct {
+	config config.Configuration
+}
+
+func NewGitOpsProvisionerPlugin(configuration config.Configuration) *GitOpsProvisionerPlugin {
+	return &GitOpsProvisionerPlugin{
+		config: configuration,
+	}
+}
+
+func (p *GitOpsProvisionerPlugin) Initialize(_ context.Context, _ *PluginData) error {
+	return nil
+}
+
+// CreateEvent creates the repository of the project if it does not exist yet, and keeps its deploy key if the key
+// stored in the secret is still registered on the repository. Otherwise, or if the event asks for new credentials,
+// the deploy keys of the controller are replaced by a new one.
+func (p *GitOpsProvisionerPlugin) CreateEvent(ctx context.Context, event Event, pluginData *PluginData) error {
+	git, err := GitServerFactory(ctx, p.config)
+	if err != nil {
+		return err
+	}
+	store, err := GitOpsSecretStoreFactory(p.config)
+	if err != nil {
+		return err
+	}
+	name := GitOpsRepositoryName(event.Organization, event.Name)
+
+	event.ReportProgress("Creating GitOps repository")
+	repository, err := git.GetRepository(ctx, name)
+	if errors.Is(err, southbound.ErrNotFound) {
+		repository, err = git.CreateRepository(ctx, name, fmt.Sprintf("Application configuration of project %s of organization %s",
+			event.Name, event.Organization))
+	}
+	if err != nil {
+		return err
+	}
+
+	event.ReportProgress("Registering GitOps deploy key")
+	keys, err := git.ListDeployKeys(ctx, name)
+	if err != nil {
+		return err
+	}
+	credentials, err := store.Load(ctx, event.UUID)
+	if err != nil {
+		return err
+	}
+	ensure := event.EventType == "ensure"
+	var deployKey *southbound.GitDeployKey
+	changed := false
+	for i, key := range keys {
+		if key.Title != gitOpsDeployKeyTitle {
+			continue
+		}
+		if deployKey == nil && credentials != nil && !event.RefreshCredentials && key.ReadOnly == p.config.GitOps.DeployKeyReadOnly &&
+			southbound.SameDeployKey(key.Key, credentials.PublicKey) {
+			deployKey = &keys[i]
+			continue
+		}
+		if ensure {
+			log.Infof("Leaving deploy key %d of GitOps repository %s in place for the ensure event", key.ID, name)
+			continue
+		}
+		if err := git.DeleteDeployKey(ctx, name, key.ID); err != nil {
+			return err
+		}
+	}
+
+	if deployKey == nil && ensure && credentials != nil {
+		// The stored key is registered again, so that the GitOps agents using it keep their access
+		deployKey, err = git.AddDeployKey(ctx, name, gitOpsDeployKeyTitle, credentials.PublicKey, p.config.GitOps.DeployKeyReadOnly)
+		if err != nil {
+			return err
+		}
+	}
+	if deployKey == nil {
+		publicKey, privateKey, err := southbound.GenerateDeployKey(gitOpsDeployKeyTitle + "@" + name)
+		if err != nil {
+			return err
+		}
+		deployKey, err = git.AddDeployKey(ctx, name, gitOpsDeployKeyTitle, publicKey, p.config.GitOps.DeployKeyReadOnly)
+		if err != nil {
+			return err
+		}
+		credentials = &southbound.GitOpsCredentials{PrivateKey: privateKey, PublicKey: publicKey}
+		changed = true
+	}
+	if changed || credentials.Repository != repository.SSHURL {
+		credentials.Repository = repository.SSHURL
+		if err := store.Save(ctx, event.UUID, event.Organization, event.Name, *credentials); err != nil {
+			return err
+		}
+	}
+
+	recordInventory(pluginData, func(inventory *southbound.Inventory) {
+		inventory.GitRepository = &southbound.InventoryGitRepository{
+			Name:        name,
+			URL:         repository.URL,
+			SSHURL:      repository.SSHURL,
+			DeployKeyID: deployKey.ID,
+			Secret:      southbound.GitOpsSecretPrefix + event.UUID,
+		}
+	})
+	return nil
+}
+
+// EnsureEvent creates the repository of the project if it does not exist yet, and registers the deploy key stored in
+// the secret on it if it is missing. Other deploy keys are left in place, and a new key is only generated if none is
+// stored.
+func (p *GitOpsProvisionerPlugin) EnsureEvent(ctx context.Context, event Event, pluginData *PluginData) error {
+	return p.CreateEvent(ctx, event, pluginData)
+}
+
+// DeleteEvent deletes the repository of the project, or only revokes its deploy keys if the project data is
+// retained, then deletes the deploy key secret.
+func (p *GitOpsProvisionerPlugin) DeleteEvent(ctx context.Context, event Event, _ *PluginData) error {
+	git, err := GitServerFactory(ctx, p.config)
+	if err != nil {
+		return err
+	}
+	store, err := GitOpsSecretStoreFactory(p.config)
+	if err != nil {
+		return err
+	}
+	name := GitOpsRepositoryName(event.Organization, event.Name)
+
+	if event.RetainData {
+		event.ReportProgress("Revoking GitOps deploy keys")
+		keys, err := git.ListDeployKeys(ctx, name)
+		if err != nil && !errors.Is(err, southbound.ErrNotFound) {
+			return err
+		}
+		for _, key := range keys {
+			if key.Title != gitOpsDeployKeyTitle {
+				continue
+			}
+			if err := git.DeleteDeployKey(ctx, name, key.ID); err != nil {
+				return err
+			}
+		}
+	} else {
+		event.ReportProgress("Deleting GitOps repository")
+		if err := git.DeleteRepository(ctx, name); err != nil {
+			return err
+		}
+	}
+	return store.Delete(ctx, event.UUID)
+}
+
+func (p *GitOpsProvisionerPlugin) Name() string {
+	return "GitOps Provisioner"
+}
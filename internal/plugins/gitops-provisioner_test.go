@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+)
+
+type testGitServer struct {
+	repositories map[string]*southbound.GitRepository
+	keys         map[string][]southbound.GitDeployKey
+	nextID       int
+	deletedKeys  []int
+}
+
+func newTestGitServer() *testGitServer {
+	return &testGitServer{repositories: map[string]*southbound.GitRepository{}, keys: map[string][]southbound.GitDeployKey{}}
+}
+
+func (g *testGitServer) GetRepository(_ context.Context, name string) (*southbound.GitRepository, error) {
+	if repository, ok := g.repositories[name]; ok {
+		return repository, nil
+	}
+	return nil, fmt.Errorf("repository %s %w", name, southbound.ErrNotFound)
+}
+
+func (g *testGitServer) CreateRepository(_ context.Context, name string, _ string) (*southbound.GitRepository, error) {
+	g.nextID++
+	g.repositories[name] = &southbound.GitRepository{
+		ID:     g.nextID,
+		Name:   name,
+		URL:    "https://git/tenants/" + name,
+		SSHURL: "git@git:tenants/" + name + ".git",
+	}
+	return g.repositories[name], nil
+}
+
+func (g *testGitServer) DeleteRepository(_ context.Context, name string) error {
+	delete(g.repositories, name)
+	delete(g.keys, name)
+	return nil
+}
+
+func (g *testGitServer) ListDeployKeys(_ context.Context, name string) ([]southbound.GitDeployKey, error) {
+	if _, ok := g.repositories[name]; !ok {
+		return nil, fmt.Errorf("repository %s %w", name, southbound.ErrNotFound)
+	}
+	return g.keys[name], nil
+}
+
+func (g *testGitServer) AddDeployKey(_ context.Context, name string, title string, publicKey string, readOnly bool) (*southbound.GitDeployKey, error) {
+	g.nextID++
+	key := southbound.GitDeployKey{ID: g.nextID, Title: title, Key: publicKey, ReadOnly: readOnly}
+	g.keys[name] = append(g.keys[name], key)
+	return &key, nil
+}
+
+func (g *testGitServer) DeleteDeployKey(_ context.Context, name string, id int) error {
+	g.deletedKeys = append(g.deletedKeys, id)
+	var kept []southbound.GitDeployKey
+	for _, key := range g.keys[name] {
+		if key.ID != id {
+			kept = append(kept, key)
+		}
+	}
+	g.keys[name] = kept
+	return nil
+}
+
+type testGitOpsSecretStore struct {
+	secrets map[string]southbound.GitOpsCredentials
+}
+
+func (s *testGitOpsSecretStore) Save(_ context.Context, uuid string, _ string, _ string, credentials southbound.GitOpsCredentials) error {
+	s.secrets[uuid] = credentials
+	return nil
+}
+
+func (s *testGitOpsSecretStore) Load(_ context.Context, uuid string) (*southbound.GitOpsCredentials, error) {
+	credentials, ok := s.secrets[uuid]
+	if !ok {
+		return nil, nil
+	}
+	return &credentials, nil
+}
+
+func (s *testGitOpsSecretStore) Delete(_ context.Context, uuid string) error {
+	delete(s.secrets, uuid)
+	return nil
+}
+
+func (s *PluginsTestSuite) TestGitOpsPlugin() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	git := newTestGitServer()
+	store := &testGitOpsSecretStore{secrets: map[string]southbound.GitOpsCredentials{}}
+	GitServerFactory = func(_ context.Context, _ config.Configuration) (GitServer, error) { return git, nil }
+	GitOpsSecretStoreFactory = func(_ config.Configuration) (GitOpsSecretStore, error) { return store, nil }
+	defer func() {
+		GitServerFactory = NewGitServer
+		GitOpsSecretStoreFactory = NewGitOpsSecretStore
+	}()
+
+	plugin := NewGitOpsProvisionerPlugin(config.Configuration{GitOps: config.GitOps{Provider: config.GitOpsProviderGitea, DeployKeyReadOnly: true}})
+	event := Event{EventType: "create", Organization: "Org", Name: "Proj", UUID: "uuid-1"}
+
+	// The repository is created with a deploy key, whose private key is stored in the secret
+	pluginData := NewPluginData()
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	s.Contains(git.repositories, "org-proj")
+	s.Len(git.keys["org-proj"], 1)
+	key := git.keys["org-proj"][0]
+	s.Equal("app-orch-tenant-controller", key.Title)
+	s.True(key.ReadOnly)
+	credentials := store.secrets["uuid-1"]
+	s.Equal("git@git:tenants/org-proj.git", credentials.Repository)
+	s.True(southbound.SameDeployKey(key.Key, credentials.PublicKey))
+	s.NotEmpty(credentials.PrivateKey)
+	s.Equal(&southbound.InventoryGitRepository{
+		Name:        "org-proj",
+		URL:         "https://git/tenants/org-proj",
+		SSHURL:      "git@git:tenants/org-proj.git",
+		DeployKeyID: key.ID,
+		Secret:      "tenant-gitops-uuid-1",
+	}, pendingInventory(pluginData).GitRepository)
+
+	// Provisioning again keeps the repository and the deploy key, and leaves other deploy keys alone
+	_, _ = git.AddDeployKey(ctx, "org-proj", "flux", "ssh-ed25519 CCCC", true)
+	s.NoError(plugin.CreateEvent(ctx, event, NewPluginData()))
+	s.Len(git.repositories, 1)
+	s.Len(git.keys["org-proj"], 2)
+	s.Equal(key, git.keys["org-proj"][0])
+	s.Equal(credentials.PrivateKey, store.secrets["uuid-1"].PrivateKey)
+
+	// A deploy key whose secret is lost is replaced
+	delete(store.secrets, "uuid-1")
+	s.NoError(plugin.CreateEvent(ctx, event, NewPluginData()))
+	s.Equal([]int{key.ID}, git.deletedKeys)
+	s.Len(git.keys["org-proj"], 2)
+	s.Contains(store.secrets, "uuid-1")
+
+	// New credentials are issued on request
+	event.RefreshCredentials = true
+	s.NoError(plugin.CreateEvent(ctx, event, NewPluginData()))
+	s.Len(git.deletedKeys, 2)
+	event.RefreshCredentials = false
+
+	// Retained projects keep their repository without the deploy keys of the controller
+	event.EventType = "delete"
+	event.RetainData = true
+	s.NoError(plugin.DeleteEvent(ctx, event, NewPluginData()))
+	s.Contains(git.repositories, "org-proj")
+	s.Equal([]southbound.GitDeployKey{{ID: 3, Title: "flux", Key: "ssh-ed25519 CCCC", ReadOnly: true}}, git.keys["org-proj"])
+	s.Empty(store.secrets)
+
+	event.RetainData = false
+	s.NoError(plugin.DeleteEvent(ctx, event, NewPluginData()))
+	s.Empty(git.repositories)
+
+	// Deleting a project without a repository succeeds
+	s.NoError(plugin.DeleteEvent(ctx, event, NewPluginData()))
+	event.RetainData = true
+	s.NoError(plugin.DeleteEvent(ctx, event, NewPluginData()))
+}
+
+func (s *PluginsTestSuite) TestGitOpsPluginEnsure() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	git := newTestGitServer()
+	store := &testGitOpsSecretStore{secrets: map[string]southbound.GitOpsCredentials{}}
+	GitServerFactory = func(_ context.Context, _ config.Configuration) (GitServer, error) { return git, nil }
+	GitOpsSecretStoreFactory = func(_ config.Configuration) (GitOpsSecretStore, error) { return store, nil }
+	defer func() {
+		GitServerFactory = NewGitServer
+		GitOpsSecretStoreFactory = NewGitOpsSecretStore
+	}()
+
+	plugin := NewGitOpsProvisionerPlugin(config.Configuration{GitOps: config.GitOps{Provider: config.GitOpsProviderGitea, DeployKeyReadOnly: true}})
+	event := Event{EventType: "ensure", Organization: "Org", Name: "Proj", UUID: "uuid-1"}
+
+	// A missing repository is created with a new deploy key
+	s.NoError(plugin.EnsureEvent(ctx, event, NewPluginData()))
+	s.Contains(git.repositories, "org-proj")
+	s.Len(git.keys["org-proj"], 1)
+	credentials := store.secrets["uuid-1"]
+
+	// A deploy key removed from the repository is registered again with the stored key, and the other deploy keys
+	// of the controller are left in place
+	git.keys["org-proj"] = []southbound.GitDeployKey{{ID: 10, Title: "app-orch-tenant-controller", Key: "ssh-ed25519 CCCC", ReadOnly: true}}
+	pluginData := NewPluginData()
+	s.NoError(plugin.EnsureEvent(ctx, event, pluginData))
+	s.Empty(git.deletedKeys)
+	s.Len(git.keys["org-proj"], 2)
+	s.True(southbound.SameDeployKey(git.keys["org-proj"][1].Key, credentials.PublicKey))
+	s.Equal(credentials, store.secrets["uuid-1"])
+	s.Equal(git.keys["org-proj"][1].ID, pendingInventory(pluginData).GitRepository.DeployKeyID)
+}
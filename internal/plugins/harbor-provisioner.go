@@ -6,175 +6,792 @@ package plugins
 
 import (
 	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	nexushook "github.com/open-edge-platform/app-orch-tenant-controller/internal/nexus"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/retry"
 	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
 )
 
 type Harbor interface {
 	Configurations(ctx context.Context) error
-	CreateProject(ctx context.Context, org string, displayName string) error
+	CreateProject(ctx context.Context, org string, displayName string, storageLimit int64) error
+	SetProjectStorageLimit(ctx context.Context, org string, displayName string, storageLimit int64) error
+	SetProjectContentTrust(ctx context.Context, org string, displayName string, trust southbound.HarborContentTrust) error
 	SetMemberPermissions(ctx context.Context, roleID int, org string, displayName string, groupName string) error
-	CreateRobot(ctx context.Context, robotName string, org string, displayName string) (string, string, error)
+	SetMemberRole(ctx context.Context, roleID int, org string, displayName string, groupName string) error
+	CreateRobot(ctx context.Context, robotName string, org string, displayName string, access []config.HarborRobotAccess) (string, string, error)
 	GetProjectID(ctx context.Context, org string, displayName string) (int, error)
 	GetRobot(ctx context.Context, org string, displayName string, robotName string, projectID int) (*southbound.HarborRobot, error)
+	RefreshRobotSecret(ctx context.Context, robotID int) (string, error)
+	SetRobotDisabled(ctx context.Context, robot southbound.HarborRobot, disabled bool) error
 	DeleteRobot(ctx context.Context, robotID int) error
 	DeleteProject(ctx context.Context, org string, displayName string) error
+	ListRepositories(ctx context.Context, org string, displayName string) ([]southbound.HarborRepository, error)
+	DeleteRepository(ctx context.Context, org string, displayName string, repositoryName string) error
+	ListProjectLabels(ctx context.Context, projectID int) ([]southbound.HarborLabel, error)
+	SetProjectLabel(ctx context.Context, projectID int, label config.HarborLabel) (int, error)
+	DeleteLabel(ctx context.Context, labelID int) error
+	Statistics(ctx context.Context) (southbound.HarborStatistics, error)
 	Ping(ctx context.Context) error
+	NegotiateCapabilities(ctx context.Context) (southbound.HarborCapabilities, error)
+	SetRequestTimeout(timeout time.Duration)
+	ReloadCredentials(ctx context.Context) (bool, error)
+}
+
+// configurationBackoff is used to apply the Harbor configuration at startup
+var configurationBackoff = retry.Backoff{
+	Initial:  2 * time.Second,
+	Attempts: 3,
+	Jitter:   0.2,
 }
 
 type HarborProvisionerPlugin struct {
-	harbor                Harbor
-	harborHost            string
-	harborNamespace       string
-	harborAdminCredential string
-	oidcURL               string
+	harbor      Harbor
+	harborHost  string
+	adminSecret config.SecretRef
+	oidcURL     string
+	robotPolicy string
+	groups      config.HarborGroups
+	// access granted to the robot accounts, the defaults are used if unset
+	robotPermissions config.HarborRobotPermissions
+	// robot accounts created in the Harbor projects
+	robots []config.HarborRobot
+	// labels created in the Harbor projects
+	labels []config.HarborLabel
+	// bytes used by all Harbor projects above which new projects are refused, 0 for no limit
+	maxStorage int64
+	// configuration of the inventory store, projects are always provisioned in full if it has no pod namespace
+	inventory config.Configuration
 }
 
-func NewHarbor(ctx context.Context, harborHost string, oidcURL string, harborNamespace string, harborAdminCredential string) (Harbor, error) {
-	return southbound.NewHarborOCI(ctx, harborHost, oidcURL, harborNamespace, harborAdminCredential)
+func NewHarbor(ctx context.Context, harborHost string, oidcURL string, adminSecret config.SecretRef) (Harbor, error) {
+	return southbound.NewHarborOCI(ctx, harborHost, oidcURL, adminSecret)
 }
 
 var HarborFactory = NewHarbor
 
 func (p *HarborProvisionerPlugin) waitForHarbor(ctx context.Context) error {
 	log.Info("Waiting for Harbor")
-	harbor, err := HarborFactory(ctx, p.harborHost, p.oidcURL, p.harborNamespace, p.harborAdminCredential)
+	harbor, err := HarborFactory(ctx, p.harborHost, p.oidcURL, p.adminSecret)
 	if err != nil {
 		return fmt.Errorf("failed to create Harbor client: %w", err)
 	}
 
-	maxRetries := 12 // Total wait time: ~5 minutes with exponential backoff
-	retryDelay := 5 * time.Second
-
-	for attempt := 1; attempt <= maxRetries; attempt++ {
+	err = retry.Do(ctx, "Harbor ping", serviceBackoff, southbound.IsRetryable, func(ctx context.Context) error {
 		lctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-		err := harbor.Ping(lctx)
-		cancel()
-
-		if err == nil {
-			log.Info("Harbor ready")
-			return nil
-		}
-
-		if attempt == maxRetries {
-			return fmt.Errorf("harbor not available after %d attempts: %w", maxRetries, err)
-		}
-
-		log.Infof("Harbor ping failed (attempt %d/%d): %v. Retrying in %v...", attempt, maxRetries, err, retryDelay)
-		time.Sleep(retryDelay)
-
-		// Exponential backoff with cap at 60 seconds
-		retryDelay = retryDelay * 2
-		if retryDelay > 60*time.Second {
-			retryDelay = 60 * time.Second
-		}
+		defer cancel()
+		return harbor.Ping(lctx)
+	})
+	if err != nil {
+		return retryFailed("harbor not available", err)
 	}
-
-	return fmt.Errorf("harbor not available after maximum retries")
+	log.Info("Harbor ready")
+	return nil
 }
 
-func harborGroupName(event Event, kind string) string {
-	return fmt.Sprintf("%s_Edge-%s-Group", event.UUID, kind)
+// groupName returns the OIDC group given the role in the Harbor project of the event
+func (p *HarborProvisionerPlugin) groupName(event Event, role string) (string, error) {
+	name, err := p.groups.GroupName(event.Organization, event.Name, event.UUID, role)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", southbound.ErrPermanent, err)
+	}
+	return name, nil
 }
 
-func NewHarborProvisionerPlugin(ctx context.Context, harborHost string, oidcURL string, harborNamespace string, harborAdminCredential string) (*HarborProvisionerPlugin, error) {
-	harbor, err := HarborFactory(ctx, harborHost, oidcURL, harborNamespace, harborAdminCredential)
+func NewHarborProvisionerPlugin(ctx context.Context, harborHost string, oidcURL string, adminSecret config.SecretRef) (*HarborProvisionerPlugin, error) {
+	harbor, err := HarborFactory(ctx, harborHost, oidcURL, adminSecret)
 	if err != nil {
 		return nil, err
 	}
 	plugin := &HarborProvisionerPlugin{
-		harbor:                harbor,
-		harborHost:            harborHost,
-		oidcURL:               oidcURL,
-		harborNamespace:       harborNamespace,
-		harborAdminCredential: harborAdminCredential,
+		harbor:      harbor,
+		harborHost:  harborHost,
+		oidcURL:     oidcURL,
+		adminSecret: adminSecret,
+		robotPolicy: config.RobotPolicyRecreate,
+		robots:      config.DefaultHarborRobots,
 	}
 	return plugin, nil
 }
 
-func (p *HarborProvisionerPlugin) Initialize(ctx context.Context, _ PluginData) error {
+// WithRobotPolicy sets what happens to an existing robot account when a project is provisioned again.
+func (p *HarborProvisionerPlugin) WithRobotPolicy(robotPolicy string) *HarborProvisionerPlugin {
+	if robotPolicy != "" {
+		p.robotPolicy = robotPolicy
+	}
+	return p
+}
+
+// WithGroups sets the realms, roles and group name templates of the OIDC groups that are members of the Harbor
+// projects.
+func (p *HarborProvisionerPlugin) WithGroups(groups config.HarborGroups) *HarborProvisionerPlugin {
+	p.groups = groups
+	return p
+}
+
+// WithRobotPermissions sets the resources and actions granted to the robot accounts of the projects.
+func (p *HarborProvisionerPlugin) WithRobotPermissions(permissions config.HarborRobotPermissions) *HarborProvisionerPlugin {
+	p.robotPermissions = permissions
+	return p
+}
+
+// WithRobots sets the robot accounts created in the Harbor projects, the defaults are kept if robots is empty.
+func (p *HarborProvisionerPlugin) WithRobots(robots []config.HarborRobot) *HarborProvisionerPlugin {
+	if len(robots) > 0 {
+		p.robots = robots
+	}
+	return p
+}
+
+// WithLabels sets the labels created in the Harbor projects to classify their artifacts.
+func (p *HarborProvisionerPlugin) WithLabels(labels []config.HarborLabel) *HarborProvisionerPlugin {
+	p.labels = labels
+	return p
+}
+
+// WithMaxStorage sets the bytes used by all Harbor projects from which new projects are refused, see Admit.
+func (p *HarborProvisionerPlugin) WithMaxStorage(maxStorage int64) *HarborProvisionerPlugin {
+	p.maxStorage = maxStorage
+	return p
+}
+
+// WithInventory lets the plugin skip the projects whose Harbor project and robot accounts are recorded in the
+// inventory, see provisioned.
+func (p *HarborProvisionerPlugin) WithInventory(configuration config.Configuration) *HarborProvisionerPlugin {
+	p.inventory = configuration
+	return p
+}
+
+// ReloadHarborCredentials reads the Harbor admin credential again, so that events use the rotated credential.
+func (p *HarborProvisionerPlugin) ReloadHarborCredentials(ctx context.Context) (bool, error) {
+	return p.harbor.ReloadCredentials(ctx)
+}
+
+// WithRequestTimeout sets the time allowed for each Harbor REST call.
+func (p *HarborProvisionerPlugin) WithRequestTimeout(timeout time.Duration) *HarborProvisionerPlugin {
+	p.harbor.SetRequestTimeout(timeout)
+	return p
+}
+
+func (p *HarborProvisionerPlugin) Initialize(ctx context.Context, _ *PluginData) error {
 	if err := p.waitForHarbor(ctx); err != nil {
 		return fmt.Errorf("harbor initialization failed during ping check: %w", err)
 	}
 
-	// Retry configuration
-	maxRetries := 3
-	retryDelay := 2 * time.Second
-
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		err := p.harbor.Configurations(ctx)
+	err := retry.Do(ctx, "Harbor version check", configurationBackoff, southbound.IsRetryable, func(ctx context.Context) error {
+		capabilities, err := p.harbor.NegotiateCapabilities(ctx)
 		if err == nil {
-			log.Info("Harbor configuration applied successfully")
-			return nil
-		}
-
-		if attempt == maxRetries {
-			return fmt.Errorf("failed to apply harbor configuration after %d attempts: %w", maxRetries, err)
+			log.Infof("Harbor %s supported, capabilities %+v", capabilities.Version, capabilities)
 		}
+		return err
+	})
+	if err != nil {
+		return retryFailed("unsupported harbor", err)
+	}
 
-		log.Infof("Harbor configuration failed (attempt %d/%d): %v. Retrying in %v...", attempt, maxRetries, err, retryDelay)
-		time.Sleep(retryDelay)
-		retryDelay = retryDelay * 2
+	err = retry.Do(ctx, "Harbor configuration", configurationBackoff, southbound.IsRetryable, p.harbor.Configurations)
+	if err != nil {
+		return retryFailed("failed to apply harbor configuration", err)
 	}
+	log.Info("Harbor configuration applied successfully")
+	return nil
+}
 
-	return fmt.Errorf("harbor configuration failed after maximum retries")
+// Admit refuses a new project while the Harbor projects use the maximum storage or more, rather than creating a
+// project that its users cannot push to. Projects that already have a Harbor project are admitted.
+func (p *HarborProvisionerPlugin) Admit(ctx context.Context, event Event) error {
+	if p.maxStorage <= 0 {
+		return nil
+	}
+	statistics, err := p.harbor.Statistics(ctx)
+	if err != nil {
+		return err
+	}
+	if !atCapacity(statistics.TotalStorageConsumption, p.maxStorage) {
+		return nil
+	}
+	_, err = p.harbor.GetProjectID(ctx, strings.ToLower(event.Organization), strings.ToLower(event.Name))
+	if err == nil {
+		log.Warnf("Harbor storage is at its maximum, admitting project %s as its Harbor project exists", event.Name)
+		return nil
+	}
+	if southbound.IsRetryable(err) {
+		return err
+	}
+	return capacityExceeded(CapacityHarborStorage, "MAX_HARBOR_STORAGE", statistics.TotalStorageConsumption, p.maxStorage)
 }
 
-func (p *HarborProvisionerPlugin) CreateEvent(ctx context.Context, event Event, pluginData PluginData) error {
+func (p *HarborProvisionerPlugin) CreateEvent(ctx context.Context, event Event, pluginData *PluginData) error {
 	org := strings.ToLower(event.Organization)
 	name := strings.ToLower(event.Name)
-	err := p.harbor.CreateProject(ctx, org, name)
+	var storageLimit int64
+	if event.Profile != nil {
+		storageLimit = event.Profile.HarborStorageLimit
+	}
+	settings, err := p.settings(event, storageLimit)
+	if err != nil {
+		return err
+	}
+	if recorded := p.provisioned(ctx, event, org, name, settings); recorded != nil {
+		log.Infof("Harbor project %s is already provisioned, skipping", recorded.Name)
+		event.ReportProgress("Harbor project already provisioned")
+		p.setKeptCredentials(recorded, pluginData)
+		recordInventory(pluginData, func(inventory *southbound.Inventory) {
+			inventory.HarborProject = recorded
+		})
+		return nil
+	}
+
+	event.ReportProgress("Creating Harbor project")
+	err = p.harbor.CreateProject(ctx, org, name, storageLimit)
 	if err != nil {
 		return err
 	}
+	// The storage limit is only set when the project is created, an existing project may have another one
+	if event.EventType == "ensure" && storageLimit != 0 {
+		event.ReportProgress("Setting Harbor project storage limit")
+		if err := p.harbor.SetProjectStorageLimit(ctx, org, name, storageLimit); err != nil {
+			return err
+		}
+	}
+	// Projects without a provisioning profile keep the Harbor default, which accepts unsigned artifacts
+	if event.Profile != nil {
+		event.ReportProgress("Setting Harbor project signature enforcement")
+		if err := p.harbor.SetProjectContentTrust(ctx, org, name, contentTrust(event.Profile)); err != nil {
+			return err
+		}
+	}
 
-	operatorGroupName := harborGroupName(event, "Operator")
+	event.ReportProgress("Setting Harbor project member permissions")
+	for _, groupRole := range p.groups.GroupRoles() {
+		groupName, err := p.groupName(event, groupRole.Role)
+		if err != nil {
+			return err
+		}
+		err = p.harbor.SetMemberPermissions(ctx, groupRole.RoleID, org, name, groupName)
+		if err != nil {
+			return err
+		}
+	}
 
-	err = p.harbor.SetMemberPermissions(ctx, 3, org, name, operatorGroupName)
+	event.ReportProgress("Creating Harbor robot account")
+	projectID, err := p.harbor.GetProjectID(ctx, org, name)
 	if err != nil {
 		return err
 	}
 
-	managerGroupName := harborGroupName(event, "Manager")
+	robots := make([]southbound.InventoryRobot, 0, len(p.robots))
+	for _, robot := range p.robots {
+		username, secret, changed, err := p.provisionRobot(ctx, event, org, name, projectID, robot)
+		if err != nil {
+			return err
+		}
+		if robot.Catalog {
+			p.setCatalogCredentials(robot, HarborRobot{Username: username, Token: secret, Kept: !changed}, pluginData)
+		}
+		robots = append(robots, p.inventoryRobot(ctx, org, name, projectID, robot.Name, username))
+	}
+	// Robots removed from the configuration stay recorded, so that they are revoked with the project
+	if recorded := p.recordedHarbor(ctx, event); recorded != nil && recorded.Archived == nil {
+		for _, robot := range recorded.Robots {
+			if !slices.ContainsFunc(robots, func(r southbound.InventoryRobot) bool { return r.Name == robot.Name }) {
+				robots = append(robots, robot)
+			}
+		}
+	}
 
-	err = p.harbor.SetMemberPermissions(ctx, 4, org, name, managerGroupName)
+	labels, err := p.provisionLabels(ctx, event, projectID)
 	if err != nil {
 		return err
 	}
 
+	recordInventory(pluginData, func(inventory *southbound.Inventory) {
+		inventory.HarborProject = &southbound.InventoryHarbor{
+			ID:       projectID,
+			Name:     southbound.HarborProjectName(org, name),
+			Robots:   robots,
+			Labels:   labels,
+			Settings: settings,
+		}
+	})
+	return nil
+}
+
+// settings returns a digest of the settings a project is provisioned with, so that a project provisioned with other
+// settings is not skipped.
+func (p *HarborProvisionerPlugin) settings(event Event, storageLimit int64) (string, error) {
+	digest := sha256.New()
+	_, _ = fmt.Fprintf(digest, "storage %d\n", storageLimit)
+	// Only written when signatures are required, so that the digest of the projects provisioned before signature
+	// enforcement existed does not change
+	if event.Profile != nil && len(event.Profile.HarborSigning) > 0 {
+		_, _ = fmt.Fprintf(digest, "signing %s\n", strings.Join(event.Profile.HarborSigning, ","))
+	}
+	for _, groupRole := range p.groups.GroupRoles() {
+		groupName, err := p.groupName(event, groupRole.Role)
+		if err != nil {
+			return "", err
+		}
+		_, _ = fmt.Fprintf(digest, "member %d %s\n", groupRole.RoleID, groupName)
+	}
+	// The default robots are written as before robots were configurable, so that the digest of the projects
+	// provisioned with them does not change
+	if slices.Equal(p.robots, config.DefaultHarborRobots) {
+		_, _ = fmt.Fprintf(digest, "read-write %s\n", config.FormatRobotAccess(p.robotPermissions.ReadWriteAccess()))
+		_, _ = fmt.Fprintf(digest, "read-only %s\n", config.FormatRobotAccess(p.robotPermissions.PullAccess()))
+	} else {
+		for _, robot := range p.robots {
+			_, _ = fmt.Fprintf(digest, "robot %s %t %s\n", robot.Name, robot.Catalog, config.FormatRobotAccess(p.robotPermissions.Access(robot.Permissions)))
+		}
+	}
+	for _, label := range p.labels {
+		_, _ = fmt.Fprintf(digest, "label %s %s %s\n", label.Name, label.Color, label.Description)
+	}
+	return fmt.Sprintf("%x", digest.Sum(nil)), nil
+}
+
+// provisioned returns the Harbor project recorded in the inventory of the project if provisioning it again would
+// change nothing, so that replayed create events and ensure events skip the Harbor calls. This is the case when the
+// robot accounts are kept, as for ensure events or when they are reused and no new credentials are requested, the
+// project was provisioned with the same settings and the recorded Harbor project still exists. It returns nil if the
+// project must be provisioned.
+func (p *HarborProvisionerPlugin) provisioned(ctx context.Context, event Event, org string, name string, settings string) *southbound.InventoryHarbor {
+	keepRobots := event.EventType == "ensure" || (p.robotPolicy == config.RobotPolicyReuse && !event.RefreshCredentials)
+	if p.inventory.PodNamespace == "" || !keepRobots {
+		return nil
+	}
+	recorded := p.recordedHarbor(ctx, event)
+	if recorded == nil || recorded.Archived != nil || recorded.Name != southbound.HarborProjectName(org, name) ||
+		recorded.Settings != settings || !p.robotsRecorded(recorded) {
+		return nil
+	}
 	projectID, err := p.harbor.GetProjectID(ctx, org, name)
+	if err != nil || projectID != recorded.ID {
+		return nil
+	}
+	return recorded
+}
+
+// recordedHarbor returns the Harbor project recorded in the inventory of the project, or nil if there is none or the
+// inventory cannot be loaded.
+func (p *HarborProvisionerPlugin) recordedHarbor(ctx context.Context, event Event) *southbound.InventoryHarbor {
+	if p.inventory.PodNamespace == "" {
+		return nil
+	}
+	store, err := InventoryStoreFactory(p.inventory)
+	if err != nil {
+		log.Warnf("Unable to load the inventory of project %s: %v", event.Name, err)
+		return nil
+	}
+	inventory, err := store.Load(ctx, event.UUID)
+	if err != nil {
+		log.Warnf("Unable to load the inventory of project %s: %v", event.Name, err)
+		return nil
+	}
+	if inventory == nil {
+		return nil
+	}
+	return inventory.HarborProject
+}
+
+// projectRobots returns the names of the robot accounts of the project: the configured robots, and the robots
+// recorded in its inventory that were removed from the configuration since it was provisioned.
+func (p *HarborProvisionerPlugin) projectRobots(ctx context.Context, event Event) []string {
+	names := make([]string, 0, len(p.robots))
+	for _, robot := range p.robots {
+		names = append(names, robot.Name)
+	}
+	if recorded := p.recordedHarbor(ctx, event); recorded != nil {
+		for _, robot := range recorded.Robots {
+			// Harbor prefixes the name of the robot with robot$ and the project name
+			_, robotName, found := strings.Cut(robot.Name, "+")
+			if found && !slices.Contains(names, robotName) {
+				names = append(names, robotName)
+			}
+		}
+	}
+	return names
+}
+
+// recordedRobot returns the full name of the robot account recorded in the inventory with the given name, Harbor
+// prefixes it with robot$ and the project name. It returns an empty string if the robot is not recorded.
+func recordedRobot(recorded *southbound.InventoryHarbor, robotName string) string {
+	for _, robot := range recorded.Robots {
+		if strings.HasSuffix(robot.Name, "+"+robotName) {
+			return robot.Name
+		}
+	}
+	return ""
+}
+
+// robotsRecorded reports whether every configured robot account is recorded in the inventory.
+func (p *HarborProvisionerPlugin) robotsRecorded(recorded *southbound.InventoryHarbor) bool {
+	for _, robot := range p.robots {
+		if recordedRobot(recorded, robot.Name) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// setCatalogCredentials passes on the credentials of a robot account given to the catalog, as the read-write or
+// pull credentials depending on its permission profile.
+func (p *HarborProvisionerPlugin) setCatalogCredentials(robot config.HarborRobot, credentials HarborRobot, pluginData *PluginData) {
+	if robot.Permissions == config.RobotProfilePull {
+		pluginData.SetHarborPullCredentials(credentials)
+		return
+	}
+	pluginData.SetHarborCredentials(credentials)
+}
+
+// setKeptCredentials passes on the robot accounts given to the catalog that are recorded in the inventory as kept.
+func (p *HarborProvisionerPlugin) setKeptCredentials(recorded *southbound.InventoryHarbor, pluginData *PluginData) {
+	for _, robot := range p.robots {
+		if username := recordedRobot(recorded, robot.Name); robot.Catalog && username != "" {
+			p.setCatalogCredentials(robot, HarborRobot{Username: username, Kept: true}, pluginData)
+		}
+	}
+}
+
+// inventoryRobot looks up the ID of a provisioned robot account for the inventory. The ID is left out if the
+// lookup fails, it is not needed to use the robot.
+func (p *HarborProvisionerPlugin) inventoryRobot(ctx context.Context, org string, name string, projectID int, robotName string, username string) southbound.InventoryRobot {
+	robot := southbound.InventoryRobot{Name: username}
+	harborRobot, err := p.harbor.GetRobot(ctx, org, name, robotName, projectID)
+	if err != nil {
+		log.Warnf("Unable to look up robot %s for the inventory of project %s: %v", username, name, err)
+		return robot
+	}
+	robot.ID = harborRobot.ID
+	return robot
+}
+
+// provisionLabels creates the configured labels in the Harbor project, or updates the labels that exist with another
+// description or color, and returns them for the inventory. Labels that are no longer configured are left alone, as
+// artifacts may still be labeled with them.
+func (p *HarborProvisionerPlugin) provisionLabels(ctx context.Context, event Event, projectID int) ([]southbound.InventoryHarborLabel, error) {
+	if len(p.labels) == 0 {
+		return nil, nil
+	}
+	event.ReportProgress("Creating Harbor project labels")
+	labels := make([]southbound.InventoryHarborLabel, 0, len(p.labels))
+	for _, label := range p.labels {
+		id, err := p.harbor.SetProjectLabel(ctx, projectID, label)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, southbound.InventoryHarborLabel{Name: label.Name, ID: id})
+	}
+	return labels, nil
+}
+
+// deleteLabels deletes the labels of the Harbor project before the project itself, so that none of them is left
+// behind in Harbor. A project that cannot be found has nothing left to delete.
+func (p *HarborProvisionerPlugin) deleteLabels(ctx context.Context, event Event, org string, name string) error {
+	projectID, err := p.harbor.GetProjectID(ctx, org, name)
+	if err != nil {
+		if southbound.IsRetryable(err) {
+			return err
+		}
+		log.Infof("Harbor project of project %s not found, no labels to delete: %v", event.Name, err)
+		return nil
+	}
+	labels, err := p.harbor.ListProjectLabels(ctx, projectID)
 	if err != nil {
 		return err
 	}
+	if len(labels) > 0 {
+		event.ReportProgress("Deleting Harbor project labels")
+	}
+	for _, label := range labels {
+		log.Infof("Deleting label %s of project %s", label.Name, event.Name)
+		if err := p.harbor.DeleteLabel(ctx, label.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	robot, _ := p.harbor.GetRobot(ctx, org, name, "catalog-apps-read-write", projectID)
-	if robot != nil {
-		err = p.harbor.DeleteRobot(ctx, robot.ID)
+// provisionRobot applies the robot policy to the configured robot account, creating it with the access of its
+// permission profile if needed. The access of a reused robot is not changed. It returns the full name and secret of
+// the robot, and whether the secret changed. The secret is empty if an existing robot was reused without refreshing
+// it. Ensure events always reuse an existing robot, and so does any event for a robot not given to the catalog: its
+// secret is not stored, so a new one would break the credentials its users copied from Harbor.
+func (p *HarborProvisionerPlugin) provisionRobot(ctx context.Context, event Event, org string, name string, projectID int,
+	configured config.HarborRobot,
+) (string, string, bool, error) {
+	robot, err := p.harbor.GetRobot(ctx, org, name, configured.Name, projectID)
+	if err != nil && !errors.Is(err, southbound.ErrNotFound) {
+		return "", "", false, err
+	}
+	if robot != nil && (event.EventType == "ensure" || !configured.Catalog) {
+		log.Infof("Keeping robot %s for project %s", robot.Name, event.Name)
+		return robot.Name, "", false, nil
+	}
+	if robot != nil && p.robotPolicy == config.RobotPolicyReuse {
+		if !event.RefreshCredentials {
+			log.Infof("Reusing robot %s for project %s", robot.Name, event.Name)
+			return robot.Name, "", false, nil
+		}
+		log.Infof("Refreshing secret of robot %s for project %s", robot.Name, event.Name)
+		secret, err := p.harbor.RefreshRobotSecret(ctx, robot.ID)
 		if err != nil {
+			return "", "", false, err
+		}
+		return robot.Name, secret, true, nil
+	}
+	if robot != nil {
+		if err := p.harbor.DeleteRobot(ctx, robot.ID); err != nil {
+			return "", "", false, err
+		}
+	}
+
+	access := p.robotPermissions.Access(configured.Permissions)
+	log.Infof("Creating robot %s for project %s with access %s", configured.Name, event.Name, config.FormatRobotAccess(access))
+	username, secret, err := p.harbor.CreateRobot(ctx, configured.Name, org, name, access)
+	if err != nil {
+		return "", "", false, err
+	}
+	return username, secret, true, nil
+}
+
+// UpdateEvent applies the Harbor storage limit and signature enforcement of a newly selected provisioning profile
+// to the existing project.
+func (p *HarborProvisionerPlugin) UpdateEvent(ctx context.Context, event Event, _ *PluginData) error {
+	if !event.Changes.AnnotationChanged(nexushook.ProvisioningProfileAnnotationKey) {
+		return nil
+	}
+	if event.Profile == nil {
+		log.Infof("No provisioning profile applies to project %s, leaving the Harbor project unchanged", event.Name)
+		return nil
+	}
+	org := strings.ToLower(event.Organization)
+	name := strings.ToLower(event.Name)
+	if event.Profile.HarborStorageLimit == 0 {
+		log.Infof("Provisioning profile for project %s has no Harbor storage limit, leaving quota unchanged", event.Name)
+	} else {
+		event.ReportProgress("Updating Harbor project storage limit")
+		if err := p.harbor.SetProjectStorageLimit(ctx, org, name, event.Profile.HarborStorageLimit); err != nil {
 			return err
 		}
 	}
+	event.ReportProgress("Updating Harbor project signature enforcement")
+	return p.harbor.SetProjectContentTrust(ctx, org, name, contentTrust(event.Profile))
+}
+
+// EnsureEvent converges the Harbor project: the project and its robot accounts are created if they are missing, and
+// the storage limit, signature enforcement and members of the project are applied again. Existing robot accounts are
+// kept with their secrets, whatever the robot policy.
+func (p *HarborProvisionerPlugin) EnsureEvent(ctx context.Context, event Event, pluginData *PluginData) error {
+	return p.CreateEvent(ctx, event, pluginData)
+}
 
-	name, secret, err := p.harbor.CreateRobot(ctx, `catalog-apps-read-write`, org, name)
+// LoadProvisioned passes on the robot accounts recorded in the inventory of the project as kept, so that the
+// catalog registries that use them are kept as they are. Harbor is not called. Nothing is passed on if the project
+// has no Harbor project recorded.
+func (p *HarborProvisionerPlugin) LoadProvisioned(ctx context.Context, event Event, pluginData *PluginData) error {
+	if p.inventory.PodNamespace == "" {
+		return nil
+	}
+	store, err := InventoryStoreFactory(p.inventory)
+	if err != nil {
+		return err
+	}
+	inventory, err := store.Load(ctx, event.UUID)
 	if err != nil {
 		return err
 	}
+	if inventory == nil || inventory.HarborProject == nil || inventory.HarborProject.Archived != nil || !p.robotsRecorded(inventory.HarborProject) {
+		log.Infof("No Harbor project is recorded for project %s, the Harbor robot accounts are not known", event.Name)
+		return nil
+	}
+	p.setKeptCredentials(inventory.HarborProject, pluginData)
+	return nil
+}
 
-	(*pluginData)[HarborUsernameName] = name
-	(*pluginData)[HarborTokenName] = secret
+// contentTrust returns the signatures that the Harbor projects of the profile require.
+func contentTrust(profile *config.ProvisioningProfile) southbound.HarborContentTrust {
+	return southbound.HarborContentTrust{
+		Cosign:   profile.RequiresSignature(config.HarborSigningCosign),
+		Notation: profile.RequiresSignature(config.HarborSigningNotation),
+	}
+}
 
+// purgeRepositories removes every repository from the project. Harbor refuses to delete a project
+// that still contains repositories, so this must be done before the project itself is deleted.
+func (p *HarborProvisionerPlugin) purgeRepositories(ctx context.Context, event Event, org string, name string) error {
+	repositories, err := p.harbor.ListRepositories(ctx, org, name)
+	if err != nil {
+		return err
+	}
+	for i, repository := range repositories {
+		event.ReportProgress("Purging Harbor project repositories %d/%d", i+1, len(repositories))
+		log.Infof("Deleting repository %s with %d artifacts", repository.Name, repository.ArtifactCount)
+		err = p.harbor.DeleteRepository(ctx, org, name, repository.Name)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (p *HarborProvisionerPlugin) DeleteEvent(ctx context.Context, event Event, _ PluginData) error {
+func (p *HarborProvisionerPlugin) DeleteEvent(ctx context.Context, event Event, pluginData *PluginData) error {
 	org := strings.ToLower(event.Organization)
 	name := strings.ToLower(event.Name)
+	if event.RetainData {
+		return p.archiveProject(ctx, event, org, name, pluginData)
+	}
+	event.ReportProgress("Purging Harbor project repositories")
+	if err := p.purgeRepositories(ctx, event, org, name); err != nil {
+		return err
+	}
+	if err := p.deleteLabels(ctx, event, org, name); err != nil {
+		return err
+	}
+	event.ReportProgress("Deleting Harbor project")
 	return p.harbor.DeleteProject(ctx, org, name)
 }
 
+// archiveProject keeps the Harbor project of a deleted project with its repositories, so that the images can be
+// recovered by creating a project with the same name again. Harbor cannot rename projects, so the project keeps its
+// name; the robot accounts, including the recorded robots that are no longer configured, are revoked so that nothing
+// can push or pull with the credentials of the deleted project, and the archive time is recorded in the inventory.
+func (p *HarborProvisionerPlugin) archiveProject(ctx context.Context, event Event, org string, name string, pluginData *PluginData) error {
+	event.ReportProgress("Archiving Harbor project")
+	projectID, err := p.harbor.GetProjectID(ctx, org, name)
+	if err != nil {
+		return err
+	}
+	for _, robotName := range p.projectRobots(ctx, event) {
+		robot, err := p.harbor.GetRobot(ctx, org, name, robotName, projectID)
+		if errors.Is(err, southbound.ErrNotFound) || (err == nil && robot == nil) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		log.Infof("Revoking robot %s of archived project %s", robot.Name, event.Name)
+		if err := p.harbor.DeleteRobot(ctx, robot.ID); err != nil {
+			return err
+		}
+	}
+
+	archived := time.Now().UTC()
+	log.Infof("Archived Harbor project %s at %s", southbound.HarborProjectName(org, name), archived.Format(time.RFC3339))
+	recordInventory(pluginData, func(inventory *southbound.Inventory) {
+		inventory.HarborProject = &southbound.InventoryHarbor{
+			ID:       projectID,
+			Name:     southbound.HarborProjectName(org, name),
+			Archived: &archived,
+		}
+	})
+	return nil
+}
+
+// DeactivateEvent disables the Harbor project of a project deleted with a retention period, keeping its data. The
+// robot accounts are disabled, which also stops the catalog registries that use them. Harbor has no read-only
+// setting for a project, so its member groups are given the limited guest role, which can only pull.
+func (p *HarborProvisionerPlugin) DeactivateEvent(ctx context.Context, event Event, _ *PluginData) error {
+	event.ReportProgress("Deactivating Harbor project")
+	return p.setActive(ctx, event, false)
+}
+
+// ReactivateEvent enables the robot accounts of a deactivated Harbor project again, with the secrets they had, and
+// gives the member groups their roles back.
+func (p *HarborProvisionerPlugin) ReactivateEvent(ctx context.Context, event Event, pluginData *PluginData) error {
+	event.ReportProgress("Reactivating Harbor project")
+	if err := p.setActive(ctx, event, true); err != nil {
+		return err
+	}
+	return p.refreshCatalogCredentials(ctx, event, pluginData)
+}
+
+// refreshCatalogCredentials issues new secrets for the robot accounts given to the catalog and passes them on, as
+// the catalog registries lost their credentials when the project was deactivated. Only the catalog holds the secrets
+// of these robots, so nothing else is affected.
+func (p *HarborProvisionerPlugin) refreshCatalogCredentials(ctx context.Context, event Event, pluginData *PluginData) error {
+	org := strings.ToLower(event.Organization)
+	name := strings.ToLower(event.Name)
+	projectID, err := p.harbor.GetProjectID(ctx, org, name)
+	if err != nil {
+		return err
+	}
+	for _, configured := range p.robots {
+		if !configured.Catalog {
+			continue
+		}
+		robot, err := p.harbor.GetRobot(ctx, org, name, configured.Name, projectID)
+		if errors.Is(err, southbound.ErrNotFound) || (err == nil && robot == nil) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		secret, err := p.harbor.RefreshRobotSecret(ctx, robot.ID)
+		if err != nil {
+			return err
+		}
+		log.Infof("Refreshed secret of robot %s for project %s", robot.Name, event.Name)
+		p.setCatalogCredentials(configured, HarborRobot{Username: robot.Name, Token: secret}, pluginData)
+	}
+	return nil
+}
+
+// setActive enables or disables the robot accounts of the Harbor project, including the recorded robots that are no
+// longer configured, and sets the roles of its member groups.
+// Robots and groups that are missing are skipped when deactivating; groups are made members again when
+// reactivating.
+func (p *HarborProvisionerPlugin) setActive(ctx context.Context, event Event, active bool) error {
+	org := strings.ToLower(event.Organization)
+	name := strings.ToLower(event.Name)
+	projectID, err := p.harbor.GetProjectID(ctx, org, name)
+	if err != nil {
+		return err
+	}
+	for _, robotName := range p.projectRobots(ctx, event) {
+		robot, err := p.harbor.GetRobot(ctx, org, name, robotName, projectID)
+		if errors.Is(err, southbound.ErrNotFound) || (err == nil && robot == nil) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		log.Infof("Setting robot %s of project %s to disabled %v", robot.Name, event.Name, !active)
+		if err := p.harbor.SetRobotDisabled(ctx, *robot, !active); err != nil {
+			return err
+		}
+	}
+
+	for _, groupRole := range p.groups.GroupRoles() {
+		groupName, err := p.groupName(event, groupRole.Role)
+		if err != nil {
+			return err
+		}
+		roleID := config.HarborRoleLimitedGuest
+		if active {
+			roleID = groupRole.RoleID
+			if err := p.harbor.SetMemberPermissions(ctx, roleID, org, name, groupName); err != nil {
+				return err
+			}
+		}
+		err = p.harbor.SetMemberRole(ctx, roleID, org, name, groupName)
+		if errors.Is(err, southbound.ErrNotFound) && !active {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (p *HarborProvisionerPlugin) Name() string {
 	return "Harbor Provisioner"
 }
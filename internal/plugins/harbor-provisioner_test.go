@@ -7,9 +7,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
+	"slices"
 	"time"
 
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	nexushook "github.com/open-edge-platform/app-orch-tenant-controller/internal/nexus"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/retry/retrytest"
 	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
 	"github.com/stretchr/testify/assert"
 )
@@ -21,7 +24,7 @@ func (s *PluginsTestSuite) TestHarborPluginCreate() {
 	HarborFactory = NewTestHarbor
 	CatalogFactory = newTestCatalog
 
-	plugin, err := NewHarborProvisionerPlugin(ctx, "", "", "harbor", "credential")
+	plugin, err := NewHarborProvisionerPlugin(ctx, "", "", testAdminSecret)
 	s.NoError(err, "Cannot create harbor provisioner plugin")
 	s.NotNil(plugin)
 
@@ -29,7 +32,7 @@ func (s *PluginsTestSuite) TestHarborPluginCreate() {
 	err = Initialize(ctx)
 	assert.NoError(s.T(), err, "Initialize harbor plugin")
 
-	err = Dispatch(ctx, Event{
+	_, err = Dispatch(ctx, Event{
 		EventType:    "create",
 		Name:         "fOo",
 		Organization: "xYzzY",
@@ -39,32 +42,808 @@ func (s *PluginsTestSuite) TestHarborPluginCreate() {
 	s.Len(testHarborInstance.createdProjects, 1)
 	createdProject := testHarborInstance.createdProjects[`xyzzy-foo`]
 	s.Equal(`xyzzy-foo`, createdProject)
+	s.Equal(int64(0), testHarborInstance.storageLimits[`xyzzy-foo`])
 
 	expectedRobotName := `robot$catalog-apps-xyzzy-foo+catalog-apps-read-write`
-	s.Len(testHarborInstance.robots, 1)
+	expectedPullRobotName := `robot$catalog-apps-xyzzy-foo+catalog-apps-read-only`
+	s.Len(testHarborInstance.robots, 2)
 	r := testHarborInstance.robots[expectedRobotName]
 	s.Equal(expectedRobotName, r.robotName)
 	s.Equal(1, r.robotID)
+	s.Equal(config.DefaultHarborReadWriteAccess, r.access)
+	pr := testHarborInstance.robots[expectedPullRobotName]
+	s.Equal(expectedPullRobotName, pr.robotName)
+	s.Equal(config.DefaultHarborPullAccess, pr.access)
 
-	err = Dispatch(ctx, Event{
+	_, err = Dispatch(ctx, Event{
 		EventType:    "create",
 		Name:         "fOo",
 		Organization: "xYzzY",
 	}, nil)
 	s.NoError(err)
-	s.Len(testHarborInstance.robots, 1)
+	s.Len(testHarborInstance.robots, 2)
 	r2 := testHarborInstance.robots[expectedRobotName]
 	s.Equal(expectedRobotName, r2.robotName)
-	s.Equal(2, r2.robotID)
+	s.Equal(3, r2.robotID)
+	s.Equal(4, testHarborInstance.robots[expectedPullRobotName].robotID)
+
+	// Without a provisioning profile the signature enforcement of Harbor is left alone
+	s.NotContains(testHarborInstance.contentTrust, `xyzzy-foo`)
+
+	// A provisioning profile sets the project quota and signature enforcement
+	_, err = Dispatch(ctx, Event{
+		EventType:    "create",
+		Name:         "fOo",
+		Organization: "xYzzY",
+		Profile:      &config.ProvisioningProfile{Name: "premium", HarborStorageLimit: 1 << 30, HarborSigning: []string{config.HarborSigningCosign}},
+	}, nil)
+	s.NoError(err)
+	s.Equal(int64(1<<30), testHarborInstance.storageLimits[`xyzzy-foo`])
+	s.Equal(southbound.HarborContentTrust{Cosign: true}, testHarborInstance.contentTrust[`xyzzy-foo`])
+
+	// Push some content into the project; it must be purged before the project can be deleted
+	testHarborInstance.repositories[`catalog-apps-xyzzy-foo/charts/app`] = `xyzzy-foo`
+	testHarborInstance.repositories[`catalog-apps-xyzzy-foo/images/app`] = `xyzzy-foo`
 
 	// Now delete the project
-	err = Dispatch(ctx, Event{
+	_, err = Dispatch(ctx, Event{
 		EventType:    "delete",
 		Name:         "fOo",
 		Organization: "xYzzY",
 	}, nil)
 	s.NoError(err)
 	s.Len(testHarborInstance.createdProjects, 0)
+	s.Len(testHarborInstance.repositories, 0)
+}
+
+func (s *PluginsTestSuite) TestHarborPluginRetainData() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	store := &testInventoryStore{inventories: map[string]*southbound.Inventory{}}
+	InventoryStoreFactory = func(_ config.Configuration) (InventoryStore, error) { return store, nil }
+	defer func() { InventoryStoreFactory = NewInventoryStore }()
+	testHarborInstance = nil
+	HarborFactory = NewTestHarbor
+
+	plugin, err := NewHarborProvisionerPlugin(ctx, "", "", testAdminSecret)
+	s.NoError(err)
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+	Register(plugin)
+	Register(NewInventoryRecorderPlugin(config.Configuration{}))
+
+	event := Event{
+		EventType:    "create",
+		Name:         "fOo",
+		Organization: "xYzzY",
+		UUID:         "uuid-retain",
+	}
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	s.Len(testHarborInstance.robots, 2)
+	testHarborInstance.repositories[`catalog-apps-xyzzy-foo/images/app`] = `xyzzy-foo`
+
+	// The project is archived: its repositories are kept and its robots revoked
+	event.EventType = "delete"
+	event.RetainData = true
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	s.Contains(testHarborInstance.createdProjects, `xyzzy-foo`)
+	s.Len(testHarborInstance.repositories, 1)
+	s.Empty(testHarborInstance.robots)
+
+	inventory := store.inventories["uuid-retain"]
+	s.NotNil(inventory)
+	s.Equal("catalog-apps-xyzzy-foo", inventory.HarborProject.Name)
+	s.Empty(inventory.HarborProject.Robots)
+	s.NotNil(inventory.HarborProject.Archived)
+	s.Empty(inventory.CatalogRegistries)
+
+	// Archiving again finds no robots to revoke
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+
+	// Creating the project again recovers the images with new robots
+	event.EventType = "create"
+	event.RetainData = false
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	s.Len(testHarborInstance.robots, 2)
+	s.Len(testHarborInstance.repositories, 1)
+	s.Nil(store.inventories["uuid-retain"].HarborProject.Archived)
+
+	delete(testHarborInstance.repositories, `catalog-apps-xyzzy-foo/images/app`)
+}
+
+func (s *PluginsTestSuite) TestHarborPluginSkipsProvisionedProject() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	store := &testInventoryStore{inventories: map[string]*southbound.Inventory{}}
+	InventoryStoreFactory = func(_ config.Configuration) (InventoryStore, error) { return store, nil }
+	defer func() { InventoryStoreFactory = NewInventoryStore }()
+	testHarborInstance = nil
+	HarborFactory = NewTestHarbor
+
+	configuration := config.Configuration{PodNamespace: "orch-app"}
+	plugin, err := NewHarborProvisionerPlugin(ctx, "", "", testAdminSecret)
+	s.NoError(err)
+	plugin.WithRobotPolicy(config.RobotPolicyReuse).WithInventory(configuration)
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+	Register(plugin)
+	Register(NewInventoryRecorderPlugin(configuration))
+
+	event := Event{
+		EventType:    "create",
+		Name:         "fOo",
+		Organization: "xYzzY",
+		UUID:         "uuid-fast",
+	}
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	recorded := store.inventories["uuid-fast"].HarborProject
+	s.NotEmpty(recorded.Settings)
+	permissions := len(testHarborInstance.permissions)
+
+	// A replayed event finds the project recorded with the same settings and makes no changes in Harbor
+	delete(testHarborInstance.createdProjects, "xyzzy-foo")
+	pluginData := NewPluginData()
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	s.NotContains(testHarborInstance.createdProjects, "xyzzy-foo")
+	s.Len(testHarborInstance.permissions, permissions)
+	s.Equal(HarborRobot{Username: "robot$catalog-apps-xyzzy-foo+catalog-apps-read-write", Kept: true}, pluginData.HarborCredentials())
+	s.Equal(HarborRobot{Username: "robot$catalog-apps-xyzzy-foo+catalog-apps-read-only", Kept: true}, pluginData.HarborPullCredentials())
+	s.Equal(recorded, pendingInventory(pluginData).HarborProject)
+
+	// Requesting new credentials provisions the project
+	event.RefreshCredentials = true
+	s.NoError(plugin.CreateEvent(ctx, event, NewPluginData()))
+	s.Contains(testHarborInstance.createdProjects, "xyzzy-foo")
+	event.RefreshCredentials = false
+
+	// So does a change of the settings, such as the storage limit of the provisioning profile
+	delete(testHarborInstance.createdProjects, "xyzzy-foo")
+	event.Profile = &config.ProvisioningProfile{HarborStorageLimit: 1024}
+	s.NoError(plugin.CreateEvent(ctx, event, NewPluginData()))
+	s.Contains(testHarborInstance.createdProjects, "xyzzy-foo")
+	event.Profile = nil
+
+	// Or the signatures it requires. A profile that requires none keeps the settings of a project without a profile
+	delete(testHarborInstance.createdProjects, "xyzzy-foo")
+	event.Profile = &config.ProvisioningProfile{HarborSigning: []string{config.HarborSigningCosign}}
+	s.NoError(plugin.CreateEvent(ctx, event, NewPluginData()))
+	s.Contains(testHarborInstance.createdProjects, "xyzzy-foo")
+	signed, err := plugin.settings(event, 0)
+	s.NoError(err)
+	s.NotEqual(recorded.Settings, signed)
+	event.Profile = &config.ProvisioningProfile{}
+	unsigned, err := plugin.settings(event, 0)
+	s.NoError(err)
+	s.Equal(recorded.Settings, unsigned)
+	event.Profile = nil
+
+	// And a Harbor project that does not match the inventory
+	delete(testHarborInstance.createdProjects, "xyzzy-foo")
+	store.inventories["uuid-fast"].HarborProject.ID++
+	s.NoError(plugin.CreateEvent(ctx, event, NewPluginData()))
+	s.Contains(testHarborInstance.createdProjects, "xyzzy-foo")
+
+	// Robots that are recreated every time are never skipped
+	store.inventories["uuid-fast"].HarborProject.ID--
+	delete(testHarborInstance.createdProjects, "xyzzy-foo")
+	plugin.WithRobotPolicy(config.RobotPolicyRecreate)
+	s.NoError(plugin.CreateEvent(ctx, event, NewPluginData()))
+	s.Contains(testHarborInstance.createdProjects, "xyzzy-foo")
+}
+
+func (s *PluginsTestSuite) TestHarborPluginLoadProvisioned() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	store := &testInventoryStore{inventories: map[string]*southbound.Inventory{}}
+	InventoryStoreFactory = func(_ config.Configuration) (InventoryStore, error) { return store, nil }
+	defer func() { InventoryStoreFactory = NewInventoryStore }()
+	testHarborInstance = nil
+	HarborFactory = NewTestHarbor
+	mockCatalog = testCatalog{}
+	CatalogFactory = newTestCatalog
+
+	configuration := config.Configuration{PodNamespace: "orch-app", ReleaseServiceRootURL: "oci://release-service-root.root.io"}
+	harbor, err := NewHarborProvisionerPlugin(ctx, "", "", testAdminSecret)
+	s.NoError(err)
+	harbor.WithInventory(configuration)
+	catalog, err := NewCatalogProvisionerPlugin(configuration)
+	s.NoError(err)
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+	Register(harbor)
+	Register(catalog)
+	Register(NewInventoryRecorderPlugin(configuration))
+
+	event := Event{EventType: "create", Name: "web", Organization: "acme", UUID: "uuid-partial"}
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	robots := len(testHarborInstance.robots)
+	harborRegistry := mockCatalog.registries["harbor-helm-oci"]
+
+	// Only the catalog is provisioned again: Harbor is not called, the registries that use the robot accounts are
+	// kept as they are, and the missing registry is created again
+	delete(testHarborInstance.createdProjects, "acme-web")
+	delete(mockCatalog.registries, "intel-rs-helm")
+	event.EventType = "ensure"
+	event.Plugins = []string{"catalog"}
+	result, err := Dispatch(ctx, event, nil)
+	s.NoError(err)
+	s.Empty(result.Warnings)
+	s.Equal(PluginSkipped, result.Plugin(harbor.Name()).Status)
+	s.NotContains(testHarborInstance.createdProjects, "acme-web")
+	s.Len(testHarborInstance.robots, robots)
+	s.Contains(mockCatalog.registries, "intel-rs-helm")
+	s.Equal(harborRegistry, mockCatalog.registries["harbor-helm-oci"])
+	s.NotNil(store.inventories["uuid-partial"].HarborProject)
+	s.Contains(store.inventories["uuid-partial"].CatalogRegistries, "intel-rs-helm")
+
+	// Without a recorded Harbor project the robot accounts are not known
+	pluginData := NewPluginData()
+	s.NoError(harbor.LoadProvisioned(ctx, Event{UUID: "unknown"}, pluginData))
+	s.Equal(HarborRobot{}, pluginData.HarborCredentials())
+}
+
+func (s *PluginsTestSuite) TestHarborPluginDeactivate() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	store := &testInventoryStore{inventories: map[string]*southbound.Inventory{}}
+	InventoryStoreFactory = func(_ config.Configuration) (InventoryStore, error) { return store, nil }
+	defer func() { InventoryStoreFactory = NewInventoryStore }()
+	testHarborInstance = nil
+	HarborFactory = NewTestHarbor
+
+	plugin, err := NewHarborProvisionerPlugin(ctx, "", "", testAdminSecret)
+	s.NoError(err)
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+	createOnly := &recordingPlugin{}
+	Register(plugin)
+	Register(createOnly)
+	Register(NewInventoryRecorderPlugin(config.Configuration{}))
+
+	event := Event{
+		EventType:    "create",
+		Name:         "fOo",
+		Organization: "xYzzY",
+		UUID:         "uuid-deactivate",
+	}
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	testHarborInstance.repositories[`catalog-apps-xyzzy-foo/images/app`] = `xyzzy-foo`
+	defer delete(testHarborInstance.repositories, `catalog-apps-xyzzy-foo/images/app`)
+
+	// The robots are disabled and the members can only pull, the data is kept
+	event.EventType = "deactivate"
+	event.PurgeAfter = time.Now().Add(time.Hour)
+	s.NoError(event.Validate())
+	result, err := Dispatch(ctx, event, nil)
+	s.NoError(err)
+	s.Equal(PluginSkipped, result.Plugin("Recording").Status)
+	s.Equal([]string{"create"}, createOnly.events)
+	s.Len(testHarborInstance.robots, 2)
+	for _, robot := range testHarborInstance.robots {
+		s.True(robot.disabled, robot.robotName)
+	}
+	s.Equal([]permission{
+		{roleID: config.HarborRoleLimitedGuest, groupName: "uuid-deactivate_Edge-Operator-Group", projectID: "foo"},
+		{roleID: config.HarborRoleLimitedGuest, groupName: "uuid-deactivate_Edge-Manager-Group", projectID: "foo"},
+	}, testHarborInstance.permissions)
+	s.Len(testHarborInstance.repositories, 1)
+	inventory := store.inventories["uuid-deactivate"]
+	s.Require().NotNil(inventory.Deactivation)
+	s.True(inventory.Deactivation.PurgeAfter.Equal(event.PurgeAfter))
+	s.NotNil(inventory.HarborProject)
+
+	// Reactivating enables the robots with their secrets and gives the members their roles back
+	event.EventType = "reactivate"
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	s.Len(testHarborInstance.robots, 2)
+	for _, robot := range testHarborInstance.robots {
+		s.False(robot.disabled, robot.robotName)
+	}
+	for _, permission := range testHarborInstance.permissions {
+		s.NotEqual(config.HarborRoleLimitedGuest, permission.roleID, permission.groupName)
+	}
+	s.Nil(store.inventories["uuid-deactivate"].Deactivation)
+	s.NotNil(store.inventories["uuid-deactivate"].HarborProject)
+
+	// The catalog robots are given new secrets for the catalog registries
+	pluginData := NewPluginData()
+	s.NoError(plugin.ReactivateEvent(ctx, event, pluginData))
+	s.Equal("robot$catalog-apps-xyzzy-foo+"+harborReadWriteRobot, pluginData.HarborCredentials().Username)
+	s.Contains(pluginData.HarborCredentials().Token, "refreshed-secret-")
+	s.Equal("robot$catalog-apps-xyzzy-foo+"+harborReadOnlyRobot, pluginData.HarborPullCredentials().Username)
+	s.Contains(pluginData.HarborPullCredentials().Token, "refreshed-secret-")
+
+	// A project without robots or members is deactivated all the same
+	testHarborInstance.robots = map[string]robot{}
+	testHarborInstance.permissions = nil
+	event.EventType = "deactivate"
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	s.NotNil(store.inventories["uuid-deactivate"].Deactivation)
+}
+
+func (s *PluginsTestSuite) TestHarborPluginGroups() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	testHarborInstance = nil
+	HarborFactory = NewTestHarbor
+
+	plugin, err := NewHarborProvisionerPlugin(ctx, "", "", testAdminSecret)
+	s.NoError(err)
+	plugin.WithGroups(config.HarborGroups{
+		Orgs:   map[string]string{"acme": "acme-realm", "globex": "globex-realm"},
+		Realms: map[string]config.HarborGroupRealm{"acme-realm": {NameTemplate: "/{{.Realm}}/{{.Project}}-{{.Role}}"}},
+	})
+
+	// An organization without a realm gets the default group names
+	s.NoError(plugin.CreateEvent(ctx, Event{EventType: "create", Name: "proj", Organization: "other", UUID: "uuid-1"}, NewPluginData()))
+	// A realm without a template gets the default group names
+	s.NoError(plugin.CreateEvent(ctx, Event{EventType: "create", Name: "proj", Organization: "globex", UUID: "uuid-2"}, NewPluginData()))
+	// The realm template names the groups of its organizations
+	s.NoError(plugin.CreateEvent(ctx, Event{EventType: "create", Name: "proj", Organization: "acme", UUID: "uuid-3"}, NewPluginData()))
+	s.Equal([]permission{
+		{roleID: 3, groupName: "uuid-1_Edge-Operator-Group", projectID: "proj"},
+		{roleID: 4, groupName: "uuid-1_Edge-Manager-Group", projectID: "proj"},
+		{roleID: 3, groupName: "uuid-2_Edge-Operator-Group", projectID: "proj"},
+		{roleID: 4, groupName: "uuid-2_Edge-Manager-Group", projectID: "proj"},
+		{roleID: 3, groupName: "/acme-realm/proj-Operator", projectID: "proj"},
+		{roleID: 4, groupName: "/acme-realm/proj-Manager", projectID: "proj"},
+	}, testHarborInstance.permissions)
+
+	// Configured roles replace the default roles, and may name their groups with their own template
+	testHarborInstance.permissions = nil
+	plugin.WithGroups(config.HarborGroups{Roles: []config.HarborGroupRole{
+		{Role: "Manager", RoleID: config.HarborRoleProjectAdmin},
+		{Role: "Viewer", RoleID: config.HarborRoleLimitedGuest, NameTemplate: "{{.Organization}}-{{.Project}}-viewers"},
+	}})
+	s.NoError(plugin.CreateEvent(ctx, Event{EventType: "create", Name: "proj", Organization: "acme", UUID: "uuid-5"}, NewPluginData()))
+	s.Equal([]permission{
+		{roleID: 1, groupName: "uuid-5_Edge-Manager-Group", projectID: "proj"},
+		{roleID: 5, groupName: "acme-proj-viewers", projectID: "proj"},
+	}, testHarborInstance.permissions)
+
+	// A template that fails fails the event permanently
+	plugin.WithGroups(config.HarborGroups{Realms: map[string]config.HarborGroupRealm{"master": {NameTemplate: "{{.Unknown}}"}}})
+	err = plugin.CreateEvent(ctx, Event{EventType: "create", Name: "proj", Organization: "other", UUID: "uuid-4"}, NewPluginData())
+	s.ErrorIs(err, southbound.ErrPermanent)
+	s.ErrorContains(err, "invalid group name template of realm master")
+}
+
+func (s *PluginsTestSuite) TestHarborPluginLabels() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	store := &testInventoryStore{inventories: map[string]*southbound.Inventory{}}
+	InventoryStoreFactory = func(_ config.Configuration) (InventoryStore, error) { return store, nil }
+	defer func() { InventoryStoreFactory = NewInventoryStore }()
+	testHarborInstance = nil
+	HarborFactory = NewTestHarbor
+
+	configuration := config.Configuration{PodNamespace: "orch-app"}
+	plugin, err := NewHarborProvisionerPlugin(ctx, "", "", testAdminSecret)
+	s.NoError(err)
+	plugin.WithRobotPolicy(config.RobotPolicyReuse).WithLabels(config.DefaultHarborLabels).WithInventory(configuration)
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+	Register(plugin)
+	Register(NewInventoryRecorderPlugin(configuration))
+
+	event := Event{
+		EventType:    "create",
+		Name:         "labels",
+		Organization: "org",
+		UUID:         "uuid-labels",
+	}
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+
+	// The labels are created in the project and recorded with their IDs, so that artifacts can be labeled
+	labels, err := testHarborInstance.ListProjectLabels(ctx, HarborProjectID)
+	s.NoError(err)
+	s.Require().Len(labels, 2)
+	s.Equal("extension", labels[0].Name)
+	s.Equal("p", labels[0].Scope)
+	s.Equal("customer-app", labels[1].Name)
+	s.Equal([]southbound.InventoryHarborLabel{
+		{Name: "extension", ID: labels[0].ID},
+		{Name: "customer-app", ID: labels[1].ID},
+	}, store.inventories["uuid-labels"].HarborProject.Labels)
+
+	// Another label configuration provisions the project again, updating the labels that exist
+	plugin.WithLabels([]config.HarborLabel{{Name: "extension", Description: "Extension", Color: "#FF0000"}})
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	labels, err = testHarborInstance.ListProjectLabels(ctx, HarborProjectID)
+	s.NoError(err)
+	s.Len(labels, 2)
+	s.Equal("#FF0000", labels[0].Color)
+	s.Equal([]southbound.InventoryHarborLabel{{Name: "extension", ID: labels[0].ID}},
+		store.inventories["uuid-labels"].HarborProject.Labels)
+
+	// The labels are deleted with the project
+	event.EventType = "delete"
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	s.Empty(testHarborInstance.labels)
+}
+
+// newProjectsHarbor is a test Harbor that only has the Harbor projects of the existing projects
+type newProjectsHarbor struct {
+	*testHarbor
+	existing []string
+}
+
+func (t *newProjectsHarbor) GetProjectID(ctx context.Context, org string, displayName string) (int, error) {
+	if !slices.Contains(t.existing, displayName) {
+		return 0, fmt.Errorf("%w: project %s not found", southbound.ErrPermanent, displayName)
+	}
+	return t.testHarbor.GetProjectID(ctx, org, displayName)
+}
+
+func (s *PluginsTestSuite) TestHarborPluginAdmit() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	testHarborInstance = nil
+	harbor, _ := NewTestHarbor(ctx, "", "", testAdminSecret)
+	testHarborInstance.storageUsed = 1000
+	defer func() { testHarborInstance.storageUsed = 0 }()
+	HarborFactory = func(_ context.Context, _ string, _ string, _ config.SecretRef) (Harbor, error) {
+		return &newProjectsHarbor{testHarbor: harbor.(*testHarbor), existing: []string{"upgraded"}}, nil
+	}
+	defer func() { HarborFactory = NewTestHarbor }()
+	plugin, err := NewHarborProvisionerPlugin(ctx, "", "", testAdminSecret)
+	s.NoError(err)
+	event := Event{EventType: "create", Name: "new", Organization: "org", UUID: "uuid-new"}
+
+	// Without a maximum every project is admitted
+	s.NoError(plugin.Admit(ctx, event))
+	plugin.WithMaxStorage(1001)
+	s.NoError(plugin.Admit(ctx, event))
+
+	plugin.WithMaxStorage(1000)
+	err = plugin.Admit(ctx, event)
+	s.ErrorIs(err, ErrCapacityExceeded)
+	s.ErrorContains(err, "1000 bytes of Harbor storage are used, the maximum is 1000 (MAX_HARBOR_STORAGE)")
+
+	// Projects with a Harbor project are admitted, so that they can be upgraded
+	event.Name = "upgraded"
+	s.NoError(plugin.Admit(ctx, event))
+}
+
+func (s *PluginsTestSuite) TestHarborPluginRobotPermissions() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	testHarborInstance = nil
+	HarborFactory = NewTestHarbor
+
+	plugin, err := NewHarborProvisionerPlugin(ctx, "", "", testAdminSecret)
+	s.NoError(err)
+	readWrite := []config.HarborRobotAccess{{Resource: "repository", Actions: []string{"list", "pull", "push"}}}
+	plugin.WithRobotPermissions(config.HarborRobotPermissions{ReadWrite: readWrite}).WithRequestTimeout(time.Minute)
+	s.Equal(time.Minute, testHarborInstance.requestTimeout)
+
+	// The configured access replaces the default access of the read-write robot only
+	s.NoError(plugin.CreateEvent(ctx, Event{EventType: "create", Name: "proj", Organization: "acme", UUID: "uuid-1"}, NewPluginData()))
+	s.Equal(readWrite, testHarborInstance.robots[`robot$catalog-apps-acme-proj+catalog-apps-read-write`].access)
+	s.Equal(config.DefaultHarborPullAccess, testHarborInstance.robots[`robot$catalog-apps-acme-proj+catalog-apps-read-only`].access)
+}
+
+func (s *PluginsTestSuite) TestHarborPluginRobots() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	store := &testInventoryStore{inventories: map[string]*southbound.Inventory{}}
+	InventoryStoreFactory = func(_ config.Configuration) (InventoryStore, error) { return store, nil }
+	defer func() { InventoryStoreFactory = NewInventoryStore }()
+	testHarborInstance = nil
+	HarborFactory = NewTestHarbor
+
+	configuration := config.Configuration{PodNamespace: "orch-app"}
+	plugin, err := NewHarborProvisionerPlugin(ctx, "", "", testAdminSecret)
+	s.NoError(err)
+	defaultSettings, err := plugin.settings(Event{Name: "proj", Organization: "acme"}, 0)
+	s.NoError(err)
+
+	// A CI robot that pushes and an edge robot given to the catalog for pulling, in place of the default pull robot
+	plugin.WithRobots([]config.HarborRobot{
+		{Name: "catalog-apps-read-write", Permissions: config.RobotProfileReadWrite, Catalog: true},
+		{Name: "ci-push", Permissions: config.RobotProfileReadWrite},
+		{Name: "edge-pull", Permissions: config.RobotProfilePull, Catalog: true},
+	}).WithRobotPolicy(config.RobotPolicyReuse).WithInventory(configuration)
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+	Register(plugin)
+	Register(NewInventoryRecorderPlugin(configuration))
+
+	event := Event{EventType: "create", Name: "proj", Organization: "acme", UUID: "uuid-robots"}
+	pluginData := NewPluginData()
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	s.Equal(config.DefaultHarborReadWriteAccess, testHarborInstance.robots[`robot$catalog-apps-acme-proj+ci-push`].access)
+	s.Equal(config.DefaultHarborPullAccess, testHarborInstance.robots[`robot$catalog-apps-acme-proj+edge-pull`].access)
+	s.NotContains(testHarborInstance.robots, `robot$catalog-apps-acme-proj+catalog-apps-read-only`)
+	s.Equal("robot$catalog-apps-acme-proj+catalog-apps-read-write", pluginData.HarborCredentials().Username)
+	s.Equal("robot$catalog-apps-acme-proj+edge-pull", pluginData.HarborPullCredentials().Username)
+	recorded := pendingInventory(pluginData).HarborProject
+	s.Len(recorded.Robots, 3)
+	s.Equal("robot$catalog-apps-acme-proj+ci-push", recorded.Robots[1].Name)
+	s.NotEqual(defaultSettings, recorded.Settings)
+
+	// A replayed event finds every robot recorded and passes on the catalog robots as kept
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	delete(testHarborInstance.createdProjects, "acme-proj")
+	pluginData = NewPluginData()
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	s.NotContains(testHarborInstance.createdProjects, "acme-proj")
+	s.Equal(HarborRobot{Username: "robot$catalog-apps-acme-proj+edge-pull", Kept: true}, pluginData.HarborPullCredentials())
+
+	// Deactivating the project disables the CI robot too
+	event.EventType = "deactivate"
+	s.NoError(plugin.DeactivateEvent(ctx, event, NewPluginData()))
+	for name, robot := range testHarborInstance.robots {
+		s.True(robot.disabled, name)
+	}
+}
+
+func (s *PluginsTestSuite) TestHarborPluginKeepsOtherRobots() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	store := &testInventoryStore{inventories: map[string]*southbound.Inventory{}}
+	InventoryStoreFactory = func(_ config.Configuration) (InventoryStore, error) { return store, nil }
+	defer func() { InventoryStoreFactory = NewInventoryStore }()
+	testHarborInstance = nil
+	HarborFactory = NewTestHarbor
+
+	configuration := config.Configuration{PodNamespace: "orch-app"}
+	plugin, err := NewHarborProvisionerPlugin(ctx, "", "", testAdminSecret)
+	s.NoError(err)
+	catalogRobot := config.HarborRobot{Name: "catalog-apps-read-write", Permissions: config.RobotProfileReadWrite, Catalog: true}
+	plugin.WithRobots([]config.HarborRobot{catalogRobot, {Name: "ci-push", Permissions: config.RobotProfileReadWrite}}).
+		WithInventory(configuration)
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+	Register(plugin)
+	Register(NewInventoryRecorderPlugin(configuration))
+
+	const ciPush = `robot$catalog-apps-acme-proj+ci-push`
+	const catalog = `robot$catalog-apps-acme-proj+catalog-apps-read-write`
+	event := Event{EventType: "create", Name: "proj", Organization: "acme", UUID: "uuid-robots"}
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	ciPushID := testHarborInstance.robots[ciPush].robotID
+	catalogID := testHarborInstance.robots[catalog].robotID
+
+	// With the recreate policy, only the robot given to the catalog is replaced, the secret of the CI robot is not known
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	s.Equal(ciPushID, testHarborInstance.robots[ciPush].robotID)
+	s.NotEqual(catalogID, testHarborInstance.robots[catalog].robotID)
+
+	// A robot removed from the configuration stays recorded
+	plugin.WithRobots([]config.HarborRobot{catalogRobot})
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+	s.Len(store.inventories["uuid-robots"].HarborProject.Robots, 2)
+	s.Equal(ciPush, store.inventories["uuid-robots"].HarborProject.Robots[1].Name)
+
+	// and is disabled with the project, then revoked when the project is archived
+	event.EventType = "deactivate"
+	s.NoError(plugin.DeactivateEvent(ctx, event, NewPluginData()))
+	s.True(testHarborInstance.robots[ciPush].disabled)
+	event.EventType = "reactivate"
+	s.NoError(plugin.ReactivateEvent(ctx, event, NewPluginData()))
+	s.False(testHarborInstance.robots[ciPush].disabled)
+	event.EventType = "delete"
+	event.RetainData = true
+	s.NoError(plugin.DeleteEvent(ctx, event, NewPluginData()))
+	s.NotContains(testHarborInstance.robots, ciPush)
+	s.NotContains(testHarborInstance.robots, catalog)
+}
+
+func (s *PluginsTestSuite) TestReloadHarborCredentials() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	testHarborInstance = nil
+	HarborFactory = NewTestHarbor
+	plugin, err := NewHarborProvisionerPlugin(ctx, "", "", testAdminSecret)
+	s.NoError(err)
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+
+	// Nothing to reload without a plugin using the Harbor admin credential
+	Register(&InitPlugin{})
+	changed, err := ReloadHarborCredentials(ctx)
+	s.NoError(err)
+	s.False(changed)
+
+	// The client of the registered plugin reads the credential again
+	Register(plugin)
+	changed, err = ReloadHarborCredentials(ctx)
+	s.NoError(err)
+	s.True(changed)
+	s.Equal(1, testHarborInstance.credentialReloads)
+}
+
+func (s *PluginsTestSuite) TestHarborPluginReuseRobot() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	HarborFactory = NewTestHarbor
+
+	plugin, err := NewHarborProvisionerPlugin(ctx, "", "", testAdminSecret)
+	s.NoError(err)
+
+	event := Event{
+		EventType:    "create",
+		Name:         "fOo",
+		Organization: "xYzzY",
+	}
+	expectedRobotName := `robot$catalog-apps-xyzzy-foo+catalog-apps-read-write`
+	expectedPullRobotName := `robot$catalog-apps-xyzzy-foo+catalog-apps-read-only`
+
+	pluginData := NewPluginData()
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	robotID := testHarborInstance.robots[expectedRobotName].robotID
+	pullRobotID := testHarborInstance.robots[expectedPullRobotName].robotID
+	s.False(pluginData.HarborCredentials().Kept)
+	s.NotEmpty(pluginData.HarborCredentials().Token)
+	s.False(pluginData.HarborPullCredentials().Kept)
+	s.Equal("pull-secret", pluginData.HarborPullCredentials().Token)
+
+	// The existing robots are kept and their secrets are not known
+	plugin.WithRobotPolicy(config.RobotPolicyReuse)
+	pluginData = NewPluginData()
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	s.Equal(robotID, testHarborInstance.robots[expectedRobotName].robotID)
+	s.Equal(HarborRobot{Username: expectedRobotName, Kept: true}, pluginData.HarborCredentials())
+	s.Equal(HarborRobot{Username: expectedPullRobotName, Kept: true}, pluginData.HarborPullCredentials())
+
+	// Refreshing the credentials issues new secrets for the same robots
+	event.RefreshCredentials = true
+	pluginData = NewPluginData()
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	s.Equal(robotID, testHarborInstance.robots[expectedRobotName].robotID)
+	s.Equal(fmt.Sprintf("refreshed-secret-%d", robotID), pluginData.HarborCredentials().Token)
+	s.False(pluginData.HarborCredentials().Kept)
+	s.Equal(pullRobotID, testHarborInstance.robots[expectedPullRobotName].robotID)
+	s.Equal(fmt.Sprintf("refreshed-secret-%d", pullRobotID), pluginData.HarborPullCredentials().Token)
+}
+
+func (s *PluginsTestSuite) TestHarborPluginEnsure() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	HarborFactory = NewTestHarbor
+
+	plugin, err := NewHarborProvisionerPlugin(ctx, "", "", testAdminSecret)
+	s.NoError(err)
+
+	event := Event{
+		EventType:    "create",
+		Name:         "ensured",
+		Organization: "acme",
+	}
+	expectedRobotName := `robot$catalog-apps-acme-ensured+catalog-apps-read-write`
+	expectedPullRobotName := `robot$catalog-apps-acme-ensured+catalog-apps-read-only`
+	s.NoError(plugin.CreateEvent(ctx, event, NewPluginData()))
+	robotID := testHarborInstance.robots[expectedRobotName].robotID
+
+	// The robots are kept even though the policy recreates them, and the storage limit is applied
+	event.EventType = "ensure"
+	event.Profile = &config.ProvisioningProfile{Name: "premium", HarborStorageLimit: 1 << 30}
+	pluginData := NewPluginData()
+	s.NoError(plugin.EnsureEvent(ctx, event, pluginData))
+	s.Equal(robotID, testHarborInstance.robots[expectedRobotName].robotID)
+	s.Equal(HarborRobot{Username: expectedRobotName, Kept: true}, pluginData.HarborCredentials())
+	s.Equal(HarborRobot{Username: expectedPullRobotName, Kept: true}, pluginData.HarborPullCredentials())
+	s.Equal(int64(1<<30), testHarborInstance.storageLimits[`acme-ensured`])
+
+	// A missing robot is created
+	delete(testHarborInstance.robots, expectedPullRobotName)
+	pluginData = NewPluginData()
+	s.NoError(plugin.EnsureEvent(ctx, event, pluginData))
+	s.Equal(robotID, testHarborInstance.robots[expectedRobotName].robotID)
+	s.True(pluginData.HarborCredentials().Kept)
+	s.False(pluginData.HarborPullCredentials().Kept)
+	s.Equal("pull-secret", pluginData.HarborPullCredentials().Token)
+}
+
+func (s *PluginsTestSuite) TestHarborPluginUpdate() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	HarborFactory = NewTestHarbor
+
+	plugin, err := NewHarborProvisionerPlugin(ctx, "", "", testAdminSecret)
+	s.NoError(err)
+
+	event := Event{
+		EventType:    "create",
+		Name:         "fOo",
+		Organization: "xYzzY",
+	}
+	s.NoError(plugin.CreateEvent(ctx, event, NewPluginData()))
+	s.Equal(int64(0), testHarborInstance.storageLimits[`xyzzy-foo`])
+
+	// Label changes do not touch the quota
+	event.EventType = "update"
+	event.Profile = &config.ProvisioningProfile{Name: "premium", HarborStorageLimit: 1 << 30}
+	event.Changes = nexushook.ProjectChanges{Labels: map[string]nexushook.ProjectChange{"tier": {New: "gold"}}}
+	s.NoError(plugin.UpdateEvent(ctx, event, NewPluginData()))
+	s.Equal(int64(0), testHarborInstance.storageLimits[`xyzzy-foo`])
+
+	// Switching profile applies the new quota
+	event.Changes = nexushook.ProjectChanges{Annotations: map[string]nexushook.ProjectChange{
+		nexushook.ProvisioningProfileAnnotationKey: {New: "premium"},
+	}}
+	s.NoError(plugin.UpdateEvent(ctx, event, NewPluginData()))
+	s.Equal(int64(1<<30), testHarborInstance.storageLimits[`xyzzy-foo`])
+	s.Equal(southbound.HarborContentTrust{}, testHarborInstance.contentTrust[`xyzzy-foo`])
+
+	// Signature enforcement follows the profile, and is turned off by a profile that does not require signatures
+	event.Profile = &config.ProvisioningProfile{Name: "signed", HarborSigning: []string{config.HarborSigningCosign, config.HarborSigningNotation}}
+	s.NoError(plugin.UpdateEvent(ctx, event, NewPluginData()))
+	s.Equal(int64(1<<30), testHarborInstance.storageLimits[`xyzzy-foo`])
+	s.Equal(southbound.HarborContentTrust{Cosign: true, Notation: true}, testHarborInstance.contentTrust[`xyzzy-foo`])
+	event.Profile = &config.ProvisioningProfile{Name: "basic"}
+	s.NoError(plugin.UpdateEvent(ctx, event, NewPluginData()))
+	s.Equal(southbound.HarborContentTrust{}, testHarborInstance.contentTrust[`xyzzy-foo`])
+}
+
+func (s *PluginsTestSuite) TestHarborPluginReportsProgress() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	HarborFactory = NewTestHarbor
+
+	plugin, err := NewHarborProvisionerPlugin(ctx, "", "", testAdminSecret)
+	s.NoError(err)
+
+	messages := []string{}
+	event := Event{
+		EventType:    "create",
+		Name:         "progress",
+		Organization: "org",
+		progress: func(message string) {
+			messages = append(messages, message)
+		},
+	}
+	err = plugin.CreateEvent(ctx, event, NewPluginData())
+	s.NoError(err)
+	s.Equal([]string{
+		"Creating Harbor project",
+		"Setting Harbor project member permissions",
+		"Creating Harbor robot account",
+	}, messages)
+
+	messages = []string{}
+	testHarborInstance.repositories[`catalog-apps-org-progress/charts/app`] = `org-progress`
+	testHarborInstance.repositories[`catalog-apps-org-progress/images/app`] = `org-progress`
+	err = plugin.DeleteEvent(ctx, event, NewPluginData())
+	s.NoError(err)
+	s.Equal([]string{
+		"Purging Harbor project repositories",
+		"Purging Harbor project repositories 1/2",
+		"Purging Harbor project repositories 2/2",
+		"Deleting Harbor project",
+	}, messages)
 }
 
 // Mock Harbor that fails Ping operations for testing failure scenarios
@@ -82,12 +861,22 @@ func (t *failingHarborPing) Ping(_ context.Context) error {
 	return nil
 }
 
+func (t *failingHarborPing) NegotiateCapabilities(_ context.Context) (southbound.HarborCapabilities, error) {
+	return southbound.HarborCapabilities{Version: "v2.10", ScanStop: true}, nil
+}
+
+func (t *failingHarborPing) ReloadCredentials(_ context.Context) (bool, error) {
+	return false, nil
+}
+
+func (t *failingHarborPing) SetRequestTimeout(_ time.Duration) {}
+
 func (t *failingHarborPing) Configurations(_ context.Context) error {
 	t.configurationsCallCount++
 	return nil
 }
 
-func (t *failingHarborPing) CreateProject(_ context.Context, _ string, _ string) error {
+func (t *failingHarborPing) CreateProject(_ context.Context, _ string, _ string, _ int64) error {
 	return nil
 }
 
@@ -95,11 +884,18 @@ func (t *failingHarborPing) SetMemberPermissions(_ context.Context, _ int, _ str
 	return nil
 }
 
+func (t *failingHarborPing) SetMemberRole(_ context.Context, _ int, _ string, _ string, _ string) error {
+	return nil
+}
+
 func (t *failingHarborPing) GetProjectID(_ context.Context, _ string, _ string) (int, error) {
 	return HarborProjectID, nil
 }
 
-func (t *failingHarborPing) CreateRobot(_ context.Context, _ string, _ string, _ string) (string, string, error) {
+func (t *failingHarborPing) CreateRobot(_ context.Context, robotName string, _ string, _ string, _ []config.HarborRobotAccess) (string, string, error) {
+	if robotName == harborReadOnlyRobot {
+		return "pull-name", "pull-secret", nil
+	}
 	return "name", "secret", nil
 }
 
@@ -115,11 +911,52 @@ func (t *failingHarborPing) DeleteProject(_ context.Context, _ string, _ string)
 	return nil
 }
 
+func (t *failingHarborPing) ListRepositories(_ context.Context, _ string, _ string) ([]southbound.HarborRepository, error) {
+	return nil, nil
+}
+
+func (t *failingHarborPing) DeleteRepository(_ context.Context, _ string, _ string, _ string) error {
+	return nil
+}
+
+func (t *failingHarborPing) RefreshRobotSecret(_ context.Context, _ int) (string, error) {
+	return "", nil
+}
+
+func (t *failingHarborPing) SetRobotDisabled(_ context.Context, _ southbound.HarborRobot, _ bool) error {
+	return nil
+}
+
+func (t *failingHarborPing) SetProjectStorageLimit(_ context.Context, _ string, _ string, _ int64) error {
+	return nil
+}
+
+func (t *failingHarborPing) ListProjectLabels(_ context.Context, _ int) ([]southbound.HarborLabel, error) {
+	return nil, nil
+}
+
+func (t *failingHarborPing) SetProjectLabel(_ context.Context, _ int, _ config.HarborLabel) (int, error) {
+	return 0, nil
+}
+
+func (t *failingHarborPing) DeleteLabel(_ context.Context, _ int) error {
+	return nil
+}
+
+func (t *failingHarborPing) Statistics(_ context.Context) (southbound.HarborStatistics, error) {
+	return southbound.HarborStatistics{}, nil
+}
+
+func (t *failingHarborPing) SetProjectContentTrust(_ context.Context, _ string, _ string, _ southbound.HarborContentTrust) error {
+	return nil
+}
+
 // Mock Harbor that fails Configuration operations for testing failure scenarios
 type failingHarborConfig struct {
 	pingCallCount                  int
 	configurationsCallCount        int
-	failConfigurationsUntilAttempt int // Succeed after this many attempts (0 = always fail)
+	failConfigurationsUntilAttempt int   // Succeed after this many attempts (0 = always fail)
+	configurationsErr              error // Error returned on failure, defaults to an internal server error
 }
 
 func (t *failingHarborConfig) Ping(_ context.Context) error {
@@ -127,15 +964,28 @@ func (t *failingHarborConfig) Ping(_ context.Context) error {
 	return nil
 }
 
+func (t *failingHarborConfig) ReloadCredentials(_ context.Context) (bool, error) {
+	return false, nil
+}
+
+func (t *failingHarborConfig) SetRequestTimeout(_ time.Duration) {}
+
+func (t *failingHarborConfig) NegotiateCapabilities(_ context.Context) (southbound.HarborCapabilities, error) {
+	return southbound.HarborCapabilities{Version: "v2.10", ScanStop: true}, nil
+}
+
 func (t *failingHarborConfig) Configurations(_ context.Context) error {
 	t.configurationsCallCount++
 	if t.failConfigurationsUntilAttempt == 0 || t.configurationsCallCount <= t.failConfigurationsUntilAttempt {
+		if t.configurationsErr != nil {
+			return t.configurationsErr
+		}
 		return fmt.Errorf(`{"errors":[{"code":"UNKNOWN","message":"internal server error"}]}`)
 	}
 	return nil
 }
 
-func (t *failingHarborConfig) CreateProject(_ context.Context, _ string, _ string) error {
+func (t *failingHarborConfig) CreateProject(_ context.Context, _ string, _ string, _ int64) error {
 	return nil
 }
 
@@ -143,11 +993,18 @@ func (t *failingHarborConfig) SetMemberPermissions(_ context.Context, _ int, _ s
 	return nil
 }
 
+func (t *failingHarborConfig) SetMemberRole(_ context.Context, _ int, _ string, _ string, _ string) error {
+	return nil
+}
+
 func (t *failingHarborConfig) GetProjectID(_ context.Context, _ string, _ string) (int, error) {
 	return HarborProjectID, nil
 }
 
-func (t *failingHarborConfig) CreateRobot(_ context.Context, _ string, _ string, _ string) (string, string, error) {
+func (t *failingHarborConfig) CreateRobot(_ context.Context, robotName string, _ string, _ string, _ []config.HarborRobotAccess) (string, string, error) {
+	if robotName == harborReadOnlyRobot {
+		return "pull-name", "pull-secret", nil
+	}
 	return "name", "secret", nil
 }
 
@@ -163,35 +1020,67 @@ func (t *failingHarborConfig) DeleteProject(_ context.Context, _ string, _ strin
 	return nil
 }
 
+func (t *failingHarborConfig) ListRepositories(_ context.Context, _ string, _ string) ([]southbound.HarborRepository, error) {
+	return nil, nil
+}
+
+func (t *failingHarborConfig) DeleteRepository(_ context.Context, _ string, _ string, _ string) error {
+	return nil
+}
+
+func (t *failingHarborConfig) RefreshRobotSecret(_ context.Context, _ int) (string, error) {
+	return "", nil
+}
+
+func (t *failingHarborConfig) SetRobotDisabled(_ context.Context, _ southbound.HarborRobot, _ bool) error {
+	return nil
+}
+
+func (t *failingHarborConfig) SetProjectStorageLimit(_ context.Context, _ string, _ string, _ int64) error {
+	return nil
+}
+
+func (t *failingHarborConfig) ListProjectLabels(_ context.Context, _ int) ([]southbound.HarborLabel, error) {
+	return nil, nil
+}
+
+func (t *failingHarborConfig) SetProjectLabel(_ context.Context, _ int, _ config.HarborLabel) (int, error) {
+	return 0, nil
+}
+
+func (t *failingHarborConfig) DeleteLabel(_ context.Context, _ int) error {
+	return nil
+}
+
+func (t *failingHarborConfig) Statistics(_ context.Context) (southbound.HarborStatistics, error) {
+	return southbound.HarborStatistics{}, nil
+}
+
+func (t *failingHarborConfig) SetProjectContentTrust(_ context.Context, _ string, _ string, _ southbound.HarborContentTrust) error {
+	return nil
+}
+
 // Test: Harbor Ping fails permanently - should return error after max retries
-// Note: This test is SKIPPED by default as it takes ~5 minutes due to realistic exponential backoff
-// To run: RUN_LONG_TESTS=1 go test -v -run TestPlugins/TestHarborPingFailsPermanently -timeout 10m
 func (s *PluginsTestSuite) TestHarborPingFailsPermanently() {
-	// Skip this test unless RUN_LONG_TESTS environment variable is set
-	if os.Getenv("RUN_LONG_TESTS") != "1" {
-		s.T().Skip("Skipping long-running test. To run: RUN_LONG_TESTS=1 go test -v -run TestPlugins/TestHarborPingFailsPermanently -timeout 10m")
-	}
-
-	// Use a longer timeout since retries take time with exponential backoff
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*6)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
+	// The backoff of about 8 minutes passes on a fake clock
+	ctx, clock := retrytest.Context(ctx)
 
 	mockHarbor := &failingHarborPing{
 		failPingUntilAttempt: 0, // Always fail
 	}
 
-	HarborFactory = func(_ context.Context, _ string, _ string, _ string, _ string) (Harbor, error) {
+	HarborFactory = func(_ context.Context, _ string, _ string, _ config.SecretRef) (Harbor, error) {
 		return mockHarbor, nil
 	}
 
-	plugin, err := NewHarborProvisionerPlugin(ctx, "http://harbor", "http://keycloak", "harbor", "credential")
+	plugin, err := NewHarborProvisionerPlugin(ctx, "http://harbor", "http://keycloak", testAdminSecret)
 	s.NoError(err, "Plugin creation should succeed")
 	s.NotNil(plugin)
 
 	// Initialize should fail after max retries
-	start := time.Now()
 	err = plugin.Initialize(ctx, nil)
-	elapsed := time.Since(start)
 
 	s.Error(err, "Initialize should fail when Harbor ping fails permanently")
 	s.Contains(err.Error(), "harbor not available after", "Error should mention retry exhaustion")
@@ -202,24 +1091,25 @@ func (s *PluginsTestSuite) TestHarborPingFailsPermanently() {
 	// Verify Configurations was never called
 	s.Equal(0, mockHarbor.configurationsCallCount, "Configurations should not be called if ping fails")
 
-	s.T().Logf("Total elapsed time: %v", elapsed)
-	s.GreaterOrEqual(elapsed, 4*time.Minute, "Should respect exponential backoff timing")
+	s.Len(clock.Waits(), 11, "Should wait between the attempts")
+	s.GreaterOrEqual(clock.Waited(), 4*time.Minute, "Should respect exponential backoff timing")
 }
 
 // Test: Harbor Ping recovers after a few retries
 func (s *PluginsTestSuite) TestHarborPingRecoversAfterRetries() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
+	ctx, clock := retrytest.Context(ctx)
 
 	mockHarbor := &failingHarborPing{
 		failPingUntilAttempt: 3,
 	}
 
-	HarborFactory = func(_ context.Context, _ string, _ string, _ string, _ string) (Harbor, error) {
+	HarborFactory = func(_ context.Context, _ string, _ string, _ config.SecretRef) (Harbor, error) {
 		return mockHarbor, nil
 	}
 
-	plugin, err := NewHarborProvisionerPlugin(ctx, "http://harbor", "http://keycloak", "harbor", "credential")
+	plugin, err := NewHarborProvisionerPlugin(ctx, "http://harbor", "http://keycloak", testAdminSecret)
 	s.NoError(err, "Plugin creation should succeed")
 	s.NotNil(plugin)
 
@@ -228,6 +1118,7 @@ func (s *PluginsTestSuite) TestHarborPingRecoversAfterRetries() {
 	s.NoError(err, "Initialize should succeed when Harbor recovers")
 
 	s.Equal(4, mockHarbor.pingCallCount, "Should have made 4 ping attempts (3 failures + 1 success)")
+	s.Len(clock.Waits(), 3, "Should have waited after each failure")
 
 	// Verify Configurations was called once
 	s.Equal(1, mockHarbor.configurationsCallCount, "Configurations should be called once after ping succeeds")
@@ -237,16 +1128,17 @@ func (s *PluginsTestSuite) TestHarborPingRecoversAfterRetries() {
 func (s *PluginsTestSuite) TestHarborConfigurationFailsPermanently() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
+	ctx, _ = retrytest.Context(ctx)
 
 	mockHarbor := &failingHarborConfig{
 		failConfigurationsUntilAttempt: 0, // Always fail
 	}
 
-	HarborFactory = func(_ context.Context, _ string, _ string, _ string, _ string) (Harbor, error) {
+	HarborFactory = func(_ context.Context, _ string, _ string, _ config.SecretRef) (Harbor, error) {
 		return mockHarbor, nil
 	}
 
-	plugin, err := NewHarborProvisionerPlugin(ctx, "http://harbor", "http://keycloak", "harbor", "credential")
+	plugin, err := NewHarborProvisionerPlugin(ctx, "http://harbor", "http://keycloak", testAdminSecret)
 	s.NoError(err, "Plugin creation should succeed")
 	s.NotNil(plugin)
 
@@ -266,16 +1158,17 @@ func (s *PluginsTestSuite) TestHarborConfigurationFailsPermanently() {
 func (s *PluginsTestSuite) TestHarborConfigurationRecoversAfterRetries() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
+	ctx, _ = retrytest.Context(ctx)
 
 	mockHarbor := &failingHarborConfig{
 		failConfigurationsUntilAttempt: 2, // Fail first 2 attempts, succeed on 3rd
 	}
 
-	HarborFactory = func(_ context.Context, _ string, _ string, _ string, _ string) (Harbor, error) {
+	HarborFactory = func(_ context.Context, _ string, _ string, _ config.SecretRef) (Harbor, error) {
 		return mockHarbor, nil
 	}
 
-	plugin, err := NewHarborProvisionerPlugin(ctx, "http://harbor", "http://keycloak", "harbor", "credential")
+	plugin, err := NewHarborProvisionerPlugin(ctx, "http://harbor", "http://keycloak", testAdminSecret)
 	s.NoError(err, "Plugin creation should succeed")
 	s.NotNil(plugin)
 
@@ -294,50 +1187,66 @@ func (s *PluginsTestSuite) TestHarborFactoryFails() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	HarborFactory = func(_ context.Context, _ string, _ string, _ string, _ string) (Harbor, error) {
+	HarborFactory = func(_ context.Context, _ string, _ string, _ config.SecretRef) (Harbor, error) {
 		return nil, errors.New("failed to read harbor credentials")
 	}
 
-	plugin, err := NewHarborProvisionerPlugin(ctx, "http://harbor", "http://keycloak", "harbor", "credential")
+	plugin, err := NewHarborProvisionerPlugin(ctx, "http://harbor", "http://keycloak", testAdminSecret)
 	s.Error(err, "Plugin creation should fail when factory fails")
 	s.Nil(plugin)
 	s.Contains(err.Error(), "failed to read harbor credentials", "Error should propagate from factory")
 }
 
 // Test: Verify exponential backoff timing
-// Note: This test is SKIPPED by default as it takes ~2.5 minutes
-// To run: RUN_LONG_TESTS=1 go test -v -run TestPlugins/TestHarborPingExponentialBackoff -timeout 5m
 func (s *PluginsTestSuite) TestHarborPingExponentialBackoff() {
-	// Skip this test unless RUN_LONG_TESTS environment variable is set
-	if os.Getenv("RUN_LONG_TESTS") != "1" {
-		s.T().Skip("Skipping long-running test. To run: RUN_LONG_TESTS=1 go test -v -run TestPlugins/TestHarborPingExponentialBackoff -timeout 5m")
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*3)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
+	ctx, clock := retrytest.Context(ctx)
 
 	mockHarbor := &failingHarborPing{
 		failPingUntilAttempt: 5, // Fail first 5 attempts
 	}
 
-	HarborFactory = func(_ context.Context, _ string, _ string, _ string, _ string) (Harbor, error) {
+	HarborFactory = func(_ context.Context, _ string, _ string, _ config.SecretRef) (Harbor, error) {
 		return mockHarbor, nil
 	}
 
-	plugin, err := NewHarborProvisionerPlugin(ctx, "http://harbor", "http://keycloak", "harbor", "credential")
+	plugin, err := NewHarborProvisionerPlugin(ctx, "http://harbor", "http://keycloak", testAdminSecret)
 	s.NoError(err, "Plugin creation should succeed")
 
-	start := time.Now()
 	err = plugin.Initialize(ctx, nil)
-	elapsed := time.Since(start)
-
 	s.NoError(err, "Initialize should succeed after retries")
 
-	// With exponential backoff: 5s, 10s, 20s, 40s, 60s(capped) = ~135s for 5 attempts
-	// Allow some tolerance for test execution time
-	s.T().Logf("Total elapsed time: %v", elapsed)
-	s.GreaterOrEqual(elapsed, 2*time.Minute, "Should have waited for exponential backoff delays")
-	s.LessOrEqual(elapsed, 170*time.Second, "Should not have waited excessively long")
+	// With exponential backoff: 5s, 10s, 20s, 40s, 60s(capped), each with 20% jitter
+	expected := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second, 60 * time.Second}
+	waits := clock.Waits()
+	s.Len(waits, len(expected), "Should have waited after each failure")
+	for i, wait := range waits {
+		s.InDelta(expected[i], wait, 0.2*float64(expected[i]), "Wait %d should follow the exponential backoff", i+1)
+	}
 
 	s.Equal(6, mockHarbor.pingCallCount, "Should have made 6 ping attempts (5 failures + 1 success)")
 }
+
+// Test: Harbor rejects the configuration - a permanent error is not retried
+func (s *PluginsTestSuite) TestHarborConfigurationRejected() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	mockHarbor := &failingHarborConfig{
+		failConfigurationsUntilAttempt: 0, // Always fail
+		configurationsErr:              fmt.Errorf("%w: invalid oidc_endpoint", southbound.ErrPermanent),
+	}
+
+	HarborFactory = func(_ context.Context, _ string, _ string, _ config.SecretRef) (Harbor, error) {
+		return mockHarbor, nil
+	}
+
+	plugin, err := NewHarborProvisionerPlugin(ctx, "http://harbor", "http://keycloak", testAdminSecret)
+	s.NoError(err, "Plugin creation should succeed")
+
+	err = plugin.Initialize(ctx, nil)
+	s.Error(err, "Initialize should fail when Harbor rejects the configuration")
+	s.ErrorIs(err, southbound.ErrPermanent)
+	s.Equal(1, mockHarbor.configurationsCallCount, "Permanent errors should not be retried")
+}
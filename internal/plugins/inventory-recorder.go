@@ -0,0 +1,286 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	nexushook "github.com/open-edge-platform/app-orch-tenant-controller/internal/nexus"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	"k8s.io/client-go/rest"
+)
+
+// InventoryName is the key in the inventory section of the plugin data of the resources recorded by the plugins
+// while handling an event, as a JSON southbound.Inventory document
+const InventoryName = "inventory"
+
+// InventorySavedName is the key in the inventory section of the plugin data of the complete inventory of the project
+// saved by the Inventory Recorder, as a JSON southbound.Inventory document
+const InventorySavedName = "saved"
+
+// recordInventory lets a plugin add the resources it created to the inventory of the event.
+func recordInventory(pluginData *PluginData, record func(inventory *southbound.Inventory)) {
+	if pluginData == nil {
+		return
+	}
+	inventory := pendingInventory(pluginData)
+	record(inventory)
+	document, err := json.Marshal(inventory)
+	if err != nil {
+		log.Warnf("Unable to record inventory: %v", err)
+		return
+	}
+	pluginData.Set(InventorySection, InventoryName, string(document))
+}
+
+// pendingInventory returns the resources recorded by the plugins so far.
+func pendingInventory(pluginData *PluginData) *southbound.Inventory {
+	inventory := &southbound.Inventory{}
+	if document := pluginData.Get(InventorySection, InventoryName); document != "" {
+		if err := json.Unmarshal([]byte(document), inventory); err != nil {
+			log.Warnf("Discarding invalid inventory: %v", err)
+		}
+	}
+	return inventory
+}
+
+// savedInventory returns the inventory the recorder saved for the event, or nil if it saved none.
+func savedInventory(pluginData *PluginData) *southbound.Inventory {
+	document := pluginData.Get(InventorySection, InventorySavedName)
+	if document == "" {
+		return nil
+	}
+	inventory := &southbound.Inventory{}
+	if err := json.Unmarshal([]byte(document), inventory); err != nil {
+		log.Warnf("Discarding invalid saved inventory: %v", err)
+		return nil
+	}
+	return inventory
+}
+
+// watcherInventory returns the summary of the inventory of the project shown on its watcher after the event: the
+// inventory saved by the recorder, or for create events without the recorder the resources recorded by the plugins.
+// It returns false if the event leaves the inventory unchanged.
+func watcherInventory(event Event, pluginData *PluginData) (nexushook.InventorySummary, bool) {
+	inventory := savedInventory(pluginData)
+	if inventory == nil {
+		if event.EventType != "create" || pluginData.Get(InventorySection, InventoryName) == "" {
+			return nexushook.InventorySummary{}, false
+		}
+		inventory = pendingInventory(pluginData)
+	}
+	summary := nexushook.InventorySummary{Registries: inventory.CatalogRegistries}
+	if inventory.HarborProject != nil {
+		summary.HarborProject = inventory.HarborProject.Name
+	}
+	for _, pkg := range inventory.ExtensionPackages {
+		summary.Extensions = append(summary.Extensions, pkg.Name+":"+pkg.Version)
+	}
+	return summary, true
+}
+
+type InventoryStore interface {
+	Save(ctx context.Context, inventory *southbound.Inventory) error
+	Load(ctx context.Context, uuid string) (*southbound.Inventory, error)
+	List(ctx context.Context) ([]*southbound.Inventory, error)
+	Delete(ctx context.Context, uuid string) error
+}
+
+func NewInventoryStore(configuration config.Configuration) (InventoryStore, error) {
+	restConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	return southbound.NewInventoryStore(restConfig, configuration.PodNamespace)
+}
+
+var InventoryStoreFactory = NewInventoryStore
+
+// InventoryRecorderPlugin stores the resources created for a project in a ConfigMap in the controller namespace,
+// so that support can see what a tenant owns without recomputing resource names. It must be registered last, so
+// that it sees the resources recorded by all other plugins.
+type InventoryRecorderPlugin struct {
+	config config.Configuration
+}
+
+func NewInventoryRecorderPlugin(configuration config.Configuration) *InventoryRecorderPlugin {
+	return &InventoryRecorderPlugin{
+		config: configuration,
+	}
+}
+
+func (p *InventoryRecorderPlugin) Initialize(_ context.Context, _ *PluginData) error {
+	return nil
+}
+
+// CreateEvent replaces the inventory of the project with the resources recorded while provisioning it.
+func (p *InventoryRecorderPlugin) CreateEvent(ctx context.Context, event Event, pluginData *PluginData) error {
+	store, err := InventoryStoreFactory(p.config)
+	if err != nil {
+		return err
+	}
+	event.ReportProgress("Recording resource inventory")
+	inventory := pendingInventory(pluginData)
+	inventory.Organization = event.Organization
+	inventory.Project = event.Name
+	inventory.UUID = event.UUID
+	inventory.Updated = time.Now().UTC()
+	return p.save(ctx, store, inventory, pluginData)
+}
+
+// save stores the inventory of the project and keeps it in the plugin data, so that the project watcher shows it.
+func (p *InventoryRecorderPlugin) save(ctx context.Context, store InventoryStore, inventory *southbound.Inventory, pluginData *PluginData) error {
+	if err := store.Save(ctx, inventory); err != nil {
+		return err
+	}
+	document, err := json.Marshal(inventory)
+	if err != nil {
+		log.Warnf("Unable to keep the saved inventory: %v", err)
+		return nil
+	}
+	pluginData.Set(InventorySection, InventorySavedName, string(document))
+	return nil
+}
+
+// UpdateEvent adds the resources created by an update, such as the deployments of a new provisioning profile, to
+// the inventory of the project.
+func (p *InventoryRecorderPlugin) UpdateEvent(ctx context.Context, event Event, pluginData *PluginData) error {
+	recorded := pendingInventory(pluginData)
+	if recorded.HarborProject == nil && len(recorded.CatalogRegistries) == 0 && len(recorded.StarterApps) == 0 &&
+		recorded.ExtensionPackages == nil && len(recorded.Deployments) == 0 && recorded.GitRepository == nil &&
+		recorded.Namespace == "" {
+		return nil
+	}
+	store, err := InventoryStoreFactory(p.config)
+	if err != nil {
+		return err
+	}
+	inventory, err := store.Load(ctx, event.UUID)
+	if err != nil {
+		return err
+	}
+	if inventory == nil {
+		return p.CreateEvent(ctx, event, pluginData)
+	}
+	event.ReportProgress("Recording resource inventory")
+	if recorded.HarborProject != nil {
+		inventory.HarborProject = recorded.HarborProject
+	}
+	if recorded.GitRepository != nil {
+		inventory.GitRepository = recorded.GitRepository
+	}
+	if recorded.Namespace != "" {
+		inventory.Namespace = recorded.Namespace
+	}
+	if len(recorded.CatalogRegistries) > 0 {
+		inventory.CatalogRegistryPrefix = recorded.CatalogRegistryPrefix
+		inventory.CatalogRegistrySuffix = recorded.CatalogRegistrySuffix
+	}
+	for _, registry := range recorded.CatalogRegistries {
+		if !slices.Contains(inventory.CatalogRegistries, registry) {
+			inventory.CatalogRegistries = append(inventory.CatalogRegistries, registry)
+		}
+	}
+	for _, app := range recorded.StarterApps {
+		if !slices.Contains(inventory.StarterApps, app) {
+			inventory.StarterApps = append(inventory.StarterApps, app)
+		}
+	}
+	// The extensions plugin records all the packages it keeps, including those of earlier events
+	if recorded.ExtensionPackages != nil {
+		inventory.ExtensionPackages = recorded.ExtensionPackages
+		inventory.ExtensionHashes = recorded.ExtensionHashes
+	}
+	for _, deployment := range recorded.Deployments {
+		i := slices.IndexFunc(inventory.Deployments, func(d southbound.InventoryDeployment) bool { return d.ID == deployment.ID })
+		if i < 0 {
+			inventory.Deployments = append(inventory.Deployments, deployment)
+		} else if deployment.Hash != "" {
+			inventory.Deployments[i].Hash = deployment.Hash
+		}
+	}
+	inventory.Updated = time.Now().UTC()
+	return p.save(ctx, store, inventory, pluginData)
+}
+
+// EnsureEvent adds the resources recorded while converging the project to its inventory. Resources that an ensure
+// event leaves in place stay recorded, so the inventory is merged as for update events.
+func (p *InventoryRecorderPlugin) EnsureEvent(ctx context.Context, event Event, pluginData *PluginData) error {
+	return p.UpdateEvent(ctx, event, pluginData)
+}
+
+// DeleteEvent removes the inventory once the other plugins have deleted the resources of the project. If the
+// Harbor project was archived, the inventory is kept with only the archived Harbor project in it, so that support
+// can find the retained data of the deleted project.
+func (p *InventoryRecorderPlugin) DeleteEvent(ctx context.Context, event Event, pluginData *PluginData) error {
+	store, err := InventoryStoreFactory(p.config)
+	if err != nil {
+		return err
+	}
+	recorded := pendingInventory(pluginData)
+	if recorded.HarborProject == nil || recorded.HarborProject.Archived == nil {
+		return store.Delete(ctx, event.UUID)
+	}
+	event.ReportProgress("Recording archived Harbor project")
+	return store.Save(ctx, &southbound.Inventory{
+		Organization:  event.Organization,
+		Project:       event.Name,
+		UUID:          event.UUID,
+		HarborProject: recorded.HarborProject,
+		Updated:       time.Now().UTC(),
+	})
+}
+
+// DeactivateEvent records that the resources of the project are kept until the purge time of the event, so that
+// they are deleted then. A project without an inventory gets one, recording only the deactivation.
+func (p *InventoryRecorderPlugin) DeactivateEvent(ctx context.Context, event Event, _ *PluginData) error {
+	store, err := InventoryStoreFactory(p.config)
+	if err != nil {
+		return err
+	}
+	inventory, err := store.Load(ctx, event.UUID)
+	if err != nil {
+		return err
+	}
+	if inventory == nil {
+		inventory = &southbound.Inventory{Organization: event.Organization, Project: event.Name, UUID: event.UUID}
+	}
+	event.ReportProgress("Recording deactivation")
+	now := time.Now().UTC()
+	inventory.Deactivation = &southbound.InventoryDeactivation{
+		Deactivated: now,
+		PurgeAfter:  event.PurgeAfter.UTC(),
+		RetainData:  event.RetainData,
+	}
+	inventory.Updated = now
+	return store.Save(ctx, inventory)
+}
+
+// ReactivateEvent removes the deactivation from the inventory of the project, so that its resources are kept.
+func (p *InventoryRecorderPlugin) ReactivateEvent(ctx context.Context, event Event, _ *PluginData) error {
+	store, err := InventoryStoreFactory(p.config)
+	if err != nil {
+		return err
+	}
+	inventory, err := store.Load(ctx, event.UUID)
+	if err != nil {
+		return err
+	}
+	if inventory == nil || inventory.Deactivation == nil {
+		return nil
+	}
+	event.ReportProgress("Recording reactivation")
+	inventory.Deactivation = nil
+	inventory.Updated = time.Now().UTC()
+	return store.Save(ctx, inventory)
+}
+
+func (p *InventoryRecorderPlugin) Name() string {
+	return "Inventory Recorder"
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	yaml "gopkg.in/yaml.v2"
+)
+
+type testInventoryStore struct {
+	inventories map[string]*southbound.Inventory
+}
+
+func (t *testInventoryStore) Save(_ context.Context, inventory *southbound.Inventory) error {
+	t.inventories[inventory.UUID] = inventory
+	return nil
+}
+
+func (t *testInventoryStore) Load(_ context.Context, uuid string) (*southbound.Inventory, error) {
+	return t.inventories[uuid], nil
+}
+
+func (t *testInventoryStore) List(_ context.Context) ([]*southbound.Inventory, error) {
+	return slices.Collect(maps.Values(t.inventories)), nil
+}
+
+func (t *testInventoryStore) Delete(_ context.Context, uuid string) error {
+	delete(t.inventories, uuid)
+	return nil
+}
+
+func (s *PluginsTestSuite) TestInventoryRecorderPlugin() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	store := &testInventoryStore{inventories: map[string]*southbound.Inventory{}}
+	InventoryStoreFactory = func(_ config.Configuration) (InventoryStore, error) { return store, nil }
+	defer func() { InventoryStoreFactory = NewInventoryStore }()
+	HarborFactory = NewTestHarbor
+	CatalogFactory = newTestCatalog
+
+	harborPlugin, err := NewHarborProvisionerPlugin(ctx, "", "", testAdminSecret)
+	s.NoError(err)
+	catalogPlugin, err := NewCatalogProvisionerPlugin(config.Configuration{})
+	s.NoError(err)
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+	Register(harborPlugin)
+	Register(catalogPlugin)
+	Register(NewInventoryRecorderPlugin(config.Configuration{PodNamespace: "orch-app"}))
+
+	event := Event{
+		EventType:    "create",
+		Name:         "Proj",
+		Organization: "Org",
+		UUID:         "uuid-1",
+	}
+	_, err = Dispatch(ctx, event, nil)
+	s.NoError(err)
+
+	inventory := store.inventories["uuid-1"]
+	s.NotNil(inventory)
+	s.Equal("Org", inventory.Organization)
+	s.Equal("Proj", inventory.Project)
+	s.Equal(HarborProjectID, inventory.HarborProject.ID)
+	s.Equal("catalog-apps-org-proj", inventory.HarborProject.Name)
+	s.Len(inventory.HarborProject.Robots, 2)
+	s.Equal("robot$catalog-apps-org-proj+catalog-apps-read-write", inventory.HarborProject.Robots[0].Name)
+	s.Equal("robot$catalog-apps-org-proj+catalog-apps-read-only", inventory.HarborProject.Robots[1].Name)
+	for _, robot := range inventory.HarborProject.Robots {
+		s.NotZero(robot.ID)
+	}
+	s.Equal([]string{"intel-rs-helm", "intel-rs-images", "harbor-helm-oci", "harbor-docker-oci"}, inventory.CatalogRegistries)
+	s.False(inventory.Updated.IsZero())
+
+	// Deployments created by an update are added to the inventory
+	mockDeployments = map[string]*mockDeployment{}
+	AppDeploymentFactory = newTestADM
+	ad, _ := AppDeploymentFactory(config.Configuration{})
+	s.NoError(ad.CreateDeployment(ctx, "base-extensions", "base", "0.2.0", "default", "uuid-1", southbound.DeploymentTargets{}))
+	s.NoError(ad.CreateDeployment(ctx, "other", "other", "1.0", "default", "uuid-1", southbound.DeploymentTargets{}))
+	manifest := &Manifest{}
+	s.NoError(yaml.Unmarshal([]byte(`
+lpke:
+  deploymentList:
+    - dpName: base-extensions
+      displayName: base
+`), manifest))
+	pluginData := NewPluginData()
+	s.NoError(recordDeployments(ctx, ad, "uuid-1", manifest, nil, pluginData))
+
+	plugin := NewInventoryRecorderPlugin(config.Configuration{})
+	s.NoError(plugin.UpdateEvent(ctx, Event{EventType: "update", UUID: "uuid-1"}, pluginData))
+	inventory = store.inventories["uuid-1"]
+	s.Equal([]southbound.InventoryDeployment{
+		{ID: "id-base", DisplayName: "base", AppName: "base-extensions", AppVersion: "0.2.0", ProfileName: "default"},
+	}, inventory.Deployments)
+	s.Len(inventory.CatalogRegistries, 4)
+
+	// The saved inventory is shown on the project watcher
+	summary, ok := watcherInventory(Event{EventType: "update", UUID: "uuid-1"}, pluginData)
+	s.True(ok)
+	s.Equal("catalog-apps-org-proj", summary.HarborProject)
+	s.Equal(inventory.CatalogRegistries, summary.Registries)
+	_, ok = watcherInventory(Event{EventType: "update", UUID: "uuid-1"}, NewPluginData())
+	s.False(ok)
+
+	event.EventType = "delete"
+	s.NoError(plugin.DeleteEvent(ctx, event, pluginData))
+	s.Empty(store.inventories)
+}
@@ -0,0 +1,237 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+)
+
+// Phase is a step in the lifecycle of a project event.
+type Phase string
+
+const (
+	PhaseReceived     Phase = "Received"
+	PhaseValidating   Phase = "Validating"
+	PhaseProvisioning Phase = "Provisioning"
+	PhaseCompleted    Phase = "Completed"
+	PhaseFailed       Phase = "Failed"
+	PhaseCancelled    Phase = "Cancelled"
+)
+
+// allowed phase transitions; phases without transitions are terminal
+var transitions = map[Phase][]Phase{
+	PhaseReceived:     {PhaseValidating, PhaseCancelled},
+	PhaseValidating:   {PhaseProvisioning, PhaseFailed, PhaseCancelled},
+	PhaseProvisioning: {PhaseProvisioning, PhaseCompleted, PhaseFailed, PhaseCancelled},
+}
+
+// Terminal returns true if no further transitions are possible from the phase.
+func (p Phase) Terminal() bool {
+	return len(transitions[p]) == 0
+}
+
+// Lifecycle is the state machine of a project event:
+//
+//	Received -> Validating -> Provisioning(plugin 1..n) -> Completed, Failed or Cancelled
+//
+// It is kept with the event across retries, so that a retry resumes with the plugin that failed and the plugin
+// data produced by the plugins that already completed. The lifecycle is shared by all copies of an event.
+type Lifecycle struct {
+	mu     sync.Mutex
+	phase  Phase
+	plugin string
+	// index of the first plugin that has not completed the event
+	next   int
+	data   *PluginData
+	result *DispatchResult
+	// bytes sent to the southbound services, over all attempts
+	payloads *southbound.PayloadSizes
+	err      error
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+// NewLifecycle creates the lifecycle of a newly received event. Its context is cancelled when the event is
+// cancelled or reaches a terminal phase.
+func NewLifecycle(parent context.Context) *Lifecycle {
+	ctx, cancel := context.WithCancel(parent)
+	return &Lifecycle{
+		phase:  PhaseReceived,
+		data:   NewPluginData(),
+		result: &DispatchResult{},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Context returns the context that bounds the processing of the event.
+func (l *Lifecycle) Context() context.Context {
+	return l.ctx
+}
+
+func (l *Lifecycle) Phase() Phase {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.phase
+}
+
+// Plugin returns the plugin that is processing the event, or that failed it.
+func (l *Lifecycle) Plugin() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.plugin
+}
+
+// Result returns the outcome of dispatching the event to the plugins so far.
+func (l *Lifecycle) Result() *DispatchResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.result.clone()
+}
+
+// Err returns the error that failed the event.
+func (l *Lifecycle) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+func (l *Lifecycle) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.plugin != "" && (l.phase == PhaseProvisioning || l.phase == PhaseFailed) {
+		return fmt.Sprintf("%s (%s)", l.phase, l.plugin)
+	}
+	return string(l.phase)
+}
+
+func (l *Lifecycle) transition(to Phase) error {
+	if !slices.Contains(transitions[l.phase], to) {
+		return fmt.Errorf("invalid event transition from %s to %s", l.phase, to)
+	}
+	l.phase = to
+	if to.Terminal() {
+		l.cancel()
+	}
+	return nil
+}
+
+// Validate moves a received event to the Validating phase.
+func (l *Lifecycle) Validate() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.transition(PhaseValidating)
+}
+
+// Complete marks the event as successfully processed by all plugins.
+func (l *Lifecycle) Complete() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.transition(PhaseCompleted)
+}
+
+// Fail marks the event as failed with the given error.
+func (l *Lifecycle) Fail(err error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if transitionErr := l.transition(PhaseFailed); transitionErr != nil {
+		return transitionErr
+	}
+	l.err = err
+	return nil
+}
+
+// Cancel stops the event, cancelling its context. It returns false if the event had already ended.
+func (l *Lifecycle) Cancel() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.transition(PhaseCancelled) == nil
+}
+
+// provision moves the event to the Provisioning phase of the given plugin.
+func (l *Lifecycle) provision(plugin string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.transition(PhaseProvisioning); err != nil {
+		return err
+	}
+	l.plugin = plugin
+	return nil
+}
+
+// resume returns the index of the first plugin to run and the plugin data to pass to it.
+func (l *Lifecycle) resume() (int, *PluginData) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.next, l.data
+}
+
+// payloadSizes returns the totals of the bytes sent to the southbound services for the event of the organization.
+func (l *Lifecycle) payloadSizes(organization string) *southbound.PayloadSizes {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.payloads == nil {
+		l.payloads = southbound.NewPayloadSizes(organization)
+	}
+	return l.payloads
+}
+
+// pluginDone records that the plugin with the given index completed the event.
+func (l *Lifecycle) pluginDone(index int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.next = index + 1
+}
+
+// pluginAttempted records an attempt of the plugin to handle the event, taking the given time.
+func (l *Lifecycle) pluginAttempted(plugin string, eventType string, elapsed time.Duration, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.result.EventType = eventType
+	result := l.result.pluginResult(plugin)
+	result.Attempts++
+	result.Duration += elapsed
+	result.Err = err
+	result.Status = PluginSucceeded
+	if err != nil {
+		result.Status = PluginFailed
+	}
+}
+
+// pluginSkipped records that the plugin does not handle the event.
+func (l *Lifecycle) pluginSkipped(plugin string, eventType string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.result.EventType = eventType
+	l.result.pluginResult(plugin).Status = PluginSkipped
+}
+
+// warn records an issue that does not fail the event.
+func (l *Lifecycle) warn(message string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.result.addWarning(message)
+}
+
+// recordInventory sets the resources recorded by the plugins so far on the result.
+func (l *Lifecycle) recordInventory(inventory *southbound.Inventory) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.result.Inventory = inventory
+}
+
+// recordPayloads sets the bytes sent to the southbound services so far on the result.
+func (l *Lifecycle) recordPayloads(payloads *southbound.PayloadSizes) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if bytes := payloads.Bytes(); len(bytes) > 0 {
+		l.result.PayloadBytes = bytes
+	}
+}
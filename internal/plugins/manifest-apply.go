@@ -0,0 +1,239 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package plugins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+)
+
+// ManifestDiff is the difference between the ADM deployments of a project and the deployments of an extensions
+// manifest. A deployment whose package, version or profile changed is both deleted and created.
+type ManifestDiff struct {
+	ManifestRelease string
+	// deployment packages uploaded to the catalog for the created deployments
+	Packages  []PlannedPackage
+	Create    []PlannedDeployment
+	Delete    []PlannedDeployment
+	Unchanged []PlannedDeployment
+}
+
+// IsEmpty returns true if the project deployments already match the manifest.
+func (d ManifestDiff) IsEmpty() bool {
+	return len(d.Create) == 0 && len(d.Delete) == 0
+}
+
+// ApplyManifestOptions selects the manifest applied to a project by ApplyManifest.
+type ApplyManifestOptions struct {
+	// manifest tag to apply, defaults to the configured manifest tag
+	ManifestTag string
+	// manifest tag the project was last provisioned with. Deployments of this manifest that the new manifest no
+	// longer lists are deleted. If empty, only deployments listed in the new manifest are considered
+	PreviousManifestTag string
+	// work out the changes without making them
+	DryRun bool
+}
+
+// DiffManifestDeployments works out the deployments to create and delete so that the existing deployments of a
+// project, keyed by display name, match the manifest. Only deployments listed in the manifest or the previous
+// manifest are considered, so deployments created by users are left alone. Deployments that the provisioning
+// profile of the event does not allow are left in place, as they are by the extensions plugin.
+func DiffManifestDeployments(manifest *Manifest, previous *Manifest, existing map[string]southbound.DeploymentInfo, event Event) ManifestDiff {
+	diff := ManifestDiff{ManifestRelease: manifest.Metadata.Release}
+	listed := map[string]bool{}
+	deleteExisting := func(displayName string, appName string) {
+		if deployment, ok := existing[displayName]; ok && deployment.AppName == appName {
+			diff.Delete = append(diff.Delete, PlannedDeployment{
+				Name:        deployment.AppName,
+				DisplayName: deployment.DisplayName,
+				Profile:     deployment.ProfileName,
+				Version:     deployment.AppVersion,
+			})
+		}
+	}
+
+	for _, dl := range manifest.Lpke.DeploymentList {
+		listed[dl.DisplayName] = true
+		if strings.EqualFold(dl.DesiredState, DesiredStateAbsent) {
+			deleteExisting(dl.DisplayName, dl.DpName)
+			continue
+		}
+		if !event.Profile.AllowsDeploymentPackage(dl.DpName) || !event.Profile.AllowsDeploymentProfile(dl.DpProfileName) {
+			continue
+		}
+		wanted := PlannedDeployment{
+			Name:        dl.DpName,
+			DisplayName: dl.DisplayName,
+			Profile:     dl.DpProfileName,
+			Version:     dl.DpVersion,
+			Labels:      deploymentLabelSets(dl, event),
+		}
+		deployment, exists := existing[dl.DisplayName]
+		if exists && deployment.AppName == dl.DpName && deployment.AppVersion == dl.DpVersion && deployment.ProfileName == dl.DpProfileName {
+			diff.Unchanged = append(diff.Unchanged, wanted)
+			continue
+		}
+		if exists {
+			deleteExisting(dl.DisplayName, deployment.AppName)
+		}
+		diff.Create = append(diff.Create, wanted)
+	}
+
+	if previous != nil {
+		for _, dl := range previous.Lpke.DeploymentList {
+			if !listed[dl.DisplayName] {
+				listed[dl.DisplayName] = true
+				deleteExisting(dl.DisplayName, dl.DpName)
+			}
+		}
+	}
+
+	packages := map[string]bool{}
+	for _, deployment := range diff.Create {
+		if !packages[deployment.Name] {
+			packages[deployment.Name] = true
+			diff.Packages = append(diff.Packages, PlannedPackage{Name: deployment.Name, Version: deployment.Version})
+		}
+	}
+	return diff
+}
+
+// ApplyManifest brings the ADM deployments of a single project in line with an extensions manifest, creating and
+// deleting only the deployments that changed rather than provisioning the whole project again. The packages of the
+// created deployments are uploaded to the catalog of the project first. The recorded inventory of the project is
+// updated if the controller namespace is known.
+func ApplyManifest(ctx context.Context, configuration config.Configuration, event Event, options ApplyManifestOptions) (*ManifestDiff, error) {
+	if configuration.AdmServer == "" {
+		return nil, errors.New("no ADM server is configured")
+	}
+	if options.ManifestTag != "" {
+		configuration.ManifestTag = options.ManifestTag
+	}
+	orgExtensions, err := loadOrgExtensions(configuration)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := LoadManifest(ctx, configuration)
+	if err != nil {
+		return nil, err
+	}
+	manifest = orgExtensions[event.Organization].apply(manifest)
+	if errs := ValidateManifest(manifest); len(errs) > 0 {
+		return nil, fmt.Errorf("manifest %s is invalid: %w", configuration.ManifestTag, errors.Join(errs...))
+	}
+	if err := checkQuota(QuotaExtensionDeployments, "MAX_EXTENSION_DEPLOYMENTS", countDeployments(manifest, event), configuration.MaxExtensionDeployments); err != nil {
+		return nil, err
+	}
+	var previous *Manifest
+	if options.PreviousManifestTag != "" && options.PreviousManifestTag != configuration.ManifestTag {
+		previousConfiguration := configuration
+		previousConfiguration.UseLocalManifest = ""
+		previousConfiguration.ManifestTag = options.PreviousManifestTag
+		previous, err = LoadManifest(ctx, previousConfiguration)
+		if err != nil {
+			log.Warnf("Unable to load previous manifest %s, deployments it no longer lists are kept: %v", options.PreviousManifestTag, err)
+			previous = nil
+		} else {
+			previous = orgExtensions[event.Organization].apply(previous)
+		}
+	}
+
+	ad, err := AppDeploymentFactory(configuration)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := ad.ListDeployments(ctx, event.UUID, southbound.DeploymentFilter{})
+	if err != nil {
+		return nil, err
+	}
+	diff := DiffManifestDeployments(manifest, previous, existing, event)
+	if options.DryRun || diff.IsEmpty() {
+		return &diff, nil
+	}
+
+	cat, err := CatalogFactory(configuration)
+	if err != nil {
+		return nil, err
+	}
+	if len(diff.Packages) > 0 {
+		pkgOras, err := OrasFactory(configuration)
+		if err != nil {
+			return nil, err
+		}
+		defer pkgOras.Close()
+		upload := &southbound.CatalogUpload{}
+		for _, dp := range manifest.Lpke.DeploymentPackages {
+			name := path.Base(dp.Dpkg)
+			if strings.EqualFold(dp.DesiredState, DesiredStateAbsent) || !slices.ContainsFunc(diff.Packages, func(p PlannedPackage) bool { return p.Name == name }) {
+				continue
+			}
+			log.Infof("Uploading deployment package %s version %s to project %s", name, dp.Version, event.Name)
+			if err := addDeploymentPackage(ctx, pkgOras, upload, dp.Dpkg, dp.Version); err != nil {
+				return nil, err
+			}
+		}
+		if err := cat.CommitUpload(ctx, event.UUID, upload); err != nil {
+			return nil, err
+		}
+	}
+
+	// Deployments are deleted first, as a replaced deployment keeps its display name
+	for _, dl := range diff.Delete {
+		if err := ad.DeleteDeployment(ctx, dl.Name, dl.DisplayName, dl.Version, dl.Profile, event.UUID, true); err != nil {
+			return nil, err
+		}
+	}
+	for _, dl := range diff.Create {
+		targets, err := deploymentTargets(ctx, cat, event.UUID, dl.Name, dl.Version, dl.Labels)
+		if err != nil {
+			return nil, err
+		}
+		if err := ad.CreateDeployment(ctx, dl.Name, dl.DisplayName, dl.Version, dl.Profile, event.UUID, targets); err != nil {
+			return nil, err
+		}
+	}
+
+	if configuration.PodNamespace != "" {
+		if err := updateInventoryDeployments(ctx, configuration, ad, event, manifest, diff); err != nil {
+			log.Warnf("Unable to update the inventory of project %s: %v", event.Name, err)
+		}
+	}
+	return &diff, nil
+}
+
+// updateInventoryDeployments replaces the deployments recorded in the inventory of the project with the deployments
+// of the manifest. Deployments that the diff left unchanged keep their recorded hash. Projects without an inventory
+// are left without one.
+func updateInventoryDeployments(ctx context.Context, configuration config.Configuration, ad AppDeployment, event Event, manifest *Manifest, diff ManifestDiff) error {
+	store, err := InventoryStoreFactory(configuration)
+	if err != nil {
+		return err
+	}
+	inventory, err := store.Load(ctx, event.UUID)
+	if err != nil || inventory == nil {
+		return err
+	}
+	hashes := deploymentHashes(manifest, event)
+	for displayName, hash := range recordedDeploymentHashes(inventory) {
+		if slices.ContainsFunc(diff.Unchanged, func(d PlannedDeployment) bool { return d.DisplayName == displayName }) {
+			hashes[displayName] = hash
+		}
+	}
+	pluginData := NewPluginData()
+	if err := recordDeployments(ctx, ad, event.UUID, manifest, hashes, pluginData); err != nil {
+		return err
+	}
+	inventory.Deployments = pendingInventory(pluginData).Deployments
+	inventory.Updated = time.Now().UTC()
+	return store.Save(ctx, inventory)
+}
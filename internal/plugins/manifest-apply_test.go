@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"context"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+)
+
+const applyManifest = `---
+metadata:
+  schemaVersion: 0.3.0
+  release: 1.3.0
+lpke:
+  deploymentPackages:
+    - dpkg: registry/edge-node/dp/base-extensions
+      version: 0.2.0
+  deploymentList:
+    - dpName: base-extensions
+      displayName: base-extensions-baseline
+      dpProfileName: baseline
+      dpVersion: 0.2.0
+    - dpName: base-extensions
+      displayName: base-extensions-restricted
+      dpProfileName: restricted
+      dpVersion: 0.2.0
+      allAppTargetClusters:
+        - key: color
+          val: red
+    - dpName: base-extensions
+      displayName: base-extensions-privileged
+      dpProfileName: privileged
+      dpVersion: 0.1.0
+      desiredState: absent`
+
+func (s *PluginsTestSuite) TestDiffManifestDeployments() {
+	manifest, err := ParseManifest([]byte(applyManifest))
+	s.NoError(err)
+	previous, err := ParseManifest([]byte(`---
+lpke:
+  deploymentList:
+    - dpName: base-extensions
+      displayName: base-extensions-baseline
+      dpProfileName: baseline
+      dpVersion: 0.1.0
+    - dpName: skupper
+      displayName: skupper
+      dpProfileName: default
+      dpVersion: 0.1.4`))
+	s.NoError(err)
+
+	existing := map[string]southbound.DeploymentInfo{
+		"base-extensions-baseline":   {DisplayName: "base-extensions-baseline", AppName: "base-extensions", AppVersion: "0.2.0", ProfileName: "baseline"},
+		"base-extensions-restricted": {DisplayName: "base-extensions-restricted", AppName: "base-extensions", AppVersion: "0.1.0", ProfileName: "restricted"},
+		"base-extensions-privileged": {DisplayName: "base-extensions-privileged", AppName: "base-extensions", AppVersion: "0.1.0", ProfileName: "privileged"},
+		"skupper":                    {DisplayName: "skupper", AppName: "skupper", AppVersion: "0.1.4", ProfileName: "default"},
+		"my-app":                     {DisplayName: "my-app", AppName: "my-app", AppVersion: "1.0.0", ProfileName: "default"},
+	}
+	diff := DiffManifestDeployments(manifest, previous, existing, Event{DeploymentLabels: map[string]string{"region": "eu"}})
+	s.False(diff.IsEmpty())
+	s.Equal("1.3.0", diff.ManifestRelease)
+	s.Equal([]PlannedDeployment{
+		{Name: "base-extensions", DisplayName: "base-extensions-baseline", Profile: "baseline", Version: "0.2.0", Labels: []map[string]string{{"region": "eu"}}},
+	}, diff.Unchanged)
+	s.Equal([]PlannedDeployment{
+		{Name: "base-extensions", DisplayName: "base-extensions-restricted", Profile: "restricted", Version: "0.2.0", Labels: []map[string]string{{"color": "red", "region": "eu"}}},
+	}, diff.Create)
+	// The replaced and absent deployments, and the deployment the previous manifest listed, are deleted
+	s.Equal([]PlannedDeployment{
+		{Name: "base-extensions", DisplayName: "base-extensions-restricted", Profile: "restricted", Version: "0.1.0"},
+		{Name: "base-extensions", DisplayName: "base-extensions-privileged", Profile: "privileged", Version: "0.1.0"},
+		{Name: "skupper", DisplayName: "skupper", Profile: "default", Version: "0.1.4"},
+	}, diff.Delete)
+	s.Equal([]PlannedPackage{{Name: "base-extensions", Version: "0.2.0"}}, diff.Packages)
+
+	// Without the previous manifest, deployments it listed are kept
+	diff = DiffManifestDeployments(manifest, nil, existing, Event{})
+	s.Len(diff.Delete, 2)
+
+	// Deployments the provisioning profile does not allow are left in place
+	profile := &config.ProvisioningProfile{Name: "small", DeploymentProfiles: []string{"baseline"}}
+	diff = DiffManifestDeployments(manifest, nil, existing, Event{Profile: profile})
+	s.Empty(diff.Create)
+	s.Len(diff.Unchanged, 1)
+	s.Equal([]PlannedDeployment{
+		{Name: "base-extensions", DisplayName: "base-extensions-privileged", Profile: "privileged", Version: "0.1.0"},
+	}, diff.Delete)
+}
+
+func (s *PluginsTestSuite) TestApplyManifest() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	OrasFactory = NewTestOras
+	CatalogFactory = newTestCatalog
+	AppDeploymentFactory = newTestADM
+	mockCatalog = testCatalog{}
+	mockDeployments = map[string]*mockDeployment{
+		"base-extensions-0.2.0-baseline": {
+			name:        "base-extensions",
+			displayName: "base-extensions-baseline",
+			version:     "0.2.0",
+			profileName: "baseline",
+		},
+		"base-extensions-0.1.0-restricted": {
+			name:        "base-extensions",
+			displayName: "base-extensions-restricted",
+			version:     "0.1.0",
+			profileName: "restricted",
+		},
+		"my-app-1.0.0-default": {
+			name:        "my-app",
+			displayName: "my-app",
+			version:     "1.0.0",
+			profileName: "default",
+		},
+	}
+
+	configuration := config.Configuration{
+		AdmServer:        "http://admserver",
+		ManifestPath:     "/registry/edge-node/en/manifest",
+		ManifestTag:      "latest",
+		UseLocalManifest: applyManifest,
+	}
+	event := Event{Organization: "org", Name: "project", UUID: "foo"}
+
+	diff, err := ApplyManifest(ctx, configuration, event, ApplyManifestOptions{DryRun: true})
+	s.NoError(err)
+	s.Len(diff.Create, 1)
+	s.Len(diff.Delete, 1)
+	s.Contains(mockDeployments, "base-extensions-0.1.0-restricted")
+	s.Empty(mockCatalog.uploadedFiles)
+
+	diff, err = ApplyManifest(ctx, configuration, event, ApplyManifestOptions{})
+	s.NoError(err)
+	s.Len(diff.Unchanged, 1)
+	s.Len(mockDeployments, 3)
+	s.Contains(mockDeployments, "base-extensions-0.2.0-baseline")
+	s.Contains(mockDeployments, "base-extensions-0.2.0-restricted")
+	s.Contains(mockDeployments, "my-app-1.0.0-default")
+	s.Equal(map[string]string{"color": "red"}, mockDeployments["base-extensions-0.2.0-restricted"].labels)
+	s.Len(mockCatalog.uploadedFiles, 1)
+
+	// Applying the manifest again changes nothing
+	diff, err = ApplyManifest(ctx, configuration, event, ApplyManifestOptions{})
+	s.NoError(err)
+	s.True(diff.IsEmpty())
+
+	configuration.UseLocalManifest = `---
+lpke:
+  deploymentList:
+    - dpName: base-extensions
+      dpVersion: 0.2.0`
+	_, err = ApplyManifest(ctx, configuration, event, ApplyManifestOptions{})
+	s.ErrorContains(err, "is invalid")
+
+	configuration.AdmServer = ""
+	_, err = ApplyManifest(ctx, configuration, event, ApplyManifestOptions{})
+	s.ErrorContains(err, "no ADM server")
+}
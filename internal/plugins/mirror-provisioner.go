@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package plugins
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	mirroredArtifacts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tenant_controller_mirrored_artifacts_total",
+		Help: "Release service artifacts copied into tenant Harbor projects",
+	}, []string{"result"})
+
+	mirrorDuration = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name:       "tenant_controller_mirror_duration_seconds",
+		Help:       "Time spent copying a release service artifact into a tenant Harbor project",
+		Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(mirroredArtifacts, mirrorDuration)
+}
+
+type Mirror interface {
+	Copy(ctx context.Context, sourceRepository string, tag string, destinationRepository string, username string, password string) error
+}
+
+func NewMirror(configuration config.Configuration) (Mirror, error) {
+	options, err := southbound.ReleaseServiceOrasOptions(configuration)
+	if err != nil {
+		return nil, err
+	}
+	return southbound.NewOrasMirror(configuration.ReleaseServiceBase, options, configuration.HarborServer)
+}
+
+var MirrorFactory = NewMirror
+
+// MirrorProvisionerPlugin copies a baseline of release service images and charts into the Harbor project of a new
+// project, so that edge nodes pull them from Harbor instead of through the release service proxy.
+type MirrorProvisionerPlugin struct {
+	config config.Configuration
+}
+
+func NewMirrorProvisionerPlugin(configuration config.Configuration) *MirrorProvisionerPlugin {
+	return &MirrorProvisionerPlugin{
+		config: configuration,
+	}
+}
+
+func (p *MirrorProvisionerPlugin) Initialize(_ context.Context, _ *PluginData) error {
+	return nil
+}
+
+// CreateEvent mirrors the configured artifacts with the read-write robot account created by the Harbor plugin.
+// Mirroring is an optimization: an artifact that cannot be copied is still available through the proxy, so
+// failures are reported but do not fail the event.
+func (p *MirrorProvisionerPlugin) CreateEvent(ctx context.Context, event Event, pluginData *PluginData) error {
+	robot := pluginData.HarborCredentials()
+	username, password := robot.Username, robot.Token
+	if password == "" {
+		log.Infof("Harbor robot secret of project %s is not known, skipping mirroring", event.Name)
+		return nil
+	}
+
+	mirror, err := MirrorFactory(p.config)
+	if err != nil {
+		return err
+	}
+	harborProject := southbound.HarborProjectName(strings.ToLower(event.Organization), strings.ToLower(event.Name))
+	var failed []string
+	for i, artifact := range p.config.MirrorArtifacts {
+		event.ReportProgress("Mirroring artifacts %d/%d", i+1, len(p.config.MirrorArtifacts))
+		start := time.Now()
+		err = mirror.Copy(ctx, artifact.Repository, artifact.Tag, harborProject+"/"+artifact.Repository, username, password)
+		mirrorDuration.Observe(time.Since(start).Seconds())
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Warnf("Unable to mirror %s into Harbor project %s: %v", artifact, harborProject, err)
+			mirroredArtifacts.WithLabelValues("error").Inc()
+			failed = append(failed, artifact.String())
+			continue
+		}
+		mirroredArtifacts.WithLabelValues("success").Inc()
+	}
+	if len(failed) > 0 {
+		event.ReportWarning("Mirrored %d/%d artifacts, failed: %s", len(p.config.MirrorArtifacts)-len(failed),
+			len(p.config.MirrorArtifacts), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// EnsureEvent mirrors the configured artifacts again if the robot secret is known. Copying an artifact that is
+// already mirrored changes nothing.
+func (p *MirrorProvisionerPlugin) EnsureEvent(ctx context.Context, event Event, pluginData *PluginData) error {
+	return p.CreateEvent(ctx, event, pluginData)
+}
+
+// DeleteEvent does nothing, the mirrored artifacts are purged with the Harbor project.
+func (p *MirrorProvisionerPlugin) DeleteEvent(_ context.Context, _ Event, _ *PluginData) error {
+	return nil
+}
+
+func (p *MirrorProvisionerPlugin) Name() string {
+	return "Mirror Provisioner"
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type mirrorCopy struct {
+	source      string
+	destination string
+	username    string
+	password    string
+}
+
+type testMirror struct {
+	copies []mirrorCopy
+	// source repositories that fail to copy
+	failing map[string]bool
+}
+
+func (m *testMirror) Copy(_ context.Context, sourceRepository string, tag string, destinationRepository string, username string, password string) error {
+	if m.failing[sourceRepository] {
+		return errors.New("manifest unknown")
+	}
+	m.copies = append(m.copies, mirrorCopy{
+		source:      sourceRepository + ":" + tag,
+		destination: destinationRepository + ":" + tag,
+		username:    username,
+		password:    password,
+	})
+	return nil
+}
+
+func (s *PluginsTestSuite) TestMirrorPlugin() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	mirror := &testMirror{failing: map[string]bool{"edge-orch/en/images/missing": true}}
+	MirrorFactory = func(_ config.Configuration) (Mirror, error) { return mirror, nil }
+	defer func() { MirrorFactory = NewMirror }()
+
+	plugin := NewMirrorProvisionerPlugin(config.Configuration{
+		MirrorArtifacts: []config.MirrorArtifact{
+			{Repository: "edge-orch/en/charts/base-extensions", Tag: "0.2.0"},
+			{Repository: "edge-orch/en/images/missing", Tag: "1.0"},
+			{Repository: "edge-orch/en/images/agent", Tag: "1.0"},
+		},
+	})
+	var progress, warnings []string
+	event := Event{
+		EventType:    "create",
+		Organization: "Org",
+		Name:         "Proj",
+		progress:     func(message string) { progress = append(progress, message) },
+		warn:         func(message string) { warnings = append(warnings, message) },
+	}
+	succeeded := testutil.ToFloat64(mirroredArtifacts.WithLabelValues("success"))
+	failed := testutil.ToFloat64(mirroredArtifacts.WithLabelValues("error"))
+
+	// A failed copy is reported but does not fail the event
+	pluginData := newHarborPluginData(HarborRobot{Username: "robot", Token: "secret"}, HarborRobot{})
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	s.Equal([]mirrorCopy{
+		{
+			source:      "edge-orch/en/charts/base-extensions:0.2.0",
+			destination: "catalog-apps-org-proj/edge-orch/en/charts/base-extensions:0.2.0",
+			username:    "robot",
+			password:    "secret",
+		},
+		{
+			source:      "edge-orch/en/images/agent:1.0",
+			destination: "catalog-apps-org-proj/edge-orch/en/images/agent:1.0",
+			username:    "robot",
+			password:    "secret",
+		},
+	}, mirror.copies)
+	s.Equal([]string{
+		"Mirroring artifacts 1/3",
+		"Mirroring artifacts 2/3",
+		"Mirroring artifacts 3/3",
+	}, progress)
+	s.Equal([]string{"Mirrored 2/3 artifacts, failed: edge-orch/en/images/missing:1.0"}, warnings)
+	s.Equal(succeeded+2, testutil.ToFloat64(mirroredArtifacts.WithLabelValues("success")))
+	s.Equal(failed+1, testutil.ToFloat64(mirroredArtifacts.WithLabelValues("error")))
+
+	// Without the robot secret nothing can be pushed
+	mirror.copies = nil
+	pluginData = newHarborPluginData(HarborRobot{Username: "robot", Kept: true}, HarborRobot{})
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	s.Empty(mirror.copies)
+}
@@ -6,16 +6,25 @@ package plugins
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
 	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	"github.com/open-edge-platform/app-orch-tenant-controller/test/manifestgen"
 	"oras.land/oras-go/v2/content/file"
 )
 
 const (
 	HarborProjectID = 1234 // Mock project ID for testing
+
+	// names of the default robot accounts, see config.DefaultHarborRobots
+	harborReadWriteRobot = "catalog-apps-read-write"
+	harborReadOnlyRobot  = "catalog-apps-read-only"
 )
 
 // Catalog client mock
@@ -28,6 +37,21 @@ type upload struct {
 type testCatalog struct {
 	registries    map[string]southbound.RegistryAttributes
 	uploadedFiles map[string]upload
+	// number of committed uploads, and the error returned by CommitUpload
+	commits   int
+	commitErr error
+	// registries passed to the last ownership verification, the error it returns, and whether the project was wiped
+	verified  []string
+	verifyErr error
+	wiped     bool
+	// applications of the deployment packages, keyed by name:version
+	packageApps map[string][]string
+	// if set, changes registries as they are written, like a catalog that defaults fields
+	rewriteRegistry func(attrs southbound.RegistryAttributes) southbound.RegistryAttributes
+	// number of registry writes
+	registryWrites int
+	// deployment packages deleted, as name:version
+	deletedFiles []string
 }
 
 var mockCatalog testCatalog
@@ -43,10 +67,61 @@ func newTestCatalog(_ config.Configuration) (Catalog, error) {
 }
 
 func (c *testCatalog) CreateOrUpdateRegistry(_ context.Context, attrs southbound.RegistryAttributes) error {
+	c.registryWrites++
+	if c.rewriteRegistry != nil {
+		attrs = c.rewriteRegistry(attrs)
+	}
 	c.registries[attrs.Name] = attrs
 	return nil
 }
 
+func (c *testCatalog) GetRegistry(_ context.Context, _ string, name string) (southbound.RegistryAttributes, error) {
+	attrs, ok := c.registries[name]
+	if !ok {
+		return attrs, fmt.Errorf("registry %s %w", name, southbound.ErrNotFound)
+	}
+	return attrs, nil
+}
+
+func (c *testCatalog) ListProjectRegistries(_ context.Context, projectUUID string) ([]southbound.RegistryAttributes, error) {
+	registries := []southbound.RegistryAttributes{}
+	for _, name := range slices.Sorted(maps.Keys(c.registries)) {
+		attrs := c.registries[name]
+		if attrs.ProjectUUID == projectUUID {
+			attrs.AuthToken = ""
+			registries = append(registries, attrs)
+		}
+	}
+	return registries, nil
+}
+
+func (c *testCatalog) RegistryExists(_ context.Context, _ string, name string) (bool, error) {
+	_, ok := c.registries[name]
+	return ok, nil
+}
+
+func (c *testCatalog) UpdateRegistryCredentials(_ context.Context, _ string, name string, username string, authToken string) error {
+	attrs, ok := c.registries[name]
+	if !ok {
+		return fmt.Errorf("registry %s %w", name, southbound.ErrNotFound)
+	}
+	attrs.Username = username
+	attrs.AuthToken = authToken
+	c.registries[name] = attrs
+	return nil
+}
+
+func (c *testCatalog) ClearRegistryCredentials(_ context.Context, _ string, name string) error {
+	attrs, ok := c.registries[name]
+	if !ok {
+		return fmt.Errorf("registry %s %w", name, southbound.ErrNotFound)
+	}
+	attrs.Username = ""
+	attrs.AuthToken = ""
+	c.registries[name] = attrs
+	return nil
+}
+
 func (c *testCatalog) ListRegistries(_ context.Context) error {
 	return nil
 }
@@ -75,15 +150,55 @@ func (m *mockDynamicCatalog) CreateOrUpdateRegistry(_ context.Context, _ southbo
 	return nil
 }
 
+func (m *mockDynamicCatalog) ListProjectRegistries(_ context.Context, _ string) ([]southbound.RegistryAttributes, error) {
+	return nil, nil
+}
+
+func (m *mockDynamicCatalog) RegistryExists(_ context.Context, _ string, _ string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockDynamicCatalog) GetRegistry(_ context.Context, _ string, _ string) (southbound.RegistryAttributes, error) {
+	return southbound.RegistryAttributes{}, nil
+}
+
+func (m *mockDynamicCatalog) UpdateRegistryCredentials(_ context.Context, _ string, _ string, _ string, _ string) error {
+	return nil
+}
+
+func (m *mockDynamicCatalog) ClearRegistryCredentials(_ context.Context, _ string, _ string) error {
+	return nil
+}
+
 func (m *mockDynamicCatalog) UploadYAMLFile(_ context.Context, _ string, _ string, _ []byte, _ bool) error {
 	return nil
 }
 
+func (m *mockDynamicCatalog) CommitUpload(_ context.Context, _ string, _ *southbound.CatalogUpload) error {
+	return nil
+}
+
 func (m *mockDynamicCatalog) ListPublishers(_ context.Context) error {
 	return nil
 }
 
-func (m *mockDynamicCatalog) WipeProject(_ context.Context, _ string, _ string) error {
+func (m *mockDynamicCatalog) DeploymentPackageApplications(_ context.Context, _ string, _ string, _ string) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockDynamicCatalog) ListProjectFiles(_ context.Context, _ string) ([]southbound.ProjectFile, error) {
+	return nil, nil
+}
+
+func (m *mockDynamicCatalog) DeleteProjectFile(_ context.Context, _ string, _ southbound.ProjectFile) error {
+	return nil
+}
+
+func (m *mockDynamicCatalog) VerifyProjectOwnership(_ context.Context, _ string, _ []string) error {
+	return nil
+}
+
+func (m *mockDynamicCatalog) WipeProject(_ context.Context, _ string, _ string, _ func(message string)) error {
 	return nil
 }
 
@@ -98,22 +213,72 @@ func (c *testCatalog) UploadYAMLFile(_ context.Context, _ string, filePath strin
 	return nil
 }
 
+// CommitUpload records the files of the upload, or none of them if commitErr is set
+func (c *testCatalog) CommitUpload(ctx context.Context, projectUUID string, catalogUpload *southbound.CatalogUpload) error {
+	if c.commitErr != nil {
+		return c.commitErr
+	}
+	c.commits++
+	files := catalogUpload.Files()
+	for i, f := range files {
+		if err := c.UploadYAMLFile(ctx, projectUUID, f.Name, f.Artifact, i == len(files)-1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *testCatalog) InitializeClientSecret(_ context.Context) (string, error) {
 	return "", nil
 }
 
 func (c *testCatalog) ListPublishers(_ context.Context) error { return nil }
 
-func (c *testCatalog) WipeProject(_ context.Context, _ string, _ string) error {
+func (c *testCatalog) DeploymentPackageApplications(_ context.Context, _ string, name string, version string) ([]string, error) {
+	apps, ok := c.packageApps[name+":"+version]
+	if !ok {
+		return nil, fmt.Errorf("deployment package %s:%s %w", name, version, southbound.ErrNotFound)
+	}
+	return apps, nil
+}
+
+// ListProjectFiles returns the deployment packages of the uploaded files, named after the package as name_version.yaml
+func (c *testCatalog) ListProjectFiles(_ context.Context, _ string) ([]southbound.ProjectFile, error) {
+	files := []southbound.ProjectFile{}
+	for _, fileName := range slices.Sorted(maps.Keys(c.uploadedFiles)) {
+		name, version, ok := strings.Cut(strings.TrimSuffix(fileName, filepath.Ext(fileName)), "_")
+		if ok {
+			files = append(files, southbound.ProjectFile{Name: name, Version: version})
+		}
+	}
+	return files, nil
+}
+
+func (c *testCatalog) DeleteProjectFile(_ context.Context, _ string, file southbound.ProjectFile) error {
+	c.deletedFiles = append(c.deletedFiles, file.Name+":"+file.Version)
+	delete(c.uploadedFiles, file.Name+"_"+file.Version+".yaml")
+	return nil
+}
+
+func (c *testCatalog) VerifyProjectOwnership(_ context.Context, _ string, recorded []string) error {
+	c.verified = recorded
+	return c.verifyErr
+}
+
+func (c *testCatalog) WipeProject(_ context.Context, _ string, _ string, _ func(message string)) error {
+	c.wiped = true
 	return nil
 }
 
-// Oras client mock
+// Oras client mock. Besides the files listed in paths, it serves the generated manifest in testdata/extensions/generated
+// as the manifest tag "generated", along with its deployment packages.
+//
+//go:generate go run ../../cmd/genmanifest -packages 25 -deployments 100 -absent-every 5 -out testdata/extensions/generated
 type testOras struct {
 	dest string
 }
 
-func NewTestOras(_ string) (Oras, error) {
+func NewTestOras(_ config.Configuration) (Oras, error) {
 	return &testOras{}, nil
 }
 
@@ -130,9 +295,11 @@ var paths = map[string]string{
 	"/registry/edge-node/dp/base-extensions:0.2.0": "base-extensions_0.2.0.yaml",
 	"/registry/edge-node/dp/loadbalancer:0.2.6":    "loadbalancer_0.2.6.yaml",
 	"/registry/edge-node/dp/skupper:0.1.4":         "skupper_0.1.4.yaml",
+	"/registry/edge-node/en/manifest:generated":    filepath.Join("generated", manifestgen.ManifestFile),
 }
 
-func (o *testOras) Load(path string, version string) error {
+func (o *testOras) Load(_ context.Context, path string, version string) error {
+	o.Close()
 	var err error
 	o.dest, err = os.MkdirTemp("", "repo")
 	if err != nil {
@@ -146,9 +313,13 @@ func (o *testOras) Load(path string, version string) error {
 	defer func() { _ = fs.Close() }()
 
 	fullPath := path + ":" + version
+	fileName, ok := paths[fullPath]
+	if !ok {
+		fileName = filepath.Join("generated", filepath.Base(path)+"_"+version+".yaml")
+	}
 
-	srcFilePath := filepath.Join("testdata", "extensions", paths[fullPath])
-	destFilePath := filepath.Join(o.dest, paths[fullPath])
+	srcFilePath := filepath.Join("testdata", "extensions", fileName)
+	destFilePath := filepath.Join(o.dest, filepath.Base(fileName))
 
 	// Read in test data
 	data, err := os.ReadFile(srcFilePath)
@@ -180,24 +351,41 @@ type robot struct {
 	projectName string
 	robotName   string
 	robotID     int
+	access      []config.HarborRobotAccess
+	disabled    bool
 }
 
 type testHarbor struct {
 	configurations  int
 	createdProjects map[string]string
+	storageLimits   map[string]int64
+	contentTrust    map[string]southbound.HarborContentTrust
 	permissions     []permission
 	robots          map[string]robot
+	repositories    map[string]string
+	labels          map[int]southbound.HarborLabel
+	storageUsed     int64
+	requestTimeout  time.Duration
+	// times the admin credential was reloaded
+	credentialReloads int
 }
 
 var testHarborInstance *testHarbor
 
-func NewTestHarbor(_ context.Context, _ string, _ string, _ string, _ string) (Harbor, error) {
+// testAdminSecret is the Harbor admin credential passed to the Harbor factory
+var testAdminSecret = config.SecretRef{Namespace: "harbor", Name: "credential", Key: config.DefaultHarborAdminCredentialKey}
+
+func NewTestHarbor(_ context.Context, _ string, _ string, _ config.SecretRef) (Harbor, error) {
 	if testHarborInstance == nil {
 		testHarborInstance = &testHarbor{
 			configurations:  0,
 			createdProjects: map[string]string{},
+			storageLimits:   map[string]int64{},
+			contentTrust:    map[string]southbound.HarborContentTrust{},
 			permissions:     []permission{},
 			robots:          map[string]robot{},
+			repositories:    map[string]string{},
+			labels:          map[int]southbound.HarborLabel{},
 		}
 	}
 	return testHarborInstance, nil
@@ -208,9 +396,28 @@ func (t *testHarbor) Configurations(_ context.Context) error {
 	return nil
 }
 
-func (t *testHarbor) CreateProject(_ context.Context, org string, displayName string) error {
+func (t *testHarbor) CreateProject(_ context.Context, org string, displayName string, storageLimit int64) error {
 	name := org + "-" + displayName
 	t.createdProjects[name] = name
+	t.storageLimits[name] = storageLimit
+	return nil
+}
+
+func (t *testHarbor) SetProjectStorageLimit(_ context.Context, org string, displayName string, storageLimit int64) error {
+	name := org + "-" + displayName
+	if _, ok := t.createdProjects[name]; !ok {
+		return fmt.Errorf("project %s not found", name)
+	}
+	t.storageLimits[name] = storageLimit
+	return nil
+}
+
+func (t *testHarbor) SetProjectContentTrust(_ context.Context, org string, displayName string, trust southbound.HarborContentTrust) error {
+	name := org + "-" + displayName
+	if _, ok := t.createdProjects[name]; !ok {
+		return fmt.Errorf("project %s not found", name)
+	}
+	t.contentTrust[name] = trust
 	return nil
 }
 
@@ -219,37 +426,94 @@ func (t *testHarbor) SetMemberPermissions(_ context.Context, roleID int, _ strin
 	return nil
 }
 
+// SetMemberRole changes the role of the group, which must have been made a member before
+func (t *testHarbor) SetMemberRole(_ context.Context, roleID int, _ string, displayName string, groupName string) error {
+	found := false
+	for i, p := range t.permissions {
+		if p.groupName == groupName && p.projectID == displayName {
+			t.permissions[i].roleID = roleID
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("group %s %w", groupName, southbound.ErrNotFound)
+	}
+	return nil
+}
+
 func (t *testHarbor) Ping(_ context.Context) error {
 	return nil
 }
 
+func (t *testHarbor) NegotiateCapabilities(_ context.Context) (southbound.HarborCapabilities, error) {
+	return southbound.HarborCapabilities{Version: "v2.10", ScanStop: true}, nil
+}
+
+func (t *testHarbor) ReloadCredentials(_ context.Context) (bool, error) {
+	t.credentialReloads++
+	return true, nil
+}
+
+func (t *testHarbor) SetRequestTimeout(timeout time.Duration) {
+	t.requestTimeout = timeout
+}
+
 func (t *testHarbor) GetProjectID(_ context.Context, _ string, _ string) (int, error) {
 	return HarborProjectID, nil
 }
 
 var nextRobotID = 1
 
-func (t *testHarbor) CreateRobot(_ context.Context, robotName string, org string, displayName string) (string, string, error) {
+func (t *testHarbor) createRobot(robotName string, org string, displayName string, access []config.HarborRobotAccess) string {
 	// robot$catalog-apps-coke-proj1+catalog-apps-read-write
 	robotName = fmt.Sprintf("robot$catalog-apps-%s-%s+%s", org, displayName, robotName)
 	t.robots[robotName] = robot{
 		projectName: displayName,
 		robotName:   robotName,
 		robotID:     nextRobotID,
+		access:      access,
 	}
 	nextRobotID++
-	return "name", "secret", nil
+	return robotName
 }
 
-func (t *testHarbor) GetRobot(_ context.Context, _ string, _ string, robotName string, projectID int) (*southbound.HarborRobot, error) {
+func (t *testHarbor) CreateRobot(_ context.Context, robotName string, org string, displayName string, access []config.HarborRobotAccess) (string, string, error) {
+	fullName := t.createRobot(robotName, org, displayName, access)
+	if robotName == harborReadOnlyRobot {
+		return fullName, "pull-secret", nil
+	}
+	return fullName, "secret", nil
+}
+
+func (t *testHarbor) GetRobot(_ context.Context, org string, displayName string, robotName string, projectID int) (*southbound.HarborRobot, error) {
 	if projectID != HarborProjectID {
-		return nil, fmt.Errorf("robot %s projectID %d not found", robotName, projectID)
+		return nil, fmt.Errorf("robot %s projectID %d %w", robotName, projectID, southbound.ErrNotFound)
 	}
+	robotName = fmt.Sprintf("robot$catalog-apps-%s-%s+%s", org, displayName, robotName)
 	r, ok := t.robots[robotName]
 	if !ok {
-		return nil, fmt.Errorf("robot %s not found", robotName)
+		return nil, fmt.Errorf("robot %s %w", robotName, southbound.ErrNotFound)
 	}
-	return &southbound.HarborRobot{Name: r.robotName, ID: r.robotID}, nil
+	return &southbound.HarborRobot{Name: r.robotName, ID: r.robotID, Disable: r.disabled}, nil
+}
+
+func (t *testHarbor) SetRobotDisabled(_ context.Context, harborRobot southbound.HarborRobot, disabled bool) error {
+	r, ok := t.robots[harborRobot.Name]
+	if !ok || r.robotID != harborRobot.ID {
+		return fmt.Errorf("robot %s %w", harborRobot.Name, southbound.ErrNotFound)
+	}
+	r.disabled = disabled
+	t.robots[harborRobot.Name] = r
+	return nil
+}
+
+func (t *testHarbor) RefreshRobotSecret(_ context.Context, robotID int) (string, error) {
+	for _, r := range t.robots {
+		if r.robotID == robotID {
+			return fmt.Sprintf("refreshed-secret-%d", robotID), nil
+		}
+	}
+	return "", fmt.Errorf("refresh robot %d not found", robotID)
 }
 
 func (t *testHarbor) DeleteRobot(_ context.Context, robotID int) error {
@@ -263,10 +527,66 @@ func (t *testHarbor) DeleteRobot(_ context.Context, robotID int) error {
 }
 
 func (t *testHarbor) DeleteProject(_ context.Context, org string, displayName string) error {
-	delete(t.createdProjects, org+"-"+displayName)
+	name := org + "-" + displayName
+	for repositoryName, projectName := range t.repositories {
+		if projectName == name {
+			return fmt.Errorf("project %s still contains repository %s", name, repositoryName)
+		}
+	}
+	delete(t.createdProjects, name)
+	return nil
+}
+
+func (t *testHarbor) ListRepositories(_ context.Context, org string, displayName string) ([]southbound.HarborRepository, error) {
+	name := org + "-" + displayName
+	repositories := []southbound.HarborRepository{}
+	for repositoryName, projectName := range t.repositories {
+		if projectName == name {
+			repositories = append(repositories, southbound.HarborRepository{Name: repositoryName})
+		}
+	}
+	return repositories, nil
+}
+
+func (t *testHarbor) DeleteRepository(_ context.Context, _ string, _ string, repositoryName string) error {
+	delete(t.repositories, repositoryName)
+	return nil
+}
+
+var nextLabelID = 1
+
+func (t *testHarbor) ListProjectLabels(_ context.Context, projectID int) ([]southbound.HarborLabel, error) {
+	labels := []southbound.HarborLabel{}
+	for _, id := range slices.Sorted(maps.Keys(t.labels)) {
+		if t.labels[id].ProjectID == projectID {
+			labels = append(labels, t.labels[id])
+		}
+	}
+	return labels, nil
+}
+
+func (t *testHarbor) SetProjectLabel(_ context.Context, projectID int, label config.HarborLabel) (int, error) {
+	for id, existing := range t.labels {
+		if existing.ProjectID == projectID && existing.Name == label.Name {
+			t.labels[id] = southbound.HarborLabel{ID: id, Name: label.Name, Description: label.Description, Color: label.Color, Scope: "p", ProjectID: projectID}
+			return id, nil
+		}
+	}
+	id := nextLabelID
+	nextLabelID++
+	t.labels[id] = southbound.HarborLabel{ID: id, Name: label.Name, Description: label.Description, Color: label.Color, Scope: "p", ProjectID: projectID}
+	return id, nil
+}
+
+func (t *testHarbor) DeleteLabel(_ context.Context, labelID int) error {
+	delete(t.labels, labelID)
 	return nil
 }
 
+func (t *testHarbor) Statistics(_ context.Context) (southbound.HarborStatistics, error) {
+	return southbound.HarborStatistics{TotalProjectCount: int64(len(t.createdProjects)), TotalStorageConsumption: t.storageUsed}, nil
+}
+
 // ADM client mock
 type testADM struct {
 }
@@ -282,31 +602,46 @@ func newTestADM(_ config.Configuration) (AppDeployment, error) {
 	return mockADM, nil
 }
 
-func (t *testADM) ListDeploymentNames(_ context.Context, _ string) (map[string]string, error) {
-	displayName := make(map[string]string)
+func (t *testADM) ListDeployments(_ context.Context, _ string, _ southbound.DeploymentFilter) (map[string]southbound.DeploymentInfo, error) {
+	deployments := make(map[string]southbound.DeploymentInfo)
 	for _, md := range mockDeployments {
-		if md.name != "" {
-			displayName[md.name] = md.name
+		if md.displayName != "" {
+			deployments[md.displayName] = southbound.DeploymentInfo{
+				ID:          "id-" + md.displayName,
+				DisplayName: md.displayName,
+				AppName:     md.name,
+				AppVersion:  md.version,
+				ProfileName: md.profileName,
+			}
 		}
 	}
-	return displayName, nil
+	return deployments, nil
 }
 
 type mockDeployment struct {
 	name        string
+	displayName string
 	version     string
 	profileName string
 	projectID   string
-	labels      map[string]string
+	// first label set of the targets
+	labels  map[string]string
+	targets southbound.DeploymentTargets
 }
 
-func (t *testADM) CreateDeployment(_ context.Context, name string, _ string, version string, profileName string, projectID string, labels map[string]string) error {
+func (t *testADM) CreateDeployment(_ context.Context, name string, displayName string, version string, profileName string, projectID string, targets southbound.DeploymentTargets) error {
+	var labels map[string]string
+	if len(targets.LabelSets) > 0 {
+		labels = targets.LabelSets[0]
+	}
 	md := &mockDeployment{
 		name:        name,
+		displayName: displayName,
 		version:     version,
 		profileName: profileName,
 		projectID:   projectID,
 		labels:      labels,
+		targets:     targets,
 	}
 	mdKey := fmt.Sprintf("%s-%s-%s", md.name, md.version, md.profileName)
 	mockDeployments[mdKey] = md
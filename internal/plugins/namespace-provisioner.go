@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package plugins
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	"k8s.io/client-go/rest"
+)
+
+// maxNamespaceName is the longest Kubernetes namespace name
+const maxNamespaceName = 63
+
+// invalidNamespaceChars matches the runs of characters that cannot be used in a namespace name
+var invalidNamespaceChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+type NamespaceStore interface {
+	Ensure(ctx context.Context, name string, uuid string, organization string, project string) (bool, error)
+	Release(ctx context.Context, name string, uuid string) (bool, error)
+}
+
+func NewNamespaceStore(_ config.Configuration) (NamespaceStore, error) {
+	restConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	return southbound.NewNamespaceStore(restConfig)
+}
+
+var NamespaceStoreFactory = NewNamespaceStore
+
+// TenantNamespaceName returns the name of the namespace of a project: the prefix followed by the organization and
+// project name, lower cased with the characters a namespace name cannot have replaced by '-'. Names longer than a
+// namespace name allows are shortened, keeping a hash of the full name so that they stay unique.
+func TenantNamespaceName(prefix string, org string, name string) string {
+	namespace := prefix + strings.Trim(invalidNamespaceChars.ReplaceAllString(strings.ToLower(org+"-"+name), "-"), "-")
+	if len(namespace) <= maxNamespaceName {
+		return namespace
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(namespace)))[:8]
+	return strings.TrimRight(namespace[:maxNamespaceName-len(hash)-1], "-") + "-" + hash
+}
+
+// NamespaceProvisionerPlugin creates a Kubernetes namespace for each project, labeled with the project UUID and
+// organization, so that other components can colocate the resources of a project in it. A namespace that already
+// exists is labeled instead, and is kept when the project is deleted.
+type NamespaceProvisionerPlugin struct {
+	config config.Configuration
+}
+
+func NewNamespaceProvisionerPlugin(configuration config.Configuration) *NamespaceProvisionerPlugin {
+	return &NamespaceProvisionerPlugin{
+		config: configuration,
+	}
+}
+
+func (p *NamespaceProvisionerPlugin) Initialize(_ context.Context, _ *PluginData) error {
+	return nil
+}
+
+// CreateEvent creates the namespace of the project, or labels it if it already exists.
+func (p *NamespaceProvisionerPlugin) CreateEvent(ctx context.Context, event Event, pluginData *PluginData) error {
+	store, err := NamespaceStoreFactory(p.config)
+	if err != nil {
+		return err
+	}
+	name := TenantNamespaceName(p.config.TenantNamespaces.Prefix, event.Organization, event.Name)
+	event.ReportProgress("Creating namespace")
+	created, err := store.Ensure(ctx, name, event.UUID, event.Organization, event.Name)
+	if err != nil {
+		return err
+	}
+	if created {
+		log.Infof("Created namespace %s for project %s", name, event.Name)
+	}
+	recordInventory(pluginData, func(inventory *southbound.Inventory) {
+		inventory.Namespace = name
+	})
+	return nil
+}
+
+// UpdateEvent restores the labels of the namespace of the project, creating it if it is missing.
+func (p *NamespaceProvisionerPlugin) UpdateEvent(ctx context.Context, event Event, pluginData *PluginData) error {
+	return p.CreateEvent(ctx, event, pluginData)
+}
+
+// EnsureEvent creates the namespace of the project if it is missing, and restores its labels.
+func (p *NamespaceProvisionerPlugin) EnsureEvent(ctx context.Context, event Event, pluginData *PluginData) error {
+	return p.CreateEvent(ctx, event, pluginData)
+}
+
+// DeleteEvent deletes the namespace of the project if the controller created it, or else removes the labels of the
+// project from it. The namespace is kept if the project data is retained.
+func (p *NamespaceProvisionerPlugin) DeleteEvent(ctx context.Context, event Event, _ *PluginData) error {
+	name := TenantNamespaceName(p.config.TenantNamespaces.Prefix, event.Organization, event.Name)
+	if event.RetainData {
+		log.Infof("Keeping namespace %s of project %s, its data is retained", name, event.Name)
+		return nil
+	}
+	store, err := NamespaceStoreFactory(p.config)
+	if err != nil {
+		return err
+	}
+	event.ReportProgress("Deleting namespace")
+	deleted, err := store.Release(ctx, name, event.UUID)
+	if err != nil {
+		return err
+	}
+	if deleted {
+		log.Infof("Deleted namespace %s of project %s", name, event.Name)
+	}
+	return nil
+}
+
+func (p *NamespaceProvisionerPlugin) Name() string {
+	return "Namespace Provisioner"
+}
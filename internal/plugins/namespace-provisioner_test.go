@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+)
+
+// testNamespaceStore keeps the project UUID of each namespace, and whether the controller created it
+type testNamespaceStore struct {
+	owners  map[string]string
+	managed map[string]bool
+}
+
+func newTestNamespaceStore() *testNamespaceStore {
+	return &testNamespaceStore{owners: map[string]string{}, managed: map[string]bool{}}
+}
+
+func (n *testNamespaceStore) Ensure(_ context.Context, name string, uuid string, _ string, _ string) (bool, error) {
+	_, exists := n.owners[name]
+	n.owners[name] = uuid
+	if !exists {
+		n.managed[name] = true
+	}
+	return !exists, nil
+}
+
+func (n *testNamespaceStore) Release(_ context.Context, name string, uuid string) (bool, error) {
+	if n.owners[name] != uuid {
+		return false, nil
+	}
+	delete(n.owners, name)
+	if n.managed[name] {
+		delete(n.managed, name)
+		return true, nil
+	}
+	return false, nil
+}
+
+func (s *PluginsTestSuite) TestTenantNamespaceName() {
+	s.Equal("tenant-acme-web", TenantNamespaceName("tenant-", "Acme", "web"))
+	s.Equal("acme-corp-web-app", TenantNamespaceName("", "acme_corp", "web.app"))
+	long := TenantNamespaceName("tenant-", strings.Repeat("o", 40), strings.Repeat("p", 40))
+	s.Len(long, 63)
+	s.NotEqual(long, TenantNamespaceName("tenant-", strings.Repeat("o", 40), strings.Repeat("p", 41)))
+}
+
+func (s *PluginsTestSuite) TestNamespacePlugin() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	store := newTestNamespaceStore()
+	NamespaceStoreFactory = func(_ config.Configuration) (NamespaceStore, error) { return store, nil }
+	defer func() { NamespaceStoreFactory = NewNamespaceStore }()
+
+	plugin := NewNamespaceProvisionerPlugin(config.Configuration{TenantNamespaces: config.TenantNamespaces{Enabled: true, Prefix: "tenant-"}})
+	event := Event{Organization: "acme", Name: "web", UUID: "uuid-1"}
+	pluginData := NewPluginData()
+	s.NoError(plugin.CreateEvent(ctx, event, pluginData))
+	s.Equal("uuid-1", store.owners["tenant-acme-web"])
+	s.Equal("tenant-acme-web", pendingInventory(pluginData).Namespace)
+
+	// The namespace is kept while the project data is retained
+	event.RetainData = true
+	s.NoError(plugin.DeleteEvent(ctx, event, NewPluginData()))
+	s.Contains(store.owners, "tenant-acme-web")
+
+	event.RetainData = false
+	s.NoError(plugin.DeleteEvent(ctx, event, NewPluginData()))
+	s.NotContains(store.owners, "tenant-acme-web")
+
+	// A namespace that existed before the project is kept
+	store.owners["tenant-acme-api"] = ""
+	event = Event{Organization: "acme", Name: "api", UUID: "uuid-2"}
+	s.NoError(plugin.EnsureEvent(ctx, event, NewPluginData()))
+	s.Equal("uuid-2", store.owners["tenant-acme-api"])
+	s.NoError(plugin.DeleteEvent(ctx, event, NewPluginData()))
+	s.NotContains(store.owners, "tenant-acme-api")
+	s.False(store.managed["tenant-acme-api"])
+}
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"encoding/json"
+	"maps"
+	"strconv"
+	"sync"
+)
+
+// Sections of the plugin data
+const (
+	// HarborSection holds the robot accounts created by the Harbor plugin
+	HarborSection = "harbor"
+	// InventorySection holds the resources recorded by the plugins for the inventory
+	InventorySection = "inventory"
+)
+
+// PluginData is the state handed from plugin to plugin while an event is processed. Values are kept in sections,
+// one for each kind of state, so that keys written by different plugins cannot collide. The plugin data is kept
+// with the event lifecycle across retries, and marshals to JSON so that it can be checkpointed.
+type PluginData struct {
+	mu       sync.RWMutex
+	sections map[string]map[string]string
+}
+
+// NewPluginData returns empty plugin data.
+func NewPluginData() *PluginData {
+	return &PluginData{sections: make(map[string]map[string]string)}
+}
+
+// Get returns the value of the key in the section, or an empty string if it is not set.
+func (d *PluginData) Get(section string, key string) string {
+	value, _ := d.Lookup(section, key)
+	return value
+}
+
+// Lookup returns the value of the key in the section, and whether it is set.
+func (d *PluginData) Lookup(section string, key string) (string, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	value, ok := d.sections[section][key]
+	return value, ok
+}
+
+// Set sets the value of the key in the section.
+func (d *PluginData) Set(section string, key string, value string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.sections == nil {
+		d.sections = make(map[string]map[string]string)
+	}
+	if d.sections[section] == nil {
+		d.sections[section] = make(map[string]string)
+	}
+	d.sections[section][key] = value
+}
+
+// Section returns a copy of the values in the section.
+func (d *PluginData) Section(section string) map[string]string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return maps.Clone(d.sections[section])
+}
+
+func (d *PluginData) MarshalJSON() ([]byte, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return json.Marshal(d.sections)
+}
+
+func (d *PluginData) UnmarshalJSON(document []byte) error {
+	sections := make(map[string]map[string]string)
+	if err := json.Unmarshal(document, &sections); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sections = sections
+	return nil
+}
+
+// HarborRobot is a robot account created by the Harbor plugin for the plugins after it.
+type HarborRobot struct {
+	Username string
+	// empty if the robot account was kept with its current secret, which cannot be read back from Harbor
+	Token string
+	// true if an existing robot account was kept with its current secret
+	Kept bool
+}
+
+// HarborCredentials returns the read-write robot account of the project.
+func (d *PluginData) HarborCredentials() HarborRobot {
+	return d.harborRobot(HarborUsernameName, HarborTokenName, HarborCredentialsChangedName)
+}
+
+// SetHarborCredentials records the read-write robot account of the project.
+func (d *PluginData) SetHarborCredentials(robot HarborRobot) {
+	d.setHarborRobot(robot, HarborUsernameName, HarborTokenName, HarborCredentialsChangedName)
+}
+
+// HarborPullCredentials returns the pull-only robot account of the project.
+func (d *PluginData) HarborPullCredentials() HarborRobot {
+	return d.harborRobot(HarborPullUsernameName, HarborPullTokenName, HarborPullCredentialsChangedName)
+}
+
+// SetHarborPullCredentials records the pull-only robot account of the project.
+func (d *PluginData) SetHarborPullCredentials(robot HarborRobot) {
+	d.setHarborRobot(robot, HarborPullUsernameName, HarborPullTokenName, HarborPullCredentialsChangedName)
+}
+
+func (d *PluginData) harborRobot(usernameKey string, tokenKey string, changedKey string) HarborRobot {
+	return HarborRobot{
+		Username: d.Get(HarborSection, usernameKey),
+		Token:    d.Get(HarborSection, tokenKey),
+		// Credentials are only known to be kept if the Harbor plugin said so
+		Kept: d.Get(HarborSection, changedKey) == "false",
+	}
+}
+
+func (d *PluginData) setHarborRobot(robot HarborRobot, usernameKey string, tokenKey string, changedKey string) {
+	d.Set(HarborSection, usernameKey, robot.Username)
+	d.Set(HarborSection, tokenKey, robot.Token)
+	d.Set(HarborSection, changedKey, strconv.FormatBool(!robot.Kept))
+}
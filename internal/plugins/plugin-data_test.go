@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"encoding/json"
+)
+
+// newHarborPluginData returns plugin data with the robot accounts left by the Harbor plugin
+func newHarborPluginData(credentials HarborRobot, pullCredentials HarborRobot) *PluginData {
+	data := NewPluginData()
+	data.SetHarborCredentials(credentials)
+	data.SetHarborPullCredentials(pullCredentials)
+	return data
+}
+
+func (s *PluginsTestSuite) TestPluginData() {
+	data := NewPluginData()
+	s.Empty(data.Get("first", "result"))
+	_, ok := data.Lookup("first", "result")
+	s.False(ok)
+
+	// The same key in different sections does not collide
+	data.Set("first", "result", "done")
+	data.Set("second", "result", "failed")
+	s.Equal("done", data.Get("first", "result"))
+	s.Equal("failed", data.Get("second", "result"))
+	section := data.Section("first")
+	section["result"] = "changed"
+	s.Equal("done", data.Get("first", "result"))
+
+	// Credentials are only known to be kept if the Harbor plugin said so
+	s.Equal(HarborRobot{}, data.HarborCredentials())
+	data.SetHarborCredentials(HarborRobot{Username: "robot", Kept: true})
+	s.Equal(HarborRobot{Username: "robot", Kept: true}, data.HarborCredentials())
+	s.Equal(HarborRobot{}, data.HarborPullCredentials())
+
+	// The plugin data can be checkpointed as JSON
+	document, err := json.Marshal(data)
+	s.NoError(err)
+	restored := NewPluginData()
+	s.NoError(json.Unmarshal(document, restored))
+	s.Equal("failed", restored.Get("second", "result"))
+	s.Equal(HarborRobot{Username: "robot", Kept: true}, restored.HarborCredentials())
+	s.Error(json.Unmarshal([]byte(`{"first": "done"}`), restored))
+}
@@ -6,35 +6,199 @@ package plugins
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
 	nexushook "github.com/open-edge-platform/app-orch-tenant-controller/internal/nexus"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/retry"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
 	"github.com/open-edge-platform/orch-library/go/dazl"
 )
 
 var log = dazl.GetPackageLogger()
 
+// serviceBackoff is used while waiting for a service to become available, for about 8 minutes in total
+var serviceBackoff = retry.Backoff{
+	Initial:  5 * time.Second,
+	Max:      60 * time.Second,
+	Attempts: 12,
+	Jitter:   0.2,
+}
+
+// KeycloakHealthCheck checks that Keycloak is available to issue the service account tokens of the configuration
+var KeycloakHealthCheck = func(ctx context.Context, configuration config.Configuration) error {
+	return southbound.CheckKeycloak(ctx, configuration.KeycloakServiceBase)
+}
+
+// waitForKeycloak waits for Keycloak to be available, so that the plugins using service account tokens fail to
+// initialize with a Keycloak error rather than with token errors of the services they call.
+func waitForKeycloak(ctx context.Context, configuration config.Configuration) error {
+	if configuration.KeycloakServiceBase == "" {
+		log.Info("No keycloakServiceBase is set, skipping wait")
+		return nil
+	}
+	log.Infof("Waiting for Keycloak %s", configuration.KeycloakServiceBase)
+	err := retry.Do(ctx, "Keycloak health check", serviceBackoff, southbound.IsRetryable, func(ctx context.Context) error {
+		lctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		return KeycloakHealthCheck(lctx, configuration)
+	})
+	if err != nil {
+		return retryFailed("keycloak not available", err)
+	}
+	log.Info("Keycloak ready")
+	return nil
+}
+
+// retryFailed describes an operation that failed after retrying.
+func retryFailed(operation string, err error) error {
+	var retryErr *retry.Error
+	if errors.As(err, &retryErr) {
+		return fmt.Errorf("%s after %d attempts: %w", operation, retryErr.Attempts, err)
+	}
+	return fmt.Errorf("%s: %w", operation, err)
+}
+
 type Event struct {
 	EventType    string
 	Organization string
 	Name         string
 	UUID         string
 	Project      nexushook.NexusProjectInterface
+	// selected provisioning profile, nil if no profile applies
+	Profile *config.ProvisioningProfile
+	// labels and annotations changed by an update event
+	Changes nexushook.ProjectChanges
+	// issue new credentials even if the existing ones could be kept
+	RefreshCredentials bool
+	// archive the Harbor project of a deleted project instead of deleting it
+	RetainData bool
+	// when the resources of a project deactivated by a deactivate event are deleted
+	PurgeAfter time.Time
+	// project labels added to the ADM deployments of the project
+	DeploymentLabels map[string]string
+	// when the event was received, for measuring provisioning time
+	Received time.Time
+	// lifecycle of the event, if nil Dispatch runs all plugins with a new lifecycle
+	Lifecycle *Lifecycle
+	// plugins that handle an ensure event, all if empty. The other plugins only pass on what they provisioned
+	// earlier, and the Inventory Recorder always records the result. See CheckPluginNames for how plugins are named.
+	Plugins []string
+
+	// set by Dispatch to forward progress messages to the project watcher
+	progress func(message string)
+	// set by Dispatch to add warnings to the dispatch result
+	warn func(message string)
+}
+
+// ReportProgress publishes a human readable progress message for the event, e.g. "Uploading extensions 3/7".
+// Progress is best effort; failing to report it does not fail the event.
+func (e Event) ReportProgress(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	log.Infof("Project %s progress: %s", e.Name, message)
+	if e.progress != nil {
+		e.progress(message)
+	}
 }
 
-type PluginData *map[string]string
+// ReportWarning records an issue that does not fail the event, e.g. an artifact that could not be mirrored, in the
+// result of dispatching it.
+func (e Event) ReportWarning(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	log.Warnf("Project %s warning: %s", e.Name, message)
+	if e.warn != nil {
+		e.warn(message)
+	}
+}
+
+// Validate checks that the event can be processed. Invalid events fail permanently.
+func (e Event) Validate() error {
+	if !slices.Contains([]string{"create", "update", "delete", "ensure", "deactivate", "reactivate"}, e.EventType) {
+		return fmt.Errorf("%w: unknown event type: %s", southbound.ErrPermanent, e.EventType)
+	}
+	if strings.TrimSpace(e.Organization) == "" || strings.TrimSpace(e.Name) == "" || strings.TrimSpace(e.UUID) == "" {
+		return fmt.Errorf("%w: %s event is missing the organization, name or UUID of the project", southbound.ErrPermanent, e.EventType)
+	}
+	if len(e.Plugins) > 0 {
+		if e.EventType != "ensure" {
+			return fmt.Errorf("%w: only ensure events can be limited to some plugins, not %s events", southbound.ErrPermanent, e.EventType)
+		}
+		if err := CheckPluginNames(e.Plugins); err != nil {
+			return fmt.Errorf("%w: %w", southbound.ErrPermanent, err)
+		}
+	}
+	return nil
+}
+
+// handledBy reports whether the plugin handles the event, rather than only passing on what it provisioned earlier.
+func (e Event) handledBy(plugin Plugin) bool {
+	if len(e.Plugins) == 0 {
+		return true
+	}
+	// The recorder merges the resources of the selected plugins into the inventory for ensure events
+	if _, ok := plugin.(*InventoryRecorderPlugin); ok {
+		return true
+	}
+	return slices.ContainsFunc(e.Plugins, func(name string) bool { return pluginNamed(plugin, name) })
+}
 
 type Plugin interface {
 	Name() string
-	Initialize(context.Context, PluginData) error
-	CreateEvent(context.Context, Event, PluginData) error
-	DeleteEvent(context.Context, Event, PluginData) error
+	Initialize(context.Context, *PluginData) error
+	CreateEvent(context.Context, Event, *PluginData) error
+	DeleteEvent(context.Context, Event, *PluginData) error
+}
+
+// UpdatePlugin is implemented by plugins that can apply project changes in place. Plugins that do not
+// implement it are skipped for update events.
+type UpdatePlugin interface {
+	UpdateEvent(context.Context, Event, *PluginData) error
+}
+
+// EnsurePlugin is implemented by plugins that can converge an existing project to its desired state. Ensure events
+// create what is missing and apply settings that drifted, but never delete resources or issue new credentials, so
+// that they can be sent for projects that are already provisioned. Plugins that do not implement it are skipped for
+// ensure events.
+type EnsurePlugin interface {
+	EnsureEvent(context.Context, Event, *PluginData) error
+}
+
+// DeactivatePlugin is implemented by plugins whose resources can be disabled without deleting them. Deactivate
+// events disable the resources of a project deleted with a retention period, so that nothing can use them until
+// they are deleted by a delete event once the period is over. Reactivate events enable them again, for a deletion
+// that is undone. Plugins that do not implement it are skipped for both, their resources are kept as they are.
+type DeactivatePlugin interface {
+	DeactivateEvent(context.Context, Event, *PluginData) error
+	ReactivateEvent(context.Context, Event, *PluginData) error
+}
+
+// ProvisionedPlugin is implemented by plugins whose results other plugins use, such as the Harbor robot accounts
+// that the catalog registries are created with. When an event is limited to other plugins, it passes on what it
+// provisioned earlier, without changing anything.
+type ProvisionedPlugin interface {
+	LoadProvisioned(context.Context, Event, *PluginData) error
+}
+
+// SecretRefreshPlugin is implemented by plugins whose clients depend on the Keycloak service account secret. It is
+// called when the secret changes, so that the plugin picks up the new credentials without a restart.
+type SecretRefreshPlugin interface {
+	RefreshSecrets(context.Context) error
+}
+
+// HarborCredentialPlugin is implemented by plugins whose clients use the Harbor admin credential. It is called when
+// the credential is rotated, so that the plugin uses the new credential without a restart.
+type HarborCredentialPlugin interface {
+	ReloadHarborCredentials(context.Context) (bool, error)
 }
 
 var plugins = []Plugin{}
 
 func Initialize(ctx context.Context) error {
-	data := &map[string]string{}
+	data := NewPluginData()
 	for _, plugin := range plugins {
 		log.Infof("Initializing plugin %s", plugin.Name())
 		err := plugin.Initialize(ctx, data)
@@ -47,10 +211,113 @@ func Initialize(ctx context.Context) error {
 	return nil
 }
 
-func Dispatch(ctx context.Context, event Event, hook *nexushook.Hook) error {
-	data := &map[string]string{}
-	var err error
+// RefreshSecrets drops the cached service account tokens and lets the plugins that implement SecretRefreshPlugin
+// initialize their credentials again. Every plugin is refreshed even if another fails.
+func RefreshSecrets(ctx context.Context) error {
+	southbound.InvalidateTokenSources()
+	var errs []error
 	for _, plugin := range plugins {
+		refreshPlugin, ok := plugin.(SecretRefreshPlugin)
+		if !ok {
+			continue
+		}
+		log.Infof("Refreshing secrets of plugin %s", plugin.Name())
+		if err := refreshPlugin.RefreshSecrets(ctx); err != nil {
+			log.Warnf("Unable to refresh secrets of plugin %s: %v", plugin.Name(), southbound.ScrubError(err))
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReloadHarborCredentials lets the plugins that implement HarborCredentialPlugin read the Harbor admin credential
+// again. It returns true if the credential of any plugin changed. Every plugin is reloaded even if another fails.
+func ReloadHarborCredentials(ctx context.Context) (bool, error) {
+	changed := false
+	var errs []error
+	for _, plugin := range plugins {
+		reloadPlugin, ok := plugin.(HarborCredentialPlugin)
+		if !ok {
+			continue
+		}
+		pluginChanged, err := reloadPlugin.ReloadHarborCredentials(ctx)
+		if err != nil {
+			log.Warnf("Unable to reload the Harbor admin credential of plugin %s: %v", plugin.Name(), southbound.ScrubError(err))
+			errs = append(errs, err)
+			continue
+		}
+		if pluginChanged {
+			log.Infof("Plugin %s uses the new Harbor admin credential", plugin.Name())
+		}
+		changed = changed || pluginChanged
+	}
+	return changed, errors.Join(errs...)
+}
+
+// Dispatch sends the event to the plugins in order. If the event lifecycle shows that some plugins already
+// completed the event, dispatching resumes with the first plugin that did not. The result describes how each plugin
+// handled the event, including earlier attempts with the same lifecycle, and is returned even if the event failed.
+func Dispatch(ctx context.Context, event Event, hook *nexushook.Hook) (*DispatchResult, error) {
+	lifecycle := event.Lifecycle
+	if lifecycle == nil {
+		lifecycle = NewLifecycle(ctx)
+		defer lifecycle.cancel()
+		if err := lifecycle.Validate(); err != nil {
+			return lifecycle.Result(), err
+		}
+	}
+	first, data := lifecycle.resume()
+	payloads := lifecycle.payloadSizes(event.Organization)
+	err := dispatch(southbound.WithPayloadSizes(ctx, payloads), event, hook, lifecycle, first, data)
+	if data.Get(InventorySection, InventoryName) != "" {
+		lifecycle.recordInventory(pendingInventory(data))
+	}
+	lifecycle.recordPayloads(payloads)
+	return lifecycle.Result(), err
+}
+
+func dispatch(ctx context.Context, event Event, hook *nexushook.Hook, lifecycle *Lifecycle, first int, data *PluginData) error {
+	var err error
+	if hook != nil && event.Project != nil {
+		event.progress = func(message string) {
+			if progressErr := hook.SetWatcherStatusInProgress(event.Project, message); progressErr != nil {
+				log.Warnf("Unable to report progress for project %s: %v", event.Name, progressErr)
+			}
+		}
+	}
+	event.warn = lifecycle.warn
+	// A retry that resumes with a later plugin was admitted already
+	if event.EventType == "create" && first == 0 {
+		if err = admit(ctx, event, lifecycle); err != nil {
+			return err
+		}
+	}
+	for i := first; i < len(plugins); i++ {
+		plugin := plugins[i]
+		if !event.handledBy(plugin) {
+			log.Debugf("Plugin %s is not selected for the %s event", plugin.Name(), event.EventType)
+			if provisionedPlugin, ok := plugin.(ProvisionedPlugin); ok {
+				if err = provisionedPlugin.LoadProvisioned(ctx, event, data); err != nil {
+					return err
+				}
+			}
+			lifecycle.pluginSkipped(plugin.Name(), event.EventType)
+			lifecycle.pluginDone(i)
+			continue
+		}
+		updatePlugin, canUpdate := plugin.(UpdatePlugin)
+		ensurePlugin, canEnsure := plugin.(EnsurePlugin)
+		deactivatePlugin, canDeactivate := plugin.(DeactivatePlugin)
+		if (event.EventType == "update" && !canUpdate) || (event.EventType == "ensure" && !canEnsure) ||
+			((event.EventType == "deactivate" || event.EventType == "reactivate") && !canDeactivate) {
+			log.Debugf("Plugin %s does not handle %s events", plugin.Name(), event.EventType)
+			lifecycle.pluginSkipped(plugin.Name(), event.EventType)
+			lifecycle.pluginDone(i)
+			continue
+		}
+		if err = lifecycle.provision(plugin.Name()); err != nil {
+			return err
+		}
 		log.Infof("Sending event %v to %s", event, plugin.Name())
 		if hook != nil && event.Project != nil {
 			err = hook.SetWatcherStatusInProgress(event.Project, fmt.Sprintf("Processing project %s with %s", event.EventType, plugin.Name()))
@@ -58,30 +325,47 @@ func Dispatch(ctx context.Context, event Event, hook *nexushook.Hook) error {
 		if err != nil {
 			return err
 		}
+		start := time.Now()
 		if event.EventType == "create" {
 			err = plugin.CreateEvent(ctx, event, data)
 		} else if event.EventType == "delete" {
 			err = plugin.DeleteEvent(ctx, event, data)
+		} else if event.EventType == "update" {
+			err = updatePlugin.UpdateEvent(ctx, event, data)
+		} else if event.EventType == "ensure" {
+			err = ensurePlugin.EnsureEvent(ctx, event, data)
+		} else if event.EventType == "deactivate" {
+			err = deactivatePlugin.DeactivateEvent(ctx, event, data)
+		} else if event.EventType == "reactivate" {
+			err = deactivatePlugin.ReactivateEvent(ctx, event, data)
 		} else {
 			err = fmt.Errorf("unknown event type: %s", event.EventType)
 		}
+		lifecycle.pluginAttempted(plugin.Name(), event.EventType, time.Since(start), err)
 		if err != nil {
-			log.Infof("Error processing event %v by %s, error is %v", event, plugin.Name(), err)
+			log.Infof("Error processing event %v by %s, error is %v", event, plugin.Name(), southbound.ScrubError(err))
 		} else {
 			log.Infof("Successfully processed event %v by %s", event, plugin.Name())
 		}
 		if err != nil {
 			return err
 		}
+		lifecycle.pluginDone(i)
 	}
 	log.Infof("Done dispatching event: %v", event)
-	if event.EventType == "create" {
-		if hook != nil && event.Project != nil {
+	if hook != nil && event.Project != nil {
+		if event.EventType == "create" {
 			err = hook.UpdateProjectManifestTag(event.Project)
 			if err != nil {
 				return err
 			}
 		}
+		// The inventory on the watcher is only informational, failing to update it does not fail the event
+		if summary, ok := watcherInventory(event, data); ok {
+			if watcherErr := hook.SetWatcherInventory(event.Project, summary); watcherErr != nil {
+				log.Warnf("Unable to show the inventory of project %s on its watcher: %v", event.Name, watcherErr)
+			}
+		}
 	}
 	return nil
 }
@@ -90,6 +374,36 @@ func Register(plugin Plugin) {
 	plugins = append(plugins, plugin)
 }
 
+// Names returns the names of the registered plugins, in dispatch order.
+func Names() []string {
+	names := make([]string, 0, len(plugins))
+	for _, plugin := range plugins {
+		names = append(names, plugin.Name())
+	}
+	return names
+}
+
+// ErrUnknownPlugin is returned for plugin names that match none of the registered plugins
+var ErrUnknownPlugin = errors.New("unknown plugin")
+
+// CheckPluginNames returns an error wrapping ErrUnknownPlugin if one of the names matches none of the registered
+// plugins. Plugins are named by their name, or for provisioners by their name without the Provisioner suffix, in
+// any case: catalog names the Catalog Provisioner.
+func CheckPluginNames(names []string) error {
+	for _, name := range names {
+		if !slices.ContainsFunc(plugins, func(plugin Plugin) bool { return pluginNamed(plugin, name) }) {
+			return fmt.Errorf("%w %q, the plugins are: %s", ErrUnknownPlugin, name, strings.Join(Names(), ", "))
+		}
+	}
+	return nil
+}
+
+// pluginNamed reports whether the name names the plugin.
+func pluginNamed(plugin Plugin, name string) bool {
+	name = strings.TrimSpace(name)
+	return strings.EqualFold(plugin.Name(), name) || strings.EqualFold(strings.TrimSuffix(plugin.Name(), " Provisioner"), name)
+}
+
 func RemoveAllPlugins() {
 	plugins = []Plugin{}
 }
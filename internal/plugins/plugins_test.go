@@ -4,8 +4,14 @@
 package plugins
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
 	"github.com/stretchr/testify/suite"
 	"testing"
+	"time"
 )
 
 // Suite of plugins tests
@@ -25,6 +31,366 @@ func (s *PluginsTestSuite) SetupTest() {
 func (s *PluginsTestSuite) TearDownTest() {
 }
 
+// recordingPlugin records the events it receives. It does not handle update events.
+type recordingPlugin struct {
+	events []string
+}
+
+func (p *recordingPlugin) Name() string {
+	return "Recording"
+}
+
+func (p *recordingPlugin) Initialize(_ context.Context, _ *PluginData) error {
+	return nil
+}
+
+func (p *recordingPlugin) CreateEvent(_ context.Context, event Event, _ *PluginData) error {
+	p.events = append(p.events, event.EventType)
+	return nil
+}
+
+func (p *recordingPlugin) DeleteEvent(_ context.Context, event Event, _ *PluginData) error {
+	p.events = append(p.events, event.EventType)
+	return nil
+}
+
+type recordingUpdatePlugin struct {
+	recordingPlugin
+}
+
+func (p *recordingUpdatePlugin) UpdateEvent(_ context.Context, event Event, _ *PluginData) error {
+	p.events = append(p.events, event.EventType)
+	return nil
+}
+
+func (s *PluginsTestSuite) TestDispatchUpdate() {
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+
+	createOnly := &recordingPlugin{}
+	updater := &recordingUpdatePlugin{}
+	Register(createOnly)
+	Register(updater)
+
+	ctx := context.Background()
+	for _, eventType := range []string{"create", "update", "delete"} {
+		_, err := Dispatch(ctx, Event{EventType: eventType, Name: "foo", Organization: "org"}, nil)
+		s.NoError(err)
+	}
+	s.Equal([]string{"create", "delete"}, createOnly.events)
+	s.Equal([]string{"create", "update", "delete"}, updater.events)
+
+	_, err := Dispatch(ctx, Event{EventType: "rename", Name: "foo", Organization: "org"}, nil)
+	s.Error(err)
+}
+
+type recordingEnsurePlugin struct {
+	recordingPlugin
+}
+
+func (p *recordingEnsurePlugin) Name() string {
+	return "Ensuring"
+}
+
+func (p *recordingEnsurePlugin) EnsureEvent(_ context.Context, event Event, _ *PluginData) error {
+	p.events = append(p.events, event.EventType)
+	return nil
+}
+
+func (s *PluginsTestSuite) TestDispatchEnsure() {
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+
+	createOnly := &recordingPlugin{}
+	updater := &recordingUpdatePlugin{}
+	ensurer := &recordingEnsurePlugin{}
+	Register(createOnly)
+	Register(updater)
+	Register(ensurer)
+
+	result, err := Dispatch(context.Background(), Event{EventType: "ensure", Name: "foo", Organization: "org"}, nil)
+	s.NoError(err)
+	s.Empty(createOnly.events)
+	s.Empty(updater.events)
+	s.Equal([]string{"ensure"}, ensurer.events)
+	s.Equal(PluginSkipped, result.Plugin("Recording").Status)
+	s.Equal(PluginSucceeded, result.Plugin("Ensuring").Status)
+
+	s.NoError(Event{EventType: "ensure", Name: "foo", Organization: "org", UUID: "uuid"}.Validate())
+	s.ErrorIs(Event{EventType: "ensure", Name: "foo", Organization: "", UUID: "uuid"}.Validate(), southbound.ErrPermanent)
+	s.ErrorIs(Event{EventType: "ensure", Name: "foo", Organization: " ", UUID: "uuid"}.Validate(), southbound.ErrPermanent)
+}
+
+// passingPlugin passes on a value in the plugin data when an event is limited to other plugins
+type passingPlugin struct {
+	recordingEnsurePlugin
+}
+
+func (p *passingPlugin) Name() string {
+	return "Passing Provisioner"
+}
+
+func (p *passingPlugin) LoadProvisioned(_ context.Context, _ Event, data *PluginData) error {
+	data.Set("passing", "provisioned", "true")
+	return nil
+}
+
+func (s *PluginsTestSuite) TestDispatchSelectedPlugins() {
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+
+	passing := &passingPlugin{}
+	ensurer := &recordingEnsurePlugin{}
+	Register(passing)
+	Register(ensurer)
+	Register(NewInventoryRecorderPlugin(config.Configuration{}))
+
+	ctx := context.Background()
+	event := Event{EventType: "ensure", Name: "foo", Organization: "org", UUID: "uuid", Plugins: []string{"ENSURING"}, Lifecycle: NewLifecycle(ctx)}
+	s.NoError(event.Validate())
+	s.NoError(event.Lifecycle.Validate())
+	result, err := Dispatch(ctx, event, nil)
+	s.NoError(err)
+	s.Empty(passing.events)
+	s.Equal([]string{"ensure"}, ensurer.events)
+	s.Equal(PluginSkipped, result.Plugin("Passing Provisioner").Status)
+	s.Equal(PluginSucceeded, result.Plugin("Ensuring").Status)
+	// The recorder always handles the event, and the skipped plugin passes on what it provisioned
+	s.Equal(PluginSucceeded, result.Plugin("Inventory Recorder").Status)
+	_, data := event.Lifecycle.resume()
+	s.Equal("true", data.Get("passing", "provisioned"))
+
+	// Provisioners are also named without the suffix
+	passing.events = nil
+	_, err = Dispatch(ctx, Event{EventType: "ensure", Name: "foo", Organization: "org", Plugins: []string{"passing"}}, nil)
+	s.NoError(err)
+	s.Equal([]string{"ensure"}, passing.events)
+	s.Equal([]string{"ensure"}, ensurer.events)
+
+	err = Event{EventType: "ensure", Name: "foo", Organization: "org", UUID: "uuid", Plugins: []string{"catalog"}}.Validate()
+	s.ErrorIs(err, southbound.ErrPermanent)
+	s.ErrorIs(err, ErrUnknownPlugin)
+	s.ErrorContains(err, "Passing Provisioner, Ensuring, Inventory Recorder")
+	s.ErrorIs(Event{EventType: "create", Name: "foo", Organization: "org", UUID: "uuid", Plugins: []string{"passing"}}.Validate(), southbound.ErrPermanent)
+	s.NoError(CheckPluginNames([]string{"Passing Provisioner", " ensuring", "inventory recorder"}))
+}
+
+// admittingPlugin refuses new projects while full is set
+type admittingPlugin struct {
+	recordingUpdatePlugin
+	full     bool
+	admitted []string
+}
+
+func (p *admittingPlugin) Name() string {
+	return "Admitting"
+}
+
+func (p *admittingPlugin) Admit(_ context.Context, event Event) error {
+	if p.full {
+		return capacityExceeded(CapacityCatalogProjects, "MAX_CATALOG_PROJECTS", 3, 3)
+	}
+	p.admitted = append(p.admitted, event.Name)
+	return nil
+}
+
+func (s *PluginsTestSuite) TestDispatchAdmission() {
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+
+	first := &recordingPlugin{}
+	admitting := &admittingPlugin{full: true}
+	Register(first)
+	Register(admitting)
+
+	// A refused project is not provisioned by any plugin, including the plugins before the one that refused it
+	ctx := context.Background()
+	event := Event{EventType: "create", Name: "foo", Organization: "org", UUID: "uuid", Lifecycle: NewLifecycle(ctx)}
+	s.NoError(event.Lifecycle.Validate())
+	_, err := Dispatch(ctx, event, nil)
+	s.ErrorIs(err, ErrCapacityExceeded)
+	s.ErrorIs(err, southbound.ErrPermanent)
+	s.ErrorContains(err, "3 projects are provisioned in the catalog, the maximum is 3 (MAX_CATALOG_PROJECTS)")
+	s.Empty(first.events)
+	s.Empty(admitting.events)
+	s.Equal("Admitting", event.Lifecycle.Plugin())
+
+	// Other events are not checked
+	_, err = Dispatch(ctx, Event{EventType: "update", Name: "foo", Organization: "org"}, nil)
+	s.NoError(err)
+	s.Equal([]string{"update"}, admitting.events)
+
+	admitting.full = false
+	_, err = Dispatch(ctx, Event{EventType: "create", Name: "foo", Organization: "org"}, nil)
+	s.NoError(err)
+	s.Equal([]string{"foo"}, admitting.admitted)
+	s.Equal([]string{"create"}, first.events)
+}
+
+func (s *PluginsTestSuite) TestDispatchResult() {
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+	Register(&recordingPlugin{})
+
+	result, err := Dispatch(context.Background(), Event{EventType: "create", Name: "foo"}, nil)
+	s.NoError(err)
+	s.Equal("create", result.EventType)
+	s.Equal(PluginSucceeded, result.Plugin("Recording").Status)
+	s.Equal(1, result.Plugin("Recording").Attempts)
+	s.Contains(result.PluginTimes(), "Recording")
+	s.Empty(result.FailedPlugin())
+	s.Nil(result.Inventory)
+
+	// Plugins skipped for update events take no time
+	result, err = Dispatch(context.Background(), Event{EventType: "update", Name: "foo"}, nil)
+	s.NoError(err)
+	s.Equal(PluginSkipped, result.Plugin("Recording").Status)
+	s.Empty(result.PluginTimes())
+	s.Nil(result.Plugin("Unknown"))
+}
+
+// flakyPlugin fails the first create events, and passes the plugin data it finds on to later plugins
+type flakyPlugin struct {
+	name     string
+	failures int
+	calls    int
+	data     string
+}
+
+func (p *flakyPlugin) Name() string {
+	return p.name
+}
+
+func (p *flakyPlugin) Initialize(_ context.Context, _ *PluginData) error {
+	return nil
+}
+
+func (p *flakyPlugin) CreateEvent(_ context.Context, _ Event, data *PluginData) error {
+	p.calls++
+	document, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	p.data = string(document)
+	if p.calls <= p.failures {
+		return errors.New("unavailable")
+	}
+	data.Set(p.name, "result", "done")
+	return nil
+}
+
+func (p *flakyPlugin) DeleteEvent(_ context.Context, _ Event, _ *PluginData) error {
+	return nil
+}
+
+func (s *PluginsTestSuite) TestDispatchResumesFailedPlugin() {
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+	first := &flakyPlugin{name: "first"}
+	second := &flakyPlugin{name: "second", failures: 2}
+	Register(first)
+	Register(second)
+
+	lifecycle := NewLifecycle(context.Background())
+	s.NoError(lifecycle.Validate())
+	event := Event{EventType: "create", Name: "foo", Lifecycle: lifecycle}
+	_, err := Dispatch(context.Background(), event, nil)
+	s.Error(err)
+	s.Equal("Provisioning (second)", lifecycle.String())
+	s.Equal("second", lifecycle.Result().FailedPlugin())
+	_, err = Dispatch(context.Background(), event, nil)
+	s.Error(err)
+	_, err = Dispatch(context.Background(), event, nil)
+	s.NoError(err)
+
+	// Retries start with the failed plugin and keep the data of the completed plugins
+	s.Equal(1, first.calls)
+	s.Equal(3, second.calls)
+	s.JSONEq(`{"first": {"result": "done"}}`, second.data)
+
+	// The result covers all attempts
+	result := lifecycle.Result()
+	s.Equal(1, result.Plugin("first").Attempts)
+	s.Equal(3, result.Plugin("second").Attempts)
+	s.Equal(PluginSucceeded, result.Plugin("second").Status)
+	s.NoError(result.Plugin("second").Err)
+
+	s.NoError(lifecycle.Complete())
+	s.Equal(PhaseCompleted, lifecycle.Phase())
+	s.Error(lifecycle.Context().Err())
+}
+
+// deletingPlugin cancels the create events it handles, as the manager does when a delete event of the project is
+// received meanwhile
+type deletingPlugin struct {
+	recordingPlugin
+}
+
+func (p *deletingPlugin) CreateEvent(ctx context.Context, event Event, data *PluginData) error {
+	event.Lifecycle.Cancel()
+	return p.recordingPlugin.CreateEvent(ctx, event, data)
+}
+
+// The manager refuses the events of a project received after its delete event by cancelling them, and cancels the
+// create event being handled when the delete is received. Neither must provision the deleted project again.
+func (s *PluginsTestSuite) TestDispatchCancelledByDelete() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	testHarborInstance = nil
+	HarborFactory = NewTestHarbor
+	harbor, err := NewHarborProvisionerPlugin(ctx, "", "", testAdminSecret)
+	s.NoError(err)
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+
+	// A create event refused after the delete provisions nothing
+	Register(harbor)
+	lifecycle := NewLifecycle(ctx)
+	s.True(lifecycle.Cancel())
+	event := Event{EventType: "create", Name: "proj", Organization: "acme", UUID: "uuid-deleted", Lifecycle: lifecycle}
+	_, err = Dispatch(ctx, event, nil)
+	s.Error(err)
+	s.NotContains(testHarborInstance.createdProjects, "acme-proj")
+
+	// A create event cancelled by the delete while it is handled stops before the next plugin, also when it is retried
+	RemoveAllPlugins()
+	deleting := &deletingPlugin{}
+	Register(deleting)
+	Register(harbor)
+	event.Lifecycle = NewLifecycle(ctx)
+	s.NoError(event.Lifecycle.Validate())
+	_, err = Dispatch(ctx, event, nil)
+	s.Error(err)
+	_, err = Dispatch(ctx, event, nil)
+	s.Error(err)
+	s.Equal(PhaseCancelled, event.Lifecycle.Phase())
+	s.Equal([]string{"create"}, deleting.events)
+	s.NotContains(testHarborInstance.createdProjects, "acme-proj")
+}
+
+func (s *PluginsTestSuite) TestLifecycleTransitions() {
+	lifecycle := NewLifecycle(context.Background())
+	s.Equal(PhaseReceived, lifecycle.Phase())
+	s.Error(lifecycle.Complete())
+	s.Error(lifecycle.provision("plugin"))
+
+	s.NoError(lifecycle.Validate())
+	s.NoError(lifecycle.Fail(errors.New("invalid")))
+	s.Equal(PhaseFailed, lifecycle.Phase())
+	s.EqualError(lifecycle.Err(), "invalid")
+	s.True(lifecycle.Phase().Terminal())
+
+	// Terminal phases are final
+	s.False(lifecycle.Cancel())
+	s.Error(lifecycle.Validate())
+
+	lifecycle = NewLifecycle(context.Background())
+	s.True(lifecycle.Cancel())
+	s.Equal(PhaseCancelled, lifecycle.Phase())
+	s.Error(lifecycle.Context().Err())
+	s.Error(lifecycle.Validate())
+}
+
 func TestPlugins(t *testing.T) {
 	suite.Run(t, &PluginsTestSuite{})
 }
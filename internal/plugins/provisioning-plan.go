@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package plugins
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+)
+
+const (
+	// Placeholders for the robot account credentials, which only exist once Harbor has been provisioned
+	PlanHarborUsername     = "<harbor robot account>"
+	PlanHarborToken        = "<harbor robot token>"
+	PlanHarborPullUsername = "<harbor pull robot account>"
+	PlanHarborPullToken    = "<harbor pull robot token>"
+)
+
+type PlannedPackage struct {
+	Name    string
+	Version string
+}
+
+type PlannedDeployment struct {
+	Name        string
+	DisplayName string
+	Profile     string
+	Version     string
+	// cluster label sets, the deployment targets the clusters matching any of them
+	Labels []map[string]string
+}
+
+// ProvisioningPlan describes what provisioning a project would do, without doing any of it.
+type ProvisioningPlan struct {
+	Organization       string
+	Project            string
+	UUID               string
+	Profile            string
+	HarborProject      string
+	HarborStorageLimit int64
+	// signatures that Harbor requires of the artifacts of the project
+	HarborSigning      []string
+	Registries         []southbound.RegistryAttributes
+	StarterApps        []string
+	ManifestRelease    string
+	DeploymentPackages []PlannedPackage
+	Deployments        []PlannedDeployment
+	RemovedDeployments []PlannedDeployment
+}
+
+// PlanProvisioning works out the Harbor project, catalog registries and extensions that a create event would
+// provision. Only the extensions manifest is read; no tenant resources are created.
+func PlanProvisioning(ctx context.Context, configuration config.Configuration, event Event) (*ProvisioningPlan, error) {
+	org := strings.ToLower(event.Organization)
+	name := strings.ToLower(event.Name)
+	plan := &ProvisioningPlan{
+		Organization:  event.Organization,
+		Project:       event.Name,
+		UUID:          event.UUID,
+		HarborProject: southbound.HarborProjectName(org, name),
+	}
+	if event.Profile != nil {
+		plan.Profile = event.Profile.Name
+		plan.HarborStorageLimit = event.Profile.HarborStorageLimit
+		plan.HarborSigning = event.Profile.HarborSigning
+	}
+
+	registries, err := loadRegistryTemplates(configuration)
+	if err != nil {
+		return nil, err
+	}
+	data := newRegistryTemplateData(configuration, event,
+		HarborRobot{Username: PlanHarborUsername, Token: PlanHarborToken},
+		HarborRobot{Username: PlanHarborPullUsername, Token: PlanHarborPullToken})
+	// The plan is for a new project, so the registries are named with the configured affixes
+	affixes := registryAffixes{prefix: configuration.CatalogRegistryPrefix, suffix: configuration.CatalogRegistrySuffix}
+	for _, registry := range registries {
+		attrs, err := registry.expand(data)
+		if err != nil {
+			return nil, err
+		}
+		attrs.Name = affixes.name(attrs.Name)
+		plan.Registries = append(plan.Registries, attrs)
+	}
+
+	starterApps, err := loadStarterApps(configuration)
+	if err != nil {
+		return nil, err
+	}
+	for _, app := range starterApps {
+		plan.StarterApps = append(plan.StarterApps, app.Name)
+	}
+
+	manifest, err := LoadManifestForOrganization(ctx, configuration, event.Organization)
+	if err != nil {
+		return nil, err
+	}
+	plan.ManifestRelease = manifest.Metadata.Release
+	for _, dp := range manifest.Lpke.DeploymentPackages {
+		if strings.EqualFold(dp.DesiredState, DesiredStateAbsent) || !event.Profile.AllowsDeploymentPackage(path.Base(dp.Dpkg)) {
+			continue
+		}
+		plan.DeploymentPackages = append(plan.DeploymentPackages, PlannedPackage{Name: path.Base(dp.Dpkg), Version: dp.Version})
+	}
+	if configuration.AdmServer == "" {
+		return plan, nil
+	}
+	for _, dl := range manifest.Lpke.DeploymentList {
+		deployment := PlannedDeployment{
+			Name:        dl.DpName,
+			DisplayName: dl.DisplayName,
+			Profile:     dl.DpProfileName,
+			Version:     dl.DpVersion,
+			Labels:      deploymentLabelSets(dl, event),
+		}
+		if strings.EqualFold(dl.DesiredState, DesiredStateAbsent) {
+			plan.RemovedDeployments = append(plan.RemovedDeployments, deployment)
+		} else if event.Profile.AllowsDeploymentPackage(dl.DpName) && event.Profile.AllowsDeploymentProfile(dl.DpProfileName) {
+			plan.Deployments = append(plan.Deployments, deployment)
+		}
+	}
+	return plan, nil
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"context"
+	"os"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+)
+
+func (s *PluginsTestSuite) TestPlanProvisioning() {
+	OrasFactory = NewTestOras
+
+	configuration := config.Configuration{
+		AdmServer:            "http://admserver",
+		HarborServerExternal: "https://harbor.example.com",
+		ManifestPath:         "/registry/edge-node/en/manifest",
+		ManifestTag:          "latest",
+	}
+	profile := &config.ProvisioningProfile{
+		Name:               "small",
+		HarborStorageLimit: 1024,
+		HarborSigning:      []string{config.HarborSigningNotation},
+		DeploymentPackages: []string{"base-extensions", "intel-gpu"},
+		DeploymentProfiles: []string{"baseline"},
+	}
+
+	plan, err := PlanProvisioning(context.Background(), configuration, Event{
+		EventType:        "create",
+		Organization:     "Org",
+		Name:             "Proj",
+		UUID:             "uuid",
+		Profile:          profile,
+		DeploymentLabels: map[string]string{"color": "green", "region": "eu"},
+	})
+	s.NoError(err)
+
+	s.Equal("catalog-apps-org-proj", plan.HarborProject)
+	s.Equal("small", plan.Profile)
+	s.Equal(int64(1024), plan.HarborStorageLimit)
+	s.Equal([]string{config.HarborSigningNotation}, plan.HarborSigning)
+	s.Equal("24.11.0-dev", plan.ManifestRelease)
+
+	s.Len(plan.Registries, 4)
+	s.Equal("oci://harbor.example.com/catalog-apps-org-proj", plan.Registries[3].RootURL)
+	s.Equal(PlanHarborPullUsername, plan.Registries[3].Username)
+	s.Equal(PlanHarborPullToken, plan.Registries[3].AuthToken)
+	s.Equal(PlanHarborUsername, plan.Registries[2].Username)
+	s.Equal(PlanHarborToken, plan.Registries[2].AuthToken)
+
+	s.Equal([]PlannedPackage{
+		{Name: "base-extensions", Version: "0.2.0"},
+		{Name: "intel-gpu", Version: "1.0.2"},
+	}, plan.DeploymentPackages)
+	s.Equal([]PlannedDeployment{
+		{Name: "base-extensions", Profile: "baseline", Version: "0.2.0", Labels: []map[string]string{{"color": "green", "region": "eu"}}},
+	}, plan.Deployments)
+	s.Empty(plan.RemovedDeployments)
+
+	// Without ADM there are no deployments
+	configuration.AdmServer = ""
+	plan, err = PlanProvisioning(context.Background(), configuration, Event{Organization: "org", Name: "proj"})
+	s.NoError(err)
+	s.Len(plan.DeploymentPackages, 7)
+	s.Empty(plan.Deployments)
+
+	// The Harbor registries can be reached on their own external hosts
+	configuration.HarborHelmRegistryExternal = "oci://charts.example.com"
+	configuration.HarborDockerRegistryExternal = "https://images.example.com"
+	plan, err = PlanProvisioning(context.Background(), configuration, Event{Organization: "org", Name: "proj"})
+	s.NoError(err)
+	s.Equal("oci://charts.example.com/catalog-apps-org-proj", plan.Registries[2].RootURL)
+	s.Equal("https://harbor.example.com/api/v2.0/projects/catalog-apps-org-proj", plan.Registries[2].InventoryURL)
+	s.Equal("oci://images.example.com/catalog-apps-org-proj", plan.Registries[3].RootURL)
+}
+
+func (s *PluginsTestSuite) TestValidateManifest() {
+	yamlBytes, err := os.ReadFile("testdata/extensions/24.11.0.yaml")
+	s.NoError(err)
+	manifest, err := ParseManifest(yamlBytes)
+	s.NoError(err)
+	s.Empty(ValidateManifest(manifest))
+
+	manifest, err = ParseManifest([]byte(`
+lpke:
+  deploymentPackages:
+    - dpkg: registry/edge-node/dp/base-extensions
+      version: 0.2.0
+    - dpkg: registry/edge-node/dp/base-extensions
+      version: 0.3.0
+    - dpkg: registry/edge-node/dp/usb
+      desiredState: gone
+  deploymentList:
+    - dpName: base-extensions
+      dpVersion: 0.1.0
+    - dpName: skupper
+      dpVersion: 0.1.4
+    - dpName: skupper
+      dpVersion: 0.1.4
+      desiredState: absent
+`))
+	s.NoError(err)
+	errs := ValidateManifest(manifest)
+	messages := []string{}
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	s.Equal([]string{
+		"metadata.schemaVersion is required",
+		"deployment package base-extensions: listed more than once",
+		"deployment package 2: dpkg and version are required",
+		"deployment base-extensions: version 0.1.0 does not match deployment package version 0.3.0",
+		"deployment skupper: deployment package is not in the manifest",
+		"deployment skupper with profile : listed more than once",
+	}, messages)
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package plugins
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Project quotas
+const (
+	// QuotaCatalogRegistries caps the catalog registries created for a project
+	QuotaCatalogRegistries = "catalog_registries"
+	// QuotaExtensionDeployments caps the ADM deployments created for the extensions of a project
+	QuotaExtensionDeployments = "extension_deployments"
+)
+
+// ErrQuotaExceeded is returned when provisioning a project would create more objects than its quota allows. It is
+// a permanent error: the event is not retried until the configuration or the manifest is fixed.
+var ErrQuotaExceeded = fmt.Errorf("%w: project quota exceeded", southbound.ErrPermanent)
+
+var quotaRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "tenant_controller_quota_rejections_total",
+	Help: "Project events rejected because they would exceed a project quota",
+}, []string{"quota"})
+
+func init() {
+	metrics.Registry.MustRegister(quotaRejections)
+}
+
+// checkQuota returns ErrQuotaExceeded if count objects are more than the limit of the quota, where a limit of 0 or
+// less means there is no limit. Nothing must have been created when the quota is checked, so that a rejected project
+// is not left half provisioned.
+func checkQuota(quota string, setting string, count int, limit int) error {
+	if limit <= 0 || count <= limit {
+		return nil
+	}
+	quotaRejections.WithLabelValues(quota).Inc()
+	return fmt.Errorf("%w: %d %s would be created, the maximum is %d (%s)", ErrQuotaExceeded, count,
+		strings.ReplaceAll(quota, "_", " "), limit, setting)
+}
+
+// countDeployments returns the number of deployments of the manifest that the event creates or keeps.
+func countDeployments(manifest *Manifest, event Event) int {
+	count := 0
+	for _, dl := range manifest.Lpke.DeploymentList {
+		if strings.EqualFold(dl.DesiredState, DesiredStateAbsent) ||
+			!event.Profile.AllowsDeploymentPackage(dl.DpName) ||
+			!event.Profile.AllowsDeploymentProfile(dl.DpProfileName) {
+			continue
+		}
+		count++
+	}
+	return count
+}
@@ -0,0 +1,289 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package plugins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+)
+
+// ExportBundleVersion is the format version of the bundles written by ExportTenant
+const ExportBundleVersion = "1"
+
+// ExportBundle is a portable description of the resources provisioned for a project, written by ExportTenant and
+// replayed against another orchestrator by ImportTenant. It holds no credentials: the robot accounts are recreated
+// with new secrets, and registries that need credentials get them from the provisioning of the target.
+type ExportBundle struct {
+	Version  string    `json:"version"`
+	Exported time.Time `json:"exported"`
+	// project in the orchestrator it was exported from
+	Organization string `json:"organization"`
+	Project      string `json:"project"`
+	UUID         string `json:"uuid"`
+	// provisioning profile of the project, empty if it is not known or none applies
+	Profile string `json:"profile,omitempty"`
+	// project labels added to the ADM deployments of the project
+	DeploymentLabels  map[string]string             `json:"deploymentLabels,omitempty"`
+	HarborProject     *ExportHarborProject          `json:"harborProject,omitempty"`
+	Registries        []ExportRegistry              `json:"registries,omitempty"`
+	StarterApps       []string                      `json:"starterApps,omitempty"`
+	ExtensionPackages []southbound.InventoryPackage `json:"extensionPackages,omitempty"`
+	Deployments       []ExportDeployment            `json:"deployments,omitempty"`
+}
+
+// ExportHarborProject is the Harbor project of an exported project
+type ExportHarborProject struct {
+	Name string `json:"name"`
+	// storage limit of the provisioning profile, 0 if it is not known or unlimited
+	StorageLimit int64 `json:"storageLimit,omitempty"`
+	// robot accounts to recreate
+	Robots []string `json:"robots,omitempty"`
+}
+
+// ExportRegistry is the definition of a catalog registry of an exported project, without its credentials
+type ExportRegistry struct {
+	Name         string `json:"name"`
+	DisplayName  string `json:"displayName,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Type         string `json:"type"`
+	RootURL      string `json:"rootURL"`
+	InventoryURL string `json:"inventoryURL,omitempty"`
+	Cacerts      string `json:"cacerts,omitempty"`
+	Anonymous    bool   `json:"anonymous,omitempty"`
+	// the registry has credentials, which are not exported. It can only be restored by provisioning the target
+	Credentials bool `json:"credentials,omitempty"`
+}
+
+// ExportDeployment is an ADM deployment of an extension of an exported project
+type ExportDeployment struct {
+	DisplayName string `json:"displayName"`
+	AppName     string `json:"appName"`
+	AppVersion  string `json:"appVersion"`
+	ProfileName string `json:"profileName"`
+}
+
+// ErrNoInventory is returned by ExportTenant for projects without a recorded inventory
+var ErrNoInventory = errors.New("project has no inventory")
+
+// ExportTenant builds the export bundle of a provisioned project from its recorded inventory and the definitions of
+// its catalog registries. Only the UUID of the event is required; the organization and project name are taken from
+// the inventory if they are not set, and the profile and deployment labels are only exported if they are set.
+func ExportTenant(ctx context.Context, configuration config.Configuration, event Event) (*ExportBundle, error) {
+	store, err := InventoryStoreFactory(configuration)
+	if err != nil {
+		return nil, err
+	}
+	inventory, err := store.Load(ctx, event.UUID)
+	if err != nil {
+		return nil, err
+	}
+	if inventory == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoInventory, event.UUID)
+	}
+	if inventory.HarborProject != nil && inventory.HarborProject.Archived != nil {
+		return nil, fmt.Errorf("project %s/%s was deleted, only its archived Harbor project is left", inventory.Organization, inventory.Project)
+	}
+
+	bundle := &ExportBundle{
+		Version:           ExportBundleVersion,
+		Exported:          time.Now().UTC(),
+		Organization:      inventory.Organization,
+		Project:           inventory.Project,
+		UUID:              event.UUID,
+		DeploymentLabels:  event.DeploymentLabels,
+		StarterApps:       inventory.StarterApps,
+		ExtensionPackages: inventory.ExtensionPackages,
+	}
+	if event.Profile != nil {
+		bundle.Profile = event.Profile.Name
+	}
+	if harbor := inventory.HarborProject; harbor != nil {
+		bundle.HarborProject = &ExportHarborProject{Name: harbor.Name}
+		if event.Profile != nil {
+			bundle.HarborProject.StorageLimit = event.Profile.HarborStorageLimit
+		}
+		for _, robot := range harbor.Robots {
+			bundle.HarborProject.Robots = append(bundle.HarborProject.Robots, robot.Name)
+		}
+	}
+	for _, deployment := range inventory.Deployments {
+		bundle.Deployments = append(bundle.Deployments, ExportDeployment{
+			DisplayName: deployment.DisplayName,
+			AppName:     deployment.AppName,
+			AppVersion:  deployment.AppVersion,
+			ProfileName: deployment.ProfileName,
+		})
+	}
+
+	if len(inventory.CatalogRegistries) == 0 {
+		return bundle, nil
+	}
+	catalog, err := CatalogFactory(configuration)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range inventory.CatalogRegistries {
+		attrs, err := catalog.GetRegistry(ctx, event.UUID, name)
+		if errors.Is(err, southbound.ErrNotFound) {
+			log.Warnf("Registry %s of project %s is in its inventory but not in the catalog, not exporting it", name, inventory.Project)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to export registry %s: %w", name, err)
+		}
+		bundle.Registries = append(bundle.Registries, ExportRegistry{
+			Name:         attrs.Name,
+			DisplayName:  attrs.DisplayName,
+			Description:  attrs.Description,
+			Type:         attrs.Type,
+			RootURL:      attrs.RootURL,
+			InventoryURL: attrs.InventoryURL,
+			Cacerts:      attrs.Cacerts,
+			Anonymous:    attrs.Anonymous,
+			Credentials:  attrs.Username != "" || attrs.AuthToken != "",
+		})
+	}
+	return bundle, nil
+}
+
+// ImportResult is the outcome of replaying an export bundle.
+type ImportResult struct {
+	// result of provisioning the project in the target
+	Dispatch *DispatchResult
+	// anonymous registries of the bundle that provisioning did not create, created from their exported definition
+	RestoredRegistries []string
+	// resources of the bundle that the target does not have after the import, named as by DispatchResult.Resources
+	// except for deployments, which are named by display name
+	Missing []string
+}
+
+// ImportTenant replays an export bundle against the orchestrator of the configuration. The project is provisioned
+// with a create event, which recreates the Harbor project and its robot accounts with new secrets, the catalog
+// registries and the extensions of the target. Anonymous registries of the bundle that the target does not create
+// are then created from their exported definition, and the resources of the bundle that the target still lacks are
+// reported as missing, e.g. deployments of an extensions manifest release the target does not use. The event
+// identifies the project in the target, which must have the organization and name of the bundle.
+func ImportTenant(ctx context.Context, configuration config.Configuration, bundle *ExportBundle, event Event) (*ImportResult, error) {
+	if bundle.Version != ExportBundleVersion {
+		return nil, fmt.Errorf("unsupported export bundle version %q, expected %q", bundle.Version, ExportBundleVersion)
+	}
+	if event.Organization != bundle.Organization || event.Name != bundle.Project {
+		return nil, fmt.Errorf("the bundle is of project %s/%s, not %s/%s", bundle.Organization, bundle.Project, event.Organization, event.Name)
+	}
+	event.EventType = "create"
+	dispatchResult, err := Dispatch(ctx, event, nil)
+	result := &ImportResult{Dispatch: dispatchResult}
+	if err != nil {
+		return result, err
+	}
+	inventory := dispatchResult.Inventory
+	if inventory == nil {
+		inventory = &southbound.Inventory{}
+	}
+
+	var restore []ExportRegistry
+	for _, registry := range bundle.Registries {
+		switch {
+		case slices.Contains(inventory.CatalogRegistries, registry.Name):
+		case registry.Anonymous && !registry.Credentials:
+			restore = append(restore, registry)
+		default:
+			result.Missing = append(result.Missing, "catalog-registry/"+registry.Name)
+		}
+	}
+	if len(restore) > 0 {
+		catalog, err := CatalogFactory(configuration)
+		if err != nil {
+			return result, err
+		}
+		for _, registry := range restore {
+			err := catalog.CreateOrUpdateRegistry(ctx, southbound.RegistryAttributes{
+				Name:         registry.Name,
+				DisplayName:  registry.DisplayName,
+				Description:  registry.Description,
+				Type:         registry.Type,
+				RootURL:      registry.RootURL,
+				InventoryURL: registry.InventoryURL,
+				Cacerts:      registry.Cacerts,
+				ProjectUUID:  event.UUID,
+				Anonymous:    true,
+			})
+			if err != nil {
+				return result, fmt.Errorf("unable to restore registry %s: %w", registry.Name, err)
+			}
+			result.RestoredRegistries = append(result.RestoredRegistries, registry.Name)
+		}
+		if configuration.PodNamespace != "" {
+			if err := addInventoryRegistries(ctx, configuration, event.UUID, result.RestoredRegistries); err != nil {
+				log.Warnf("Unable to update the inventory of project %s: %v", event.Name, err)
+			}
+		}
+	}
+
+	result.Missing = append(result.Missing, missingResources(bundle, inventory)...)
+	return result, nil
+}
+
+// missingResources lists the Harbor, starter application and extension resources of the bundle that the inventory
+// does not have.
+func missingResources(bundle *ExportBundle, inventory *southbound.Inventory) []string {
+	var missing []string
+	if harbor := bundle.HarborProject; harbor != nil {
+		if inventory.HarborProject == nil {
+			missing = append(missing, "harbor-project/"+harbor.Name)
+		} else {
+			for _, robot := range harbor.Robots {
+				if !slices.ContainsFunc(inventory.HarborProject.Robots, func(r southbound.InventoryRobot) bool { return r.Name == robot }) {
+					missing = append(missing, "harbor-robot/"+robot)
+				}
+			}
+		}
+	}
+	for _, app := range bundle.StarterApps {
+		if !slices.Contains(inventory.StarterApps, app) {
+			missing = append(missing, "starter-app/"+app)
+		}
+	}
+	for _, pkg := range bundle.ExtensionPackages {
+		if !slices.Contains(inventory.ExtensionPackages, pkg) {
+			missing = append(missing, "catalog-package/"+pkg.Name+":"+pkg.Version)
+		}
+	}
+	for _, deployment := range bundle.Deployments {
+		if !slices.ContainsFunc(inventory.Deployments, func(d southbound.InventoryDeployment) bool {
+			return d.DisplayName == deployment.DisplayName && d.AppName == deployment.AppName &&
+				d.AppVersion == deployment.AppVersion && d.ProfileName == deployment.ProfileName
+		}) {
+			missing = append(missing, "adm-deployment/"+deployment.DisplayName)
+		}
+	}
+	return missing
+}
+
+// addInventoryRegistries adds registries to the recorded inventory of the project. Projects without an inventory
+// are left without one.
+func addInventoryRegistries(ctx context.Context, configuration config.Configuration, uuid string, registries []string) error {
+	store, err := InventoryStoreFactory(configuration)
+	if err != nil {
+		return err
+	}
+	inventory, err := store.Load(ctx, uuid)
+	if err != nil || inventory == nil {
+		return err
+	}
+	for _, registry := range registries {
+		if !slices.Contains(inventory.CatalogRegistries, registry) {
+			inventory.CatalogRegistries = append(inventory.CatalogRegistries, registry)
+		}
+	}
+	inventory.Updated = time.Now().UTC()
+	return store.Save(ctx, inventory)
+}
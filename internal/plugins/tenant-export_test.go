@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	"github.com/open-edge-platform/app-orch-tenant-controller/pkg/api"
+)
+
+// provisioningPlugin records a fixed inventory for create events, standing in for the provisioning of the target
+type provisioningPlugin struct {
+	inventory southbound.Inventory
+}
+
+func (p *provisioningPlugin) Name() string {
+	return "Provisioning"
+}
+
+func (p *provisioningPlugin) Initialize(_ context.Context, _ *PluginData) error {
+	return nil
+}
+
+func (p *provisioningPlugin) CreateEvent(_ context.Context, _ Event, pluginData *PluginData) error {
+	recordInventory(pluginData, func(inventory *southbound.Inventory) {
+		*inventory = p.inventory
+	})
+	return nil
+}
+
+func (p *provisioningPlugin) DeleteEvent(_ context.Context, _ Event, _ *PluginData) error {
+	return nil
+}
+
+func exportInventory() *southbound.Inventory {
+	return &southbound.Inventory{
+		Organization: "acme",
+		Project:      "web",
+		UUID:         "source-uuid",
+		HarborProject: &southbound.InventoryHarbor{ID: 7, Name: "catalog-apps-acme-web", Robots: []southbound.InventoryRobot{
+			{Name: "robot$catalog-apps-acme-web+catalog-apps-read-write", ID: 1},
+			{Name: "robot$catalog-apps-acme-web+catalog-apps-read-only", ID: 2},
+		}},
+		CatalogRegistries: []string{"harbor-helm", "partner-charts", "removed"},
+		StarterApps:       []string{"nginx"},
+		ExtensionPackages: []southbound.InventoryPackage{{Name: "base-extensions", Version: "0.2.0"}, {Name: "skupper", Version: "0.1.4"}},
+		Deployments: []southbound.InventoryDeployment{
+			{ID: "d1", DisplayName: "base-extensions-baseline", AppName: "base-extensions", AppVersion: "0.2.0", ProfileName: "baseline"},
+			{ID: "d2", DisplayName: "skupper", AppName: "skupper", AppVersion: "0.1.4", ProfileName: "default"},
+		},
+	}
+}
+
+func (s *PluginsTestSuite) TestExportTenant() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	store := &testInventoryStore{inventories: map[string]*southbound.Inventory{"source-uuid": exportInventory()}}
+	InventoryStoreFactory = func(_ config.Configuration) (InventoryStore, error) { return store, nil }
+	defer func() { InventoryStoreFactory = NewInventoryStore }()
+	mockCatalog = testCatalog{}
+	CatalogFactory = newTestCatalog
+	catalog, _ := newTestCatalog(config.Configuration{})
+	s.NoError(catalog.CreateOrUpdateRegistry(ctx, southbound.RegistryAttributes{Name: "harbor-helm", Type: "HELM",
+		RootURL: "oci://harbor.source", Username: "robot", AuthToken: "secret", ProjectUUID: "source-uuid"}))
+	s.NoError(catalog.CreateOrUpdateRegistry(ctx, southbound.RegistryAttributes{Name: "partner-charts", DisplayName: "Partner charts",
+		Type: "HELM", RootURL: "oci://charts.partner.example", Anonymous: true, ProjectUUID: "source-uuid"}))
+
+	bundle, err := ExportTenant(ctx, config.Configuration{}, Event{
+		UUID:             "source-uuid",
+		Profile:          &config.ProvisioningProfile{Name: "gold", HarborStorageLimit: 1024},
+		DeploymentLabels: map[string]string{"region": "eu"},
+	})
+	s.NoError(err)
+	s.Equal(ExportBundleVersion, bundle.Version)
+	s.Equal("acme", bundle.Organization)
+	s.Equal("web", bundle.Project)
+	s.Equal("gold", bundle.Profile)
+	s.Equal(map[string]string{"region": "eu"}, bundle.DeploymentLabels)
+	s.Equal(&ExportHarborProject{Name: "catalog-apps-acme-web", StorageLimit: 1024, Robots: []string{
+		"robot$catalog-apps-acme-web+catalog-apps-read-write", "robot$catalog-apps-acme-web+catalog-apps-read-only",
+	}}, bundle.HarborProject)
+	// The credentials are not exported, and the registry missing from the catalog is left out
+	s.Equal([]ExportRegistry{
+		{Name: "harbor-helm", Type: "HELM", RootURL: "oci://harbor.source", Credentials: true},
+		{Name: "partner-charts", DisplayName: "Partner charts", Type: "HELM", RootURL: "oci://charts.partner.example", Anonymous: true},
+	}, bundle.Registries)
+	s.Equal([]string{"nginx"}, bundle.StarterApps)
+	s.Len(bundle.ExtensionPackages, 2)
+	s.Equal([]ExportDeployment{
+		{DisplayName: "base-extensions-baseline", AppName: "base-extensions", AppVersion: "0.2.0", ProfileName: "baseline"},
+		{DisplayName: "skupper", AppName: "skupper", AppVersion: "0.1.4", ProfileName: "default"},
+	}, bundle.Deployments)
+
+	// The bundle matches the schema of the export API, and decodes with its generated type without losing fields
+	encoded, err := json.Marshal(bundle)
+	s.NoError(err)
+	spec, err := openapi3.NewLoader().LoadFromData(api.Spec)
+	s.Require().NoError(err)
+	var value interface{}
+	s.NoError(json.Unmarshal(encoded, &value))
+	s.NoError(spec.Components.Schemas["ExportBundle"].Value.VisitJSON(value))
+	decoded := api.ExportBundle{}
+	s.NoError(json.Unmarshal(encoded, &decoded))
+	reencoded, err := json.Marshal(decoded)
+	s.NoError(err)
+	s.JSONEq(string(encoded), string(reencoded))
+
+	_, err = ExportTenant(ctx, config.Configuration{}, Event{UUID: "unknown"})
+	s.ErrorIs(err, ErrNoInventory)
+
+	archived := time.Now()
+	store.inventories["source-uuid"].HarborProject.Archived = &archived
+	_, err = ExportTenant(ctx, config.Configuration{}, Event{UUID: "source-uuid"})
+	s.ErrorContains(err, "was deleted")
+}
+
+func (s *PluginsTestSuite) TestImportTenant() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	mockCatalog = testCatalog{}
+	CatalogFactory = newTestCatalog
+	store := &testInventoryStore{inventories: map[string]*southbound.Inventory{"target-uuid": {UUID: "target-uuid", CatalogRegistries: []string{"harbor-helm"}}}}
+	InventoryStoreFactory = func(_ config.Configuration) (InventoryStore, error) { return store, nil }
+	defer func() { InventoryStoreFactory = NewInventoryStore }()
+
+	// The target creates the Harbor project with one of the robots, the Harbor registry and one of the deployments
+	provisioned := exportInventory()
+	provisioned.HarborProject.Robots = provisioned.HarborProject.Robots[:1]
+	provisioned.CatalogRegistries = []string{"harbor-helm"}
+	provisioned.ExtensionPackages = provisioned.ExtensionPackages[:1]
+	provisioned.Deployments = provisioned.Deployments[:1]
+	RemoveAllPlugins()
+	defer RemoveAllPlugins()
+	Register(&provisioningPlugin{inventory: *provisioned})
+
+	bundle := &ExportBundle{
+		Version:       ExportBundleVersion,
+		Organization:  "acme",
+		Project:       "web",
+		UUID:          "source-uuid",
+		HarborProject: &ExportHarborProject{Name: "catalog-apps-acme-web", Robots: []string{"robot$catalog-apps-acme-web+catalog-apps-read-write", "robot$catalog-apps-acme-web+catalog-apps-read-only"}},
+		Registries: []ExportRegistry{
+			{Name: "harbor-helm", Type: "HELM", RootURL: "oci://harbor.source", Credentials: true},
+			{Name: "partner-charts", Type: "HELM", RootURL: "oci://charts.partner.example", Anonymous: true},
+			{Name: "private-images", Type: "IMAGE", RootURL: "oci://images.partner.example", Credentials: true},
+		},
+		StarterApps:       []string{"nginx"},
+		ExtensionPackages: exportInventory().ExtensionPackages,
+		Deployments: []ExportDeployment{
+			{DisplayName: "base-extensions-baseline", AppName: "base-extensions", AppVersion: "0.2.0", ProfileName: "baseline"},
+			{DisplayName: "skupper", AppName: "skupper", AppVersion: "0.1.4", ProfileName: "default"},
+		},
+	}
+	event := Event{Organization: "acme", Name: "web", UUID: "target-uuid"}
+	result, err := ImportTenant(ctx, config.Configuration{PodNamespace: "orch-app"}, bundle, event)
+	s.NoError(err)
+	s.Equal(PluginSucceeded, result.Dispatch.Plugin("Provisioning").Status)
+	s.Equal([]string{"partner-charts"}, result.RestoredRegistries)
+	restored := mockCatalog.registries["partner-charts"]
+	s.Equal("target-uuid", restored.ProjectUUID)
+	s.Equal("oci://charts.partner.example", restored.RootURL)
+	s.True(restored.Anonymous)
+	s.Equal([]string{"harbor-helm", "partner-charts"}, store.inventories["target-uuid"].CatalogRegistries)
+	s.Equal([]string{
+		"catalog-registry/private-images",
+		"harbor-robot/robot$catalog-apps-acme-web+catalog-apps-read-only",
+		"catalog-package/skupper:0.1.4",
+		"adm-deployment/skupper",
+	}, result.Missing)
+
+	_, err = ImportTenant(ctx, config.Configuration{}, bundle, Event{Organization: "acme", Name: "api", UUID: "target-uuid"})
+	s.ErrorContains(err, "not acme/api")
+	bundle.Version = "0"
+	_, err = ImportTenant(ctx, config.Configuration{}, bundle, event)
+	s.ErrorContains(err, "unsupported export bundle version")
+}
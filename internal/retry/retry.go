@@ -0,0 +1,215 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package retry provides jittered exponential backoff and a retry budget. The budget is a deadline carried in the
+// context, so that every retry loop working on behalf of the same project event shares it: once waiting for the
+// next attempt would go past the deadline, retrying stops, however many attempts each loop has left.
+//
+//nolint:revive // Internal package
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/open-edge-platform/orch-library/go/dazl"
+)
+
+var log = dazl.GetPackageLogger()
+
+// ErrBudgetExhausted is the reason retrying stopped when the retry budget of the context ran out.
+var ErrBudgetExhausted = errors.New("retry budget exhausted")
+
+// maxThrottledWaits bounds the waits for a throttling server in a single Do, as they do not count as attempts
+const maxThrottledWaits = 10
+
+// Throttled is implemented by errors of a server asking the client to slow down. RetryAfter returns how long the
+// server asked the client to wait, 0 if it did not say.
+type Throttled interface {
+	error
+	RetryAfter() time.Duration
+}
+
+// Backoff describes how long to wait between attempts
+type Backoff struct {
+	// delay after the first failed attempt, doubled after every further attempt
+	Initial time.Duration
+	// upper bound of the delay before jitter, 0 for no bound
+	Max time.Duration
+	// maximum number of attempts, 0 to retry until the budget runs out or the context is done
+	Attempts int
+	// fraction of the delay that is randomized, e.g. 0.2 waits between 80% and 120% of the delay
+	Jitter float64
+}
+
+// Delay returns how long to wait after the given failed attempt, counting from 1.
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := b.Initial
+	for i := 1; i < attempt && (b.Max == 0 || delay < b.Max); i++ {
+		delay *= 2
+	}
+	if b.Max > 0 && delay > b.Max {
+		delay = b.Max
+	}
+	if b.Jitter > 0 {
+		delay = time.Duration(float64(delay) * (1 + b.Jitter*(2*rand.Float64()-1)))
+	}
+	return delay
+}
+
+// ThrottleDelay returns how long to wait before the next attempt if err is a Throttled error: the delay the server
+// asked for, or the initial delay if it did not say, bounded by the maximum delay. Unlike the delay after a failed
+// attempt, it does not grow with the attempts, so that throttled clients come back when the server is ready for
+// them rather than ever later.
+func (b Backoff) ThrottleDelay(err error) (time.Duration, bool) {
+	var throttled Throttled
+	if !errors.As(err, &throttled) {
+		return 0, false
+	}
+	delay := throttled.RetryAfter()
+	if delay <= 0 {
+		delay = b.Initial
+	}
+	if b.Max > 0 && delay > b.Max {
+		delay = b.Max
+	}
+	// the server asked for at least the delay, so the jitter only ever adds to it
+	if b.Jitter > 0 {
+		delay = time.Duration(float64(delay) * (1 + b.Jitter*rand.Float64()))
+	}
+	return delay, true
+}
+
+// Clock tells the time and waits out the delays of the retry loops. The retry loops use the clock of their context,
+// set with WithClock, so that tests can run them without waiting in real time.
+type Clock interface {
+	Now() time.Time
+	// After returns a channel that receives the time once the delay is over
+	After(delay time.Duration) <-chan time.Time
+}
+
+// realClock is the clock of contexts that do not carry one
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(delay time.Duration) <-chan time.Time {
+	return time.After(delay)
+}
+
+type clockKey struct{}
+
+// WithClock returns a context whose retry loops use the clock.
+func WithClock(ctx context.Context, clock Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, clock)
+}
+
+// ClockFrom returns the clock carried by the context, or the real clock if it carries none.
+func ClockFrom(ctx context.Context) Clock {
+	if clock, ok := ctx.Value(clockKey{}).(Clock); ok {
+		return clock
+	}
+	return realClock{}
+}
+
+type budgetKey struct{}
+
+// WithBudget returns a context carrying a retry budget that ends at the deadline. If the parent context already
+// carries a budget that ends earlier, that budget is kept.
+func WithBudget(ctx context.Context, deadline time.Time) context.Context {
+	if current, ok := Budget(ctx); ok && current.Before(deadline) {
+		return ctx
+	}
+	return context.WithValue(ctx, budgetKey{}, deadline)
+}
+
+// Budget returns the end of the retry budget carried by the context.
+func Budget(ctx context.Context) (time.Time, bool) {
+	deadline, ok := ctx.Value(budgetKey{}).(time.Time)
+	return deadline, ok
+}
+
+// Sleep waits for the delay on the clock of the context. It returns ErrBudgetExhausted right away if the delay would
+// end after the retry budget of the context, or the context error if the context is done before the delay is over.
+func Sleep(ctx context.Context, delay time.Duration) error {
+	clock := ClockFrom(ctx)
+	if deadline, ok := Budget(ctx); ok && clock.Now().Add(delay).After(deadline) {
+		return ErrBudgetExhausted
+	}
+	select {
+	case <-clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Error is returned by Do when it gives up retrying
+type Error struct {
+	// attempts made
+	Attempts int
+	// ErrBudgetExhausted or the context error, nil if all attempts failed
+	Reason error
+	// error of the last attempt
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Reason != nil {
+		return fmt.Sprintf("%v: %v", e.Reason, e.Err)
+	}
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Reason != nil {
+		return []error{e.Reason, e.Err}
+	}
+	return []error{e.Err}
+}
+
+// Do calls fn until it succeeds or returns an error that retryable rejects, which is returned as it is. If the
+// attempts run out, the retry budget runs out or the context is done first, an *Error is returned. The operation
+// name is used to log failed attempts. Attempts rejected with a Throttled error are retried after the delay the
+// server asked for, and up to maxThrottledWaits of them do not count as attempts.
+func Do(ctx context.Context, operation string, backoff Backoff, retryable func(error) bool, fn func(ctx context.Context) error) error {
+	throttledWaits := 0
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if !retryable(err) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return &Error{Attempts: attempt, Reason: ctx.Err(), Err: err}
+		}
+		if delay, ok := backoff.ThrottleDelay(err); ok && throttledWaits < maxThrottledWaits {
+			throttledWaits++
+			attempt--
+			log.Infof("%s throttled: %v. Retrying in %v...", operation, err, delay.Round(time.Millisecond))
+			if sleepErr := Sleep(ctx, delay); sleepErr != nil {
+				return &Error{Attempts: attempt + throttledWaits, Reason: sleepErr, Err: err}
+			}
+			continue
+		}
+		if backoff.Attempts > 0 && attempt >= backoff.Attempts {
+			return &Error{Attempts: attempt + throttledWaits, Err: err}
+		}
+		delay := backoff.Delay(attempt)
+		if backoff.Attempts > 0 {
+			log.Infof("%s failed (attempt %d/%d): %v. Retrying in %v...", operation, attempt, backoff.Attempts, err, delay.Round(time.Millisecond))
+		} else {
+			log.Infof("%s failed (attempt %d): %v. Retrying in %v...", operation, attempt, err, delay.Round(time.Millisecond))
+		}
+		if sleepErr := Sleep(ctx, delay); sleepErr != nil {
+			return &Error{Attempts: attempt + throttledWaits, Reason: sleepErr, Err: err}
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Suite of retry tests
+type RetryTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *RetryTestSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 1*time.Minute)
+}
+
+func (s *RetryTestSuite) TearDownTest() {
+	s.cancel()
+}
+
+func TestRetry(t *testing.T) {
+	suite.Run(t, &RetryTestSuite{})
+}
+
+var errPermanent = errors.New("permanent")
+
+func notPermanent(err error) bool {
+	return !errors.Is(err, errPermanent)
+}
+
+func (s *RetryTestSuite) TestDelay() {
+	backoff := Backoff{Initial: time.Second, Max: 5 * time.Second}
+	s.Equal(time.Second, backoff.Delay(1))
+	s.Equal(2*time.Second, backoff.Delay(2))
+	s.Equal(4*time.Second, backoff.Delay(3))
+	s.Equal(5*time.Second, backoff.Delay(4))
+	s.Equal(5*time.Second, backoff.Delay(100))
+
+	backoff.Jitter = 0.2
+	for attempt := 1; attempt < 10; attempt++ {
+		delay := backoff.Delay(attempt)
+		s.GreaterOrEqual(delay, 800*time.Millisecond)
+		s.LessOrEqual(delay, 6*time.Second)
+	}
+}
+
+func (s *RetryTestSuite) TestBudget() {
+	_, ok := Budget(s.ctx)
+	s.False(ok)
+
+	deadline := time.Now().Add(time.Second)
+	ctx := WithBudget(s.ctx, deadline)
+	budget, ok := Budget(ctx)
+	s.True(ok)
+	s.Equal(deadline, budget)
+
+	// A nested budget cannot extend the budget of its parent
+	budget, _ = Budget(WithBudget(ctx, deadline.Add(time.Hour)))
+	s.Equal(deadline, budget)
+	budget, _ = Budget(WithBudget(ctx, deadline.Add(-time.Millisecond)))
+	s.Equal(deadline.Add(-time.Millisecond), budget)
+
+	// Sleeping past the budget fails right away
+	start := time.Now()
+	s.ErrorIs(Sleep(ctx, time.Hour), ErrBudgetExhausted)
+	s.Less(time.Since(start), time.Second)
+	s.NoError(Sleep(ctx, time.Millisecond))
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	s.ErrorIs(Sleep(cancelled, 100*time.Millisecond), context.Canceled)
+}
+
+func (s *RetryTestSuite) TestDo() {
+	backoff := Backoff{Initial: time.Millisecond, Attempts: 3}
+
+	calls := 0
+	err := Do(s.ctx, "succeeding", backoff, notPermanent, func(_ context.Context) error {
+		calls++
+		if calls < 2 {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+	s.NoError(err)
+	s.Equal(2, calls)
+
+	// Errors that are not retryable are returned as they are
+	calls = 0
+	err = Do(s.ctx, "permanent", backoff, notPermanent, func(_ context.Context) error {
+		calls++
+		return errPermanent
+	})
+	s.Equal(errPermanent, err)
+	s.Equal(1, calls)
+
+	// Running out of attempts
+	calls = 0
+	unavailable := errors.New("unavailable")
+	err = Do(s.ctx, "failing", backoff, notPermanent, func(_ context.Context) error {
+		calls++
+		return unavailable
+	})
+	s.Equal(3, calls)
+	var retryErr *Error
+	s.ErrorAs(err, &retryErr)
+	s.Equal(3, retryErr.Attempts)
+	s.ErrorIs(err, unavailable)
+	s.Equal("unavailable", err.Error())
+
+	// Running out of budget stops retrying before the attempts run out
+	ctx := WithBudget(s.ctx, time.Now().Add(50*time.Millisecond))
+	calls = 0
+	err = Do(ctx, "budget", Backoff{Initial: 20 * time.Millisecond, Attempts: 100}, notPermanent, func(_ context.Context) error {
+		calls++
+		return unavailable
+	})
+	s.ErrorIs(err, ErrBudgetExhausted)
+	s.ErrorIs(err, unavailable)
+	s.Equal("retry budget exhausted: unavailable", err.Error())
+	s.Less(calls, 100)
+}
+
+type throttledError time.Duration
+
+func (e throttledError) Error() string {
+	return "slow down"
+}
+
+func (e throttledError) RetryAfter() time.Duration {
+	return time.Duration(e)
+}
+
+func (s *RetryTestSuite) TestThrottleDelay() {
+	backoff := Backoff{Initial: time.Second, Max: 5 * time.Second}
+	_, ok := backoff.ThrottleDelay(errors.New("unavailable"))
+	s.False(ok)
+
+	delay, ok := backoff.ThrottleDelay(fmt.Errorf("create project: %w", throttledError(3*time.Second)))
+	s.True(ok)
+	s.Equal(3*time.Second, delay)
+	delay, _ = backoff.ThrottleDelay(throttledError(0))
+	s.Equal(time.Second, delay)
+	delay, _ = backoff.ThrottleDelay(throttledError(time.Hour))
+	s.Equal(5*time.Second, delay)
+
+	// The jitter never shortens the delay the server asked for
+	backoff.Jitter = 0.2
+	for i := 0; i < 10; i++ {
+		delay, _ = backoff.ThrottleDelay(throttledError(3 * time.Second))
+		s.GreaterOrEqual(delay, 3*time.Second)
+		s.LessOrEqual(delay, 3600*time.Millisecond)
+	}
+}
+
+func (s *RetryTestSuite) TestDoThrottled() {
+	backoff := Backoff{Initial: time.Millisecond, Attempts: 2}
+
+	// Throttled attempts do not use up the attempts
+	calls := 0
+	err := Do(s.ctx, "throttled", backoff, notPermanent, func(_ context.Context) error {
+		calls++
+		switch calls {
+		case 1, 2, 3:
+			return throttledError(time.Millisecond)
+		case 4:
+			return errors.New("unavailable")
+		default:
+			return nil
+		}
+	})
+	s.NoError(err)
+	s.Equal(5, calls)
+
+	// A server that keeps throttling is only waited for so long
+	calls = 0
+	err = Do(s.ctx, "always throttled", backoff, notPermanent, func(_ context.Context) error {
+		calls++
+		return throttledError(time.Millisecond)
+	})
+	var retryErr *Error
+	s.ErrorAs(err, &retryErr)
+	s.Equal(maxThrottledWaits+backoff.Attempts, calls)
+	s.Equal(calls, retryErr.Attempts)
+}
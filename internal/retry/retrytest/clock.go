@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package retrytest provides a fake clock for testing retry loops without waiting in real time.
+//
+//nolint:revive // Test utility package
+package retrytest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/retry"
+)
+
+// Clock is a fake retry.Clock. Waiting on it takes no time: the clock moves forward by the delay right away, and the
+// delay is recorded.
+type Clock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits []time.Duration
+}
+
+// NewClock returns a fake clock set to the given time.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Context returns a context whose retry loops use a new fake clock, set to the current time, and the clock.
+func Context(ctx context.Context) (context.Context, *Clock) {
+	clock := NewClock(time.Now())
+	return retry.WithClock(ctx, clock), clock
+}
+
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *Clock) After(delay time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(delay)
+	c.waits = append(c.waits, delay)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// Advance moves the clock forward without recording a wait.
+func (c *Clock) Advance(delay time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(delay)
+}
+
+// Waits returns the delays waited for, in order.
+func (c *Clock) Waits() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration{}, c.waits...)
+}
+
+// Waited returns the total of the delays waited for.
+func (c *Clock) Waited() time.Duration {
+	var total time.Duration
+	for _, wait := range c.Waits() {
+		total += wait
+	}
+	return total
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package retrytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/retry"
+	"github.com/stretchr/testify/suite"
+)
+
+// Suite of fake clock tests
+type ClockTestSuite struct {
+	suite.Suite
+}
+
+func TestClock(t *testing.T) {
+	suite.Run(t, &ClockTestSuite{})
+}
+
+func retryable(_ error) bool {
+	return true
+}
+
+func (s *ClockTestSuite) TestDo() {
+	ctx, clock := Context(context.Background())
+	start := clock.Now()
+
+	// Hours of backoff pass without waiting
+	unavailable := errors.New("unavailable")
+	backoff := retry.Backoff{Initial: time.Minute, Max: time.Hour, Attempts: 10}
+	calls := 0
+	err := retry.Do(ctx, "failing", backoff, retryable, func(_ context.Context) error {
+		calls++
+		return unavailable
+	})
+	s.ErrorIs(err, unavailable)
+	s.Equal(10, calls)
+	s.Equal([]time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 16 * time.Minute,
+		32 * time.Minute, time.Hour, time.Hour, time.Hour}, clock.Waits())
+	s.Equal(clock.Waited(), clock.Now().Sub(start))
+
+	// The retry budget is measured on the clock
+	clock = NewClock(start)
+	ctx = retry.WithBudget(retry.WithClock(context.Background(), clock), start.Add(10*time.Minute))
+	calls = 0
+	err = retry.Do(ctx, "budget", backoff, retryable, func(_ context.Context) error {
+		calls++
+		return unavailable
+	})
+	s.ErrorIs(err, retry.ErrBudgetExhausted)
+	s.Equal(4, calls)
+	s.Equal(7*time.Minute, clock.Waited())
+
+	// Moving the clock forward uses up the budget
+	clock.Advance(3 * time.Minute)
+	s.ErrorIs(retry.Sleep(ctx, time.Second), retry.ErrBudgetExhausted)
+	s.Equal(3, len(clock.Waits()))
+}
+
+func (s *ClockTestSuite) TestRealClock() {
+	_, ok := retry.ClockFrom(context.Background()).(*Clock)
+	s.False(ok)
+	start := time.Now()
+	s.NoError(retry.Sleep(context.Background(), time.Millisecond))
+	s.GreaterOrEqual(time.Since(start), time.Millisecond)
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package slo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	coreV1Types "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	notifyTimeout = 10 * time.Second
+
+	// EventReason is the reason of the Kubernetes events recorded for SLO violations
+	EventReason = "ProvisioningSLOExceeded"
+)
+
+// WebhookPayload is the JSON body posted to the SLO webhook
+type WebhookPayload struct {
+	Message        string             `json:"message"`
+	EventType      string             `json:"eventType"`
+	Organization   string             `json:"organization"`
+	Project        string             `json:"project"`
+	UUID           string             `json:"uuid"`
+	Result         string             `json:"result"`
+	ElapsedSeconds float64            `json:"elapsedSeconds"`
+	SLOSeconds     float64            `json:"sloSeconds"`
+	Plugin         string             `json:"plugin"`
+	PluginSeconds  map[string]float64 `json:"pluginSeconds"`
+}
+
+// WebhookNotifier posts SLO violations as JSON to a URL.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: notifyTimeout},
+	}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, violation Violation) error {
+	payload := WebhookPayload{
+		Message:        violation.Message(),
+		EventType:      violation.EventType,
+		Organization:   violation.Organization,
+		Project:        violation.Project,
+		UUID:           violation.UUID,
+		Result:         violation.Result,
+		ElapsedSeconds: violation.Elapsed.Seconds(),
+		SLOSeconds:     violation.SLO.Seconds(),
+		Plugin:         violation.Plugin,
+		PluginSeconds:  map[string]float64{},
+	}
+	for plugin, elapsed := range violation.PluginTimes {
+		payload.PluginSeconds[plugin] = elapsed.Seconds()
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("content-type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		responseBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, string(responseBody))
+	}
+	return nil
+}
+
+// EventNotifier records SLO violations as Kubernetes warning events on the controller pod.
+type EventNotifier struct {
+	events    coreV1Types.EventInterface
+	namespace string
+	podName   string
+}
+
+// NewEventNotifier creates a notifier using the in-cluster Kubernetes configuration.
+func NewEventNotifier(namespace string, podName string) (*EventNotifier, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return newEventNotifier(clientset.CoreV1().Events(namespace), namespace, podName), nil
+}
+
+func newEventNotifier(events coreV1Types.EventInterface, namespace string, podName string) *EventNotifier {
+	return &EventNotifier{
+		events:    events,
+		namespace: namespace,
+		podName:   podName,
+	}
+}
+
+func (n *EventNotifier) Notify(ctx context.Context, violation Violation) error {
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+	now := metaV1.Now()
+	event := &coreV1.Event{
+		ObjectMeta: metaV1.ObjectMeta{
+			GenerateName: n.podName + ".",
+			Namespace:    n.namespace,
+			Annotations: map[string]string{
+				"organization": violation.Organization,
+				"project":      violation.Project,
+				"plugin":       violation.Plugin,
+			},
+		},
+		InvolvedObject: coreV1.ObjectReference{
+			Kind:       "Pod",
+			APIVersion: "v1",
+			Namespace:  n.namespace,
+			Name:       n.podName,
+		},
+		Reason:         EventReason,
+		Message:        violation.Message(),
+		Type:           coreV1.EventTypeWarning,
+		Source:         coreV1.EventSource{Component: "app-orch-tenant-controller"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	_, err := n.events.Create(ctx, event, metaV1.CreateOptions{})
+	return err
+}
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package slo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/open-edge-platform/orch-library/go/dazl"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var log = dazl.GetPackageLogger()
+
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+var (
+	provisioningDuration = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Name:       "tenant_controller_provisioning_duration_seconds",
+		Help:       "Time from receiving a project event until the project watcher is idle again",
+		Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+	}, []string{"event_type", "result"})
+
+	pluginDuration = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Name:       "tenant_controller_plugin_duration_seconds",
+		Help:       "Time spent by a provisioning plugin on a project event, including retries",
+		Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+	}, []string{"event_type", "plugin"})
+
+	sloViolations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tenant_controller_provisioning_slo_violations_total",
+		Help: "Project events that took longer than the provisioning SLO, by the plugin that took the most time",
+	}, []string{"event_type", "plugin"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(provisioningDuration, pluginDuration, sloViolations)
+}
+
+// Provisioning is the outcome of handling a project event.
+type Provisioning struct {
+	EventType    string
+	Organization string
+	Project      string
+	UUID         string
+	Received     time.Time
+	Finished     time.Time
+	Err          error
+	// time spent in each plugin, keyed by plugin name
+	PluginTimes map[string]time.Duration
+}
+
+// Violation describes a project event that took longer than the SLO.
+type Violation struct {
+	EventType    string
+	Organization string
+	Project      string
+	UUID         string
+	Result       string
+	Elapsed      time.Duration
+	SLO          time.Duration
+	// the plugin that took the most time, empty if no plugin ran
+	Plugin        string
+	PluginElapsed time.Duration
+	PluginTimes   map[string]time.Duration
+}
+
+func (v Violation) Message() string {
+	message := fmt.Sprintf("%s of project %s/%s took %s, exceeding the provisioning SLO of %s", v.EventType,
+		v.Organization, v.Project, v.Elapsed.Round(time.Second), v.SLO)
+	if v.Plugin != "" {
+		message += fmt.Sprintf("; %s took %s", v.Plugin, v.PluginElapsed.Round(time.Second))
+	}
+	return message
+}
+
+// Notifier sends an alert for an SLO violation.
+type Notifier interface {
+	Notify(ctx context.Context, violation Violation) error
+}
+
+// Tracker records provisioning time metrics and alerts when a project event takes longer than the SLO.
+type Tracker struct {
+	slo       time.Duration
+	notifiers []Notifier
+}
+
+// NewTracker creates a tracker for the given SLO. An SLO of 0 records metrics without alerting.
+func NewTracker(slo time.Duration, notifiers ...Notifier) *Tracker {
+	return &Tracker{
+		slo:       slo,
+		notifiers: notifiers,
+	}
+}
+
+// WithNotifier adds a notifier. Notifiers must be added before the tracker is in use.
+func (t *Tracker) WithNotifier(notifier Notifier) *Tracker {
+	t.notifiers = append(t.notifiers, notifier)
+	return t
+}
+
+// Observe records the provisioning time of a project event and sends alerts if it exceeded the SLO. Failing to
+// send an alert is logged and does not fail the event.
+func (t *Tracker) Observe(ctx context.Context, p Provisioning) {
+	result := ResultSuccess
+	if p.Err != nil {
+		result = ResultError
+	}
+	elapsed := p.Finished.Sub(p.Received)
+	provisioningDuration.WithLabelValues(p.EventType, result).Observe(elapsed.Seconds())
+	for plugin, pluginElapsed := range p.PluginTimes {
+		pluginDuration.WithLabelValues(p.EventType, plugin).Observe(pluginElapsed.Seconds())
+	}
+
+	if t.slo == 0 || elapsed <= t.slo {
+		return
+	}
+	violation := Violation{
+		EventType:    p.EventType,
+		Organization: p.Organization,
+		Project:      p.Project,
+		UUID:         p.UUID,
+		Result:       result,
+		Elapsed:      elapsed,
+		SLO:          t.slo,
+		PluginTimes:  p.PluginTimes,
+	}
+	for plugin, pluginElapsed := range p.PluginTimes {
+		if pluginElapsed > violation.PluginElapsed || (pluginElapsed == violation.PluginElapsed && plugin < violation.Plugin) {
+			violation.Plugin, violation.PluginElapsed = plugin, pluginElapsed
+		}
+	}
+	sloViolations.WithLabelValues(p.EventType, violation.Plugin).Inc()
+
+	log.Warn(violation.Message())
+	for _, notifier := range t.notifiers {
+		if err := notifier.Notify(ctx, violation); err != nil {
+			log.Warnf("Unable to send provisioning SLO alert for project %s: %v", p.Project, err)
+		}
+	}
+}
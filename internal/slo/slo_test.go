@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package slo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Suite of SLO tracker tests
+type SLOTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *SLOTestSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 1*time.Minute)
+}
+
+func (s *SLOTestSuite) TearDownTest() {
+	s.cancel()
+}
+
+func TestSLO(t *testing.T) {
+	suite.Run(t, &SLOTestSuite{})
+}
+
+type recordingNotifier struct {
+	violations []Violation
+	err        error
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, violation Violation) error {
+	n.violations = append(n.violations, violation)
+	return n.err
+}
+
+func provisioning(elapsed time.Duration, pluginTimes map[string]time.Duration) Provisioning {
+	received := time.Now()
+	return Provisioning{
+		EventType:    "create",
+		Organization: "org",
+		Project:      "proj",
+		UUID:         "uuid",
+		Received:     received,
+		Finished:     received.Add(elapsed),
+		PluginTimes:  pluginTimes,
+	}
+}
+
+func (s *SLOTestSuite) TestWithinSLO() {
+	notifier := &recordingNotifier{}
+	tracker := NewTracker(5*time.Minute, notifier)
+	tracker.Observe(s.ctx, provisioning(4*time.Minute, map[string]time.Duration{"Harbor Provisioner": time.Minute}))
+	s.Empty(notifier.violations)
+}
+
+func (s *SLOTestSuite) TestViolation() {
+	notifier := &recordingNotifier{}
+	failingNotifier := &recordingNotifier{err: errors.New("unavailable")}
+	tracker := NewTracker(5*time.Minute, failingNotifier).WithNotifier(notifier)
+	violations := testutil.ToFloat64(sloViolations.WithLabelValues("create", "Extensions Provisioner"))
+
+	tracker.Observe(s.ctx, provisioning(7*time.Minute, map[string]time.Duration{
+		"Harbor Provisioner":     time.Minute,
+		"Catalog Provisioner":    30 * time.Second,
+		"Extensions Provisioner": 5 * time.Minute,
+	}))
+
+	// A failing notifier does not stop the others
+	s.Len(failingNotifier.violations, 1)
+	s.Len(notifier.violations, 1)
+	violation := notifier.violations[0]
+	s.Equal("Extensions Provisioner", violation.Plugin)
+	s.Equal(5*time.Minute, violation.PluginElapsed)
+	s.Equal(ResultSuccess, violation.Result)
+	s.Equal("create of project org/proj took 7m0s, exceeding the provisioning SLO of 5m0s; Extensions Provisioner took 5m0s",
+		violation.Message())
+	s.Equal(violations+1, testutil.ToFloat64(sloViolations.WithLabelValues("create", "Extensions Provisioner")))
+}
+
+func (s *SLOTestSuite) TestDisabled() {
+	notifier := &recordingNotifier{}
+	tracker := NewTracker(0, notifier)
+	tracker.Observe(s.ctx, provisioning(time.Hour, nil))
+	s.Empty(notifier.violations)
+}
+
+func (s *SLOTestSuite) TestWebhookNotifier() {
+	payload := WebhookPayload{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal(http.MethodPost, r.Method)
+		s.NoError(json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	violation := Violation{
+		EventType:     "create",
+		Organization:  "org",
+		Project:       "proj",
+		Result:        ResultError,
+		Elapsed:       10 * time.Minute,
+		SLO:           5 * time.Minute,
+		Plugin:        "Harbor Provisioner",
+		PluginElapsed: 9 * time.Minute,
+		PluginTimes:   map[string]time.Duration{"Harbor Provisioner": 9 * time.Minute},
+	}
+	s.NoError(NewWebhookNotifier(server.URL).Notify(s.ctx, violation))
+	s.Equal("proj", payload.Project)
+	s.Equal(ResultError, payload.Result)
+	s.Equal(600.0, payload.ElapsedSeconds)
+	s.Equal(300.0, payload.SLOSeconds)
+	s.Equal("Harbor Provisioner", payload.Plugin)
+	s.Equal(map[string]float64{"Harbor Provisioner": 540}, payload.PluginSeconds)
+	s.Equal(violation.Message(), payload.Message)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	s.ErrorContains(NewWebhookNotifier(failing.URL).Notify(s.ctx, violation), "502")
+}
+
+func (s *SLOTestSuite) TestEventNotifier() {
+	clientset := fake.NewClientset()
+	notifier := newEventNotifier(clientset.CoreV1().Events("orch-app"), "orch-app", "tenant-controller-1")
+	violation := Violation{
+		EventType:    "delete",
+		Organization: "org",
+		Project:      "proj",
+		Elapsed:      6 * time.Minute,
+		SLO:          5 * time.Minute,
+	}
+	s.NoError(notifier.Notify(s.ctx, violation))
+
+	events, err := clientset.CoreV1().Events("orch-app").List(s.ctx, metaV1.ListOptions{})
+	s.NoError(err)
+	s.Len(events.Items, 1)
+	event := events.Items[0]
+	s.Equal(EventReason, event.Reason)
+	s.Equal("Warning", event.Type)
+	s.Equal("tenant-controller-1", event.InvolvedObject.Name)
+	s.Equal("delete of project org/proj took 6m0s, exceeding the provisioning SLO of 5m0s", event.Message)
+	s.Equal("proj", event.Annotations["project"])
+}
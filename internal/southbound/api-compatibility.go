@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"context"
+	"fmt"
+
+	catalogv3 "github.com/open-edge-platform/app-orch-catalog/pkg/api/catalog/v3"
+	adm "github.com/open-edge-platform/app-orch-deployment/app-deployment-manager/api/nbi/v2/deployment/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// gRPC APIs the controller is built against. A catalog or ADM that does not serve them is not compatible.
+var (
+	CatalogAPI = catalogv3.CatalogService_ServiceDesc.ServiceName
+	ADMAPI     = adm.DeploymentService_ServiceDesc.ServiceName
+)
+
+// CheckAPI reports whether the catalog serves CatalogAPI. It returns a permanent error if the catalog does not,
+// and a transient error if the catalog cannot be reached.
+func (c *AppCatalog) CheckAPI(ctx context.Context) error {
+	// The call is not authenticated: gRPC servers reject calls to unknown services before checking credentials
+	_, err := c.catalogClient.ListRegistries(ctx, &catalogv3.ListRegistriesRequest{PageSize: 1})
+	return checkAPIResponse(ServiceCatalog, CatalogAPI, err)
+}
+
+// CheckAPI reports whether ADM serves ADMAPI. It returns a permanent error if ADM does not, and a transient error
+// if ADM cannot be reached.
+func (a *AppDeployment) CheckAPI(ctx context.Context) error {
+	_, err := a.admClient.ListDeployments(ctx, &adm.ListDeploymentsRequest{PageSize: 1})
+	return checkAPIResponse(ServiceADM, ADMAPI, err)
+}
+
+// checkAPIResponse classifies the response to a call made to check that a service serves an API. Any answer
+// other than Unimplemented comes from the API, even if the call itself was refused.
+func checkAPIResponse(service string, api string, err error) error {
+	switch status.Code(err) {
+	case codes.Unimplemented:
+		return classify(ErrPermanent, fmt.Errorf("the %s service does not serve the %s API required by this controller: %v",
+			service, api, status.Convert(err).Message()))
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return classify(ErrTransient, fmt.Errorf("unable to check the %s API of the %s service: %w", api, service, err))
+	default:
+		return nil
+	}
+}
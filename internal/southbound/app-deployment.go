@@ -6,13 +6,12 @@ package southbound
 
 import (
 	"context"
+	"fmt"
 
 	adm "github.com/open-edge-platform/app-orch-deployment/app-deployment-manager/api/nbi/v2/deployment/v1"
 	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
-	"github.com/open-edge-platform/orch-library/go/pkg/grpc/retry"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -26,6 +25,7 @@ type AdmClient interface {
 type AppDeployment struct {
 	configuration config.Configuration
 	admClient     AdmClient
+	tokens        *TokenSource
 }
 
 var admClientFactory = NewAdmClient
@@ -34,60 +34,152 @@ func NewAppDeployment(configuration config.Configuration) (*AppDeployment, error
 	return newADM(configuration)
 }
 
-func NewAppDeploymentGRPCClient(admGrpcHost string) (adm.DeploymentServiceClient, error) {
-	var opts []grpc.DialOption
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithStreamInterceptor(retry.RetryingStreamClientInterceptor(retry.WithRetryOn(codes.Unavailable, codes.Unknown))),
-		grpc.WithUnaryInterceptor(retry.RetryingUnaryClientInterceptor(retry.WithRetryOn(codes.Unavailable, codes.Unknown))))
-
-	conn, err := grpc.NewClient(admGrpcHost, opts...)
+func NewAppDeploymentGRPCClient(admGrpcHost string, settings config.GRPCSettings) (adm.DeploymentServiceClient, error) {
+	conn, err := grpc.NewClient(admGrpcHost, grpcDialOptions(ServiceADM, settings)...)
 	if err != nil {
 		return nil, err
 	}
 	return adm.NewDeploymentServiceClient(conn), nil
 }
 
-func NewAdmClient(admGrpcHost string) (AdmClient, error) {
-	return NewAppDeploymentGRPCClient(admGrpcHost)
+func NewAdmClient(admGrpcHost string, settings config.GRPCSettings) (AdmClient, error) {
+	return NewAppDeploymentGRPCClient(admGrpcHost, settings)
 }
 
 func newADM(configuration config.Configuration) (*AppDeployment, error) {
 	ad := &AppDeployment{
 		configuration: configuration,
+		tokens:        SharedTokenSource(configuration),
 	}
 	var err error
-	ad.admClient, err = admClientFactory(configuration.AdmServer)
+	ad.admClient, err = admClientFactory(configuration.AdmServer, configuration.GRPC)
 	if err != nil {
 		return nil, err
 	}
 	return ad, nil
 }
 
-func (a *AppDeployment) ListDeploymentNames(ctx context.Context, projectID string) (map[string]string, error) {
-	ctx, err := getCtxForProjectID(ctx, projectID, a.configuration)
+// admPageSize is the number of deployments requested from ADM per page
+const admPageSize = 100
+
+// DeploymentFilter selects the deployments returned by ListDeployments.
+type DeploymentFilter struct {
+	// cluster labels in key=value form that the deployments must target
+	Labels []string
+	// ADM selection criteria, passed to ADM as is
+	Filter string
+	// number of deployments requested per page, admPageSize if 0
+	PageSize int32
+}
+
+// DeploymentInfo is the metadata of an existing ADM deployment.
+type DeploymentInfo struct {
+	ID          string
+	DisplayName string
+	AppName     string
+	AppVersion  string
+	ProfileName string
+	// ADM state of the deployment, such as RUNNING or ERROR
+	State string
+}
+
+// ListDeployments returns the deployments of a project that match the filter, keyed by display name. All pages
+// of the ADM response are read.
+func (a *AppDeployment) ListDeployments(ctx context.Context, projectID string, filter DeploymentFilter) (map[string]DeploymentInfo, error) {
+	ctx, err := getCtxForProjectID(ctx, projectID, a.tokens)
 	if err != nil {
 		return nil, err
 	}
-	admResp, err := a.admClient.ListDeployments(ctx, &adm.ListDeploymentsRequest{})
+	existingDeployments, err := a.listDeployments(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	existingDeployments := admResp.GetDeployments()
-	existingDisplayNames := make(map[string]string)
+	deployments := make(map[string]DeploymentInfo, len(existingDeployments))
 	for _, dep := range existingDeployments {
-		log.Infof("displayName : %s", dep.DisplayName)
-		existingDisplayNames[dep.DisplayName] = dep.DisplayName
+		info := DeploymentInfo{
+			ID:          dep.DeployId,
+			DisplayName: dep.DisplayName,
+			AppName:     dep.AppName,
+			AppVersion:  dep.AppVersion,
+			ProfileName: dep.ProfileName,
+			State:       dep.GetStatus().GetState().String(),
+		}
+		log.Debugf("Deployment %s: %s:%s profile %s state %s", info.DisplayName, info.AppName, info.AppVersion,
+			info.ProfileName, info.State)
+		deployments[dep.DisplayName] = info
+	}
+
+	log.Infof("deployment list size : %d", len(deployments))
+	return deployments, nil
+}
+
+func (a *AppDeployment) listDeployments(ctx context.Context, filter DeploymentFilter) ([]*adm.Deployment, error) {
+	pageSize := filter.PageSize
+	if pageSize <= 0 {
+		pageSize = admPageSize
+	}
+	var deployments []*adm.Deployment
+	for {
+		admResp, err := a.admClient.ListDeployments(ctx, &adm.ListDeploymentsRequest{
+			Labels:   filter.Labels,
+			Filter:   filter.Filter,
+			PageSize: pageSize,
+			Offset:   int32(len(deployments)), //nolint:gosec // Bounded by the ADM total
+		})
+		if err != nil {
+			return nil, grpcError(err)
+		}
+		page := admResp.GetDeployments()
+		deployments = append(deployments, page...)
+		if len(page) < int(pageSize) || int32(len(deployments)) >= admResp.GetTotalElements() { //nolint:gosec // Bounded by the ADM total
+			return deployments, nil
+		}
 	}
+}
 
-	log.Infof("display name list size : %d", len(existingDisplayNames))
-	return existingDisplayNames, nil
+// DeploymentTargets selects the clusters of an auto-scaling deployment.
+type DeploymentTargets struct {
+	// cluster label sets. A cluster is targeted if it has all the labels of any of the sets
+	LabelSets []map[string]string
+	// applications of the deployment package. ADM takes a single label set for all the applications of a
+	// deployment, so several label sets are set for each application instead
+	Apps []string
 }
 
+// SingleTarget returns the targets of a deployment with a single label set.
+func SingleTarget(labels map[string]string) DeploymentTargets {
+	return DeploymentTargets{LabelSets: []map[string]string{labels}}
+}
+
+// targetClusters sets the cluster targets of the deployment.
+func (t DeploymentTargets) targetClusters(deployment *adm.Deployment) error {
+	if len(t.LabelSets) <= 1 {
+		var labels map[string]string
+		if len(t.LabelSets) == 1 {
+			labels = t.LabelSets[0]
+		}
+		deployment.AllAppTargetClusters = &adm.TargetClusters{Labels: labels}
+		return nil
+	}
+	if len(t.Apps) == 0 {
+		return classify(ErrPermanent, fmt.Errorf("deployment %s has %d target label sets but no applications to set them for",
+			deployment.DisplayName, len(t.LabelSets)))
+	}
+	for _, app := range t.Apps {
+		for _, labels := range t.LabelSets {
+			deployment.TargetClusters = append(deployment.TargetClusters, &adm.TargetClusters{AppName: app, Labels: labels})
+		}
+	}
+	return nil
+}
+
+// CreateDeployment creates an auto-scaling deployment of a deployment package, targeting the clusters that match
+// any of the label sets of the targets. An existing deployment is left as is.
 func (a *AppDeployment) CreateDeployment(ctx context.Context,
 	dpName string, displayName string, version string, profileName string,
-	projectID string, labels map[string]string) error {
-	log.Infof("ADM Create Deployment DP name:%s display name:%s version:%s profileName:%s project ID:%s labels:%v", dpName, displayName, version, profileName, projectID, labels)
+	projectID string, targets DeploymentTargets) error {
+	log.Infof("ADM Create Deployment DP name:%s display name:%s version:%s profileName:%s project ID:%s labels:%v", dpName, displayName, version, profileName, projectID, targets.LabelSets)
 	deployment := &adm.CreateDeploymentRequest{
 		Deployment: &adm.Deployment{
 			DisplayName:    displayName,
@@ -95,24 +187,26 @@ func (a *AppDeployment) CreateDeployment(ctx context.Context,
 			AppVersion:     version,
 			ProfileName:    profileName,
 			DeploymentType: "auto-scaling",
-			AllAppTargetClusters: &adm.TargetClusters{
-				Labels: labels,
-			},
 		},
 	}
+	if err := targets.targetClusters(deployment.Deployment); err != nil {
+		return err
+	}
 
-	lctx, err := getCtxForProjectID(ctx, projectID, a.configuration)
+	lctx, err := getCtxForProjectID(ctx, projectID, a.tokens)
 	if err != nil {
 		return err
 	}
-	resp, err := a.admClient.CreateDeployment(lctx, deployment)
+	callCtx, cancel := withCallTimeout(lctx, a.configuration.ADMCreateTimeout)
+	defer cancel()
+	resp, err := a.admClient.CreateDeployment(callCtx, deployment)
 	if e, ok := status.FromError(err); ok {
 		if e.Code() == codes.AlreadyExists {
 			return nil
 		}
 	}
 	if err != nil {
-		return err
+		return admCreateError(displayName, dpName, version, profileName, err)
 	}
 	log.Infof("ADM Created deployment %s", resp.DeploymentId)
 	return nil
@@ -123,20 +217,17 @@ func (a *AppDeployment) DeleteDeployment(ctx context.Context,
 	projectID string, missingOkay bool) error {
 	log.Infof("ADM Delete Deployment DP name:%s display name:%s version:%s profileName:%s project ID:%s", dpName, displayName, version, profileName, projectID)
 
-	lctx, err := getCtxForProjectID(ctx, projectID, a.configuration)
+	lctx, err := getCtxForProjectID(ctx, projectID, a.tokens)
 	if err != nil {
 		return err
 	}
 
-	listDeploymentsRequest := &adm.ListDeploymentsRequest{
-		// TODO: add Filter
-	}
-	resp, err := a.admClient.ListDeployments(lctx, listDeploymentsRequest)
+	existingDeployments, err := a.listDeployments(lctx, DeploymentFilter{})
 	if err != nil {
 		return err
 	}
 	deplID := ""
-	for _, dep := range resp.GetDeployments() {
+	for _, dep := range existingDeployments {
 		if dep.DisplayName == displayName && dep.AppName == dpName && dep.AppVersion == version && dep.ProfileName == profileName {
 			deplID = dep.DeployId
 			log.Infof("Found deployment %s with ID %s", displayName, deplID)
@@ -148,7 +239,7 @@ func (a *AppDeployment) DeleteDeployment(ctx context.Context,
 			log.Infof("Deployment %s not found, skipping deletion", displayName)
 			return nil
 		}
-		return status.Errorf(codes.NotFound, "Deployment %s not found", displayName)
+		return grpcError(status.Errorf(codes.NotFound, "Deployment %s not found", displayName))
 	}
 
 	deleteDeploymentRequest := &adm.DeleteDeploymentRequest{
@@ -158,7 +249,7 @@ func (a *AppDeployment) DeleteDeployment(ctx context.Context,
 
 	_, err = a.admClient.DeleteDeployment(lctx, deleteDeploymentRequest)
 	if err != nil {
-		return err
+		return grpcError(err)
 	}
 	log.Info("ADM Deleted Deployment")
 	return nil
@@ -6,6 +6,8 @@ package southbound
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"testing"
 	"time"
 
@@ -36,6 +38,7 @@ func (s *AppDeploymentTestSuite) SetupTest() {
 	mockClient := MockCatalogClient{}
 	_ = mockClient
 	deployments = make(map[string]*adm.Deployment)
+	listRequests = nil
 }
 
 func (s *AppDeploymentTestSuite) TearDownTest() {
@@ -51,10 +54,16 @@ type testAdmClient struct {
 
 var deployments map[string]*adm.Deployment
 
-func (c *testAdmClient) ListDeployments(_ context.Context, _ *adm.ListDeploymentsRequest, _ ...grpc.CallOption) (*adm.ListDeploymentsResponse, error) {
-	var resp adm.ListDeploymentsResponse
-	for _, deployment := range deployments {
-		resp.Deployments = append(resp.Deployments, deployment)
+var listRequests []*adm.ListDeploymentsRequest
+
+func (c *testAdmClient) ListDeployments(_ context.Context, in *adm.ListDeploymentsRequest, _ ...grpc.CallOption) (*adm.ListDeploymentsResponse, error) {
+	listRequests = append(listRequests, in)
+	names := slices.Sorted(maps.Keys(deployments))
+	resp := adm.ListDeploymentsResponse{TotalElements: int32(len(names))} //nolint:gosec // Small test data
+	start := min(int(in.Offset), len(names))
+	end := min(start+int(in.PageSize), len(names))
+	for _, name := range names[start:end] {
+		resp.Deployments = append(resp.Deployments, deployments[name])
 	}
 	return &resp, nil
 }
@@ -73,13 +82,13 @@ func (c *testAdmClient) DeleteDeployment(_ context.Context, in *adm.DeleteDeploy
 	return &emptypb.Empty{}, nil
 }
 
-func NewTestAdmClient(_ string) (AdmClient, error) {
+func NewTestAdmClient(_ string, _ config.GRPCSettings) (AdmClient, error) {
 	testClient := &testAdmClient{}
 	return testClient, nil
 }
 
 func (s *AppDeploymentTestSuite) TestClientCreation() {
-	c, err := NewAppDeploymentGRPCClient("http://localhost:1234")
+	c, err := NewAppDeploymentGRPCClient("http://localhost:1234", config.GRPCSettings{})
 	s.NoError(err)
 	s.NotNil(c)
 }
@@ -89,20 +98,82 @@ func (s *AppDeploymentTestSuite) TestAppDeployment() {
 	ADM, err := newADM(config.Configuration{AdmServer: ""})
 	s.NoError(err)
 
-	_, err = ADM.ListDeploymentNames(s.ctx, "")
+	_, err = ADM.ListDeployments(s.ctx, "", DeploymentFilter{})
 	s.NoError(err)
 
 	labels1 := map[string]string{
 		"l1": "l1",
 	}
 	err = ADM.CreateDeployment(s.ctx, "deployment1", "Deployment 1", "1.1.1",
-		"profile", "uuid", labels1)
+		"profile", "uuid", SingleTarget(labels1))
 	s.NoError(err)
 
 	s.Len(deployments, 1)
+	s.Equal(labels1, deployments[""].AllAppTargetClusters.Labels)
+}
+
+func (s *AppDeploymentTestSuite) TestCreateDeploymentLabelSets() {
+	ADM, err := newADM(config.Configuration{AdmServer: ""})
+	s.NoError(err)
+
+	// Several label sets are set for each application of the package
+	targets := DeploymentTargets{
+		LabelSets: []map[string]string{{"tier": "restricted"}, {"tier": "privileged", "zone": "b"}},
+		Apps:      []string{"app1", "app2"},
+	}
+	s.NoError(ADM.CreateDeployment(s.ctx, "dp", "DP", "1.0.0", "profile", "uuid", targets))
+	deployment := deployments[""]
+	s.Nil(deployment.AllAppTargetClusters)
+	s.Len(deployment.TargetClusters, 4)
+	s.Equal("app1", deployment.TargetClusters[0].AppName)
+	s.Equal(map[string]string{"tier": "restricted"}, deployment.TargetClusters[0].Labels)
+	s.Equal("app2", deployment.TargetClusters[3].AppName)
+	s.Equal(map[string]string{"tier": "privileged", "zone": "b"}, deployment.TargetClusters[3].Labels)
+
+	// The applications are needed to set several label sets
+	targets.Apps = nil
+	err = ADM.CreateDeployment(s.ctx, "dp", "DP", "1.0.0", "profile", "uuid", targets)
+	s.ErrorIs(err, ErrPermanent)
+}
+
+func (s *AppDeploymentTestSuite) TestListDeploymentsPages() {
+	ADM, err := newADM(config.Configuration{AdmServer: ""})
+	s.NoError(err)
+
+	for i := range 5 {
+		name := fmt.Sprintf("deployment%d", i)
+		deployments[name] = &adm.Deployment{
+			Name:        name,
+			DeployId:    "id-" + name,
+			DisplayName: "Deployment " + name,
+			AppName:     "app",
+			AppVersion:  "1.0.0",
+			ProfileName: "profile",
+			Status:      &adm.Deployment_Status{State: adm.State_RUNNING},
+		}
+	}
+
+	found, err := ADM.ListDeployments(s.ctx, "", DeploymentFilter{Labels: []string{"color=blue"}, PageSize: 2})
+	s.NoError(err)
+	s.Len(found, 5)
+	s.Equal(DeploymentInfo{
+		ID:          "id-deployment3",
+		DisplayName: "Deployment deployment3",
+		AppName:     "app",
+		AppVersion:  "1.0.0",
+		ProfileName: "profile",
+		State:       "RUNNING",
+	}, found["Deployment deployment3"])
+
+	s.Len(listRequests, 3)
+	for i, request := range listRequests {
+		s.Equal(int32(2*i), request.Offset) //nolint:gosec // Small test data
+		s.Equal(int32(2), request.PageSize)
+		s.Equal([]string{"color=blue"}, request.Labels)
+	}
 }
 
-func NewAdmClientWithError(_ string) (AdmClient, error) {
+func NewAdmClientWithError(_ string, _ config.GRPCSettings) (AdmClient, error) {
 	return nil, fmt.Errorf("no client here")
 }
 
@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"context"
+
+	catalogv3 "github.com/open-edge-platform/app-orch-catalog/pkg/api/catalog/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxPackagePageSize is the number of deployment packages requested from the catalog per page
+const maxPackagePageSize = 100
+
+// ProjectFile is a deployment package loaded into the catalog of a project from uploaded YAML files. The catalog
+// keeps no record of the files themselves, only of the entities loaded from them, so the files of a deployment
+// package are identified by its name and version.
+type ProjectFile struct {
+	Name    string
+	Version string
+	// applications referenced by the deployment package
+	Applications []ProjectApplication
+}
+
+// ProjectApplication is an application version referenced by a deployment package
+type ProjectApplication struct {
+	Name    string
+	Version string
+}
+
+// ListProjectFiles returns the deployment packages in the catalog of the project. All pages of the catalog
+// response are read.
+func (c *AppCatalog) ListProjectFiles(ctx context.Context, projectUUID string) ([]ProjectFile, error) {
+	ctx, err := getCtxForProjectID(ctx, projectUUID, c.tokens)
+	if err != nil {
+		return nil, err
+	}
+	var packages []*catalogv3.DeploymentPackage
+	for {
+		resp, err := c.catalogClient.ListDeploymentPackages(ctx, &catalogv3.ListDeploymentPackagesRequest{
+			PageSize: maxPackagePageSize,
+			Offset:   int32(len(packages)), //nolint:gosec // Bounded by the catalog
+		})
+		if err != nil {
+			return nil, grpcError(err)
+		}
+		if resp == nil || len(resp.DeploymentPackages) == 0 {
+			break
+		}
+		packages = append(packages, resp.DeploymentPackages...)
+		if int(resp.TotalElements) <= len(packages) {
+			break
+		}
+	}
+
+	files := make([]ProjectFile, 0, len(packages))
+	for _, dp := range packages {
+		file := ProjectFile{Name: dp.Name, Version: dp.Version}
+		for _, app := range dp.GetApplicationReferences() {
+			file.Applications = append(file.Applications, ProjectApplication{Name: app.Name, Version: app.Version})
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// DeleteProjectFile deletes a deployment package from the catalog of the project, then the applications it
+// references. Applications that another deployment package still references are kept. Deleting a deployment
+// package that does not exist is not an error.
+func (c *AppCatalog) DeleteProjectFile(ctx context.Context, projectUUID string, file ProjectFile) error {
+	log.Infof("Deleting deployment package %s:%s from project %s", file.Name, file.Version, projectUUID)
+	ctx, err := getCtxForProjectID(ctx, projectUUID, c.tokens)
+	if err != nil {
+		return err
+	}
+	_, err = c.catalogClient.DeleteDeploymentPackage(ctx, &catalogv3.DeleteDeploymentPackageRequest{
+		DeploymentPackageName: file.Name,
+		Version:               file.Version,
+	})
+	if err != nil && status.Code(err) != codes.NotFound {
+		return grpcError(err)
+	}
+	for _, app := range file.Applications {
+		_, err := c.catalogClient.DeleteApplication(ctx, &catalogv3.DeleteApplicationRequest{
+			ApplicationName: app.Name,
+			Version:         app.Version,
+		})
+		switch status.Code(err) {
+		case codes.OK, codes.NotFound:
+		case codes.FailedPrecondition:
+			log.Infof("Keeping application %s:%s of project %s, it is still referenced", app.Name, app.Version, projectUUID)
+		default:
+			return grpcError(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	catalogv3 "github.com/open-edge-platform/app-orch-catalog/pkg/api/catalog/v3"
+)
+
+// Catalog registries have no labels, so the registries created by the controller are marked as owned by their
+// project with a marker at the end of their description.
+const registryOwnerMarkerFormat = "[owner: app-orch-tenant-controller/%s]"
+
+// maxRegistryPageSize is the number of registries requested from the catalog per page
+const maxRegistryPageSize = 100
+
+var registryOwnerPattern = regexp.MustCompile(`\s*\[owner: app-orch-tenant-controller/([^\]]*)\]$`)
+
+// ownedDescription returns the description with the ownership marker of the project, replacing any previous one.
+func ownedDescription(description string, projectUUID string) string {
+	description = registryOwnerPattern.ReplaceAllString(description, "")
+	if projectUUID == "" {
+		return description
+	}
+	marker := fmt.Sprintf(registryOwnerMarkerFormat, projectUUID)
+	if description == "" {
+		return marker
+	}
+	return description + " " + marker
+}
+
+// registryOwner returns the project UUID of the ownership marker of the registry description, or an empty string
+// if the registry has no marker.
+func registryOwner(description string) string {
+	match := registryOwnerPattern.FindStringSubmatch(description)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// listRegistries returns all the registries of the project of the context, reading every page of the catalog
+// response. The credentials of the registries are not returned.
+func (c *AppCatalog) listRegistries(ctx context.Context) ([]*catalogv3.Registry, error) {
+	var registries []*catalogv3.Registry
+	for {
+		resp, err := c.catalogClient.ListRegistries(ctx, &catalogv3.ListRegistriesRequest{
+			PageSize: maxRegistryPageSize,
+			Offset:   int32(len(registries)), //nolint:gosec // Bounded by the catalog
+		})
+		if err != nil {
+			return nil, grpcError(err)
+		}
+		if resp == nil || len(resp.Registries) == 0 {
+			return registries, nil
+		}
+		registries = append(registries, resp.Registries...)
+		if int(resp.TotalElements) <= len(registries) {
+			return registries, nil
+		}
+	}
+}
+
+// ListProjectRegistries returns the registries of the project, without their credentials.
+func (c *AppCatalog) ListProjectRegistries(ctx context.Context, projectUUID string) ([]RegistryAttributes, error) {
+	ctx, err := getCtxForProjectID(ctx, projectUUID, c.tokens)
+	if err != nil {
+		return nil, err
+	}
+	registries, err := c.listRegistries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	attrs := make([]RegistryAttributes, 0, len(registries))
+	for _, registry := range registries {
+		attrs = append(attrs, RegistryAttributes{
+			Name:         registry.GetName(),
+			DisplayName:  registry.GetDisplayName(),
+			Description:  registry.GetDescription(),
+			Type:         registry.GetType(),
+			RootURL:      registry.GetRootUrl(),
+			InventoryURL: registry.GetInventoryUrl(),
+			Username:     registry.GetUsername(),
+			Cacerts:      registry.GetCacerts(),
+			ProjectUUID:  projectUUID,
+			Anonymous:    registry.GetUsername() == "",
+		})
+	}
+	return attrs, nil
+}
+
+// VerifyProjectOwnership checks that the catalog of the project holds what the controller created for it before the
+// catalog is wiped. It lists the registries of the project and returns a permanent error if a registry is marked as
+// owned by another project, or if the registries marked as owned by the project are not the registries recorded in
+// its inventory. A nil list of recorded registries, for projects without an inventory, skips the comparison.
+//
+// Projects provisioned before the ownership markers were introduced have no marked registries until they are
+// provisioned again; their registries are only compared by name with the inventory.
+func (c *AppCatalog) VerifyProjectOwnership(ctx context.Context, projectUUID string, recorded []string) error {
+	if projectUUID == "" {
+		return classify(ErrPermanent, fmt.Errorf("refusing to wipe the catalog of a project without UUID"))
+	}
+	ctx, err := getCtxForProjectID(ctx, projectUUID, c.tokens)
+	if err != nil {
+		return err
+	}
+
+	registries, err := c.listRegistries(ctx)
+	if err != nil {
+		return err
+	}
+
+	owned := []string{}
+	all := []string{}
+	for _, registry := range registries {
+		all = append(all, registry.Name)
+		switch owner := registryOwner(registry.Description); owner {
+		case "":
+		case projectUUID:
+			owned = append(owned, registry.Name)
+		default:
+			return classify(ErrPermanent, fmt.Errorf("refusing to wipe the catalog of project %s: registry %s is owned by project %s",
+				projectUUID, registry.Name, owner))
+		}
+	}
+	if recorded == nil {
+		return nil
+	}
+
+	legacy := len(owned) == 0
+	if legacy {
+		// Provisioned before the ownership markers. Registries added by users cannot be told apart, so only the
+		// recorded registries are looked for.
+		log.Warnf("Catalog registries of project %s have no ownership marker, verifying them by name", projectUUID)
+		owned = all
+	}
+	missing := []string{}
+	for _, name := range recorded {
+		if !slices.Contains(owned, name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 || !legacy && len(owned) != len(recorded) {
+		return classify(ErrPermanent, fmt.Errorf("refusing to wipe the catalog of project %s: %d registries are owned by the project, the inventory records %d (missing: %s)",
+			projectUUID, len(owned), len(recorded), strings.Join(missing, ", ")))
+	}
+	return nil
+}
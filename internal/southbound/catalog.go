@@ -6,16 +6,14 @@ package southbound
 
 import (
 	"context"
+	"fmt"
 	catalogv3 "github.com/open-edge-platform/app-orch-catalog/pkg/api/catalog/v3"
 	"github.com/open-edge-platform/app-orch-catalog/pkg/wiper"
 	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
 	"github.com/open-edge-platform/orch-library/go/dazl"
 	"github.com/open-edge-platform/orch-library/go/pkg/auth"
 	"github.com/open-edge-platform/orch-library/go/pkg/errors"
-	"github.com/open-edge-platform/orch-library/go/pkg/grpc/retry"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -27,39 +25,40 @@ type CatalogClient interface {
 	UpdateRegistry(ctx context.Context, in *catalogv3.UpdateRegistryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	UploadCatalogEntities(ctx context.Context, in *catalogv3.UploadCatalogEntitiesRequest, opts ...grpc.CallOption) (*catalogv3.UploadCatalogEntitiesResponse, error)
 	ListRegistries(ctx context.Context, in *catalogv3.ListRegistriesRequest, opts ...grpc.CallOption) (*catalogv3.ListRegistriesResponse, error)
+	GetDeploymentPackage(ctx context.Context, in *catalogv3.GetDeploymentPackageRequest, opts ...grpc.CallOption) (*catalogv3.GetDeploymentPackageResponse, error)
+	ListDeploymentPackages(ctx context.Context, in *catalogv3.ListDeploymentPackagesRequest, opts ...grpc.CallOption) (*catalogv3.ListDeploymentPackagesResponse, error)
+	DeleteDeploymentPackage(ctx context.Context, in *catalogv3.DeleteDeploymentPackageRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DeleteApplication(ctx context.Context, in *catalogv3.DeleteApplicationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type AppCatalog struct {
 	config        config.Configuration
 	catalogClient CatalogClient
+	tokens        *TokenSource
 	sessionID     string
 }
 
 var catalogClientFactory = NewCatalogClient
 
-func NewCatalogGRPCClient(catalogGrpcHost string) (catalogv3.CatalogServiceClient, error) {
-	var opts []grpc.DialOption
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithStreamInterceptor(retry.RetryingStreamClientInterceptor(retry.WithRetryOn(codes.Unavailable, codes.Unknown))),
-		grpc.WithUnaryInterceptor(retry.RetryingUnaryClientInterceptor(retry.WithRetryOn(codes.Unavailable, codes.Unknown))))
-
-	conn, err := grpc.NewClient(catalogGrpcHost, opts...)
+func NewCatalogGRPCClient(catalogGrpcHost string, settings config.GRPCSettings) (catalogv3.CatalogServiceClient, error) {
+	conn, err := grpc.NewClient(catalogGrpcHost, grpcDialOptions(ServiceCatalog, settings)...)
 	if err != nil {
 		return nil, err
 	}
 	return catalogv3.NewCatalogServiceClient(conn), nil
 }
 
-func NewCatalogClient(catalogGrpcHost string) (CatalogClient, error) {
-	return NewCatalogGRPCClient(catalogGrpcHost)
+func NewCatalogClient(catalogGrpcHost string, settings config.GRPCSettings) (CatalogClient, error) {
+	return NewCatalogGRPCClient(catalogGrpcHost, settings)
 }
 
 func newCatalog(config config.Configuration) (*AppCatalog, error) {
 	cat := &AppCatalog{
 		config: config,
+		tokens: SharedTokenSource(config),
 	}
 	var err error
-	cat.catalogClient, err = catalogClientFactory(cat.config.CatalogServer)
+	cat.catalogClient, err = catalogClientFactory(cat.config.CatalogServer, cat.config.GRPC)
 	if err != nil {
 		return nil, err
 	}
@@ -73,23 +72,22 @@ func (c *AppCatalog) InitializeClientSecret(ctx context.Context) (string, error)
 		return "", err
 	}
 
-	k8sClient, err := K8sFactory(c.config.KeycloakNamespace)
-	if err != nil {
-		return "", err
-	}
-
-	data, err := k8sClient.ReadSecret(ctx, c.config.KeycloakSecret)
+	password, err := ReadSecretRef(ctx, c.config.Secrets.KeycloakAdmin)
 	if err != nil {
 		return "", err
 	}
 
-	p := string(data["admin-password"])
+	p := string(password)
+	RegisterSecret("keycloak-admin-password", p)
 	u := "admin"
 	m2m, err := v.GetM2MToken(ctx)
 	if err != nil || m2m == "" {
 		log.Infof("Client secret not found, creating a new one")
-		return v.CreateClientSecret(ctx, u, p)
+		secret, err := v.CreateClientSecret(ctx, u, p)
+		RegisterSecret("keycloak-client-secret", secret)
+		return secret, ScrubError(err)
 	}
+	RegisterSecret("keycloak-client-secret", m2m)
 	log.Infof("Client secret found")
 	return m2m, nil
 }
@@ -109,11 +107,35 @@ type RegistryAttributes struct {
 	Cacerts      string
 	AuthToken    string
 	ProjectUUID  string
+	// the registry is pulled without credentials, e.g. the release service registries. Registries that are not
+	// anonymous need both a username and an auth token
+	Anonymous bool
+}
+
+// ValidateCredentials returns a permanent error if the registry is neither anonymous nor has complete credentials,
+// so that a registry whose token was omitted is not created only for pulls from it to fail later.
+func (a RegistryAttributes) ValidateCredentials() error {
+	switch {
+	case a.Anonymous && (a.Username != "" || a.AuthToken != ""):
+		return classify(ErrPermanent, fmt.Errorf("registry %s is anonymous but has credentials", a.Name))
+	case a.Anonymous:
+		return nil
+	case a.Username == "" && a.AuthToken == "":
+		return classify(ErrPermanent, fmt.Errorf("registry %s has no credentials and is not anonymous", a.Name))
+	case a.Username == "":
+		return classify(ErrPermanent, fmt.Errorf("registry %s has an auth token but no username", a.Name))
+	case a.AuthToken == "":
+		return classify(ErrPermanent, fmt.Errorf("registry %s has a username but no auth token", a.Name))
+	}
+	return nil
 }
 
 func (c *AppCatalog) CreateOrUpdateRegistry(ctx context.Context, attrs RegistryAttributes) error {
 	log.Infof("Creating or updating registry %s url %s", attrs.Name, attrs.RootURL)
-	ctx, err := getCtxForProjectID(ctx, attrs.ProjectUUID, c.config)
+	if err := attrs.ValidateCredentials(); err != nil {
+		return err
+	}
+	ctx, err := getCtxForProjectID(ctx, attrs.ProjectUUID, c.tokens)
 	if err != nil {
 		return err
 	}
@@ -121,7 +143,7 @@ func (c *AppCatalog) CreateOrUpdateRegistry(ctx context.Context, attrs RegistryA
 	registry := &catalogv3.Registry{
 		Name:         attrs.Name,
 		DisplayName:  attrs.DisplayName,
-		Description:  attrs.Description,
+		Description:  ownedDescription(attrs.Description, attrs.ProjectUUID),
 		Type:         attrs.Type,
 		RootUrl:      attrs.RootURL,
 		InventoryUrl: attrs.InventoryURL,
@@ -132,33 +154,150 @@ func (c *AppCatalog) CreateOrUpdateRegistry(ctx context.Context, attrs RegistryA
 
 	if _, err = c.catalogClient.GetRegistry(ctx, &catalogv3.GetRegistryRequest{RegistryName: attrs.Name}); err != nil {
 		if !errors.IsNotFound(errors.FromGRPC(err)) {
-			return err
+			return grpcError(err)
 		}
 		if _, err = c.catalogClient.CreateRegistry(ctx, &catalogv3.CreateRegistryRequest{Registry: registry}); err != nil {
-			return err
+			return grpcError(err)
 		}
 		log.Infof("Registry %s created", attrs.Name)
 	} else {
 		if _, err = c.catalogClient.UpdateRegistry(ctx, &catalogv3.UpdateRegistryRequest{RegistryName: registry.Name, Registry: registry}); err != nil {
-			return err
+			return grpcError(err)
 		}
 		log.Infof("Registry %s updated", attrs.Name)
 	}
 	return nil
 }
 
+// UpdateRegistryCredentials replaces the username and auth token of an existing registry of the project, leaving its
+// other fields as they are. It returns an error wrapping ErrNotFound if the project has no such registry.
+func (c *AppCatalog) UpdateRegistryCredentials(ctx context.Context, projectUUID string, name string, username string, authToken string) error {
+	if err := (RegistryAttributes{Name: name, Username: username, AuthToken: authToken}).ValidateCredentials(); err != nil {
+		return err
+	}
+	if err := c.setRegistryCredentials(ctx, projectUUID, name, username, authToken); err != nil {
+		return err
+	}
+	log.Infof("Registry %s credentials updated", name)
+	return nil
+}
+
+// ClearRegistryCredentials removes the username and auth token of an existing registry of the project, so that
+// pulls from it fail without the registry being deleted. It returns an error wrapping ErrNotFound if the project has
+// no such registry.
+func (c *AppCatalog) ClearRegistryCredentials(ctx context.Context, projectUUID string, name string) error {
+	if err := c.setRegistryCredentials(ctx, projectUUID, name, "", ""); err != nil {
+		return err
+	}
+	log.Infof("Registry %s credentials removed", name)
+	return nil
+}
+
+func (c *AppCatalog) setRegistryCredentials(ctx context.Context, projectUUID string, name string, username string, authToken string) error {
+	ctx, err := getCtxForProjectID(ctx, projectUUID, c.tokens)
+	if err != nil {
+		return err
+	}
+	// The sensitive fields are needed, otherwise the update would clear the CA certificates
+	resp, err := c.catalogClient.GetRegistry(ctx, &catalogv3.GetRegistryRequest{RegistryName: name, ShowSensitiveInfo: true})
+	if err != nil {
+		if errors.IsNotFound(errors.FromGRPC(err)) {
+			return classify(ErrPermanent, fmt.Errorf("registry %s %w", name, ErrNotFound))
+		}
+		return grpcError(err)
+	}
+	registry := resp.GetRegistry()
+	registry.Username = username
+	registry.AuthToken = authToken
+	if _, err = c.catalogClient.UpdateRegistry(ctx, &catalogv3.UpdateRegistryRequest{RegistryName: name, Registry: registry}); err != nil {
+		return grpcError(err)
+	}
+	return nil
+}
+
+// RegistryExists returns true if the project already has a registry with the given name.
+func (c *AppCatalog) RegistryExists(ctx context.Context, projectUUID string, name string) (bool, error) {
+	ctx, err := getCtxForProjectID(ctx, projectUUID, c.tokens)
+	if err != nil {
+		return false, err
+	}
+	if _, err = c.catalogClient.GetRegistry(ctx, &catalogv3.GetRegistryRequest{RegistryName: name}); err != nil {
+		if errors.IsNotFound(errors.FromGRPC(err)) {
+			return false, nil
+		}
+		return false, grpcError(err)
+	}
+	return true, nil
+}
+
+// GetRegistry reads back a registry of the project, including its credentials. It returns an error wrapping
+// ErrNotFound if the project has no such registry.
+func (c *AppCatalog) GetRegistry(ctx context.Context, projectUUID string, name string) (RegistryAttributes, error) {
+	ctx, err := getCtxForProjectID(ctx, projectUUID, c.tokens)
+	if err != nil {
+		return RegistryAttributes{}, err
+	}
+	resp, err := c.catalogClient.GetRegistry(ctx, &catalogv3.GetRegistryRequest{RegistryName: name, ShowSensitiveInfo: true})
+	if err != nil {
+		if errors.IsNotFound(errors.FromGRPC(err)) {
+			return RegistryAttributes{}, classify(ErrPermanent, fmt.Errorf("registry %s %w", name, ErrNotFound))
+		}
+		return RegistryAttributes{}, grpcError(err)
+	}
+	registry := resp.GetRegistry()
+	return RegistryAttributes{
+		Name:         registry.GetName(),
+		DisplayName:  registry.GetDisplayName(),
+		Description:  registry.GetDescription(),
+		Type:         registry.GetType(),
+		RootURL:      registry.GetRootUrl(),
+		InventoryURL: registry.GetInventoryUrl(),
+		Username:     registry.GetUsername(),
+		Cacerts:      registry.GetCacerts(),
+		AuthToken:    registry.GetAuthToken(),
+		ProjectUUID:  projectUUID,
+		Anonymous:    registry.GetUsername() == "" && registry.GetAuthToken() == "",
+	}, nil
+}
+
+// DeploymentPackageApplications returns the names of the applications of a deployment package of the project.
+func (c *AppCatalog) DeploymentPackageApplications(ctx context.Context, projectUUID string, name string, version string) ([]string, error) {
+	ctx, err := getCtxForProjectID(ctx, projectUUID, c.tokens)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.catalogClient.GetDeploymentPackage(ctx, &catalogv3.GetDeploymentPackageRequest{
+		DeploymentPackageName: name,
+		Version:               version,
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	apps := []string{}
+	for _, app := range resp.GetDeploymentPackage().GetApplicationReferences() {
+		apps = append(apps, app.Name)
+	}
+	return apps, nil
+}
+
 func (c *AppCatalog) ListRegistries(ctx context.Context) error {
-	ctx, err := getCtxForProjectID(ctx, "", c.config)
+	ctx, err := getCtxForProjectID(ctx, "", c.tokens)
 	if err != nil {
 		return err
 	}
 	_, err = c.catalogClient.ListRegistries(ctx, &catalogv3.ListRegistriesRequest{})
-	return err
+	return grpcError(err)
 }
 
+// UploadYAMLFile uploads a file to the catalog of the project in the current upload session. Files larger than the
+// maximum catalog artifact size fail with ErrArtifactTooLarge without being sent.
 func (c *AppCatalog) UploadYAMLFile(ctx context.Context, projectUUID string, fileName string, artifact []byte, lastFile bool) error {
 	log.Debugf("Uploading file %s to %s last file %t", fileName, projectUUID, lastFile)
-	ctx, err := getCtxForProjectID(ctx, projectUUID, c.config)
+	if err := checkArtifactSize(fileName, artifact, c.config.MaxCatalogArtifactSize); err != nil {
+		c.sessionID = ""
+		return err
+	}
+	ctx, err := getCtxForProjectID(ctx, projectUUID, c.tokens)
 	if err != nil {
 		return err
 	}
@@ -171,29 +310,122 @@ func (c *AppCatalog) UploadYAMLFile(ctx context.Context, projectUUID string, fil
 		Upload:     fileUpload,
 		LastUpload: lastFile,
 	}
-	resp, err := c.catalogClient.UploadCatalogEntities(ctx, catalogUpload)
+	callCtx, cancel := withCallTimeout(ctx, c.config.CatalogUploadTimeout)
+	defer cancel()
+	recordPayload(ctx, ServiceCatalog, int64(len(artifact)))
+	resp, err := c.catalogClient.UploadCatalogEntities(callCtx, catalogUpload)
 	if err != nil {
-		return err
+		// the session is abandoned, so the next upload starts a new one
+		c.sessionID = ""
+		return catalogUploadError(fileName, err)
 	}
 	c.sessionID = resp.SessionId
+	if lastFile {
+		c.sessionID = ""
+	}
 	return nil
 }
 
-func (c *AppCatalog) WipeProject(ctx context.Context, projectUUID string, catalogServer string) error {
+// CatalogUpload collects the files of a catalog upload so that they can be sent in a single upload session. The
+// catalog only loads the files of a session when its last file arrives, so an upload that fails part way through
+// leaves nothing behind in the project.
+type CatalogUpload struct {
+	files []CatalogFile
+}
+
+// CatalogFile is a file of a catalog upload.
+type CatalogFile struct {
+	Name     string
+	Artifact []byte
+}
+
+// Add adds a file to the upload.
+func (u *CatalogUpload) Add(fileName string, artifact []byte) {
+	u.files = append(u.files, CatalogFile{Name: fileName, Artifact: artifact})
+}
+
+// Files returns the files of the upload, in upload order.
+func (u *CatalogUpload) Files() []CatalogFile {
+	return u.files
+}
+
+// CommitUpload sends the files of the upload to the catalog of the project in a new upload session, marking the
+// final file as the last upload. An empty upload does nothing. If a file is larger than the maximum catalog artifact
+// size, the upload fails with ErrArtifactTooLarge before any file is sent.
+func (c *AppCatalog) CommitUpload(ctx context.Context, projectUUID string, upload *CatalogUpload) error {
+	c.sessionID = ""
+	for _, f := range upload.files {
+		if err := checkArtifactSize(f.Name, f.Artifact, c.config.MaxCatalogArtifactSize); err != nil {
+			return err
+		}
+	}
+	for i, f := range upload.files {
+		if err := c.UploadYAMLFile(ctx, projectUUID, f.Name, f.Artifact, i == len(upload.files)-1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WipeProject deletes the deployment packages, applications, artifacts and registries of the project. The progress
+// function, if not nil, is called as the wipe moves from one kind of entity to the next.
+func (c *AppCatalog) WipeProject(ctx context.Context, projectUUID string, catalogServer string, progress func(message string)) error {
 	log.Infof("Wiping project %s", projectUUID)
-	ctx, err := getCtxForProjectID(ctx, projectUUID, c.config)
+	ctx, err := getCtxForProjectID(ctx, projectUUID, c.tokens)
 	if err != nil {
 		return err
 	}
-	gc, err := NewCatalogGRPCClient(catalogServer)
+	gc, err := NewCatalogGRPCClient(catalogServer, c.config.GRPC)
 
 	if err != nil {
 		return err
 	}
+	if progress != nil {
+		gc = &wipeProgressClient{CatalogServiceClient: gc, progress: progress}
+	}
 	grpcWiper := wiper.NewGRPCWiper(gc)
 	errs := grpcWiper.Wipe(ctx, projectUUID)
 	if len(errs) == 0 {
 		return nil
 	}
-	return errs[0]
+	return grpcError(errs[0])
+}
+
+// wipeStages are the kinds of entities deleted by the catalog wiper, in the order it lists them
+var wipeStages = []string{"deployment packages", "applications", "artifacts", "registries"}
+
+// wipeProgressClient reports the progress of a project wipe, recognizing each stage of the wiper by the entities
+// it lists.
+type wipeProgressClient struct {
+	catalogv3.CatalogServiceClient
+	progress func(message string)
+	stage    int
+}
+
+func (c *wipeProgressClient) enter(stage int) {
+	if stage <= c.stage {
+		return
+	}
+	c.stage = stage
+	c.progress(fmt.Sprintf("Wiping catalog %s %d/%d", wipeStages[stage-1], stage, len(wipeStages)))
+}
+
+func (c *wipeProgressClient) ListDeploymentPackages(ctx context.Context, in *catalogv3.ListDeploymentPackagesRequest, opts ...grpc.CallOption) (*catalogv3.ListDeploymentPackagesResponse, error) {
+	c.enter(1)
+	return c.CatalogServiceClient.ListDeploymentPackages(ctx, in, opts...)
+}
+
+func (c *wipeProgressClient) ListApplications(ctx context.Context, in *catalogv3.ListApplicationsRequest, opts ...grpc.CallOption) (*catalogv3.ListApplicationsResponse, error) {
+	c.enter(2)
+	return c.CatalogServiceClient.ListApplications(ctx, in, opts...)
+}
+
+func (c *wipeProgressClient) ListArtifacts(ctx context.Context, in *catalogv3.ListArtifactsRequest, opts ...grpc.CallOption) (*catalogv3.ListArtifactsResponse, error) {
+	c.enter(3)
+	return c.CatalogServiceClient.ListArtifacts(ctx, in, opts...)
+}
+
+func (c *wipeProgressClient) ListRegistries(ctx context.Context, in *catalogv3.ListRegistriesRequest, opts ...grpc.CallOption) (*catalogv3.ListRegistriesResponse, error) {
+	c.enter(4)
+	return c.CatalogServiceClient.ListRegistries(ctx, in, opts...)
 }
@@ -5,13 +5,21 @@ package southbound
 
 import (
 	"context"
+	"fmt"
 	catalogv3 "github.com/open-edge-platform/app-orch-catalog/pkg/api/catalog/v3"
+	"github.com/open-edge-platform/app-orch-catalog/pkg/wiper"
 	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/retry"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"maps"
+	"net/http"
+	"slices"
 	"testing"
 	"time"
 )
@@ -36,7 +44,9 @@ func (s *CatalogTestSuite) SetupTest() {
 	K8sFactory = NewTestK8s
 	mockClient := MockCatalogClient{}
 	_ = mockClient
-	s.configuration = config.Configuration{}
+	s.configuration = config.Configuration{
+		Secrets: config.K8sSecretsRef{KeycloakAdmin: config.SecretRef{Key: config.DefaultKeycloakSecretKey}},
+	}
 }
 
 func (s *CatalogTestSuite) TearDownTest() {
@@ -115,7 +125,53 @@ func (c *testCatalogClient) ListRegistries(_ context.Context, _ *catalogv3.ListR
 	return nil, nil
 }
 
-func NewTestCatalogClient(_ string) (CatalogClient, error) {
+var deploymentPackages = map[string]*catalogv3.DeploymentPackage{}
+
+func (c *testCatalogClient) GetDeploymentPackage(_ context.Context, in *catalogv3.GetDeploymentPackageRequest, _ ...grpc.CallOption) (*catalogv3.GetDeploymentPackageResponse, error) {
+	dp := deploymentPackages[in.DeploymentPackageName+":"+in.Version]
+	if dp == nil {
+		return nil, status.Errorf(codes.NotFound, "deployment package %s not found", in.DeploymentPackageName)
+	}
+	return &catalogv3.GetDeploymentPackageResponse{DeploymentPackage: dp}, nil
+}
+
+func (c *testCatalogClient) ListDeploymentPackages(_ context.Context, in *catalogv3.ListDeploymentPackagesRequest, _ ...grpc.CallOption) (*catalogv3.ListDeploymentPackagesResponse, error) {
+	keys := slices.Sorted(maps.Keys(deploymentPackages))
+	resp := &catalogv3.ListDeploymentPackagesResponse{TotalElements: int32(len(keys))} //nolint:gosec // Small test data
+	start := min(int(in.Offset), len(keys))
+	end := min(start+int(in.PageSize), len(keys))
+	for _, key := range keys[start:end] {
+		resp.DeploymentPackages = append(resp.DeploymentPackages, deploymentPackages[key])
+	}
+	return resp, nil
+}
+
+func (c *testCatalogClient) DeleteDeploymentPackage(_ context.Context, in *catalogv3.DeleteDeploymentPackageRequest, _ ...grpc.CallOption) (*emptypb.Empty, error) {
+	key := in.DeploymentPackageName + ":" + in.Version
+	if deploymentPackages[key] == nil {
+		return nil, status.Errorf(codes.NotFound, "deployment package %s not found", key)
+	}
+	delete(deploymentPackages, key)
+	return &emptypb.Empty{}, nil
+}
+
+// applications deleted from the test catalog, as name:version
+var deletedApplications []string
+
+// DeleteApplication refuses to delete applications still referenced by a deployment package, like the catalog
+func (c *testCatalogClient) DeleteApplication(_ context.Context, in *catalogv3.DeleteApplicationRequest, _ ...grpc.CallOption) (*emptypb.Empty, error) {
+	for _, dp := range deploymentPackages {
+		for _, app := range dp.ApplicationReferences {
+			if app.Name == in.ApplicationName && app.Version == in.Version {
+				return nil, status.Errorf(codes.FailedPrecondition, "application %s is referenced by %s", in.ApplicationName, dp.Name)
+			}
+		}
+	}
+	deletedApplications = append(deletedApplications, in.ApplicationName+":"+in.Version)
+	return &emptypb.Empty{}, nil
+}
+
+func NewTestCatalogClient(_ string, _ config.GRPCSettings) (CatalogClient, error) {
 	testClient := &testCatalogClient{}
 	return testClient, nil
 }
@@ -126,7 +182,7 @@ func (s *CatalogTestSuite) TestRegistryCreation() {
 	s.NoError(err)
 
 	// make new registry
-	err = cat.CreateOrUpdateRegistry(s.ctx, RegistryAttributes{Name: "r", RootURL: "https://root1"})
+	err = cat.CreateOrUpdateRegistry(s.ctx, RegistryAttributes{Name: "r", RootURL: "https://root1", Anonymous: true})
 	s.NoError(err)
 
 	// check it
@@ -135,7 +191,7 @@ func (s *CatalogTestSuite) TestRegistryCreation() {
 	s.Equal("https://root1", registries["r"].RootUrl)
 
 	// update registry
-	err = cat.CreateOrUpdateRegistry(s.ctx, RegistryAttributes{Name: "r", RootURL: "https://root2"})
+	err = cat.CreateOrUpdateRegistry(s.ctx, RegistryAttributes{Name: "r", RootURL: "https://root2", Anonymous: true})
 	s.NoError(err)
 
 	// check it
@@ -144,6 +200,114 @@ func (s *CatalogTestSuite) TestRegistryCreation() {
 	s.Equal("https://root2", registries["r"].RootUrl)
 }
 
+func (s *CatalogTestSuite) TestRegistryExists() {
+	var err error
+	cat, err := newCatalog(s.configuration)
+	s.NoError(err)
+
+	exists, err := cat.RegistryExists(s.ctx, "", "exists")
+	s.NoError(err)
+	s.False(exists)
+
+	err = cat.CreateOrUpdateRegistry(s.ctx, RegistryAttributes{Name: "exists", RootURL: "https://root1", Anonymous: true})
+	s.NoError(err)
+
+	exists, err = cat.RegistryExists(s.ctx, "", "exists")
+	s.NoError(err)
+	s.True(exists)
+}
+
+func (s *CatalogTestSuite) TestRegistryCredentialsUpdate() {
+	cat, err := newCatalog(s.configuration)
+	s.NoError(err)
+
+	err = cat.UpdateRegistryCredentials(s.ctx, "", "rotated", "user", "token")
+	s.ErrorIs(err, ErrNotFound)
+	s.ErrorIs(err, ErrPermanent)
+
+	err = cat.CreateOrUpdateRegistry(s.ctx, RegistryAttributes{Name: "rotated", RootURL: "oci://harbor", Cacerts: "ca",
+		Username: "old-user", AuthToken: "old-token"})
+	s.NoError(err)
+	s.NoError(cat.UpdateRegistryCredentials(s.ctx, "", "rotated", "new-user", "new-token"))
+
+	// Only the credentials change
+	s.Equal("new-user", registries["rotated"].Username)
+	s.Equal("new-token", registries["rotated"].AuthToken)
+	s.Equal("oci://harbor", registries["rotated"].RootUrl)
+	s.Equal("ca", registries["rotated"].Cacerts)
+
+	// Clearing the credentials keeps the registry and its other fields
+	s.NoError(cat.ClearRegistryCredentials(s.ctx, "", "rotated"))
+	s.Empty(registries["rotated"].Username)
+	s.Empty(registries["rotated"].AuthToken)
+	s.Equal("oci://harbor", registries["rotated"].RootUrl)
+	s.Equal("ca", registries["rotated"].Cacerts)
+	s.ErrorIs(cat.ClearRegistryCredentials(s.ctx, "", "missing"), ErrNotFound)
+}
+
+func (s *CatalogTestSuite) TestGetRegistry() {
+	cat, err := newCatalog(s.configuration)
+	s.NoError(err)
+	defer delete(registries, "read-back")
+
+	_, err = cat.GetRegistry(s.ctx, "uuid-1", "read-back")
+	s.ErrorIs(err, ErrNotFound)
+	s.ErrorIs(err, ErrPermanent)
+
+	err = cat.CreateOrUpdateRegistry(s.ctx, RegistryAttributes{Name: "read-back", Type: "HELM", RootURL: "oci://harbor",
+		Username: "user", AuthToken: "token", ProjectUUID: "uuid-1"})
+	s.NoError(err)
+	registry, err := cat.GetRegistry(s.ctx, "uuid-1", "read-back")
+	s.NoError(err)
+	s.Equal("HELM", registry.Type)
+	s.Equal("oci://harbor", registry.RootURL)
+	s.Equal("user", registry.Username)
+	s.Equal("token", registry.AuthToken)
+	s.Equal("uuid-1", registry.ProjectUUID)
+	s.False(registry.Anonymous)
+}
+
+func (s *CatalogTestSuite) TestRegistryCredentialsValidation() {
+	cat, err := newCatalog(s.configuration)
+	s.NoError(err)
+
+	for _, attrs := range []RegistryAttributes{
+		{Name: "no-credentials"},
+		{Name: "no-token", Username: "user"},
+		{Name: "no-username", AuthToken: "token"},
+		{Name: "anonymous-with-token", AuthToken: "token", Anonymous: true},
+	} {
+		err = cat.CreateOrUpdateRegistry(s.ctx, attrs)
+		s.ErrorIs(err, ErrPermanent, attrs.Name)
+		s.NotContains(registries, attrs.Name)
+	}
+	s.ErrorContains(RegistryAttributes{Name: "r", Username: "user"}.ValidateCredentials(), "registry r has a username but no auth token")
+	s.NoError(RegistryAttributes{Name: "r", Username: "user", AuthToken: "token"}.ValidateCredentials())
+	s.NoError(RegistryAttributes{Name: "r", Anonymous: true}.ValidateCredentials())
+
+	s.ErrorIs(cat.UpdateRegistryCredentials(s.ctx, "", "r", "user", ""), ErrPermanent)
+}
+
+func (s *CatalogTestSuite) TestDeploymentPackageApplications() {
+	cat, err := newCatalog(s.configuration)
+	s.NoError(err)
+
+	deploymentPackages["base-extensions:0.2.0"] = &catalogv3.DeploymentPackage{
+		Name:    "base-extensions",
+		Version: "0.2.0",
+		ApplicationReferences: []*catalogv3.ApplicationReference{
+			{Name: "network-policies", Version: "0.1.0"},
+			{Name: "ingress-nginx", Version: "0.2.0"},
+		},
+	}
+	apps, err := cat.DeploymentPackageApplications(s.ctx, "", "base-extensions", "0.2.0")
+	s.NoError(err)
+	s.Equal([]string{"network-policies", "ingress-nginx"}, apps)
+
+	_, err = cat.DeploymentPackageApplications(s.ctx, "", "base-extensions", "0.3.0")
+	s.ErrorIs(err, ErrPermanent)
+}
+
 func (s *CatalogTestSuite) TestRegistryList() {
 	var err error
 	cat, err := newCatalog(s.configuration)
@@ -171,6 +335,123 @@ func (s *CatalogTestSuite) TestYAMLUpload() {
 	s.Equal(true, uploads["file-name2"].LastUpload)
 }
 
+// sessionCatalogClient issues upload session IDs, failing the upload of failFile
+type sessionCatalogClient struct {
+	testCatalogClient
+	requests    []*catalogv3.UploadCatalogEntitiesRequest
+	nextSession int
+	failFile    string
+}
+
+func (c *sessionCatalogClient) UploadCatalogEntities(_ context.Context, in *catalogv3.UploadCatalogEntitiesRequest, _ ...grpc.CallOption) (*catalogv3.UploadCatalogEntitiesResponse, error) {
+	c.requests = append(c.requests, in)
+	if in.Upload.FileName == c.failFile {
+		return nil, status.Error(codes.Unavailable, "catalog is unavailable")
+	}
+	sessionID := in.SessionId
+	if sessionID == "" {
+		c.nextSession++
+		sessionID = fmt.Sprintf("session-%d", c.nextSession)
+	}
+	return &catalogv3.UploadCatalogEntitiesResponse{SessionId: sessionID}, nil
+}
+
+// deadlineCatalogClient records the deadline of the last upload
+type deadlineCatalogClient struct {
+	testCatalogClient
+	deadline    time.Time
+	hasDeadline bool
+}
+
+func (c *deadlineCatalogClient) UploadCatalogEntities(ctx context.Context, _ *catalogv3.UploadCatalogEntitiesRequest, _ ...grpc.CallOption) (*catalogv3.UploadCatalogEntitiesResponse, error) {
+	c.deadline, c.hasDeadline = ctx.Deadline()
+	return &catalogv3.UploadCatalogEntitiesResponse{}, nil
+}
+
+func (s *CatalogTestSuite) TestUploadTimeout() {
+	cat, err := newCatalog(s.configuration)
+	s.NoError(err)
+	client := &deadlineCatalogClient{}
+	cat.catalogClient = client
+
+	// Without an upload timeout the upload is only bounded by its context
+	cat.config.CatalogUploadTimeout = 0
+	s.NoError(cat.UploadYAMLFile(context.Background(), "project", "file-name", []byte("abc"), true))
+	s.False(client.hasDeadline)
+
+	cat.config.CatalogUploadTimeout = 5 * time.Minute
+	s.NoError(cat.UploadYAMLFile(context.Background(), "project", "file-name", []byte("abc"), true))
+	s.True(client.hasDeadline)
+	s.WithinDuration(time.Now().Add(5*time.Minute), client.deadline, time.Minute)
+}
+
+func (s *CatalogTestSuite) TestCommitUpload() {
+	cat, err := newCatalog(s.configuration)
+	s.NoError(err)
+	client := &sessionCatalogClient{failFile: "file-name2"}
+	cat.catalogClient = client
+
+	upload := &CatalogUpload{}
+	upload.Add("file-name1", []byte("abc"))
+	upload.Add("file-name2", []byte("def"))
+	upload.Add("file-name3", []byte("ghi"))
+	s.Len(upload.Files(), 3)
+
+	// The failed session is never completed, so the catalog loads none of its files
+	err = cat.CommitUpload(s.ctx, "project", upload)
+	s.ErrorIs(err, ErrTransient)
+	s.Len(client.requests, 2)
+	for _, request := range client.requests {
+		s.False(request.LastUpload)
+	}
+
+	// The upload is sent again in a new session
+	client.failFile = ""
+	client.requests = nil
+	s.NoError(cat.CommitUpload(s.ctx, "project", upload))
+	s.Len(client.requests, 3)
+	s.Equal("", client.requests[0].SessionId)
+	s.Equal("session-2", client.requests[1].SessionId)
+	s.Equal("session-2", client.requests[2].SessionId)
+	s.Equal([]bool{false, false, true}, []bool{client.requests[0].LastUpload, client.requests[1].LastUpload, client.requests[2].LastUpload})
+	s.Equal([]byte("ghi"), client.requests[2].Upload.Artifact)
+
+	// An empty upload sends nothing
+	client.requests = nil
+	s.NoError(cat.CommitUpload(s.ctx, "project", &CatalogUpload{}))
+	s.Empty(client.requests)
+}
+
+func (s *CatalogTestSuite) TestArtifactSizeLimit() {
+	cat, err := newCatalog(s.configuration)
+	s.NoError(err)
+	client := &sessionCatalogClient{}
+	cat.catalogClient = client
+	cat.config.MaxCatalogArtifactSize = 4
+
+	sizes := NewPayloadSizes("org")
+	ctx := WithPayloadSizes(s.ctx, sizes)
+	upload := &CatalogUpload{}
+	upload.Add("dir/file-name1", []byte("abc"))
+	upload.Add("dir/file-name2", []byte("too large"))
+
+	// A file over the limit fails the upload before anything is sent
+	err = cat.CommitUpload(ctx, "project", upload)
+	s.ErrorIs(err, ErrArtifactTooLarge)
+	s.ErrorIs(err, ErrPermanent)
+	s.Equal("Catalog file file-name2 is 9 bytes, larger than the maximum of 4 bytes", UserMessage(err))
+	s.Empty(client.requests)
+	s.ErrorIs(cat.UploadYAMLFile(ctx, "project", "file-name", []byte("too large"), true), ErrArtifactTooLarge)
+	s.Empty(client.requests)
+	s.Empty(sizes.Bytes())
+
+	// The sizes of the files sent are added to the payload totals
+	cat.config.MaxCatalogArtifactSize = 0
+	s.NoError(cat.CommitUpload(ctx, "project", upload))
+	s.Len(client.requests, 2)
+	s.Equal(map[string]int64{ServiceCatalog: 12}, sizes.Bytes())
+}
+
 func (s *CatalogTestSuite) TestSecret() {
 	var err error
 	cat, err := newCatalog(s.configuration)
@@ -180,3 +461,229 @@ func (s *CatalogTestSuite) TestSecret() {
 	s.NoError(err)
 	s.Equal("", secret)
 }
+
+// emptyCatalogClient lists no entities, so that a wipe only lists each kind of entity once
+type emptyCatalogClient struct {
+	catalogv3.CatalogServiceClient
+}
+
+func (c *emptyCatalogClient) ListDeploymentPackages(_ context.Context, _ *catalogv3.ListDeploymentPackagesRequest, _ ...grpc.CallOption) (*catalogv3.ListDeploymentPackagesResponse, error) {
+	return &catalogv3.ListDeploymentPackagesResponse{}, nil
+}
+
+func (c *emptyCatalogClient) ListApplications(_ context.Context, _ *catalogv3.ListApplicationsRequest, _ ...grpc.CallOption) (*catalogv3.ListApplicationsResponse, error) {
+	return &catalogv3.ListApplicationsResponse{}, nil
+}
+
+func (c *emptyCatalogClient) ListArtifacts(_ context.Context, _ *catalogv3.ListArtifactsRequest, _ ...grpc.CallOption) (*catalogv3.ListArtifactsResponse, error) {
+	return &catalogv3.ListArtifactsResponse{}, nil
+}
+
+func (c *emptyCatalogClient) ListRegistries(_ context.Context, _ *catalogv3.ListRegistriesRequest, _ ...grpc.CallOption) (*catalogv3.ListRegistriesResponse, error) {
+	return &catalogv3.ListRegistriesResponse{}, nil
+}
+
+func (s *CatalogTestSuite) TestWipeProgress() {
+	messages := []string{}
+	client := &wipeProgressClient{
+		CatalogServiceClient: &emptyCatalogClient{},
+		progress:             func(message string) { messages = append(messages, message) },
+	}
+	s.Empty(wiper.NewGRPCWiper(client).Wipe(s.ctx, "uuid-1"))
+	s.Equal([]string{
+		"Wiping catalog deployment packages 1/4",
+		"Wiping catalog applications 2/4",
+		"Wiping catalog artifacts 3/4",
+		"Wiping catalog registries 4/4",
+	}, messages)
+}
+
+func (s *CatalogTestSuite) TestGRPCErrorClassification() {
+	tests := []struct {
+		code  codes.Code
+		class error
+	}{
+		{codes.Unavailable, ErrTransient},
+		{codes.DeadlineExceeded, ErrTransient},
+		{codes.AlreadyExists, ErrConflict},
+		{codes.Aborted, ErrConflict},
+		{codes.Unauthenticated, ErrTransient},
+		{codes.InvalidArgument, ErrPermanent},
+		{codes.PermissionDenied, ErrPermanent},
+		{codes.Canceled, nil},
+	}
+	for _, tt := range tests {
+		err := grpcError(status.Error(tt.code, "registry harbor-helm-oci"))
+		s.Equal(tt.class, Classify(err), tt.code.String())
+		s.Equal(tt.class != ErrPermanent, IsRetryable(err), tt.code.String())
+		s.Contains(err.Error(), "registry harbor-helm-oci")
+		// The gRPC status is still available to callers
+		s.Equal(tt.code, status.Code(err))
+	}
+	s.NoError(grpcError(nil))
+	s.False(IsRetryable(nil))
+}
+
+func (s *CatalogTestSuite) TestGRPCThrottled() {
+	st, err := status.New(codes.ResourceExhausted, "too many registries requests").
+		WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(3 * time.Second)})
+	s.NoError(err)
+	err = grpcError(st.Err())
+	s.ErrorIs(err, ErrThrottled)
+	s.True(IsRetryable(err))
+	var throttled retry.Throttled
+	s.ErrorAs(err, &throttled)
+	s.Equal(3*time.Second, throttled.RetryAfter())
+
+	// Without retry info the server did not say how long to wait
+	s.ErrorAs(grpcError(status.Error(codes.ResourceExhausted, "quota")), &throttled)
+	s.Zero(throttled.RetryAfter())
+}
+
+func (s *CatalogTestSuite) TestProjectFiles() {
+	saved := deploymentPackages
+	deploymentPackages = map[string]*catalogv3.DeploymentPackage{
+		"base:1.0": {Name: "base", Version: "1.0",
+			ApplicationReferences: []*catalogv3.ApplicationReference{{Name: "shared", Version: "1.0"}, {Name: "base", Version: "1.0"}}},
+		"gpu:2.0": {Name: "gpu", Version: "2.0",
+			ApplicationReferences: []*catalogv3.ApplicationReference{{Name: "shared", Version: "1.0"}}},
+	}
+	defer func() {
+		deploymentPackages = saved
+		deletedApplications = nil
+	}()
+	cat, err := newCatalog(s.configuration)
+	s.NoError(err)
+
+	files, err := cat.ListProjectFiles(s.ctx, "uuid-1")
+	s.NoError(err)
+	s.Equal([]ProjectFile{
+		{Name: "base", Version: "1.0", Applications: []ProjectApplication{{Name: "shared", Version: "1.0"}, {Name: "base", Version: "1.0"}}},
+		{Name: "gpu", Version: "2.0", Applications: []ProjectApplication{{Name: "shared", Version: "1.0"}}},
+	}, files)
+
+	// Applications still referenced by another deployment package are kept
+	s.NoError(cat.DeleteProjectFile(s.ctx, "uuid-1", files[0]))
+	s.Equal([]string{"base:1.0"}, deletedApplications)
+	files, err = cat.ListProjectFiles(s.ctx, "uuid-1")
+	s.NoError(err)
+	s.Len(files, 1)
+
+	// Deleting a package twice is not an error
+	s.NoError(cat.DeleteProjectFile(s.ctx, "uuid-1", ProjectFile{Name: "base", Version: "1.0"}))
+}
+
+func (s *CatalogTestSuite) TestCheckAPI() {
+	catalog, err := newCatalog(s.configuration)
+	s.NoError(err)
+	s.NoError(catalog.CheckAPI(s.ctx))
+
+	// Refused calls are answered by the API
+	s.NoError(checkAPIResponse(ServiceCatalog, CatalogAPI, status.Error(codes.Unauthenticated, "no token")))
+	err = checkAPIResponse(ServiceCatalog, CatalogAPI, status.Error(codes.Unimplemented, "unknown service catalog.v3.CatalogService"))
+	s.ErrorIs(err, ErrPermanent)
+	s.ErrorContains(err, "catalog.v3.CatalogService")
+	s.ErrorIs(checkAPIResponse(ServiceADM, ADMAPI, status.Error(codes.Unavailable, "connection refused")), ErrTransient)
+}
+
+func (s *CatalogTestSuite) TestParseRetryAfter() {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	delay, ok := parseRetryAfter("120", now)
+	s.True(ok)
+	s.Equal(2*time.Minute, delay)
+	delay, ok = parseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now)
+	s.True(ok)
+	s.Equal(30*time.Second, delay)
+	_, ok = parseRetryAfter("", now)
+	s.False(ok)
+	_, ok = parseRetryAfter("soon", now)
+	s.False(ok)
+}
+
+// pagedRegistriesClient lists the given registries, one page of pageSize registries at a time
+type pagedRegistriesClient struct {
+	testCatalogClient
+	registries []*catalogv3.Registry
+	pageSize   int
+}
+
+func (c *pagedRegistriesClient) ListRegistries(_ context.Context, in *catalogv3.ListRegistriesRequest, _ ...grpc.CallOption) (*catalogv3.ListRegistriesResponse, error) {
+	start := min(int(in.Offset), len(c.registries))
+	end := min(start+c.pageSize, len(c.registries))
+	return &catalogv3.ListRegistriesResponse{Registries: c.registries[start:end], TotalElements: int32(len(c.registries))}, nil //nolint:gosec // Small test data
+}
+
+func (s *CatalogTestSuite) TestRegistryOwnerMarker() {
+	description := ownedDescription("Repo on registry harbor", "uuid-1")
+	s.Equal("Repo on registry harbor [owner: app-orch-tenant-controller/uuid-1]", description)
+	s.Equal("uuid-1", registryOwner(description))
+
+	// The marker is replaced rather than added again
+	s.Equal("Repo on registry harbor [owner: app-orch-tenant-controller/uuid-2]", ownedDescription(description, "uuid-2"))
+	s.Equal("[owner: app-orch-tenant-controller/uuid-1]", ownedDescription("", "uuid-1"))
+	s.Equal("Repo", ownedDescription("Repo", ""))
+	s.Empty(registryOwner("Repo on registry harbor"))
+
+	cat, err := newCatalog(s.configuration)
+	s.NoError(err)
+	s.NoError(cat.CreateOrUpdateRegistry(s.ctx, RegistryAttributes{Name: "owned", Description: "Repo", ProjectUUID: "uuid-1", Anonymous: true}))
+	s.Equal("uuid-1", registryOwner(registries["owned"].Description))
+}
+
+func (s *CatalogTestSuite) TestVerifyProjectOwnership() {
+	owned := func(name string, owner string) *catalogv3.Registry {
+		return &catalogv3.Registry{Name: name, Description: ownedDescription("Repo", owner)}
+	}
+	client := &pagedRegistriesClient{pageSize: 1}
+	cat, err := newCatalog(s.configuration)
+	s.NoError(err)
+	cat.catalogClient = client
+
+	// The marked registries, listed over several pages, match the inventory
+	client.registries = []*catalogv3.Registry{owned("helm", "uuid-1"), owned("docker", "uuid-1"), {Name: "user-added"}}
+	s.NoError(cat.VerifyProjectOwnership(s.ctx, "uuid-1", []string{"helm", "docker"}))
+	s.NoError(cat.VerifyProjectOwnership(s.ctx, "uuid-1", nil))
+
+	// The counts do not match the inventory
+	err = cat.VerifyProjectOwnership(s.ctx, "uuid-1", []string{"helm"})
+	s.ErrorIs(err, ErrPermanent)
+	s.ErrorContains(err, "2 registries are owned by the project, the inventory records 1")
+	err = cat.VerifyProjectOwnership(s.ctx, "uuid-1", []string{"helm", "docker", "images"})
+	s.ErrorIs(err, ErrPermanent)
+	s.ErrorContains(err, "missing: images")
+
+	// A registry of another project is never wiped, with or without an inventory
+	client.registries = append(client.registries, owned("other", "uuid-2"))
+	err = cat.VerifyProjectOwnership(s.ctx, "uuid-1", nil)
+	s.ErrorIs(err, ErrPermanent)
+	s.ErrorContains(err, "registry other is owned by project uuid-2")
+
+	// Registries created before the markers are verified by name
+	client.registries = []*catalogv3.Registry{{Name: "helm"}, {Name: "docker"}, {Name: "user-added"}}
+	s.NoError(cat.VerifyProjectOwnership(s.ctx, "uuid-1", []string{"helm", "docker"}))
+	s.ErrorIs(cat.VerifyProjectOwnership(s.ctx, "uuid-1", []string{"helm", "images"}), ErrPermanent)
+
+	s.ErrorIs(cat.VerifyProjectOwnership(s.ctx, "", nil), ErrPermanent)
+}
+
+func (s *CatalogTestSuite) TestListProjectRegistries() {
+	client := &pagedRegistriesClient{pageSize: 1}
+	cat, err := newCatalog(s.configuration)
+	s.NoError(err)
+	cat.catalogClient = client
+
+	client.registries = []*catalogv3.Registry{
+		{Name: "helm", Type: "HELM", RootUrl: "oci://harbor/helm", Username: "robot", AuthToken: "secret"},
+		{Name: "images", Type: "IMAGE", RootUrl: "oci://rs/images"},
+	}
+	registries, err := cat.ListProjectRegistries(s.ctx, "uuid-1")
+	s.NoError(err)
+	s.Len(registries, 2)
+	s.Equal(RegistryAttributes{Name: "helm", Type: "HELM", RootURL: "oci://harbor/helm", Username: "robot", ProjectUUID: "uuid-1"}, registries[0])
+	s.True(registries[1].Anonymous)
+
+	client.registries = nil
+	registries, err = cat.ListProjectRegistries(s.ctx, "uuid-1")
+	s.NoError(err)
+	s.Empty(registries)
+}
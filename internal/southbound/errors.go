@@ -0,0 +1,229 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+)
+
+// Southbound errors are classified so that callers can decide whether a failed operation is worth retrying.
+// Use errors.Is to test for a class; the message of the original error is preserved.
+var (
+	// ErrTransient marks failures that may succeed when retried, such as network errors and server overload
+	ErrTransient = errors.New("transient error")
+	// ErrPermanent marks failures that will fail again until the request or configuration is fixed
+	ErrPermanent = errors.New("permanent error")
+	// ErrConflict marks requests that conflict with the current state of a resource, usually a concurrent change
+	ErrConflict = errors.New("conflict")
+)
+
+// ErrThrottled marks failures of a server asking the controller to slow down, e.g. HTTP 429 Too Many Requests or
+// gRPC ResourceExhausted. Throttled errors are also transient, and implement retry.Throttled so that retries wait as
+// long as the server asked for.
+var ErrThrottled = errors.New("throttled")
+
+// ErrNotFound is wrapped by lookups of resources that do not exist, so that callers can tell absence from a failure
+// to look the resource up. It is classified as permanent.
+var ErrNotFound = errors.New("not found")
+
+type classifiedError struct {
+	class error
+	err   error
+}
+
+// Error returns the message of the original error, scrubbed of credentials as it may contain a response body
+func (e *classifiedError) Error() string {
+	return Scrub(e.err.Error())
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.class, e.err}
+}
+
+type throttledError struct {
+	retryAfter time.Duration
+	err        error
+}
+
+func (e *throttledError) Error() string {
+	return Scrub(e.err.Error())
+}
+
+func (e *throttledError) Unwrap() []error {
+	return []error{ErrThrottled, ErrTransient, e.err}
+}
+
+// RetryAfter returns how long the server asked the controller to wait, 0 if it did not say
+func (e *throttledError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+func throttled(retryAfter time.Duration, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &throttledError{retryAfter: retryAfter, err: err}
+}
+
+// Classify returns the class of the error: ErrTransient, ErrPermanent, ErrConflict, or nil if the error was not
+// classified.
+func Classify(err error) error {
+	for _, class := range []error{ErrPermanent, ErrConflict, ErrTransient} {
+		if errors.Is(err, class) {
+			return class
+		}
+	}
+	return nil
+}
+
+// IsRetryable returns false for errors that will not succeed when retried. Errors that are not classified are
+// assumed to be retryable.
+func IsRetryable(err error) bool {
+	return err != nil && !errors.Is(err, ErrPermanent)
+}
+
+func classify(class error, err error) error {
+	if err == nil || Classify(err) != nil {
+		return err
+	}
+	return &classifiedError{class: class, err: err}
+}
+
+func httpStatusClass(statusCode int) error {
+	switch {
+	case statusCode == http.StatusConflict:
+		return ErrConflict
+	case statusCode == http.StatusRequestTimeout, statusCode == http.StatusTooManyRequests, statusCode >= http.StatusInternalServerError:
+		return ErrTransient
+	case statusCode >= http.StatusBadRequest:
+		return ErrPermanent
+	default:
+		// an unexpected success or redirect status, most likely from a proxy in front of the server
+		return ErrTransient
+	}
+}
+
+// httpError classifies an error returned for an unexpected HTTP response status.
+func httpError(statusCode int, err error) error {
+	return classify(httpStatusClass(statusCode), err)
+}
+
+// httpResponseError classifies an error returned for an unexpected HTTP response. Too Many Requests, and Service
+// Unavailable with a Retry-After header, are throttled for the time given by the header.
+func httpResponseError(statusCode int, header http.Header, err error) error {
+	retryAfter, ok := parseRetryAfter(header.Get("Retry-After"), time.Now())
+	if statusCode == http.StatusTooManyRequests || (statusCode == http.StatusServiceUnavailable && ok) {
+		return throttled(retryAfter, err)
+	}
+	return httpError(statusCode, err)
+}
+
+// parseRetryAfter parses a Retry-After header, either a number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// requestError classifies an error from sending an HTTP request. Cancellation is left unclassified, as the
+// caller gave up on the request.
+func requestError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+	return classify(ErrTransient, err)
+}
+
+// grpcError classifies an error returned by a gRPC call according to its status code. Unauthenticated calls are
+// transient: the M2M token expired or was revoked before its expiry time, grpcTokenInterceptor drops it from the
+// token source of the client and the next attempt is made with a new token.
+func grpcError(err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	switch s.Code() {
+	case codes.ResourceExhausted:
+		return throttled(grpcRetryDelay(s), err)
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown:
+		return classify(ErrTransient, err)
+	case codes.Unauthenticated:
+		return classify(ErrTransient, err)
+	case codes.AlreadyExists, codes.Aborted:
+		return classify(ErrConflict, err)
+	case codes.InvalidArgument, codes.NotFound, codes.PermissionDenied,
+		codes.FailedPrecondition, codes.OutOfRange, codes.Unimplemented:
+		return classify(ErrPermanent, err)
+	default:
+		return err
+	}
+}
+
+// grpcRetryDelay returns the delay of the RetryInfo detail of a status, 0 if it has none.
+func grpcRetryDelay(s *status.Status) time.Duration {
+	for _, detail := range s.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+			return info.GetRetryDelay().AsDuration()
+		}
+	}
+	return 0
+}
+
+// orasError classifies an error from pulling an artifact with ORAS.
+func orasError(err error) error {
+	var response *errcode.ErrorResponse
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &response):
+		return httpResponseError(response.StatusCode, http.Header{}, err)
+	case errors.Is(err, errdef.ErrNotFound), errors.Is(err, errdef.ErrInvalidReference),
+		errors.Is(err, auth.ErrBasicCredentialNotFound):
+		return classify(ErrPermanent, err)
+	case errors.Is(err, context.Canceled):
+		return err
+	default:
+		return classify(ErrTransient, err)
+	}
+}
+
+// k8sError classifies an error returned by the Kubernetes API server.
+func k8sError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case apierrors.IsConflict(err), apierrors.IsAlreadyExists(err):
+		return classify(ErrConflict, err)
+	case apierrors.IsServerTimeout(err), apierrors.IsTimeout(err), apierrors.IsTooManyRequests(err),
+		apierrors.IsServiceUnavailable(err), apierrors.IsInternalError(err):
+		return classify(ErrTransient, err)
+	case apierrors.IsNotFound(err), apierrors.IsForbidden(err), apierrors.IsUnauthorized(err), apierrors.IsInvalid(err),
+		apierrors.IsBadRequest(err):
+		return classify(ErrPermanent, err)
+	case errors.Is(err, context.Canceled):
+		return err
+	default:
+		return classify(ErrTransient, err)
+	}
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"context"
+
+	coreV1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	coreV1Types "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// GitOpsSecretPrefix is followed by the project UUID in the name of the deploy key secret of a project
+	GitOpsSecretPrefix = "tenant-gitops-"
+	// GitOpsLabel is set on all deploy key secrets, so that they can be listed
+	GitOpsLabel = "app-orch-tenant-controller/gitops"
+
+	// Keys of the deploy key secret, named as expected by Flux Git repository sources
+	GitOpsIdentityKey   = "identity"
+	GitOpsPublicKeyKey  = "identity.pub"
+	GitOpsRepositoryKey = "repository"
+)
+
+// GitOpsCredentials are the GitOps repository of a project and the deploy key giving access to it
+type GitOpsCredentials struct {
+	// SSH URL of the repository
+	Repository string
+	// OpenSSH private key
+	PrivateKey []byte
+	// public key in authorized_keys format
+	PublicKey string
+}
+
+// GitOpsSecretStore keeps the deploy key of each project in a secret named after the project UUID.
+type GitOpsSecretStore struct {
+	secrets coreV1Types.SecretInterface
+}
+
+// NewGitOpsSecretStore creates a store for the secrets of the namespace.
+func NewGitOpsSecretStore(config *rest.Config, namespace string) (*GitOpsSecretStore, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return newGitOpsSecretStore(clientset.CoreV1().Secrets(namespace)), nil
+}
+
+func newGitOpsSecretStore(secrets coreV1Types.SecretInterface) *GitOpsSecretStore {
+	return &GitOpsSecretStore{secrets: secrets}
+}
+
+// Save creates or replaces the deploy key secret of the project.
+func (s *GitOpsSecretStore) Save(ctx context.Context, uuid string, organization string, project string, credentials GitOpsCredentials) error {
+	name := GitOpsSecretPrefix + uuid
+	secret, err := s.secrets.Get(ctx, name, metaV1.GetOptions{})
+	exists := err == nil
+	if apierrors.IsNotFound(err) {
+		secret = &coreV1.Secret{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{GitOpsLabel: "true"},
+			},
+			Type: coreV1.SecretTypeOpaque,
+		}
+	} else if err != nil {
+		return k8sError(err)
+	}
+	secret.Annotations = map[string]string{
+		"organization": organization,
+		"project":      project,
+	}
+	secret.Data = map[string][]byte{
+		GitOpsIdentityKey:   credentials.PrivateKey,
+		GitOpsPublicKeyKey:  []byte(credentials.PublicKey),
+		GitOpsRepositoryKey: []byte(credentials.Repository),
+	}
+	if exists {
+		_, err = s.secrets.Update(ctx, secret, metaV1.UpdateOptions{})
+	} else {
+		_, err = s.secrets.Create(ctx, secret, metaV1.CreateOptions{})
+	}
+	return k8sError(err)
+}
+
+// Load returns the deploy key of the project, or nil if the project has none.
+func (s *GitOpsSecretStore) Load(ctx context.Context, uuid string) (*GitOpsCredentials, error) {
+	secret, err := s.secrets.Get(ctx, GitOpsSecretPrefix+uuid, metaV1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, k8sError(err)
+	}
+	return &GitOpsCredentials{
+		Repository: string(secret.Data[GitOpsRepositoryKey]),
+		PrivateKey: secret.Data[GitOpsIdentityKey],
+		PublicKey:  string(secret.Data[GitOpsPublicKeyKey]),
+	}, nil
+}
+
+// Delete removes the deploy key secret of the project. A missing secret is not an error.
+func (s *GitOpsSecretStore) Delete(ctx context.Context, uuid string) error {
+	err := s.secrets.Delete(ctx, GitOpsSecretPrefix+uuid, metaV1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return k8sError(err)
+}
@@ -0,0 +1,303 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// time allowed for a single call to the Git server
+	gitRequestTimeout = 60 * time.Second
+	// largest Git server response body read
+	maxGitResponseSize = 8 << 20
+)
+
+// GitRepository is a repository on the GitOps server
+type GitRepository struct {
+	ID   int
+	Name string
+	// web page of the repository
+	URL      string
+	CloneURL string
+	SSHURL   string
+}
+
+// GitDeployKey is an SSH key given access to a single repository
+type GitDeployKey struct {
+	ID       int
+	Title    string
+	Key      string
+	ReadOnly bool
+}
+
+// GitServer creates the GitOps repositories of the projects in the organization (Gitea) or group (GitLab) of the
+// configuration, through the REST API of the provider.
+type GitServer struct {
+	provider string
+	server   string
+	owner    string
+	token    string
+	client   *http.Client
+}
+
+// NewGitServer returns a client of the Git server authenticating with the API token.
+func NewGitServer(gitOps config.GitOps, token string) *GitServer {
+	return &GitServer{
+		provider: gitOps.Provider,
+		server:   strings.TrimSuffix(gitOps.Server, "/"),
+		owner:    gitOps.Owner,
+		token:    strings.TrimSpace(token),
+		client:   &http.Client{},
+	}
+}
+
+// repositoryEndpoint returns the API endpoint of a repository of the owner.
+func (g *GitServer) repositoryEndpoint(name string) string {
+	if g.provider == config.GitOpsProviderGitLab {
+		return g.server + "/api/v4/projects/" + url.PathEscape(g.owner+"/"+name)
+	}
+	return g.server + "/api/v1/repos/" + url.PathEscape(g.owner) + "/" + url.PathEscape(name)
+}
+
+// deployKeysEndpoint returns the API endpoint of the deploy keys of a repository.
+func (g *GitServer) deployKeysEndpoint(name string) string {
+	if g.provider == config.GitOpsProviderGitLab {
+		return g.repositoryEndpoint(name) + "/deploy_keys"
+	}
+	return g.repositoryEndpoint(name) + "/keys"
+}
+
+// doGitREST sends a JSON request and decodes the JSON response into result, if not nil. A 404 response wraps
+// ErrNotFound.
+func (g *GitServer) doGitREST(ctx context.Context, method string, endpoint string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		document, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(document)
+	}
+	callCtx, cancel := withCallTimeout(ctx, gitRequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(callCtx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	if g.provider == config.GitOpsProviderGitLab {
+		req.Header.Set("PRIVATE-TOKEN", g.token)
+	} else {
+		req.Header.Set("Authorization", "token "+g.token)
+	}
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("accept", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return requestError(ctx, err)
+	}
+	defer resp.Body.Close()
+	responseBody, err := io.ReadAll(io.LimitReader(resp.Body, maxGitResponseSize))
+	if err != nil {
+		return requestError(ctx, err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return classify(ErrPermanent, fmt.Errorf("%s %s %w: %s", method, req.URL.Redacted(), ErrNotFound, string(responseBody)))
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return httpResponseError(resp.StatusCode, resp.Header, fmt.Errorf("%s %s returned %s: %s", method,
+			req.URL.Redacted(), resp.Status, string(responseBody)))
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(responseBody, result); err != nil {
+		return classify(ErrPermanent, fmt.Errorf("invalid response to %s %s: %w", method, req.URL.Redacted(), err))
+	}
+	return nil
+}
+
+// gitRepositoryResponse holds the fields of both the Gitea repository and the GitLab project responses
+type gitRepositoryResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Gitea
+	HTMLURL  string `json:"html_url"`
+	CloneURL string `json:"clone_url"`
+	SSHURL   string `json:"ssh_url"`
+	// GitLab
+	WebURL        string `json:"web_url"`
+	HTTPURLToRepo string `json:"http_url_to_repo"`
+	SSHURLToRepo  string `json:"ssh_url_to_repo"`
+}
+
+func (r gitRepositoryResponse) repository() *GitRepository {
+	repository := &GitRepository{ID: r.ID, Name: r.Name, URL: r.HTMLURL, CloneURL: r.CloneURL, SSHURL: r.SSHURL}
+	if r.WebURL != "" {
+		repository.URL, repository.CloneURL, repository.SSHURL = r.WebURL, r.HTTPURLToRepo, r.SSHURLToRepo
+	}
+	return repository
+}
+
+// GetRepository returns a repository of the owner. The error wraps ErrNotFound if the repository does not exist.
+func (g *GitServer) GetRepository(ctx context.Context, name string) (*GitRepository, error) {
+	response := gitRepositoryResponse{}
+	if err := g.doGitREST(ctx, http.MethodGet, g.repositoryEndpoint(name), nil, &response); err != nil {
+		return nil, err
+	}
+	return response.repository(), nil
+}
+
+// CreateRepository creates a private repository in the owner, initialized with a README so that it can be cloned.
+func (g *GitServer) CreateRepository(ctx context.Context, name string, description string) (*GitRepository, error) {
+	log.Infof("Creating %s repository %s/%s", g.provider, g.owner, name)
+	response := gitRepositoryResponse{}
+	if g.provider == config.GitOpsProviderGitLab {
+		namespace := struct {
+			ID int `json:"id"`
+		}{}
+		if err := g.doGitREST(ctx, http.MethodGet, g.server+"/api/v4/namespaces/"+url.PathEscape(g.owner), nil, &namespace); err != nil {
+			return nil, err
+		}
+		body := map[string]interface{}{
+			"name":                   name,
+			"path":                   name,
+			"namespace_id":           namespace.ID,
+			"description":            description,
+			"visibility":             "private",
+			"initialize_with_readme": true,
+		}
+		if err := g.doGitREST(ctx, http.MethodPost, g.server+"/api/v4/projects", body, &response); err != nil {
+			return nil, err
+		}
+		return response.repository(), nil
+	}
+	body := map[string]interface{}{
+		"name":        name,
+		"description": description,
+		"private":     true,
+		"auto_init":   true,
+	}
+	if err := g.doGitREST(ctx, http.MethodPost, g.server+"/api/v1/orgs/"+url.PathEscape(g.owner)+"/repos", body, &response); err != nil {
+		return nil, err
+	}
+	return response.repository(), nil
+}
+
+// DeleteRepository deletes a repository of the owner with its deploy keys. Deleting a repository that does not
+// exist is not an error.
+func (g *GitServer) DeleteRepository(ctx context.Context, name string) error {
+	log.Infof("Deleting %s repository %s/%s", g.provider, g.owner, name)
+	err := g.doGitREST(ctx, http.MethodDelete, g.repositoryEndpoint(name), nil, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// gitDeployKeyResponse holds the fields of both the Gitea and the GitLab deploy key responses
+type gitDeployKeyResponse struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Key   string `json:"key"`
+	// Gitea
+	ReadOnly bool `json:"read_only"`
+	// GitLab
+	CanPush *bool `json:"can_push"`
+}
+
+func (r gitDeployKeyResponse) deployKey() GitDeployKey {
+	key := GitDeployKey{ID: r.ID, Title: r.Title, Key: r.Key, ReadOnly: r.ReadOnly}
+	if r.CanPush != nil {
+		key.ReadOnly = !*r.CanPush
+	}
+	return key
+}
+
+// ListDeployKeys returns the deploy keys of a repository.
+func (g *GitServer) ListDeployKeys(ctx context.Context, name string) ([]GitDeployKey, error) {
+	var response []gitDeployKeyResponse
+	if err := g.doGitREST(ctx, http.MethodGet, g.deployKeysEndpoint(name), nil, &response); err != nil {
+		return nil, err
+	}
+	keys := make([]GitDeployKey, 0, len(response))
+	for _, key := range response {
+		keys = append(keys, key.deployKey())
+	}
+	return keys, nil
+}
+
+// AddDeployKey gives an SSH public key access to a repository.
+func (g *GitServer) AddDeployKey(ctx context.Context, name string, title string, publicKey string, readOnly bool) (*GitDeployKey, error) {
+	log.Infof("Adding deploy key %s to %s repository %s/%s, read only %v", title, g.provider, g.owner, name, readOnly)
+	body := map[string]interface{}{
+		"title": title,
+		"key":   publicKey,
+	}
+	if g.provider == config.GitOpsProviderGitLab {
+		body["can_push"] = !readOnly
+	} else {
+		body["read_only"] = readOnly
+	}
+	response := gitDeployKeyResponse{}
+	if err := g.doGitREST(ctx, http.MethodPost, g.deployKeysEndpoint(name), body, &response); err != nil {
+		return nil, err
+	}
+	key := response.deployKey()
+	return &key, nil
+}
+
+// DeleteDeployKey removes a deploy key from a repository. Deleting a key that does not exist is not an error.
+func (g *GitServer) DeleteDeployKey(ctx context.Context, name string, id int) error {
+	log.Infof("Deleting deploy key %d of %s repository %s/%s", id, g.provider, g.owner, name)
+	err := g.doGitREST(ctx, http.MethodDelete, g.deployKeysEndpoint(name)+"/"+strconv.Itoa(id), nil, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// GenerateDeployKey returns a new ed25519 SSH key pair: the public key in authorized_keys format with the comment,
+// and the private key in OpenSSH PEM format.
+func GenerateDeployKey(comment string) (string, []byte, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", nil, err
+	}
+	sshPublic, err := ssh.NewPublicKey(public)
+	if err != nil {
+		return "", nil, err
+	}
+	block, err := ssh.MarshalPrivateKey(private, comment)
+	if err != nil {
+		return "", nil, err
+	}
+	publicKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPublic))) + " " + comment
+	return publicKey, pem.EncodeToMemory(block), nil
+}
+
+// SameDeployKey returns true if two public keys in authorized_keys format are the same key, whatever their
+// comments. Git servers may drop or replace the comment of a deploy key.
+func SameDeployKey(a string, b string) bool {
+	fieldsA, fieldsB := strings.Fields(a), strings.Fields(b)
+	return len(fieldsA) >= 2 && len(fieldsB) >= 2 && fieldsA[0] == fieldsB[0] && fieldsA[1] == fieldsB[1]
+}
@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package southbound
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/ssh"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Suite of GitOps southbound tests
+type GitOpsTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *GitOpsTestSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 1*time.Minute)
+}
+
+func (s *GitOpsTestSuite) TearDownTest() {
+	s.cancel()
+}
+
+func TestGitOps(t *testing.T) {
+	suite.Run(t, &GitOpsTestSuite{})
+}
+
+// gitRequest is a request received by the test Git server
+type gitRequest struct {
+	method string
+	path   string
+	auth   string
+	body   map[string]interface{}
+}
+
+// newGitServer starts a Git server answering each request with the response of its method and escaped path. Paths
+// without a response are not found.
+func (s *GitOpsTestSuite) newGitServer(responses map[string]string) (*httptest.Server, *[]gitRequest) {
+	requests := &[]gitRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := gitRequest{method: r.Method, path: r.URL.EscapedPath(), auth: r.Header.Get("Authorization") + r.Header.Get("PRIVATE-TOKEN")}
+		_ = json.NewDecoder(r.Body).Decode(&request.body)
+		*requests = append(*requests, request)
+		response, ok := responses[r.Method+" "+r.URL.EscapedPath()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+			return
+		}
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	s.T().Cleanup(server.Close)
+	return server, requests
+}
+
+func (s *GitOpsTestSuite) TestGitea() {
+	server, requests := s.newGitServer(map[string]string{
+		"POST /api/v1/orgs/tenants/repos": `{"id":7,"name":"org-proj","html_url":"https://git/tenants/org-proj",` +
+			`"clone_url":"https://git/tenants/org-proj.git","ssh_url":"git@git:tenants/org-proj.git"}`,
+		"GET /api/v1/repos/tenants/org-proj/keys":  `[{"id":3,"title":"app-orch-tenant-controller","key":"ssh-ed25519 AAAA","read_only":true}]`,
+		"POST /api/v1/repos/tenants/org-proj/keys": `{"id":4,"title":"app-orch-tenant-controller","key":"ssh-ed25519 BBBB","read_only":true}`,
+		"DELETE /api/v1/repos/tenants/org-proj":    ``,
+	})
+	git := NewGitServer(config.GitOps{Provider: config.GitOpsProviderGitea, Server: server.URL + "/", Owner: "tenants"}, "secret\n")
+
+	_, err := git.GetRepository(s.ctx, "org-proj")
+	s.True(errors.Is(err, ErrNotFound))
+	s.True(errors.Is(err, ErrPermanent))
+
+	repository, err := git.CreateRepository(s.ctx, "org-proj", "configuration")
+	s.NoError(err)
+	s.Equal(&GitRepository{ID: 7, Name: "org-proj", URL: "https://git/tenants/org-proj",
+		CloneURL: "https://git/tenants/org-proj.git", SSHURL: "git@git:tenants/org-proj.git"}, repository)
+	create := (*requests)[1]
+	s.Equal("token secret", create.auth)
+	s.Equal(map[string]interface{}{"name": "org-proj", "description": "configuration", "private": true, "auto_init": true}, create.body)
+
+	keys, err := git.ListDeployKeys(s.ctx, "org-proj")
+	s.NoError(err)
+	s.Equal([]GitDeployKey{{ID: 3, Title: "app-orch-tenant-controller", Key: "ssh-ed25519 AAAA", ReadOnly: true}}, keys)
+
+	key, err := git.AddDeployKey(s.ctx, "org-proj", "app-orch-tenant-controller", "ssh-ed25519 BBBB", true)
+	s.NoError(err)
+	s.Equal(4, key.ID)
+	s.Equal(true, (*requests)[len(*requests)-1].body["read_only"])
+
+	// Deleting what is already gone is not an error
+	s.NoError(git.DeleteDeployKey(s.ctx, "org-proj", 3))
+	s.NoError(git.DeleteRepository(s.ctx, "org-proj"))
+	s.NoError(git.DeleteRepository(s.ctx, "other"))
+}
+
+func (s *GitOpsTestSuite) TestGitLab() {
+	server, requests := s.newGitServer(map[string]string{
+		"GET /api/v4/namespaces/tenants": `{"id":12}`,
+		"POST /api/v4/projects": `{"id":7,"name":"org-proj","web_url":"https://git/tenants/org-proj",` +
+			`"http_url_to_repo":"https://git/tenants/org-proj.git","ssh_url_to_repo":"git@git:tenants/org-proj.git"}`,
+		"GET /api/v4/projects/tenants%2Forg-proj":              `{"id":7,"name":"org-proj","web_url":"https://git/tenants/org-proj"}`,
+		"GET /api/v4/projects/tenants%2Forg-proj/deploy_keys":  `[{"id":3,"title":"app-orch-tenant-controller","key":"ssh-ed25519 AAAA","can_push":true}]`,
+		"POST /api/v4/projects/tenants%2Forg-proj/deploy_keys": `{"id":4,"title":"app-orch-tenant-controller","key":"ssh-ed25519 BBBB","can_push":false}`,
+	})
+	git := NewGitServer(config.GitOps{Provider: config.GitOpsProviderGitLab, Server: server.URL, Owner: "tenants"}, "secret")
+
+	repository, err := git.CreateRepository(s.ctx, "org-proj", "configuration")
+	s.NoError(err)
+	s.Equal("git@git:tenants/org-proj.git", repository.SSHURL)
+	s.Equal("https://git/tenants/org-proj.git", repository.CloneURL)
+	create := (*requests)[1]
+	s.Equal("secret", create.auth)
+	s.Equal(float64(12), create.body["namespace_id"])
+	s.Equal("private", create.body["visibility"])
+
+	repository, err = git.GetRepository(s.ctx, "org-proj")
+	s.NoError(err)
+	s.Equal(7, repository.ID)
+
+	keys, err := git.ListDeployKeys(s.ctx, "org-proj")
+	s.NoError(err)
+	s.Equal([]GitDeployKey{{ID: 3, Title: "app-orch-tenant-controller", Key: "ssh-ed25519 AAAA", ReadOnly: false}}, keys)
+
+	key, err := git.AddDeployKey(s.ctx, "org-proj", "app-orch-tenant-controller", "ssh-ed25519 BBBB", true)
+	s.NoError(err)
+	s.True(key.ReadOnly)
+	s.Equal(false, (*requests)[len(*requests)-1].body["can_push"])
+}
+
+func (s *GitOpsTestSuite) TestGitServerErrors() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/keys") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	git := NewGitServer(config.GitOps{Provider: config.GitOpsProviderGitea, Server: server.URL, Owner: "tenants"}, "secret")
+
+	_, err := git.CreateRepository(s.ctx, "org-proj", "configuration")
+	s.True(errors.Is(err, ErrPermanent))
+	s.False(errors.Is(err, ErrNotFound))
+
+	_, err = git.ListDeployKeys(s.ctx, "org-proj")
+	s.True(errors.Is(err, ErrTransient))
+}
+
+func (s *GitOpsTestSuite) TestGenerateDeployKey() {
+	publicKey, privateKey, err := GenerateDeployKey("app-orch-tenant-controller@org-proj")
+	s.NoError(err)
+	s.True(strings.HasPrefix(publicKey, "ssh-ed25519 "))
+	s.True(strings.HasSuffix(publicKey, " app-orch-tenant-controller@org-proj"))
+
+	signer, err := ssh.ParsePrivateKey(privateKey)
+	s.NoError(err)
+	s.True(SameDeployKey(publicKey, string(ssh.MarshalAuthorizedKey(signer.PublicKey()))))
+
+	otherKey, _, err := GenerateDeployKey("app-orch-tenant-controller@org-proj")
+	s.NoError(err)
+	s.False(SameDeployKey(publicKey, otherKey))
+	s.False(SameDeployKey(publicKey, ""))
+}
+
+func (s *GitOpsTestSuite) TestSecretStore() {
+	store := newGitOpsSecretStore(fake.NewClientset().CoreV1().Secrets("orch-app"))
+
+	credentials, err := store.Load(s.ctx, "uuid-1")
+	s.NoError(err)
+	s.Nil(credentials)
+
+	saved := GitOpsCredentials{Repository: "git@git:tenants/org-proj.git", PrivateKey: []byte("private"), PublicKey: "ssh-ed25519 AAAA"}
+	s.NoError(store.Save(s.ctx, "uuid-1", "org", "proj", saved))
+	credentials, err = store.Load(s.ctx, "uuid-1")
+	s.NoError(err)
+	s.Equal(&saved, credentials)
+
+	saved.PublicKey = "ssh-ed25519 BBBB"
+	s.NoError(store.Save(s.ctx, "uuid-1", "org", "proj", saved))
+	credentials, err = store.Load(s.ctx, "uuid-1")
+	s.NoError(err)
+	s.Equal("ssh-ed25519 BBBB", credentials.PublicKey)
+
+	s.NoError(store.Delete(s.ctx, "uuid-1"))
+	s.NoError(store.Delete(s.ctx, "uuid-1"))
+	credentials, err = store.Load(s.ctx, "uuid-1")
+	s.NoError(err)
+	s.Nil(credentials)
+}
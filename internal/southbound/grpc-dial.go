@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"context"
+	"path"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/orch-library/go/pkg/grpc/retry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+)
+
+// grpcDialOptions returns the options of the gRPC connection to a service. Calls that fail as Unavailable or Unknown
+// are retried with backoff, after a single immediate retry if the connection was reset, and every attempt is counted
+// in the metrics. A call rejected as Unauthenticated drops its M2M token. Keepalive pings detect connections that
+// went stale behind a load balancer, so that a call on one fails instead of hanging until the TCP timeout.
+func grpcDialOptions(service string, settings config.GRPCSettings) []grpc.DialOption {
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStreamInterceptor(retry.RetryingStreamClientInterceptor(retry.WithRetryOn(codes.Unavailable, codes.Unknown))),
+		grpc.WithChainUnaryInterceptor(
+			grpcRetryStateInterceptor(),
+			retry.RetryingUnaryClientInterceptor(retry.WithRetryOn(codes.Unavailable, codes.Unknown)),
+			grpcFastRetryInterceptor(service),
+			grpcMetricsInterceptor(service),
+			grpcRedialInterceptor(),
+			grpcTokenInterceptor(),
+		),
+	}
+	if settings.KeepaliveTime > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                settings.KeepaliveTime,
+			Timeout:             settings.KeepaliveTimeout,
+			PermitWithoutStream: settings.KeepalivePermitWithoutStream,
+		}))
+	}
+	if settings.BackoffBaseDelay > 0 && settings.BackoffMaxDelay > 0 {
+		connectBackoff := backoff.DefaultConfig
+		connectBackoff.BaseDelay = settings.BackoffBaseDelay
+		connectBackoff.MaxDelay = settings.BackoffMaxDelay
+		opts = append(opts, grpc.WithConnectParams(grpc.ConnectParams{Backoff: connectBackoff}))
+	}
+	return opts
+}
+
+// grpcRedialInterceptor reconnects right away when a call fails as Unavailable, instead of waiting for the
+// connection backoff, so that the retry of the call is made on a new connection.
+func grpcRedialInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if status.Code(err) == codes.Unavailable {
+			log.Infof("%s is unavailable, reconnecting", cc.Target())
+			cc.ResetConnectBackoff()
+			cc.Connect()
+		}
+		return err
+	}
+}
+
+// grpcTokenInterceptor drops the M2M token of a call rejected as Unauthenticated from the token source it was taken
+// from, so that the next call of the client fetches a new token. The tokens of the other clients are kept.
+func grpcTokenInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if status.Code(err) == codes.Unauthenticated {
+			if call, ok := ctx.Value(callTokenKey{}).(callToken); ok {
+				log.Infof("%s rejected the M2M token, fetching a new one for the next call", path.Base(method))
+				call.source.Reject(call.token)
+			}
+		}
+		return err
+	}
+}
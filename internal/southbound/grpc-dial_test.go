@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package southbound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/orch-library/go/pkg/grpc/retry"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Suite of gRPC connection tests
+type GRPCDialTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *GRPCDialTestSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 1*time.Minute)
+}
+
+func (s *GRPCDialTestSuite) TearDownTest() {
+	s.cancel()
+}
+
+func TestGRPCDial(t *testing.T) {
+	suite.Run(t, &GRPCDialTestSuite{})
+}
+
+// serveHealth starts a gRPC server with the health service on the address
+func (s *GRPCDialTestSuite) serveHealth(address string) *grpc.Server {
+	listener, err := net.Listen("tcp", address)
+	s.Require().NoError(err)
+	server := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(listener) }()
+	return server
+}
+
+func (s *GRPCDialTestSuite) TestDialOptions() {
+	s.Len(grpcDialOptions(ServiceCatalog, config.GRPCSettings{}), 3)
+	s.Len(grpcDialOptions(ServiceCatalog, config.GRPCSettings{
+		KeepaliveTime:    time.Minute,
+		KeepaliveTimeout: 20 * time.Second,
+		BackoffBaseDelay: time.Second,
+		BackoffMaxDelay:  30 * time.Second,
+	}), 5)
+}
+
+func (s *GRPCDialTestSuite) TestRedialOnUnavailable() {
+	// Reserve an address with no server behind it
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	address := listener.Addr().String()
+	s.NoError(listener.Close())
+
+	// The connection backoff is longer than the test, so only redialing lets the client reach the server
+	conn, err := grpc.NewClient(address, grpcDialOptions(ServiceCatalog, config.GRPCSettings{
+		KeepaliveTime:    10 * time.Second,
+		KeepaliveTimeout: time.Second,
+		BackoffBaseDelay: 2 * time.Minute,
+		BackoffMaxDelay:  2 * time.Minute,
+	})...)
+	s.Require().NoError(err)
+	defer func() { _ = conn.Close() }()
+	client := grpc_health_v1.NewHealthClient(conn)
+
+	ctx, cancel := context.WithTimeout(s.ctx, time.Second)
+	_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	cancel()
+	s.Error(err)
+
+	server := s.serveHealth(address)
+	defer server.Stop()
+	ctx, cancel = context.WithTimeout(s.ctx, 20*time.Second)
+	defer cancel()
+	response, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	s.NoError(err)
+	s.Equal(grpc_health_v1.HealthCheckResponse_SERVING, response.GetStatus())
+}
+
+func (s *GRPCDialTestSuite) TestUnauthenticatedDropsToken() {
+	newSource := func(name string) (*TokenSource, *int) {
+		fetches := 0
+		return NewTokenSource(func(_ context.Context) (string, error) {
+			fetches++
+			return fmt.Sprintf("%s-%d", name, fetches), nil
+		}), &fetches
+	}
+	catalogTokens, catalogFetches := newSource("catalog")
+	admTokens, admFetches := newSource("adm")
+	_, err := admTokens.Token(s.ctx)
+	s.NoError(err)
+	call := func(tokens *TokenSource, callErr error) error {
+		ctx, err := getCtxForProjectID(s.ctx, "uuid-1", tokens)
+		s.Require().NoError(err)
+		return grpcTokenInterceptor()(ctx, "/catalog.orchestrator.apis.v3.CatalogService/ListRegistries", nil, nil, nil,
+			func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+				return callErr
+			})
+	}
+	unauthenticated := status.Error(codes.Unauthenticated, "token expired")
+
+	// Classifying the error has no side effect, the token is kept until the call is rejected
+	s.ErrorIs(grpcError(unauthenticated), ErrTransient)
+	s.NoError(call(catalogTokens, nil))
+	s.Equal(1, *catalogFetches)
+
+	// The rejected token is dropped from the token source of the client only
+	stale, err := catalogTokens.Token(s.ctx)
+	s.NoError(err)
+	s.Equal(status.Code(unauthenticated), status.Code(call(catalogTokens, unauthenticated)))
+	token, err := catalogTokens.Token(s.ctx)
+	s.NoError(err)
+	s.Equal("catalog-2", token)
+	s.Equal(1, *admFetches)
+
+	// A call rejected with the stale token does not drop the new one
+	catalogTokens.Reject(stale)
+	token, err = catalogTokens.Token(s.ctx)
+	s.NoError(err)
+	s.Equal("catalog-2", token)
+	s.Equal(2, *catalogFetches)
+}
+
+func southboundRetryCount(service string, endpoint string, kind string) float64 {
+	return testutil.ToFloat64(southboundRetries.WithLabelValues(service, endpoint, kind))
+}
+
+// invokeWithRetries makes a call through the retry interceptors of grpcDialOptions, failing with the given errors
+// before it succeeds. It returns the number of attempts.
+func (s *GRPCDialTestSuite) invokeWithRetries(failures ...error) (int, error) {
+	attempts := 0
+	invoker := func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+		attempts++
+		if attempts <= len(failures) {
+			return failures[attempts-1]
+		}
+		return nil
+	}
+	fastRetry := grpcFastRetryInterceptor(ServiceCatalog)
+	retrying := retry.RetryingUnaryClientInterceptor(retry.WithRetryOn(codes.Unavailable, codes.Unknown))
+	method := "/catalog.orchestrator.apis.v3.CatalogService/CreateRegistry"
+	err := grpcRetryStateInterceptor()(s.ctx, method, nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return retrying(ctx, method, req, reply, cc,
+				func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+					return fastRetry(ctx, method, req, reply, cc, invoker, opts...)
+				}, opts...)
+		})
+	return attempts, err
+}
+
+func (s *GRPCDialTestSuite) TestFastRetry() {
+	reset := status.Error(codes.Unavailable, "stream terminated by RST_STREAM with error code: NO_ERROR")
+	down := status.Error(codes.Unavailable, "connection error: desc = \"transport: Error while dialing: connection refused\"")
+	fast := southboundRetryCount(ServiceCatalog, "CreateRegistry", RetryFast)
+	backoff := southboundRetryCount(ServiceCatalog, "CreateRegistry", RetryBackoff)
+
+	// A reset stream is retried right away
+	attempts, err := s.invokeWithRetries(reset)
+	s.NoError(err)
+	s.Equal(2, attempts)
+	s.Equal(fast+1, southboundRetryCount(ServiceCatalog, "CreateRegistry", RetryFast))
+	s.Equal(backoff, southboundRetryCount(ServiceCatalog, "CreateRegistry", RetryBackoff))
+
+	// ... only once per call, further attempts wait for the backoff
+	attempts, err = s.invokeWithRetries(reset, reset, reset)
+	s.NoError(err)
+	s.Equal(4, attempts)
+	s.Equal(fast+2, southboundRetryCount(ServiceCatalog, "CreateRegistry", RetryFast))
+	s.Equal(backoff+2, southboundRetryCount(ServiceCatalog, "CreateRegistry", RetryBackoff))
+
+	// A service that is down is only retried with backoff
+	attempts, err = s.invokeWithRetries(down)
+	s.NoError(err)
+	s.Equal(2, attempts)
+	s.Equal(fast+2, southboundRetryCount(ServiceCatalog, "CreateRegistry", RetryFast))
+	s.Equal(backoff+3, southboundRetryCount(ServiceCatalog, "CreateRegistry", RetryBackoff))
+
+	// Errors that are not transient are not retried
+	attempts, err = s.invokeWithRetries(status.Error(codes.NotFound, "connection reset"))
+	s.Equal(codes.NotFound, status.Code(err))
+	s.Equal(1, attempts)
+
+	s.True(fastRetryable(status.Error(codes.Unavailable, "read tcp 10.0.0.1:443: connection reset by peer")))
+	s.False(fastRetryable(down))
+	s.False(fastRetryable(errors.New("RST_STREAM")))
+}
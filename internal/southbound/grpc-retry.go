@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"context"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Kinds of gRPC retries, as reported in the kind label of the retry metrics
+const (
+	// RetryFast is the single immediate retry of a call whose connection was reset
+	RetryFast = "fast"
+	// RetryBackoff is a retry made after the exponential backoff delay
+	RetryBackoff = "backoff"
+)
+
+var southboundRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "tenant_controller_southbound_retries_total",
+	Help: "Retried calls to southbound gRPC services, by service, endpoint and kind of retry (fast or backoff)",
+}, []string{"service", "endpoint", "kind"})
+
+func init() {
+	metrics.Registry.MustRegister(southboundRetries)
+}
+
+// Messages of Unavailable errors caused by a reset stream or connection rather than by a service that is down. A
+// call that failed this way usually succeeds right away on a new stream or connection.
+var fastRetryMessages = []string{
+	"RST_STREAM",
+	"connection reset",
+	"transport is closing",
+	"error reading from server: EOF",
+}
+
+// fastRetryable reports whether err is worth retrying right away, before the backoff delay.
+func fastRetryable(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok || s.Code() != codes.Unavailable {
+		return false
+	}
+	for _, message := range fastRetryMessages {
+		if strings.Contains(s.Message(), message) {
+			return true
+		}
+	}
+	return false
+}
+
+// grpcRetryState follows the attempts of a single call through the retrying interceptor
+type grpcRetryState struct {
+	mu        sync.Mutex
+	attempts  int
+	fastRetry bool
+}
+
+type grpcRetryStateKey struct{}
+
+// grpcRetryStateInterceptor gives every call its own retry state. It runs outside the retrying interceptor, so that
+// the state is shared by all the attempts of the call.
+func grpcRetryStateInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(context.WithValue(ctx, grpcRetryStateKey{}, &grpcRetryState{}), method, req, reply, cc, opts...)
+	}
+}
+
+// grpcFastRetryInterceptor retries a call once right away if its stream or connection was reset, before the
+// retrying interceptor it runs inside of starts waiting between attempts. Fast and backoff retries are counted
+// apart in the metrics.
+func grpcFastRetryInterceptor(service string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		state, ok := ctx.Value(grpcRetryStateKey{}).(*grpcRetryState)
+		if !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		state.mu.Lock()
+		state.attempts++
+		retried := state.attempts > 1
+		state.mu.Unlock()
+		if retried {
+			southboundRetries.WithLabelValues(service, path.Base(method), RetryBackoff).Inc()
+		}
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if !fastRetryable(err) || ctx.Err() != nil {
+			return err
+		}
+		state.mu.Lock()
+		fastRetry := !state.fastRetry
+		state.fastRetry = true
+		state.mu.Unlock()
+		if !fastRetry {
+			return err
+		}
+		log.Infof("%s %s failed: %v. Retrying right away", service, path.Base(method), err)
+		southboundRetries.WithLabelValues(service, path.Base(method), RetryFast).Inc()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,201 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// HarborRequestIDHeader carries the ID of a Harbor REST call, so that it can be matched with the Harbor logs
+	HarborRequestIDHeader = "X-Request-Id"
+
+	harborAPIPrefix = "/api/v2.0/"
+	// longest request or response body written to the log
+	maxLoggedBodySize = 512
+	// largest Harbor response body read, a page of 100 robots is well below this
+	maxHarborResponseSize = 8 << 20
+)
+
+var harborRequestDuration = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+	Name:       "tenant_controller_harbor_request_duration_seconds",
+	Help:       "Time taken by Harbor REST calls, by method, endpoint and response status code",
+	Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+}, []string{"method", "endpoint", "code"})
+
+func init() {
+	metrics.Registry.MustRegister(harborRequestDuration)
+}
+
+// HarborMiddleware wraps the transport of the Harbor REST client, in the same way as HTTP server middleware wraps a
+// handler.
+type HarborMiddleware func(next http.RoundTripper) http.RoundTripper
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// defaultHarborMiddleware is applied to every Harbor REST call. The request ID is set first so that it is logged.
+var defaultHarborMiddleware = []HarborMiddleware{
+	harborRequestIDMiddleware,
+	harborLoggingMiddleware,
+	harborMetricsMiddleware,
+}
+
+// newHarborClient returns an HTTP client sending requests through the middleware, the first middleware seeing each
+// request first.
+func newHarborClient(transport http.RoundTripper, middleware ...HarborMiddleware) *http.Client {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		transport = middleware[i](transport)
+	}
+	return &http.Client{Transport: transport}
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context whose Harbor REST calls carry the given request ID. Calls made without one are
+// given a random ID.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func requestIDFromContext(ctx context.Context) string {
+	if requestID, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return requestID
+	}
+	return ""
+}
+
+func newRequestID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// harborRequestIDMiddleware sets the request ID header, unless the request already has one.
+func harborRequestIDMiddleware(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get(HarborRequestIDHeader) != "" {
+			return next.RoundTrip(req)
+		}
+		requestID := requestIDFromContext(req.Context())
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		// A round tripper must not change the request it is given
+		req = req.Clone(req.Context())
+		req.Header.Set(HarborRequestIDHeader, requestID)
+		return next.RoundTrip(req)
+	})
+}
+
+// harborLoggingMiddleware logs each call with its request and response bodies. The response body is read in full
+// and replaced, so that it can be logged and still be read by the caller. Authorization headers are never logged.
+func harborLoggingMiddleware(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requestID := req.Header.Get(HarborRequestIDHeader)
+		var requestBody []byte
+		if req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				requestBody, _ = io.ReadAll(io.LimitReader(body, maxHarborResponseSize))
+				_ = body.Close()
+			}
+		}
+
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		elapsed := time.Since(start)
+		if err != nil {
+			log.Warnf("Harbor REST %s %s request %s failed after %v: %v", req.Method, req.URL.Redacted(), requestID, elapsed, err)
+			return nil, err
+		}
+
+		responseBody, readErr := io.ReadAll(io.LimitReader(resp.Body, maxHarborResponseSize))
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+		if readErr != nil {
+			log.Warnf("Harbor REST %s %s request %s unable to read response: %v", req.Method, req.URL.Redacted(), requestID, readErr)
+		}
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			log.Warnf("Harbor REST %s %s request %s returned %s in %v response %s", req.Method, req.URL.Redacted(), requestID,
+				resp.Status, elapsed, logBody(responseBody))
+		} else {
+			log.Infof("Harbor REST %s %s request %s returned %s in %v", req.Method, req.URL.Redacted(), requestID, resp.Status, elapsed)
+		}
+		log.Debugf("Harbor REST request %s body %s response %s", requestID, logBody(requestBody), logBody(responseBody))
+		return resp, nil
+	})
+}
+
+// harborMetricsMiddleware records the duration of each call and counts it in the southbound request metrics, and
+// records the size of its request body. Calls that failed without a response are recorded with the code "error".
+func harborMetricsMiddleware(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.ContentLength > 0 {
+			recordPayload(req.Context(), ServiceHarbor, req.ContentLength)
+		}
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		code := "error"
+		if err == nil {
+			code = strconv.Itoa(resp.StatusCode)
+		}
+		endpoint := harborEndpoint(req.URL.EscapedPath())
+		harborRequestDuration.WithLabelValues(req.Method, endpoint, code).Observe(time.Since(start).Seconds())
+		southboundRequests.WithLabelValues(ServiceHarbor, req.Method+" "+endpoint, code).Inc()
+		return resp, err
+	})
+}
+
+// harborEndpoint returns the path of a Harbor REST call with project names, repository names and IDs replaced by
+// placeholders, so that the metric labels do not grow with the number of projects.
+func harborEndpoint(path string) string {
+	index := strings.Index(path, harborAPIPrefix)
+	if index < 0 {
+		return "other"
+	}
+	segments := strings.Split(strings.Trim(path[index+len(harborAPIPrefix):], "/"), "/")
+	endpoint := harborAPIPrefix + segments[0]
+	if len(segments) > 1 {
+		if segments[0] == "projects" {
+			endpoint += "/{project}"
+		} else {
+			endpoint += "/{id}"
+		}
+	}
+	if len(segments) > 2 {
+		endpoint += "/" + segments[2]
+	}
+	if len(segments) > 3 {
+		endpoint += "/{name}"
+	}
+	return endpoint
+}
+
+// logBody returns a body for logging with credentials redacted, truncated to maxLoggedBodySize.
+func logBody(body []byte) string {
+	if len(body) == 0 {
+		return "<empty>"
+	}
+	scrubbed := Scrub(string(body))
+	if len(scrubbed) > maxLoggedBodySize {
+		return scrubbed[:maxLoggedBodySize] + "...<truncated " + strconv.Itoa(len(scrubbed)-maxLoggedBodySize) + " bytes>"
+	}
+	return scrubbed
+}
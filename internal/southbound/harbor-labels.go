@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+)
+
+const (
+	HarborLabelsURL = "/api/v2.0/labels"
+
+	// scope of the labels that belong to a single project
+	harborProjectLabelScope = "p"
+)
+
+// HarborLabel is a Harbor label that can be attached to artifacts
+type HarborLabel struct {
+	ID          int    `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Color       string `json:"color,omitempty"`
+	Scope       string `json:"scope"`
+	ProjectID   int    `json:"project_id"`
+}
+
+func (h *HarborOCI) listProjectLabelsPage(ctx context.Context, projectID int, page int) ([]HarborLabel, bool, error) {
+	query := url.Values{}
+	query.Set("scope", harborProjectLabelScope)
+	query.Set("project_id", strconv.Itoa(projectID))
+	query.Set("page", strconv.Itoa(page))
+	query.Set("page_size", strconv.Itoa(harborPageSize))
+	resp, err := h.doHarborREST(ctx, http.MethodGet, h.harborHost+HarborLabelsURL+"?"+query.Encode(), nil, AddHeaders)
+	if err != nil {
+		return nil, false, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, resp.statusError()
+	}
+	labels := []HarborLabel{}
+	if err := json.Unmarshal(resp.Body, &labels); err != nil {
+		return nil, false, err
+	}
+	return labels, len(labels) == harborPageSize, nil
+}
+
+// ListProjectLabels returns the labels of the Harbor project with the given ID.
+func (h *HarborOCI) ListProjectLabels(ctx context.Context, projectID int) ([]HarborLabel, error) {
+	labels := []HarborLabel{}
+	for page := 1; ; page++ {
+		pageResults, more, err := h.listProjectLabelsPage(ctx, projectID, page)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, pageResults...)
+		if !more {
+			break
+		}
+	}
+	return labels, nil
+}
+
+// SetProjectLabel creates the label in the Harbor project with the given ID, or updates the description and color
+// of the project label with the same name. It returns the ID of the label.
+func (h *HarborOCI) SetProjectLabel(ctx context.Context, projectID int, label config.HarborLabel) (int, error) {
+	labels, err := h.ListProjectLabels(ctx, projectID)
+	if err != nil {
+		return 0, err
+	}
+	wanted := HarborLabel{
+		Name:        label.Name,
+		Description: label.Description,
+		Color:       label.Color,
+		Scope:       harborProjectLabelScope,
+		ProjectID:   projectID,
+	}
+	for _, existing := range labels {
+		if existing.Name != label.Name {
+			continue
+		}
+		if existing.Description == label.Description && existing.Color == label.Color {
+			return existing.ID, nil
+		}
+		wanted.ID = existing.ID
+		labelBody, err := json.Marshal(wanted)
+		if err != nil {
+			return 0, err
+		}
+		resp, err := h.doHarborREST(ctx, http.MethodPut, fmt.Sprintf("%s%s/%d", h.harborHost, HarborLabelsURL, existing.ID), bytes.NewReader(labelBody), AddHeaders)
+		if err != nil {
+			return 0, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return 0, resp.statusError()
+		}
+		return existing.ID, nil
+	}
+
+	labelBody, err := json.Marshal(wanted)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := h.doHarborREST(ctx, http.MethodPost, h.harborHost+HarborLabelsURL, bytes.NewReader(labelBody), AddHeaders)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return 0, resp.statusError()
+	}
+	// Harbor answers with the URL of the new label
+	id, err := strconv.Atoi(path.Base(resp.Header.Get("Location")))
+	if err != nil {
+		return 0, fmt.Errorf("harbor label %s was created without a label location: %w", label.Name, err)
+	}
+	return id, nil
+}
+
+// DeleteLabel deletes the label with the given ID, which is removed from the artifacts it is attached to. A label
+// that does not exist is already deleted.
+func (h *HarborOCI) DeleteLabel(ctx context.Context, labelID int) error {
+	resp, err := h.doHarborREST(ctx, http.MethodDelete, fmt.Sprintf("%s%s/%d", h.harborHost, HarborLabelsURL, labelID), nil, AddHeaders)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return resp.statusError()
+	}
+	return nil
+}
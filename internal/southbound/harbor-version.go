@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const HarborSystemInfoURL = "/api/v2.0/systeminfo"
+
+// HarborVersion is the major and minor version of a Harbor server
+type HarborVersion struct {
+	Major int
+	Minor int
+}
+
+func (v HarborVersion) String() string {
+	return fmt.Sprintf("v%d.%d", v.Major, v.Minor)
+}
+
+// AtLeast reports whether the version is the same as or newer than the other version.
+func (v HarborVersion) AtLeast(other HarborVersion) bool {
+	return v.Major > other.Major || (v.Major == other.Major && v.Minor >= other.Minor)
+}
+
+var (
+	// MinHarborVersion is the oldest Harbor supported, the first with project level robot accounts whose secrets
+	// can be refreshed
+	MinHarborVersion = HarborVersion{Major: 2, Minor: 2}
+	// harborScanStopVersion is the first Harbor that lets robot accounts stop scans
+	harborScanStopVersion = HarborVersion{Major: 2, Minor: 8}
+	// harborNotaryRemovedVersion is the first Harbor without Notary, whose enable_content_trust project metadata no
+	// longer makes Harbor require Notary v1 (Docker Content Trust) signatures
+	harborNotaryRemovedVersion = HarborVersion{Major: 2, Minor: 9}
+)
+
+// HarborCapabilities are the optional Harbor features used by the controller, as supported by the Harbor version.
+type HarborCapabilities struct {
+	// empty if the version has not been negotiated
+	Version string
+	// robot accounts may be given the scan stop permission
+	ScanStop bool
+	// projects may require Notary v1 signatures, which the notation signing of the profiles is enforced with
+	NotaryContentTrust bool
+}
+
+// defaultHarborCapabilities are assumed until the version of Harbor is known
+var defaultHarborCapabilities = HarborCapabilities{ScanStop: true}
+
+func harborCapabilities(version HarborVersion) HarborCapabilities {
+	return HarborCapabilities{
+		Version:            version.String(),
+		ScanStop:           version.AtLeast(harborScanStopVersion),
+		NotaryContentTrust: !version.AtLeast(harborNotaryRemovedVersion),
+	}
+}
+
+var harborVersionPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)`)
+
+// ParseHarborVersion parses the version reported by Harbor, such as v2.10.0-a4d8ce3a.
+func ParseHarborVersion(version string) (HarborVersion, error) {
+	match := harborVersionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return HarborVersion{}, fmt.Errorf("invalid Harbor version %q", version)
+	}
+	major, _ := strconv.Atoi(match[1])
+	minor, _ := strconv.Atoi(match[2])
+	return HarborVersion{Major: major, Minor: minor}, nil
+}
+
+var harborInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "tenant_controller_harbor_info",
+	Help: "Version of the Harbor server the controller provisions, always 1",
+}, []string{"version"})
+
+func init() {
+	metrics.Registry.MustRegister(harborInfo)
+}
+
+type HarborSystemInfo struct {
+	HarborVersion string `json:"harbor_version"`
+}
+
+// NegotiateCapabilities reads the version of Harbor and selects the features the client uses accordingly. It
+// returns a permanent error if the version cannot be read or is older than MinHarborVersion.
+func (h *HarborOCI) NegotiateCapabilities(ctx context.Context) (HarborCapabilities, error) {
+	resp, err := h.doHarborREST(ctx, http.MethodGet, h.harborHost+HarborSystemInfoURL, nil, AddHeaders)
+	if err != nil {
+		return HarborCapabilities{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return HarborCapabilities{}, resp.statusError()
+	}
+	systemInfo := HarborSystemInfo{}
+	if err := json.Unmarshal(resp.Body, &systemInfo); err != nil {
+		return HarborCapabilities{}, classify(ErrPermanent, fmt.Errorf("invalid Harbor system info: %w", err))
+	}
+	version, err := ParseHarborVersion(systemInfo.HarborVersion)
+	if err != nil {
+		return HarborCapabilities{}, classify(ErrPermanent, err)
+	}
+	if !version.AtLeast(MinHarborVersion) {
+		return HarborCapabilities{}, classify(ErrPermanent, fmt.Errorf("harbor %s is not supported, the minimum supported version is %s",
+			systemInfo.HarborVersion, MinHarborVersion))
+	}
+
+	harborInfo.Reset()
+	harborInfo.WithLabelValues(systemInfo.HarborVersion).Set(1)
+	h.capabilities = harborCapabilities(version)
+	return h.capabilities, nil
+}
@@ -11,15 +11,29 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
 )
 
 type HarborOCI struct {
-	harborHost string
-	oidcURL    string
-	username   string
-	token      string
+	harborHost  string
+	oidcURL     string
+	adminSecret config.SecretRef
+	// the admin credential, replaced by ReloadCredentials while calls are made
+	credentialsMu sync.RWMutex
+	username      string
+	token         string
+	client        *http.Client
+	// features of the Harbor version, set by NegotiateCapabilities
+	capabilities HarborCapabilities
+	// time allowed for each REST call, zero if only bounded by the context of the call
+	requestTimeout time.Duration
 }
 
 const (
@@ -27,8 +41,13 @@ const (
 	HarborRobotsURL        = "/api/v2.0/robots"
 	HarborProjectsURL      = "/api/v2.0/projects"
 	HarborPingURL          = "/api/v2.0/ping"
+	HarborQuotasURL        = "/api/v2.0/quotas"
+	HarborStatisticsURL    = "/api/v2.0/statistics"
 	AddHeaders             = true
 	NoHeaders              = false
+
+	// Harbor caps page_size at 100 for list endpoints
+	harborPageSize = 100
 )
 
 type K8s interface {
@@ -45,69 +64,167 @@ func HarborProjectName(org string, displayName string) string {
 	return fmt.Sprintf(`catalog-apps-%s-%s`, org, displayName)
 }
 
-func readHarborAdminCredentials(ctx context.Context, harborNamespace string, harborAdminCredential string) (username, password string, err error) {
-	k8sClient, err := K8sFactory(harborNamespace)
-	if err != nil {
-		return "", "", err
+// harborProject returns the name of the Harbor project of the organization and project. An empty organization or
+// project name is a permanent error, so that no call is made for a project such as catalog-apps--name, which would
+// be shared by every project with an empty organization.
+func harborProject(org string, displayName string) (string, error) {
+	if strings.TrimSpace(org) == "" || strings.TrimSpace(displayName) == "" {
+		return "", classify(ErrPermanent, fmt.Errorf("invalid Harbor project for organization %q and project %q: names must not be empty",
+			org, displayName))
 	}
-	data, err := k8sClient.ReadSecret(ctx, harborAdminCredential)
+	return HarborProjectName(org, displayName), nil
+}
+
+func readHarborAdminCredentials(ctx context.Context, adminSecret config.SecretRef) (username, password string, err error) {
+	credString, err := ReadSecretRef(ctx, adminSecret)
 	if err != nil {
 		return "", "", err
 	}
 
-	credString, ok := data["credential"]
+	username, password, ok := strings.Cut(string(credString), ":")
 	if !ok {
-		return "", "", fmt.Errorf("no credential found in secret")
+		return "", "", classify(ErrPermanent, fmt.Errorf("harbor admin credential is not in username:password form"))
 	}
-
-	creds := strings.Split(string(credString), ":")
-	return creds[0], creds[1], nil
+	RegisterSecret("harbor-admin-password", password)
+	return username, password, nil
 }
 
-func newHarbor(ctx context.Context, harborHost string, oidcURL string, harborNamespace string, harborAdminCredential string) (*HarborOCI, error) {
-	u, p, err := readHarborAdminCredentials(ctx, harborNamespace, harborAdminCredential)
+func newHarbor(ctx context.Context, harborHost string, oidcURL string, adminSecret config.SecretRef) (*HarborOCI, error) {
+	u, p, err := readHarborAdminCredentials(ctx, adminSecret)
 	if err != nil {
 		return nil, err
 	}
 	harbor := &HarborOCI{
-		harborHost: harborHost,
-		oidcURL:    oidcURL,
-		username:   u,
-		token:      p,
+		harborHost:  harborHost,
+		oidcURL:     oidcURL,
+		adminSecret: adminSecret,
+		username:    u,
+		token:       p,
+		client:      newHarborClient(http.DefaultTransport, defaultHarborMiddleware...),
+		// assume a current Harbor until the version is negotiated
+		capabilities: defaultHarborCapabilities,
 	}
 	return harbor, nil
 }
 
-func NewHarborOCI(ctx context.Context, harborHost string, oidcURL string, harborNamespace string, harborAdminCredential string) (*HarborOCI, error) {
-	return newHarbor(ctx, harborHost, oidcURL, harborNamespace, harborAdminCredential)
+func NewHarborOCI(ctx context.Context, harborHost string, oidcURL string, adminSecret config.SecretRef) (*HarborOCI, error) {
+	return newHarbor(ctx, harborHost, oidcURL, adminSecret)
+}
+
+// SetRequestTimeout bounds each REST call, so that a Harbor that stops responding fails the call rather than the
+// whole event. A call that times out fails with a transient error.
+func (h *HarborOCI) SetRequestTimeout(timeout time.Duration) {
+	h.requestTimeout = timeout
+}
+
+// ReloadCredentials reads the admin credential from the admin secret again, so that a rotated credential is used
+// without creating a new client. It returns true if the credential changed. Calls in progress finish with the
+// credential they were sent with.
+func (h *HarborOCI) ReloadCredentials(ctx context.Context) (bool, error) {
+	username, token, err := readHarborAdminCredentials(ctx, h.adminSecret)
+	if err != nil {
+		return false, err
+	}
+	h.credentialsMu.Lock()
+	defer h.credentialsMu.Unlock()
+	if username == h.username && token == h.token {
+		return false, nil
+	}
+	h.username, h.token = username, token
+	log.Infof("Reloaded the Harbor admin credential from %s", h.adminSecret)
+	return true, nil
+}
+
+func (h *HarborOCI) credentials() (string, string) {
+	h.credentialsMu.RLock()
+	defer h.credentialsMu.RUnlock()
+	return h.username, h.token
 }
 
+// harborResponse is a Harbor REST response whose body has been read and closed
+type harborResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// statusError returns the classified error for an unexpected response status, with the response body as message
+// and the Harbor error messages of the body as user message.
+func (r *harborResponse) statusError() error {
+	return WithUserMessage(r.error(fmt.Errorf("%s", string(r.Body))), "%s", harborUserMessage(r.StatusCode, r.Body))
+}
+
+// error classifies err according to the response status, throttled if Harbor asked to slow down.
+func (r *harborResponse) error(err error) error {
+	return httpResponseError(r.StatusCode, r.Header, err)
+}
+
+// doHarborREST makes a Harbor REST call through the client middleware. The response body is always read and closed,
+// so callers cannot leak connections. A call that Harbor rejects as unauthorized is sent once more if the admin
+// credential was rotated since it was read, so that events in progress are not failed by the rotation.
 func (h *HarborOCI) doHarborREST(
 	ctx context.Context,
 	method string,
 	endpoint string,
 	body io.Reader,
 	addHeaders bool,
-) (*http.Response, error) {
-	c := &http.Client{}
-	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+) (*harborResponse, error) {
+	var requestBody []byte
+	if body != nil {
+		var err error
+		if requestBody, err = io.ReadAll(body); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := h.sendHarborREST(ctx, method, endpoint, requestBody, addHeaders)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || !addHeaders {
+		return resp, err
+	}
+	changed, reloadErr := h.ReloadCredentials(ctx)
+	if reloadErr != nil {
+		log.Warnf("Unable to reload the Harbor admin credential: %v", ScrubError(reloadErr))
+	}
+	if !changed {
+		return resp, nil
+	}
+	log.Info("Harbor rejected the admin credential after it was rotated, calling again with the new credential")
+	return h.sendHarborREST(ctx, method, endpoint, requestBody, addHeaders)
+}
+
+func (h *HarborOCI) sendHarborREST(
+	ctx context.Context,
+	method string,
+	endpoint string,
+	requestBody []byte,
+	addHeaders bool,
+) (*harborResponse, error) {
+	callCtx, cancel := withCallTimeout(ctx, h.requestTimeout)
+	defer cancel()
+	var body io.Reader
+	if requestBody != nil {
+		body = bytes.NewReader(requestBody)
+	}
+	req, err := http.NewRequestWithContext(callCtx, method, endpoint, body)
 	if err != nil {
 		return nil, err
 	}
 	if addHeaders {
-		req.SetBasicAuth(h.username, h.token)
+		req.SetBasicAuth(h.credentials())
 		req.Header.Add("content-type", "application/json")
 		req.Header.Add("accept", "application/json")
 	}
-	log.Infof("Harbor REST request method %s base URL %s", method, req.URL.String())
 
-	resp, err := c.Do(req)
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, requestError(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(io.LimitReader(resp.Body, maxHarborResponseSize))
 	if err != nil {
-		log.Infof("Harbor REST call failed with error %s", err.Error())
-	} else {
-		log.Infof("Harbor REST call succeeded %s", resp.Status)
+		return nil, requestError(ctx, err)
 	}
-	return resp, err
+	return &harborResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: responseBody}, nil
 }
 
 type ConfigurationAttributes struct {
@@ -145,12 +262,9 @@ func (h *HarborOCI) Configurations(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	defer func() { _ = resp.Body.Close() }()
-	
+
 	if resp.StatusCode != http.StatusOK {
-		responseBody, _ := io.ReadAll(resp.Body)
-		responseJSON := string(responseBody)
-		return fmt.Errorf("%s", responseJSON)
+		return resp.statusError()
 	}
 
 	return err
@@ -159,15 +273,21 @@ func (h *HarborOCI) Configurations(ctx context.Context) error {
 type CreateProjectAttributes struct {
 	ProjectName  string `json:"project_name"`
 	Public       bool   `json:"public"`
-	StorageLimit int    `json:"storage_limit"`
+	StorageLimit int64  `json:"storage_limit"`
 }
 
-func (h *HarborOCI) CreateProject(ctx context.Context, org string, displayName string) error {
+// CreateProject creates the Harbor project for the given org and project. A storage limit of 0 leaves the
+// Harbor default quota in place.
+func (h *HarborOCI) CreateProject(ctx context.Context, org string, displayName string, storageLimit int64) error {
+	projectName, err := harborProject(org, displayName)
+	if err != nil {
+		return err
+	}
 	URL := h.harborHost + HarborProjectsURL
 	projectAttrs := CreateProjectAttributes{
-		ProjectName:  HarborProjectName(org, displayName),
+		ProjectName:  projectName,
 		Public:       false,
-		StorageLimit: 0,
+		StorageLimit: storageLimit,
 	}
 	projectBody, err := json.Marshal(projectAttrs)
 	if err != nil {
@@ -179,9 +299,58 @@ func (h *HarborOCI) CreateProject(ctx context.Context, org string, displayName s
 		return err
 	}
 	if !(resp.StatusCode == http.StatusCreated || resp.StatusCode == http.StatusConflict) {
-		responseBody, _ := io.ReadAll(resp.Body)
-		responseJSON := string(responseBody)
-		return fmt.Errorf("%s", responseJSON)
+		return resp.statusError()
+	}
+	return nil
+}
+
+type HarborQuota struct {
+	ID int `json:"id"`
+}
+
+type UpdateQuotaAttributes struct {
+	Hard map[string]int64 `json:"hard"`
+}
+
+// SetProjectStorageLimit changes the storage quota of an existing Harbor project.
+func (h *HarborOCI) SetProjectStorageLimit(ctx context.Context, org string, displayName string, storageLimit int64) error {
+	projectID, err := h.GetProjectID(ctx, org, displayName)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("reference", "project")
+	query.Set("reference_id", strconv.Itoa(projectID))
+	URL := h.harborHost + HarborQuotasURL + "?" + query.Encode()
+	resp, err := h.doHarborREST(ctx, http.MethodGet, URL, nil, AddHeaders)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.statusError()
+	}
+	quotas := []HarborQuota{}
+	if err := json.Unmarshal(resp.Body, &quotas); err != nil {
+		return err
+	}
+	if len(quotas) == 0 {
+		return classify(ErrPermanent, fmt.Errorf("no quota found for project %s", HarborProjectName(org, displayName)))
+	}
+
+	quotaBody, err := json.Marshal(UpdateQuotaAttributes{Hard: map[string]int64{"storage": storageLimit}})
+	if err != nil {
+		return err
+	}
+	URL = fmt.Sprintf("%s%s/%d", h.harborHost, HarborQuotasURL, quotas[0].ID)
+	updateResp, err := h.doHarborREST(ctx, http.MethodPut, URL, bytes.NewReader(quotaBody), AddHeaders)
+	if err != nil {
+		return err
+	}
+
+	if updateResp.StatusCode != http.StatusOK {
+		return updateResp.statusError()
 	}
 	return nil
 }
@@ -196,7 +365,11 @@ type MembersAttributes struct {
 }
 
 func (h *HarborOCI) SetMemberPermissions(ctx context.Context, roleID int, org string, displayName string, groupName string) error {
-	URL := fmt.Sprintf("%s/api/v2.0/projects/%s/members", h.harborHost, HarborProjectName(org, displayName))
+	projectName, err := harborProject(org, displayName)
+	if err != nil {
+		return err
+	}
+	URL := fmt.Sprintf("%s/api/v2.0/projects/%s/members", h.harborHost, projectName)
 	membersAttrs := MembersAttributes{
 		RoleID:      roleID,
 		MemberGroup: MemberGroup{GroupName: groupName},
@@ -211,37 +384,181 @@ func (h *HarborOCI) SetMemberPermissions(ctx context.Context, roleID int, org st
 	if err != nil {
 		return err
 	}
-	defer func() { _ = resp.Body.Close() }()
-	
+
 	if !(resp.StatusCode == http.StatusCreated || resp.StatusCode == http.StatusConflict) {
-		responseBody, _ := io.ReadAll(resp.Body)
-		responseJSON := string(responseBody)
-		return fmt.Errorf("%s", responseJSON)
+		return resp.statusError()
+	}
+	return nil
+}
+
+// HarborMember is a member of a Harbor project
+type HarborMember struct {
+	ID         int    `json:"id"`
+	EntityName string `json:"entity_name"`
+	EntityType string `json:"entity_type"`
+	RoleID     int    `json:"role_id"`
+}
+
+type MemberRole struct {
+	RoleID int `json:"role_id"`
+}
+
+// SetMemberRole changes the role of a group that is already a member of the Harbor project for the given org and
+// project, as SetMemberPermissions keeps the role of an existing member. The error wraps ErrNotFound if the group is
+// not a member.
+func (h *HarborOCI) SetMemberRole(ctx context.Context, roleID int, org string, displayName string, groupName string) error {
+	projectName, err := harborProject(org, displayName)
+	if err != nil {
+		return err
+	}
+	query := url.Values{}
+	query.Set("entityname", groupName)
+	URL := fmt.Sprintf("%s%s/%s/members", h.harborHost, HarborProjectsURL, projectName)
+	resp, err := h.doHarborREST(ctx, http.MethodGet, URL+"?"+query.Encode(), nil, AddHeaders)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp.statusError()
+	}
+	members := []HarborMember{}
+	if err := json.Unmarshal(resp.Body, &members); err != nil {
+		return err
+	}
+	// Harbor matches the entity name fuzzily, and users can have the name of a group
+	i := slices.IndexFunc(members, func(member HarborMember) bool {
+		return member.EntityType == "g" && member.EntityName == groupName
+	})
+	if i < 0 {
+		return classify(ErrPermanent, fmt.Errorf("group %s of harbor project %s %w", groupName, projectName, ErrNotFound))
+	}
+	if members[i].RoleID == roleID {
+		return nil
+	}
+
+	roleBody, err := json.Marshal(MemberRole{RoleID: roleID})
+	if err != nil {
+		return err
+	}
+	resp, err = h.doHarborREST(ctx, http.MethodPut, fmt.Sprintf("%s/%d", URL, members[i].ID), bytes.NewReader(roleBody), AddHeaders)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp.statusError()
 	}
 	return nil
 }
 
 type HarborProject struct {
-	ProjectID int `json:"project_id"`
+	ProjectID int               `json:"project_id"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// Harbor project metadata keys that make Harbor refuse to serve artifacts without a signature
+const (
+	HarborMetadataContentTrust       = "enable_content_trust"
+	HarborMetadataContentTrustCosign = "enable_content_trust_cosign"
+)
+
+// HarborContentTrust selects the signatures that artifacts pulled from a Harbor project must have.
+type HarborContentTrust struct {
+	// require a cosign signature
+	Cosign bool
+	// require a notation signature
+	Notation bool
+}
+
+// metadata returns the project metadata that enforces the signatures. The enable_content_trust metadata only enforces
+// Notary v1 (Docker Content Trust) signatures, so it is only set for a Harbor that still has Notary; requiring a
+// notation signature from a newer Harbor is a permanent error rather than a setting that Harbor silently ignores.
+func (c HarborContentTrust) metadata(capabilities HarborCapabilities) (map[string]string, error) {
+	metadata := map[string]string{HarborMetadataContentTrustCosign: strconv.FormatBool(c.Cosign)}
+	if capabilities.NotaryContentTrust {
+		metadata[HarborMetadataContentTrust] = strconv.FormatBool(c.Notation)
+	} else if c.Notation {
+		version := capabilities.Version
+		if version == "" {
+			version = "of an unknown version"
+		}
+		return nil, classify(ErrPermanent, fmt.Errorf(
+			"harbor %s cannot require notation signatures: its projects only enforce Notary v1 signatures with %s, "+
+				"which Harbor %s removed", version, HarborMetadataContentTrust, harborNotaryRemovedVersion))
+	}
+	return metadata, nil
+}
+
+type UpdateProjectAttributes struct {
+	Metadata map[string]string `json:"metadata"`
+}
+
+// SetProjectContentTrust sets the signature enforcement of an existing Harbor project, then reads the project back
+// to verify that Harbor applied it. A Harbor that ignores the setting, or cannot enforce the signatures, is a
+// permanent error, so that a project is not left accepting unsigned artifacts unnoticed.
+func (h *HarborOCI) SetProjectContentTrust(ctx context.Context, org string, displayName string, trust HarborContentTrust) error {
+	projectName, err := harborProject(org, displayName)
+	if err != nil {
+		return err
+	}
+	wanted, err := trust.metadata(h.capabilities)
+	if err != nil {
+		return err
+	}
+	URL := h.harborHost + HarborProjectsURL + "/" + projectName
+	projectBody, err := json.Marshal(UpdateProjectAttributes{Metadata: wanted})
+	if err != nil {
+		return err
+	}
+	resp, err := h.doHarborREST(ctx, http.MethodPut, URL, bytes.NewReader(projectBody), AddHeaders)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp.statusError()
+	}
+
+	resp, err = h.doHarborREST(ctx, http.MethodGet, URL, nil, AddHeaders)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp.statusError()
+	}
+	project := HarborProject{}
+	if err := json.Unmarshal(resp.Body, &project); err != nil {
+		return err
+	}
+	for key, value := range wanted {
+		// Harbor leaves out metadata that was never enabled
+		applied, ok := project.Metadata[key]
+		if !ok {
+			applied = "false"
+		}
+		if applied != value {
+			return classify(ErrPermanent, fmt.Errorf("harbor project %s has %s %q instead of %q", projectName, key, applied, value))
+		}
+	}
+	return nil
 }
 
 func (h *HarborOCI) GetProjectID(ctx context.Context, org string, displayName string) (int, error) {
-	URL := h.harborHost + "/api/v2.0/projects/" + HarborProjectName(org, displayName)
+	projectName, err := harborProject(org, displayName)
+	if err != nil {
+		return 0, err
+	}
+	URL := h.harborHost + "/api/v2.0/projects/" + projectName
 
 	projectResults := HarborProject{}
 	resp, err := h.doHarborREST(ctx, http.MethodGet, URL, nil, AddHeaders)
 	if err != nil {
 		return 0, err
 	}
-	defer func() { _ = resp.Body.Close() }()
-	
+
 	if resp.StatusCode != http.StatusOK {
-		responseBody, _ := io.ReadAll(resp.Body)
-		responseJSON := string(responseBody)
-		return 0, fmt.Errorf("%s", responseJSON)
+		return 0, resp.statusError()
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&projectResults)
+	err = json.Unmarshal(resp.Body, &projectResults)
 	if err != nil {
 		return 0, err
 	}
@@ -284,13 +601,13 @@ func addAccess(resource string, actions []string, permissions *RobotPermissions)
 	}
 }
 
-func (h *HarborOCI) CreateRobot(ctx context.Context, robotName string, org string, displayName string) (string, string, error) {
-	repositoryActions := []string{"list", "pull", "push", "delete"}
-	artifactActions := []string{"read", "list", "delete"}
-	artifactLabelActions := []string{"create", "delete"}
-	tagActions := []string{"create", "delete", "list"}
-	scanActions := []string{"create", "stop"}
-
+// CreateRobot creates a robot account with the given access to the project. It returns the full name of the robot
+// and its secret. The stop action on scans is left out if Harbor does not support it.
+func (h *HarborOCI) CreateRobot(ctx context.Context, robotName string, org string, displayName string, access []config.HarborRobotAccess) (string, string, error) {
+	projectName, err := harborProject(org, displayName)
+	if err != nil {
+		return "", "", err
+	}
 	URL := h.harborHost + HarborRobotsURL
 	robotAttrs := CreateRobotAttributes{}
 	robotAttrs.Name = robotName
@@ -298,14 +615,16 @@ func (h *HarborOCI) CreateRobot(ctx context.Context, robotName string, org strin
 	robotAttrs.Duration = -1
 	permission := &RobotPermissions{
 		Kind:      "project",
-		Namespace: HarborProjectName(org, displayName),
+		Namespace: projectName,
 		Access:    make([]RobotAccess, 0),
 	}
-	addAccess("repository", repositoryActions, permission)
-	addAccess("artifact", artifactActions, permission)
-	addAccess("artifact-label", artifactLabelActions, permission)
-	addAccess("tag", tagActions, permission)
-	addAccess("scan", scanActions, permission)
+	for _, a := range access {
+		actions := a.Actions
+		if a.Resource == "scan" && !h.capabilities.ScanStop {
+			actions = slices.DeleteFunc(slices.Clone(actions), func(action string) bool { return action == "stop" })
+		}
+		addAccess(a.Resource, actions, permission)
+	}
 	robotAttrs.Permissions = append(robotAttrs.Permissions, *permission)
 
 	robotBody, err := json.Marshal(robotAttrs)
@@ -317,15 +636,12 @@ func (h *HarborOCI) CreateRobot(ctx context.Context, robotName string, org strin
 	if err != nil {
 		return "", "", err
 	}
-	defer func() { _ = resp.Body.Close() }()
-	
-	createRobotResponseBody, _ := io.ReadAll(resp.Body)
+
 	if resp.StatusCode != http.StatusCreated {
-		responseJSON := string(createRobotResponseBody)
-		return "", "", fmt.Errorf("%s", responseJSON)
+		return "", "", resp.statusError()
 	}
 	createRobotResponse := &CreateRobotResponse{}
-	err = json.Unmarshal(createRobotResponseBody, createRobotResponse)
+	err = json.Unmarshal(resp.Body, createRobotResponse)
 	if err != nil {
 		return "", "", err
 	}
@@ -335,6 +651,7 @@ func (h *HarborOCI) CreateRobot(ctx context.Context, robotName string, org strin
 
 type HarborRobot struct {
 	CreationTime time.Time `json:"creation_time"`
+	Description  string    `json:"description"`
 	Disable      bool      `json:"disable"`
 	Duration     int       `json:"duration"`
 	Editable     bool      `json:"editable"`
@@ -353,36 +670,120 @@ type HarborRobot struct {
 	UpdateTime time.Time `json:"update_time"`
 }
 
-func (h *HarborOCI) GetRobot(ctx context.Context, org string, displayName string, robotName string, projectID int) (*HarborRobot, error) {
-	robotName = fmt.Sprintf(`robot$%s+%s`, HarborProjectName(org, displayName), robotName)
-	URL := h.harborHost + "/api/v2.0/robots?q=Level=project,ProjectID=" + fmt.Sprintf("%d", projectID)
+func (h *HarborOCI) listRobotsPage(ctx context.Context, robotName string, projectID int, page int) ([]HarborRobot, bool, error) {
+	// The name is matched fuzzily, as Harbor stores project robot names without the robot$ prefix
+	query := url.Values{}
+	query.Set("q", fmt.Sprintf("Level=project,ProjectID=%d,name=~%s", projectID, robotName))
+	query.Set("page", strconv.Itoa(page))
+	query.Set("page_size", strconv.Itoa(harborPageSize))
+	URL := h.harborHost + HarborRobotsURL + "?" + query.Encode()
 
-	robotsResults := []HarborRobot{}
 	resp, err := h.doHarborREST(ctx, http.MethodGet, URL, nil, AddHeaders)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	defer func() { _ = resp.Body.Close() }()
-	
+
 	if resp.StatusCode != http.StatusOK {
-		responseBody, _ := io.ReadAll(resp.Body)
-		responseJSON := string(responseBody)
-		return nil, fmt.Errorf("%s", responseJSON)
+		return nil, false, resp.statusError()
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&robotsResults)
+	robots := []HarborRobot{}
+	err = json.Unmarshal(resp.Body, &robots)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
+	return robots, len(robots) == harborPageSize, nil
+}
 
-	for i, robot := range robotsResults {
-		log.Infof("Checking robot[%d] %v for %s", i, robot, robotName)
-		if robot.Name == robotName {
-			return &robot, err
+// GetRobot returns the robot account with the given name in the Harbor project for the given org and project.
+// The error wraps ErrNotFound if the robot does not exist.
+func (h *HarborOCI) GetRobot(ctx context.Context, org string, displayName string, robotName string, projectID int) (*HarborRobot, error) {
+	projectName, err := harborProject(org, displayName)
+	if err != nil {
+		return nil, err
+	}
+	fullName := fmt.Sprintf(`robot$%s+%s`, projectName, robotName)
+	for page := 1; ; page++ {
+		robots, more, err := h.listRobotsPage(ctx, robotName, projectID, page)
+		if err != nil {
+			return nil, err
 		}
+		for i := range robots {
+			if robots[i].Name == fullName {
+				return &robots[i], nil
+			}
+		}
+		if !more {
+			break
+		}
+	}
+	return nil, classify(ErrPermanent, fmt.Errorf("harbor robot %s %w", fullName, ErrNotFound))
+}
+
+type RobotSecret struct {
+	Secret string `json:"secret"`
+}
+
+// RefreshRobotSecret has Harbor generate a new secret for an existing robot account, keeping the account and
+// its permissions.
+func (h *HarborOCI) RefreshRobotSecret(ctx context.Context, robotID int) (string, error) {
+	URL := fmt.Sprintf("%s%s/%d", h.harborHost, HarborRobotsURL, robotID)
+	// An empty secret asks Harbor to generate one
+	secretBody, err := json.Marshal(RobotSecret{})
+	if err != nil {
+		return "", err
+	}
+	resp, err := h.doHarborREST(ctx, http.MethodPatch, URL, bytes.NewReader(secretBody), AddHeaders)
+	if err != nil {
+		return "", err
 	}
 
-	return nil, fmt.Errorf("harbor robot %s not found", robotName)
+	if resp.StatusCode != http.StatusOK {
+		return "", resp.statusError()
+	}
+	robotSecret := RobotSecret{}
+	if err := json.Unmarshal(resp.Body, &robotSecret); err != nil {
+		return "", err
+	}
+	return robotSecret.Secret, nil
+}
+
+// SetRobotDisabled disables or enables a robot account, keeping its secret and permissions. Harbor refuses the
+// credentials of a disabled robot until it is enabled again. The error wraps ErrNotFound if the robot does not exist.
+func (h *HarborOCI) SetRobotDisabled(ctx context.Context, robot HarborRobot, disabled bool) error {
+	if robot.Disable == disabled {
+		return nil
+	}
+	URL := fmt.Sprintf("%s%s/%d", h.harborHost, HarborRobotsURL, robot.ID)
+	// Harbor replaces the robot with the body, so the robot is read again and sent back with every field it has,
+	// only changing whether it is disabled
+	resp, err := h.doHarborREST(ctx, http.MethodGet, URL, nil, AddHeaders)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return classify(ErrPermanent, fmt.Errorf("harbor robot %s %w", robot.Name, ErrNotFound))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp.statusError()
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(resp.Body, &fields); err != nil {
+		return fmt.Errorf("invalid harbor robot %s: %w", robot.Name, err)
+	}
+	fields["disable"] = json.RawMessage(strconv.FormatBool(disabled))
+	robotBody, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	resp, err = h.doHarborREST(ctx, http.MethodPut, URL, bytes.NewReader(robotBody), AddHeaders)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp.statusError()
+	}
+	return nil
 }
 
 func (h *HarborOCI) DeleteRobot(ctx context.Context, robotID int) error {
@@ -391,47 +792,142 @@ func (h *HarborOCI) DeleteRobot(ctx context.Context, robotID int) error {
 	if err != nil {
 		return err
 	}
-	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
-		responseBody, _ := io.ReadAll(resp.Body)
-		responseJSON := string(responseBody)
-		return fmt.Errorf("%s", responseJSON)
+		return resp.statusError()
 	}
 
 	return err
 }
 
 func (h *HarborOCI) DeleteProject(ctx context.Context, org string, displayName string) error {
-	URL := fmt.Sprintf("%s%s/%s", h.harborHost, HarborProjectsURL, HarborProjectName(org, displayName))
+	projectName, err := harborProject(org, displayName)
+	if err != nil {
+		return err
+	}
+	URL := fmt.Sprintf("%s%s/%s", h.harborHost, HarborProjectsURL, projectName)
 	resp, err := h.doHarborREST(ctx, http.MethodDelete, URL, nil, AddHeaders)
 	if err != nil {
 		return err
 	}
-	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
-		responseBody, _ := io.ReadAll(resp.Body)
-		responseJSON := string(responseBody)
-		return fmt.Errorf("error deleting project %s-%s: code %d message %s", org, displayName, resp.StatusCode, responseJSON)
+		responseJSON := string(resp.Body)
+		return resp.error(fmt.Errorf("error deleting project %s-%s: code %d message %s", org, displayName, resp.StatusCode, responseJSON))
 	}
 
 	return err
 }
 
+type HarborRepository struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	ProjectID     int    `json:"project_id"`
+	ArtifactCount int    `json:"artifact_count"`
+}
+
+func (h *HarborOCI) listRepositoriesPage(ctx context.Context, projectName string, page int) ([]HarborRepository, bool, error) {
+	URL := fmt.Sprintf("%s%s/%s/repositories?page=%d&page_size=%d", h.harborHost, HarborProjectsURL, projectName, page, harborPageSize)
+	resp, err := h.doHarborREST(ctx, http.MethodGet, URL, nil, AddHeaders)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		// project is already gone, so there is nothing to list
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		responseJSON := string(resp.Body)
+		return nil, false, resp.error(fmt.Errorf("error listing repositories for project %s: code %d message %s", projectName, resp.StatusCode, responseJSON))
+	}
+
+	repositories := []HarborRepository{}
+	err = json.Unmarshal(resp.Body, &repositories)
+	if err != nil {
+		return nil, false, err
+	}
+	return repositories, len(repositories) == harborPageSize, nil
+}
+
+// ListRepositories returns all repositories contained in the Harbor project for the given org and project.
+// A project that does not exist has no repositories.
+func (h *HarborOCI) ListRepositories(ctx context.Context, org string, displayName string) ([]HarborRepository, error) {
+	projectName, err := harborProject(org, displayName)
+	if err != nil {
+		return nil, err
+	}
+	repositories := []HarborRepository{}
+	for page := 1; ; page++ {
+		pageResults, more, err := h.listRepositoriesPage(ctx, projectName, page)
+		if err != nil {
+			return nil, err
+		}
+		repositories = append(repositories, pageResults...)
+		if !more {
+			break
+		}
+	}
+	return repositories, nil
+}
+
+// DeleteRepository deletes a repository and all of its artifacts from the Harbor project for the given org and project.
+// The repository name may be given with or without the leading project name, as returned by ListRepositories.
+func (h *HarborOCI) DeleteRepository(ctx context.Context, org string, displayName string, repositoryName string) error {
+	projectName, err := harborProject(org, displayName)
+	if err != nil {
+		return err
+	}
+	repositoryName = strings.TrimPrefix(repositoryName, projectName+"/")
+
+	// Harbor requires slashes in nested repository names to be double encoded
+	URL := fmt.Sprintf("%s%s/%s/repositories/%s", h.harborHost, HarborProjectsURL, projectName, url.PathEscape(url.PathEscape(repositoryName)))
+	resp, err := h.doHarborREST(ctx, http.MethodDelete, URL, nil, AddHeaders)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		responseJSON := string(resp.Body)
+		return resp.error(fmt.Errorf("error deleting repository %s in project %s: code %d message %s", repositoryName, projectName, resp.StatusCode, responseJSON))
+	}
+
+	return nil
+}
+
 func (h *HarborOCI) Ping(ctx context.Context) error {
 	URL := h.harborHost + HarborPingURL
 	resp, err := h.doHarborREST(ctx, http.MethodGet, URL, nil, NoHeaders)
 	if err != nil {
 		return err
 	}
-	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		responseBody, _ := io.ReadAll(resp.Body)
-		responseJSON := string(responseBody)
-		return fmt.Errorf("%s", responseJSON)
+		return resp.statusError()
 	}
 
 	return nil
 }
+
+// HarborStatistics is the usage of the whole Harbor instance, over all its projects
+type HarborStatistics struct {
+	TotalProjectCount int64 `json:"total_project_count"`
+	// bytes used by the artifacts of all projects
+	TotalStorageConsumption int64 `json:"total_storage_consumption"`
+}
+
+// Statistics returns the number of projects and the storage used by all Harbor projects.
+func (h *HarborOCI) Statistics(ctx context.Context) (HarborStatistics, error) {
+	statistics := HarborStatistics{}
+	resp, err := h.doHarborREST(ctx, http.MethodGet, h.harborHost+HarborStatisticsURL, nil, AddHeaders)
+	if err != nil {
+		return statistics, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return statistics, resp.statusError()
+	}
+	if err := json.Unmarshal(resp.Body, &statistics); err != nil {
+		return statistics, err
+	}
+	return statistics, nil
+}
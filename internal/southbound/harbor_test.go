@@ -4,17 +4,30 @@
 package southbound
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/golden"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/retry"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -40,10 +53,14 @@ func (s *HarborTestSuite) SetupTest() {
 	testServer.WithConfigurationHandler(configurationHandler).
 		WithProjectHandler(projectHandler).
 		WithRobotsHandler(robotsHandler).
+		WithRobotsRefreshHandler(robotsRefreshHandler).
 		WithProjectsGetRobotsHandler(projectsRobotsGetHandler).
 		WithProjectsDeleteRobotsHandler(projectsRobotsDeleteHandler).
 		WithPermissionsHandler(permissionsHandler).
-		WithPingHandler(pingHandler)
+		WithRepositoriesHandler(repositoriesHandler).
+		WithQuotasHandler(quotasHandler).
+		WithPingHandler(pingHandler).
+		WithSystemInfoHandler(systemInfoHandler("v2.10.0-a4d8ce3a"))
 }
 
 func (s *HarborTestSuite) TearDownTest() {
@@ -58,10 +75,14 @@ type TestHarborServer struct {
 	ConfigurationHandler        func(w http.ResponseWriter, r *http.Request)
 	ProjectHandler              func(w http.ResponseWriter, r *http.Request)
 	RobotsHandler               func(w http.ResponseWriter, r *http.Request)
+	RobotsRefreshHandler        func(w http.ResponseWriter, r *http.Request)
 	ProjectsRobotsGetHandler    func(w http.ResponseWriter, r *http.Request)
 	ProjectsRobotsDeleteHandler func(w http.ResponseWriter, r *http.Request)
 	ProjectsPermissionsHandler  func(w http.ResponseWriter, r *http.Request)
+	RepositoriesHandler         func(w http.ResponseWriter, r *http.Request)
+	QuotasHandler               func(w http.ResponseWriter, r *http.Request)
 	PingHandler                 func(w http.ResponseWriter, r *http.Request)
+	SystemInfoHandler           func(w http.ResponseWriter, r *http.Request)
 	Server                      *httptest.Server
 }
 
@@ -80,6 +101,11 @@ func (t *TestHarborServer) WithRobotsHandler(robotsHandler func(w http.ResponseW
 	return t
 }
 
+func (t *TestHarborServer) WithRobotsRefreshHandler(robotsRefreshHandler func(w http.ResponseWriter, r *http.Request)) *TestHarborServer {
+	t.RobotsRefreshHandler = robotsRefreshHandler
+	return t
+}
+
 func (t *TestHarborServer) WithProjectsGetRobotsHandler(projectsRobotsGetHandler func(w http.ResponseWriter, r *http.Request)) *TestHarborServer {
 	t.ProjectsRobotsGetHandler = projectsRobotsGetHandler
 	return t
@@ -95,11 +121,32 @@ func (t *TestHarborServer) WithPermissionsHandler(projectsPermissionsHandler fun
 	return t
 }
 
+func (t *TestHarborServer) WithRepositoriesHandler(repositoriesHandler func(w http.ResponseWriter, r *http.Request)) *TestHarborServer {
+	t.RepositoriesHandler = repositoriesHandler
+	return t
+}
+
+func (t *TestHarborServer) WithQuotasHandler(quotasHandler func(w http.ResponseWriter, r *http.Request)) *TestHarborServer {
+	t.QuotasHandler = quotasHandler
+	return t
+}
+
 func (t *TestHarborServer) WithPingHandler(pingHandler func(w http.ResponseWriter, r *http.Request)) *TestHarborServer {
 	t.PingHandler = pingHandler
 	return t
 }
 
+func (t *TestHarborServer) WithSystemInfoHandler(systemInfoHandler func(w http.ResponseWriter, r *http.Request)) *TestHarborServer {
+	t.SystemInfoHandler = systemInfoHandler
+	return t
+}
+
+func systemInfoHandler(version string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(HarborSystemInfo{HarborVersion: version})
+	}
+}
+
 func configurationHandler(w http.ResponseWriter, r *http.Request) {
 	b, _ := io.ReadAll(r.Body)
 	body := string(b)
@@ -124,6 +171,10 @@ func permissionsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// metadata of the project, and metadata keys that the project handler ignores when they are set
+var mockProjectMetadata = map[string]string{}
+var ignoredProjectMetadata = []string{}
+
 func projectHandler(w http.ResponseWriter, r *http.Request) {
 	b, _ := io.ReadAll(r.Body)
 	body := string(b)
@@ -133,9 +184,22 @@ func projectHandler(w http.ResponseWriter, r *http.Request) {
 	} else if r.Method == http.MethodDelete &&
 		strings.Contains(r.URL.Path, `catalog-apps-org-new-project`) {
 		w.WriteHeader(http.StatusOK)
+	} else if r.Method == http.MethodPut &&
+		strings.HasSuffix(r.URL.Path, `catalog-apps-org-new-project`) {
+		projectAttrs := UpdateProjectAttributes{}
+		if err := json.Unmarshal(b, &projectAttrs); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for key, value := range projectAttrs.Metadata {
+			if !slices.Contains(ignoredProjectMetadata, key) {
+				mockProjectMetadata[key] = value
+			}
+		}
+		w.WriteHeader(http.StatusOK)
 	} else if r.Method == http.MethodGet {
 		w.WriteHeader(http.StatusOK)
-		projectResults := HarborProject{ProjectID: 0}
+		projectResults := HarborProject{ProjectID: 0, Metadata: mockProjectMetadata}
 		_ = json.NewEncoder(w).Encode(projectResults)
 	} else {
 		w.WriteHeader(http.StatusBadRequest)
@@ -188,6 +252,19 @@ func robotsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func robotsRefreshHandler(w http.ResponseWriter, r *http.Request) {
+	URLSegments := strings.Split(r.URL.Path, "/")
+	robotID := URLSegments[len(URLSegments)-1]
+
+	for robotName := range mockRobots {
+		if strconv.Itoa(mockRobotIDs[robotName]) == robotID {
+			_ = json.NewEncoder(w).Encode(RobotSecret{Secret: "new-sekret-" + robotID})
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
 func projectsRobotsGetHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
@@ -213,6 +290,63 @@ func projectsRobotsDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+var mockRepositories = map[string]HarborRepository{}
+
+func repositoriesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if !strings.Contains(r.URL.Path, "catalog-apps-org-new-project") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+		names := make([]string, 0, len(mockRepositories))
+		for name := range mockRepositories {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		repositoriesResults := []HarborRepository{}
+		for i := (page - 1) * pageSize; i < len(names) && i < page*pageSize; i++ {
+			repositoriesResults = append(repositoriesResults, mockRepositories[names[i]])
+		}
+		_ = json.NewEncoder(w).Encode(repositoriesResults)
+	case http.MethodDelete:
+		URLSegments := strings.Split(r.URL.Path, "/")
+		repositoryName, _ := url.PathUnescape(URLSegments[len(URLSegments)-1])
+		fullName := "catalog-apps-org-new-project/" + repositoryName
+		if _, ok := mockRepositories[fullName]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(mockRepositories, fullName)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+var mockQuotas = map[int]int64{}
+
+func quotasHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		quotasResults := []HarborQuota{}
+		if r.URL.Query().Get("reference") == "project" && r.URL.Query().Get("reference_id") == "0" {
+			quotasResults = append(quotasResults, HarborQuota{ID: 7})
+		}
+		_ = json.NewEncoder(w).Encode(quotasResults)
+	case http.MethodPut:
+		URLSegments := strings.Split(r.URL.Path, "/")
+		quotaID, _ := strconv.Atoi(URLSegments[len(URLSegments)-1])
+		quotaAttrs := UpdateQuotaAttributes{}
+		if err := json.NewDecoder(r.Body).Decode(&quotaAttrs); err != nil || quotaID != 7 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mockQuotas[quotaID] = quotaAttrs.Hard["storage"]
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
 func (t *TestHarborServer) Start() *TestHarborServer {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == HarborConfigurationURL {
@@ -221,14 +355,22 @@ func (t *TestHarborServer) Start() *TestHarborServer {
 			t.RobotsHandler(w, r)
 		} else if strings.Contains(r.URL.Path, "robots") && r.Method == http.MethodGet {
 			t.ProjectsRobotsGetHandler(w, r)
+		} else if strings.HasPrefix(r.URL.Path, HarborRobotsURL+"/") && r.Method == http.MethodPatch {
+			t.RobotsRefreshHandler(w, r)
 		} else if strings.Contains(r.URL.Path, "robots") && r.Method == http.MethodDelete {
 			t.ProjectsRobotsDeleteHandler(w, r)
+		} else if strings.Contains(r.URL.Path, "/repositories") {
+			t.RepositoriesHandler(w, r)
+		} else if strings.Contains(r.URL.Path, HarborQuotasURL) {
+			t.QuotasHandler(w, r)
 		} else if strings.Contains(r.URL.Path, "/members") {
 			t.ProjectsPermissionsHandler(w, r)
 		} else if strings.Contains(r.URL.Path, HarborProjectsURL) {
 			t.ProjectHandler(w, r)
 		} else if strings.Contains(r.URL.Path, "ping") && r.Method == http.MethodGet {
 			t.PingHandler(w, r)
+		} else if r.URL.Path == HarborSystemInfoURL {
+			t.SystemInfoHandler(w, r)
 		}
 
 	}))
@@ -236,12 +378,16 @@ func (t *TestHarborServer) Start() *TestHarborServer {
 	return t
 }
 
+// testAdminSecret is the Harbor admin credential returned by testK8s
+var testAdminSecret = config.SecretRef{Namespace: "harbor", Name: "credential", Key: config.DefaultHarborAdminCredentialKey}
+
 type testK8s struct {
 }
 
 func (k *testK8s) ReadSecret(_ context.Context, _ string) (map[string][]byte, error) {
 	result := make(map[string][]byte)
 	result["credential"] = []byte(`admin:admin`)
+	result["admin-password"] = []byte(`admin`)
 	return result, nil
 }
 
@@ -252,10 +398,176 @@ func NewTestK8s(_ string) (K8s, error) {
 	return c, nil
 }
 
+func (s *HarborTestSuite) TestHarborAdminCredentials() {
+	// Read through the Kubernetes API
+	username, password, err := readHarborAdminCredentials(s.ctx, testAdminSecret)
+	s.NoError(err)
+	s.Equal("admin", username)
+	s.Equal("admin", password)
+
+	missingKey := testAdminSecret
+	missingKey.Key = "password"
+	_, _, err = readHarborAdminCredentials(s.ctx, missingKey)
+	s.ErrorIs(err, ErrPermanent)
+	s.ErrorContains(err, "no password found in secret harbor/credential")
+
+	// Read from a mounted secret, without the Kubernetes API
+	K8sFactory = func(_ string) (K8s, error) { return nil, fmt.Errorf("no access to secrets") }
+	defer func() { K8sFactory = NewTestK8s }()
+	mount := s.T().TempDir()
+	s.NoError(os.WriteFile(filepath.Join(mount, "credential"), []byte("harbor-admin:pass:word"), 0600))
+	mounted := config.SecretRef{Key: "credential", MountPath: mount}
+	username, password, err = readHarborAdminCredentials(s.ctx, mounted)
+	s.NoError(err)
+	s.Equal("harbor-admin", username)
+	s.Equal("pass:word", password)
+
+	s.NoError(os.WriteFile(filepath.Join(mount, "credential"), []byte("harbor-admin"), 0600))
+	_, _, err = readHarborAdminCredentials(s.ctx, mounted)
+	s.ErrorIs(err, ErrPermanent)
+
+	mounted.Key = "missing"
+	_, _, err = readHarborAdminCredentials(s.ctx, mounted)
+	s.ErrorIs(err, ErrPermanent)
+}
+
+func (s *HarborTestSuite) TestHarborReloadCredentials() {
+	mount := s.T().TempDir()
+	writeCredential := func(credential string) {
+		s.NoError(os.WriteFile(filepath.Join(mount, "credential"), []byte(credential), 0600))
+	}
+	writeCredential("admin:old")
+	password := "old"
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if username, pass, _ := r.BasicAuth(); username != "admin" || pass != password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	h, err := newHarbor(s.ctx, server.URL, "OIDC", config.SecretRef{Key: "credential", MountPath: mount})
+	s.NoError(err)
+	s.NoError(h.Configurations(s.ctx))
+	s.Len(bodies, 1)
+
+	// Harbor is given the rotated credential while the client still has the old one: the call is sent again
+	// with the same body once the new credential is read
+	password = "new"
+	writeCredential("admin:new")
+	bodies = nil
+	s.NoError(h.Configurations(s.ctx))
+	s.Len(bodies, 2)
+	s.Equal(bodies[0], bodies[1])
+	s.Contains(bodies[1], "registry-client")
+
+	changed, err := h.ReloadCredentials(s.ctx)
+	s.NoError(err)
+	s.False(changed)
+
+	// A credential Harbor rejects is not retried if it did not change
+	password = "newer"
+	bodies = nil
+	err = h.Configurations(s.ctx)
+	s.ErrorIs(err, ErrPermanent)
+	s.Len(bodies, 1)
+
+	writeCredential("admin:newer")
+	changed, err = h.ReloadCredentials(s.ctx)
+	s.NoError(err)
+	s.True(changed)
+	s.NoError(h.Configurations(s.ctx))
+
+	// The credential is kept if the secret cannot be read
+	writeCredential("admin")
+	_, err = h.ReloadCredentials(s.ctx)
+	s.ErrorIs(err, ErrPermanent)
+	s.NoError(h.Configurations(s.ctx))
+}
+
+// harborRequest is a Harbor REST call as recorded for the golden files
+type harborRequest struct {
+	Method string
+	URL    string
+	Body   json.RawMessage `json:",omitempty"`
+}
+
+// recordingTransport records the calls made through it, with their exact body
+type recordingTransport struct {
+	requests []harborRequest
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	request := harborRequest{Method: req.Method, URL: req.URL.RequestURI()}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		if len(body) > 0 {
+			request.Body = body
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	t.requests = append(t.requests, request)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// TestHarborGoldenRequests checks the calls made to Harbor to provision a project against the reviewed golden file
+// testdata/golden/harbor-provision-project.json. Run the test with -update to write it after an intended change, and
+// review the diff.
+func (s *HarborTestSuite) TestHarborGoldenRequests() {
+	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "https://keycloak.orch.example.com", testAdminSecret)
+	s.NoError(err)
+	recorder := &recordingTransport{}
+	h.client = newHarborClient(recorder, defaultHarborMiddleware...)
+
+	s.NoError(h.Configurations(s.ctx))
+	s.NoError(h.CreateProject(s.ctx, "org", "new-project", 1<<30))
+	s.NoError(h.SetMemberPermissions(s.ctx, 4, "org", "new-project", "org_new-project_Edge-Manager-Group"))
+	_, _, err = h.CreateRobot(s.ctx, "catalog-apps-read-write", "org", "new-project", config.DefaultHarborReadWriteAccess)
+	s.NoError(err)
+	_, _, err = h.CreateRobot(s.ctx, "catalog-apps-read-only", "org", "new-project", config.DefaultHarborPullAccess)
+	s.NoError(err)
+	s.NoError(h.SetProjectContentTrust(s.ctx, "org", "new-project", HarborContentTrust{Cosign: true}))
+
+	golden.Assert(s.T(), "harbor-provision-project", recorder.requests)
+}
+
+// closeRecordingBody records whether the response body was closed
+type closeRecordingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *closeRecordingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func (s *HarborTestSuite) TestHarborClosesResponseBody() {
+	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", testAdminSecret)
+	s.NoError(err)
+
+	// The body is closed without the middleware that reads it
+	body := &closeRecordingBody{Reader: strings.NewReader("{}")}
+	h.client = newHarborClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: body, Request: req}, nil
+	}))
+	resp, err := h.sendHarborREST(s.ctx, http.MethodGet, s.testServer.Server.URL+"/api/v2.0/configurations", nil, true)
+	s.NoError(err)
+	s.Equal("{}", string(resp.Body))
+	s.True(body.closed)
+}
+
 func (s *HarborTestSuite) TestHarborConfigurations() {
 	var err error
 
-	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", "harbor", "credential")
+	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", testAdminSecret)
 	s.NoError(err)
 
 	err = h.Configurations(s.ctx)
@@ -265,20 +577,20 @@ func (s *HarborTestSuite) TestHarborConfigurations() {
 func (s *HarborTestSuite) TestHarborCreateProject() {
 	var err error
 
-	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", "harbor", "credential")
+	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", testAdminSecret)
 	s.NoError(err)
 
-	err = h.CreateProject(s.ctx, "org", "new-project")
+	err = h.CreateProject(s.ctx, "org", "new-project", 0)
 	s.NoError(err)
 }
 
 func (s *HarborTestSuite) TestHarborCreateRobot() {
 	var err error
 
-	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", "harbor", "credential")
+	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", testAdminSecret)
 	s.NoError(err)
 
-	name, secret, err := h.CreateRobot(s.ctx, "new-robot", "org", "new-project")
+	name, secret, err := h.CreateRobot(s.ctx, "new-robot", "org", "new-project", config.DefaultHarborReadWriteAccess)
 	s.NoError(err)
 	s.Equal("robot$catalog-apps-org-new-project+new-robot", name)
 	s.Equal("super-sekret-shhh", secret)
@@ -294,32 +606,317 @@ func (s *HarborTestSuite) TestHarborCreateRobot() {
 	s.NotNil(robot)
 	s.Equal("robot$catalog-apps-org-new-project+new-robot", robot.Name)
 
+	secret, err = h.RefreshRobotSecret(s.ctx, robot.ID)
+	s.NoError(err)
+	s.Equal(fmt.Sprintf("new-sekret-%d", robot.ID), secret)
+
 	err = h.DeleteRobot(s.ctx, robot.ID)
 	s.NoError(err)
 	s.Len(mockRobots, 0)
 
+	_, err = h.RefreshRobotSecret(s.ctx, robot.ID)
+	s.Error(err)
+
 	robot, err = h.GetRobot(s.ctx, "org", "new-project", "new-robot", projectID)
 	s.Error(err)
 	s.Nil(robot)
 }
 
+func (s *HarborTestSuite) TestHarborNegotiateCapabilities() {
+	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", testAdminSecret)
+	s.NoError(err)
+
+	capabilities, err := h.NegotiateCapabilities(s.ctx)
+	s.NoError(err)
+	s.Equal(HarborCapabilities{Version: "v2.10", ScanStop: true}, capabilities)
+	s.Equal(1.0, testutil.ToFloat64(harborInfo.WithLabelValues("v2.10.0-a4d8ce3a")))
+
+	// An older Harbor does not give robots the permission to stop scans
+	s.testServer.WithSystemInfoHandler(systemInfoHandler("v2.6.2"))
+	capabilities, err = h.NegotiateCapabilities(s.ctx)
+	s.NoError(err)
+	s.False(capabilities.ScanStop)
+	s.True(capabilities.NotaryContentTrust)
+	name, _, err := h.CreateRobot(s.ctx, "old-robot", "org", "new-project", config.DefaultHarborReadWriteAccess)
+	s.NoError(err)
+	s.NotContains(mockRobots[name].Permissions[0].Access, RobotAccess{Resource: "scan", Action: "stop"})
+	s.Contains(mockRobots[name].Permissions[0].Access, RobotAccess{Resource: "scan", Action: "create"})
+	delete(mockRobots, name)
+
+	// Unsupported and unknown versions fail permanently
+	for _, version := range []string{"v2.1.0", "v1.10.17", "dev"} {
+		s.testServer.WithSystemInfoHandler(systemInfoHandler(version))
+		_, err = h.NegotiateCapabilities(s.ctx)
+		s.ErrorIs(err, ErrPermanent, version)
+	}
+	s.testServer.WithSystemInfoHandler(systemInfoHandler("v2.1.0"))
+	_, err = h.NegotiateCapabilities(s.ctx)
+	s.ErrorContains(err, "harbor v2.1.0 is not supported, the minimum supported version is v2.2")
+}
+
+func (s *HarborTestSuite) TestParseHarborVersion() {
+	version, err := ParseHarborVersion("v2.10.0-a4d8ce3a")
+	s.NoError(err)
+	s.Equal(HarborVersion{Major: 2, Minor: 10}, version)
+	version, err = ParseHarborVersion("2.2.1")
+	s.NoError(err)
+	s.Equal(HarborVersion{Major: 2, Minor: 2}, version)
+	s.True(version.AtLeast(MinHarborVersion))
+	s.False(HarborVersion{Major: 1, Minor: 10}.AtLeast(MinHarborVersion))
+	_, err = ParseHarborVersion("")
+	s.Error(err)
+}
+
+func (s *HarborTestSuite) TestHarborCreateRobotAccess() {
+	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", testAdminSecret)
+	s.NoError(err)
+
+	// The configured access is sent as is, without the default actions
+	access := []config.HarborRobotAccess{
+		{Resource: "repository", Actions: []string{"list", "pull", "push"}},
+		{Resource: "tag", Actions: []string{"list"}},
+	}
+	name, _, err := h.CreateRobot(s.ctx, "tight-robot", "org", "new-project", access)
+	s.NoError(err)
+	s.ElementsMatch([]RobotAccess{
+		{Resource: "repository", Action: "list"},
+		{Resource: "repository", Action: "pull"},
+		{Resource: "repository", Action: "push"},
+		{Resource: "tag", Action: "list"},
+	}, mockRobots[name].Permissions[0].Access)
+	delete(mockRobots, name)
+}
+
+func (s *HarborTestSuite) TestHarborCreatePullRobot() {
+	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", testAdminSecret)
+	s.NoError(err)
+
+	name, _, err := h.CreateRobot(s.ctx, "pull-robot", "org", "new-project", config.DefaultHarborPullAccess)
+	s.NoError(err)
+	s.Equal("robot$catalog-apps-org-new-project+pull-robot", name)
+
+	permissions := mockRobots[name].Permissions
+	s.Len(permissions, 1)
+	s.Equal("catalog-apps-org-new-project", permissions[0].Namespace)
+	for _, access := range permissions[0].Access {
+		s.Contains([]string{"list", "pull", "read"}, access.Action, "%s on %s", access.Action, access.Resource)
+	}
+	s.Contains(permissions[0].Access, RobotAccess{Resource: "repository", Action: "pull"})
+
+	robot, err := h.GetRobot(s.ctx, "org", "new-project", "pull-robot", 0)
+	s.NoError(err)
+	s.NoError(h.DeleteRobot(s.ctx, robot.ID))
+}
+
+func (s *HarborTestSuite) TestHarborGetRobotPaged() {
+	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", testAdminSecret)
+	s.NoError(err)
+
+	// 150 robots match the fuzzy name filter, the wanted one is on the second page
+	var queries []string
+	failing := false
+	s.testServer.RobotsHandler = func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		queries = append(queries, r.URL.Query().Get("q"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+		robots := []HarborRobot{}
+		for id := (page-1)*pageSize + 1; id <= min(page*pageSize, 150); id++ {
+			robots = append(robots, HarborRobot{ID: id, Name: fmt.Sprintf("robot$catalog-apps-org-proj+catalog-apps-%d", id)})
+		}
+		_ = json.NewEncoder(w).Encode(robots)
+	}
+
+	robot, err := h.GetRobot(s.ctx, "org", "proj", "catalog-apps-120", 7)
+	s.NoError(err)
+	s.Equal(120, robot.ID)
+	s.Equal([]string{"Level=project,ProjectID=7,name=~catalog-apps-120", "Level=project,ProjectID=7,name=~catalog-apps-120"}, queries)
+
+	// A missing robot is told apart from a failed lookup
+	robot, err = h.GetRobot(s.ctx, "org", "proj", "catalog-apps-200", 7)
+	s.Nil(robot)
+	s.ErrorIs(err, ErrNotFound)
+	s.False(IsRetryable(err))
+
+	failing = true
+	_, err = h.GetRobot(s.ctx, "org", "proj", "catalog-apps-120", 7)
+	s.Error(err)
+	s.NotErrorIs(err, ErrNotFound)
+	s.True(IsRetryable(err))
+}
+
 func (s *HarborTestSuite) TestHarborPermissions() {
 	var err error
 
-	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", "harbor", "credential")
+	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", testAdminSecret)
 	s.NoError(err)
 
 	err = h.SetMemberPermissions(s.ctx, 3, "org", "new-project", "new-project")
 	s.NoError(err)
 }
 
+func (s *HarborTestSuite) TestHarborSetMemberRole() {
+	var updates []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == HarborProjectsURL+"/catalog-apps-org-proj/members":
+			// Harbor matches the name fuzzily, so that a user and another group are returned as well
+			_ = json.NewEncoder(w).Encode([]HarborMember{
+				{ID: 1, EntityName: r.URL.Query().Get("entityname"), EntityType: "u", RoleID: 1},
+				{ID: 2, EntityName: r.URL.Query().Get("entityname") + "-2", EntityType: "g", RoleID: 4},
+				{ID: 3, EntityName: "org_proj_Manager", EntityType: "g", RoleID: 4},
+			})
+		case r.Method == http.MethodPut:
+			updates = append(updates, r.URL.Path+" "+string(body))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	h, err := newHarbor(s.ctx, server.URL, "OIDC", testAdminSecret)
+	s.NoError(err)
+	s.NoError(h.SetMemberRole(s.ctx, 5, "org", "proj", "org_proj_Manager"))
+	s.Equal([]string{HarborProjectsURL + `/catalog-apps-org-proj/members/3 {"role_id":5}`}, updates)
+
+	// A member that has the role already is not updated
+	updates = nil
+	s.NoError(h.SetMemberRole(s.ctx, 4, "org", "proj", "org_proj_Manager"))
+	s.Empty(updates)
+
+	err = h.SetMemberRole(s.ctx, 5, "org", "proj", "org_proj_Operator")
+	s.ErrorIs(err, ErrNotFound)
+	s.ErrorIs(err, ErrPermanent)
+	s.Error(h.SetMemberRole(s.ctx, 5, "org", "other", "org_other_Manager"))
+}
+
+func (s *HarborTestSuite) TestHarborSetRobotDisabled() {
+	stored := `{"id":7,"name":"robot$catalog-apps-org-proj+catalog-apps-read-write","description":"CI push robot",` +
+		`"level":"project","duration":-1,"disable":false,"editable":true,"expires_at":-1,"secret":"",` +
+		`"permissions":[{"kind":"project","namespace":"catalog-apps-org-proj","access":[{"action":"push","resource":"repository"}]}],` +
+		`"creation_time":"2026-01-02T03:04:05Z","update_time":"2026-01-02T03:04:05Z"}`
+	var updates []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == HarborRobotsURL+"/7":
+			_, _ = w.Write([]byte(stored))
+		case r.Method == http.MethodPut && r.URL.Path == HarborRobotsURL+"/7":
+			update := map[string]interface{}{}
+			s.NoError(json.NewDecoder(r.Body).Decode(&update))
+			updates = append(updates, update)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	h, err := newHarbor(s.ctx, server.URL, "OIDC", testAdminSecret)
+	s.NoError(err)
+	robot := HarborRobot{ID: 7, Name: "robot$catalog-apps-org-proj+catalog-apps-read-write", Level: "project", Duration: -1}
+	s.NoError(h.SetRobotDisabled(s.ctx, robot, true))
+	s.Require().Len(updates, 1)
+	// The robot is sent with every field Harbor returned, so that it keeps its description and permissions
+	expected := map[string]interface{}{}
+	s.NoError(json.Unmarshal([]byte(stored), &expected))
+	expected["disable"] = true
+	s.Equal(expected, updates[0])
+	robot.Disable = true
+
+	// A robot that is disabled already is not updated
+	s.NoError(h.SetRobotDisabled(s.ctx, robot, true))
+	s.Len(updates, 1)
+	s.NoError(h.SetRobotDisabled(s.ctx, robot, false))
+	s.Len(updates, 2)
+	s.Equal(false, updates[1]["disable"])
+	s.Equal("CI push robot", updates[1]["description"])
+
+	robot.ID = 8
+	s.ErrorIs(h.SetRobotDisabled(s.ctx, robot, false), ErrNotFound)
+}
+
+func (s *HarborTestSuite) TestHarborProjectLabels() {
+	labels := map[int]HarborLabel{
+		3: {ID: 3, Name: "extension", Description: "old", Color: "#000000", Scope: "p", ProjectID: 1234},
+		4: {ID: 4, Name: "customer-app", Scope: "p", ProjectID: 1234},
+	}
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == HarborLabelsURL:
+			s.Equal("p", r.URL.Query().Get("scope"))
+			result := []HarborLabel{}
+			for _, id := range slices.Sorted(maps.Keys(labels)) {
+				if strconv.Itoa(labels[id].ProjectID) == r.URL.Query().Get("project_id") {
+					result = append(result, labels[id])
+				}
+			}
+			_ = json.NewEncoder(w).Encode(result)
+		case r.Method == http.MethodPost && r.URL.Path == HarborLabelsURL:
+			label := HarborLabel{}
+			s.NoError(json.NewDecoder(r.Body).Decode(&label))
+			label.ID = 10
+			labels[label.ID] = label
+			w.Header().Set("Location", HarborLabelsURL+"/10")
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPut:
+			label := HarborLabel{}
+			s.NoError(json.NewDecoder(r.Body).Decode(&label))
+			labels[label.ID] = label
+		case r.Method == http.MethodDelete && r.URL.Path == HarborLabelsURL+"/4":
+			delete(labels, 4)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	h, err := newHarbor(s.ctx, server.URL, "OIDC", testAdminSecret)
+	s.NoError(err)
+
+	// A label with another description or color is updated in place
+	id, err := h.SetProjectLabel(s.ctx, 1234, config.HarborLabel{Name: "extension", Description: "Extension", Color: "#0065FF"})
+	s.NoError(err)
+	s.Equal(3, id)
+	s.Equal(HarborLabel{ID: 3, Name: "extension", Description: "Extension", Color: "#0065FF", Scope: "p", ProjectID: 1234}, labels[3])
+
+	// A label that is up to date is left alone
+	requests = nil
+	id, err = h.SetProjectLabel(s.ctx, 1234, config.HarborLabel{Name: "customer-app"})
+	s.NoError(err)
+	s.Equal(4, id)
+	s.Equal([]string{"GET " + HarborLabelsURL}, requests)
+
+	// A missing label is created in the project
+	id, err = h.SetProjectLabel(s.ctx, 1234, config.HarborLabel{Name: "certified", Color: "#00B86B"})
+	s.NoError(err)
+	s.Equal(10, id)
+	s.Equal(HarborLabel{ID: 10, Name: "certified", Color: "#00B86B", Scope: "p", ProjectID: 1234}, labels[10])
+
+	projectLabels, err := h.ListProjectLabels(s.ctx, 1234)
+	s.NoError(err)
+	s.Len(projectLabels, 3)
+	projectLabels, err = h.ListProjectLabels(s.ctx, 99)
+	s.NoError(err)
+	s.Empty(projectLabels)
+
+	// Deleting a label that is gone already succeeds
+	s.NoError(h.DeleteLabel(s.ctx, 4))
+	s.NotContains(labels, 4)
+	s.NoError(h.DeleteLabel(s.ctx, 4))
+}
+
 func (s *HarborTestSuite) TestHarborDeleteProject() {
 	var err error
 
-	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", "harbor", "credential")
+	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", testAdminSecret)
 	s.NoError(err)
 
-	err = h.CreateProject(s.ctx, "org", "new-project")
+	err = h.CreateProject(s.ctx, "org", "new-project", 0)
 	s.NoError(err)
 	err = h.DeleteProject(s.ctx, "org", "new-project")
 	s.NoError(err)
@@ -328,10 +925,139 @@ func (s *HarborTestSuite) TestHarborDeleteProject() {
 	s.Contains(err.Error(), "error deleting project org-nobody-home")
 }
 
+func (s *HarborTestSuite) TestHarborEmptyProjectName() {
+	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", testAdminSecret)
+	s.NoError(err)
+	recorder := &recordingTransport{}
+	h.client = newHarborClient(recorder, defaultHarborMiddleware...)
+
+	// No call is made for a Harbor project with an empty organization or project name
+	for _, names := range [][2]string{{"", "project"}, {"org", ""}, {" ", "project"}} {
+		org, project := names[0], names[1]
+		s.ErrorIs(h.CreateProject(s.ctx, org, project, 0), ErrPermanent)
+		s.ErrorIs(h.SetProjectStorageLimit(s.ctx, org, project, 1<<30), ErrPermanent)
+		s.ErrorIs(h.SetMemberPermissions(s.ctx, 4, org, project, "group"), ErrPermanent)
+		s.ErrorIs(h.SetProjectContentTrust(s.ctx, org, project, HarborContentTrust{Cosign: true}), ErrPermanent)
+		_, _, err = h.CreateRobot(s.ctx, "robot", org, project, config.DefaultHarborPullAccess)
+		s.ErrorIs(err, ErrPermanent)
+		_, err = h.GetRobot(s.ctx, org, project, "robot", 1)
+		s.ErrorIs(err, ErrPermanent)
+		_, err = h.ListRepositories(s.ctx, org, project)
+		s.ErrorIs(err, ErrPermanent)
+		s.ErrorIs(h.DeleteRepository(s.ctx, org, project, "repository"), ErrPermanent)
+		s.ErrorIs(h.DeleteProject(s.ctx, org, project), ErrPermanent)
+	}
+	s.Empty(recorder.requests)
+}
+
+func (s *HarborTestSuite) TestHarborPurgeRepositories() {
+	var err error
+
+	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", testAdminSecret)
+	s.NoError(err)
+
+	mockRepositories = map[string]HarborRepository{}
+	for i := 0; i < harborPageSize+5; i++ {
+		name := fmt.Sprintf("catalog-apps-org-new-project/charts/app-%03d", i)
+		mockRepositories[name] = HarborRepository{ID: i, Name: name, ArtifactCount: 1}
+	}
+
+	repositories, err := h.ListRepositories(s.ctx, "org", "new-project")
+	s.NoError(err)
+	s.Len(repositories, harborPageSize+5)
+
+	for _, repository := range repositories {
+		err = h.DeleteRepository(s.ctx, "org", "new-project", repository.Name)
+		s.NoError(err)
+	}
+	s.Len(mockRepositories, 0)
+
+	repositories, err = h.ListRepositories(s.ctx, "org", "new-project")
+	s.NoError(err)
+	s.Len(repositories, 0)
+
+	// missing project has nothing to purge
+	repositories, err = h.ListRepositories(s.ctx, "org", "nobody-home")
+	s.NoError(err)
+	s.Len(repositories, 0)
+}
+
+func (s *HarborTestSuite) TestHarborSetProjectStorageLimit() {
+	var err error
+
+	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", testAdminSecret)
+	s.NoError(err)
+
+	mockQuotas = map[int]int64{}
+	err = h.SetProjectStorageLimit(s.ctx, "org", "new-project", 1024)
+	s.NoError(err)
+	s.Equal(int64(1024), mockQuotas[7])
+}
+
+func (s *HarborTestSuite) TestHarborSetProjectContentTrust() {
+	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", testAdminSecret)
+	s.NoError(err)
+
+	mockProjectMetadata = map[string]string{}
+	s.NoError(h.SetProjectContentTrust(s.ctx, "org", "new-project", HarborContentTrust{Cosign: true}))
+	s.Equal(map[string]string{HarborMetadataContentTrustCosign: "true"}, mockProjectMetadata)
+
+	// Harbor without Notary cannot require notation signatures, the legacy metadata is not set
+	_, err = h.NegotiateCapabilities(s.ctx)
+	s.NoError(err)
+	err = h.SetProjectContentTrust(s.ctx, "org", "new-project", HarborContentTrust{Cosign: true, Notation: true})
+	s.ErrorIs(err, ErrPermanent)
+	s.ErrorContains(err, "harbor v2.10 cannot require notation signatures")
+	s.NotContains(mockProjectMetadata, HarborMetadataContentTrust)
+
+	// An older Harbor enforces them with Notary
+	s.testServer.WithSystemInfoHandler(systemInfoHandler("v2.8.4"))
+	defer s.testServer.WithSystemInfoHandler(systemInfoHandler("v2.10.0-a4d8ce3a"))
+	_, err = h.NegotiateCapabilities(s.ctx)
+	s.NoError(err)
+	s.NoError(h.SetProjectContentTrust(s.ctx, "org", "new-project", HarborContentTrust{Cosign: true, Notation: true}))
+	s.Equal(map[string]string{HarborMetadataContentTrust: "true", HarborMetadataContentTrustCosign: "true"}, mockProjectMetadata)
+	s.NoError(h.SetProjectContentTrust(s.ctx, "org", "new-project", HarborContentTrust{Cosign: true}))
+	s.Equal("false", mockProjectMetadata[HarborMetadataContentTrust])
+
+	// Metadata that Harbor leaves out is not enabled
+	mockProjectMetadata = map[string]string{}
+	ignoredProjectMetadata = []string{HarborMetadataContentTrust, HarborMetadataContentTrustCosign}
+	defer func() { ignoredProjectMetadata = []string{} }()
+	s.NoError(h.SetProjectContentTrust(s.ctx, "org", "new-project", HarborContentTrust{}))
+
+	// A setting that Harbor does not apply is detected by the read-back
+	err = h.SetProjectContentTrust(s.ctx, "org", "new-project", HarborContentTrust{Cosign: true})
+	s.ErrorIs(err, ErrPermanent)
+	s.ErrorContains(err, `has enable_content_trust_cosign "false" instead of "true"`)
+
+	s.Error(h.SetProjectContentTrust(s.ctx, "org", "missing-project", HarborContentTrust{}))
+}
+
+func (s *HarborTestSuite) TestHarborStatistics() {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal(HarborStatisticsURL, r.URL.Path)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"private_project_count": 12, "total_project_count": 14, "total_repo_count": 40, "total_storage_consumption": 1073741824}`))
+	}))
+	defer server.Close()
+
+	h, err := newHarbor(s.ctx, server.URL, "OIDC", testAdminSecret)
+	s.NoError(err)
+	statistics, err := h.Statistics(s.ctx)
+	s.NoError(err)
+	s.Equal(HarborStatistics{TotalProjectCount: 14, TotalStorageConsumption: 1073741824}, statistics)
+
+	status = http.StatusServiceUnavailable
+	_, err = h.Statistics(s.ctx)
+	s.ErrorIs(err, ErrTransient)
+}
+
 func (s *HarborTestSuite) TestHarborPing() {
 	var err error
 
-	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", "harbor", "credential")
+	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", testAdminSecret)
 	s.NoError(err)
 
 	err = h.Ping(s.ctx)
@@ -340,4 +1066,155 @@ func (s *HarborTestSuite) TestHarborPing() {
 	errorOnPing = true
 	err = h.Ping(s.ctx)
 	s.Error(err)
+	s.ErrorIs(err, ErrTransient)
+}
+
+func (s *HarborTestSuite) TestHarborRequestTimeout() {
+	// A Harbor that never responds
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	h, err := newHarbor(s.ctx, server.URL, "OIDC", testAdminSecret)
+	s.NoError(err)
+	h.SetRequestTimeout(50 * time.Millisecond)
+
+	// The call fails on its own timeout with a transient error, its context is not done
+	start := time.Now()
+	err = h.Ping(s.ctx)
+	s.ErrorIs(err, ErrTransient)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Less(time.Since(start), 5*time.Second)
+	s.NoError(s.ctx.Err())
+}
+
+func (s *HarborTestSuite) TestHarborErrorClassification() {
+	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", testAdminSecret)
+	s.NoError(err)
+
+	tests := []struct {
+		statusCode int
+		class      error
+	}{
+		{http.StatusBadRequest, ErrPermanent},
+		{http.StatusUnauthorized, ErrPermanent},
+		{http.StatusConflict, ErrConflict},
+		{http.StatusTooManyRequests, ErrTransient},
+		{http.StatusServiceUnavailable, ErrTransient},
+	}
+	for _, tt := range tests {
+		s.testServer.WithConfigurationHandler(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(tt.statusCode)
+			_, _ = w.Write([]byte(`{"errors":[{"code":"ERROR","message":"configuration rejected"}]}`))
+		})
+		err = h.Configurations(s.ctx)
+		s.ErrorIs(err, tt.class, http.StatusText(tt.statusCode))
+		s.Contains(err.Error(), "configuration rejected")
+	}
+
+	// Harbor asking to slow down is throttled for the time it gave
+	s.testServer.WithConfigurationHandler(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	err = h.Configurations(s.ctx)
+	s.ErrorIs(err, ErrThrottled)
+	var throttled retry.Throttled
+	s.ErrorAs(err, &throttled)
+	s.Equal(7*time.Second, throttled.RetryAfter())
+
+	// Connection failures are transient
+	s.testServer.Server.Close()
+	err = h.Ping(s.ctx)
+	s.ErrorIs(err, ErrTransient)
+}
+
+// harborRequestCount returns the number of Harbor REST calls recorded for the method, endpoint and code
+func harborRequestCount(method string, endpoint string, code string) uint64 {
+	metric := &dto.Metric{}
+	_ = harborRequestDuration.WithLabelValues(method, endpoint, code).(prometheus.Metric).Write(metric)
+	return metric.GetSummary().GetSampleCount()
+}
+
+func (s *HarborTestSuite) TestHarborRequestMiddleware() {
+	h, err := newHarbor(s.ctx, s.testServer.Server.URL, "OIDC", testAdminSecret)
+	s.NoError(err)
+
+	var requestIDs []string
+	s.testServer.WithConfigurationHandler(func(w http.ResponseWriter, r *http.Request) {
+		requestIDs = append(requestIDs, r.Header.Get(HarborRequestIDHeader))
+		w.WriteHeader(http.StatusOK)
+	})
+	calls := harborRequestCount(http.MethodPut, "/api/v2.0/configurations", "200")
+	requests := testutil.ToFloat64(southboundRequests.WithLabelValues(ServiceHarbor, "PUT /api/v2.0/configurations", "200"))
+
+	s.NoError(h.Configurations(WithRequestID(s.ctx, "request-1")))
+	s.NoError(h.Configurations(s.ctx))
+	s.Len(requestIDs, 2)
+	s.Equal("request-1", requestIDs[0])
+	s.Len(requestIDs[1], 16)
+	s.Equal(calls+2, harborRequestCount(http.MethodPut, "/api/v2.0/configurations", "200"))
+	s.Equal(requests+2, testutil.ToFloat64(southboundRequests.WithLabelValues(ServiceHarbor, "PUT /api/v2.0/configurations", "200")))
+
+	// Failed connections are recorded without a status code
+	failures := harborRequestCount(http.MethodGet, "/api/v2.0/ping", "error")
+	s.testServer.Server.Close()
+	s.Error(h.Ping(s.ctx))
+	s.Equal(failures+1, harborRequestCount(http.MethodGet, "/api/v2.0/ping", "error"))
+}
+
+func (s *HarborTestSuite) TestHarborMiddlewareOrder() {
+	var order []string
+	recorder := func(name string) HarborMiddleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+	client := newHarborClient(http.DefaultTransport, recorder("first"), recorder("second"))
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodGet, s.testServer.Server.URL+HarborPingURL, nil)
+	s.NoError(err)
+	resp, err := client.Do(req)
+	s.NoError(err)
+	_ = resp.Body.Close()
+	s.Equal([]string{"first", "second"}, order)
+}
+
+func (s *HarborTestSuite) TestHarborEndpoint() {
+	tests := map[string]string{
+		"/api/v2.0/configurations":                                         "/api/v2.0/configurations",
+		"/api/v2.0/projects":                                               "/api/v2.0/projects",
+		"/api/v2.0/projects/catalog-apps-org-project":                      "/api/v2.0/projects/{project}",
+		"/api/v2.0/projects/catalog-apps-org-project/members":              "/api/v2.0/projects/{project}/members",
+		"/api/v2.0/projects/catalog-apps-org-project/repositories":         "/api/v2.0/projects/{project}/repositories",
+		"/api/v2.0/projects/catalog-apps-org-project/repositories/a%252Fb": "/api/v2.0/projects/{project}/repositories/{name}",
+		"/api/v2.0/robots/12":                                              "/api/v2.0/robots/{id}",
+		"/api/v2.0/quotas/7":                                               "/api/v2.0/quotas/{id}",
+		"/v2/_catalog":                                                     "other",
+	}
+	for path, endpoint := range tests {
+		s.Equal(endpoint, harborEndpoint(path), path)
+	}
+}
+
+func (s *HarborTestSuite) TestHarborLogBody() {
+	s.Equal("<empty>", logBody(nil))
+
+	body := logBody([]byte(`{"name":"robot$catalog-apps-org-project+robot","secret":"s3cr\"et","oidc_client_secret": "abc","password":"pw","id":1}`))
+	s.NotContains(body, "s3cr")
+	s.NotContains(body, "abc")
+	s.NotContains(body, "pw")
+	s.Contains(body, `"secret":"<redacted>"`)
+	s.Contains(body, `"name":"robot$catalog-apps-org-project+robot"`)
+	s.Contains(body, `"id":1`)
+
+	body = logBody([]byte(strings.Repeat("x", maxLoggedBodySize+10)))
+	s.True(strings.HasPrefix(body, strings.Repeat("x", maxLoggedBodySize)+"..."))
+	s.Contains(body, "truncated 10 bytes")
 }
@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	coreV1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	coreV1Types "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// InventoryConfigMapPrefix is followed by the project UUID in the name of the inventory ConfigMap of a project
+	InventoryConfigMapPrefix = "tenant-inventory-"
+	// InventoryKey is the ConfigMap key holding the inventory document
+	InventoryKey = "inventory.json"
+	// InventoryLabel is set on all inventory ConfigMaps, so that they can be listed
+	InventoryLabel = "app-orch-tenant-controller/inventory"
+)
+
+// Inventory lists the resources created for a project by the tenant controller. ExtensionHashes holds the content
+// hashes of the extension packages by name:version, so that unchanged packages are not uploaded again.
+type Inventory struct {
+	Organization      string           `json:"organization"`
+	Project           string           `json:"project"`
+	UUID              string           `json:"uuid"`
+	HarborProject     *InventoryHarbor `json:"harborProject,omitempty"`
+	CatalogRegistries []string         `json:"catalogRegistries,omitempty"`
+	// added to the names of the catalog registries when they were created
+	CatalogRegistryPrefix string                  `json:"catalogRegistryPrefix,omitempty"`
+	CatalogRegistrySuffix string                  `json:"catalogRegistrySuffix,omitempty"`
+	StarterApps           []string                `json:"starterApps,omitempty"`
+	ExtensionPackages     []InventoryPackage      `json:"extensionPackages,omitempty"`
+	ExtensionHashes       map[string]string       `json:"extensionHashes,omitempty"`
+	Deployments           []InventoryDeployment   `json:"deployments,omitempty"`
+	GitRepository         *InventoryGitRepository `json:"gitRepository,omitempty"`
+	Namespace             string                  `json:"namespace,omitempty"`
+	// set while the resources of a deleted project are kept for its retention period, nil otherwise
+	Deactivation *InventoryDeactivation `json:"deactivation,omitempty"`
+	Updated      time.Time              `json:"updated"`
+}
+
+// InventoryDeactivation is the deletion of a project whose resources were deactivated rather than deleted
+type InventoryDeactivation struct {
+	Deactivated time.Time `json:"deactivated"`
+	// when the resources are deleted, unless the project is restored before
+	PurgeAfter time.Time `json:"purgeAfter"`
+	// archive the Harbor project when the resources are deleted
+	RetainData bool `json:"retainData,omitempty"`
+}
+
+// InventoryHarbor is the Harbor project of a project and its robot accounts
+type InventoryHarbor struct {
+	ID     int              `json:"id"`
+	Name   string           `json:"name"`
+	Robots []InventoryRobot `json:"robots,omitempty"`
+	// labels created in the project to classify its artifacts
+	Labels []InventoryHarborLabel `json:"labels,omitempty"`
+	// when the project was deleted with its Harbor data retained, nil otherwise
+	Archived *time.Time `json:"archived,omitempty"`
+	// digest of the storage limit, member groups, robot access and labels the project was provisioned with
+	Settings string `json:"settings,omitempty"`
+}
+
+// InventoryRobot is a Harbor robot account. The ID is 0 if it could not be looked up.
+type InventoryRobot struct {
+	Name string `json:"name"`
+	ID   int    `json:"id,omitempty"`
+}
+
+// InventoryHarborLabel is a Harbor project label, with the ID that artifacts are labeled with.
+type InventoryHarborLabel struct {
+	Name string `json:"name"`
+	ID   int    `json:"id"`
+}
+
+// InventoryPackage is an extension deployment package uploaded to the catalog of the project
+type InventoryPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// InventoryDeployment is an ADM deployment of an extension
+type InventoryDeployment struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	AppName     string `json:"appName"`
+	AppVersion  string `json:"appVersion"`
+	ProfileName string `json:"profileName"`
+	// hash of the manifest definition the deployment was created from, empty if it is not known
+	Hash string `json:"hash,omitempty"`
+}
+
+// InventoryGitRepository is the GitOps repository of the project and its deploy key
+type InventoryGitRepository struct {
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	SSHURL      string `json:"sshURL"`
+	DeployKeyID int    `json:"deployKeyID,omitempty"`
+	// secret in the controller namespace holding the deploy key
+	Secret string `json:"secret"`
+}
+
+// InventoryStore keeps the inventory of each project in a ConfigMap named after the project UUID.
+type InventoryStore struct {
+	configMaps coreV1Types.ConfigMapInterface
+}
+
+// NewInventoryStore creates a store for the ConfigMaps of the namespace.
+func NewInventoryStore(config *rest.Config, namespace string) (*InventoryStore, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return newInventoryStore(clientset.CoreV1().ConfigMaps(namespace)), nil
+}
+
+func newInventoryStore(configMaps coreV1Types.ConfigMapInterface) *InventoryStore {
+	return &InventoryStore{configMaps: configMaps}
+}
+
+// Save creates or replaces the inventory of the project.
+func (s *InventoryStore) Save(ctx context.Context, inventory *Inventory) error {
+	document, err := json.MarshalIndent(inventory, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := InventoryConfigMapPrefix + inventory.UUID
+	configMap, err := s.configMaps.Get(ctx, name, metaV1.GetOptions{})
+	exists := err == nil
+	if apierrors.IsNotFound(err) {
+		configMap = &coreV1.ConfigMap{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{InventoryLabel: "true"},
+			},
+		}
+	} else if err != nil {
+		return k8sError(err)
+	}
+	configMap.Annotations = map[string]string{
+		"organization": inventory.Organization,
+		"project":      inventory.Project,
+	}
+	configMap.Data = map[string]string{InventoryKey: string(document)}
+	if exists {
+		_, err = s.configMaps.Update(ctx, configMap, metaV1.UpdateOptions{})
+	} else {
+		_, err = s.configMaps.Create(ctx, configMap, metaV1.CreateOptions{})
+	}
+	return k8sError(err)
+}
+
+// Load returns the inventory of the project, or nil if the project has none.
+func (s *InventoryStore) Load(ctx context.Context, uuid string) (*Inventory, error) {
+	configMap, err := s.configMaps.Get(ctx, InventoryConfigMapPrefix+uuid, metaV1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, k8sError(err)
+	}
+	inventory := &Inventory{}
+	if err := json.Unmarshal([]byte(configMap.Data[InventoryKey]), inventory); err != nil {
+		return nil, fmt.Errorf("%w: invalid inventory of project %s: %v", ErrPermanent, uuid, err)
+	}
+	return inventory, nil
+}
+
+// List returns the inventories of all projects. Inventories that cannot be read are skipped.
+func (s *InventoryStore) List(ctx context.Context) ([]*Inventory, error) {
+	configMaps, err := s.configMaps.List(ctx, metaV1.ListOptions{LabelSelector: InventoryLabel + "=true"})
+	if err != nil {
+		return nil, k8sError(err)
+	}
+	inventories := []*Inventory{}
+	for _, configMap := range configMaps.Items {
+		inventory := &Inventory{}
+		if err := json.Unmarshal([]byte(configMap.Data[InventoryKey]), inventory); err != nil {
+			log.Warnf("Skipping invalid inventory %s: %v", configMap.Name, err)
+			continue
+		}
+		inventories = append(inventories, inventory)
+	}
+	return inventories, nil
+}
+
+// Delete removes the inventory of the project. A missing inventory is not an error.
+func (s *InventoryStore) Delete(ctx context.Context, uuid string) error {
+	err := s.configMaps.Delete(ctx, InventoryConfigMapPrefix+uuid, metaV1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return k8sError(err)
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package southbound
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Suite of inventory store tests
+type InventoryTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *InventoryTestSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 1*time.Minute)
+}
+
+func (s *InventoryTestSuite) TearDownTest() {
+	s.cancel()
+}
+
+func TestInventory(t *testing.T) {
+	suite.Run(t, &InventoryTestSuite{})
+}
+
+func (s *InventoryTestSuite) TestSaveLoadDelete() {
+	configMaps := fake.NewClientset().CoreV1().ConfigMaps("orch-app")
+	store := newInventoryStore(configMaps)
+
+	inventory, err := store.Load(s.ctx, "uuid-1")
+	s.NoError(err)
+	s.Nil(inventory)
+
+	saved := &Inventory{
+		Organization: "org",
+		Project:      "proj",
+		UUID:         "uuid-1",
+		HarborProject: &InventoryHarbor{
+			ID:     10,
+			Name:   "catalog-apps-org-proj",
+			Robots: []InventoryRobot{{Name: "robot$catalog-apps-org-proj+catalog-apps-read-write", ID: 3}},
+		},
+		CatalogRegistries: []string{"harbor-helm-oci"},
+		Deployments:       []InventoryDeployment{{ID: "d-1", DisplayName: "base", AppName: "base", AppVersion: "1.0", ProfileName: "default"}},
+		Updated:           time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	s.NoError(store.Save(s.ctx, saved))
+	configMap, err := configMaps.Get(s.ctx, "tenant-inventory-uuid-1", metaV1.GetOptions{})
+	s.NoError(err)
+	s.Equal("true", configMap.Labels[InventoryLabel])
+	s.Equal("proj", configMap.Annotations["project"])
+	s.Contains(configMap.Data[InventoryKey], `"catalog-apps-org-proj"`)
+
+	inventory, err = store.Load(s.ctx, "uuid-1")
+	s.NoError(err)
+	s.Equal(saved, inventory)
+
+	// Saving again replaces the inventory
+	saved.Deployments = nil
+	s.NoError(store.Save(s.ctx, saved))
+	inventory, err = store.Load(s.ctx, "uuid-1")
+	s.NoError(err)
+	s.Empty(inventory.Deployments)
+
+	// Listing skips inventories that cannot be read
+	s.NoError(store.Save(s.ctx, &Inventory{Organization: "org", Project: "other", UUID: "uuid-2"}))
+	_, err = configMaps.Create(s.ctx, &coreV1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{Name: "tenant-inventory-uuid-3", Labels: map[string]string{InventoryLabel: "true"}},
+		Data:       map[string]string{InventoryKey: "{"},
+	}, metaV1.CreateOptions{})
+	s.NoError(err)
+	inventories, err := store.List(s.ctx)
+	s.NoError(err)
+	s.Len(inventories, 2)
+
+	s.NoError(store.Delete(s.ctx, "uuid-1"))
+	inventory, err = store.Load(s.ctx, "uuid-1")
+	s.NoError(err)
+	s.Nil(inventory)
+	s.NoError(store.Delete(s.ctx, "uuid-1"))
+}
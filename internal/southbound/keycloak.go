@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// keycloakRealm is the realm that issues the M2M service account tokens
+const keycloakRealm = "master"
+
+// keycloakClient makes the Keycloak health checks, counted in the southbound request metrics
+var keycloakClient = &http.Client{Transport: httpMetricsTransport(ServiceKeycloak, keycloakEndpoint, http.DefaultTransport)}
+
+// CheckKeycloak checks that the Keycloak service at the service base is up and serves the OpenID configuration of
+// the realm that issues the M2M service account tokens. Connection failures and server errors are transient; a
+// realm that is not found is a permanent error.
+func CheckKeycloak(ctx context.Context, serviceBase string) error {
+	url := strings.TrimSuffix(serviceBase, "/") + "/realms/" + keycloakRealm + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("%w: invalid Keycloak service base %q: %v", ErrPermanent, serviceBase, err)
+	}
+	resp, err := keycloakClient.Do(req)
+	if err != nil {
+		return requestError(ctx, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxHarborResponseSize))
+	if resp.StatusCode != http.StatusOK {
+		return httpResponseError(resp.StatusCode, resp.Header,
+			fmt.Errorf("keycloak realm %s returned %s", keycloakRealm, resp.Status))
+	}
+	return nil
+}
+
+// keycloakEndpoint returns the path of a Keycloak call with the realm name replaced by a placeholder.
+func keycloakEndpoint(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "realms" {
+			segments[i+1] = "{realm}"
+			return "/" + strings.Join(segments[i:], "/")
+		}
+	}
+	return "other"
+}
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package southbound
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Suite of Keycloak health check tests
+type KeycloakTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *KeycloakTestSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 1*time.Minute)
+}
+
+func (s *KeycloakTestSuite) TearDownTest() {
+	s.cancel()
+}
+
+func TestKeycloak(t *testing.T) {
+	suite.Run(t, &KeycloakTestSuite{})
+}
+
+// keycloakServer returns a Keycloak that answers the OpenID configuration of the master realm with the status code.
+func (s *KeycloakTestSuite) keycloakServer(statusCode int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/realms/master/.well-known/openid-configuration" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(`{"issuer":"http://keycloak/realms/master"}`))
+	}))
+	s.T().Cleanup(server.Close)
+	return server
+}
+
+func (s *KeycloakTestSuite) TestCheckKeycloak() {
+	endpoint := "GET /realms/{realm}/.well-known/openid-configuration"
+	calls := southboundRequestCount(ServiceKeycloak, endpoint, "200")
+
+	server := s.keycloakServer(http.StatusOK)
+	s.NoError(CheckKeycloak(s.ctx, server.URL))
+	s.NoError(CheckKeycloak(s.ctx, server.URL+"/"))
+	s.Equal(calls+2, southboundRequestCount(ServiceKeycloak, endpoint, "200"))
+}
+
+func (s *KeycloakTestSuite) TestCheckKeycloakErrors() {
+	// Keycloak starting up is retried
+	err := CheckKeycloak(s.ctx, s.keycloakServer(http.StatusServiceUnavailable).URL)
+	s.ErrorIs(err, ErrTransient)
+	s.Contains(err.Error(), "503")
+
+	// A missing realm does not fix itself
+	err = CheckKeycloak(s.ctx, s.keycloakServer(http.StatusNotFound).URL+"/auth")
+	s.ErrorIs(err, ErrPermanent)
+
+	// Keycloak not listening is retried
+	server := s.keycloakServer(http.StatusOK)
+	server.Close()
+	s.ErrorIs(CheckKeycloak(s.ctx, server.URL), ErrTransient)
+
+	// A check that is given up on is not classified
+	ctx, cancel := context.WithCancel(s.ctx)
+	cancel()
+	err = CheckKeycloak(ctx, s.keycloakServer(http.StatusOK).URL)
+	s.True(errors.Is(err, context.Canceled))
+	s.Nil(Classify(err))
+
+	s.ErrorIs(CheckKeycloak(s.ctx, "http://keycloak\x7f"), ErrPermanent)
+}
+
+func (s *KeycloakTestSuite) TestKeycloakEndpoint() {
+	tests := map[string]string{
+		"/realms/master/.well-known/openid-configuration":   "/realms/{realm}/.well-known/openid-configuration",
+		"/auth/realms/master/protocol/openid-connect/token": "/realms/{realm}/protocol/openid-connect/token",
+		"/realms/": "other",
+		"/health":  "other",
+	}
+	for path, endpoint := range tests {
+		s.Equal(endpoint, keycloakEndpoint(path), path)
+	}
+}
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"oras.land/oras-go/v2/registry/remote/retry"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Southbound services, as reported in the service label of the request metrics
+const (
+	ServiceCatalog        = "catalog"
+	ServiceADM            = "adm"
+	ServiceHarbor         = "harbor"
+	ServiceHarborRegistry = "harbor-registry"
+	ServiceReleaseService = "release-service"
+	ServiceKeycloak       = "keycloak"
+)
+
+var southboundRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "tenant_controller_southbound_requests_total",
+	Help: "Calls made to southbound services, by service, endpoint and HTTP or gRPC status code",
+}, []string{"service", "endpoint", "code"})
+
+func init() {
+	metrics.Registry.MustRegister(southboundRequests)
+}
+
+// grpcMetricsInterceptor counts the calls made to a gRPC service, by method and status code. It runs inside the
+// retrying interceptor, so that every attempt is counted.
+func grpcMetricsInterceptor(service string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		southboundRequests.WithLabelValues(service, path.Base(method), status.Code(err).String()).Inc()
+		return err
+	}
+}
+
+// httpMetricsTransport counts the calls made through the transport, by method, endpoint and status code. Calls that
+// failed without a response are counted with the code "error".
+func httpMetricsTransport(service string, endpoint func(path string) string, next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		code := "error"
+		if err == nil {
+			code = strconv.Itoa(resp.StatusCode)
+		}
+		southboundRequests.WithLabelValues(service, req.Method+" "+endpoint(req.URL.EscapedPath()), code).Inc()
+		return resp, err
+	})
+}
+
+// orasHTTPClient returns the retrying HTTP client used by ORAS, with the calls counted for the service. A retried
+// call is counted once, with the status of its last attempt.
+func orasHTTPClient(service string) *http.Client {
+	return &http.Client{Transport: httpMetricsTransport(service, ociEndpoint, retry.DefaultClient.Transport)}
+}
+
+// orasHTTPClientTLS returns the HTTP client of orasHTTPClient with the given TLS configuration. The proxy settings of
+// the environment still apply. A nil configuration uses the default one.
+func orasHTTPClientTLS(service string, tlsConfig *tls.Config) *http.Client {
+	if tlsConfig == nil {
+		return orasHTTPClient(service)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: httpMetricsTransport(service, ociEndpoint, retry.NewTransport(transport))}
+}
+
+// ociEndpoint returns the path of an OCI distribution API call with the repository name and reference replaced by
+// placeholders, so that the metric labels do not grow with the number of projects and artifacts.
+func ociEndpoint(path string) string {
+	if !strings.HasPrefix(path, "/v2/") {
+		return "other"
+	}
+	for _, kind := range []string{"manifests", "blobs/uploads", "blobs", "tags", "referrers"} {
+		if strings.LastIndex(path, "/"+kind+"/") > len("/v2") {
+			return "/v2/{name}/" + kind
+		}
+	}
+	return "/v2/"
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package southbound
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Suite of southbound metrics tests
+type MetricsTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *MetricsTestSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 1*time.Minute)
+}
+
+func (s *MetricsTestSuite) TearDownTest() {
+	s.cancel()
+}
+
+func TestMetrics(t *testing.T) {
+	suite.Run(t, &MetricsTestSuite{})
+}
+
+func southboundRequestCount(service string, endpoint string, code string) float64 {
+	return testutil.ToFloat64(southboundRequests.WithLabelValues(service, endpoint, code))
+}
+
+func (s *MetricsTestSuite) TestGRPCMetricsInterceptor() {
+	interceptor := grpcMetricsInterceptor(ServiceCatalog)
+	method := "/catalog.orchestrator.apis.v3.CatalogService/CreateRegistry"
+	calls := southboundRequestCount(ServiceCatalog, "CreateRegistry", "OK")
+	failures := southboundRequestCount(ServiceCatalog, "CreateRegistry", "Unavailable")
+
+	s.NoError(interceptor(s.ctx, method, nil, nil, nil, func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+		return nil
+	}))
+	err := interceptor(s.ctx, method, nil, nil, nil, func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+		return status.Error(codes.Unavailable, "catalog is down")
+	})
+	s.Equal(codes.Unavailable, status.Code(err))
+
+	s.Equal(calls+1, southboundRequestCount(ServiceCatalog, "CreateRegistry", "OK"))
+	s.Equal(failures+1, southboundRequestCount(ServiceCatalog, "CreateRegistry", "Unavailable"))
+}
+
+func (s *MetricsTestSuite) TestHTTPMetricsTransport() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	client := &http.Client{Transport: httpMetricsTransport(ServiceReleaseService, ociEndpoint, http.DefaultTransport)}
+	endpoint := "GET /v2/{name}/manifests"
+	calls := southboundRequestCount(ServiceReleaseService, endpoint, "503")
+	failures := southboundRequestCount(ServiceReleaseService, endpoint, "error")
+
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodGet, server.URL+"/v2/edge-node/en/manifest/manifests/latest", nil)
+	s.NoError(err)
+	resp, err := client.Do(req)
+	s.NoError(err)
+	_ = resp.Body.Close()
+	s.Equal(calls+1, southboundRequestCount(ServiceReleaseService, endpoint, "503"))
+
+	// Failed connections are counted without a status code
+	server.Close()
+	_, err = client.Do(req)
+	s.Error(err)
+	s.Equal(failures+1, southboundRequestCount(ServiceReleaseService, endpoint, "error"))
+}
+
+func (s *MetricsTestSuite) TestHarborPayloadSizes() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	client := newHarborClient(http.DefaultTransport, harborMetricsMiddleware)
+	sent := testutil.ToFloat64(southboundPayloadBytes.WithLabelValues(ServiceHarbor, "org"))
+
+	sizes := NewPayloadSizes("org")
+	ctx := WithPayloadSizes(s.ctx, sizes)
+	for _, body := range []string{`{"project_name":"catalog-apps-org-proj"}`, ""} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/api/v2.0/projects", strings.NewReader(body))
+		s.NoError(err)
+		resp, err := client.Do(req)
+		s.NoError(err)
+		_ = resp.Body.Close()
+	}
+	s.Equal(map[string]int64{ServiceHarbor: 40}, sizes.Bytes())
+	s.Equal(sent+40, testutil.ToFloat64(southboundPayloadBytes.WithLabelValues(ServiceHarbor, "org")))
+}
+
+func (s *MetricsTestSuite) TestOCIEndpoint() {
+	tests := map[string]string{
+		"/v2/": "/v2/",
+		"/v2/edge-node/en/manifest/manifests/latest":       "/v2/{name}/manifests",
+		"/v2/edge-node/dp/usb/blobs/sha256:abcd":           "/v2/{name}/blobs",
+		"/v2/catalog-apps-org-proj/app/blobs/uploads/":     "/v2/{name}/blobs/uploads",
+		"/v2/catalog-apps-org-proj/app/blobs/uploads/0a1b": "/v2/{name}/blobs/uploads",
+		"/v2/edge-node/dp/usb/tags/list":                   "/v2/{name}/tags",
+		"/v2/manifests/manifests/1.0":                      "/v2/{name}/manifests",
+		"/service/token":                                   "other",
+	}
+	for path, endpoint := range tests {
+		s.Equal(endpoint, ociEndpoint(path), path)
+	}
+}
@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"context"
+	"fmt"
+
+	coreV1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	coreV1Types "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// NamespaceProjectUUIDLabel is set to the project UUID on the namespace of a project
+	NamespaceProjectUUIDLabel = "app-orch-tenant-controller/project-uuid"
+	// NamespaceOrganizationLabel is set to the organization on the namespace of a project, if it is a valid label value
+	NamespaceOrganizationLabel = "app-orch-tenant-controller/organization"
+	// NamespaceManagedLabel is set on the namespaces created by the controller, which are deleted with their project.
+	// Namespaces that existed before are only labeled, and the labels are removed with the project
+	NamespaceManagedLabel = "app-orch-tenant-controller/managed"
+)
+
+// NamespaceStore creates and labels the namespaces of projects.
+type NamespaceStore struct {
+	namespaces coreV1Types.NamespaceInterface
+}
+
+// NewNamespaceStore creates a store for the namespaces of the cluster.
+func NewNamespaceStore(config *rest.Config) (*NamespaceStore, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return newNamespaceStore(clientset.CoreV1().Namespaces()), nil
+}
+
+func newNamespaceStore(namespaces coreV1Types.NamespaceInterface) *NamespaceStore {
+	return &NamespaceStore{namespaces: namespaces}
+}
+
+// namespaceLabels returns the labels of the namespace of a project.
+func namespaceLabels(uuid string, organization string) map[string]string {
+	labels := map[string]string{NamespaceProjectUUIDLabel: uuid}
+	if len(validation.IsValidLabelValue(organization)) == 0 {
+		labels[NamespaceOrganizationLabel] = organization
+	}
+	return labels
+}
+
+// Ensure creates the namespace of the project, labeled with its UUID and organization, or adds the labels to the
+// namespace if it already exists. It returns true if the namespace was created. A namespace labeled for another
+// project is not changed, and fails with a permanent error.
+func (s *NamespaceStore) Ensure(ctx context.Context, name string, uuid string, organization string, project string) (bool, error) {
+	namespace, err := s.namespaces.Get(ctx, name, metaV1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		labels := namespaceLabels(uuid, organization)
+		labels[NamespaceManagedLabel] = "true"
+		_, err = s.namespaces.Create(ctx, &coreV1.Namespace{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:   name,
+				Labels: labels,
+				Annotations: map[string]string{
+					"organization": organization,
+					"project":      project,
+				},
+			},
+		}, metaV1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			// Created meanwhile, e.g. by another component, so it is labeled instead
+			return s.Ensure(ctx, name, uuid, organization, project)
+		}
+		return err == nil, k8sError(err)
+	}
+	if err != nil {
+		return false, k8sError(err)
+	}
+	if owner := namespace.Labels[NamespaceProjectUUIDLabel]; owner != "" && owner != uuid {
+		return false, fmt.Errorf("%w: namespace %s belongs to project %s", ErrPermanent, name, owner)
+	}
+	if namespace.Labels == nil {
+		namespace.Labels = map[string]string{}
+	}
+	if namespace.Annotations == nil {
+		namespace.Annotations = map[string]string{}
+	}
+	changed := false
+	for key, value := range namespaceLabels(uuid, organization) {
+		if namespace.Labels[key] != value {
+			namespace.Labels[key] = value
+			changed = true
+		}
+	}
+	for key, value := range map[string]string{"organization": organization, "project": project} {
+		if namespace.Annotations[key] != value {
+			namespace.Annotations[key] = value
+			changed = true
+		}
+	}
+	if changed {
+		_, err = s.namespaces.Update(ctx, namespace, metaV1.UpdateOptions{})
+	}
+	return false, k8sError(err)
+}
+
+// Release deletes the namespace of the project if the controller created it, or else removes the labels of the
+// project from it. It returns true if the namespace was deleted. A missing namespace, or a namespace of another
+// project, is not an error and is left alone.
+func (s *NamespaceStore) Release(ctx context.Context, name string, uuid string) (bool, error) {
+	namespace, err := s.namespaces.Get(ctx, name, metaV1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, k8sError(err)
+	}
+	if namespace.Labels[NamespaceProjectUUIDLabel] != uuid {
+		log.Infof("Namespace %s is not labeled for project %s, leaving it alone", name, uuid)
+		return false, nil
+	}
+	if namespace.Labels[NamespaceManagedLabel] == "true" {
+		err = s.namespaces.Delete(ctx, name, metaV1.DeleteOptions{})
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return err == nil, k8sError(err)
+	}
+	delete(namespace.Labels, NamespaceProjectUUIDLabel)
+	delete(namespace.Labels, NamespaceOrganizationLabel)
+	delete(namespace.Annotations, "organization")
+	delete(namespace.Annotations, "project")
+	_, err = s.namespaces.Update(ctx, namespace, metaV1.UpdateOptions{})
+	return false, k8sError(err)
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package southbound
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	coreV1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Suite of namespace store tests
+type NamespacesTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *NamespacesTestSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 1*time.Minute)
+}
+
+func (s *NamespacesTestSuite) TearDownTest() {
+	s.cancel()
+}
+
+func TestNamespaces(t *testing.T) {
+	suite.Run(t, &NamespacesTestSuite{})
+}
+
+func (s *NamespacesTestSuite) TestCreateAndDelete() {
+	namespaces := fake.NewClientset().CoreV1().Namespaces()
+	store := newNamespaceStore(namespaces)
+
+	created, err := store.Ensure(s.ctx, "org-proj", "uuid-1", "org", "proj")
+	s.NoError(err)
+	s.True(created)
+	namespace, err := namespaces.Get(s.ctx, "org-proj", metaV1.GetOptions{})
+	s.NoError(err)
+	s.Equal(map[string]string{
+		NamespaceProjectUUIDLabel:  "uuid-1",
+		NamespaceOrganizationLabel: "org",
+		NamespaceManagedLabel:      "true",
+	}, namespace.Labels)
+	s.Equal("proj", namespace.Annotations["project"])
+
+	// Ensuring again leaves the namespace as it is
+	created, err = store.Ensure(s.ctx, "org-proj", "uuid-1", "org", "proj")
+	s.NoError(err)
+	s.False(created)
+
+	// The namespace of another project is left alone
+	_, err = store.Ensure(s.ctx, "org-proj", "uuid-2", "org", "proj")
+	s.ErrorIs(err, ErrPermanent)
+	deleted, err := store.Release(s.ctx, "org-proj", "uuid-2")
+	s.NoError(err)
+	s.False(deleted)
+
+	deleted, err = store.Release(s.ctx, "org-proj", "uuid-1")
+	s.NoError(err)
+	s.True(deleted)
+	_, err = namespaces.Get(s.ctx, "org-proj", metaV1.GetOptions{})
+	s.True(apierrors.IsNotFound(err))
+	deleted, err = store.Release(s.ctx, "org-proj", "uuid-1")
+	s.NoError(err)
+	s.False(deleted)
+}
+
+func (s *NamespacesTestSuite) TestLabelExisting() {
+	namespaces := fake.NewClientset(&coreV1.Namespace{ObjectMeta: metaV1.ObjectMeta{
+		Name:   "org-proj",
+		Labels: map[string]string{"team": "edge"},
+	}}).CoreV1().Namespaces()
+	store := newNamespaceStore(namespaces)
+
+	// Organizations that are not valid label values are only annotated
+	created, err := store.Ensure(s.ctx, "org-proj", "uuid-1", "Org Name", "proj")
+	s.NoError(err)
+	s.False(created)
+	namespace, err := namespaces.Get(s.ctx, "org-proj", metaV1.GetOptions{})
+	s.NoError(err)
+	s.Equal(map[string]string{"team": "edge", NamespaceProjectUUIDLabel: "uuid-1"}, namespace.Labels)
+	s.Equal("Org Name", namespace.Annotations["organization"])
+
+	// An existing namespace is kept, without the labels of the project
+	deleted, err := store.Release(s.ctx, "org-proj", "uuid-1")
+	s.NoError(err)
+	s.False(deleted)
+	namespace, err = namespaces.Get(s.ctx, "org-proj", metaV1.GetOptions{})
+	s.NoError(err)
+	s.Equal(map[string]string{"team": "edge"}, namespace.Labels)
+	s.Empty(namespace.Annotations)
+}
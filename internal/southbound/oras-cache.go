@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Artifacts are pulled to temporary directories with this prefix in the download cache area, the temporary
+// directory of the process
+const orasTempPrefix = "repo"
+
+var (
+	orasCacheBytes = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "tenant_controller_oras_cache_bytes",
+		Help: "Bytes used by the artifacts pulled from the release service in the download cache area, including orphaned directories",
+	}, func() float64 {
+		_, bytes := OrasCacheUsage()
+		return float64(bytes)
+	})
+
+	orasCacheDirectories = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "tenant_controller_oras_cache_directories",
+		Help: "Directories artifacts are pulled to in the download cache area, including orphaned directories",
+	}, func() float64 {
+		directories, _ := OrasCacheUsage()
+		return float64(directories)
+	})
+
+	orasSweptDirectories = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "tenant_controller_oras_swept_directories_total",
+		Help: "Orphaned directories removed from the download cache area, left by a process that stopped mid-pull",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(orasCacheBytes, orasCacheDirectories, orasSweptDirectories)
+}
+
+// orasTempDirs are the directories of the download cache area used by the Oras clients of this process
+var orasTempDirs = struct {
+	sync.Mutex
+	dirs map[string]bool
+}{dirs: map[string]bool{}}
+
+// newOrasTempDir creates a directory to pull an artifact to and registers it, so that it is not swept.
+func newOrasTempDir() (string, error) {
+	orasTempDirs.Lock()
+	defer orasTempDirs.Unlock()
+	dir, err := os.MkdirTemp("", orasTempPrefix)
+	if err != nil {
+		return "", err
+	}
+	orasTempDirs.dirs[dir] = true
+	return dir, nil
+}
+
+// removeOrasTempDir removes a directory created by newOrasTempDir.
+func removeOrasTempDir(dir string) {
+	if dir == "" {
+		return
+	}
+	_ = os.RemoveAll(dir)
+	orasTempDirs.Lock()
+	defer orasTempDirs.Unlock()
+	delete(orasTempDirs.dirs, dir)
+}
+
+// orasCacheDirs returns the directories artifacts are pulled to in the download cache area, whether they are used
+// by this process or not.
+func orasCacheDirs() []string {
+	matches, err := filepath.Glob(filepath.Join(os.TempDir(), orasTempPrefix+"*"))
+	if err != nil {
+		return nil
+	}
+	dirs := []string{}
+	for _, match := range matches {
+		if info, err := os.Lstat(match); err == nil && info.IsDir() {
+			dirs = append(dirs, match)
+		}
+	}
+	return dirs
+}
+
+// SweepOrasTempDirs removes the directories of the download cache area that no Oras client of this process uses,
+// left behind when the controller stopped in the middle of a pull. It is called at startup, before any event is
+// handled, and returns the number of directories removed.
+func SweepOrasTempDirs() int {
+	swept := 0
+	for _, dir := range orasCacheDirs() {
+		orasTempDirs.Lock()
+		used := orasTempDirs.dirs[dir]
+		orasTempDirs.Unlock()
+		if used {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			log.Warnf("Unable to remove orphaned download directory %s: %v", dir, err)
+			continue
+		}
+		log.Infof("Removed orphaned download directory %s", dir)
+		orasSweptDirectories.Inc()
+		swept++
+	}
+	return swept
+}
+
+// OrasCacheUsage returns the number of directories artifacts are pulled to in the download cache area and the bytes
+// used by their files.
+func OrasCacheUsage() (int, int64) {
+	dirs := orasCacheDirs()
+	var bytes int64
+	for _, dir := range dirs {
+		_ = filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				// The directory may be removed while it is walked
+				return nil
+			}
+			if entry.Type().IsRegular() {
+				if info, err := entry.Info(); err == nil {
+					bytes += info.Size()
+				}
+			}
+			return nil
+		})
+	}
+	return len(dirs), bytes
+}
@@ -6,11 +6,16 @@ package southbound
 
 import (
 	"context"
-	"os"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
 	"oras.land/oras-go/v2/registry/remote/auth"
-	"oras.land/oras-go/v2/registry/remote/retry"
 
 	"oras.land/oras-go/v2"
 	"oras.land/oras-go/v2/content/file"
@@ -21,60 +26,162 @@ const (
 	// Default Timeout when calling Oras. We're loading very small objects (yaml for deployment packages)
 	// so 5 minutes should be plenty.
 	orasLoadTimeout = 5 * time.Minute
+
+	// Timeout for mirroring a single artifact, which may be a container image of several hundred megabytes
+	orasMirrorTimeout = 15 * time.Minute
 )
 
+// OrasResolver returns the target the artifacts of a repository are pulled from, for a repository reference of the
+// form host[:port]/path.
+type OrasResolver func(reference string) (oras.ReadOnlyTarget, error)
+
+// OrasOptions configures how Oras pulls from the registry. The zero value pulls anonymously over plain HTTP.
+type OrasOptions struct {
+	// credential of the registry, called with its host and port. If nil, the registry is pulled from anonymously
+	Credential auth.CredentialFunc
+	// connect with HTTPS instead of plain HTTP, verifying the registry certificate with TLSConfig if it is set
+	TLS       bool
+	TLSConfig *tls.Config
+	// resolves the repositories pulled from, e.g. to pull through another client. If nil, the repositories are
+	// pulled from the registry with the options above
+	Resolver OrasResolver
+}
+
 type Oras struct {
 	dest     string
 	registry string
+	options  OrasOptions
 }
 
 func NewOras(registry string) (Oras, error) {
-	o := Oras{}
-	dest, err := os.MkdirTemp("", "repo")
-	if err != nil {
-		return o, err
+	return NewOrasWithOptions(registry, OrasOptions{})
+}
+
+// NewOrasWithOptions creates an Oras client pulling from the registry, a host:port address, with the given options.
+func NewOrasWithOptions(registry string, options OrasOptions) (Oras, error) {
+	return Oras{
+		registry: registry,
+		options:  options,
+	}, nil
+}
+
+// ReleaseServiceOrasOptions returns the options of the release service registry access of the configuration. The
+// credential is read when the registry first asks for it, and then kept for the lifetime of the client.
+func ReleaseServiceOrasOptions(configuration config.Configuration) (OrasOptions, error) {
+	access := configuration.ReleaseServiceAccess
+	options := OrasOptions{TLS: access.TLS}
+	if !access.Anonymous() {
+		options.Credential = secretCredential(configuration.ReleaseServiceBase, access.Credential)
 	}
-	o = Oras{
-		dest: dest,
+	if access.CACerts != "" || access.InsecureSkipVerify {
+		options.TLSConfig = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: access.InsecureSkipVerify, //nolint:gosec // Opted into by the configuration
+		}
+		if access.CACerts != "" {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM([]byte(access.CACerts)) {
+				return options, fmt.Errorf("no PEM encoded certificate found in the release service CA certificates")
+			}
+			options.TLSConfig.RootCAs = pool
+		}
 	}
-	o.registry = registry
-	return o, nil
+	return options, nil
 }
 
-func (o *Oras) Load(manifestPath string, manifestTag string) error {
-	var err error
+// secretCredential returns a credential function answering the registry at hostport with the username:password
+// credential held by a secret. Other hosts, e.g. storage that blobs are redirected to, get no credential.
+func secretCredential(hostport string, ref config.SecretRef) auth.CredentialFunc {
+	var (
+		mu         sync.Mutex
+		credential *auth.Credential
+	)
+	return func(ctx context.Context, registry string) (auth.Credential, error) {
+		if registry != hostport {
+			return auth.EmptyCredential, nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if credential == nil {
+			value, err := ReadSecretRef(ctx, ref)
+			if err != nil {
+				return auth.EmptyCredential, err
+			}
+			username, password, ok := strings.Cut(strings.TrimSpace(string(value)), ":")
+			if !ok {
+				return auth.EmptyCredential, classify(ErrPermanent, fmt.Errorf("registry credential %s is not in username:password form", ref))
+			}
+			RegisterSecret("release-service-password", password)
+			credential = &auth.Credential{Username: username, Password: password}
+		}
+		return *credential, nil
+	}
+}
 
-	o.dest, err = os.MkdirTemp("", "repo")
+// resolve returns the target the repository at the reference is pulled from.
+func (opts OrasOptions) resolve(reference string) (oras.ReadOnlyTarget, error) {
+	if opts.Resolver != nil {
+		return opts.Resolver(reference)
+	}
+	return opts.repository(reference)
+}
+
+// repository returns the remote repository at the reference, accessed with the options.
+func (opts OrasOptions) repository(reference string) (*remote.Repository, error) {
+	repo, err := remote.NewRepository(reference)
 	if err != nil {
+		return nil, err
+	}
+	repo.PlainHTTP = !opts.TLS
+	repo.Client = &auth.Client{
+		Client:     orasHTTPClientTLS(ServiceReleaseService, opts.TLSConfig),
+		Cache:      auth.NewCache(),
+		Credential: opts.Credential,
+	}
+	return repo, nil
+}
+
+// Load pulls the artifact with the given tag from the repository at manifestPath to a new temporary directory, see
+// Dest. The directory of the artifact loaded before is removed. The pull stops when ctx is done, and the directory is
+// removed if the pull fails.
+func (o *Oras) Load(ctx context.Context, manifestPath string, manifestTag string) error {
+	o.Close()
+	dest, err := newOrasTempDir()
+	if err != nil {
+		return err
+	}
+	o.dest = dest
+	if err := o.load(ctx, manifestPath, manifestTag); err != nil {
+		o.Close()
 		return err
 	}
+	return nil
+}
 
+func (o *Oras) load(ctx context.Context, manifestPath string, manifestTag string) error {
 	fs, err := file.New(o.dest)
 	if err != nil {
 		return err
 	}
 	defer fs.Close() //nolint:errcheck // Defer close is acceptable here
 
-	ctx, cancel := context.WithTimeout(context.Background(), orasLoadTimeout)
+	ctx, cancel := context.WithTimeout(ctx, orasLoadTimeout)
 	defer cancel()
 	orasPath := o.registry + manifestPath
 	log.Infof("ORAS request base URL %s", orasPath)
 
-	repo, err := remote.NewRepository(orasPath)
+	repo, err := o.options.resolve(orasPath)
 	if err != nil {
 		return err
 	}
-	repo.PlainHTTP = true
-
-	repo.Client = &auth.Client{
-		Client: retry.DefaultClient,
-		Cache:  auth.NewCache(),
-	}
 
 	tag := manifestTag
 	_, err = oras.Copy(ctx, repo, tag, fs, tag, oras.DefaultCopyOptions)
 	if err != nil {
-		return err
+		return orasError(err)
 	}
 	return nil
 }
@@ -83,7 +190,61 @@ func (o *Oras) Dest() string {
 	return o.dest
 }
 
+// Close removes the directory of the loaded artifact.
 func (o *Oras) Close() {
-	_ = os.RemoveAll(o.dest)
+	removeOrasTempDir(o.dest)
 	o.dest = ""
 }
+
+// OrasMirror copies artifacts from the release service into Harbor
+type OrasMirror struct {
+	source           string
+	sourceOptions    OrasOptions
+	destination      string
+	destinationPlain bool
+}
+
+// NewOrasMirror creates a mirror from the release service proxy at source, a host:port address accessed with the
+// given options, to the Harbor server URL at destination.
+func NewOrasMirror(source string, sourceOptions OrasOptions, destination string) (*OrasMirror, error) {
+	u, err := url.Parse(destination)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid Harbor server URL %q", destination)
+	}
+	return &OrasMirror{
+		source:           source,
+		sourceOptions:    sourceOptions,
+		destination:      u.Host,
+		destinationPlain: u.Scheme == "http",
+	}, nil
+}
+
+// Copy copies the artifact with the given tag from the source repository on the release service to the destination
+// repository in Harbor, which is accessed with the given robot account credentials.
+func (m *OrasMirror) Copy(ctx context.Context, sourceRepository string, tag string, destinationRepository string, username string, password string) error {
+	ctx, cancel := context.WithTimeout(ctx, orasMirrorTimeout)
+	defer cancel()
+
+	src, err := m.sourceOptions.resolve(m.source + "/" + sourceRepository)
+	if err != nil {
+		return classify(ErrPermanent, err)
+	}
+
+	dst, err := remote.NewRepository(m.destination + "/" + destinationRepository)
+	if err != nil {
+		return classify(ErrPermanent, err)
+	}
+	dst.PlainHTTP = m.destinationPlain
+	dst.Client = &auth.Client{
+		Client: orasHTTPClient(ServiceHarborRegistry),
+		Cache:  auth.NewCache(),
+		Credential: auth.StaticCredential(m.destination, auth.Credential{
+			Username: username,
+			Password: password,
+		}),
+	}
+
+	log.Infof("Mirroring %s/%s:%s to %s/%s", m.source, sourceRepository, tag, m.destination, destinationRepository)
+	_, err = oras.Copy(ctx, src, tag, dst, tag, oras.DefaultCopyOptions)
+	return orasError(err)
+}
@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package southbound
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/suite"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+// Suite of Oras tests. They only use the local file store, so that they run on every platform the controller is
+// built for.
+type OrasTestSuite struct {
+	suite.Suite
+}
+
+func TestOras(t *testing.T) {
+	suite.Run(t, &OrasTestSuite{})
+}
+
+// pushArtifact tags an artifact made of the given files in the store
+func (s *OrasTestSuite) pushArtifact(store *memory.Store, tag string, files map[string]string) {
+	ctx := context.Background()
+	var layers []ocispec.Descriptor
+	for name, data := range files {
+		layer := content.NewDescriptorFromBytes("application/vnd.test.file", []byte(data))
+		layer.Annotations = map[string]string{ocispec.AnnotationTitle: name}
+		s.NoError(store.Push(ctx, layer, bytes.NewReader([]byte(data))))
+		layers = append(layers, layer)
+	}
+	manifest, err := oras.PackManifest(ctx, store, oras.PackManifestVersion1_1, "application/vnd.test.artifact",
+		oras.PackManifestOptions{Layers: layers})
+	s.NoError(err)
+	s.NoError(store.Tag(ctx, manifest, tag))
+}
+
+func (s *OrasTestSuite) TestLoad() {
+	store := memory.New()
+	s.pushArtifact(store, "1.0", map[string]string{"base.yaml": "name: base\n", "base-values.yaml": "replicas: 1\n"})
+
+	var reference string
+	o, err := NewOrasWithOptions("registry:5000", OrasOptions{Resolver: func(ref string) (oras.ReadOnlyTarget, error) {
+		reference = ref
+		return store, nil
+	}})
+	s.NoError(err)
+	defer o.Close()
+
+	s.NoError(o.Load(context.Background(), "/edge-orch/en/files/base", "1.0"))
+	s.Equal("registry:5000/edge-orch/en/files/base", reference)
+
+	// The files are written to the temporary directory with the platform path separator
+	entries, err := os.ReadDir(o.Dest())
+	s.NoError(err)
+	s.Len(entries, 2)
+	data, err := os.ReadFile(filepath.Join(o.Dest(), "base.yaml"))
+	s.NoError(err)
+	s.Equal("name: base\n", string(data))
+	data, err = os.ReadFile(filepath.Join(o.Dest(), "base-values.yaml"))
+	s.NoError(err)
+	s.Equal("replicas: 1\n", string(data))
+
+	// Closing removes the temporary directory
+	dest := o.Dest()
+	o.Close()
+	s.Empty(o.Dest())
+	_, err = os.Stat(dest)
+	s.True(os.IsNotExist(err))
+}
+
+func (s *OrasTestSuite) TestLoadMissingTag() {
+	o, err := NewOrasWithOptions("registry:5000", OrasOptions{Resolver: func(_ string) (oras.ReadOnlyTarget, error) {
+		return memory.New(), nil
+	}})
+	s.NoError(err)
+	defer o.Close()
+
+	s.Error(o.Load(context.Background(), "/edge-orch/en/files/base", "1.0"))
+	s.Empty(o.Dest())
+}
+
+// blockingTarget resolves the artifacts of a store, then blocks fetching their content until the pull is cancelled
+type blockingTarget struct {
+	*memory.Store
+	fetching chan struct{}
+}
+
+func (t *blockingTarget) Fetch(ctx context.Context, _ ocispec.Descriptor) (io.ReadCloser, error) {
+	select {
+	case t.fetching <- struct{}{}:
+	default:
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (s *OrasTestSuite) TestLoadCancelled() {
+	s.T().Setenv("TMPDIR", s.T().TempDir())
+	store := memory.New()
+	s.pushArtifact(store, "1.0", map[string]string{"base.yaml": "name: base\n"})
+	target := &blockingTarget{Store: store, fetching: make(chan struct{}, 1)}
+	o, err := NewOrasWithOptions("registry:5000", OrasOptions{Resolver: func(_ string) (oras.ReadOnlyTarget, error) {
+		return target, nil
+	}})
+	s.NoError(err)
+	defer o.Close()
+
+	// Cancelling the context stops the pull in the middle and removes its directory
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-target.fetching
+		cancel()
+	}()
+	err = o.Load(ctx, "/edge-orch/en/files/base", "1.0")
+	s.ErrorIs(err, context.Canceled)
+	s.Empty(o.Dest())
+	directories, _ := OrasCacheUsage()
+	s.Zero(directories)
+}
+
+func (s *OrasTestSuite) TestLoadRemovesPreviousArtifact() {
+	s.T().Setenv("TMPDIR", s.T().TempDir())
+	store := memory.New()
+	s.pushArtifact(store, "1.0", map[string]string{"base.yaml": "name: base\n"})
+	s.pushArtifact(store, "2.0", map[string]string{"base.yaml": "name: base\nversion: 2\n"})
+	o, err := NewOrasWithOptions("registry:5000", OrasOptions{Resolver: func(_ string) (oras.ReadOnlyTarget, error) {
+		return store, nil
+	}})
+	s.NoError(err)
+	defer o.Close()
+
+	// Nothing is written before an artifact is loaded
+	directories, _ := OrasCacheUsage()
+	s.Zero(directories)
+
+	s.NoError(o.Load(context.Background(), "/edge-orch/en/files/base", "1.0"))
+	first := o.Dest()
+	s.NoError(o.Load(context.Background(), "/edge-orch/en/files/base", "2.0"))
+	s.NotEqual(first, o.Dest())
+	_, err = os.Stat(first)
+	s.True(os.IsNotExist(err))
+	directories, bytes := OrasCacheUsage()
+	s.Equal(1, directories)
+	s.Equal(int64(len("name: base\nversion: 2\n")), bytes)
+}
+
+func (s *OrasTestSuite) TestSweepOrasTempDirs() {
+	tmp := s.T().TempDir()
+	s.T().Setenv("TMPDIR", tmp)
+	store := memory.New()
+	s.pushArtifact(store, "1.0", map[string]string{"base.yaml": "name: base\n"})
+	o, err := NewOrasWithOptions("registry:5000", OrasOptions{Resolver: func(_ string) (oras.ReadOnlyTarget, error) {
+		return store, nil
+	}})
+	s.NoError(err)
+	defer o.Close()
+	s.NoError(o.Load(context.Background(), "/edge-orch/en/files/base", "1.0"))
+
+	// A directory left by a previous run, and files that are not download directories
+	orphan := filepath.Join(tmp, "repo1234")
+	s.NoError(os.MkdirAll(filepath.Join(orphan, "partial"), 0o700))
+	s.NoError(os.WriteFile(filepath.Join(orphan, "partial", "blob"), []byte("0123456789"), 0o600))
+	s.NoError(os.WriteFile(filepath.Join(tmp, "repository.txt"), nil, 0o600))
+	s.NoError(os.Mkdir(filepath.Join(tmp, "other"), 0o700))
+	directories, bytes := OrasCacheUsage()
+	s.Equal(2, directories)
+	s.Equal(int64(len("name: base\n")+10), bytes)
+
+	// Only the orphaned directory is removed, the loaded artifact is kept
+	s.Equal(1, SweepOrasTempDirs())
+	_, err = os.Stat(orphan)
+	s.True(os.IsNotExist(err))
+	s.DirExists(o.Dest())
+	s.FileExists(filepath.Join(tmp, "repository.txt"))
+	s.DirExists(filepath.Join(tmp, "other"))
+	s.Zero(SweepOrasTempDirs())
+}
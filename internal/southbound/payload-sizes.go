@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// ErrArtifactTooLarge is returned for a catalog upload with a file larger than the maximum catalog artifact size. It
+// is a permanent error: the upload fails again until the manifest or the configuration is fixed.
+var ErrArtifactTooLarge = fmt.Errorf("%w: catalog artifact too large", ErrPermanent)
+
+var (
+	southboundPayloadSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "tenant_controller_southbound_payload_size_bytes",
+		Help:    "Size of the catalog artifacts uploaded and of the Harbor request bodies sent, by service",
+		Buckets: prometheus.ExponentialBuckets(1024, 4, 10),
+	}, []string{"service"})
+
+	southboundPayloadBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tenant_controller_southbound_payload_bytes_total",
+		Help: "Bytes of catalog artifacts uploaded and of Harbor request bodies sent, by service and organization",
+	}, []string{"service", "organization"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(southboundPayloadSize, southboundPayloadBytes)
+}
+
+// PayloadSizes totals the bytes sent to the southbound services for the events of a project. It is safe for
+// concurrent use.
+type PayloadSizes struct {
+	organization string
+	mu           sync.Mutex
+	bytes        map[string]int64
+}
+
+// NewPayloadSizes returns the payload totals of a project of the organization.
+func NewPayloadSizes(organization string) *PayloadSizes {
+	return &PayloadSizes{organization: organization, bytes: map[string]int64{}}
+}
+
+// Bytes returns the bytes sent so far, by service. Services nothing was sent to are left out.
+func (p *PayloadSizes) Bytes() map[string]int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return maps.Clone(p.bytes)
+}
+
+func (p *PayloadSizes) add(service string, size int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bytes[service] += size
+}
+
+type payloadSizesKey struct{}
+
+// WithPayloadSizes returns a context whose catalog uploads and Harbor REST calls are added to the payload totals.
+func WithPayloadSizes(ctx context.Context, sizes *PayloadSizes) context.Context {
+	return context.WithValue(ctx, payloadSizesKey{}, sizes)
+}
+
+// recordPayload counts a payload sent to the service in the metrics, and in the payload totals of the context if it
+// has any. Payloads sent without totals are counted with an empty organization.
+func recordPayload(ctx context.Context, service string, size int64) {
+	organization := ""
+	if sizes, ok := ctx.Value(payloadSizesKey{}).(*PayloadSizes); ok {
+		sizes.add(service, size)
+		organization = sizes.organization
+	}
+	southboundPayloadSize.WithLabelValues(service).Observe(float64(size))
+	southboundPayloadBytes.WithLabelValues(service, organization).Add(float64(size))
+}
+
+// checkArtifactSize returns ErrArtifactTooLarge if the artifact is larger than the maximum size, where a maximum of
+// 0 or less means there is no limit.
+func checkArtifactSize(fileName string, artifact []byte, maxSize int64) error {
+	if maxSize <= 0 || int64(len(artifact)) <= maxSize {
+		return nil
+	}
+	err := fmt.Errorf("%w: %s is %d bytes, the maximum is %d (MAX_CATALOG_ARTIFACT_SIZE)", ErrArtifactTooLarge,
+		fileName, len(artifact), maxSize)
+	return WithUserMessage(err, "Catalog file %s is %d bytes, larger than the maximum of %d bytes", path.Base(fileName),
+		len(artifact), maxSize)
+}
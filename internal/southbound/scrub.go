@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const redacted = "<redacted>"
+
+// secrets shorter than this are not scrubbed by value, as they would match unrelated text
+const minScrubbedSecretLength = 8
+
+var (
+	// secretFieldPattern matches JSON string fields holding credentials, such as robot secrets and passwords
+	secretFieldPattern = regexp.MustCompile(`(?i)("[a-z_]*(?:secret|password|token|credential)[a-z_]*"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	// secretParameterPattern matches form and query parameters holding credentials, such as client_secret=...
+	secretParameterPattern = regexp.MustCompile(`(?i)\b([a-z_-]*(?:secret|password|token|credential)[a-z_-]*=)[^\s&,;"']+`)
+	// authorizationPattern matches the credentials of Authorization headers
+	authorizationPattern = regexp.MustCompile(`(?i)\b(Basic|Bearer) [A-Za-z0-9._~+/=-]+`)
+	// userinfoPattern matches the password in the user information of URLs
+	userinfoPattern = regexp.MustCompile(`(://[^/\s:@]+:)[^/\s@]+@`)
+)
+
+var (
+	secretsMu sync.RWMutex
+	// known credential values, keyed by what they are, so that a rotated credential replaces the previous one
+	secrets = map[string]string{}
+)
+
+// RegisterSecret records the current value of a credential, such as the Harbor admin password, so that Scrub
+// redacts it wherever it appears. Registering a name again replaces its previous value.
+func RegisterSecret(name string, value string) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	if len(value) < minScrubbedSecretLength {
+		delete(secrets, name)
+		return
+	}
+	secrets[name] = value
+}
+
+// Scrub returns the message with credentials redacted: registered secrets, credential fields of JSON bodies,
+// credential form and query parameters, Authorization header values and URL passwords. Messages from southbound
+// services, which may echo the request, must be scrubbed before they are logged or shown to users.
+func Scrub(message string) string {
+	secretsMu.RLock()
+	for _, secret := range secrets {
+		message = strings.ReplaceAll(message, secret, redacted)
+	}
+	secretsMu.RUnlock()
+
+	message = secretFieldPattern.ReplaceAllString(message, `$1"`+redacted+`"`)
+	message = secretParameterPattern.ReplaceAllString(message, "${1}"+redacted)
+	message = authorizationPattern.ReplaceAllString(message, "$1 "+redacted)
+	return userinfoPattern.ReplaceAllString(message, "${1}"+redacted+"@")
+}
+
+type scrubbedError struct {
+	err error
+}
+
+func (e *scrubbedError) Error() string {
+	return Scrub(e.err.Error())
+}
+
+func (e *scrubbedError) Unwrap() error {
+	return e.err
+}
+
+// ScrubError returns the error with its message scrubbed. The error still matches the errors it wraps.
+func ScrubError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &scrubbedError{err: err}
+}
@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+// WatchSecret calls onChange every time the value of the key of the secret changes, until the context is done. The
+// value the secret has when the watch starts is not a change. Mounted secrets are not watched, as the kubelet
+// updates their file in place and it is read again on every use.
+func WatchSecret(ctx context.Context, ref config.SecretRef, onChange func(ctx context.Context)) error {
+	if ref.Mounted() {
+		log.Infof("Secret %s is mounted, not watching it", ref)
+		return nil
+	}
+	restConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return err
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	return watchSecret(ctx, clientset, ref, onChange)
+}
+
+func watchSecret(ctx context.Context, clientset kubernetes.Interface, ref config.SecretRef, onChange func(ctx context.Context)) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0, informers.WithNamespace(ref.Namespace),
+		informers.WithTweakListOptions(func(options *metaV1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", ref.Name).String()
+		}))
+	informer := factory.Core().V1().Secrets().Informer()
+
+	var (
+		mu    sync.Mutex
+		value []byte
+		known bool
+	)
+	observe := func(obj interface{}) {
+		secret, ok := obj.(*coreV1.Secret)
+		if !ok || secret.Name != ref.Name {
+			return
+		}
+		mu.Lock()
+		changed := known && !bytes.Equal(value, secret.Data[ref.Key])
+		value, known = secret.Data[ref.Key], true
+		mu.Unlock()
+		if changed {
+			log.Infof("Secret %s changed", ref)
+			onChange(ctx)
+		}
+	}
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    observe,
+		UpdateFunc: func(_, obj interface{}) { observe(obj) },
+	})
+	if err != nil {
+		return err
+	}
+
+	factory.Start(ctx.Done())
+	for _, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("unable to watch secret %s", ref)
+		}
+	}
+	log.Infof("Watching secret %s", ref)
+	return nil
+}
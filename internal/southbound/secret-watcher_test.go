@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package southbound
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/stretchr/testify/suite"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Suite of secret watcher tests
+type SecretWatcherTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *SecretWatcherTestSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 1*time.Minute)
+}
+
+func (s *SecretWatcherTestSuite) TearDownTest() {
+	s.cancel()
+}
+
+func TestSecretWatcher(t *testing.T) {
+	suite.Run(t, &SecretWatcherTestSuite{})
+}
+
+// waitForChange fails the test if no change is reported before the test times out
+func (s *SecretWatcherTestSuite) waitForChange(changes chan struct{}) {
+	select {
+	case <-changes:
+	case <-s.ctx.Done():
+		s.FailNow("the change of the secret was not reported")
+	}
+}
+
+func (s *SecretWatcherTestSuite) TestWatchSecret() {
+	secret := &coreV1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Name: "platform-keycloak", Namespace: "orch-platform"},
+		Data:       map[string][]byte{"admin-password": []byte("first"), "other": []byte("a")},
+	}
+	clientset := fake.NewClientset(secret)
+	secrets := clientset.CoreV1().Secrets("orch-platform")
+	changes := make(chan struct{}, 10)
+	ref := config.SecretRef{Namespace: "orch-platform", Name: "platform-keycloak", Key: "admin-password"}
+	s.NoError(watchSecret(s.ctx, clientset, ref, func(_ context.Context) { changes <- struct{}{} }))
+
+	// The value found when the watch starts and changes of other keys are not reported
+	secret.Data["other"] = []byte("b")
+	_, err := secrets.Update(s.ctx, secret, metaV1.UpdateOptions{})
+	s.NoError(err)
+	secret.Data["admin-password"] = []byte("second")
+	_, err = secrets.Update(s.ctx, secret, metaV1.UpdateOptions{})
+	s.NoError(err)
+	s.waitForChange(changes)
+
+	// A secret created again with a new value is a change
+	s.NoError(secrets.Delete(s.ctx, "platform-keycloak", metaV1.DeleteOptions{}))
+	secret.Data["admin-password"] = []byte("third")
+	_, err = secrets.Create(s.ctx, secret, metaV1.CreateOptions{})
+	s.NoError(err)
+	s.waitForChange(changes)
+	s.Empty(changes)
+}
+
+func (s *SecretWatcherTestSuite) TestMountedSecretNotWatched() {
+	s.NoError(WatchSecret(s.ctx, config.SecretRef{MountPath: "/etc/keycloak", Key: "admin-password"}, nil))
+}
@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+)
+
+// ReadSecretRef returns the value of the key of a secret, read from the file of a mounted secret or through the
+// Kubernetes API. A missing key or mounted file is a permanent error.
+func ReadSecretRef(ctx context.Context, ref config.SecretRef) ([]byte, error) {
+	if ref.Mounted() {
+		value, err := os.ReadFile(ref.File())
+		if err != nil {
+			return nil, classify(ErrPermanent, fmt.Errorf("unable to read mounted secret: %w", err))
+		}
+		return value, nil
+	}
+	k8sClient, err := K8sFactory(ref.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	data, err := k8sClient.ReadSecret(ctx, ref.Name)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := data[ref.Key]
+	if !ok {
+		return nil, classify(ErrPermanent, fmt.Errorf("no %s found in secret %s/%s", ref.Key, ref.Namespace, ref.Name))
+	}
+	return value, nil
+}
@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/orch-library/go/pkg/auth"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// a cached token is replaced when it has less than this time left, so that it does not expire during a call
+	tokenRefreshMargin = time.Minute
+	// lifetime assumed for tokens that do not carry an expiry time, and for the empty token used without M2M
+	defaultTokenLifetime = 5 * time.Minute
+)
+
+var tokenFetches = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "tenant_controller_token_fetches_total",
+	Help: "M2M service account token requests made to Vault and Keycloak, by result",
+}, []string{"result"})
+
+func init() {
+	metrics.Registry.MustRegister(tokenFetches)
+}
+
+// TokenSource caches the M2M service account token used by the gRPC southbound clients. The token is fetched
+// again shortly before it expires. If fetching fails while the cached token is still valid, the cached token is
+// used.
+type TokenSource struct {
+	fetch func(ctx context.Context) (string, error)
+	now   func() time.Time
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewTokenSource returns a token source fetching the token with the given function.
+func NewTokenSource(fetch func(ctx context.Context) (string, error)) *TokenSource {
+	return &TokenSource{fetch: fetch, now: time.Now}
+}
+
+type tokenSourceKey struct {
+	keycloakServiceBase string
+	vaultServer         string
+	serviceAccount      string
+}
+
+var (
+	tokenSourcesMu sync.Mutex
+	tokenSources   = map[tokenSourceKey]*TokenSource{}
+)
+
+// SharedTokenSource returns the token source for the service account of the configuration. Clients with the same
+// Keycloak, Vault and service account share a token source.
+func SharedTokenSource(configuration config.Configuration) *TokenSource {
+	key := tokenSourceKey{
+		keycloakServiceBase: configuration.KeycloakServiceBase,
+		vaultServer:         configuration.VaultServer,
+		serviceAccount:      configuration.ServiceAccount,
+	}
+	tokenSourcesMu.Lock()
+	defer tokenSourcesMu.Unlock()
+	if source, ok := tokenSources[key]; ok {
+		return source
+	}
+	source := NewTokenSource(func(ctx context.Context) (string, error) {
+		vaultAuthClient, err := auth.NewVaultAuth(key.keycloakServiceBase, key.vaultServer, key.serviceAccount)
+		if err != nil {
+			return "", err
+		}
+		return vaultAuthClient.GetM2MToken(ctx)
+	})
+	tokenSources[key] = source
+	return source
+}
+
+// InvalidateTokenSources drops the tokens cached by the shared token sources, so that the next call of every client
+// fetches a token with the current service account credentials.
+func InvalidateTokenSources() {
+	tokenSourcesMu.Lock()
+	defer tokenSourcesMu.Unlock()
+	for _, source := range tokenSources {
+		source.Invalidate()
+	}
+}
+
+// Invalidate drops the cached token.
+func (s *TokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+	s.expiry = time.Time{}
+}
+
+// Reject drops the cached token if it is the given one, which a service rejected before its expiry time. Calls
+// rejected with the same token then fetch a single new token, and a token fetched meanwhile is kept.
+func (s *TokenSource) Reject(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == token {
+		s.token = ""
+		s.expiry = time.Time{}
+	}
+}
+
+// Token returns the cached token, fetching a new one if there is none or it is about to expire. An empty token
+// means that M2M authentication is not in use.
+func (s *TokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if !s.expiry.IsZero() && now.Add(tokenRefreshMargin).Before(s.expiry) {
+		return s.token, nil
+	}
+
+	token, err := s.fetch(ctx)
+	if err != nil {
+		// Vault and Keycloak errors may echo the request with its credentials
+		err = ScrubError(err)
+		tokenFetches.WithLabelValues("error").Inc()
+		if now.Before(s.expiry) {
+			log.Warnf("Unable to refresh M2M token, using the cached token until it expires at %s: %v", s.expiry, err)
+			return s.token, nil
+		}
+		return "", err
+	}
+	tokenFetches.WithLabelValues("success").Inc()
+	s.token = token
+	s.expiry = tokenExpiry(token, now)
+	return s.token, nil
+}
+
+// tokenExpiry returns the expiry time of a JWT, read without verifying the token. Tokens that are not JWTs or have
+// no expiry time are assumed to last defaultTokenLifetime.
+func tokenExpiry(token string, now time.Time) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return now.Add(defaultTokenLifetime)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return now.Add(defaultTokenLifetime)
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return now.Add(defaultTokenLifetime)
+	}
+	return time.Unix(claims.Exp, 0)
+}
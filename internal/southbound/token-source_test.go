@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package southbound
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/metadata"
+)
+
+// Suite of token source tests
+type TokenSourceTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *TokenSourceTestSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 1*time.Minute)
+}
+
+func (s *TokenSourceTestSuite) TearDownTest() {
+	s.cancel()
+}
+
+func TestTokenSource(t *testing.T) {
+	suite.Run(t, &TokenSourceTestSuite{})
+}
+
+// testJWT returns an unsigned JWT expiring at the given time
+func testJWT(expiry time.Time) string {
+	claims := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"m2m","exp":%d}`, expiry.Unix())))
+	return "eyJhbGciOiJub25lIn0." + claims + ".signature"
+}
+
+func (s *TokenSourceTestSuite) TestTokenCachedUntilExpiry() {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fetches := 0
+	var fetchErr error
+	source := NewTokenSource(func(_ context.Context) (string, error) {
+		fetches++
+		if fetchErr != nil {
+			return "", fetchErr
+		}
+		return testJWT(now.Add(10 * time.Minute)), nil
+	})
+	source.now = func() time.Time { return now }
+	failures := testutil.ToFloat64(tokenFetches.WithLabelValues("error"))
+
+	first, err := source.Token(s.ctx)
+	s.NoError(err)
+	s.Equal(1, fetches)
+
+	// The token is reused until it is about to expire
+	now = now.Add(5 * time.Minute)
+	token, err := source.Token(s.ctx)
+	s.NoError(err)
+	s.Equal(first, token)
+	s.Equal(1, fetches)
+
+	now = now.Add(4*time.Minute + 30*time.Second)
+	token, err = source.Token(s.ctx)
+	s.NoError(err)
+	s.Equal(2, fetches)
+	s.NotEqual(first, token)
+
+	// A failed refresh falls back to the cached token while it is valid
+	fetchErr = errors.New("keycloak unavailable")
+	now = now.Add(9*time.Minute + 30*time.Second)
+	cached, err := source.Token(s.ctx)
+	s.NoError(err)
+	s.Equal(token, cached)
+	s.Equal(3, fetches)
+	s.Equal(failures+1, testutil.ToFloat64(tokenFetches.WithLabelValues("error")))
+
+	now = now.Add(time.Minute)
+	_, err = source.Token(s.ctx)
+	s.ErrorContains(err, "keycloak unavailable")
+	s.Equal(failures+2, testutil.ToFloat64(tokenFetches.WithLabelValues("error")))
+}
+
+func (s *TokenSourceTestSuite) TestTokenExpiry() {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.True(now.Add(time.Hour).Equal(tokenExpiry(testJWT(now.Add(time.Hour)), now)))
+	s.Equal(now.Add(defaultTokenLifetime), tokenExpiry("", now))
+	s.Equal(now.Add(defaultTokenLifetime), tokenExpiry("opaque-token", now))
+	s.Equal(now.Add(defaultTokenLifetime), tokenExpiry("a.!!!.c", now))
+}
+
+func (s *TokenSourceTestSuite) TestContextForProject() {
+	source := NewTokenSource(func(_ context.Context) (string, error) { return "", nil })
+	ctx, err := getCtxForProjectID(s.ctx, "uuid-1", source)
+	s.NoError(err)
+	_, ok := metadata.FromOutgoingContext(ctx)
+	s.False(ok)
+
+	source = NewTokenSource(func(_ context.Context) (string, error) { return "token", nil })
+	ctx, err = getCtxForProjectID(s.ctx, "uuid-1", source)
+	s.NoError(err)
+	md, _ := metadata.FromOutgoingContext(ctx)
+	s.Equal([]string{"Bearer token"}, md.Get("authorization"))
+	s.Equal([]string{"uuid-1"}, md.Get("ActiveProjectID"))
+
+	// Clients of the same service account share a token source
+	configuration := config.Configuration{KeycloakServiceBase: "http://keycloak", VaultServer: "http://vault", ServiceAccount: "sa"}
+	s.Same(SharedTokenSource(configuration), SharedTokenSource(configuration))
+	configuration.ServiceAccount = "other"
+	s.NotSame(SharedTokenSource(config.Configuration{ServiceAccount: "sa"}), SharedTokenSource(configuration))
+}
+
+func (s *TokenSourceTestSuite) TestInvalidate() {
+	fetches := 0
+	source := NewTokenSource(func(_ context.Context) (string, error) {
+		fetches++
+		return testJWT(time.Now().Add(10 * time.Minute)), nil
+	})
+	_, err := source.Token(s.ctx)
+	s.NoError(err)
+	_, err = source.Token(s.ctx)
+	s.NoError(err)
+	s.Equal(1, fetches)
+
+	// An invalidated token is fetched again on the next call
+	source.Invalidate()
+	_, err = source.Token(s.ctx)
+	s.NoError(err)
+	s.Equal(2, fetches)
+}
@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Internal package
+package southbound
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// longest response body quoted in a user message when the body is not a Harbor error document
+const maxUserMessageBody = 256
+
+// grpcStatusPattern matches the decoration that gRPC adds to the message of a status error
+var grpcStatusPattern = regexp.MustCompile(`rpc error: code = \w+ desc = `)
+
+// userError carries a concise message for users next to the original error. The original error keeps its message,
+// so that the logs have the full details of the failure.
+type userError struct {
+	message string
+	err     error
+}
+
+func (e *userError) Error() string {
+	return e.err.Error()
+}
+
+func (e *userError) Unwrap() error {
+	return e.err
+}
+
+// WithUserMessage returns the error with a message for users, shown in the project watcher and the history API
+// instead of the message of the error. The error keeps its message and still matches the errors it wraps.
+func WithUserMessage(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return &userError{message: fmt.Sprintf(format, args...), err: err}
+}
+
+// UserMessage returns the message of the error for users: the message set by the outermost WithUserMessage, or else
+// the message of the error without the decoration of gRPC status errors. The message is scrubbed of credentials.
+func UserMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	var user *userError
+	if errors.As(err, &user) {
+		return Scrub(user.message)
+	}
+	return Scrub(grpcStatusPattern.ReplaceAllString(err.Error(), ""))
+}
+
+// CatalogFileError is returned for a file of a catalog upload that the catalog rejected, e.g. a deployment package
+// file with a YAML syntax error or an entity that already exists.
+type CatalogFileError struct {
+	// base name of the file
+	File string
+	// reason given by the catalog, e.g. the line of a syntax error
+	Reason string
+	// classified error of the upload
+	Err error
+}
+
+func (e *CatalogFileError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CatalogFileError) Unwrap() error {
+	return e.Err
+}
+
+// catalogUploadError translates the error of uploading a file to the catalog. Files that the catalog rejects fail
+// with a CatalogFileError, with a user message naming the file.
+func catalogUploadError(fileName string, err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return grpcError(err)
+	}
+	fileErr := &CatalogFileError{File: path.Base(fileName), Reason: s.Message(), Err: grpcError(err)}
+	switch s.Code() {
+	case codes.InvalidArgument, codes.FailedPrecondition:
+		return WithUserMessage(fileErr, "Catalog file %s is invalid: %s", fileErr.File, fileErr.Reason)
+	case codes.AlreadyExists:
+		return WithUserMessage(fileErr, "Catalog file %s conflicts with an entity already in the catalog of the project: %s",
+			fileErr.File, fileErr.Reason)
+	default:
+		return grpcError(err)
+	}
+}
+
+// admCreateError translates the error of creating an ADM deployment. Deployments that ADM rejects get a user
+// message naming the deployment and its package.
+func admCreateError(displayName string, dpName string, version string, profileName string, err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return grpcError(err)
+	}
+	switch s.Code() {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.NotFound:
+		return WithUserMessage(grpcError(err), "Deployment %s of %s:%s profile %s was rejected by the App Deployment Manager: %s",
+			displayName, dpName, version, profileName, s.Message())
+	default:
+		return grpcError(err)
+	}
+}
+
+// harborErrors is the error document of Harbor REST responses
+type harborErrors struct {
+	Errors []struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// harborUserMessage returns the user message of an unexpected Harbor response: the messages of its error document,
+// with a hint for credentials Harbor rejected.
+func harborUserMessage(statusCode int, body []byte) string {
+	var document harborErrors
+	var messages []string
+	if json.Unmarshal(body, &document) == nil {
+		for _, e := range document.Errors {
+			if e.Message != "" {
+				messages = append(messages, e.Message)
+			}
+		}
+	}
+	if len(messages) == 0 {
+		if text := strings.TrimSpace(string(body)); text != "" {
+			if len(text) > maxUserMessageBody {
+				text = text[:maxUserMessageBody] + "..."
+			}
+			messages = append(messages, text)
+		}
+	}
+	message := fmt.Sprintf("Harbor returned %d %s", statusCode, http.StatusText(statusCode))
+	if len(messages) > 0 {
+		message += ": " + strings.Join(messages, "; ")
+	}
+	switch statusCode {
+	case http.StatusUnauthorized:
+		message += ". Check the Harbor admin credential of the controller"
+	case http.StatusForbidden:
+		message += ". Check that the Harbor account of the controller is a Harbor administrator"
+	}
+	return message
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package southbound
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Suite of user message tests
+type UserMessagesTestSuite struct {
+	suite.Suite
+}
+
+func TestUserMessages(t *testing.T) {
+	suite.Run(t, &UserMessagesTestSuite{})
+}
+
+func (s *UserMessagesTestSuite) TestUserMessage() {
+	s.Empty(UserMessage(nil))
+
+	// Without a user message, the gRPC status decoration is removed
+	err := fmt.Errorf("unable to list registries: %w", grpcError(status.Error(codes.Unavailable, "connection refused")))
+	s.Equal("unable to list registries: connection refused", UserMessage(err))
+
+	// The outermost user message is used, and the error keeps its message and class
+	inner := WithUserMessage(grpcError(status.Error(codes.InvalidArgument, "bad")), "inner")
+	err = WithUserMessage(fmt.Errorf("wrapped: %w", inner), "outer with password=%s", "p4ssw0rd")
+	s.Equal("wrapped: rpc error: code = InvalidArgument desc = bad", err.Error())
+	s.ErrorIs(err, ErrPermanent)
+	s.Equal("outer with password=<redacted>", UserMessage(err))
+	s.NoError(WithUserMessage(nil, "unused"))
+}
+
+func (s *UserMessagesTestSuite) TestCatalogUploadError() {
+	err := catalogUploadError("/tmp/repo123/intel-gpu.yaml", status.Error(codes.InvalidArgument, "yaml: line 13: did not find expected key"))
+	s.ErrorIs(err, ErrPermanent)
+	s.Equal("Catalog file intel-gpu.yaml is invalid: yaml: line 13: did not find expected key", UserMessage(err))
+	var fileErr *CatalogFileError
+	s.ErrorAs(err, &fileErr)
+	s.Equal("intel-gpu.yaml", fileErr.File)
+	s.Equal("yaml: line 13: did not find expected key", fileErr.Reason)
+
+	err = catalogUploadError("intel-gpu.yaml", status.Error(codes.AlreadyExists, "application intel-gpu:1.0.2 already exists"))
+	s.ErrorIs(err, ErrConflict)
+	s.Equal("Catalog file intel-gpu.yaml conflicts with an entity already in the catalog of the project: application intel-gpu:1.0.2 already exists",
+		UserMessage(err))
+
+	// Failures that are not about the file are only classified
+	err = catalogUploadError("intel-gpu.yaml", status.Error(codes.Unavailable, "connection refused"))
+	s.ErrorIs(err, ErrTransient)
+	s.False(errors.As(err, &fileErr))
+}
+
+func (s *UserMessagesTestSuite) TestADMCreateError() {
+	err := admCreateError("gpu", "intel-gpu", "1.0.2", "default", status.Error(codes.InvalidArgument, "profile default not found"))
+	s.ErrorIs(err, ErrPermanent)
+	s.Equal("Deployment gpu of intel-gpu:1.0.2 profile default was rejected by the App Deployment Manager: profile default not found",
+		UserMessage(err))
+
+	err = admCreateError("gpu", "intel-gpu", "1.0.2", "default", status.Error(codes.Unavailable, "connection refused"))
+	s.ErrorIs(err, ErrTransient)
+	s.Equal("connection refused", UserMessage(err))
+}
+
+func (s *UserMessagesTestSuite) TestHarborUserMessage() {
+	response := &harborResponse{StatusCode: http.StatusConflict,
+		Body: []byte(`{"errors":[{"code":"CONFLICT","message":"The project named catalog-apps-org-proj already exists"}]}`)}
+	err := response.statusError()
+	s.ErrorIs(err, ErrConflict)
+	s.Equal("Harbor returned 409 Conflict: The project named catalog-apps-org-proj already exists", UserMessage(err))
+
+	s.Equal("Harbor returned 401 Unauthorized: unauthorized. Check the Harbor admin credential of the controller",
+		harborUserMessage(http.StatusUnauthorized, []byte(`{"errors":[{"code":"UNAUTHORIZED","message":"unauthorized"}]}`)))
+	s.Equal("Harbor returned 502 Bad Gateway: <html>bad gateway</html>", harborUserMessage(http.StatusBadGateway, []byte("<html>bad gateway</html>\n")))
+	s.Equal("Harbor returned 500 Internal Server Error", harborUserMessage(http.StatusInternalServerError, nil))
+	s.Len(harborUserMessage(http.StatusBadRequest, []byte(strings.Repeat("x", 1000))), len("Harbor returned 400 Bad Request: ")+maxUserMessageBody+3)
+}
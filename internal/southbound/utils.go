@@ -6,19 +6,32 @@ package southbound
 
 import (
 	"context"
-	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
-	"github.com/open-edge-platform/orch-library/go/pkg/auth"
+	"time"
+
 	"google.golang.org/grpc/metadata"
 )
 
-func getCtxForProjectID(ctx context.Context, projectUUID string, config config.Configuration) (context.Context, error) {
-	vaultAuthClient, err := auth.NewVaultAuth(config.KeycloakServiceBase, config.VaultServer, config.ServiceAccount)
-	if err != nil {
-		log.Warn(err)
-		return nil, err
+// withCallTimeout returns a context bounding a single southbound call. A zero timeout leaves the call bounded by
+// the context alone.
+func withCallTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
 	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// callToken is the M2M token a gRPC call is made with, and the token source it was taken from
+type callToken struct {
+	source *TokenSource
+	token  string
+}
+
+type callTokenKey struct{}
 
-	token, err := vaultAuthClient.GetM2MToken(ctx)
+// getCtxForProjectID returns a context for calling a gRPC service on behalf of the project, carrying the M2M token
+// of the token source. Without M2M authentication the context is returned as it is.
+func getCtxForProjectID(ctx context.Context, projectUUID string, tokens *TokenSource) (context.Context, error) {
+	token, err := tokens.Token(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -30,6 +43,5 @@ func getCtxForProjectID(ctx context.Context, projectUUID string, config config.C
 		"authorization", "Bearer "+token,
 		"ActiveProjectID", projectUUID,
 	)
-	err = vaultAuthClient.Logout(ctx)
-	return outCtx, err
+	return context.WithValue(outCtx, callTokenKey{}, callToken{source: tokens, token: token}), nil
 }
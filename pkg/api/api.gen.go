@@ -0,0 +1,1546 @@
+// Package api provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.7.0 DO NOT EDIT.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+const (
+	BearerAuthScopes bearerAuthContextKey = "bearerAuth.Scopes"
+)
+
+// Defines values for EntryResult.
+const (
+	EntryResultCancelled EntryResult = "cancelled"
+	EntryResultError     EntryResult = "error"
+	EntryResultSuccess   EntryResult = "success"
+)
+
+// Valid indicates whether the value is a known member of the EntryResult enum.
+func (e EntryResult) Valid() bool {
+	switch e {
+	case EntryResultCancelled:
+		return true
+	case EntryResultError:
+		return true
+	case EntryResultSuccess:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for PluginOutcomeResult.
+const (
+	PluginOutcomeResultCancelled PluginOutcomeResult = "cancelled"
+	PluginOutcomeResultError     PluginOutcomeResult = "error"
+	PluginOutcomeResultSuccess   PluginOutcomeResult = "success"
+)
+
+// Valid indicates whether the value is a known member of the PluginOutcomeResult enum.
+func (e PluginOutcomeResult) Valid() bool {
+	switch e {
+	case PluginOutcomeResultCancelled:
+		return true
+	case PluginOutcomeResultError:
+		return true
+	case PluginOutcomeResultSuccess:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for ProjectStatusState.
+const (
+	Deleted      ProjectStatusState = "deleted"
+	Deleting     ProjectStatusState = "deleting"
+	Failed       ProjectStatusState = "failed"
+	Provisioning ProjectStatusState = "provisioning"
+	Ready        ProjectStatusState = "ready"
+)
+
+// Valid indicates whether the value is a known member of the ProjectStatusState enum.
+func (e ProjectStatusState) Valid() bool {
+	switch e {
+	case Deleted:
+		return true
+	case Deleting:
+		return true
+	case Failed:
+		return true
+	case Provisioning:
+		return true
+	case Ready:
+		return true
+	default:
+		return false
+	}
+}
+
+// CredentialReload defines model for CredentialReload.
+type CredentialReload struct {
+	Changed bool `json:"changed"`
+}
+
+// DebugDeployment defines model for DebugDeployment.
+type DebugDeployment struct {
+	AppName     string `json:"appName"`
+	AppVersion  string `json:"appVersion"`
+	DisplayName string `json:"displayName"`
+	ID          string `json:"id"`
+	ProfileName string `json:"profileName"`
+
+	// State ADM state of the deployment, such as RUNNING or ERROR
+	State *string `json:"state,omitempty"`
+}
+
+// DebugRegistry defines model for DebugRegistry.
+type DebugRegistry struct {
+	Anonymous    *bool   `json:"anonymous,omitempty"`
+	Description  *string `json:"description,omitempty"`
+	DisplayName  *string `json:"displayName,omitempty"`
+	InventoryURL *string `json:"inventoryURL,omitempty"`
+	Name         string  `json:"name"`
+	RootURL      string  `json:"rootURL"`
+	Type         string  `json:"type"`
+	Username     *string `json:"username,omitempty"`
+}
+
+// Entry defines model for Entry.
+type Entry struct {
+	ControllerVersion *string `json:"controllerVersion,omitempty"`
+	DurationSeconds   float64 `json:"durationSeconds"`
+	Error             *string `json:"error,omitempty"`
+
+	// EventType create, update or delete
+	EventType string           `json:"eventType"`
+	Finished  time.Time        `json:"finished"`
+	Plugins   *[]PluginOutcome `json:"plugins,omitempty"`
+	Profile   *string          `json:"profile,omitempty"`
+	Received  time.Time        `json:"received"`
+	Resources *[]string        `json:"resources,omitempty"`
+	Result    EntryResult      `json:"result"`
+	Warnings  *[]string        `json:"warnings,omitempty"`
+}
+
+// EntryResult defines model for Entry.Result.
+type EntryResult string
+
+// ExportBundle defines model for ExportBundle.
+type ExportBundle struct {
+	DeploymentLabels  *map[string]string   `json:"deploymentLabels,omitempty"`
+	Deployments       *[]ExportDeployment  `json:"deployments,omitempty"`
+	Exported          time.Time            `json:"exported"`
+	ExtensionPackages *[]ExportPackage     `json:"extensionPackages,omitempty"`
+	HarborProject     *ExportHarborProject `json:"harborProject,omitempty"`
+	Organization      string               `json:"organization"`
+	Profile           *string              `json:"profile,omitempty"`
+	Project           string               `json:"project"`
+	Registries        *[]ExportRegistry    `json:"registries,omitempty"`
+	StarterApps       *[]string            `json:"starterApps,omitempty"`
+	UUID              string               `json:"uuid"`
+	Version           string               `json:"version"`
+}
+
+// ExportDeployment defines model for ExportDeployment.
+type ExportDeployment struct {
+	AppName     string `json:"appName"`
+	AppVersion  string `json:"appVersion"`
+	DisplayName string `json:"displayName"`
+	ProfileName string `json:"profileName"`
+}
+
+// ExportHarborProject defines model for ExportHarborProject.
+type ExportHarborProject struct {
+	Name         string    `json:"name"`
+	Robots       *[]string `json:"robots,omitempty"`
+	StorageLimit *int64    `json:"storageLimit,omitempty"`
+}
+
+// ExportPackage defines model for ExportPackage.
+type ExportPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ExportRegistry defines model for ExportRegistry.
+type ExportRegistry struct {
+	Anonymous *bool   `json:"anonymous,omitempty"`
+	Cacerts   *string `json:"cacerts,omitempty"`
+
+	// Credentials The registry has credentials, which are not exported
+	Credentials  *bool   `json:"credentials,omitempty"`
+	Description  *string `json:"description,omitempty"`
+	DisplayName  *string `json:"displayName,omitempty"`
+	InventoryURL *string `json:"inventoryURL,omitempty"`
+	Name         string  `json:"name"`
+	RootURL      string  `json:"rootURL"`
+	Type         string  `json:"type"`
+}
+
+// History defines model for History.
+type History struct {
+	Events       []Entry `json:"events"`
+	Organization string  `json:"organization"`
+	Project      string  `json:"project"`
+	UUID         string  `json:"uuid"`
+}
+
+// PluginOutcome defines model for PluginOutcome.
+type PluginOutcome struct {
+	Attempts        *int                `json:"attempts,omitempty"`
+	DurationSeconds float64             `json:"durationSeconds"`
+	Name            string              `json:"name"`
+	Result          PluginOutcomeResult `json:"result"`
+}
+
+// PluginOutcomeResult defines model for PluginOutcome.Result.
+type PluginOutcomeResult string
+
+// ProjectStatus defines model for ProjectStatus.
+type ProjectStatus struct {
+	// Error Error of the last event, if it failed
+	Error *string `json:"error,omitempty"`
+
+	// EventType Type of the event in progress or last handled, create, update or delete
+	EventType    string             `json:"eventType"`
+	Organization string             `json:"organization"`
+	Project      string             `json:"project"`
+	State        ProjectStatusState `json:"state"`
+
+	// Updated When the last event finished, zero while an event is in progress
+	Updated time.Time `json:"updated"`
+	UUID    string    `json:"uuid"`
+}
+
+// ProjectStatusState defines model for ProjectStatus.State.
+type ProjectStatusState string
+
+// Reprovision defines model for Reprovision.
+type Reprovision struct {
+	EventType string `json:"eventType"`
+
+	// Plugins Plugins that handle the event, all if empty
+	Plugins *[]string `json:"plugins,omitempty"`
+	UUID    string    `json:"uuid"`
+}
+
+// ProjectUUID defines model for ProjectUUID.
+type ProjectUUID = string
+
+// bearerAuthContextKey is the context key for bearerAuth security scheme
+type bearerAuthContextKey string
+
+// ReprovisionProjectParams defines parameters for ReprovisionProject.
+type ReprovisionProjectParams struct {
+	// Plugin Plugin that handles the event, by its name or, for provisioners, the name without the Provisioner suffix, in any case. Repeat it for several plugins. All plugins handle the event if none is given.
+	Plugin *[]string `form:"plugin,omitempty" json:"plugin,omitempty"`
+}
+
+// RequestEditorFn  is the function signature for the RequestEditor callback function
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// Doer performs HTTP requests.
+//
+// The standard http.Client implements this interface.
+type HttpRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client which conforms to the OpenAPI3 specification for this service.
+type Client struct {
+	// The endpoint of the server conforming to this interface, with scheme,
+	// https://api.deepmap.com for example. This can contain a path relative
+	// to the server, such as https://api.deepmap.com/dev-test, and all the
+	// paths in the swagger spec will be appended to the server.
+	Server string
+
+	// Doer for performing requests, typically a *http.Client with any
+	// customized settings, such as certificate chains.
+	Client HttpRequestDoer
+
+	// A list of callbacks for modifying requests which are generated before sending over
+	// the network.
+	RequestEditors []RequestEditorFn
+}
+
+// ClientOption allows setting custom parameters during construction
+type ClientOption func(*Client) error
+
+// Creates a new Client, with reasonable defaults
+func NewClient(server string, opts ...ClientOption) (*Client, error) {
+	// create a client with sane default values
+	client := Client{
+		Server: server,
+	}
+	// mutate client and add all optional params
+	for _, o := range opts {
+		if err := o(&client); err != nil {
+			return nil, err
+		}
+	}
+	// ensure the server URL always has a trailing slash
+	if !strings.HasSuffix(client.Server, "/") {
+		client.Server += "/"
+	}
+	// create httpClient, if not already present
+	if client.Client == nil {
+		client.Client = &http.Client{}
+	}
+	return &client, nil
+}
+
+// WithHTTPClient allows overriding the default Doer, which is
+// automatically created using http.Client. This is useful for tests.
+func WithHTTPClient(doer HttpRequestDoer) ClientOption {
+	return func(c *Client) error {
+		c.Client = doer
+		return nil
+	}
+}
+
+// WithRequestEditorFn allows setting up a callback function, which will be
+// called right before sending the request. This can be used to mutate the request.
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
+// The interface specification for the client above.
+type ClientInterface interface {
+	// ListDebugDeployments request
+	ListDebugDeployments(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListDebugRegistries request
+	ListDebugRegistries(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ReloadHarborCredentials request
+	ReloadHarborCredentials(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ReprovisionProject request
+	ReprovisionProject(ctx context.Context, uuid ProjectUUID, params *ReprovisionProjectParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// RestoreProject request
+	RestoreProject(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetOpenAPISpecJSON request
+	GetOpenAPISpecJSON(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetOpenAPISpec request
+	GetOpenAPISpec(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetProjectExport request
+	GetProjectExport(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetProjectHistory request
+	GetProjectHistory(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetProjectStatus request
+	GetProjectStatus(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+func (c *Client) ListDebugDeployments(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListDebugDeploymentsRequest(c.Server, uuid)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListDebugRegistries(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListDebugRegistriesRequest(c.Server, uuid)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ReloadHarborCredentials(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewReloadHarborCredentialsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ReprovisionProject(ctx context.Context, uuid ProjectUUID, params *ReprovisionProjectParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewReprovisionProjectRequest(c.Server, uuid, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) RestoreProject(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewRestoreProjectRequest(c.Server, uuid)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetOpenAPISpecJSON(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetOpenAPISpecJSONRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetOpenAPISpec(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetOpenAPISpecRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetProjectExport(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetProjectExportRequest(c.Server, uuid)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetProjectHistory(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetProjectHistoryRequest(c.Server, uuid)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetProjectStatus(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetProjectStatusRequest(c.Server, uuid)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewListDebugDeploymentsRequest generates requests for ListDebugDeployments
+func NewListDebugDeploymentsRequest(server string, uuid ProjectUUID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithOptions("simple", false, "uuid", uuid, runtime.StyleParamOptions{ParamLocation: runtime.ParamLocationPath, Type: "string", Format: ""})
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/admin/debug/projects/%s/deployments", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewListDebugRegistriesRequest generates requests for ListDebugRegistries
+func NewListDebugRegistriesRequest(server string, uuid ProjectUUID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithOptions("simple", false, "uuid", uuid, runtime.StyleParamOptions{ParamLocation: runtime.ParamLocationPath, Type: "string", Format: ""})
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/admin/debug/projects/%s/registries", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewReloadHarborCredentialsRequest generates requests for ReloadHarborCredentials
+func NewReloadHarborCredentialsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/admin/harbor-credentials/reload")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewReprovisionProjectRequest generates requests for ReprovisionProject
+func NewReprovisionProjectRequest(server string, uuid ProjectUUID, params *ReprovisionProjectParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithOptions("simple", false, "uuid", uuid, runtime.StyleParamOptions{ParamLocation: runtime.ParamLocationPath, Type: "string", Format: ""})
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/admin/projects/%s/reprovision", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		// queryValues collects non-styled parameters (passthrough, JSON)
+		// that are safe to round-trip through url.Values.Encode().
+		queryValues := queryURL.Query()
+		// rawQueryFragments collects pre-encoded query fragments from
+		// styled parameters, preserving literal commas as delimiters
+		// per the OpenAPI spec (e.g. "color=blue,black,brown").
+		var rawQueryFragments []string
+
+		if params.Plugin != nil {
+
+			if queryFrag, err := runtime.StyleParamWithOptions("form", true, "plugin", *params.Plugin, runtime.StyleParamOptions{ParamLocation: runtime.ParamLocationQuery, Type: "array", Format: ""}); err != nil {
+				return nil, err
+			} else {
+				for _, qp := range strings.Split(queryFrag, "&") {
+					rawQueryFragments = append(rawQueryFragments, qp)
+				}
+			}
+
+		}
+
+		if encoded := queryValues.Encode(); encoded != "" {
+			rawQueryFragments = append(rawQueryFragments, encoded)
+		}
+		queryURL.RawQuery = strings.Join(rawQueryFragments, "&")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewRestoreProjectRequest generates requests for RestoreProject
+func NewRestoreProjectRequest(server string, uuid ProjectUUID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithOptions("simple", false, "uuid", uuid, runtime.StyleParamOptions{ParamLocation: runtime.ParamLocationPath, Type: "string", Format: ""})
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/admin/projects/%s/restore", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetOpenAPISpecJSONRequest generates requests for GetOpenAPISpecJSON
+func NewGetOpenAPISpecJSONRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/openapi.json")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetOpenAPISpecRequest generates requests for GetOpenAPISpec
+func NewGetOpenAPISpecRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/openapi.yaml")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetProjectExportRequest generates requests for GetProjectExport
+func NewGetProjectExportRequest(server string, uuid ProjectUUID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithOptions("simple", false, "uuid", uuid, runtime.StyleParamOptions{ParamLocation: runtime.ParamLocationPath, Type: "string", Format: ""})
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/projects/%s/export", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetProjectHistoryRequest generates requests for GetProjectHistory
+func NewGetProjectHistoryRequest(server string, uuid ProjectUUID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithOptions("simple", false, "uuid", uuid, runtime.StyleParamOptions{ParamLocation: runtime.ParamLocationPath, Type: "string", Format: ""})
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/projects/%s/history", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetProjectStatusRequest generates requests for GetProjectStatus
+func NewGetProjectStatusRequest(server string, uuid ProjectUUID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithOptions("simple", false, "uuid", uuid, runtime.StyleParamOptions{ParamLocation: runtime.ParamLocationPath, Type: "string", Format: ""})
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/projects/%s/status", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// ListDebugDeploymentsWithResponse request
+	ListDebugDeploymentsWithResponse(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*ListDebugDeploymentsResponse, error)
+
+	// ListDebugRegistriesWithResponse request
+	ListDebugRegistriesWithResponse(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*ListDebugRegistriesResponse, error)
+
+	// ReloadHarborCredentialsWithResponse request
+	ReloadHarborCredentialsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ReloadHarborCredentialsResponse, error)
+
+	// ReprovisionProjectWithResponse request
+	ReprovisionProjectWithResponse(ctx context.Context, uuid ProjectUUID, params *ReprovisionProjectParams, reqEditors ...RequestEditorFn) (*ReprovisionProjectResponse, error)
+
+	// RestoreProjectWithResponse request
+	RestoreProjectWithResponse(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*RestoreProjectResponse, error)
+
+	// GetOpenAPISpecJSONWithResponse request
+	GetOpenAPISpecJSONWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetOpenAPISpecJSONResponse, error)
+
+	// GetOpenAPISpecWithResponse request
+	GetOpenAPISpecWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetOpenAPISpecResponse, error)
+
+	// GetProjectExportWithResponse request
+	GetProjectExportWithResponse(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*GetProjectExportResponse, error)
+
+	// GetProjectHistoryWithResponse request
+	GetProjectHistoryWithResponse(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*GetProjectHistoryResponse, error)
+
+	// GetProjectStatusWithResponse request
+	GetProjectStatusWithResponse(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*GetProjectStatusResponse, error)
+}
+
+type ListDebugDeploymentsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]DebugDeployment
+}
+
+// Status returns HTTPResponse.Status
+func (r ListDebugDeploymentsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListDebugDeploymentsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// ContentType is a convenience method to retrieve the Content-Type value from the HTTP response headers
+func (r ListDebugDeploymentsResponse) ContentType() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Header.Get("Content-Type")
+	}
+	return ""
+}
+
+type ListDebugRegistriesResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]DebugRegistry
+}
+
+// Status returns HTTPResponse.Status
+func (r ListDebugRegistriesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListDebugRegistriesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// ContentType is a convenience method to retrieve the Content-Type value from the HTTP response headers
+func (r ListDebugRegistriesResponse) ContentType() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Header.Get("Content-Type")
+	}
+	return ""
+}
+
+type ReloadHarborCredentialsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *CredentialReload
+}
+
+// Status returns HTTPResponse.Status
+func (r ReloadHarborCredentialsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ReloadHarborCredentialsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// ContentType is a convenience method to retrieve the Content-Type value from the HTTP response headers
+func (r ReloadHarborCredentialsResponse) ContentType() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Header.Get("Content-Type")
+	}
+	return ""
+}
+
+type ReprovisionProjectResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON202      *Reprovision
+}
+
+// Status returns HTTPResponse.Status
+func (r ReprovisionProjectResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ReprovisionProjectResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// ContentType is a convenience method to retrieve the Content-Type value from the HTTP response headers
+func (r ReprovisionProjectResponse) ContentType() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Header.Get("Content-Type")
+	}
+	return ""
+}
+
+type RestoreProjectResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON202      *Reprovision
+}
+
+// Status returns HTTPResponse.Status
+func (r RestoreProjectResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r RestoreProjectResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// ContentType is a convenience method to retrieve the Content-Type value from the HTTP response headers
+func (r RestoreProjectResponse) ContentType() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Header.Get("Content-Type")
+	}
+	return ""
+}
+
+type GetOpenAPISpecJSONResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *map[string]interface{}
+}
+
+// Status returns HTTPResponse.Status
+func (r GetOpenAPISpecJSONResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetOpenAPISpecJSONResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// ContentType is a convenience method to retrieve the Content-Type value from the HTTP response headers
+func (r GetOpenAPISpecJSONResponse) ContentType() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Header.Get("Content-Type")
+	}
+	return ""
+}
+
+type GetOpenAPISpecResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	YAML200      *map[string]interface{}
+}
+
+// Status returns HTTPResponse.Status
+func (r GetOpenAPISpecResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetOpenAPISpecResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// ContentType is a convenience method to retrieve the Content-Type value from the HTTP response headers
+func (r GetOpenAPISpecResponse) ContentType() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Header.Get("Content-Type")
+	}
+	return ""
+}
+
+type GetProjectExportResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ExportBundle
+}
+
+// Status returns HTTPResponse.Status
+func (r GetProjectExportResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetProjectExportResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// ContentType is a convenience method to retrieve the Content-Type value from the HTTP response headers
+func (r GetProjectExportResponse) ContentType() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Header.Get("Content-Type")
+	}
+	return ""
+}
+
+type GetProjectHistoryResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *History
+}
+
+// Status returns HTTPResponse.Status
+func (r GetProjectHistoryResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetProjectHistoryResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// ContentType is a convenience method to retrieve the Content-Type value from the HTTP response headers
+func (r GetProjectHistoryResponse) ContentType() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Header.Get("Content-Type")
+	}
+	return ""
+}
+
+type GetProjectStatusResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ProjectStatus
+}
+
+// Status returns HTTPResponse.Status
+func (r GetProjectStatusResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetProjectStatusResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// ContentType is a convenience method to retrieve the Content-Type value from the HTTP response headers
+func (r GetProjectStatusResponse) ContentType() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Header.Get("Content-Type")
+	}
+	return ""
+}
+
+// ListDebugDeploymentsWithResponse request returning *ListDebugDeploymentsResponse
+func (c *ClientWithResponses) ListDebugDeploymentsWithResponse(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*ListDebugDeploymentsResponse, error) {
+	rsp, err := c.ListDebugDeployments(ctx, uuid, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListDebugDeploymentsResponse(rsp)
+}
+
+// ListDebugRegistriesWithResponse request returning *ListDebugRegistriesResponse
+func (c *ClientWithResponses) ListDebugRegistriesWithResponse(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*ListDebugRegistriesResponse, error) {
+	rsp, err := c.ListDebugRegistries(ctx, uuid, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListDebugRegistriesResponse(rsp)
+}
+
+// ReloadHarborCredentialsWithResponse request returning *ReloadHarborCredentialsResponse
+func (c *ClientWithResponses) ReloadHarborCredentialsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ReloadHarborCredentialsResponse, error) {
+	rsp, err := c.ReloadHarborCredentials(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseReloadHarborCredentialsResponse(rsp)
+}
+
+// ReprovisionProjectWithResponse request returning *ReprovisionProjectResponse
+func (c *ClientWithResponses) ReprovisionProjectWithResponse(ctx context.Context, uuid ProjectUUID, params *ReprovisionProjectParams, reqEditors ...RequestEditorFn) (*ReprovisionProjectResponse, error) {
+	rsp, err := c.ReprovisionProject(ctx, uuid, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseReprovisionProjectResponse(rsp)
+}
+
+// RestoreProjectWithResponse request returning *RestoreProjectResponse
+func (c *ClientWithResponses) RestoreProjectWithResponse(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*RestoreProjectResponse, error) {
+	rsp, err := c.RestoreProject(ctx, uuid, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRestoreProjectResponse(rsp)
+}
+
+// GetOpenAPISpecJSONWithResponse request returning *GetOpenAPISpecJSONResponse
+func (c *ClientWithResponses) GetOpenAPISpecJSONWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetOpenAPISpecJSONResponse, error) {
+	rsp, err := c.GetOpenAPISpecJSON(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetOpenAPISpecJSONResponse(rsp)
+}
+
+// GetOpenAPISpecWithResponse request returning *GetOpenAPISpecResponse
+func (c *ClientWithResponses) GetOpenAPISpecWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetOpenAPISpecResponse, error) {
+	rsp, err := c.GetOpenAPISpec(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetOpenAPISpecResponse(rsp)
+}
+
+// GetProjectExportWithResponse request returning *GetProjectExportResponse
+func (c *ClientWithResponses) GetProjectExportWithResponse(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*GetProjectExportResponse, error) {
+	rsp, err := c.GetProjectExport(ctx, uuid, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetProjectExportResponse(rsp)
+}
+
+// GetProjectHistoryWithResponse request returning *GetProjectHistoryResponse
+func (c *ClientWithResponses) GetProjectHistoryWithResponse(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*GetProjectHistoryResponse, error) {
+	rsp, err := c.GetProjectHistory(ctx, uuid, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetProjectHistoryResponse(rsp)
+}
+
+// GetProjectStatusWithResponse request returning *GetProjectStatusResponse
+func (c *ClientWithResponses) GetProjectStatusWithResponse(ctx context.Context, uuid ProjectUUID, reqEditors ...RequestEditorFn) (*GetProjectStatusResponse, error) {
+	rsp, err := c.GetProjectStatus(ctx, uuid, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetProjectStatusResponse(rsp)
+}
+
+// ParseListDebugDeploymentsResponse parses an HTTP response from a ListDebugDeploymentsWithResponse call
+func ParseListDebugDeploymentsResponse(rsp *http.Response) (*ListDebugDeploymentsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListDebugDeploymentsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []DebugDeployment
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListDebugRegistriesResponse parses an HTTP response from a ListDebugRegistriesWithResponse call
+func ParseListDebugRegistriesResponse(rsp *http.Response) (*ListDebugRegistriesResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListDebugRegistriesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []DebugRegistry
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseReloadHarborCredentialsResponse parses an HTTP response from a ReloadHarborCredentialsWithResponse call
+func ParseReloadHarborCredentialsResponse(rsp *http.Response) (*ReloadHarborCredentialsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReloadHarborCredentialsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest CredentialReload
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseReprovisionProjectResponse parses an HTTP response from a ReprovisionProjectWithResponse call
+func ParseReprovisionProjectResponse(rsp *http.Response) (*ReprovisionProjectResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReprovisionProjectResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 202:
+		var dest Reprovision
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON202 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseRestoreProjectResponse parses an HTTP response from a RestoreProjectWithResponse call
+func ParseRestoreProjectResponse(rsp *http.Response) (*RestoreProjectResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RestoreProjectResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 202:
+		var dest Reprovision
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON202 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetOpenAPISpecJSONResponse parses an HTTP response from a GetOpenAPISpecJSONWithResponse call
+func ParseGetOpenAPISpecJSONResponse(rsp *http.Response) (*GetOpenAPISpecJSONResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetOpenAPISpecJSONResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest map[string]interface{}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetOpenAPISpecResponse parses an HTTP response from a GetOpenAPISpecWithResponse call
+func ParseGetOpenAPISpecResponse(rsp *http.Response) (*GetOpenAPISpecResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetOpenAPISpecResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "yaml") && rsp.StatusCode == 200:
+		var dest map[string]interface{}
+		if err := yaml.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.YAML200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetProjectExportResponse parses an HTTP response from a GetProjectExportWithResponse call
+func ParseGetProjectExportResponse(rsp *http.Response) (*GetProjectExportResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetProjectExportResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ExportBundle
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetProjectHistoryResponse parses an HTTP response from a GetProjectHistoryWithResponse call
+func ParseGetProjectHistoryResponse(rsp *http.Response) (*GetProjectHistoryResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetProjectHistoryResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest History
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetProjectStatusResponse parses an HTTP response from a GetProjectStatusWithResponse call
+func ParseGetProjectStatusResponse(rsp *http.Response) (*GetProjectStatusResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetProjectStatusResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ProjectStatus
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
@@ -0,0 +1,23 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package api holds the OpenAPI contract of the HTTP API of the tenant controller, and the types and client
+// generated from it. The controller serves the same specification at SpecPath.
+package api
+
+import (
+	_ "embed"
+)
+
+//go:generate go tool oapi-codegen -config oapi-codegen.yaml openapi.yaml
+
+// SpecPath is the path of the OpenAPI specification, after the base URL of the API
+const SpecPath = "/api/v1/openapi.yaml"
+
+// SpecJSONPath is the path of the OpenAPI specification encoded as JSON
+const SpecJSONPath = "/api/v1/openapi.json"
+
+// Spec is the OpenAPI 3 specification of the API, as YAML
+//
+//go:embed openapi.yaml
+var Spec []byte
@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/oapi-codegen/oapi-codegen/v2/pkg/codegen"
+	"github.com/oapi-codegen/oapi-codegen/v2/pkg/util"
+	"github.com/stretchr/testify/suite"
+	"go.yaml.in/yaml/v3"
+)
+
+// Suite of API contract tests
+type APITestSuite struct {
+	suite.Suite
+}
+
+func TestAPI(t *testing.T) {
+	suite.Run(t, &APITestSuite{})
+}
+
+func (s *APITestSuite) TestSpec() {
+	spec, err := openapi3.NewLoader().LoadFromData(Spec)
+	s.Require().NoError(err)
+	s.NoError(spec.Validate(context.Background()))
+	s.NotNil(spec.Paths.Find(SpecPath))
+	s.NotNil(spec.Paths.Find(SpecJSONPath))
+}
+
+// The generated code is kept in sync with the specification, so that a changed specification fails until the code is
+// generated again with go generate.
+func (s *APITestSuite) TestGeneratedCode() {
+	configuration, err := os.ReadFile("oapi-codegen.yaml")
+	s.Require().NoError(err)
+	opts := codegen.Configuration{}
+	s.Require().NoError(yaml.Unmarshal(configuration, &opts))
+	swagger, err := util.LoadSwagger("openapi.yaml")
+	s.Require().NoError(err)
+	code, err := codegen.Generate(swagger, opts)
+	s.Require().NoError(err)
+
+	generated, err := os.ReadFile("api.gen.go")
+	s.Require().NoError(err)
+	// The header names the version of the generator, which is not known in tests
+	_, code, _ = strings.Cut(code, "\npackage api\n")
+	_, expected, _ := strings.Cut(string(generated), "\npackage api\n")
+	s.Equal(expected, code, "api.gen.go is out of date, run go generate ./pkg/api")
+}
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package client gives other orchestrator services typed access to the status API of the tenant controller, so that
+// they can hold back operations on a project until it is provisioned.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Provisioning states of a project
+const (
+	// an event of the project is queued or being handled
+	StateProvisioning = "provisioning"
+	// the last create or update event of the project succeeded
+	StateReady = "ready"
+	// the last event of the project failed; a later event may still provision it
+	StateFailed = "failed"
+	// the project is being deleted
+	StateDeleting = "deleting"
+	// the project was deleted
+	StateDeleted = "deleted"
+)
+
+// StatusPath is the path of the status of a project, after the base URL of the API
+const StatusPath = "/api/v1/projects/%s/status"
+
+// DefaultPollInterval is the time between status requests while waiting for a project
+const DefaultPollInterval = 2 * time.Second
+
+var (
+	// ErrNotFound is returned when the controller has not handled any event of the project
+	ErrNotFound = errors.New("project not found")
+	// ErrProvisioningFailed is returned when waiting for a project whose provisioning failed
+	ErrProvisioningFailed = errors.New("project provisioning failed")
+	// ErrProjectDeleted is returned when waiting for a project that is deleted or being deleted
+	ErrProjectDeleted = errors.New("project deleted")
+)
+
+// ProjectStatus is the provisioning state of a project.
+type ProjectStatus struct {
+	Organization string `json:"organization"`
+	Project      string `json:"project"`
+	UUID         string `json:"uuid"`
+	// one of the State constants
+	State string `json:"state"`
+	// type of the event in progress or last handled: create, update or delete
+	EventType string `json:"eventType"`
+	// error of the last event, if it failed
+	Error string `json:"error,omitempty"`
+	// when the last event finished, zero while an event is in progress
+	Updated time.Time `json:"updated"`
+}
+
+// Client reads the provisioning state of projects from the status API of the tenant controller.
+type Client struct {
+	baseURL      string
+	httpClient   *http.Client
+	pollInterval time.Duration
+}
+
+// New returns a client of the API served at the base URL, e.g. http://app-orch-tenant-controller.orch-app:8091.
+func New(baseURL string) *Client {
+	return &Client{
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+		pollInterval: DefaultPollInterval,
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for the requests.
+func (c *Client) WithHTTPClient(httpClient *http.Client) *Client {
+	c.httpClient = httpClient
+	return c
+}
+
+// WithPollInterval sets the time between status requests while waiting for a project.
+func (c *Client) WithPollInterval(interval time.Duration) *Client {
+	c.pollInterval = interval
+	return c
+}
+
+// GetProjectStatus returns the provisioning state of the project with the given UUID. It returns an error wrapping
+// ErrNotFound if the controller has not handled any event of the project.
+func (c *Client) GetProjectStatus(ctx context.Context, uuid string) (*ProjectStatus, error) {
+	statusURL := c.baseURL + fmt.Sprintf(StatusPath, url.PathEscape(uuid))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, statusURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, uuid)
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unexpected response %d from the tenant controller: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	status := &ProjectStatus{}
+	if err := json.NewDecoder(resp.Body).Decode(status); err != nil {
+		return nil, fmt.Errorf("invalid project status: %w", err)
+	}
+	return status, nil
+}
+
+// WaitForProjectReady polls the status of the project until it is ready and returns it. It returns an error
+// wrapping ErrProvisioningFailed or ErrProjectDeleted as soon as the project reaches one of these states. Projects
+// that are not found yet are waited for, as their first event may not have been handled yet, and so are failed
+// requests, e.g. while the controller restarts. Once the context is done, the context error is returned together
+// with the error of the last request, if it failed.
+func (c *Client) WaitForProjectReady(ctx context.Context, uuid string) (*ProjectStatus, error) {
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+	var lastErr error
+	for {
+		status, err := c.GetProjectStatus(ctx, uuid)
+		if err != nil && ctx.Err() == nil {
+			lastErr = err
+		}
+		if err == nil {
+			lastErr = nil
+			switch status.State {
+			case StateReady:
+				return status, nil
+			case StateFailed:
+				return status, fmt.Errorf("%w: %s", ErrProvisioningFailed, status.Error)
+			case StateDeleting, StateDeleted:
+				return status, fmt.Errorf("%w: %s", ErrProjectDeleted, uuid)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w: %w", ctx.Err(), lastErr)
+			}
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Suite of status client tests
+type ClientTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu sync.Mutex
+	// statuses returned by the test server, one per request, the last one repeated; nil answers 404 Not Found
+	statuses []*ProjectStatus
+	// status code of the responses, if not 200 OK
+	code     int
+	requests int
+	server   *httptest.Server
+}
+
+func (s *ClientTestSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	s.statuses = nil
+	s.code = 0
+	s.requests = 0
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveStatus))
+}
+
+func (s *ClientTestSuite) TearDownTest() {
+	s.server.Close()
+	s.cancel()
+}
+
+func TestClient(t *testing.T) {
+	suite.Run(t, &ClientTestSuite{})
+}
+
+func (s *ClientTestSuite) serveStatus(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if req.URL.Path != "/api/v1/projects/uuid-1/status" {
+		http.NotFound(w, req)
+		return
+	}
+	if s.code != 0 {
+		http.Error(w, "unavailable", s.code)
+		return
+	}
+	status := s.statuses[min(s.requests, len(s.statuses))-1]
+	if status == nil {
+		http.NotFound(w, req)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+func (s *ClientTestSuite) client() *Client {
+	return New(s.server.URL + "/").WithPollInterval(10 * time.Millisecond)
+}
+
+func (s *ClientTestSuite) TestGetProjectStatus() {
+	s.statuses = []*ProjectStatus{{Organization: "org", Project: "proj", UUID: "uuid-1", State: StateReady, EventType: "create"}}
+	status, err := s.client().GetProjectStatus(s.ctx, "uuid-1")
+	s.NoError(err)
+	s.Equal("proj", status.Project)
+	s.Equal(StateReady, status.State)
+
+	_, err = s.client().GetProjectStatus(s.ctx, "uuid-2")
+	s.ErrorIs(err, ErrNotFound)
+
+	s.code = http.StatusInternalServerError
+	_, err = s.client().GetProjectStatus(s.ctx, "uuid-1")
+	s.ErrorContains(err, "unexpected response 500 from the tenant controller: unavailable")
+}
+
+func (s *ClientTestSuite) TestWaitForProjectReady() {
+	s.statuses = []*ProjectStatus{nil, {State: StateProvisioning}, {State: StateReady, UUID: "uuid-1"}}
+	status, err := s.client().WaitForProjectReady(s.ctx, "uuid-1")
+	s.NoError(err)
+	s.Equal(StateReady, status.State)
+	s.Equal(3, s.requests)
+}
+
+func (s *ClientTestSuite) TestWaitForProjectReadyFails() {
+	s.statuses = []*ProjectStatus{{State: StateProvisioning}, {State: StateFailed, Error: "harbor unavailable"}}
+	status, err := s.client().WaitForProjectReady(s.ctx, "uuid-1")
+	s.ErrorIs(err, ErrProvisioningFailed)
+	s.ErrorContains(err, "harbor unavailable")
+	s.Equal(StateFailed, status.State)
+
+	s.requests = 0
+	s.statuses = []*ProjectStatus{{State: StateDeleting}}
+	_, err = s.client().WaitForProjectReady(s.ctx, "uuid-1")
+	s.ErrorIs(err, ErrProjectDeleted)
+}
+
+func (s *ClientTestSuite) TestWaitForProjectReadyTimeout() {
+	ctx, cancel := context.WithTimeout(s.ctx, 100*time.Millisecond)
+	defer cancel()
+	s.code = http.StatusServiceUnavailable
+	_, err := s.client().WaitForProjectReady(ctx, "uuid-1")
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.ErrorContains(err, "unexpected response 503")
+}
@@ -119,8 +119,6 @@ func (suite *ComponentTestSuite) SetupSuite() {
 func (suite *ComponentTestSuite) TearDownSuite() {
 	log.Printf("Tearing down component test suite")
 
-	suite.printTestCoverageSummary()
-
 	if suite.cancel != nil {
 		suite.cancel()
 	}
@@ -139,7 +137,6 @@ func (suite *ComponentTestSuite) setupTenantControllerComponents() {
 		ReleaseServiceBase:         "localhost:8081",  // Harbor release service
 		KeycloakServiceBase:        suite.keycloakURL, // localhost:8080
 		AdmServer:                  "localhost:8084",  // localhost:8084
-		KeycloakSecret:             "platform-keycloak",
 		ServiceAccount:             "orch-svc",
 		VaultServer:                "http://localhost:8200", // localhost:8200
 		KeycloakServer:             suite.keycloakURL,       // localhost:8080
@@ -148,12 +145,13 @@ func (suite *ComponentTestSuite) setupTenantControllerComponents() {
 		ReleaseServiceProxyRootURL: "oci://localhost:8081",  // Harbor OCI
 		ManifestPath:               "/edge-orch/en/files/manifest",
 		ManifestTag:                "latest",
-		KeycloakNamespace:          "orch-platform",
-		HarborNamespace:            "orch-harbor",
-		HarborAdminCredential:      "harbor-admin-credential",
-		NumberWorkerThreads:        2,
-		InitialSleepInterval:       60 * time.Second,
-		MaxWaitTime:                600 * time.Second,
+		Secrets: config.K8sSecretsRef{
+			HarborAdmin:   config.SecretRef{Namespace: "orch-harbor", Name: "harbor-admin-credential", Key: config.DefaultHarborAdminCredentialKey},
+			KeycloakAdmin: config.SecretRef{Namespace: "orch-platform", Name: "platform-keycloak", Key: config.DefaultKeycloakSecretKey},
+		},
+		NumberWorkerThreads:  2,
+		InitialSleepInterval: 60 * time.Second,
+		MaxWaitTime:          600 * time.Second,
 	}
 
 	// Clear any existing plugins
@@ -181,8 +179,7 @@ func (suite *ComponentTestSuite) registerRealPlugins() {
 		suite.ctx,
 		suite.config.HarborServer,
 		suite.config.KeycloakServer,
-		suite.config.HarborNamespace,
-		suite.config.KeycloakSecret,
+		suite.config.Secrets.HarborAdmin,
 	)
 	suite.Require().NoError(err, "Harbor plugin creation must succeed")
 	plugins.Register(harborPlugin)
@@ -1715,7 +1712,7 @@ func (suite *ComponentTestSuite) testExtensionsAndReleaseServiceIntegration() {
 				strings.Contains(manifestContent, "schemaVersion") ||
 				strings.Contains(manifestContent, "lpke"),
 			"Manifest should contain expected structure")
-		// Extensions provisioner calls: catalog.UploadYAMLFile(ctx, projectUUID, fileName, artifact, lastFile)
+		// Extensions provisioner calls: catalog.CommitUpload(ctx, projectUUID, upload) with the files of every package
 		suite.testCatalogPackageUpload()
 	}
 
@@ -1757,7 +1754,8 @@ lpke:
 func (suite *ComponentTestSuite) testCatalogPackageUpload() {
 	log.Printf("Testing catalog package upload workflow (as per extensions provisioner)")
 
-	// As per extensions-provisioner.go: catalog.UploadYAMLFile(ctx, projectUUID, fileName, artifact, lastFile)
+	// As per extensions-provisioner.go: catalog.CommitUpload(ctx, projectUUID, upload), which uploads the files of every
+	// package in a single session
 	// This uploads extension packages (YAML files) to the catalog
 
 	mockYAMLContent := `
@@ -1930,7 +1928,7 @@ func (suite *ComponentTestSuite) testWorkerThreadManagement() {
 			defer cancel()
 
 			startTime := time.Now()
-			err := plugins.Dispatch(ctx, evt, nil)
+			_, err := plugins.Dispatch(ctx, evt, nil)
 			duration := time.Since(startTime)
 
 			log.Printf("Event %d dispatch took %v, error: %v", idx, duration, err)
@@ -2087,7 +2085,7 @@ func (suite *ComponentTestSuite) testRealPluginSystemWorkflow() {
 	defer cancel()
 
 	startTime := time.Now()
-	err := plugins.Dispatch(dispatchCtx, event, nil)
+	_, err := plugins.Dispatch(dispatchCtx, event, nil)
 	createDuration := time.Since(startTime)
 
 	log.Printf("Plugin dispatch took: %v", createDuration)
@@ -2119,7 +2117,7 @@ func (suite *ComponentTestSuite) testRealPluginSystemWorkflow() {
 	defer cancel()
 
 	startTime = time.Now()
-	err = plugins.Dispatch(deleteCtx, deleteEvent, nil)
+	_, err = plugins.Dispatch(deleteCtx, deleteEvent, nil)
 	deleteDuration := time.Since(startTime)
 
 	log.Printf("Plugin DELETE dispatch took: %v", deleteDuration)
@@ -2160,13 +2158,6 @@ func (suite *ComponentTestSuite) verifyDeleteWorkflowAttempted(duration time.Dur
 		"DELETE workflow must have been attempted (ran for at least 100ms), got: %v", duration)
 }
 
-// printTestCoverageSummary logs completion of component tests
-func (suite *ComponentTestSuite) printTestCoverageSummary() {
-	log.Printf("======================================================================")
-	log.Printf("COMPONENT TEST SUITE COMPLETED")
-	log.Printf("======================================================================")
-}
-
 // Run the test suite
 func TestComponentTestSuite(t *testing.T) {
 	suite.Run(t, new(ComponentTestSuite))
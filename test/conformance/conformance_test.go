@@ -0,0 +1,471 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	folderv1 "github.com/open-edge-platform/orch-utils/tenancy-datamodel/build/apis/folder.edge-orchestrator.intel.com/v1"
+	orgv1 "github.com/open-edge-platform/orch-utils/tenancy-datamodel/build/apis/org.edge-orchestrator.intel.com/v1"
+	projectv1 "github.com/open-edge-platform/orch-utils/tenancy-datamodel/build/apis/project.edge-orchestrator.intel.com/v1"
+	projectActiveWatcherv1 "github.com/open-edge-platform/orch-utils/tenancy-datamodel/build/apis/projectactivewatcher.edge-orchestrator.intel.com/v1"
+	nexus "github.com/open-edge-platform/orch-utils/tenancy-datamodel/build/nexus-client"
+	"github.com/stretchr/testify/suite"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	nexushook "github.com/open-edge-platform/app-orch-tenant-controller/internal/nexus"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/plugins"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	"github.com/open-edge-platform/app-orch-tenant-controller/pkg/client"
+	"github.com/open-edge-platform/app-orch-tenant-controller/test/utils/portforward"
+)
+
+var (
+	kubeconfig = flag.String("kubeconfig", "", "kubeconfig of the orchestrator cluster; the conformance suite is skipped without it")
+	domain     = flag.String("domain", "kind.internal", "domain of the orchestrator")
+	namespace  = flag.String("namespace", "orch-app", "namespace of the tenant controller")
+	apiURL     = flag.String("api-url", "", "base URL of the tenant controller API; the API service is port-forwarded if it is not set")
+	adminURL   = flag.String("admin-url", "", "base URL of the tenant controller admin API; the debug queries are skipped if it is not set")
+	adminToken = flag.String("admin-token", "", "bearer token of the tenant controller admin API")
+	reportDir  = flag.String("report-dir", ".", "directory the JUnit and HTML reports are written to")
+	wait       = flag.Duration("wait", 10*time.Minute, "time allowed for the project to be provisioned, and to be deleted")
+)
+
+const (
+	// name of the project watcher of the tenant controller
+	watcherName = "config-provisioner"
+	// folder of the projects of an organization
+	defaultFolder = "default"
+	// API port of the tenant controller service, and the local port it is forwarded to
+	apiPort        = 8091
+	apiForwardPort = 18091
+	pollInterval   = 5 * time.Second
+)
+
+// skipError is returned by checks that do not apply to the orchestrator, e.g. as the feature they check is disabled
+type skipError struct {
+	reason string
+}
+
+func (e skipError) Error() string {
+	return e.reason
+}
+
+func skip(format string, args ...interface{}) error {
+	return skipError{reason: fmt.Sprintf(format, args...)}
+}
+
+// ConformanceTestSuite provisions a disposable project on a live orchestrator and checks everything the tenant
+// controller does for it.
+type ConformanceTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+	report *Report
+
+	nexusClient *nexus.Clientset
+	k8sClient   kubernetes.Interface
+	inventories *southbound.InventoryStore
+	status      *client.Client
+	apiURL      string
+
+	organization string
+	project      string
+	uuid         string
+	inventory    *southbound.Inventory
+	// set if the resources of the deleted project are kept for its retention period
+	deactivated bool
+	// set once a check that the later checks depend on failed
+	blocked string
+}
+
+func TestConformance(t *testing.T) {
+	if *kubeconfig == "" {
+		t.Skip("set -kubeconfig to run the conformance suite against a live orchestrator")
+	}
+	suite.Run(t, &ConformanceTestSuite{})
+}
+
+func (s *ConformanceTestSuite) SetupSuite() {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	restConfig, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
+	s.Require().NoError(err, "Failed to load kubeconfig")
+	s.nexusClient, err = nexus.NewForConfig(restConfig)
+	s.Require().NoError(err)
+	s.k8sClient, err = kubernetes.NewForConfig(restConfig)
+	s.Require().NoError(err)
+	s.inventories, err = southbound.NewInventoryStore(restConfig, *namespace)
+	s.Require().NoError(err)
+
+	s.apiURL = *apiURL
+	if s.apiURL == "" {
+		// kubectl forwards the port of the same cluster
+		s.Require().NoError(os.Setenv("KUBECONFIG", *kubeconfig))
+		s.Require().NoError(portforward.SetupTenantController(*namespace, apiForwardPort, apiPort))
+		s.apiURL = fmt.Sprintf("http://localhost:%d", apiForwardPort)
+	}
+	s.status = client.New(s.apiURL).WithPollInterval(pollInterval)
+
+	// The names are random, so that runs against the same orchestrator do not collide
+	s.organization = fmt.Sprintf("conformance-%06x", rand.Uint32()&0xffffff)
+	s.project = "conformance-project"
+	s.report = NewReport("tenant-controller-conformance")
+	s.report.Properties["domain"] = *domain
+	s.report.Properties["namespace"] = *namespace
+	s.report.Properties["organization"] = s.organization
+	s.report.Properties["project"] = s.project
+	s.report.Properties["apiURL"] = s.apiURL
+	log.Printf("Running the conformance checks with project %s/%s", s.organization, s.project)
+}
+
+func (s *ConformanceTestSuite) TearDownSuite() {
+	// The organization is removed whatever the checks left behind
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	org := s.nexusClient.TenancyMultiTenancy().Config().Orgs(s.organization)
+	if err := org.Folders(defaultFolder).DeleteProjects(ctx, s.project); err != nil && !nexus.IsNotFound(err) && !nexus.IsChildNotFound(err) {
+		log.Printf("Unable to delete project %s/%s: %v", s.organization, s.project, err)
+	}
+	if err := s.nexusClient.TenancyMultiTenancy().Config().DeleteOrgs(ctx, s.organization); err != nil && !nexus.IsNotFound(err) && !nexus.IsChildNotFound(err) {
+		log.Printf("Unable to delete organization %s: %v", s.organization, err)
+	}
+
+	if s.report != nil {
+		if err := s.report.Save(*reportDir); err != nil {
+			log.Printf("Unable to save the conformance report: %v", err)
+		}
+		log.Printf("Conformance checks: %d passed, %d failed, %d skipped, reports written to %s",
+			s.report.Count(ResultPassed), s.report.Count(ResultFailed), s.report.Count(ResultSkipped), *reportDir)
+	}
+	portforward.Cleanup()
+	s.cancel()
+}
+
+// check runs a conformance check as a subtest and records its result in the report. If a required check fails, the
+// checks after it are skipped.
+func (s *ConformanceTestSuite) check(name string, required bool, run func() error) {
+	s.Run(name, func() {
+		if s.blocked != "" {
+			s.report.Add(Check{Name: name, Result: ResultSkipped, Message: "required check " + s.blocked + " failed"})
+			s.T().Skipf("required check %s failed", s.blocked)
+		}
+		started := time.Now()
+		err := run()
+		result := Check{Name: name, Result: ResultPassed, Duration: time.Since(started)}
+		var skipped skipError
+		switch {
+		case errors.As(err, &skipped):
+			result.Result = ResultSkipped
+			result.Message = skipped.reason
+			s.report.Add(result)
+			s.T().Skip(skipped.reason)
+		case err != nil:
+			result.Result = ResultFailed
+			result.Message = err.Error()
+			if required {
+				s.blocked = name
+			}
+		}
+		s.report.Add(result)
+		s.NoError(err)
+	})
+}
+
+// poll calls condition until it returns true, an error, or the time allowed by -wait is over. The last reason the
+// condition gave for not being met is returned with the timeout.
+func (s *ConformanceTestSuite) poll(condition func(ctx context.Context) (bool, string, error)) error {
+	ctx, cancel := context.WithTimeout(s.ctx, *wait)
+	defer cancel()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		done, reason, err := condition(ctx)
+		if err != nil || done {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %s", ctx.Err(), reason)
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ConformanceTestSuite) TestProjectLifecycle() {
+	s.check("CreateProject", true, s.createProject)
+	s.check("ProjectUID", true, s.waitForProjectUID)
+	s.check("StatusReady", true, s.waitForStatusReady)
+	s.check("WatcherIdle", false, s.checkWatcher)
+	s.check("Inventory", true, s.checkInventory)
+	s.check("WatcherInventory", false, s.checkWatcherInventory)
+	s.check("Namespace", false, s.checkNamespace)
+	s.check("CatalogRegistries", false, s.checkCatalogRegistries)
+	s.check("Deployments", false, s.checkDeployments)
+	s.check("DeleteProject", true, s.deleteProject)
+	s.check("StatusDeleted", false, s.waitForStatusDeleted)
+	s.check("InventoryRemoved", false, s.checkInventoryRemoved)
+	s.check("NamespaceRemoved", false, s.checkNamespaceRemoved)
+}
+
+// createProject creates the organization, its default folder and the project through the Nexus configuration
+// nodes, as the tenancy API does.
+func (s *ConformanceTestSuite) createProject() error {
+	config := s.nexusClient.TenancyMultiTenancy().Config()
+	_, err := config.AddOrgs(s.ctx, &orgv1.Org{
+		ObjectMeta: metav1.ObjectMeta{Name: s.organization},
+		Spec:       orgv1.OrgSpec{Description: "Tenant controller conformance checks"},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create organization %s: %w", s.organization, err)
+	}
+	org := config.Orgs(s.organization)
+	if _, err := org.AddFolders(s.ctx, &folderv1.Folder{ObjectMeta: metav1.ObjectMeta{Name: defaultFolder}}); err != nil && !nexus.IsAlreadyExists(err) {
+		return fmt.Errorf("unable to create the folder of organization %s: %w", s.organization, err)
+	}
+	_, err = org.Folders(defaultFolder).AddProjects(s.ctx, &projectv1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: s.project},
+		Spec:       projectv1.ProjectSpec{Description: "Tenant controller conformance checks"},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create project %s: %w", s.project, err)
+	}
+	return nil
+}
+
+// waitForProjectUID waits for the tenancy manager to assign the UUID of the project.
+func (s *ConformanceTestSuite) waitForProjectUID() error {
+	return s.poll(func(ctx context.Context) (bool, string, error) {
+		project, err := s.nexusClient.TenancyMultiTenancy().Config().Orgs(s.organization).Folders(defaultFolder).GetProjects(ctx, s.project)
+		if err != nil {
+			return false, err.Error(), nil
+		}
+		s.uuid = project.Status.ProjectStatus.UID
+		if s.uuid == "" {
+			return false, "the project has no UID", nil
+		}
+		s.report.Properties["uuid"] = s.uuid
+		return true, "", nil
+	})
+}
+
+func (s *ConformanceTestSuite) waitForStatusReady() error {
+	ctx, cancel := context.WithTimeout(s.ctx, *wait)
+	defer cancel()
+	_, err := s.status.WaitForProjectReady(ctx, s.uuid)
+	return err
+}
+
+// runtimeWatcher returns the project watcher of the tenant controller.
+func (s *ConformanceTestSuite) runtimeWatcher(ctx context.Context) (*nexus.ProjectactivewatcherProjectActiveWatcher, error) {
+	return s.nexusClient.TenancyMultiTenancy().Runtime().Orgs(s.organization).Folders(defaultFolder).
+		Projects(s.project).GetActiveWatchers(ctx, watcherName)
+}
+
+// checkWatcher checks that the project watcher reports the project as provisioned, with the versions applied to it.
+func (s *ConformanceTestSuite) checkWatcher() error {
+	return s.poll(func(ctx context.Context) (bool, string, error) {
+		watcher, err := s.runtimeWatcher(ctx)
+		if err != nil {
+			return false, err.Error(), nil
+		}
+		if watcher.Spec.StatusIndicator != projectActiveWatcherv1.StatusIndicationIdle {
+			return false, fmt.Sprintf("the watcher is %s: %s", watcher.Spec.StatusIndicator, watcher.Spec.Message), nil
+		}
+		versions := nexushook.ProvisionedVersionsFromAnnotations(watcher.GetAnnotations())
+		if versions.ManifestTag == "" || versions.ProvisionedAt.IsZero() {
+			return false, "the watcher does not record the provisioned manifest tag and time", nil
+		}
+		s.report.Properties["manifestTag"] = versions.ManifestTag
+		s.report.Properties["controllerVersion"] = versions.ControllerVersion
+		return true, "", nil
+	})
+}
+
+// checkInventory checks that the inventory of the project lists the resources every project is provisioned with.
+func (s *ConformanceTestSuite) checkInventory() error {
+	inventory, err := s.inventories.Load(s.ctx, s.uuid)
+	if err != nil {
+		return err
+	}
+	if inventory == nil {
+		return fmt.Errorf("project %s has no inventory", s.uuid)
+	}
+	s.inventory = inventory
+	if inventory.Organization != s.organization || inventory.Project != s.project {
+		return fmt.Errorf("the inventory is of project %s/%s", inventory.Organization, inventory.Project)
+	}
+	if inventory.HarborProject == nil || inventory.HarborProject.Name == "" {
+		return errors.New("the inventory has no Harbor project")
+	}
+	if len(inventory.HarborProject.Robots) == 0 {
+		return fmt.Errorf("harbor project %s has no robot accounts", inventory.HarborProject.Name)
+	}
+	if len(inventory.CatalogRegistries) == 0 {
+		return errors.New("the inventory has no catalog registries")
+	}
+	return nil
+}
+
+// checkWatcherInventory checks that the project watcher shows the inventory of the project.
+func (s *ConformanceTestSuite) checkWatcherInventory() error {
+	watcher, err := s.runtimeWatcher(s.ctx)
+	if err != nil {
+		return err
+	}
+	summary, ok := nexushook.InventorySummaryFromAnnotations(watcher.GetAnnotations())
+	if !ok {
+		return fmt.Errorf("the watcher has no valid %s annotation", nexushook.InventoryAnnotationKey)
+	}
+	if summary.HarborProject != s.inventory.HarborProject.Name {
+		return fmt.Errorf("the watcher shows Harbor project %q, the inventory lists %q", summary.HarborProject, s.inventory.HarborProject.Name)
+	}
+	if !summary.Truncated && !slices.Equal(summary.Registries, s.inventory.CatalogRegistries) {
+		return fmt.Errorf("the watcher shows registries %v, the inventory lists %v", summary.Registries, s.inventory.CatalogRegistries)
+	}
+	return nil
+}
+
+func (s *ConformanceTestSuite) checkNamespace() error {
+	if s.inventory.Namespace == "" {
+		return skip("the controller does not create project namespaces")
+	}
+	_, err := s.k8sClient.CoreV1().Namespaces().Get(s.ctx, s.inventory.Namespace, metav1.GetOptions{})
+	return err
+}
+
+// debugQuery runs a debug query of the controller admin API about the project. It returns a skipError if the admin
+// API is not given or debug queries are not enabled.
+func (s *ConformanceTestSuite) debugQuery(query string, result interface{}) error {
+	if *adminURL == "" {
+		return skip("debug queries are admin calls, set -admin-url and -admin-token")
+	}
+	queryURL := fmt.Sprintf("%s/api/v1/admin/debug/projects/%s/%s", *adminURL, url.PathEscape(s.uuid), query)
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodGet, queryURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+*adminToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNotFound && strings.Contains(string(body), "not enabled") {
+		return skip("debug query %s is not enabled, set configProvisioner.debugQueries", query)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("debug query %s answered %d: %s", query, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, result)
+}
+
+// checkCatalogRegistries checks that the registries of the inventory exist in the catalog, and that the Harbor
+// registries point at the Harbor of the orchestrator.
+func (s *ConformanceTestSuite) checkCatalogRegistries() error {
+	registries := []plugins.DebugRegistry{}
+	if err := s.debugQuery("registries", &registries); err != nil {
+		return err
+	}
+	for _, name := range s.inventory.CatalogRegistries {
+		index := slices.IndexFunc(registries, func(registry plugins.DebugRegistry) bool { return registry.Name == name })
+		if index < 0 {
+			return fmt.Errorf("registry %s is missing from the catalog", name)
+		}
+		registry := registries[index]
+		if strings.HasPrefix(name, s.inventory.CatalogRegistryPrefix+"harbor-") && !strings.Contains(registry.RootURL, *domain) {
+			return fmt.Errorf("registry %s points at %s, outside of domain %s", name, registry.RootURL, *domain)
+		}
+	}
+	return nil
+}
+
+// checkDeployments checks that the extension deployments of the inventory exist in ADM.
+func (s *ConformanceTestSuite) checkDeployments() error {
+	if len(s.inventory.Deployments) == 0 {
+		return skip("the project has no extension deployments")
+	}
+	deployments := []plugins.DebugDeployment{}
+	if err := s.debugQuery("deployments", &deployments); err != nil {
+		return err
+	}
+	for _, expected := range s.inventory.Deployments {
+		if !slices.ContainsFunc(deployments, func(deployment plugins.DebugDeployment) bool { return deployment.ID == expected.ID }) {
+			return fmt.Errorf("deployment %s (%s) is missing from ADM", expected.DisplayName, expected.ID)
+		}
+	}
+	return nil
+}
+
+func (s *ConformanceTestSuite) deleteProject() error {
+	return s.nexusClient.TenancyMultiTenancy().Config().Orgs(s.organization).Folders(defaultFolder).DeleteProjects(s.ctx, s.project)
+}
+
+func (s *ConformanceTestSuite) waitForStatusDeleted() error {
+	return s.poll(func(ctx context.Context) (bool, string, error) {
+		status, err := s.status.GetProjectStatus(ctx, s.uuid)
+		if err != nil {
+			return false, err.Error(), nil
+		}
+		if status.State == client.StateFailed {
+			return false, "", fmt.Errorf("the deletion failed: %s", status.Error)
+		}
+		return status.State == client.StateDeleted, "the project is " + status.State, nil
+	})
+}
+
+// checkInventoryRemoved checks that the inventory is removed with the project, or kept for its retention period if
+// the controller deactivates deleted projects.
+func (s *ConformanceTestSuite) checkInventoryRemoved() error {
+	return s.poll(func(ctx context.Context) (bool, string, error) {
+		inventory, err := s.inventories.Load(ctx, s.uuid)
+		if err != nil {
+			return false, "", err
+		}
+		if inventory != nil && inventory.Deactivation == nil {
+			return false, "the inventory is still present", nil
+		}
+		s.deactivated = inventory != nil
+		return true, "", nil
+	})
+}
+
+func (s *ConformanceTestSuite) checkNamespaceRemoved() error {
+	if s.inventory == nil || s.inventory.Namespace == "" {
+		return skip("the controller does not create project namespaces")
+	}
+	if s.deactivated {
+		return skip("the resources of the project are kept for its retention period")
+	}
+	return s.poll(func(ctx context.Context) (bool, string, error) {
+		namespace, err := s.k8sClient.CoreV1().Namespaces().Get(ctx, s.inventory.Namespace, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, "", nil
+		}
+		if err != nil {
+			return false, "", err
+		}
+		return namespace.DeletionTimestamp != nil, "namespace " + s.inventory.Namespace + " is not being deleted", nil
+	})
+}
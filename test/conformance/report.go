@@ -0,0 +1,233 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package conformance checks a live orchestrator against the behavior of the tenant controller: a disposable
+// organization and project are created through Nexus, every resource provisioned for the project is validated, and
+// the project is deleted again. The results are written as a JUnit and an HTML report.
+package conformance
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Names of the report files written to the report directory
+const (
+	JUnitReportFile = "conformance-report.xml"
+	HTMLReportFile  = "conformance-report.html"
+)
+
+// Results of the checks
+const (
+	ResultPassed  = "passed"
+	ResultFailed  = "failed"
+	ResultSkipped = "skipped"
+)
+
+// Check is the result of a single conformance check
+type Check struct {
+	Name string
+	// one of the Result constants
+	Result string
+	// reason the check failed or was skipped
+	Message  string
+	Duration time.Duration
+}
+
+// Report collects the results of the conformance checks against an orchestrator
+type Report struct {
+	Name    string
+	Started time.Time
+	// settings of the run, such as the orchestrator domain, listed in the report
+	Properties map[string]string
+	Checks     []Check
+}
+
+// NewReport returns an empty report started now.
+func NewReport(name string) *Report {
+	return &Report{Name: name, Started: time.Now().UTC(), Properties: map[string]string{}}
+}
+
+// Add records the result of a check.
+func (r *Report) Add(check Check) {
+	r.Checks = append(r.Checks, check)
+}
+
+// Count returns the number of checks with the result.
+func (r *Report) Count(result string) int {
+	count := 0
+	for _, check := range r.Checks {
+		if check.Result == result {
+			count++
+		}
+	}
+	return count
+}
+
+// Duration returns the time taken by all the checks.
+func (r *Report) Duration() time.Duration {
+	var duration time.Duration
+	for _, check := range r.Checks {
+		duration += check.Duration
+	}
+	return duration
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+func junitSeconds(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
+
+// WriteJUnit writes the report in the JUnit XML format read by CI systems.
+func (r *Report) WriteJUnit(w io.Writer) error {
+	testSuite := junitTestSuite{
+		Name:      r.Name,
+		Tests:     len(r.Checks),
+		Failures:  r.Count(ResultFailed),
+		Skipped:   r.Count(ResultSkipped),
+		Time:      junitSeconds(r.Duration()),
+		Timestamp: r.Started.Format(time.RFC3339),
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.Properties)) {
+		testSuite.Properties = append(testSuite.Properties, junitProperty{Name: name, Value: r.Properties[name]})
+	}
+	for _, check := range r.Checks {
+		testCase := junitTestCase{Name: check.Name, ClassName: r.Name, Time: junitSeconds(check.Duration)}
+		switch check.Result {
+		case ResultFailed:
+			testCase.Failure = &junitMessage{Message: check.Message}
+		case ResultSkipped:
+			testCase.Skipped = &junitMessage{Message: check.Message}
+		}
+		testSuite.Cases = append(testSuite.Cases, testCase)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitTestSuites{Suites: []junitTestSuite{testSuite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.8em; text-align: left; vertical-align: top; }
+.passed { color: #00873d; }
+.failed { color: #c9190b; }
+.skipped { color: #6a6e73; }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+<p>Started {{.Started.Format "2006-01-02 15:04:05 MST"}}: {{.Passed}} passed, {{.Failed}} failed, {{.Skipped}} skipped
+in {{.Duration}}</p>
+{{- if .Properties}}
+<table>
+{{- range .Properties}}
+<tr><th>{{.Name}}</th><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+<h2>Checks</h2>
+<table>
+<tr><th>Check</th><th>Result</th><th>Duration</th><th>Message</th></tr>
+{{- range .Checks}}
+<tr><td>{{.Name}}</td><td class="{{.Result}}">{{.Result}}</td><td>{{.Duration}}</td><td>{{.Message}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// WriteHTML writes the report as a standalone HTML page for people to read.
+func (r *Report) WriteHTML(w io.Writer) error {
+	properties := []junitProperty{}
+	for _, name := range slices.Sorted(maps.Keys(r.Properties)) {
+		properties = append(properties, junitProperty{Name: name, Value: r.Properties[name]})
+	}
+	checks := []Check{}
+	for _, check := range r.Checks {
+		check.Duration = check.Duration.Round(time.Millisecond)
+		checks = append(checks, check)
+	}
+	return htmlReport.Execute(w, map[string]interface{}{
+		"Name":       r.Name,
+		"Started":    r.Started,
+		"Passed":     r.Count(ResultPassed),
+		"Failed":     r.Count(ResultFailed),
+		"Skipped":    r.Count(ResultSkipped),
+		"Duration":   r.Duration().Round(time.Millisecond),
+		"Properties": properties,
+		"Checks":     checks,
+	})
+}
+
+// Save writes the JUnit and HTML reports to the directory, which is created if needed.
+func (r *Report) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for name, write := range map[string]func(io.Writer) error{JUnitReportFile: r.WriteJUnit, HTMLReportFile: r.WriteHTML} {
+		file, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		err = write(file)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("unable to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
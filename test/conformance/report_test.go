@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package conformance
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Suite of conformance report tests. They run without an orchestrator.
+type ReportTestSuite struct {
+	suite.Suite
+}
+
+func TestReport(t *testing.T) {
+	suite.Run(t, &ReportTestSuite{})
+}
+
+func (s *ReportTestSuite) newReport() *Report {
+	report := NewReport("tenant-controller-conformance")
+	report.Properties["domain"] = "kind.internal"
+	report.Add(Check{Name: "CreateProject", Result: ResultPassed, Duration: 1500 * time.Millisecond})
+	report.Add(Check{Name: "StatusReady", Result: ResultFailed, Message: "project provisioning failed: <harbor> is down", Duration: time.Second})
+	report.Add(Check{Name: "Deployments", Result: ResultSkipped, Message: "the project has no extension deployments"})
+	return report
+}
+
+func (s *ReportTestSuite) TestJUnit() {
+	buffer := &bytes.Buffer{}
+	s.NoError(s.newReport().WriteJUnit(buffer))
+
+	suites := junitTestSuites{}
+	s.NoError(xml.Unmarshal(buffer.Bytes(), &suites))
+	s.Require().Len(suites.Suites, 1)
+	testSuite := suites.Suites[0]
+	s.Equal("tenant-controller-conformance", testSuite.Name)
+	s.Equal(3, testSuite.Tests)
+	s.Equal(1, testSuite.Failures)
+	s.Equal(1, testSuite.Skipped)
+	s.Equal("2.500", testSuite.Time)
+	s.Equal([]junitProperty{{Name: "domain", Value: "kind.internal"}}, testSuite.Properties)
+	s.Require().Len(testSuite.Cases, 3)
+	s.Equal("CreateProject", testSuite.Cases[0].Name)
+	s.Nil(testSuite.Cases[0].Failure)
+	s.Nil(testSuite.Cases[0].Skipped)
+	s.Equal("project provisioning failed: <harbor> is down", testSuite.Cases[1].Failure.Message)
+	s.Equal("the project has no extension deployments", testSuite.Cases[2].Skipped.Message)
+}
+
+func (s *ReportTestSuite) TestHTML() {
+	buffer := &bytes.Buffer{}
+	s.NoError(s.newReport().WriteHTML(buffer))
+	html := buffer.String()
+	s.Contains(html, "1 passed, 1 failed, 1 skipped")
+	s.Contains(html, `<td class="failed">failed</td>`)
+	// Messages are escaped
+	s.Contains(html, "&lt;harbor&gt; is down")
+	s.Contains(html, "<tr><th>domain</th><td>kind.internal</td></tr>")
+}
+
+func (s *ReportTestSuite) TestSave() {
+	dir := filepath.Join(s.T().TempDir(), "reports")
+	s.NoError(s.newReport().Save(dir))
+	for _, name := range []string{JUnitReportFile, HTMLReportFile} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		s.NoError(err)
+		s.NotZero(info.Size())
+	}
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Test utility package
+package fake
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+
+	adm "github.com/open-edge-platform/app-orch-deployment/app-deployment-manager/api/nbi/v2/deployment/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// ADM is a fake app deployment manager gRPC service. It stores deployments without deploying anything.
+type ADM struct {
+	adm.UnimplementedDeploymentServiceServer
+
+	listener net.Listener
+	server   *grpc.Server
+
+	mu          sync.Mutex
+	nextID      int
+	deployments map[string]*adm.Deployment
+}
+
+// NewADM starts a fake app deployment manager listening on a local port.
+func NewADM() (*ADM, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	a := &ADM{
+		listener:    listener,
+		server:      grpc.NewServer(),
+		deployments: map[string]*adm.Deployment{},
+	}
+	adm.RegisterDeploymentServiceServer(a.server, a)
+	go func() { _ = a.server.Serve(listener) }()
+	return a, nil
+}
+
+// Address returns the gRPC address of the fake app deployment manager.
+func (a *ADM) Address() string {
+	return a.listener.Addr().String()
+}
+
+func (a *ADM) Close() {
+	a.server.Stop()
+}
+
+// Deployments returns copies of the deployments, sorted by display name.
+func (a *ADM) Deployments() []*adm.Deployment {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.sortedDeployments()
+}
+
+func (a *ADM) sortedDeployments() []*adm.Deployment {
+	deployments := []*adm.Deployment{}
+	for _, deployment := range a.deployments {
+		deployments = append(deployments, proto.Clone(deployment).(*adm.Deployment))
+	}
+	slices.SortFunc(deployments, func(a, b *adm.Deployment) int { return strings.Compare(a.DisplayName, b.DisplayName) })
+	return deployments
+}
+
+// ListDeployments returns a page of the deployments, sorted by display name. Label and filter criteria are ignored.
+func (a *ADM) ListDeployments(_ context.Context, in *adm.ListDeploymentsRequest) (*adm.ListDeploymentsResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	deployments := a.sortedDeployments()
+	total := int32(len(deployments)) //nolint:gosec // Small test data
+	if in.Offset > 0 {
+		deployments = deployments[min(int(in.Offset), len(deployments)):]
+	}
+	if in.PageSize > 0 {
+		deployments = deployments[:min(int(in.PageSize), len(deployments))]
+	}
+	return &adm.ListDeploymentsResponse{Deployments: deployments, TotalElements: total}, nil
+}
+
+// CreateDeployment stores the deployment. Display names must be unique.
+func (a *ADM) CreateDeployment(_ context.Context, in *adm.CreateDeploymentRequest) (*adm.CreateDeploymentResponse, error) {
+	if in.Deployment == nil || in.Deployment.AppName == "" || in.Deployment.AppVersion == "" {
+		return nil, status.Error(codes.InvalidArgument, "app name and version are required")
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, deployment := range a.deployments {
+		if deployment.DisplayName == in.Deployment.DisplayName {
+			return nil, status.Errorf(codes.AlreadyExists, "deployment %s already exists", in.Deployment.DisplayName)
+		}
+	}
+	a.nextID++
+	deployment := proto.Clone(in.Deployment).(*adm.Deployment)
+	deployment.DeployId = fmt.Sprintf("deployment-%d", a.nextID)
+	a.deployments[deployment.DeployId] = deployment
+	return &adm.CreateDeploymentResponse{DeploymentId: deployment.DeployId}, nil
+}
+
+func (a *ADM) DeleteDeployment(_ context.Context, in *adm.DeleteDeploymentRequest) (*emptypb.Empty, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.deployments[in.DeplId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "deployment %s not found", in.DeplId)
+	}
+	delete(a.deployments, in.DeplId)
+	return &emptypb.Empty{}, nil
+}
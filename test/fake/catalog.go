@@ -0,0 +1,178 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Test utility package
+package fake
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+
+	catalogv3 "github.com/open-edge-platform/app-orch-catalog/pkg/api/catalog/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// CatalogUpload is a file uploaded to the fake catalog.
+type CatalogUpload struct {
+	SessionID string
+	FileName  string
+	Artifact  []byte
+}
+
+// Catalog is a fake application catalog gRPC service. It stores registries and records uploaded files;
+// uploads are not parsed, so the catalog has no deployment packages, applications or artifacts.
+type Catalog struct {
+	catalogv3.UnimplementedCatalogServiceServer
+
+	listener net.Listener
+	server   *grpc.Server
+
+	mu          sync.Mutex
+	nextSession int
+	registries  map[string]*catalogv3.Registry
+	uploads     []CatalogUpload
+}
+
+// NewCatalog starts a fake catalog listening on a local port.
+func NewCatalog() (*Catalog, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	c := &Catalog{
+		listener:   listener,
+		server:     grpc.NewServer(),
+		registries: map[string]*catalogv3.Registry{},
+	}
+	catalogv3.RegisterCatalogServiceServer(c.server, c)
+	go func() { _ = c.server.Serve(listener) }()
+	return c, nil
+}
+
+// Address returns the gRPC address of the fake catalog.
+func (c *Catalog) Address() string {
+	return c.listener.Addr().String()
+}
+
+func (c *Catalog) Close() {
+	c.server.Stop()
+}
+
+// Registries returns copies of the registries, sorted by name.
+func (c *Catalog) Registries() []*catalogv3.Registry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sortedRegistries()
+}
+
+// Uploads returns the files uploaded so far.
+func (c *Catalog) Uploads() []CatalogUpload {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.uploads)
+}
+
+func (c *Catalog) sortedRegistries() []*catalogv3.Registry {
+	registries := []*catalogv3.Registry{}
+	for _, registry := range c.registries {
+		registries = append(registries, proto.Clone(registry).(*catalogv3.Registry))
+	}
+	slices.SortFunc(registries, func(a, b *catalogv3.Registry) int { return strings.Compare(a.Name, b.Name) })
+	return registries
+}
+
+func (c *Catalog) GetRegistry(_ context.Context, in *catalogv3.GetRegistryRequest) (*catalogv3.GetRegistryResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	registry, ok := c.registries[in.RegistryName]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "registry %s not found", in.RegistryName)
+	}
+	return &catalogv3.GetRegistryResponse{Registry: proto.Clone(registry).(*catalogv3.Registry)}, nil
+}
+
+func (c *Catalog) CreateRegistry(_ context.Context, in *catalogv3.CreateRegistryRequest) (*catalogv3.CreateRegistryResponse, error) {
+	if in.Registry == nil || in.Registry.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "registry name is required")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.registries[in.Registry.Name]; ok {
+		return nil, status.Errorf(codes.AlreadyExists, "registry %s already exists", in.Registry.Name)
+	}
+	c.registries[in.Registry.Name] = proto.Clone(in.Registry).(*catalogv3.Registry)
+	return &catalogv3.CreateRegistryResponse{Registry: in.Registry}, nil
+}
+
+func (c *Catalog) UpdateRegistry(_ context.Context, in *catalogv3.UpdateRegistryRequest) (*emptypb.Empty, error) {
+	if in.Registry == nil || in.Registry.Name != in.RegistryName {
+		return nil, status.Error(codes.InvalidArgument, "registry name does not match")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.registries[in.RegistryName]; !ok {
+		return nil, status.Errorf(codes.NotFound, "registry %s not found", in.RegistryName)
+	}
+	c.registries[in.RegistryName] = proto.Clone(in.Registry).(*catalogv3.Registry)
+	return &emptypb.Empty{}, nil
+}
+
+func (c *Catalog) ListRegistries(_ context.Context, _ *catalogv3.ListRegistriesRequest) (*catalogv3.ListRegistriesResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	registries := c.sortedRegistries()
+	return &catalogv3.ListRegistriesResponse{Registries: registries, TotalElements: int32(len(registries))}, nil //nolint:gosec // Small test data
+}
+
+func (c *Catalog) DeleteRegistry(_ context.Context, in *catalogv3.DeleteRegistryRequest) (*emptypb.Empty, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.registries[in.RegistryName]; !ok {
+		return nil, status.Errorf(codes.NotFound, "registry %s not found", in.RegistryName)
+	}
+	delete(c.registries, in.RegistryName)
+	return &emptypb.Empty{}, nil
+}
+
+// UploadCatalogEntities records the uploaded file. A session lasts until its last upload.
+func (c *Catalog) UploadCatalogEntities(_ context.Context, in *catalogv3.UploadCatalogEntitiesRequest) (*catalogv3.UploadCatalogEntitiesResponse, error) {
+	if in.Upload == nil || in.Upload.FileName == "" {
+		return nil, status.Error(codes.InvalidArgument, "file name is required")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sessionID := in.SessionId
+	if sessionID == "" {
+		c.nextSession++
+		sessionID = fmt.Sprintf("session-%d", c.nextSession)
+	}
+	c.uploads = append(c.uploads, CatalogUpload{
+		SessionID: sessionID,
+		FileName:  in.Upload.FileName,
+		Artifact:  slices.Clone(in.Upload.Artifact),
+	})
+	if in.LastUpload {
+		return &catalogv3.UploadCatalogEntitiesResponse{}, nil
+	}
+	return &catalogv3.UploadCatalogEntitiesResponse{SessionId: sessionID}, nil
+}
+
+func (c *Catalog) ListDeploymentPackages(_ context.Context, _ *catalogv3.ListDeploymentPackagesRequest) (*catalogv3.ListDeploymentPackagesResponse, error) {
+	return &catalogv3.ListDeploymentPackagesResponse{}, nil
+}
+
+func (c *Catalog) ListApplications(_ context.Context, _ *catalogv3.ListApplicationsRequest) (*catalogv3.ListApplicationsResponse, error) {
+	return &catalogv3.ListApplicationsResponse{}, nil
+}
+
+func (c *Catalog) ListArtifacts(_ context.Context, _ *catalogv3.ListArtifactsRequest) (*catalogv3.ListArtifactsResponse, error) {
+	return &catalogv3.ListArtifactsResponse{}, nil
+}
@@ -0,0 +1,207 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package fake provides in-process fakes of the services the tenant controller provisions: Harbor, the
+// application catalog, the app deployment manager and the release service OCI registry. The fakes keep
+// their state in memory, so create and delete flows can be run and checked without a cluster.
+//
+// The fakes implement only the parts of the APIs used by the controller, and do not check tenancy: every
+// project shares a single catalog and deployment manager, as they do when no M2M token is in use.
+//
+//nolint:revive // Test utility package
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	"github.com/open-edge-platform/app-orch-tenant-controller/test/manifestgen"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// Names of the secrets holding the Harbor and Keycloak admin credentials
+	HarborNamespace       = "orch-harbor"
+	HarborAdminCredential = "harbor-admin-credential"
+	KeycloakNamespace     = "orch-platform"
+	KeycloakSecret        = "platform-keycloak"
+
+	// Admin credentials accepted by the fake Harbor
+	HarborAdminUsername = "admin"
+	HarborAdminPassword = "harbor-admin-password"
+
+	// Location of the extensions manifest in the fake registry
+	ManifestRepository = "edge-node/en/manifest"
+	ManifestTag        = "latest"
+)
+
+// Environment is a set of running fakes, wired up so that the controller uses them.
+type Environment struct {
+	Harbor   *Harbor
+	Catalog  *Catalog
+	ADM      *ADM
+	Registry *Registry
+	Secrets  *Secrets
+
+	k8sFactory func(string) (southbound.K8s, error)
+}
+
+// Start starts all of the fakes and installs the fake Kubernetes secrets in southbound.K8sFactory. Close
+// stops the fakes and restores the factory.
+func Start() (*Environment, error) {
+	e := &Environment{
+		Harbor:     NewHarbor(HarborAdminUsername, HarborAdminPassword),
+		Registry:   NewRegistry(),
+		Secrets:    NewSecrets(),
+		k8sFactory: southbound.K8sFactory,
+	}
+	var err error
+	if e.Catalog, err = NewCatalog(); err != nil {
+		e.Close()
+		return nil, err
+	}
+	if e.ADM, err = NewADM(); err != nil {
+		e.Close()
+		return nil, err
+	}
+
+	e.Secrets.Set(HarborNamespace, HarborAdminCredential, map[string][]byte{
+		"credential": []byte(HarborAdminUsername + ":" + HarborAdminPassword),
+	})
+	e.Secrets.Set(KeycloakNamespace, KeycloakSecret, map[string][]byte{
+		"admin-password": []byte("keycloak-admin-password"),
+	})
+	southbound.K8sFactory = e.Secrets.K8s
+	return e, nil
+}
+
+// Configuration returns a controller configuration that points at the fakes.
+func (e *Environment) Configuration() config.Configuration {
+	return config.Configuration{
+		CatalogServer:        e.Catalog.Address(),
+		HarborServer:         e.Harbor.URL(),
+		HarborServerExternal: e.Harbor.URL(),
+		HarborRobotPolicy:    config.RobotPolicyRecreate,
+		HarborLabels:         config.DefaultHarborLabels,
+		KeycloakServer:       "http://keycloak.fake",
+		Secrets: config.K8sSecretsRef{
+			HarborAdmin:   config.SecretRef{Namespace: HarborNamespace, Name: HarborAdminCredential, Key: config.DefaultHarborAdminCredentialKey},
+			KeycloakAdmin: config.SecretRef{Namespace: KeycloakNamespace, Name: KeycloakSecret, Key: config.DefaultKeycloakSecretKey},
+		},
+		AdmServer:                  e.ADM.Address(),
+		ReleaseServiceBase:         e.Registry.Host(),
+		ReleaseServiceRootURL:      "oci://" + e.Registry.Host(),
+		ReleaseServiceProxyRootURL: "oci://" + e.Registry.Host(),
+		ManifestPath:               "/" + ManifestRepository,
+		ManifestTag:                ManifestTag,
+	}
+}
+
+// PushManifest publishes an extensions manifest where the configuration returned by Configuration expects it.
+func (e *Environment) PushManifest(manifestYAML []byte) error {
+	return e.Registry.Push(ManifestRepository, ManifestTag, map[string][]byte{"manifest.yaml": manifestYAML})
+}
+
+// SampleManifest is an extensions manifest with a single deployment, published along with its deployment package
+// by PushSampleManifest.
+const SampleManifest = `
+metadata:
+  schemaVersion: 0.2.1
+  release: 26.0.0-sample
+lpke:
+  deploymentPackages:
+    - dpkg: edge-node/dp/base-extensions
+      version: 0.2.0
+  deploymentList:
+    - dpName: base-extensions
+      displayName: base-extensions-baseline
+      dpProfileName: baseline
+      dpVersion: 0.2.0
+`
+
+// PushSampleManifest publishes SampleManifest and the deployment package it refers to.
+func (e *Environment) PushSampleManifest() error {
+	if err := e.PushManifest([]byte(SampleManifest)); err != nil {
+		return err
+	}
+	return e.Registry.Push("edge-node/dp/base-extensions", "0.2.0", map[string][]byte{
+		"base-extensions.yaml": []byte("name: base-extensions\n"),
+		"baseline.yaml":        []byte("name: baseline\n"),
+	})
+}
+
+// PushGeneratedManifest generates a manifest of the given shape, with its deployment packages in the edge-node/dp
+// repository, and publishes it and the packages it lists as present. It returns the generated fixture.
+func (e *Environment) PushGeneratedManifest(options manifestgen.Options) (*manifestgen.Fixture, error) {
+	options.Repository = "edge-node/dp"
+	fixture, err := manifestgen.Generate(options)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.PushManifest(fixture.Manifest); err != nil {
+		return nil, err
+	}
+	for _, p := range fixture.Packages {
+		if err := e.Registry.Push(p.Dpkg, p.Version, map[string][]byte{p.FileName: p.Content}); err != nil {
+			return nil, err
+		}
+	}
+	return fixture, nil
+}
+
+// Close stops the fakes and restores southbound.K8sFactory.
+func (e *Environment) Close() {
+	southbound.K8sFactory = e.k8sFactory
+	if e.Harbor != nil {
+		e.Harbor.Close()
+	}
+	if e.Catalog != nil {
+		e.Catalog.Close()
+	}
+	if e.ADM != nil {
+		e.ADM.Close()
+	}
+	if e.Registry != nil {
+		e.Registry.Close()
+	}
+}
+
+// Secrets is a fake of the Kubernetes secrets read by the controller.
+type Secrets struct {
+	mu      sync.Mutex
+	secrets map[string]map[string][]byte
+}
+
+func NewSecrets() *Secrets {
+	return &Secrets{secrets: map[string]map[string][]byte{}}
+}
+
+// Set creates or replaces a secret.
+func (s *Secrets) Set(namespace string, name string, data map[string][]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.secrets[namespace+"/"+name] = data
+}
+
+// K8s returns a client for the secrets of a namespace. It has the signature of southbound.K8sFactory.
+func (s *Secrets) K8s(namespace string) (southbound.K8s, error) {
+	return &namespaceSecrets{secrets: s, namespace: namespace}, nil
+}
+
+type namespaceSecrets struct {
+	secrets   *Secrets
+	namespace string
+}
+
+func (n *namespaceSecrets) ReadSecret(_ context.Context, name string) (map[string][]byte, error) {
+	n.secrets.mu.Lock()
+	defer n.secrets.mu.Unlock()
+	data, ok := n.secrets.secrets[n.namespace+"/"+name]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+	}
+	return data, nil
+}
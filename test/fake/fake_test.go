@@ -0,0 +1,289 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package fake
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/config"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/manager"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/plugins"
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+	"github.com/open-edge-platform/app-orch-tenant-controller/test/manifestgen"
+	"github.com/stretchr/testify/suite"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+const testManifest = `
+metadata:
+  schemaVersion: 0.2.1
+  release: 26.0.0-test
+lpke:
+  deploymentPackages:
+    - dpkg: edge-node/dp/base-extensions
+      version: 0.2.0
+  deploymentList:
+    - dpName: base-extensions
+      displayName: base-extensions-baseline
+      dpProfileName: baseline
+      dpVersion: 0.2.0
+      allAppTargetClusters:
+        - key: color
+          val: blue
+`
+
+// Suite of tests running the provisioning plugins against the fakes
+type FakeTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+	env    *Environment
+}
+
+func (s *FakeTestSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 1*time.Minute)
+	var err error
+	s.env, err = Start()
+	s.Require().NoError(err)
+
+	s.NoError(s.env.PushManifest([]byte(testManifest)))
+	s.NoError(s.env.Registry.Push("edge-node/dp/base-extensions", "0.2.0", map[string][]byte{
+		"base-extensions.yaml": []byte("name: base-extensions\n"),
+		"baseline.yaml":        []byte("name: baseline\n"),
+	}))
+
+	plugins.RemoveAllPlugins()
+	s.NoError(manager.RegisterPlugins(s.ctx, s.env.Configuration()))
+	s.NoError(plugins.Initialize(s.ctx))
+}
+
+func (s *FakeTestSuite) TearDownTest() {
+	plugins.RemoveAllPlugins()
+	s.env.Close()
+	s.cancel()
+}
+
+func TestFake(t *testing.T) {
+	suite.Run(t, &FakeTestSuite{})
+}
+
+func (s *FakeTestSuite) TestCreateDeleteProject() {
+	s.True(s.env.Harbor.Configured())
+
+	event := plugins.Event{
+		EventType:    "create",
+		Organization: "Org",
+		Name:         "Proj",
+		UUID:         "uuid-1",
+	}
+	_, err := plugins.Dispatch(s.ctx, event, nil)
+	s.NoError(err)
+
+	project, ok := s.env.Harbor.Project("catalog-apps-org-proj")
+	s.True(ok)
+	s.Equal(map[string]int{"uuid-1_Edge-Operator-Group": 3, "uuid-1_Edge-Manager-Group": 4}, project.Members)
+	robots := s.env.Harbor.Robots("catalog-apps-org-proj")
+	s.Len(robots, 2)
+	s.Equal("robot$catalog-apps-org-proj+catalog-apps-read-write", robots[0].Name)
+	s.Contains(robots[0].Access, "repository:push")
+	s.Equal("robot$catalog-apps-org-proj+catalog-apps-read-only", robots[1].Name)
+	s.Contains(robots[1].Access, "repository:pull")
+	s.NotContains(robots[1].Access, "repository:push")
+	labels := s.env.Harbor.Labels(project.ID)
+	s.Len(labels, 2)
+	s.Equal("extension", labels[0].Name)
+	s.Equal("customer-app", labels[1].Name)
+
+	registries := s.env.Catalog.Registries()
+	s.Len(registries, 4)
+	s.Equal("harbor-docker-oci", registries[0].Name)
+	s.Equal(robots[1].Name, registries[0].Username)
+	s.Equal(robots[1].Secret, registries[0].AuthToken)
+	s.Equal("harbor-helm-oci", registries[1].Name)
+	s.Equal(robots[0].Name, registries[1].Username)
+
+	uploads := s.env.Catalog.Uploads()
+	s.Len(uploads, 2)
+	s.Equal("base-extensions.yaml", path.Base(uploads[0].FileName))
+	s.Equal("baseline.yaml", path.Base(uploads[1].FileName))
+
+	deployments := s.env.ADM.Deployments()
+	s.Len(deployments, 1)
+	s.Equal("base-extensions", deployments[0].AppName)
+	s.Equal(map[string]string{"color": "blue"}, deployments[0].AllAppTargetClusters.Labels)
+
+	// Provisioning again recreates the robot account and updates the registries
+	_, err = plugins.Dispatch(s.ctx, event, nil)
+	s.NoError(err)
+	newRobots := s.env.Harbor.Robots("catalog-apps-org-proj")
+	s.Len(newRobots, 2)
+	s.NotEqual(robots[1].Secret, newRobots[1].Secret)
+	s.Equal(newRobots[1].Secret, s.env.Catalog.Registries()[0].AuthToken)
+	s.Equal(newRobots[0].Secret, s.env.Catalog.Registries()[1].AuthToken)
+	s.Len(s.env.ADM.Deployments(), 1)
+
+	s.NoError(s.env.Harbor.AddRepository("catalog-apps-org-proj", "charts/nginx"))
+	event.EventType = "delete"
+	_, err = plugins.Dispatch(s.ctx, event, nil)
+	s.NoError(err)
+	_, ok = s.env.Harbor.Project("catalog-apps-org-proj")
+	s.False(ok)
+	s.Empty(s.env.Harbor.Labels(project.ID))
+	s.Empty(s.env.Catalog.Registries())
+}
+
+func (s *FakeTestSuite) TestHarborSignatureEnforcement() {
+	event := plugins.Event{
+		EventType:    "create",
+		Organization: "Org",
+		Name:         "Premium",
+		UUID:         "uuid-1",
+		Profile:      &config.ProvisioningProfile{Name: "premium", HarborSigning: []string{config.HarborSigningCosign}},
+	}
+	_, err := plugins.Dispatch(s.ctx, event, nil)
+	s.NoError(err)
+	project, ok := s.env.Harbor.Project("catalog-apps-org-premium")
+	s.True(ok)
+	s.Equal(map[string]string{southbound.HarborMetadataContentTrustCosign: "true"}, project.Metadata)
+
+	event.Name = "Basic"
+	event.UUID = "uuid-2"
+	event.Profile = &config.ProvisioningProfile{Name: "basic"}
+	_, err = plugins.Dispatch(s.ctx, event, nil)
+	s.NoError(err)
+	project, _ = s.env.Harbor.Project("catalog-apps-org-basic")
+	s.Equal("false", project.Metadata[southbound.HarborMetadataContentTrustCosign])
+}
+
+func (s *FakeTestSuite) TestHarborStorageAdmission() {
+	configuration := s.env.Configuration()
+	configuration.MaxHarborStorage = 1000
+	plugins.RemoveAllPlugins()
+	s.NoError(manager.RegisterPlugins(s.ctx, configuration))
+
+	event := plugins.Event{EventType: "create", Organization: "Org", Name: "Proj", UUID: "uuid-1"}
+	_, err := plugins.Dispatch(s.ctx, event, nil)
+	s.NoError(err)
+
+	// New projects are refused before anything is created for them
+	s.env.Harbor.SetStorageUsed(1000)
+	_, err = plugins.Dispatch(s.ctx, plugins.Event{EventType: "create", Organization: "Org", Name: "Other", UUID: "uuid-2"}, nil)
+	s.ErrorIs(err, plugins.ErrCapacityExceeded)
+	_, ok := s.env.Harbor.Project("catalog-apps-org-other")
+	s.False(ok)
+	s.Len(s.env.Catalog.Registries(), 4)
+
+	// Projects that exist are still provisioned again
+	_, err = plugins.Dispatch(s.ctx, event, nil)
+	s.NoError(err)
+}
+
+func (s *FakeTestSuite) TestGeneratedManifest() {
+	fixture, err := s.env.PushGeneratedManifest(manifestgen.Options{Packages: 40, Deployments: 120, AbsentEvery: 4})
+	s.NoError(err)
+	s.Len(fixture.Packages, 40)
+
+	_, err = plugins.Dispatch(s.ctx, plugins.Event{EventType: "create", Organization: "org", Name: "proj", UUID: "uuid-3"}, nil)
+	s.NoError(err)
+	// Every 4th package and deployment is absent
+	s.Len(s.env.Catalog.Uploads(), 30)
+	s.Len(s.env.ADM.Deployments(), 90)
+}
+
+func (s *FakeTestSuite) TestPrivateReleaseService() {
+	registry := NewTLSRegistry()
+	defer registry.Close()
+	s.NoError(registry.Push(ManifestRepository, ManifestTag, map[string][]byte{"manifest.yaml": []byte(SampleManifest)}))
+	registry.RequireCredential("puller", "pull-secret")
+	s.env.Secrets.Set("orch-app", "rs-credential", map[string][]byte{"credential": []byte("puller:pull-secret\n")})
+
+	configuration := s.env.Configuration()
+	configuration.ReleaseServiceBase = registry.Host()
+	configuration.ReleaseServiceAccess = config.ReleaseServiceAccess{
+		Credential: config.SecretRef{Namespace: "orch-app", Name: "rs-credential", Key: config.DefaultReleaseServiceCredentialKey},
+		TLS:        true,
+		CACerts:    registry.CACerts(),
+	}
+	manifest, err := plugins.LoadManifest(s.ctx, configuration)
+	s.NoError(err)
+	s.Equal("26.0.0-sample", manifest.Metadata.Release)
+
+	// Anonymous pulls are rejected
+	anonymous := configuration
+	anonymous.ReleaseServiceAccess.Credential = config.SecretRef{}
+	_, err = plugins.LoadManifest(s.ctx, anonymous)
+	s.ErrorIs(err, auth.ErrBasicCredentialNotFound)
+	s.False(southbound.IsRetryable(err))
+
+	// The certificate of the registry is not trusted without its CA
+	untrusted := configuration
+	untrusted.ReleaseServiceAccess.CACerts = ""
+	_, err = plugins.LoadManifest(s.ctx, untrusted)
+	s.ErrorContains(err, "certificate")
+
+	s.env.Secrets.Set("orch-app", "rs-credential", map[string][]byte{"credential": []byte("pull-secret")})
+	_, err = plugins.LoadManifest(s.ctx, configuration)
+	s.ErrorContains(err, "not in username:password form")
+	s.False(southbound.IsRetryable(err))
+}
+
+func (s *FakeTestSuite) TestOrasResolver() {
+	// The manifest is pulled through the resolver, from the registry of the environment
+	resolver := func(reference string) (oras.ReadOnlyTarget, error) {
+		repo, err := remote.NewRepository(s.env.Registry.Host() + strings.TrimPrefix(reference, "private.registry"))
+		if err != nil {
+			return nil, err
+		}
+		repo.PlainHTTP = true
+		return repo, nil
+	}
+	client, err := southbound.NewOrasWithOptions("private.registry", southbound.OrasOptions{Resolver: resolver})
+	s.NoError(err)
+	defer client.Close()
+	s.NoError(client.Load(s.ctx, "/"+ManifestRepository, ManifestTag))
+	manifest, err := os.ReadFile(path.Join(client.Dest(), "manifest.yaml"))
+	s.NoError(err)
+	s.Equal(testManifest, string(manifest))
+}
+
+func (s *FakeTestSuite) TestMissingDeploymentPackage() {
+	s.NoError(s.env.PushManifest([]byte(`
+metadata:
+  schemaVersion: 0.2.1
+  release: 26.0.0-test
+lpke:
+  deploymentPackages:
+    - dpkg: edge-node/dp/missing
+      version: 1.0.0
+`)))
+	_, err := plugins.Dispatch(s.ctx, plugins.Event{EventType: "create", Organization: "org", Name: "proj", UUID: "uuid-2"}, nil)
+	s.ErrorContains(err, "not found")
+	s.False(southbound.IsRetryable(err))
+}
+
+func (s *FakeTestSuite) TestUnsupportedHarbor() {
+	s.env.Harbor.SetVersion("v2.1.3")
+	plugins.RemoveAllPlugins()
+	s.NoError(manager.RegisterPlugins(s.ctx, s.env.Configuration()))
+	err := plugins.Initialize(s.ctx)
+	s.ErrorContains(err, "harbor v2.1.3 is not supported")
+	s.False(southbound.IsRetryable(err))
+}
+
+func (s *FakeTestSuite) TestDisabledPlugins() {
+	configuration := s.env.Configuration()
+	configuration.DisabledPlugins = []string{config.PluginExtensions}
+	plugins.RemoveAllPlugins()
+	s.NoError(manager.RegisterPlugins(s.ctx, configuration))
+	s.NotContains(plugins.Names(), "Extensions Provisioner")
+	s.Contains(plugins.Names(), "Harbor Provisioner")
+	s.NoError(plugins.Initialize(s.ctx))
+}
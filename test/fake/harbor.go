@@ -0,0 +1,543 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Test utility package
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/southbound"
+)
+
+// HarborProject is the state of a project in the fake Harbor.
+type HarborProject struct {
+	ID           int
+	Name         string
+	StorageLimit int64
+	// project metadata, such as the signature enforcement
+	Metadata map[string]string
+	// role IDs of the member groups, keyed by group name
+	Members      map[string]int
+	Repositories []string
+}
+
+// HarborRobot is the state of a robot account in the fake Harbor.
+type HarborRobot struct {
+	ID        int
+	Name      string
+	ProjectID int
+	Secret    string
+	// permissions of the robot as resource:action
+	Access []string
+}
+
+// Harbor is a fake Harbor core REST API.
+type Harbor struct {
+	server   *httptest.Server
+	username string
+	password string
+
+	mu         sync.Mutex
+	nextID     int
+	version    string
+	configured bool
+	projects   map[string]*HarborProject
+	robots     map[int]*HarborRobot
+	labels     map[int]*southbound.HarborLabel
+	// bytes reported as used by all projects
+	storageUsed int64
+}
+
+// NewHarbor starts a fake Harbor that accepts the given admin credentials.
+func NewHarbor(username string, password string) *Harbor {
+	h := &Harbor{
+		username: username,
+		password: password,
+		version:  "v2.10.0-fake",
+		projects: map[string]*HarborProject{},
+		robots:   map[int]*HarborRobot{},
+		labels:   map[int]*southbound.HarborLabel{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+southbound.HarborPingURL, h.ping)
+	mux.HandleFunc("GET "+southbound.HarborSystemInfoURL, h.admin(h.systemInfo))
+	mux.HandleFunc("GET "+southbound.HarborStatisticsURL, h.admin(h.statistics))
+	mux.HandleFunc("PUT "+southbound.HarborConfigurationURL, h.admin(h.putConfigurations))
+	mux.HandleFunc("POST "+southbound.HarborProjectsURL, h.admin(h.createProject))
+	mux.HandleFunc("GET "+southbound.HarborProjectsURL+"/{name}", h.admin(h.getProject))
+	mux.HandleFunc("PUT "+southbound.HarborProjectsURL+"/{name}", h.admin(h.updateProject))
+	mux.HandleFunc("DELETE "+southbound.HarborProjectsURL+"/{name}", h.admin(h.deleteProject))
+	mux.HandleFunc("POST "+southbound.HarborProjectsURL+"/{name}/members", h.admin(h.addMember))
+	mux.HandleFunc("GET "+southbound.HarborProjectsURL+"/{name}/repositories", h.admin(h.listRepositories))
+	mux.HandleFunc("DELETE "+southbound.HarborProjectsURL+"/{name}/repositories/{repository}", h.admin(h.deleteRepository))
+	mux.HandleFunc("GET "+southbound.HarborQuotasURL, h.admin(h.listQuotas))
+	mux.HandleFunc("PUT "+southbound.HarborQuotasURL+"/{id}", h.admin(h.updateQuota))
+	mux.HandleFunc("POST "+southbound.HarborRobotsURL, h.admin(h.createRobot))
+	mux.HandleFunc("GET "+southbound.HarborRobotsURL, h.admin(h.listRobots))
+	mux.HandleFunc("PATCH "+southbound.HarborRobotsURL+"/{id}", h.admin(h.refreshRobotSecret))
+	mux.HandleFunc("DELETE "+southbound.HarborRobotsURL+"/{id}", h.admin(h.deleteRobot))
+	mux.HandleFunc("GET "+southbound.HarborLabelsURL, h.admin(h.listLabels))
+	mux.HandleFunc("POST "+southbound.HarborLabelsURL, h.admin(h.createLabel))
+	mux.HandleFunc("PUT "+southbound.HarborLabelsURL+"/{id}", h.admin(h.updateLabel))
+	mux.HandleFunc("DELETE "+southbound.HarborLabelsURL+"/{id}", h.admin(h.deleteLabel))
+	h.server = httptest.NewServer(mux)
+	return h
+}
+
+// URL returns the base URL of the fake Harbor.
+func (h *Harbor) URL() string {
+	return h.server.URL
+}
+
+func (h *Harbor) Close() {
+	h.server.Close()
+}
+
+// SetVersion sets the version reported by the fake Harbor.
+func (h *Harbor) SetVersion(version string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.version = version
+}
+
+// SetStorageUsed sets the bytes reported as used by all projects.
+func (h *Harbor) SetStorageUsed(bytes int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.storageUsed = bytes
+}
+
+// Configured returns true once the OIDC configuration has been applied.
+func (h *Harbor) Configured() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.configured
+}
+
+// Project returns a copy of the project with the given name.
+func (h *Harbor) Project(name string) (HarborProject, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	project, ok := h.projects[name]
+	if !ok {
+		return HarborProject{}, false
+	}
+	p := *project
+	p.Metadata = maps.Clone(project.Metadata)
+	p.Members = maps.Clone(project.Members)
+	p.Repositories = slices.Clone(project.Repositories)
+	return p, true
+}
+
+// Robots returns the robot accounts of the project with the given name.
+func (h *Harbor) Robots(projectName string) []HarborRobot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	robots := []HarborRobot{}
+	project, ok := h.projects[projectName]
+	if !ok {
+		return robots
+	}
+	for _, robot := range h.robots {
+		if robot.ProjectID == project.ID {
+			robots = append(robots, *robot)
+		}
+	}
+	slices.SortFunc(robots, func(a, b HarborRobot) int { return a.ID - b.ID })
+	return robots
+}
+
+// Labels returns the labels of the Harbor project with the given ID, which are kept if the project is deleted.
+func (h *Harbor) Labels(projectID int) []southbound.HarborLabel {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	labels := []southbound.HarborLabel{}
+	for _, label := range h.labels {
+		if label.ProjectID == projectID {
+			labels = append(labels, *label)
+		}
+	}
+	slices.SortFunc(labels, func(a, b southbound.HarborLabel) int { return a.ID - b.ID })
+	return labels
+}
+
+// AddRepository adds a repository to a project, as pushing an image or chart would.
+func (h *Harbor) AddRepository(projectName string, repository string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	project, ok := h.projects[projectName]
+	if !ok {
+		return fmt.Errorf("project %s not found", projectName)
+	}
+	project.Repositories = append(project.Repositories, repository)
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error in the format used by Harbor.
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, map[string]interface{}{
+		"errors": []map[string]string{{
+			"code":    strings.ReplaceAll(strings.ToUpper(http.StatusText(status)), " ", "_"),
+			"message": fmt.Sprintf(format, args...),
+		}},
+	})
+}
+
+// admin wraps handlers of the endpoints that require the admin credentials.
+func (h *Harbor) admin(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != h.username || password != h.password {
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		handler(w, r)
+	}
+}
+
+func (h *Harbor) newID() int {
+	h.nextID++
+	return h.nextID
+}
+
+func (h *Harbor) ping(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("Pong"))
+}
+
+func (h *Harbor) systemInfo(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, southbound.HarborSystemInfo{HarborVersion: h.version})
+}
+
+func (h *Harbor) statistics(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, southbound.HarborStatistics{TotalProjectCount: int64(len(h.projects)), TotalStorageConsumption: h.storageUsed})
+}
+
+func (h *Harbor) putConfigurations(w http.ResponseWriter, r *http.Request) {
+	attrs := southbound.ConfigurationAttributes{}
+	if err := json.NewDecoder(r.Body).Decode(&attrs); err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	h.configured = true
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Harbor) createProject(w http.ResponseWriter, r *http.Request) {
+	attrs := southbound.CreateProjectAttributes{}
+	if err := json.NewDecoder(r.Body).Decode(&attrs); err != nil || attrs.ProjectName == "" {
+		writeError(w, http.StatusBadRequest, "invalid project")
+		return
+	}
+	if _, ok := h.projects[attrs.ProjectName]; ok {
+		writeError(w, http.StatusConflict, "project %s already exists", attrs.ProjectName)
+		return
+	}
+	h.projects[attrs.ProjectName] = &HarborProject{
+		ID:           h.newID(),
+		Name:         attrs.ProjectName,
+		StorageLimit: attrs.StorageLimit,
+		Metadata:     map[string]string{},
+		Members:      map[string]int{},
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+// project looks up the project named in the request path, writing an error if it does not exist.
+func (h *Harbor) project(w http.ResponseWriter, r *http.Request) *HarborProject {
+	project, ok := h.projects[r.PathValue("name")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "project %s not found", r.PathValue("name"))
+	}
+	return project
+}
+
+func (h *Harbor) getProject(w http.ResponseWriter, r *http.Request) {
+	if project := h.project(w, r); project != nil {
+		writeJSON(w, http.StatusOK, southbound.HarborProject{ProjectID: project.ID, Metadata: maps.Clone(project.Metadata)})
+	}
+}
+
+func (h *Harbor) updateProject(w http.ResponseWriter, r *http.Request) {
+	project := h.project(w, r)
+	if project == nil {
+		return
+	}
+	attrs := southbound.UpdateProjectAttributes{}
+	if err := json.NewDecoder(r.Body).Decode(&attrs); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid project metadata")
+		return
+	}
+	maps.Copy(project.Metadata, attrs.Metadata)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Harbor) deleteProject(w http.ResponseWriter, r *http.Request) {
+	project := h.project(w, r)
+	if project == nil {
+		return
+	}
+	if len(project.Repositories) > 0 {
+		writeError(w, http.StatusPreconditionFailed, "project %s contains repositories", project.Name)
+		return
+	}
+	for id, robot := range h.robots {
+		if robot.ProjectID == project.ID {
+			delete(h.robots, id)
+		}
+	}
+	delete(h.projects, project.Name)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Harbor) addMember(w http.ResponseWriter, r *http.Request) {
+	project := h.project(w, r)
+	if project == nil {
+		return
+	}
+	attrs := southbound.MembersAttributes{}
+	if err := json.NewDecoder(r.Body).Decode(&attrs); err != nil || attrs.MemberGroup.GroupName == "" {
+		writeError(w, http.StatusBadRequest, "invalid member")
+		return
+	}
+	if _, ok := project.Members[attrs.MemberGroup.GroupName]; ok {
+		writeError(w, http.StatusConflict, "group %s is already a member", attrs.MemberGroup.GroupName)
+		return
+	}
+	project.Members[attrs.MemberGroup.GroupName] = attrs.RoleID
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Harbor) listRepositories(w http.ResponseWriter, r *http.Request) {
+	project := h.project(w, r)
+	if project == nil {
+		return
+	}
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+	page = max(page, 1)
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	repositories := []southbound.HarborRepository{}
+	for i, name := range project.Repositories {
+		if i >= (page-1)*pageSize && i < page*pageSize {
+			repositories = append(repositories, southbound.HarborRepository{
+				ID:        i + 1,
+				Name:      project.Name + "/" + name,
+				ProjectID: project.ID,
+			})
+		}
+	}
+	writeJSON(w, http.StatusOK, repositories)
+}
+
+func (h *Harbor) deleteRepository(w http.ResponseWriter, r *http.Request) {
+	project := h.project(w, r)
+	if project == nil {
+		return
+	}
+	// Nested repository names are double encoded, so the path value is still encoded once
+	name, err := url.PathUnescape(r.PathValue("repository"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	i := slices.Index(project.Repositories, name)
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "repository %s not found", name)
+		return
+	}
+	project.Repositories = slices.Delete(project.Repositories, i, i+1)
+	w.WriteHeader(http.StatusOK)
+}
+
+// The quota of a project has the same ID as the project.
+func (h *Harbor) listQuotas(w http.ResponseWriter, r *http.Request) {
+	quotas := []southbound.HarborQuota{}
+	for _, project := range h.projects {
+		if strconv.Itoa(project.ID) == r.URL.Query().Get("reference_id") {
+			quotas = append(quotas, southbound.HarborQuota{ID: project.ID})
+		}
+	}
+	writeJSON(w, http.StatusOK, quotas)
+}
+
+func (h *Harbor) updateQuota(w http.ResponseWriter, r *http.Request) {
+	attrs := southbound.UpdateQuotaAttributes{}
+	if err := json.NewDecoder(r.Body).Decode(&attrs); err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	for _, project := range h.projects {
+		if strconv.Itoa(project.ID) == r.PathValue("id") {
+			project.StorageLimit = attrs.Hard["storage"]
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "quota %s not found", r.PathValue("id"))
+}
+
+func (h *Harbor) createRobot(w http.ResponseWriter, r *http.Request) {
+	attrs := southbound.CreateRobotAttributes{}
+	if err := json.NewDecoder(r.Body).Decode(&attrs); err != nil || attrs.Name == "" || len(attrs.Permissions) == 0 {
+		writeError(w, http.StatusBadRequest, "invalid robot")
+		return
+	}
+	project, ok := h.projects[attrs.Permissions[0].Namespace]
+	if !ok {
+		writeError(w, http.StatusNotFound, "project %s not found", attrs.Permissions[0].Namespace)
+		return
+	}
+	name := fmt.Sprintf("robot$%s+%s", project.Name, attrs.Name)
+	for _, robot := range h.robots {
+		if robot.Name == name {
+			writeError(w, http.StatusConflict, "robot %s already exists", name)
+			return
+		}
+	}
+	robot := &HarborRobot{ID: h.newID(), Name: name, ProjectID: project.ID}
+	for _, permission := range attrs.Permissions {
+		for _, access := range permission.Access {
+			robot.Access = append(robot.Access, access.Resource+":"+access.Action)
+		}
+	}
+	robot.Secret = fmt.Sprintf("robot-secret-%d", h.newID())
+	h.robots[robot.ID] = robot
+	writeJSON(w, http.StatusCreated, southbound.CreateRobotResponse{ID: robot.ID, Name: robot.Name, Secret: robot.Secret})
+}
+
+// listRobots supports the q=Level=project,ProjectID=<id>,name=~<name> query and the pagination used by the
+// controller.
+func (h *Harbor) listRobots(w http.ResponseWriter, r *http.Request) {
+	projectID := ""
+	name := ""
+	for _, term := range strings.Split(r.URL.Query().Get("q"), ",") {
+		if value, found := strings.CutPrefix(term, "ProjectID="); found {
+			projectID = value
+		}
+		if value, found := strings.CutPrefix(term, "name=~"); found {
+			name = value
+		}
+	}
+	robots := []southbound.HarborRobot{}
+	for _, robot := range h.robots {
+		if (projectID == "" || strconv.Itoa(robot.ProjectID) == projectID) && strings.Contains(robot.Name, name) {
+			robots = append(robots, southbound.HarborRobot{ID: robot.ID, Name: robot.Name, Level: "project"})
+		}
+	}
+	slices.SortFunc(robots, func(a, b southbound.HarborRobot) int { return a.ID - b.ID })
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+	page = max(page, 1)
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	start := min((page-1)*pageSize, len(robots))
+	writeJSON(w, http.StatusOK, robots[start:min(start+pageSize, len(robots))])
+}
+
+// robot looks up the robot with the ID in the request path, writing an error if it does not exist.
+func (h *Harbor) robot(w http.ResponseWriter, r *http.Request) *HarborRobot {
+	id, _ := strconv.Atoi(r.PathValue("id"))
+	robot, ok := h.robots[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "robot %s not found", r.PathValue("id"))
+	}
+	return robot
+}
+
+func (h *Harbor) refreshRobotSecret(w http.ResponseWriter, r *http.Request) {
+	if robot := h.robot(w, r); robot != nil {
+		robot.Secret = fmt.Sprintf("robot-secret-%d", h.newID())
+		writeJSON(w, http.StatusOK, southbound.RobotSecret{Secret: robot.Secret})
+	}
+}
+
+func (h *Harbor) deleteRobot(w http.ResponseWriter, r *http.Request) {
+	if robot := h.robot(w, r); robot != nil {
+		delete(h.robots, robot.ID)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// listLabels supports the scope=p&project_id=<id> query used by the controller, without pagination.
+func (h *Harbor) listLabels(w http.ResponseWriter, r *http.Request) {
+	labels := []southbound.HarborLabel{}
+	for _, label := range h.labels {
+		if label.Scope == r.URL.Query().Get("scope") && strconv.Itoa(label.ProjectID) == r.URL.Query().Get("project_id") {
+			labels = append(labels, *label)
+		}
+	}
+	slices.SortFunc(labels, func(a, b southbound.HarborLabel) int { return a.ID - b.ID })
+	writeJSON(w, http.StatusOK, labels)
+}
+
+func (h *Harbor) createLabel(w http.ResponseWriter, r *http.Request) {
+	label := southbound.HarborLabel{}
+	if err := json.NewDecoder(r.Body).Decode(&label); err != nil || label.Name == "" {
+		writeError(w, http.StatusBadRequest, "invalid label")
+		return
+	}
+	for _, existing := range h.labels {
+		if existing.Name == label.Name && existing.Scope == label.Scope && existing.ProjectID == label.ProjectID {
+			writeError(w, http.StatusConflict, "label %s already exists", label.Name)
+			return
+		}
+	}
+	label.ID = h.newID()
+	h.labels[label.ID] = &label
+	w.Header().Set("Location", fmt.Sprintf("%s/%d", southbound.HarborLabelsURL, label.ID))
+	w.WriteHeader(http.StatusCreated)
+}
+
+// label looks up the label with the ID in the request path, writing an error if it does not exist.
+func (h *Harbor) label(w http.ResponseWriter, r *http.Request) *southbound.HarborLabel {
+	id, _ := strconv.Atoi(r.PathValue("id"))
+	label, ok := h.labels[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "label %s not found", r.PathValue("id"))
+	}
+	return label
+}
+
+func (h *Harbor) updateLabel(w http.ResponseWriter, r *http.Request) {
+	label := h.label(w, r)
+	if label == nil {
+		return
+	}
+	update := southbound.HarborLabel{}
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid label")
+		return
+	}
+	label.Description = update.Description
+	label.Color = update.Color
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Harbor) deleteLabel(w http.ResponseWriter, r *http.Request) {
+	if label := h.label(w, r); label != nil {
+		delete(h.labels, label.ID)
+		w.WriteHeader(http.StatusOK)
+	}
+}
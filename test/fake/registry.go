@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//nolint:revive // Test utility package
+package fake
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// FileMediaType is the media type of the files pushed to the fake registry
+const FileMediaType = "application/vnd.oci.image.layer.v1.tar"
+
+// Registry is a fake OCI distribution registry serving artifacts over plain HTTP, as the release service
+// proxy does, or over HTTPS as a private registry does. It only supports pulling; artifacts are published with Push.
+type Registry struct {
+	server *httptest.Server
+
+	mu    sync.Mutex
+	blobs map[digest.Digest][]byte
+	// manifests by repository, then by tag and digest
+	manifests map[string]map[string][]byte
+	// basic auth credential required to pull, if username is set
+	username string
+	password string
+}
+
+// NewRegistry starts a fake registry serving plain HTTP.
+func NewRegistry() *Registry {
+	r := newRegistry()
+	r.server = httptest.NewServer(http.HandlerFunc(r.serve))
+	return r
+}
+
+// NewTLSRegistry starts a fake registry serving HTTPS with a self-signed certificate, see CACerts.
+func NewTLSRegistry() *Registry {
+	r := newRegistry()
+	r.server = httptest.NewTLSServer(http.HandlerFunc(r.serve))
+	return r
+}
+
+func newRegistry() *Registry {
+	return &Registry{
+		blobs:     map[digest.Digest][]byte{},
+		manifests: map[string]map[string][]byte{},
+	}
+}
+
+// CACerts returns the PEM encoded certificate of a registry serving HTTPS.
+func (r *Registry) CACerts() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: r.server.Certificate().Raw}))
+}
+
+// RequireCredential makes pulling require basic authentication with the given credential.
+func (r *Registry) RequireCredential(username string, password string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.username = username
+	r.password = password
+}
+
+// Host returns the host and port of the fake registry, as used for the release service base.
+func (r *Registry) Host() string {
+	return r.server.Listener.Addr().String()
+}
+
+func (r *Registry) Close() {
+	r.server.Close()
+}
+
+// Push publishes files as an artifact with the given repository and tag. Each file is a layer annotated with
+// its name, so pulling the artifact to a file store recreates the files.
+func (r *Registry) Push(repository string, tag string, files map[string][]byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	config := ocispec.DescriptorEmptyJSON
+	r.blobs[config.Digest] = config.Data
+	manifest := ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    config,
+		Layers:    []ocispec.Descriptor{},
+	}
+	names := []string{}
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		d := digest.FromBytes(files[name])
+		r.blobs[d] = files[name]
+		manifest.Layers = append(manifest.Layers, ocispec.Descriptor{
+			MediaType:   FileMediaType,
+			Digest:      d,
+			Size:        int64(len(files[name])),
+			Annotations: map[string]string{ocispec.AnnotationTitle: name},
+		})
+	}
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	if r.manifests[repository] == nil {
+		r.manifests[repository] = map[string][]byte{}
+	}
+	r.manifests[repository][tag] = manifestJSON
+	r.manifests[repository][digest.FromBytes(manifestJSON).String()] = manifestJSON
+	return nil
+}
+
+// serve handles /v2/<repository>/manifests/<reference> and /v2/<repository>/blobs/<digest>.
+func (r *Registry) serve(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	path, ok := strings.CutPrefix(req.URL.Path, "/v2/")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if path == "" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.username != "" {
+		if username, password, ok := req.BasicAuth(); !ok || username != r.username || password != r.password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="fake"`)
+			writeJSON(w, http.StatusUnauthorized, map[string]interface{}{
+				"errors": []map[string]string{{"code": "UNAUTHORIZED", "message": "authentication required"}},
+			})
+			return
+		}
+	}
+	var content []byte
+	mediaType := "application/octet-stream"
+	if repository, reference, found := cutLast(path, "/manifests/"); found {
+		content, ok = r.manifests[repository][reference]
+		if !ok {
+			writeRegistryError(w, "MANIFEST_UNKNOWN", "manifest unknown")
+			return
+		}
+		mediaType = ocispec.MediaTypeImageManifest
+	} else if _, d, found := cutLast(path, "/blobs/"); found {
+		content, ok = r.blobs[digest.Digest(d)]
+		if !ok {
+			writeRegistryError(w, "BLOB_UNKNOWN", "blob unknown to registry")
+			return
+		}
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.Header().Set("Docker-Content-Digest", digest.FromBytes(content).String())
+	w.WriteHeader(http.StatusOK)
+	if req.Method == http.MethodGet {
+		_, _ = w.Write(content)
+	}
+}
+
+func cutLast(s string, sep string) (before string, after string, found bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
+
+func writeRegistryError(w http.ResponseWriter, code string, message string) {
+	writeJSON(w, http.StatusNotFound, map[string]interface{}{
+		"errors": []map[string]string{{"code": code, "message": message}},
+	})
+}
@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package manifestgen generates valid extensions manifests of any size, along with the deployment packages they
+// list, for unit and scale tests of the extensions provisioner. The output only depends on the options, so
+// generated fixtures can be checked in and regenerated with cmd/genmanifest.
+//
+//nolint:revive // Test utility package
+package manifestgen
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// ManifestFile is the name of the manifest file written by Write
+	ManifestFile = "manifest.yaml"
+
+	DefaultRepository = "registry/edge-node/dp"
+	DefaultRelease    = "0.0.0-generated"
+	DefaultRegistry   = "Release Service OCI Helm registry"
+
+	header = "# SPDX-FileCopyrightText: (C) 2026 Intel Corporation\n# SPDX-License-Identifier: Apache-2.0\n" +
+		"# Code generated by genmanifest. DO NOT EDIT.\n"
+)
+
+// Options sets the size and shape of a generated manifest.
+type Options struct {
+	// number of deployment packages
+	Packages int
+	// number of deployments. Deployment i is of package i modulo Packages, with the profile profile-<i / Packages>
+	Deployments int
+	// every AbsentEvery-th package and deployment has desiredState absent, as do the deployments of absent
+	// packages. 0 makes everything present
+	AbsentEvery int
+	// repository of the deployment packages in the release service, defaults to DefaultRepository
+	Repository string
+	// release of the manifest, defaults to DefaultRelease
+	Release string
+}
+
+// Package is a generated deployment package.
+type Package struct {
+	Dpkg     string
+	Version  string
+	FileName string
+	Content  []byte
+}
+
+// Fixture is a generated manifest and the deployment packages it lists, including the absent ones.
+type Fixture struct {
+	Manifest []byte
+	Packages []Package
+}
+
+// The manifest schema, with the fields that the controller does not use left out
+type targetClusterLabel struct {
+	Key string `yaml:"key"`
+	Val string `yaml:"val"`
+}
+
+type deploymentPackage struct {
+	Description  string `yaml:"description,omitempty"`
+	Registry     string `yaml:"registry"`
+	Version      string `yaml:"version"`
+	Dpkg         string `yaml:"dpkg"`
+	DesiredState string `yaml:"desiredState,omitempty"`
+}
+
+type deployment struct {
+	DpName               string               `yaml:"dpName"`
+	DisplayName          string               `yaml:"displayName"`
+	DpProfileName        string               `yaml:"dpProfileName"`
+	DpVersion            string               `yaml:"dpVersion"`
+	AllAppTargetClusters []targetClusterLabel `yaml:"allAppTargetClusters,omitempty"`
+	DesiredState         string               `yaml:"desiredState,omitempty"`
+}
+
+type manifest struct {
+	Metadata struct {
+		SchemaVersion string `yaml:"schemaVersion"`
+		Release       string `yaml:"release"`
+	} `yaml:"metadata"`
+	Lpke struct {
+		DeploymentPackages []deploymentPackage `yaml:"deploymentPackages"`
+		DeploymentList     []deployment        `yaml:"deploymentList"`
+	} `yaml:"lpke"`
+}
+
+// absent returns true if the i-th package or deployment is absent
+func (o Options) absent(i int) bool {
+	return o.AbsentEvery > 0 && (i+1)%o.AbsentEvery == 0
+}
+
+// Generate returns the manifest and deployment packages for the options.
+func Generate(options Options) (*Fixture, error) {
+	if options.Packages < 1 {
+		return nil, fmt.Errorf("at least one deployment package is required, got %d", options.Packages)
+	}
+	if options.Deployments < 0 || options.AbsentEvery < 0 {
+		return nil, fmt.Errorf("the number of deployments and absentEvery must not be negative")
+	}
+	if options.Repository == "" {
+		options.Repository = DefaultRepository
+	}
+	if options.Release == "" {
+		options.Release = DefaultRelease
+	}
+
+	var m manifest
+	m.Metadata.SchemaVersion = "0.2.1"
+	m.Metadata.Release = options.Release
+	fixture := &Fixture{}
+	width := len(fmt.Sprint(options.Packages - 1))
+	for i := 0; i < options.Packages; i++ {
+		name := fmt.Sprintf("gen-%0*d", width, i)
+		version := fmt.Sprintf("1.%d.0", i%10)
+		dp := deploymentPackage{
+			Description: fmt.Sprintf("Generated deployment package %d", i),
+			Registry:    DefaultRegistry,
+			Version:     version,
+			Dpkg:        path.Join(options.Repository, name),
+		}
+		if options.absent(i) {
+			dp.DesiredState = "absent"
+		}
+		m.Lpke.DeploymentPackages = append(m.Lpke.DeploymentPackages, dp)
+		fixture.Packages = append(fixture.Packages, Package{
+			Dpkg:     dp.Dpkg,
+			Version:  version,
+			FileName: name + "_" + version + ".yaml",
+			Content: fmt.Appendf([]byte(header), "---\nspecSchema: DeploymentPackage\nschemaVersion: \"0.1\"\n"+
+				"name: %s\nversion: %s\ndescription: %s\n", name, version, dp.Description),
+		})
+	}
+	for i := 0; i < options.Deployments; i++ {
+		dp := m.Lpke.DeploymentPackages[i%options.Packages]
+		name := path.Base(dp.Dpkg)
+		profile := fmt.Sprintf("profile-%d", i/options.Packages)
+		d := deployment{
+			DpName:               name,
+			DisplayName:          name + "-" + profile,
+			DpProfileName:        profile,
+			DpVersion:            dp.Version,
+			AllAppTargetClusters: []targetClusterLabel{{Key: "color", Val: fmt.Sprintf("color-%d", i%3)}},
+		}
+		if dp.DesiredState != "" || options.absent(i) {
+			d.DesiredState = "absent"
+		}
+		m.Lpke.DeploymentList = append(m.Lpke.DeploymentList, d)
+	}
+
+	body, err := yaml.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	fixture.Manifest = append([]byte(header), body...)
+	return fixture, nil
+}
+
+// Write writes the manifest to ManifestFile in the directory, and each deployment package to its file name.
+func (f *Fixture) Write(dir string) error {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), f.Manifest, 0600); err != nil {
+		return err
+	}
+	for _, p := range f.Packages {
+		if err := os.WriteFile(filepath.Join(dir, p.FileName), p.Content, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: (C) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package manifestgen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/open-edge-platform/app-orch-tenant-controller/internal/plugins"
+	"github.com/stretchr/testify/suite"
+)
+
+// Suite of manifest generator tests
+type ManifestGenTestSuite struct {
+	suite.Suite
+}
+
+func TestManifestGen(t *testing.T) {
+	suite.Run(t, &ManifestGenTestSuite{})
+}
+
+func (s *ManifestGenTestSuite) TestGenerate() {
+	fixture, err := Generate(Options{Packages: 12, Deployments: 30, AbsentEvery: 5})
+	s.NoError(err)
+	manifest, err := plugins.ParseManifest(fixture.Manifest)
+	s.NoError(err)
+	s.Empty(plugins.ValidateManifest(manifest))
+	s.Equal(DefaultRelease, manifest.Metadata.Release)
+
+	s.Len(manifest.Lpke.DeploymentPackages, 12)
+	s.Len(fixture.Packages, 12)
+	s.Equal("registry/edge-node/dp/gen-00", manifest.Lpke.DeploymentPackages[0].Dpkg)
+	s.Equal("gen-00_1.0.0.yaml", fixture.Packages[0].FileName)
+	s.Contains(string(fixture.Packages[0].Content), "name: gen-00\n")
+	absentPackages := 0
+	for _, dp := range manifest.Lpke.DeploymentPackages {
+		if dp.DesiredState == plugins.DesiredStateAbsent {
+			absentPackages++
+		}
+	}
+	s.Equal(2, absentPackages)
+
+	s.Len(manifest.Lpke.DeploymentList, 30)
+	absentDeployments := 0
+	for _, dl := range manifest.Lpke.DeploymentList {
+		if dl.DesiredState == plugins.DesiredStateAbsent {
+			absentDeployments++
+		}
+	}
+	// The deployments of packages 4 and 9, and every 5th deployment, are absent
+	s.Equal(9, absentDeployments)
+	s.Equal("gen-05", manifest.Lpke.DeploymentList[29].DpName)
+	s.Equal("profile-2", manifest.Lpke.DeploymentList[29].DpProfileName)
+
+	// The output only depends on the options
+	again, err := Generate(Options{Packages: 12, Deployments: 30, AbsentEvery: 5})
+	s.NoError(err)
+	s.Equal(fixture, again)
+}
+
+func (s *ManifestGenTestSuite) TestGenerateAllPresent() {
+	fixture, err := Generate(Options{Packages: 3, Deployments: 9, Repository: "edge-node/dp", Release: "1.2.3"})
+	s.NoError(err)
+	manifest, err := plugins.ParseManifest(fixture.Manifest)
+	s.NoError(err)
+	s.Empty(plugins.ValidateManifest(manifest))
+	s.Equal("1.2.3", manifest.Metadata.Release)
+	s.Equal("edge-node/dp/gen-2", manifest.Lpke.DeploymentPackages[2].Dpkg)
+	s.NotContains(string(fixture.Manifest), "desiredState")
+}
+
+func (s *ManifestGenTestSuite) TestGenerateInvalidOptions() {
+	_, err := Generate(Options{})
+	s.ErrorContains(err, "at least one deployment package")
+	_, err = Generate(Options{Packages: 1, Deployments: -1})
+	s.ErrorContains(err, "must not be negative")
+}
+
+func (s *ManifestGenTestSuite) TestWrite() {
+	fixture, err := Generate(Options{Packages: 2, Deployments: 2})
+	s.NoError(err)
+	dir := filepath.Join(s.T().TempDir(), "generated")
+	s.NoError(fixture.Write(dir))
+
+	entries, err := os.ReadDir(dir)
+	s.NoError(err)
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	s.Equal([]string{"gen-0_1.0.0.yaml", "gen-1_1.1.0.yaml", ManifestFile}, names)
+	written, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	s.NoError(err)
+	s.Equal(fixture.Manifest, written)
+	s.True(strings.HasPrefix(string(written), "# SPDX-FileCopyrightText"))
+}